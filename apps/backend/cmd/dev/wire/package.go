@@ -2,13 +2,18 @@ package wire
 
 import (
 	crossrefclientdi "github.com/SURF-Innovatie/MORIS/external/crossref/di"
+	mailerclientdi "github.com/SURF-Innovatie/MORIS/external/mailer/di"
 	nwoclientdi "github.com/SURF-Innovatie/MORIS/external/nwo/di"
 	orcidoclientdi "github.com/SURF-Innovatie/MORIS/external/orcid/di"
 	raidclientdi "github.com/SURF-Innovatie/MORIS/external/raid/di"
+	rorclientdi "github.com/SURF-Innovatie/MORIS/external/ror/di"
 	surfconextclientdi "github.com/SURF-Innovatie/MORIS/external/surfconext/di"
+	webhookclientdi "github.com/SURF-Innovatie/MORIS/external/webhook/di"
 	zenodoclientdi "github.com/SURF-Innovatie/MORIS/external/zenodo/di"
 	adapterinternaldi "github.com/SURF-Innovatie/MORIS/internal/adapter/di"
 	affiliatedorganisationappdi "github.com/SURF-Innovatie/MORIS/internal/app/affiliatedorganisation/di"
+	apitokenappdi "github.com/SURF-Innovatie/MORIS/internal/app/apitoken/di"
+	auditappdi "github.com/SURF-Innovatie/MORIS/internal/app/audit/di"
 	authappdi "github.com/SURF-Innovatie/MORIS/internal/app/auth/di"
 	crossrefappdi "github.com/SURF-Innovatie/MORIS/internal/app/crossref/di"
 	customfieldappdi "github.com/SURF-Innovatie/MORIS/internal/app/customfield/di"
@@ -16,6 +21,7 @@ import (
 	errorlogappdi "github.com/SURF-Innovatie/MORIS/internal/app/errorlog/di"
 	eventappdi "github.com/SURF-Innovatie/MORIS/internal/app/event/di"
 	eventpolicyappdi "github.com/SURF-Innovatie/MORIS/internal/app/eventpolicy/di"
+	membershipexpiryappdi "github.com/SURF-Innovatie/MORIS/internal/app/membershipexpiry/di"
 	notificaionappdi "github.com/SURF-Innovatie/MORIS/internal/app/notification/di"
 	nwoappdi "github.com/SURF-Innovatie/MORIS/internal/app/nwo/di"
 	orcidappdi "github.com/SURF-Innovatie/MORIS/internal/app/orcid/di"
@@ -24,11 +30,14 @@ import (
 	portfolioappdi "github.com/SURF-Innovatie/MORIS/internal/app/portfolio/di"
 	productappdi "github.com/SURF-Innovatie/MORIS/internal/app/product/di"
 	projectappdi "github.com/SURF-Innovatie/MORIS/internal/app/project/di"
+	scimappdi "github.com/SURF-Innovatie/MORIS/internal/app/scim/di"
 	surfconextappdi "github.com/SURF-Innovatie/MORIS/internal/app/surfconext/di"
 	userappdi "github.com/SURF-Innovatie/MORIS/internal/app/user/di"
+	webhookappdi "github.com/SURF-Innovatie/MORIS/internal/app/webhook/di"
 	zenodoappdi "github.com/SURF-Innovatie/MORIS/internal/app/zenodo/di"
 	adapterhandlerdi "github.com/SURF-Innovatie/MORIS/internal/handler/adapter/di"
 	affiliatedorganisationhandlerdi "github.com/SURF-Innovatie/MORIS/internal/handler/affiliatedorganisation/di"
+	apitokenhandlerdi "github.com/SURF-Innovatie/MORIS/internal/handler/apitoken/di"
 	authhandlerdi "github.com/SURF-Innovatie/MORIS/internal/handler/auth/di"
 	crossrefhandlerdi "github.com/SURF-Innovatie/MORIS/internal/handler/crossref/di"
 	doihandlerdi "github.com/SURF-Innovatie/MORIS/internal/handler/doi/di"
@@ -42,27 +51,35 @@ import (
 	portfoliohandlerdi "github.com/SURF-Innovatie/MORIS/internal/handler/portfolio/di"
 	producthandlerdi "github.com/SURF-Innovatie/MORIS/internal/handler/product/di"
 	projecthandlerdi "github.com/SURF-Innovatie/MORIS/internal/handler/project/di"
+	scimhandlerdi "github.com/SURF-Innovatie/MORIS/internal/handler/scim/di"
 	systemhandlerdi "github.com/SURF-Innovatie/MORIS/internal/handler/system/di"
 	userhandlerdi "github.com/SURF-Innovatie/MORIS/internal/handler/user/di"
+	webhookhandlerdi "github.com/SURF-Innovatie/MORIS/internal/handler/webhook/di"
 	zenodohandlerdi "github.com/SURF-Innovatie/MORIS/internal/handler/zenodo/di"
 	recipientadapterinfradi "github.com/SURF-Innovatie/MORIS/internal/infra/adapters/eventpolicy/di"
+	webhookadapterinfradi "github.com/SURF-Innovatie/MORIS/internal/infra/adapters/webhook/di"
 	infradi "github.com/SURF-Innovatie/MORIS/internal/infra/di"
 	eventpublisherinfradi "github.com/SURF-Innovatie/MORIS/internal/infra/eventdispatch/di"
 	eventinfrahandlerdi "github.com/SURF-Innovatie/MORIS/internal/infra/handlers/events/di"
 	identityinfradi "github.com/SURF-Innovatie/MORIS/internal/infra/identity/di"
 	affiliatedorganisationrepodi "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/affiliatedorganisation/di"
+	apitokenrepodi "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/apitoken/di"
+	auditrepodi "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/audit/di"
 	authrepodi "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/auth/di"
 	customfieldrepodi "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/customfield/di"
 	errorlogrepodi "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/errorlog/di"
 	eventrepodi "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/event/di"
 	eventpolicyrepodi "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/eventpolicy/di"
+	membershipexpiryrepodi "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/membershipexpiry/di"
 	notificationrepodi "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/notification/di"
 	organisationrepodi "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/organisation/di"
 	personrepodi "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/person/di"
 	portfolierepodi "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/portfolio/di"
 	productrepodi "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/product/di"
 	projectrepodi "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/project/di"
+	scimrepodi "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/scim/di"
 	userrepodi "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/user/di"
+	webhookrepodi "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/webhook/di"
 	"github.com/samber/do/v2"
 )
 
@@ -76,6 +93,13 @@ var Package = do.Package(
 	affiliatedorganisationhandlerdi.Package,
 	affiliatedorganisationrepodi.Package,
 
+	apitokenappdi.Package,
+	apitokenhandlerdi.Package,
+	apitokenrepodi.Package,
+
+	auditappdi.Package,
+	auditrepodi.Package,
+
 	authappdi.Package,
 	authhandlerdi.Package,
 	authrepodi.Package,
@@ -106,9 +130,13 @@ var Package = do.Package(
 
 	identityinfradi.Package,
 
+	membershipexpiryappdi.Package,
+	membershipexpiryrepodi.Package,
+
 	notificaionappdi.Package,
 	notificationrepodi.Package,
 	notificationhandlerdi.Package,
+	mailerclientdi.Package,
 
 	nwoappdi.Package,
 	nwohandlerdi.Package,
@@ -140,8 +168,14 @@ var Package = do.Package(
 
 	raidclientdi.Package,
 
+	rorclientdi.Package,
+
 	recipientadapterinfradi.Package,
 
+	scimappdi.Package,
+	scimrepodi.Package,
+	scimhandlerdi.Package,
+
 	systemhandlerdi.Package,
 
 	surfconextappdi.Package,
@@ -151,6 +185,12 @@ var Package = do.Package(
 	userrepodi.Package,
 	userhandlerdi.Package,
 
+	webhookappdi.Package,
+	webhookrepodi.Package,
+	webhookadapterinfradi.Package,
+	webhookclientdi.Package,
+	webhookhandlerdi.Package,
+
 	zenodoappdi.Package,
 	zenodohandlerdi.Package,
 	zenodoclientdi.Package,
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+
+	"github.com/SURF-Innovatie/MORIS/cmd/dev/wire"
+	"github.com/SURF-Innovatie/MORIS/ent"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/handlers/events"
+	eventrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/event"
+	projectrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/project"
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/do/v2"
+)
+
+// Rebuilds the project-scoped event policies from the event store.
+// Use after restoring a database or to repair policies created before they carried their full definition.
+func main() {
+	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+
+	projectFlag := flag.String("project", "", "Only rebuild the policies of this project (UUID)")
+	flag.Parse()
+
+	injector := do.New(wire.Package)
+	defer injector.Shutdown() //nolint:errcheck
+
+	client := do.MustInvoke[*ent.Client](injector)
+	defer client.Close()
+
+	handler := do.MustInvoke[*events.Handler](injector)
+	eventRepo := do.MustInvoke[*eventrepo.EntRepo](injector)
+	projectRepo := do.MustInvoke[*projectrepo.EntRepo](injector)
+
+	ctx := context.Background()
+
+	var projectIDs []uuid.UUID
+	if *projectFlag != "" {
+		id, err := uuid.Parse(*projectFlag)
+		if err != nil {
+			log.Fatal().Err(err).Msg("invalid project id")
+		}
+		projectIDs = []uuid.UUID{id}
+	} else {
+		ids, err := projectRepo.ProjectIDsStarted(ctx)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to list projects")
+		}
+		projectIDs = ids
+	}
+
+	failed := 0
+	for _, id := range projectIDs {
+		history, _, err := eventRepo.Load(ctx, id)
+		if err != nil {
+			log.Error().Err(err).Msgf("failed to load events for project %s", id)
+			failed++
+			continue
+		}
+		if err := handler.Rebuild(ctx, id, history); err != nil {
+			log.Error().Err(err).Msgf("failed to rebuild policies for project %s", id)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		log.Fatal().Msgf("rebuild failed for %d of %d projects", failed, len(projectIDs))
+	}
+	log.Info().Msgf("Rebuilt event policies for %d projects", len(projectIDs))
+}
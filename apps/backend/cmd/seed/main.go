@@ -220,6 +220,9 @@ func main() {
 	if _, err := roleRepo.Create(ctx, "admin", "Project Lead", orgRoot.ID); err != nil {
 		log.Fatal().Err(err).Msg("create project role admin")
 	}
+	if _, err := roleRepo.Create(ctx, "viewer", "Viewer", orgRoot.ID); err != nil {
+		log.Fatal().Err(err).Msg("create project role viewer")
+	}
 
 	projects := []seedProject{
 		{
@@ -463,6 +466,11 @@ func main() {
 				"manage_organisation_roles",
 				"manage_custom_fields",
 				"manage_details",
+				"approve_events",
+				"manage_policies",
+				"view_projects",
+				"export_projects",
+				"manage_affiliated_orgs",
 			}).
 			Save(ctx)
 		if err != nil {
@@ -483,7 +491,7 @@ func main() {
 			SetKey("researcher").
 			SetDisplayName("Researcher").
 			SetOrganisation(orgEnt).
-			SetPermissions([]string{}). // Basic access
+			SetPermissions([]string{"view_projects", "export_projects"}). // Basic access
 			Save(ctx)
 		if err != nil {
 			log.Fatal().Err(err).Msgf("create researcher role for %s", orgID)
@@ -0,0 +1,8 @@
+-- Modify "event_policies" table
+ALTER TABLE "event_policies" ADD COLUMN "webhook_url" character varying NULL, ADD COLUMN "webhook_secret" character varying NULL;
+-- Create "webhook_deliveries" table
+CREATE TABLE "webhook_deliveries" ("id" uuid NOT NULL, "policy_id" uuid NULL, "event_id" uuid NULL, "event_type" character varying NOT NULL, "url" character varying NOT NULL, "payload" text NOT NULL, "status" character varying NOT NULL DEFAULT 'pending', "attempts" bigint NOT NULL DEFAULT 0, "next_attempt_at" timestamptz NOT NULL, "last_attempt_at" timestamptz NULL, "last_status_code" bigint NULL, "last_error" text NULL, "created_at" timestamptz NOT NULL, PRIMARY KEY ("id"));
+-- Create index "webhookdelivery_status_next_attempt_at" to table: "webhook_deliveries"
+CREATE INDEX "webhookdelivery_status_next_attempt_at" ON "webhook_deliveries" ("status", "next_attempt_at");
+-- Create index "webhookdelivery_policy_id" to table: "webhook_deliveries"
+CREATE INDEX "webhookdelivery_policy_id" ON "webhook_deliveries" ("policy_id");
@@ -0,0 +1,6 @@
+-- Modify "event_policies" table
+ALTER TABLE "event_policies" ADD COLUMN "trigger_type" character varying NOT NULL DEFAULT 'event', ADD COLUMN "schedule_anchor" character varying NULL, ADD COLUMN "schedule_offset_days" bigint NULL;
+-- Create "scheduled_policy_runs" table
+CREATE TABLE "scheduled_policy_runs" ("id" uuid NOT NULL, "policy_id" uuid NOT NULL, "project_id" uuid NOT NULL, "due_at" timestamptz NOT NULL, "created_at" timestamptz NOT NULL, PRIMARY KEY ("id"));
+-- Create index "scheduledpolicyrun_policy_id_project_id_due_at" to table: "scheduled_policy_runs"
+CREATE UNIQUE INDEX "scheduledpolicyrun_policy_id_project_id_due_at" ON "scheduled_policy_runs" ("policy_id", "project_id", "due_at");
@@ -0,0 +1,8 @@
+-- Modify "event_policies" table
+ALTER TABLE "event_policies" ADD COLUMN "version" bigint NOT NULL DEFAULT 1;
+-- Create "policy_executions" table
+CREATE TABLE "policy_executions" ("id" uuid NOT NULL, "policy_id" uuid NOT NULL, "policy_version" bigint NOT NULL, "policy_name" character varying NOT NULL, "trigger" character varying NOT NULL DEFAULT 'event', "event_id" uuid NULL, "event_type" character varying NULL, "project_id" uuid NOT NULL, "action_type" character varying NOT NULL, "outcome" character varying NOT NULL, "condition_results" jsonb NULL, "recipient_user_ids" jsonb NULL, "message" text NULL, "detail" text NULL, "created_at" timestamptz NOT NULL, PRIMARY KEY ("id"));
+-- Create index "policyexecution_event_id" to table: "policy_executions"
+CREATE INDEX "policyexecution_event_id" ON "policy_executions" ("event_id");
+-- Create index "policyexecution_policy_id_created_at" to table: "policy_executions"
+CREATE INDEX "policyexecution_policy_id_created_at" ON "policy_executions" ("policy_id", "created_at");
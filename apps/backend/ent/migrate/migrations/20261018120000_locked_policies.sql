@@ -0,0 +1,2 @@
+-- Modify "event_policies" table
+ALTER TABLE "event_policies" ADD COLUMN "locked" boolean NOT NULL DEFAULT false;
@@ -0,0 +1,4 @@
+-- Modify "notifications" table
+ALTER TABLE "notifications" ADD COLUMN "variables" jsonb NULL, ADD COLUMN "email_status" character varying NULL, ADD COLUMN "email_error" character varying NULL, ADD COLUMN "email_sent_at" timestamptz NULL;
+-- Create index "notification_email_status" to table: "notifications"
+CREATE INDEX "notification_email_status" ON "notifications" ("email_status");
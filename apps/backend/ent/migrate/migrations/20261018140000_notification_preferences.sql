@@ -0,0 +1,10 @@
+-- Modify "notifications" table
+ALTER TABLE "notifications" ADD COLUMN "email_digest" character varying NULL;
+-- Drop index "notification_email_status" from table: "notifications"
+DROP INDEX "notification_email_status";
+-- Create index "notification_email_status_email_digest" to table: "notifications"
+CREATE INDEX "notification_email_status_email_digest" ON "notifications" ("email_status", "email_digest");
+-- Create "notification_preferences" table
+CREATE TABLE "notification_preferences" ("id" uuid NOT NULL, "user_id" uuid NOT NULL, "notification_type" character varying NULL, "event_type" character varying NULL, "channel" character varying NOT NULL DEFAULT 'email', "frequency" character varying NOT NULL DEFAULT 'immediate', "updated_at" timestamptz NOT NULL, PRIMARY KEY ("id"));
+-- Create index "notificationpreference_user_id" to table: "notification_preferences"
+CREATE INDEX "notificationpreference_user_id" ON "notification_preferences" ("user_id");
@@ -0,0 +1,4 @@
+-- Modify "notifications" table
+ALTER TABLE "notifications" ADD COLUMN "archived_at" timestamptz NULL;
+-- Create index "notification_user_id_sent_at" to table: "notifications"
+CREATE INDEX "notification_user_id_sent_at" ON "notifications" ("user_id", "sent_at");
@@ -0,0 +1,2 @@
+-- Modify "users" table
+ALTER TABLE "users" ADD COLUMN "language" character varying NOT NULL DEFAULT 'en';
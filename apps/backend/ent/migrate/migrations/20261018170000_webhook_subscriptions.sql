@@ -0,0 +1,8 @@
+-- Modify "webhook_deliveries" table
+ALTER TABLE "webhook_deliveries" ADD COLUMN "subscription_id" uuid NULL;
+-- Create index "webhookdelivery_subscription_id" to table: "webhook_deliveries"
+CREATE INDEX "webhookdelivery_subscription_id" ON "webhook_deliveries" ("subscription_id");
+-- Create "webhook_subscriptions" table
+CREATE TABLE "webhook_subscriptions" ("id" uuid NOT NULL, "name" character varying NOT NULL, "org_node_id" uuid NOT NULL, "include_descendants" boolean NOT NULL DEFAULT true, "event_types" jsonb NULL, "url" character varying NOT NULL, "secret" character varying NOT NULL, "secret_rotated_at" timestamptz NOT NULL, "enabled" boolean NOT NULL DEFAULT true, "created_by" uuid NULL, "created_at" timestamptz NOT NULL, "updated_at" timestamptz NOT NULL, PRIMARY KEY ("id"));
+-- Create index "webhooksubscription_org_node_id" to table: "webhook_subscriptions"
+CREATE INDEX "webhooksubscription_org_node_id" ON "webhook_subscriptions" ("org_node_id");
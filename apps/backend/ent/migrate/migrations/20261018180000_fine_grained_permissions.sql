@@ -0,0 +1,7 @@
+-- Grant "view_projects" and "export_projects" to every organisation role, reads and exports were unchecked before
+UPDATE "organisation_roles" SET "permissions" = COALESCE("permissions", '[]'::jsonb) || '["view_projects"]'::jsonb WHERE NOT COALESCE("permissions", '[]'::jsonb) ? 'view_projects';
+UPDATE "organisation_roles" SET "permissions" = COALESCE("permissions", '[]'::jsonb) || '["export_projects"]'::jsonb WHERE NOT COALESCE("permissions", '[]'::jsonb) ? 'export_projects';
+-- Grant approvals, policy management and affiliated organisations to roles that could manage details
+UPDATE "organisation_roles" SET "permissions" = "permissions" || '["approve_events"]'::jsonb WHERE "permissions" ? 'manage_details' AND NOT "permissions" ? 'approve_events';
+UPDATE "organisation_roles" SET "permissions" = "permissions" || '["manage_policies"]'::jsonb WHERE "permissions" ? 'manage_details' AND NOT "permissions" ? 'manage_policies';
+UPDATE "organisation_roles" SET "permissions" = "permissions" || '["manage_affiliated_orgs"]'::jsonb WHERE "permissions" ? 'manage_details' AND NOT "permissions" ? 'manage_affiliated_orgs';
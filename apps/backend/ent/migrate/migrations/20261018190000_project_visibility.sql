@@ -0,0 +1,8 @@
+-- Roles that could execute every event type keep doing so, project creation picks such a role for the creator
+UPDATE "project_roles" SET "allowed_event_types" = "allowed_event_types" || '["project.visibility_changed"]'::jsonb
+WHERE "allowed_event_types" @> '["project.affiliatedorganisation_added", "project.affiliatedorganisation_removed", "project.custom_field_value_set", "project.description_changed", "project.end_date_changed", "project.event_policy_added", "project.event_policy_removed", "project.event_policy_updated", "project.owning_org_node_changed", "project.product_added", "project.product_removed", "project.project_role_assigned", "project.role_unassigned", "project.start_date_changed", "project.started", "project.title_changed"]'::jsonb
+  AND NOT "allowed_event_types" ? 'project.visibility_changed';
+-- Add a read-only "viewer" project role to every root organisation node
+INSERT INTO "project_roles" ("id", "key", "name", "allowed_event_types", "organisation_node_id")
+SELECT gen_random_uuid(), 'viewer', 'Viewer', '[]'::jsonb, "id" FROM "organisation_nodes" WHERE "parent_id" IS NULL
+ON CONFLICT ("key", "organisation_node_id") DO NOTHING;
@@ -0,0 +1,6 @@
+-- Modify "memberships" table
+ALTER TABLE "memberships" ADD COLUMN "start_date" timestamptz NULL, ADD COLUMN "end_date" timestamptz NULL;
+-- Create "membership_expiry_notices" table
+CREATE TABLE "membership_expiry_notices" ("id" uuid NOT NULL, "kind" character varying NOT NULL, "subject_id" uuid NOT NULL, "person_id" uuid NOT NULL, "role_id" uuid NOT NULL, "end_date" timestamptz NOT NULL, "created_at" timestamptz NOT NULL, PRIMARY KEY ("id"));
+-- Create index "membershipexpirynotice_kind_subject_id_person_id_role_id_end_date" to table: "membership_expiry_notices"
+CREATE UNIQUE INDEX "membershipexpirynotice_kind_subject_id_person_id_role_id_end_date" ON "membership_expiry_notices" ("kind", "subject_id", "person_id", "role_id", "end_date");
@@ -0,0 +1,6 @@
+-- Create "audit_log_entries" table
+CREATE TABLE "audit_log_entries" ("id" uuid NOT NULL, "organisation_node_id" uuid NOT NULL, "actor_user_id" uuid NULL, "entity_type" character varying NOT NULL, "entity_id" uuid NOT NULL, "action" character varying NOT NULL, "before" jsonb NULL, "after" jsonb NULL, "created_at" timestamptz NOT NULL, PRIMARY KEY ("id"));
+-- Create index "auditlogentry_organisation_node_id_created_at" to table: "audit_log_entries"
+CREATE INDEX "auditlogentry_organisation_node_id_created_at" ON "audit_log_entries" ("organisation_node_id", "created_at");
+-- Create index "auditlogentry_entity_type_entity_id" to table: "audit_log_entries"
+CREATE INDEX "auditlogentry_entity_type_entity_id" ON "audit_log_entries" ("entity_type", "entity_id");
@@ -0,0 +1,4 @@
+-- Modify "organisation_nodes" table
+ALTER TABLE "organisation_nodes" ADD COLUMN "ror_status" character varying NULL;
+-- Create index "organisationnode_ror_id" to table: "organisation_nodes"
+CREATE INDEX "organisationnode_ror_id" ON "organisation_nodes" ("ror_id");
@@ -0,0 +1,8 @@
+-- Modify "memberships" table
+ALTER TABLE "memberships" ADD COLUMN "provisioned" boolean NOT NULL DEFAULT false;
+-- Create "provisioning_rules" table
+CREATE TABLE "provisioning_rules" ("id" uuid NOT NULL, "schac_home_organization" character varying NOT NULL, "affiliation" character varying NULL, "entitlement" character varying NULL, "created_at" timestamptz NOT NULL, "root_node_id" uuid NOT NULL, "role_scope_id" uuid NOT NULL, PRIMARY KEY ("id"), CONSTRAINT "provisioning_rules_organisation_nodes_root_node" FOREIGN KEY ("root_node_id") REFERENCES "organisation_nodes" ("id") ON DELETE NO ACTION, CONSTRAINT "provisioning_rules_role_scopes_role_scope" FOREIGN KEY ("role_scope_id") REFERENCES "role_scopes" ("id") ON DELETE NO ACTION);
+-- Create index "provisioningrule_root_node_id" to table: "provisioning_rules"
+CREATE INDEX "provisioningrule_root_node_id" ON "provisioning_rules" ("root_node_id");
+-- Create index "provisioningrule_schac_home_organization" to table: "provisioning_rules"
+CREATE INDEX "provisioningrule_schac_home_organization" ON "provisioning_rules" ("schac_home_organization");
@@ -0,0 +1,12 @@
+-- Create "scim_tokens" table
+CREATE TABLE "scim_tokens" ("id" uuid NOT NULL, "name" character varying NOT NULL, "token_hash" character varying NOT NULL, "created_by_user_id" uuid NULL, "created_at" timestamptz NOT NULL, "last_used_at" timestamptz NULL, "root_node_id" uuid NOT NULL, PRIMARY KEY ("id"), CONSTRAINT "scim_tokens_organisation_nodes_root_node" FOREIGN KEY ("root_node_id") REFERENCES "organisation_nodes" ("id") ON DELETE NO ACTION);
+-- Create index "scim_tokens_token_hash_key" to table: "scim_tokens"
+CREATE UNIQUE INDEX "scim_tokens_token_hash_key" ON "scim_tokens" ("token_hash");
+-- Create index "scimtoken_root_node_id" to table: "scim_tokens"
+CREATE INDEX "scimtoken_root_node_id" ON "scim_tokens" ("root_node_id");
+-- Create "scim_users" table
+CREATE TABLE "scim_users" ("id" uuid NOT NULL, "external_id" character varying NULL, "created_at" timestamptz NOT NULL, "root_node_id" uuid NOT NULL, "user_id" uuid NOT NULL, PRIMARY KEY ("id"), CONSTRAINT "scim_users_organisation_nodes_root_node" FOREIGN KEY ("root_node_id") REFERENCES "organisation_nodes" ("id") ON DELETE NO ACTION, CONSTRAINT "scim_users_users_user" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE NO ACTION);
+-- Create index "scimuser_user_id" to table: "scim_users"
+CREATE UNIQUE INDEX "scimuser_user_id" ON "scim_users" ("user_id");
+-- Create index "scimuser_root_node_id_external_id" to table: "scim_users"
+CREATE UNIQUE INDEX "scimuser_root_node_id_external_id" ON "scim_users" ("root_node_id", "external_id");
@@ -0,0 +1,8 @@
+-- Create "sessions" table
+CREATE TABLE "sessions" ("id" uuid NOT NULL, "refresh_token_hash" character varying NOT NULL, "previous_token_hash" character varying NULL, "user_agent" character varying NULL, "ip_address" character varying NULL, "created_at" timestamptz NOT NULL, "last_used_at" timestamptz NOT NULL, "expires_at" timestamptz NOT NULL, "revoked_at" timestamptz NULL, "user_id" uuid NOT NULL, PRIMARY KEY ("id"), CONSTRAINT "sessions_users_user" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE NO ACTION);
+-- Create index "sessions_refresh_token_hash_key" to table: "sessions"
+CREATE UNIQUE INDEX "sessions_refresh_token_hash_key" ON "sessions" ("refresh_token_hash");
+-- Create index "session_user_id" to table: "sessions"
+CREATE INDEX "session_user_id" ON "sessions" ("user_id");
+-- Create index "session_previous_token_hash" to table: "sessions"
+CREATE INDEX "session_previous_token_hash" ON "sessions" ("previous_token_hash");
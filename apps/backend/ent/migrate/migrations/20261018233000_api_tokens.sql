@@ -0,0 +1,14 @@
+-- Modify "users" table
+ALTER TABLE "users" ADD COLUMN "is_service_account" boolean NOT NULL DEFAULT false;
+-- Create "api_tokens" table
+CREATE TABLE "api_tokens" ("id" uuid NOT NULL, "name" character varying NOT NULL, "token_hash" character varying NOT NULL, "scopes" jsonb NOT NULL, "created_by_user_id" uuid NULL, "created_at" timestamptz NOT NULL, "expires_at" timestamptz NOT NULL, "last_used_at" timestamptz NULL, "user_id" uuid NOT NULL, PRIMARY KEY ("id"), CONSTRAINT "api_tokens_users_user" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE NO ACTION);
+-- Create index "api_tokens_token_hash_key" to table: "api_tokens"
+CREATE UNIQUE INDEX "api_tokens_token_hash_key" ON "api_tokens" ("token_hash");
+-- Create index "apitoken_user_id" to table: "api_tokens"
+CREATE INDEX "apitoken_user_id" ON "api_tokens" ("user_id");
+-- Create "api_token_nodes" table
+CREATE TABLE "api_token_nodes" ("id" uuid NOT NULL, "api_token_id" uuid NOT NULL, "node_id" uuid NOT NULL, PRIMARY KEY ("id"), CONSTRAINT "api_token_nodes_api_tokens_token" FOREIGN KEY ("api_token_id") REFERENCES "api_tokens" ("id") ON DELETE NO ACTION, CONSTRAINT "api_token_nodes_organisation_nodes_node" FOREIGN KEY ("node_id") REFERENCES "organisation_nodes" ("id") ON DELETE NO ACTION);
+-- Create index "apitokennode_api_token_id_node_id" to table: "api_token_nodes"
+CREATE UNIQUE INDEX "apitokennode_api_token_id_node_id" ON "api_token_nodes" ("api_token_id", "node_id");
+-- Create index "apitokennode_node_id" to table: "api_token_nodes"
+CREATE INDEX "apitokennode_node_id" ON "api_token_nodes" ("node_id");
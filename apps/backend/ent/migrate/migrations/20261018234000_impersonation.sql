@@ -0,0 +1,6 @@
+-- Modify "sessions" table
+ALTER TABLE "sessions" ADD COLUMN "impersonator_user_id" uuid NULL, ADD COLUMN "impersonation_reason" character varying NULL;
+-- Create index "session_impersonator_user_id" to table: "sessions"
+CREATE INDEX "session_impersonator_user_id" ON "sessions" ("impersonator_user_id");
+-- Modify "events" table
+ALTER TABLE "events" ADD COLUMN "impersonator_id" uuid NULL;
@@ -0,0 +1,2 @@
+-- Modify "webhook_subscriptions" table
+ALTER TABLE "webhook_subscriptions" ADD COLUMN "previous_secret" character varying NULL, ADD COLUMN "previous_secret_expires_at" timestamptz NULL;
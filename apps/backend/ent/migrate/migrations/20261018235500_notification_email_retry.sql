@@ -0,0 +1,2 @@
+-- Modify "notifications" table
+ALTER TABLE "notifications" ADD COLUMN "email_attempts" bigint NOT NULL DEFAULT 0, ADD COLUMN "email_next_attempt_at" timestamptz NULL;
@@ -24,6 +24,107 @@ var (
 		Columns:    AffiliatedOrganisationsColumns,
 		PrimaryKey: []*schema.Column{AffiliatedOrganisationsColumns[0]},
 	}
+	// APITokensColumns holds the columns for the "api_tokens" table.
+	APITokensColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeUUID},
+		{Name: "name", Type: field.TypeString},
+		{Name: "token_hash", Type: field.TypeString, Unique: true},
+		{Name: "scopes", Type: field.TypeJSON},
+		{Name: "created_by_user_id", Type: field.TypeUUID, Nullable: true},
+		{Name: "created_at", Type: field.TypeTime},
+		{Name: "expires_at", Type: field.TypeTime},
+		{Name: "last_used_at", Type: field.TypeTime, Nullable: true},
+		{Name: "user_id", Type: field.TypeUUID},
+	}
+	// APITokensTable holds the schema information for the "api_tokens" table.
+	APITokensTable = &schema.Table{
+		Name:       "api_tokens",
+		Columns:    APITokensColumns,
+		PrimaryKey: []*schema.Column{APITokensColumns[0]},
+		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "api_tokens_users_user",
+				Columns:    []*schema.Column{APITokensColumns[8]},
+				RefColumns: []*schema.Column{UsersColumns[0]},
+				OnDelete:   schema.NoAction,
+			},
+		},
+		Indexes: []*schema.Index{
+			{
+				Name:    "apitoken_user_id",
+				Unique:  false,
+				Columns: []*schema.Column{APITokensColumns[8]},
+			},
+		},
+	}
+	// APITokenNodesColumns holds the columns for the "api_token_nodes" table.
+	APITokenNodesColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeUUID},
+		{Name: "api_token_id", Type: field.TypeUUID},
+		{Name: "node_id", Type: field.TypeUUID},
+	}
+	// APITokenNodesTable holds the schema information for the "api_token_nodes" table.
+	APITokenNodesTable = &schema.Table{
+		Name:       "api_token_nodes",
+		Columns:    APITokenNodesColumns,
+		PrimaryKey: []*schema.Column{APITokenNodesColumns[0]},
+		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "api_token_nodes_api_tokens_token",
+				Columns:    []*schema.Column{APITokenNodesColumns[1]},
+				RefColumns: []*schema.Column{APITokensColumns[0]},
+				OnDelete:   schema.NoAction,
+			},
+			{
+				Symbol:     "api_token_nodes_organisation_nodes_node",
+				Columns:    []*schema.Column{APITokenNodesColumns[2]},
+				RefColumns: []*schema.Column{OrganisationNodesColumns[0]},
+				OnDelete:   schema.NoAction,
+			},
+		},
+		Indexes: []*schema.Index{
+			{
+				Name:    "apitokennode_api_token_id_node_id",
+				Unique:  true,
+				Columns: []*schema.Column{APITokenNodesColumns[1], APITokenNodesColumns[2]},
+			},
+			{
+				Name:    "apitokennode_node_id",
+				Unique:  false,
+				Columns: []*schema.Column{APITokenNodesColumns[2]},
+			},
+		},
+	}
+	// AuditLogEntriesColumns holds the columns for the "audit_log_entries" table.
+	AuditLogEntriesColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeUUID},
+		{Name: "organisation_node_id", Type: field.TypeUUID},
+		{Name: "actor_user_id", Type: field.TypeUUID, Nullable: true},
+		{Name: "entity_type", Type: field.TypeEnum, Enums: []string{"organisation_role", "role_scope", "organisation_membership", "custom_field_definition", "project_role", "provisioning_rule"}},
+		{Name: "entity_id", Type: field.TypeUUID},
+		{Name: "action", Type: field.TypeEnum, Enums: []string{"create", "update", "delete"}},
+		{Name: "before", Type: field.TypeJSON, Nullable: true},
+		{Name: "after", Type: field.TypeJSON, Nullable: true},
+		{Name: "created_at", Type: field.TypeTime},
+	}
+	// AuditLogEntriesTable holds the schema information for the "audit_log_entries" table.
+	AuditLogEntriesTable = &schema.Table{
+		Name:       "audit_log_entries",
+		Columns:    AuditLogEntriesColumns,
+		PrimaryKey: []*schema.Column{AuditLogEntriesColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "auditlogentry_organisation_node_id_created_at",
+				Unique:  false,
+				Columns: []*schema.Column{AuditLogEntriesColumns[1], AuditLogEntriesColumns[8]},
+			},
+			{
+				Name:    "auditlogentry_entity_type_entity_id",
+				Unique:  false,
+				Columns: []*schema.Column{AuditLogEntriesColumns[3], AuditLogEntriesColumns[4]},
+			},
+		},
+	}
 	// CustomFieldDefinitionsColumns holds the columns for the "custom_field_definitions" table.
 	CustomFieldDefinitionsColumns = []*schema.Column{
 		{Name: "id", Type: field.TypeUUID},
@@ -75,6 +176,7 @@ var (
 		{Name: "type", Type: field.TypeString},
 		{Name: "status", Type: field.TypeEnum, Enums: []string{"pending", "approved", "rejected"}, Default: "pending"},
 		{Name: "created_by", Type: field.TypeUUID, Nullable: true},
+		{Name: "impersonator_id", Type: field.TypeUUID, Nullable: true},
 		{Name: "occurred_at", Type: field.TypeTime},
 		{Name: "data", Type: field.TypeJSON},
 	}
@@ -89,16 +191,23 @@ var (
 		{Name: "id", Type: field.TypeUUID},
 		{Name: "name", Type: field.TypeString},
 		{Name: "description", Type: field.TypeString, Nullable: true},
+		{Name: "trigger_type", Type: field.TypeEnum, Enums: []string{"event", "schedule"}, Default: "event"},
 		{Name: "event_types", Type: field.TypeJSON},
+		{Name: "schedule_anchor", Type: field.TypeString, Nullable: true},
+		{Name: "schedule_offset_days", Type: field.TypeInt, Nullable: true},
 		{Name: "conditions", Type: field.TypeJSON, Nullable: true},
-		{Name: "action_type", Type: field.TypeEnum, Enums: []string{"notify", "request_approval"}},
+		{Name: "action_type", Type: field.TypeEnum, Enums: []string{"notify", "request_approval", "webhook"}},
 		{Name: "message_template", Type: field.TypeString, Nullable: true},
+		{Name: "webhook_url", Type: field.TypeString, Nullable: true},
+		{Name: "webhook_secret", Type: field.TypeString, Nullable: true},
 		{Name: "recipient_user_ids", Type: field.TypeJSON, Nullable: true},
 		{Name: "recipient_project_role_ids", Type: field.TypeJSON, Nullable: true},
 		{Name: "recipient_org_role_ids", Type: field.TypeJSON, Nullable: true},
 		{Name: "recipient_dynamic", Type: field.TypeJSON, Nullable: true},
 		{Name: "project_id", Type: field.TypeUUID, Nullable: true},
 		{Name: "enabled", Type: field.TypeBool, Default: true},
+		{Name: "locked", Type: field.TypeBool, Default: false},
+		{Name: "version", Type: field.TypeInt, Default: 1},
 		{Name: "created_at", Type: field.TypeTime},
 		{Name: "updated_at", Type: field.TypeTime},
 		{Name: "org_node_id", Type: field.TypeUUID, Nullable: true},
@@ -111,7 +220,7 @@ var (
 		ForeignKeys: []*schema.ForeignKey{
 			{
 				Symbol:     "event_policies_organisation_nodes_org_node",
-				Columns:    []*schema.Column{EventPoliciesColumns[15]},
+				Columns:    []*schema.Column{EventPoliciesColumns[22]},
 				RefColumns: []*schema.Column{OrganisationNodesColumns[0]},
 				OnDelete:   schema.SetNull,
 			},
@@ -120,18 +229,21 @@ var (
 			{
 				Name:    "eventpolicy_org_node_id",
 				Unique:  false,
-				Columns: []*schema.Column{EventPoliciesColumns[15]},
+				Columns: []*schema.Column{EventPoliciesColumns[22]},
 			},
 			{
 				Name:    "eventpolicy_project_id",
 				Unique:  false,
-				Columns: []*schema.Column{EventPoliciesColumns[11]},
+				Columns: []*schema.Column{EventPoliciesColumns[16]},
 			},
 		},
 	}
 	// MembershipsColumns holds the columns for the "memberships" table.
 	MembershipsColumns = []*schema.Column{
 		{Name: "id", Type: field.TypeUUID},
+		{Name: "start_date", Type: field.TypeTime, Nullable: true},
+		{Name: "end_date", Type: field.TypeTime, Nullable: true},
+		{Name: "provisioned", Type: field.TypeBool, Default: false},
 		{Name: "person_id", Type: field.TypeUUID},
 		{Name: "role_scope_id", Type: field.TypeUUID},
 	}
@@ -143,13 +255,13 @@ var (
 		ForeignKeys: []*schema.ForeignKey{
 			{
 				Symbol:     "memberships_persons_person",
-				Columns:    []*schema.Column{MembershipsColumns[1]},
+				Columns:    []*schema.Column{MembershipsColumns[4]},
 				RefColumns: []*schema.Column{PersonsColumns[0]},
 				OnDelete:   schema.NoAction,
 			},
 			{
 				Symbol:     "memberships_role_scopes_role_scope",
-				Columns:    []*schema.Column{MembershipsColumns[2]},
+				Columns:    []*schema.Column{MembershipsColumns[5]},
 				RefColumns: []*schema.Column{RoleScopesColumns[0]},
 				OnDelete:   schema.NoAction,
 			},
@@ -158,7 +270,30 @@ var (
 			{
 				Name:    "membership_person_id_role_scope_id",
 				Unique:  true,
-				Columns: []*schema.Column{MembershipsColumns[1], MembershipsColumns[2]},
+				Columns: []*schema.Column{MembershipsColumns[4], MembershipsColumns[5]},
+			},
+		},
+	}
+	// MembershipExpiryNoticesColumns holds the columns for the "membership_expiry_notices" table.
+	MembershipExpiryNoticesColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeUUID},
+		{Name: "kind", Type: field.TypeEnum, Enums: []string{"organisation_membership", "project_role"}},
+		{Name: "subject_id", Type: field.TypeUUID},
+		{Name: "person_id", Type: field.TypeUUID},
+		{Name: "role_id", Type: field.TypeUUID},
+		{Name: "end_date", Type: field.TypeTime},
+		{Name: "created_at", Type: field.TypeTime},
+	}
+	// MembershipExpiryNoticesTable holds the schema information for the "membership_expiry_notices" table.
+	MembershipExpiryNoticesTable = &schema.Table{
+		Name:       "membership_expiry_notices",
+		Columns:    MembershipExpiryNoticesColumns,
+		PrimaryKey: []*schema.Column{MembershipExpiryNoticesColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "membershipexpirynotice_kind_subject_id_person_id_role_id_end_date",
+				Unique:  true,
+				Columns: []*schema.Column{MembershipExpiryNoticesColumns[1], MembershipExpiryNoticesColumns[2], MembershipExpiryNoticesColumns[3], MembershipExpiryNoticesColumns[4], MembershipExpiryNoticesColumns[5]},
 			},
 		},
 	}
@@ -169,6 +304,14 @@ var (
 		{Name: "type", Type: field.TypeEnum, Enums: []string{"info", "approval_request", "status_update"}, Default: "info"},
 		{Name: "read", Type: field.TypeBool, Default: false},
 		{Name: "sent_at", Type: field.TypeTime},
+		{Name: "archived_at", Type: field.TypeTime, Nullable: true},
+		{Name: "variables", Type: field.TypeJSON, Nullable: true},
+		{Name: "email_status", Type: field.TypeEnum, Nullable: true, Enums: []string{"pending", "sent", "bounced", "failed"}},
+		{Name: "email_digest", Type: field.TypeEnum, Nullable: true, Enums: []string{"daily", "weekly"}},
+		{Name: "email_error", Type: field.TypeString, Nullable: true},
+		{Name: "email_sent_at", Type: field.TypeTime, Nullable: true},
+		{Name: "email_attempts", Type: field.TypeInt, Default: 0},
+		{Name: "email_next_attempt_at", Type: field.TypeTime, Nullable: true},
 		{Name: "event_id", Type: field.TypeUUID, Nullable: true},
 		{Name: "user_id", Type: field.TypeUUID},
 	}
@@ -180,17 +323,52 @@ var (
 		ForeignKeys: []*schema.ForeignKey{
 			{
 				Symbol:     "notifications_events_notifications",
-				Columns:    []*schema.Column{NotificationsColumns[5]},
+				Columns:    []*schema.Column{NotificationsColumns[13]},
 				RefColumns: []*schema.Column{EventsColumns[0]},
 				OnDelete:   schema.SetNull,
 			},
 			{
 				Symbol:     "notifications_users_notifications",
-				Columns:    []*schema.Column{NotificationsColumns[6]},
+				Columns:    []*schema.Column{NotificationsColumns[14]},
 				RefColumns: []*schema.Column{UsersColumns[0]},
 				OnDelete:   schema.NoAction,
 			},
 		},
+		Indexes: []*schema.Index{
+			{
+				Name:    "notification_email_status_email_digest",
+				Unique:  false,
+				Columns: []*schema.Column{NotificationsColumns[7], NotificationsColumns[8]},
+			},
+			{
+				Name:    "notification_user_id_sent_at",
+				Unique:  false,
+				Columns: []*schema.Column{NotificationsColumns[14], NotificationsColumns[4]},
+			},
+		},
+	}
+	// NotificationPreferencesColumns holds the columns for the "notification_preferences" table.
+	NotificationPreferencesColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeUUID},
+		{Name: "user_id", Type: field.TypeUUID},
+		{Name: "notification_type", Type: field.TypeEnum, Nullable: true, Enums: []string{"info", "approval_request", "status_update"}},
+		{Name: "event_type", Type: field.TypeString, Nullable: true},
+		{Name: "channel", Type: field.TypeEnum, Enums: []string{"in_app", "email", "none"}, Default: "email"},
+		{Name: "frequency", Type: field.TypeEnum, Enums: []string{"immediate", "daily", "weekly"}, Default: "immediate"},
+		{Name: "updated_at", Type: field.TypeTime},
+	}
+	// NotificationPreferencesTable holds the schema information for the "notification_preferences" table.
+	NotificationPreferencesTable = &schema.Table{
+		Name:       "notification_preferences",
+		Columns:    NotificationPreferencesColumns,
+		PrimaryKey: []*schema.Column{NotificationPreferencesColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "notificationpreference_user_id",
+				Unique:  false,
+				Columns: []*schema.Column{NotificationPreferencesColumns[1]},
+			},
+		},
 	}
 	// OrganisationNodesColumns holds the columns for the "organisation_nodes" table.
 	OrganisationNodesColumns = []*schema.Column{
@@ -199,6 +377,7 @@ var (
 		{Name: "description", Type: field.TypeString, Nullable: true},
 		{Name: "avatar_url", Type: field.TypeString, Nullable: true},
 		{Name: "ror_id", Type: field.TypeString, Nullable: true},
+		{Name: "ror_status", Type: field.TypeString, Nullable: true},
 		{Name: "parent_id", Type: field.TypeUUID, Nullable: true},
 	}
 	// OrganisationNodesTable holds the schema information for the "organisation_nodes" table.
@@ -209,7 +388,7 @@ var (
 		ForeignKeys: []*schema.ForeignKey{
 			{
 				Symbol:     "organisation_nodes_organisation_nodes_children",
-				Columns:    []*schema.Column{OrganisationNodesColumns[5]},
+				Columns:    []*schema.Column{OrganisationNodesColumns[6]},
 				RefColumns: []*schema.Column{OrganisationNodesColumns[0]},
 				OnDelete:   schema.SetNull,
 			},
@@ -218,7 +397,12 @@ var (
 			{
 				Name:    "organisationnode_parent_id",
 				Unique:  false,
-				Columns: []*schema.Column{OrganisationNodesColumns[5]},
+				Columns: []*schema.Column{OrganisationNodesColumns[6]},
+			},
+			{
+				Name:    "organisationnode_ror_id",
+				Unique:  false,
+				Columns: []*schema.Column{OrganisationNodesColumns[4]},
 			},
 		},
 	}
@@ -305,6 +489,42 @@ var (
 		Columns:    PersonsColumns,
 		PrimaryKey: []*schema.Column{PersonsColumns[0]},
 	}
+	// PolicyExecutionsColumns holds the columns for the "policy_executions" table.
+	PolicyExecutionsColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeUUID},
+		{Name: "policy_id", Type: field.TypeUUID},
+		{Name: "policy_version", Type: field.TypeInt},
+		{Name: "policy_name", Type: field.TypeString},
+		{Name: "trigger", Type: field.TypeEnum, Enums: []string{"event", "schedule"}, Default: "event"},
+		{Name: "event_id", Type: field.TypeUUID, Nullable: true},
+		{Name: "event_type", Type: field.TypeString, Nullable: true},
+		{Name: "project_id", Type: field.TypeUUID},
+		{Name: "action_type", Type: field.TypeEnum, Enums: []string{"notify", "request_approval", "webhook"}},
+		{Name: "outcome", Type: field.TypeEnum, Enums: []string{"approval_required", "executed", "skipped", "failed"}},
+		{Name: "condition_results", Type: field.TypeJSON, Nullable: true},
+		{Name: "recipient_user_ids", Type: field.TypeJSON, Nullable: true},
+		{Name: "message", Type: field.TypeString, Nullable: true, Size: 2147483647},
+		{Name: "detail", Type: field.TypeString, Nullable: true, Size: 2147483647},
+		{Name: "created_at", Type: field.TypeTime},
+	}
+	// PolicyExecutionsTable holds the schema information for the "policy_executions" table.
+	PolicyExecutionsTable = &schema.Table{
+		Name:       "policy_executions",
+		Columns:    PolicyExecutionsColumns,
+		PrimaryKey: []*schema.Column{PolicyExecutionsColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "policyexecution_event_id",
+				Unique:  false,
+				Columns: []*schema.Column{PolicyExecutionsColumns[5]},
+			},
+			{
+				Name:    "policyexecution_policy_id_created_at",
+				Unique:  false,
+				Columns: []*schema.Column{PolicyExecutionsColumns[1], PolicyExecutionsColumns[14]},
+			},
+		},
+	}
 	// PortfoliosColumns holds the columns for the "portfolios" table.
 	PortfoliosColumns = []*schema.Column{
 		{Name: "id", Type: field.TypeUUID},
@@ -376,6 +596,48 @@ var (
 			},
 		},
 	}
+	// ProvisioningRulesColumns holds the columns for the "provisioning_rules" table.
+	ProvisioningRulesColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeUUID},
+		{Name: "schac_home_organization", Type: field.TypeString},
+		{Name: "affiliation", Type: field.TypeString, Nullable: true},
+		{Name: "entitlement", Type: field.TypeString, Nullable: true},
+		{Name: "created_at", Type: field.TypeTime},
+		{Name: "root_node_id", Type: field.TypeUUID},
+		{Name: "role_scope_id", Type: field.TypeUUID},
+	}
+	// ProvisioningRulesTable holds the schema information for the "provisioning_rules" table.
+	ProvisioningRulesTable = &schema.Table{
+		Name:       "provisioning_rules",
+		Columns:    ProvisioningRulesColumns,
+		PrimaryKey: []*schema.Column{ProvisioningRulesColumns[0]},
+		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "provisioning_rules_organisation_nodes_root_node",
+				Columns:    []*schema.Column{ProvisioningRulesColumns[5]},
+				RefColumns: []*schema.Column{OrganisationNodesColumns[0]},
+				OnDelete:   schema.NoAction,
+			},
+			{
+				Symbol:     "provisioning_rules_role_scopes_role_scope",
+				Columns:    []*schema.Column{ProvisioningRulesColumns[6]},
+				RefColumns: []*schema.Column{RoleScopesColumns[0]},
+				OnDelete:   schema.NoAction,
+			},
+		},
+		Indexes: []*schema.Index{
+			{
+				Name:    "provisioningrule_root_node_id",
+				Unique:  false,
+				Columns: []*schema.Column{ProvisioningRulesColumns[5]},
+			},
+			{
+				Name:    "provisioningrule_schac_home_organization",
+				Unique:  false,
+				Columns: []*schema.Column{ProvisioningRulesColumns[1]},
+			},
+		},
+	}
 	// RoleScopesColumns holds the columns for the "role_scopes" table.
 	RoleScopesColumns = []*schema.Column{
 		{Name: "id", Type: field.TypeUUID},
@@ -409,6 +671,144 @@ var (
 			},
 		},
 	}
+	// ScheduledPolicyRunsColumns holds the columns for the "scheduled_policy_runs" table.
+	ScheduledPolicyRunsColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeUUID},
+		{Name: "policy_id", Type: field.TypeUUID},
+		{Name: "project_id", Type: field.TypeUUID},
+		{Name: "due_at", Type: field.TypeTime},
+		{Name: "created_at", Type: field.TypeTime},
+	}
+	// ScheduledPolicyRunsTable holds the schema information for the "scheduled_policy_runs" table.
+	ScheduledPolicyRunsTable = &schema.Table{
+		Name:       "scheduled_policy_runs",
+		Columns:    ScheduledPolicyRunsColumns,
+		PrimaryKey: []*schema.Column{ScheduledPolicyRunsColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "scheduledpolicyrun_policy_id_project_id_due_at",
+				Unique:  true,
+				Columns: []*schema.Column{ScheduledPolicyRunsColumns[1], ScheduledPolicyRunsColumns[2], ScheduledPolicyRunsColumns[3]},
+			},
+		},
+	}
+	// ScimTokensColumns holds the columns for the "scim_tokens" table.
+	ScimTokensColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeUUID},
+		{Name: "name", Type: field.TypeString},
+		{Name: "token_hash", Type: field.TypeString, Unique: true},
+		{Name: "created_by_user_id", Type: field.TypeUUID, Nullable: true},
+		{Name: "created_at", Type: field.TypeTime},
+		{Name: "last_used_at", Type: field.TypeTime, Nullable: true},
+		{Name: "root_node_id", Type: field.TypeUUID},
+	}
+	// ScimTokensTable holds the schema information for the "scim_tokens" table.
+	ScimTokensTable = &schema.Table{
+		Name:       "scim_tokens",
+		Columns:    ScimTokensColumns,
+		PrimaryKey: []*schema.Column{ScimTokensColumns[0]},
+		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "scim_tokens_organisation_nodes_root_node",
+				Columns:    []*schema.Column{ScimTokensColumns[6]},
+				RefColumns: []*schema.Column{OrganisationNodesColumns[0]},
+				OnDelete:   schema.NoAction,
+			},
+		},
+		Indexes: []*schema.Index{
+			{
+				Name:    "scimtoken_root_node_id",
+				Unique:  false,
+				Columns: []*schema.Column{ScimTokensColumns[6]},
+			},
+		},
+	}
+	// ScimUsersColumns holds the columns for the "scim_users" table.
+	ScimUsersColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeUUID},
+		{Name: "external_id", Type: field.TypeString, Nullable: true},
+		{Name: "created_at", Type: field.TypeTime},
+		{Name: "root_node_id", Type: field.TypeUUID},
+		{Name: "user_id", Type: field.TypeUUID},
+	}
+	// ScimUsersTable holds the schema information for the "scim_users" table.
+	ScimUsersTable = &schema.Table{
+		Name:       "scim_users",
+		Columns:    ScimUsersColumns,
+		PrimaryKey: []*schema.Column{ScimUsersColumns[0]},
+		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "scim_users_organisation_nodes_root_node",
+				Columns:    []*schema.Column{ScimUsersColumns[3]},
+				RefColumns: []*schema.Column{OrganisationNodesColumns[0]},
+				OnDelete:   schema.NoAction,
+			},
+			{
+				Symbol:     "scim_users_users_user",
+				Columns:    []*schema.Column{ScimUsersColumns[4]},
+				RefColumns: []*schema.Column{UsersColumns[0]},
+				OnDelete:   schema.NoAction,
+			},
+		},
+		Indexes: []*schema.Index{
+			{
+				Name:    "scimuser_user_id",
+				Unique:  true,
+				Columns: []*schema.Column{ScimUsersColumns[4]},
+			},
+			{
+				Name:    "scimuser_root_node_id_external_id",
+				Unique:  true,
+				Columns: []*schema.Column{ScimUsersColumns[3], ScimUsersColumns[1]},
+			},
+		},
+	}
+	// SessionsColumns holds the columns for the "sessions" table.
+	SessionsColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeUUID},
+		{Name: "refresh_token_hash", Type: field.TypeString, Unique: true},
+		{Name: "previous_token_hash", Type: field.TypeString, Nullable: true},
+		{Name: "user_agent", Type: field.TypeString, Nullable: true},
+		{Name: "ip_address", Type: field.TypeString, Nullable: true},
+		{Name: "impersonator_user_id", Type: field.TypeUUID, Nullable: true},
+		{Name: "impersonation_reason", Type: field.TypeString, Nullable: true},
+		{Name: "created_at", Type: field.TypeTime},
+		{Name: "last_used_at", Type: field.TypeTime},
+		{Name: "expires_at", Type: field.TypeTime},
+		{Name: "revoked_at", Type: field.TypeTime, Nullable: true},
+		{Name: "user_id", Type: field.TypeUUID},
+	}
+	// SessionsTable holds the schema information for the "sessions" table.
+	SessionsTable = &schema.Table{
+		Name:       "sessions",
+		Columns:    SessionsColumns,
+		PrimaryKey: []*schema.Column{SessionsColumns[0]},
+		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "sessions_users_user",
+				Columns:    []*schema.Column{SessionsColumns[11]},
+				RefColumns: []*schema.Column{UsersColumns[0]},
+				OnDelete:   schema.NoAction,
+			},
+		},
+		Indexes: []*schema.Index{
+			{
+				Name:    "session_user_id",
+				Unique:  false,
+				Columns: []*schema.Column{SessionsColumns[11]},
+			},
+			{
+				Name:    "session_previous_token_hash",
+				Unique:  false,
+				Columns: []*schema.Column{SessionsColumns[2]},
+			},
+			{
+				Name:    "session_impersonator_user_id",
+				Unique:  false,
+				Columns: []*schema.Column{SessionsColumns[5]},
+			},
+		},
+	}
 	// UsersColumns holds the columns for the "users" table.
 	UsersColumns = []*schema.Column{
 		{Name: "id", Type: field.TypeUUID, Unique: true},
@@ -416,6 +816,8 @@ var (
 		{Name: "password", Type: field.TypeString, Nullable: true},
 		{Name: "is_sys_admin", Type: field.TypeBool, Default: false},
 		{Name: "is_active", Type: field.TypeBool, Default: true},
+		{Name: "is_service_account", Type: field.TypeBool, Default: false},
+		{Name: "language", Type: field.TypeEnum, Enums: []string{"en", "nl"}, Default: "en"},
 		{Name: "zenodo_access_token", Type: field.TypeString, Nullable: true},
 		{Name: "zenodo_refresh_token", Type: field.TypeString, Nullable: true},
 	}
@@ -425,6 +827,76 @@ var (
 		Columns:    UsersColumns,
 		PrimaryKey: []*schema.Column{UsersColumns[0]},
 	}
+	// WebhookDeliveriesColumns holds the columns for the "webhook_deliveries" table.
+	WebhookDeliveriesColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeUUID, Unique: true},
+		{Name: "policy_id", Type: field.TypeUUID, Nullable: true},
+		{Name: "subscription_id", Type: field.TypeUUID, Nullable: true},
+		{Name: "event_id", Type: field.TypeUUID, Nullable: true},
+		{Name: "event_type", Type: field.TypeString},
+		{Name: "url", Type: field.TypeString},
+		{Name: "payload", Type: field.TypeString, Size: 2147483647},
+		{Name: "status", Type: field.TypeEnum, Enums: []string{"pending", "succeeded", "failed"}, Default: "pending"},
+		{Name: "attempts", Type: field.TypeInt, Default: 0},
+		{Name: "next_attempt_at", Type: field.TypeTime},
+		{Name: "last_attempt_at", Type: field.TypeTime, Nullable: true},
+		{Name: "last_status_code", Type: field.TypeInt, Nullable: true},
+		{Name: "last_error", Type: field.TypeString, Nullable: true, Size: 2147483647},
+		{Name: "created_at", Type: field.TypeTime},
+	}
+	// WebhookDeliveriesTable holds the schema information for the "webhook_deliveries" table.
+	WebhookDeliveriesTable = &schema.Table{
+		Name:       "webhook_deliveries",
+		Columns:    WebhookDeliveriesColumns,
+		PrimaryKey: []*schema.Column{WebhookDeliveriesColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "webhookdelivery_status_next_attempt_at",
+				Unique:  false,
+				Columns: []*schema.Column{WebhookDeliveriesColumns[7], WebhookDeliveriesColumns[9]},
+			},
+			{
+				Name:    "webhookdelivery_policy_id",
+				Unique:  false,
+				Columns: []*schema.Column{WebhookDeliveriesColumns[1]},
+			},
+			{
+				Name:    "webhookdelivery_subscription_id",
+				Unique:  false,
+				Columns: []*schema.Column{WebhookDeliveriesColumns[2]},
+			},
+		},
+	}
+	// WebhookSubscriptionsColumns holds the columns for the "webhook_subscriptions" table.
+	WebhookSubscriptionsColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeUUID, Unique: true},
+		{Name: "name", Type: field.TypeString},
+		{Name: "org_node_id", Type: field.TypeUUID},
+		{Name: "include_descendants", Type: field.TypeBool, Default: true},
+		{Name: "event_types", Type: field.TypeJSON, Nullable: true},
+		{Name: "url", Type: field.TypeString},
+		{Name: "secret", Type: field.TypeString},
+		{Name: "secret_rotated_at", Type: field.TypeTime},
+		{Name: "previous_secret", Type: field.TypeString, Nullable: true},
+		{Name: "previous_secret_expires_at", Type: field.TypeTime, Nullable: true},
+		{Name: "enabled", Type: field.TypeBool, Default: true},
+		{Name: "created_by", Type: field.TypeUUID, Nullable: true},
+		{Name: "created_at", Type: field.TypeTime},
+		{Name: "updated_at", Type: field.TypeTime},
+	}
+	// WebhookSubscriptionsTable holds the schema information for the "webhook_subscriptions" table.
+	WebhookSubscriptionsTable = &schema.Table{
+		Name:       "webhook_subscriptions",
+		Columns:    WebhookSubscriptionsColumns,
+		PrimaryKey: []*schema.Column{WebhookSubscriptionsColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "webhooksubscription_org_node_id",
+				Unique:  false,
+				Columns: []*schema.Column{WebhookSubscriptionsColumns[2]},
+			},
+		},
+	}
 	// PersonProductsColumns holds the columns for the "person_products" table.
 	PersonProductsColumns = []*schema.Column{
 		{Name: "person_id", Type: field.TypeUUID},
@@ -453,26 +925,42 @@ var (
 	// Tables holds all the tables in the schema.
 	Tables = []*schema.Table{
 		AffiliatedOrganisationsTable,
+		APITokensTable,
+		APITokenNodesTable,
+		AuditLogEntriesTable,
 		CustomFieldDefinitionsTable,
 		ErrorLogsTable,
 		EventsTable,
 		EventPoliciesTable,
 		MembershipsTable,
+		MembershipExpiryNoticesTable,
 		NotificationsTable,
+		NotificationPreferencesTable,
 		OrganisationNodesTable,
 		OrganisationNodeClosuresTable,
 		OrganisationRolesTable,
 		PersonsTable,
+		PolicyExecutionsTable,
 		PortfoliosTable,
 		ProductsTable,
 		ProjectRolesTable,
+		ProvisioningRulesTable,
 		RoleScopesTable,
+		ScheduledPolicyRunsTable,
+		ScimTokensTable,
+		ScimUsersTable,
+		SessionsTable,
 		UsersTable,
+		WebhookDeliveriesTable,
+		WebhookSubscriptionsTable,
 		PersonProductsTable,
 	}
 )
 
 func init() {
+	APITokensTable.ForeignKeys[0].RefTable = UsersTable
+	APITokenNodesTable.ForeignKeys[0].RefTable = APITokensTable
+	APITokenNodesTable.ForeignKeys[1].RefTable = OrganisationNodesTable
 	CustomFieldDefinitionsTable.ForeignKeys[0].RefTable = OrganisationNodesTable
 	EventPoliciesTable.ForeignKeys[0].RefTable = OrganisationNodesTable
 	MembershipsTable.ForeignKeys[0].RefTable = PersonsTable
@@ -485,8 +973,14 @@ func init() {
 	OrganisationRolesTable.ForeignKeys[0].RefTable = OrganisationNodesTable
 	PortfoliosTable.ForeignKeys[0].RefTable = PersonsTable
 	ProjectRolesTable.ForeignKeys[0].RefTable = OrganisationNodesTable
+	ProvisioningRulesTable.ForeignKeys[0].RefTable = OrganisationNodesTable
+	ProvisioningRulesTable.ForeignKeys[1].RefTable = RoleScopesTable
 	RoleScopesTable.ForeignKeys[0].RefTable = OrganisationRolesTable
 	RoleScopesTable.ForeignKeys[1].RefTable = OrganisationNodesTable
+	ScimTokensTable.ForeignKeys[0].RefTable = OrganisationNodesTable
+	ScimUsersTable.ForeignKeys[0].RefTable = OrganisationNodesTable
+	ScimUsersTable.ForeignKeys[1].RefTable = UsersTable
+	SessionsTable.ForeignKeys[0].RefTable = UsersTable
 	PersonProductsTable.ForeignKeys[0].RefTable = PersonsTable
 	PersonProductsTable.ForeignKeys[1].RefTable = ProductsTable
 }
@@ -0,0 +1,50 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/google/uuid"
+)
+
+// ApiToken is a personal access token of a user or a token of a service account
+type ApiToken struct {
+	ent.Schema
+}
+
+func (ApiToken) Fields() []ent.Field {
+	return []ent.Field{
+		field.UUID("id", uuid.UUID{}).Default(uuid.New),
+
+		field.UUID("user_id", uuid.UUID{}),
+		field.String("name").NotEmpty(),
+		// SHA-256 of the bearer token; the token itself is shown once at creation
+		field.String("token_hash").NotEmpty().Unique().Sensitive(),
+		field.JSON("scopes", []string{}),
+
+		field.UUID("created_by_user_id", uuid.UUID{}).Optional().Nillable(),
+		field.Time("created_at").Default(time.Now).Immutable(),
+		field.Time("expires_at"),
+		field.Time("last_used_at").Optional().Nillable(),
+	}
+}
+
+func (ApiToken) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("user", User.Type).
+			Field("user_id").
+			Unique().
+			Required(),
+		edge.From("nodes", ApiTokenNode.Type).
+			Ref("token"),
+	}
+}
+
+func (ApiToken) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("user_id"),
+	}
+}
@@ -0,0 +1,43 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/google/uuid"
+)
+
+// ApiTokenNode limits an API token to an organisation node and its descendants
+type ApiTokenNode struct {
+	ent.Schema
+}
+
+func (ApiTokenNode) Fields() []ent.Field {
+	return []ent.Field{
+		field.UUID("id", uuid.UUID{}).Default(uuid.New),
+
+		field.UUID("api_token_id", uuid.UUID{}),
+		field.UUID("node_id", uuid.UUID{}),
+	}
+}
+
+func (ApiTokenNode) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("token", ApiToken.Type).
+			Field("api_token_id").
+			Unique().
+			Required(),
+		edge.To("node", OrganisationNode.Type).
+			Field("node_id").
+			Unique().
+			Required(),
+	}
+}
+
+func (ApiTokenNode) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("api_token_id", "node_id").Unique(),
+		index.Fields("node_id"),
+	}
+}
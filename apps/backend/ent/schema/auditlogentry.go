@@ -0,0 +1,42 @@
+package schema
+
+import (
+	"encoding/json"
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/google/uuid"
+)
+
+// AuditLogEntry is an append-only record of a change to organisation configuration:
+// roles, role scopes, memberships, custom field definitions and project role definitions.
+type AuditLogEntry struct {
+	ent.Schema
+}
+
+func (AuditLogEntry) Fields() []ent.Field {
+	return []ent.Field{
+		field.UUID("id", uuid.UUID{}).Default(uuid.New).Immutable(),
+		// organisation_node_id is the node the changed entity belongs to
+		field.UUID("organisation_node_id", uuid.UUID{}).Immutable(),
+		// actor_user_id is empty for changes made by the system
+		field.UUID("actor_user_id", uuid.UUID{}).Optional().Nillable().Immutable(),
+		field.Enum("entity_type").
+			Values("organisation_role", "role_scope", "organisation_membership", "custom_field_definition", "project_role", "provisioning_rule").
+			Immutable(),
+		field.UUID("entity_id", uuid.UUID{}).Immutable(),
+		field.Enum("action").Values("create", "update", "delete").Immutable(),
+		field.JSON("before", json.RawMessage{}).Optional().Immutable(),
+		field.JSON("after", json.RawMessage{}).Optional().Immutable(),
+		field.Time("created_at").Default(time.Now).Immutable(),
+	}
+}
+
+func (AuditLogEntry) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("organisation_node_id", "created_at"),
+		index.Fields("entity_type", "entity_id"),
+	}
+}
@@ -26,6 +26,10 @@ func (Event) Fields() []ent.Field {
 			Default("pending"),
 		field.UUID("created_by", uuid.UUID{}).
 			Optional(),
+		// User ID of the sysadmin who appended the event while impersonating created_by
+		field.UUID("impersonator_id", uuid.UUID{}).
+			Optional().
+			Nillable(),
 		field.Time("occurred_at").
 			Default(time.Now),
 		field.JSON("data", map[string]any{}).
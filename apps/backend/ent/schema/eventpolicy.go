@@ -21,17 +21,26 @@ func (EventPolicy) Fields() []ent.Field {
 		field.String("name"),
 		field.String("description").Optional().Nillable(),
 
-		// Trigger: event types + optional conditions
+		// Trigger: event types (or a schedule) + optional conditions
+		field.Enum("trigger_type").Values("event", "schedule").Default("event"),
 		field.Strings("event_types"),
+		// Schedule: fires offset days (negative = before) after the anchor date,
+		// e.g. "project.end_date" or "event:project.started"
+		field.String("schedule_anchor").Optional().Nillable(),
+		field.Int("schedule_offset_days").Optional().Nillable(),
 		// Conditions stored as JSON array of objects: [{field, operator, value}, ...]
 		field.JSON("conditions", []map[string]any{}).
 			Optional().
 			Annotations(entoas.Skip(true)),
 
 		// Action configuration
-		field.Enum("action_type").Values("notify", "request_approval"),
+		field.Enum("action_type").Values("notify", "request_approval", "webhook"),
 		field.String("message_template").Optional().Nillable(),
 
+		// Webhook target (only used when action_type is "webhook")
+		field.String("webhook_url").Optional().Nillable(),
+		field.String("webhook_secret").Optional().Nillable().Sensitive(),
+
 		// Recipients (at least one should be set)
 		field.JSON("recipient_user_ids", []uuid.UUID{}).
 			Optional().
@@ -49,6 +58,10 @@ func (EventPolicy) Fields() []ent.Field {
 		field.UUID("project_id", uuid.UUID{}).Optional().Nillable(),
 
 		field.Bool("enabled").Default(true),
+		// Locked org policies are mandatory for all descendant nodes and projects
+		field.Bool("locked").Default(false),
+		// Incremented on every update so that execution logs can refer to the definition that ran
+		field.Int("version").Default(1),
 		field.Time("created_at").Default(time.Now),
 		field.Time("updated_at").Default(time.Now).UpdateDefault(time.Now),
 	}
@@ -18,6 +18,14 @@ func (Membership) Fields() []ent.Field {
 
 		field.UUID("person_id", uuid.UUID{}),
 		field.UUID("role_scope_id", uuid.UUID{}),
+
+		// The membership grants its role's permissions from start_date (inclusive) until end_date (exclusive)
+		field.Time("start_date").Optional().Nillable(),
+		field.Time("end_date").Optional().Nillable(),
+
+		// Provisioned memberships were granted by a provisioning rule and are revoked at login
+		// once no rule grants them anymore
+		field.Bool("provisioned").Default(false),
 	}
 }
 
@@ -0,0 +1,36 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/google/uuid"
+)
+
+// MembershipExpiryNotice records that admins were told about an expiring organisation membership
+// or project role assignment, so that each end date is announced only once.
+type MembershipExpiryNotice struct {
+	ent.Schema
+}
+
+func (MembershipExpiryNotice) Fields() []ent.Field {
+	return []ent.Field{
+		field.UUID("id", uuid.UUID{}).Default(uuid.New),
+		field.Enum("kind").Values("organisation_membership", "project_role"),
+		// subject_id is the membership for organisation memberships and the project for project roles
+		field.UUID("subject_id", uuid.UUID{}),
+		field.UUID("person_id", uuid.UUID{}),
+		// role_id is the role scope for organisation memberships and the project role for project roles
+		field.UUID("role_id", uuid.UUID{}),
+		field.Time("end_date"),
+		field.Time("created_at").Default(time.Now).Immutable(),
+	}
+}
+
+func (MembershipExpiryNotice) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("kind", "subject_id", "person_id", "role_id", "end_date").Unique(),
+	}
+}
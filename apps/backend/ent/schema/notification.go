@@ -3,9 +3,11 @@ package schema
 import (
 	"time"
 
+	"entgo.io/contrib/entoas"
 	"entgo.io/ent"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
 	"github.com/google/uuid"
 )
 
@@ -22,6 +24,25 @@ func (Notification) Fields() []ent.Field {
 			Default("info"),
 		field.Bool("read").Default(false),
 		field.Time("sent_at").Default(time.Now),
+		// Archived notifications are hidden from the inbox but kept until retention removes them
+		field.Time("archived_at").Optional().Nillable(),
+		// Template variables the message was rendered from; the email templates reuse them
+		field.JSON("variables", map[string]string{}).
+			Optional().
+			Annotations(entoas.Skip(true)),
+
+		// Email delivery; unset when email is not configured
+		field.Enum("email_status").
+			Values("pending", "sent", "bounced", "failed").
+			Optional().
+			Nillable(),
+		// Set when the email waits for the recipient's daily or weekly digest
+		field.Enum("email_digest").Values("daily", "weekly").Optional().Nillable(),
+		field.String("email_error").Optional().Nillable(),
+		field.Time("email_sent_at").Optional().Nillable(),
+		// Transient delivery failures are retried; a pending email is not sent again before this time
+		field.Int("email_attempts").Default(0),
+		field.Time("email_next_attempt_at").Optional().Nillable(),
 
 		field.UUID("user_id", uuid.UUID{}),
 		field.UUID("event_id", uuid.UUID{}).Optional().Nillable(),
@@ -42,3 +63,10 @@ func (Notification) Edges() []ent.Edge {
 			Unique(),
 	}
 }
+
+func (Notification) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("email_status", "email_digest"),
+		index.Fields("user_id", "sent_at"),
+	}
+}
@@ -0,0 +1,38 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/google/uuid"
+)
+
+// NotificationPreference is a user's delivery choice for a notification type, an event type
+// or (with neither set) for all notifications. The most specific matching preference applies.
+type NotificationPreference struct {
+	ent.Schema
+}
+
+func (NotificationPreference) Fields() []ent.Field {
+	return []ent.Field{
+		field.UUID("id", uuid.UUID{}).Default(uuid.New),
+		field.UUID("user_id", uuid.UUID{}),
+		field.Enum("notification_type").
+			Values("info", "approval_request", "status_update").
+			Optional().
+			Nillable(),
+		field.String("event_type").Optional().Nillable(),
+		field.Enum("channel").Values("in_app", "email", "none").Default("email"),
+		// How email is delivered: one message per notification or bundled in a digest
+		field.Enum("frequency").Values("immediate", "daily", "weekly").Default("immediate"),
+		field.Time("updated_at").Default(time.Now).UpdateDefault(time.Now),
+	}
+}
+
+func (NotificationPreference) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("user_id"),
+	}
+}
@@ -19,6 +19,9 @@ func (OrganisationNode) Fields() []ent.Field {
 		field.String("description").Optional().Nillable(),
 		field.String("avatar_url").Optional().Nillable(),
 		field.String("ror_id").Optional().Nillable(),
+		// ror_status is the registry status of the organisation (active, inactive or withdrawn)
+		// as of the last ROR import
+		field.String("ror_status").Optional().Nillable(),
 
 		// Explicit FK column (nullable => root nodes)
 		field.UUID("parent_id", uuid.UUID{}).
@@ -46,5 +49,6 @@ func (OrganisationNode) Edges() []ent.Edge {
 func (OrganisationNode) Indexes() []ent.Index {
 	return []ent.Index{
 		index.Fields("parent_id"),
+		index.Fields("ror_id"),
 	}
 }
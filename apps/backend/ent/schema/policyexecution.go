@@ -0,0 +1,48 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/contrib/entoas"
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/google/uuid"
+)
+
+// PolicyExecution is the audit record of a policy that matched an event (or a schedule)
+type PolicyExecution struct {
+	ent.Schema
+}
+
+func (PolicyExecution) Fields() []ent.Field {
+	return []ent.Field{
+		field.UUID("id", uuid.UUID{}).Default(uuid.New),
+		field.UUID("policy_id", uuid.UUID{}),
+		field.Int("policy_version"),
+		field.String("policy_name"),
+		field.Enum("trigger").Values("event", "schedule").Default("event"),
+		field.UUID("event_id", uuid.UUID{}).Optional().Nillable(),
+		field.String("event_type").Optional(),
+		field.UUID("project_id", uuid.UUID{}),
+		field.Enum("action_type").Values("notify", "request_approval", "webhook"),
+		field.Enum("outcome").Values("approval_required", "executed", "skipped", "failed"),
+		// Condition results stored as JSON array: [{field, operator, expected, actual, passed}, ...]
+		field.JSON("condition_results", []map[string]any{}).
+			Optional().
+			Annotations(entoas.Skip(true)),
+		field.JSON("recipient_user_ids", []uuid.UUID{}).
+			Optional().
+			Annotations(entoas.Skip(true)),
+		field.Text("message").Optional().Nillable(),
+		field.Text("detail").Optional().Nillable(),
+		field.Time("created_at").Default(time.Now).Immutable(),
+	}
+}
+
+func (PolicyExecution) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("event_id"),
+		index.Fields("policy_id", "created_at"),
+	}
+}
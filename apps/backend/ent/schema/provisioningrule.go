@@ -0,0 +1,54 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/google/uuid"
+)
+
+// ProvisioningRule grants a role scope to people who log in through SURFconext with matching claims
+type ProvisioningRule struct {
+	ent.Schema
+}
+
+func (ProvisioningRule) Fields() []ent.Field {
+	return []ent.Field{
+		field.UUID("id", uuid.UUID{}).Default(uuid.New),
+
+		// The root node the rule is configured on
+		field.UUID("root_node_id", uuid.UUID{}),
+		field.UUID("role_scope_id", uuid.UUID{}),
+
+		// Claims to match; an unset affiliation or entitlement matches any value
+		field.String("schac_home_organization").NotEmpty(),
+		field.String("affiliation").Optional().Nillable(),
+		field.String("entitlement").Optional().Nillable(),
+
+		field.Time("created_at").Default(time.Now).Immutable(),
+	}
+}
+
+func (ProvisioningRule) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("root_node", OrganisationNode.Type).
+			Field("root_node_id").
+			Unique().
+			Required(),
+
+		edge.To("role_scope", RoleScope.Type).
+			Field("role_scope_id").
+			Unique().
+			Required(),
+	}
+}
+
+func (ProvisioningRule) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("root_node_id"),
+		index.Fields("schac_home_organization"),
+	}
+}
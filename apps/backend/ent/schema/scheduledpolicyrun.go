@@ -0,0 +1,32 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/google/uuid"
+)
+
+// ScheduledPolicyRun records that a scheduled policy fired for a project,
+// so that each reminder is sent only once per due date.
+type ScheduledPolicyRun struct {
+	ent.Schema
+}
+
+func (ScheduledPolicyRun) Fields() []ent.Field {
+	return []ent.Field{
+		field.UUID("id", uuid.UUID{}).Default(uuid.New),
+		field.UUID("policy_id", uuid.UUID{}),
+		field.UUID("project_id", uuid.UUID{}),
+		field.Time("due_at"),
+		field.Time("created_at").Default(time.Now).Immutable(),
+	}
+}
+
+func (ScheduledPolicyRun) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("policy_id", "project_id", "due_at").Unique(),
+	}
+}
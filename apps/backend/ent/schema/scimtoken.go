@@ -0,0 +1,46 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/google/uuid"
+)
+
+// ScimToken authorises an identity management system to provision the users of a root node
+type ScimToken struct {
+	ent.Schema
+}
+
+func (ScimToken) Fields() []ent.Field {
+	return []ent.Field{
+		field.UUID("id", uuid.UUID{}).Default(uuid.New),
+
+		field.UUID("root_node_id", uuid.UUID{}),
+		field.String("name").NotEmpty(),
+		// SHA-256 of the bearer token; the token itself is shown once at creation
+		field.String("token_hash").NotEmpty().Unique().Sensitive(),
+
+		field.UUID("created_by_user_id", uuid.UUID{}).Optional().Nillable(),
+		field.Time("created_at").Default(time.Now).Immutable(),
+		field.Time("last_used_at").Optional().Nillable(),
+	}
+}
+
+func (ScimToken) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("root_node", OrganisationNode.Type).
+			Field("root_node_id").
+			Unique().
+			Required(),
+	}
+}
+
+func (ScimToken) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("root_node_id"),
+	}
+}
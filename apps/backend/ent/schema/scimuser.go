@@ -0,0 +1,51 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/google/uuid"
+)
+
+// ScimUser links a user to the root node whose identity management system provisions it
+type ScimUser struct {
+	ent.Schema
+}
+
+func (ScimUser) Fields() []ent.Field {
+	return []ent.Field{
+		field.UUID("id", uuid.UUID{}).Default(uuid.New),
+
+		field.UUID("root_node_id", uuid.UUID{}),
+		field.UUID("user_id", uuid.UUID{}),
+		// The identifier the identity management system knows the user by
+		field.String("external_id").Optional().Nillable(),
+
+		field.Time("created_at").Default(time.Now).Immutable(),
+	}
+}
+
+func (ScimUser) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("root_node", OrganisationNode.Type).
+			Field("root_node_id").
+			Unique().
+			Required(),
+
+		edge.To("user", User.Type).
+			Field("user_id").
+			Unique().
+			Required(),
+	}
+}
+
+func (ScimUser) Indexes() []ent.Index {
+	return []ent.Index{
+		// A user is provisioned by one identity management system
+		index.Fields("user_id").Unique(),
+		index.Fields("root_node_id", "external_id").Unique(),
+	}
+}
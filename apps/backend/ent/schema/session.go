@@ -0,0 +1,57 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/google/uuid"
+)
+
+// Session is a signed-in device of a user; its refresh token is rotated on every use
+type Session struct {
+	ent.Schema
+}
+
+func (Session) Fields() []ent.Field {
+	return []ent.Field{
+		field.UUID("id", uuid.UUID{}).Default(uuid.New),
+
+		field.UUID("user_id", uuid.UUID{}),
+		// SHA-256 of the current refresh token
+		field.String("refresh_token_hash").NotEmpty().Unique().Sensitive(),
+		// SHA-256 of the refresh token it replaced; presenting it again revokes the session
+		field.String("previous_token_hash").Optional().Nillable().Sensitive(),
+
+		field.String("user_agent").Optional().Nillable(),
+		field.String("ip_address").Optional().Nillable(),
+
+		// Set when a sysadmin acts as the user; such sessions end at expires_at and are kept for audit
+		field.UUID("impersonator_user_id", uuid.UUID{}).Optional().Nillable(),
+		field.String("impersonation_reason").Optional().Nillable(),
+
+		field.Time("created_at").Default(time.Now).Immutable(),
+		field.Time("last_used_at").Default(time.Now),
+		field.Time("expires_at"),
+		field.Time("revoked_at").Optional().Nillable(),
+	}
+}
+
+func (Session) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("user", User.Type).
+			Field("user_id").
+			Unique().
+			Required(),
+	}
+}
+
+func (Session) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("user_id"),
+		index.Fields("previous_token_hash"),
+		index.Fields("impersonator_user_id"),
+	}
+}
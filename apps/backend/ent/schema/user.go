@@ -20,6 +20,10 @@ func (User) Fields() []ent.Field {
 		field.String("password").Optional().Sensitive(),
 		field.Bool("is_sys_admin").Default(false),
 		field.Bool("is_active").Default(true),
+		// Service accounts cannot sign in and authenticate with API tokens only
+		field.Bool("is_service_account").Default(false),
+		// Preferred language of notifications and emails
+		field.Enum("language").Values("en", "nl").Default("en"),
 		// Zenodo OAuth tokens
 		field.String("zenodo_access_token").
 			Optional().
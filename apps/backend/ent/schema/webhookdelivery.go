@@ -0,0 +1,56 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/google/uuid"
+)
+
+// WebhookDelivery holds the schema definition for the persisted webhook delivery queue.
+type WebhookDelivery struct {
+	ent.Schema
+}
+
+// Fields of the WebhookDelivery.
+func (WebhookDelivery) Fields() []ent.Field {
+	return []ent.Field{
+		field.UUID("id", uuid.UUID{}).Default(uuid.New).Immutable().Unique(),
+
+		// Source of the delivery
+		field.UUID("policy_id", uuid.UUID{}).Optional().Nillable().Comment("Event policy that triggered the delivery"),
+		field.UUID("subscription_id", uuid.UUID{}).Optional().Nillable().Comment("Webhook subscription the delivery belongs to"),
+		field.UUID("event_id", uuid.UUID{}).Optional().Nillable().Comment("Project event the delivery describes"),
+		field.String("event_type"),
+
+		// Request
+		field.String("url").NotEmpty().Comment("Endpoint the payload is POSTed to"),
+		field.Text("payload").Comment("JSON envelope, signed as-is on every attempt"),
+
+		// Delivery state
+		field.Enum("status").Values("pending", "succeeded", "failed").Default("pending"),
+		field.Int("attempts").Default(0),
+		field.Time("next_attempt_at").Default(time.Now),
+		field.Time("last_attempt_at").Optional().Nillable(),
+		field.Int("last_status_code").Optional().Nillable(),
+		field.Text("last_error").Optional().Nillable(),
+
+		field.Time("created_at").Default(time.Now).Immutable(),
+	}
+}
+
+// Edges of the WebhookDelivery.
+func (WebhookDelivery) Edges() []ent.Edge {
+	return nil
+}
+
+// Indexes of the WebhookDelivery.
+func (WebhookDelivery) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("status", "next_attempt_at"),
+		index.Fields("policy_id"),
+		index.Fields("subscription_id"),
+	}
+}
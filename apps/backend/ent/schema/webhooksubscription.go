@@ -0,0 +1,50 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/google/uuid"
+)
+
+// WebhookSubscription sends every event of the subscribed types for projects owned by an
+// organisation node (and optionally its descendants) to an endpoint.
+type WebhookSubscription struct {
+	ent.Schema
+}
+
+// Fields of the WebhookSubscription.
+func (WebhookSubscription) Fields() []ent.Field {
+	return []ent.Field{
+		field.UUID("id", uuid.UUID{}).Default(uuid.New).Immutable().Unique(),
+		field.String("name").NotEmpty(),
+
+		// Scope
+		field.UUID("org_node_id", uuid.UUID{}),
+		field.Bool("include_descendants").Default(true),
+		// Event types to deliver; empty means all event types
+		field.Strings("event_types").Optional(),
+
+		// Endpoint
+		field.String("url").NotEmpty().Comment("Endpoint the payload is POSTed to"),
+		field.String("secret").Sensitive().Comment("HMAC signing secret, generated by the server"),
+		field.Time("secret_rotated_at").Default(time.Now),
+		// The secret replaced by the last rotation; deliveries are signed with it as well until it expires
+		field.String("previous_secret").Optional().Nillable().Sensitive(),
+		field.Time("previous_secret_expires_at").Optional().Nillable(),
+
+		field.Bool("enabled").Default(true),
+		field.UUID("created_by", uuid.UUID{}).Optional().Nillable(),
+		field.Time("created_at").Default(time.Now).Immutable(),
+		field.Time("updated_at").Default(time.Now).UpdateDefault(time.Now),
+	}
+}
+
+// Indexes of the WebhookSubscription.
+func (WebhookSubscription) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("org_node_id"),
+	}
+}
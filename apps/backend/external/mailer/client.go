@@ -0,0 +1,174 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrRejected is returned when the server permanently rejects a message (a 5xx reply),
+// e.g. because the recipient does not exist. Retrying will not help.
+var ErrRejected = errors.New("mail_rejected")
+
+// Message is a multipart/alternative email with a plain-text and an HTML body
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Client sends email over SMTP
+type Client interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+type client struct {
+	config *Config
+}
+
+// NewClient creates a new SMTP client
+func NewClient(config *Config) Client {
+	return &client{config: config}
+}
+
+func (c *client) Send(ctx context.Context, msg Message) error {
+	from, err := mail.ParseAddress(c.config.From)
+	if err != nil {
+		return fmt.Errorf("parse from address: %w", err)
+	}
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return fmt.Errorf("%w: invalid recipient %q", ErrRejected, msg.To)
+	}
+
+	body, err := buildMessage(from, to, msg)
+	if err != nil {
+		return fmt.Errorf("build message: %w", err)
+	}
+
+	timeout := c.config.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(c.config.Host, strconv.Itoa(c.config.Port)))
+	if err != nil {
+		return fmt.Errorf("dial: %w", err)
+	}
+	deadline := time.Now().Add(timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = conn.SetDeadline(deadline)
+
+	sc, err := smtp.NewClient(conn, c.config.Host)
+	if err != nil {
+		_ = conn.Close()
+		return classify("greeting", err)
+	}
+	defer sc.Close()
+
+	if ok, _ := sc.Extension("STARTTLS"); ok {
+		if err := sc.StartTLS(&tls.Config{ServerName: c.config.Host}); err != nil {
+			return classify("starttls", err)
+		}
+	}
+	if c.config.Username != "" {
+		auth := smtp.PlainAuth("", c.config.Username, c.config.Password, c.config.Host)
+		if err := sc.Auth(auth); err != nil {
+			return classify("auth", err)
+		}
+	}
+
+	if err := sc.Mail(from.Address); err != nil {
+		return classify("mail from", err)
+	}
+	if err := sc.Rcpt(to.Address); err != nil {
+		return classify("rcpt to", err)
+	}
+	w, err := sc.Data()
+	if err != nil {
+		return classify("data", err)
+	}
+	if _, err := w.Write(body); err != nil {
+		return classify("write", err)
+	}
+	if err := w.Close(); err != nil {
+		return classify("end data", err)
+	}
+	return sc.Quit()
+}
+
+// classify wraps permanent (5xx) SMTP replies in ErrRejected
+func classify(stage string, err error) error {
+	var tpErr *textproto.Error
+	if errors.As(err, &tpErr) && tpErr.Code >= 500 {
+		return fmt.Errorf("%w: %s: %d %s", ErrRejected, stage, tpErr.Code, tpErr.Msg)
+	}
+	return fmt.Errorf("%s: %w", stage, err)
+}
+
+// buildMessage renders the RFC 5322 message with quoted-printable text and HTML alternatives
+func buildMessage(from, to *mail.Address, msg Message) ([]byte, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+
+	header := func(k, v string) { fmt.Fprintf(&buf, "%s: %s\r\n", k, v) }
+	header("From", from.String())
+	header("To", to.String())
+	header("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", messageID(from.Address))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "multipart/alternative; boundary="+mw.Boundary())
+	buf.WriteString("\r\n")
+
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", msg.Text},
+		{"text/html; charset=utf-8", msg.HTML},
+	} {
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(pw)
+		if _, err := qp.Write([]byte(part.body)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func messageID(from string) string {
+	domain := "localhost"
+	if _, d, ok := strings.Cut(from, "@"); ok {
+		domain = d
+	}
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(b), domain)
+}
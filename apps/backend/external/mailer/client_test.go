@@ -0,0 +1,154 @@
+package mailer
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+// fakeSMTP is a minimal local SMTP stand-in. Recipients starting with "bounce" are
+// rejected with a permanent 550 reply, accepted messages are sent on received.
+type fakeSMTP struct {
+	ln       net.Listener
+	received chan string
+}
+
+func newFakeSMTP(t *testing.T) *fakeSMTP {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &fakeSMTP{ln: ln, received: make(chan string, 4)}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeSMTP) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 localhost ESMTP fake")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(cmd, "MAIL FROM"):
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO"):
+			if strings.Contains(cmd, "<BOUNCE") {
+				reply("550 5.1.1 mailbox unavailable")
+				continue
+			}
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 end data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(l)
+			}
+			s.received <- data.String()
+			reply("250 OK queued")
+		case cmd == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func (s *fakeSMTP) config() *Config {
+	addr := s.ln.Addr().(*net.TCPAddr)
+	return &Config{Host: "127.0.0.1", Port: addr.Port, From: "MORIS <no-reply@moris.local>"}
+}
+
+func TestClient_SendMultipart(t *testing.T) {
+	server := newFakeSMTP(t)
+	client := NewClient(server.config())
+
+	err := client.Send(context.Background(), Message{
+		To:      "Ada <ada@example.org>",
+		Subject: "[MORIS] Approval requested: Ünïcode",
+		Text:    "Please review",
+		HTML:    "<p>Please review</p>",
+	})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(<-server.received))
+	if err != nil {
+		t.Fatalf("parse message: %v", err)
+	}
+	subject, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if subject != "[MORIS] Approval requested: Ünïcode" {
+		t.Fatalf("unexpected subject %q", subject)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("unexpected content type %q (%v)", mediaType, err)
+	}
+
+	var types []string
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if err != nil {
+			break
+		}
+		types = append(types, part.Header.Get("Content-Type"))
+	}
+	if len(types) != 2 || !strings.HasPrefix(types[0], "text/plain") || !strings.HasPrefix(types[1], "text/html") {
+		t.Fatalf("unexpected parts %v", types)
+	}
+}
+
+func TestClient_PermanentRejection(t *testing.T) {
+	server := newFakeSMTP(t)
+	client := NewClient(server.config())
+
+	err := client.Send(context.Background(), Message{To: "bounce@example.org", Subject: "x", Text: "x", HTML: "x"})
+	if !errors.Is(err, ErrRejected) {
+		t.Fatalf("expected ErrRejected, got %v", err)
+	}
+
+	// A server that cannot be reached is a temporary failure
+	ln, _ := net.Listen("tcp", "127.0.0.1:0")
+	port := ln.Addr().(*net.TCPAddr).Port
+	_ = ln.Close()
+	cfg := server.config()
+	cfg.Port = port
+	err = NewClient(cfg).Send(context.Background(), Message{To: "ada@example.org"})
+	if err == nil || errors.Is(err, ErrRejected) {
+		t.Fatalf("expected a temporary error, got %v", err)
+	}
+}
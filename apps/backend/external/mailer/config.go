@@ -0,0 +1,16 @@
+package mailer
+
+import "time"
+
+// Config holds the configuration of the SMTP server used for outgoing mail
+type Config struct {
+	// Host is the SMTP server host; mail is disabled when it is empty
+	Host string
+	Port int
+	// Username and Password enable PLAIN authentication when set
+	Username string
+	Password string
+	// From is the sender address, e.g. "MORIS <no-reply@example.org>"
+	From    string
+	Timeout time.Duration
+}
@@ -0,0 +1,26 @@
+package di
+
+import (
+	exmailer "github.com/SURF-Innovatie/MORIS/external/mailer"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/env"
+	"github.com/samber/do/v2"
+)
+
+var Package = do.Package(
+	do.Lazy(provideMailerClient),
+)
+
+// provideMailerClient returns a nil client when no SMTP host is configured, which disables email delivery
+func provideMailerClient(i do.Injector) (exmailer.Client, error) {
+	if env.Global.SMTP.Host == "" {
+		return nil, nil
+	}
+	cfg := &exmailer.Config{
+		Host:     env.Global.SMTP.Host,
+		Port:     env.Global.SMTP.Port,
+		Username: env.Global.SMTP.Username,
+		Password: env.Global.SMTP.Password,
+		From:     env.Global.SMTP.From,
+	}
+	return exmailer.NewClient(cfg), nil
+}
@@ -0,0 +1,16 @@
+package di
+
+import (
+	"net/http"
+
+	"github.com/SURF-Innovatie/MORIS/external/ror"
+	"github.com/samber/do/v2"
+)
+
+var Package = do.Package(
+	do.Lazy(provideRORClient),
+)
+
+func provideRORClient(i do.Injector) (*ror.Client, error) {
+	return ror.NewClient(http.DefaultClient), nil
+}
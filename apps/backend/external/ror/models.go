@@ -1,5 +1,10 @@
 package ror
 
+import (
+	"slices"
+	"strings"
+)
+
 // OrganizationStatus represents the status of an organization.
 type OrganizationStatus string
 
@@ -78,8 +83,10 @@ type OrganizationLocation struct {
 
 // Organization represents a Research Organization Registry (ROR) organization.
 type Organization struct {
-	Id                 string                     `json:"id"`
-	Name               string                     `json:"name"`
+	Id   string `json:"id"`
+	Name string `json:"name"`
+	// Names replaces Name in version 2 of the API
+	Names              []OrganizationName         `json:"names,omitempty"`
 	Domains            []string                   `json:"domains,omitempty"`
 	Established        *int                       `json:"established,omitempty"`
 	Links              []string                   `json:"links,omitempty"`
@@ -91,6 +98,28 @@ type Organization struct {
 	Locations []OrganizationLocation `json:"locations,omitempty"`
 }
 
+// DisplayName returns the ror_display name of a version 2 record, falling back to the version 1 name.
+func (o Organization) DisplayName() string {
+	for _, n := range o.Names {
+		if slices.Contains(n.Types, OrganizationNameTypeRorDisplay) {
+			return n.Value
+		}
+	}
+	return o.Name
+}
+
+// RelatedIds returns the IDs of the organizations related by the given type. Version 1 of the API
+// capitalises the types and version 2 does not, so they are compared case-insensitively.
+func (o Organization) RelatedIds(t OrganizationRelationshipType) []string {
+	var ids []string
+	for _, r := range o.Relationships {
+		if strings.EqualFold(string(r.Type), string(t)) {
+			ids = append(ids, r.Id)
+		}
+	}
+	return ids
+}
+
 // MetadataCounter is an interface for types that have an ID and a generic count.
 type MetadataCounter interface {
 	GetId() string
@@ -28,6 +28,11 @@ type Claims struct {
 	Name       string `json:"name"`
 	GivenName  string `json:"given_name"`
 	FamilyName string `json:"family_name"`
+
+	// Institution attributes, used to provision organisation memberships
+	SchacHomeOrganization string   `json:"schac_home_organization"`
+	EduPersonAffiliation  []string `json:"eduperson_affiliation"`
+	EduPersonEntitlement  []string `json:"eduperson_entitlement"`
 }
 
 func (c *Client) oauthConfig(provider *oidc.Provider) (*oauth2.Config, error) {
@@ -104,7 +109,7 @@ func (c *Client) ExchangeCode(ctx context.Context, code string) (*Claims, error)
 	}
 
 	// SURFconext can provide additional attributes/claims via the userinfo endpoint.
-	if claims.Email == "" {
+	if claims.Email == "" || claims.SchacHomeOrganization == "" {
 		ui, err := provider.UserInfo(ctx, oauth2.StaticTokenSource(tok))
 		if err == nil {
 			_ = ui.Claims(claims)
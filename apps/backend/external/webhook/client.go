@@ -0,0 +1,52 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Client POSTs webhook payloads to third-party endpoints
+type Client interface {
+	Post(ctx context.Context, url string, body []byte, headers map[string]string) (int, error)
+}
+
+type client struct {
+	httpClient *http.Client
+}
+
+func NewClient() Client {
+	return &client{httpClient: &http.Client{Timeout: 15 * time.Second}}
+}
+
+func NewClientWithHTTP(httpClient *http.Client) Client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 15 * time.Second}
+	}
+	return &client{httpClient: httpClient}
+}
+
+func (c *client) Post(ctx context.Context, url string, body []byte, headers map[string]string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("create request: %w", err)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("User-Agent", "MORIS-Webhooks/1.0")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Drain a bounded amount of the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	return resp.StatusCode, nil
+}
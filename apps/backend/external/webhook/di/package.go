@@ -0,0 +1,18 @@
+package di
+
+import (
+	"time"
+
+	exwebhook "github.com/SURF-Innovatie/MORIS/external/webhook"
+	"github.com/SURF-Innovatie/MORIS/internal/common/netguard"
+	"github.com/samber/do/v2"
+)
+
+var Package = do.Package(
+	do.Lazy(provideWebhookClient),
+)
+
+func provideWebhookClient(i do.Injector) (exwebhook.Client, error) {
+	// Endpoints are user-supplied, so connections to internal addresses are refused
+	return exwebhook.NewClientWithHTTP(netguard.NewHTTPClient(15 * time.Second)), nil
+}
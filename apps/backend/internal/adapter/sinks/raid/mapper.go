@@ -180,17 +180,22 @@ func (m *RAiDMapper) extractContributors(evts []events2.Event, persons []identit
 	for _, e := range evts {
 		switch evt := e.(type) {
 		case *events2.ProjectRoleAssigned:
+			// The position dates of the assignment win over the time it was recorded;
+			// re-assigning a role replaces the period
 			key := memberKey{personID: evt.PersonID, roleID: evt.ProjectRoleID}
 			person := lo.ToPtr(personMap[evt.PersonID])
 			states[key] = &contributorState{
 				personID:  evt.PersonID,
 				person:    person,
 				roleID:    evt.ProjectRoleID,
-				startDate: evt.OccurredAt(),
+				startDate: lo.FromPtrOr(evt.StartDate, evt.OccurredAt()),
+				endDate:   evt.EndDate,
 			}
 		case *events2.ProjectRoleUnassigned:
+			// A role removed before its end date ends the position early; the expiry job removes
+			// roles after their end date, which then stays the real end of the position
 			key := memberKey{personID: evt.PersonID, roleID: evt.ProjectRoleID}
-			if state, ok := states[key]; ok {
+			if state, ok := states[key]; ok && (state.endDate == nil || evt.OccurredAt().Before(*state.endDate)) {
 				state.endDate = lo.ToPtr(evt.OccurredAt())
 			}
 		}
@@ -239,6 +239,52 @@ func TestRAiDMapper_ContributorWithEndDate(t *testing.T) {
 	}
 }
 
+func TestRAiDMapper_ContributorUsesPositionDates(t *testing.T) {
+	mapper := raidsink.NewRAiDMapper()
+
+	projectID := uuid.New()
+	actor := uuid.New()
+	personID := uuid.New()
+	roleID := uuid.New()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	positionStart := time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC)
+	positionEnd := time.Date(2024, 8, 31, 0, 0, 0, 0, time.UTC)
+
+	assigned := makeRoleAssigned(projectID, actor, personID, roleID, start.AddDate(0, 1, 0))
+	assigned.StartDate = &positionStart
+	assigned.EndDate = &positionEnd
+
+	pc := adapter.ProjectContext{
+		ProjectID: projectID,
+		Events: []events2.Event{
+			makeProjectStarted(projectID, actor, "Test", "", start, start.AddDate(1, 0, 0)),
+			assigned,
+			// removed by the expiry job some time after the position ended
+			makeRoleUnassigned(projectID, actor, personID, roleID, positionEnd.Add(36*time.Hour)),
+		},
+		Members: []identity.Person{
+			{
+				ID:    personID,
+				Name:  "John Doe",
+				ORCiD: ptr("0000-0001-2345-6789"),
+			},
+		},
+	}
+
+	req := mapper.MapToCreateRequest(pc)
+
+	if len(req.Contributor) != 1 {
+		t.Fatalf("expected 1 contributor, got %d", len(req.Contributor))
+	}
+	pos := req.Contributor[0].Position[0]
+	if pos.StartDate != "2023-09-01" {
+		t.Errorf("expected start date '2023-09-01', got %q", pos.StartDate)
+	}
+	if pos.EndDate == nil || *pos.EndDate != "2024-08-31" {
+		t.Errorf("expected end date '2024-08-31', got %v", pos.EndDate)
+	}
+}
+
 func TestRAiDMapper_SkipContributorWithoutORCID(t *testing.T) {
 	mapper := raidsink.NewRAiDMapper()
 
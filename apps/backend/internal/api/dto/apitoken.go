@@ -0,0 +1,72 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/internal/domain/apitoken"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+type APITokenRequest struct {
+	Name string `json:"name" example:"Nightly export"`
+	// Scopes are any of projects:read, events:write and export
+	Scopes []string `json:"scopes" example:"projects:read,export"`
+	// NodeIDs limit the token to these organisation nodes and their descendants; empty means no limit
+	NodeIDs   []uuid.UUID `json:"nodeIds"`
+	ExpiresAt time.Time   `json:"expiresAt"`
+}
+
+type APITokenResponse struct {
+	ID              uuid.UUID   `json:"id"`
+	UserID          uuid.UUID   `json:"userId"`
+	Name            string      `json:"name"`
+	Scopes          []string    `json:"scopes"`
+	NodeIDs         []uuid.UUID `json:"nodeIds"`
+	CreatedByUserID *uuid.UUID  `json:"createdByUserId"`
+	CreatedAt       time.Time   `json:"createdAt"`
+	ExpiresAt       time.Time   `json:"expiresAt"`
+	LastUsedAt      *time.Time  `json:"lastUsedAt"`
+	// Token is the bearer secret, only returned when the token is created
+	Token string `json:"token,omitempty"`
+}
+
+func (r APITokenResponse) FromEntity(e apitoken.Token) APITokenResponse {
+	return APITokenResponse{
+		ID:              e.ID,
+		UserID:          e.UserID,
+		Name:            e.Name,
+		Scopes:          lo.Map(e.Scopes, func(s apitoken.Scope, _ int) string { return string(s) }),
+		NodeIDs:         e.NodeIDs,
+		CreatedByUserID: e.CreatedByUserID,
+		CreatedAt:       e.CreatedAt,
+		ExpiresAt:       e.ExpiresAt,
+		LastUsedAt:      e.LastUsedAt,
+	}
+}
+
+type ServiceAccountRequest struct {
+	Name        string  `json:"name" example:"Reporting pipeline"`
+	Description *string `json:"description"`
+	// Active defaults to true; deactivating an account deletes its tokens
+	Active *bool `json:"active"`
+}
+
+type ServiceAccountResponse struct {
+	ID uuid.UUID `json:"id"`
+	// PersonID is used to give the account organisation and project roles
+	PersonID    uuid.UUID `json:"personId"`
+	Name        string    `json:"name"`
+	Description *string   `json:"description"`
+	Active      bool      `json:"active"`
+}
+
+func (r ServiceAccountResponse) FromEntity(e apitoken.ServiceAccount) ServiceAccountResponse {
+	return ServiceAccountResponse{
+		ID:          e.UserID,
+		PersonID:    e.PersonID,
+		Name:        e.Name,
+		Description: e.Description,
+		Active:      e.Active,
+	}
+}
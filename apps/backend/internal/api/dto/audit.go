@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/internal/domain/audit"
+	"github.com/google/uuid"
+)
+
+type AuditLogEntryResponse struct {
+	ID                 uuid.UUID  `json:"id"`
+	OrganisationNodeID uuid.UUID  `json:"organisationNodeId"`
+	ActorUserID        *uuid.UUID `json:"actorUserId"` // null => system
+	ActorName          string     `json:"actorName,omitempty"`
+	EntityType         string     `json:"entityType" enums:"organisation_role,role_scope,organisation_membership,custom_field_definition,project_role,provisioning_rule"`
+	EntityID           uuid.UUID  `json:"entityId"`
+	Action             string     `json:"action" enums:"create,update,delete"`
+	// Before and After hold the entity as JSON; Before is null for creations and After for deletions
+	Before    json.RawMessage `json:"before" swaggertype:"object"`
+	After     json.RawMessage `json:"after" swaggertype:"object"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+func (r AuditLogEntryResponse) FromEntity(e audit.Entry) AuditLogEntryResponse {
+	return AuditLogEntryResponse{
+		ID:                 e.ID,
+		OrganisationNodeID: e.OrgNodeID,
+		ActorUserID:        e.ActorUserID,
+		ActorName:          e.ActorName,
+		EntityType:         string(e.EntityType),
+		EntityID:           e.EntityID,
+		Action:             string(e.Action),
+		Before:             orNull(e.Before),
+		After:              orNull(e.After),
+		CreatedAt:          e.CreatedAt,
+	}
+}
+
+// AuditLogPageResponse is a page of the audit log; pass nextCursor as cursor to fetch the next page
+type AuditLogPageResponse struct {
+	Data       []AuditLogEntryResponse `json:"data"`
+	NextCursor *string                 `json:"nextCursor,omitempty"`
+}
+
+func orNull(raw json.RawMessage) json.RawMessage {
+	if len(raw) == 0 {
+		return json.RawMessage("null")
+	}
+	return raw
+}
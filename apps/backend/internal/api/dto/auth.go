@@ -1,9 +1,14 @@
 package dto
 
 import (
+	"time"
+
+	coreauth "github.com/SURF-Innovatie/MORIS/internal/app/auth"
 	"github.com/SURF-Innovatie/MORIS/internal/common/transform"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/identity"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/identity/readmodels"
 	"github.com/google/uuid"
+	"github.com/samber/lo"
 )
 
 // RegisterRequest swagger:model RegisterRequest
@@ -29,16 +34,128 @@ type LoginRequest struct {
 }
 
 // LoginResponse swagger:model LoginResponse
-// Represents the response body for successful login.
+// Represents the response body for successful login and token refresh.
 type LoginResponse struct {
-	Token string       `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
-	User  UserResponse `json:"user"`
+	// Token is the short-lived access token
+	Token     string    `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	ExpiresAt time.Time `json:"expiresAt"`
+	// RefreshToken is exchanged at /auth/refresh for new tokens; each one can be used once
+	RefreshToken string       `json:"refreshToken"`
+	SessionID    uuid.UUID    `json:"sessionId"`
+	User         UserResponse `json:"user"`
 }
 
-func FromEntity(token string, user *readmodels.UserAccount) LoginResponse {
+func FromEntity(tokens *coreauth.Tokens, user *readmodels.UserAccount) LoginResponse {
 	return LoginResponse{
-		Token: token,
-		User:  transform.ToDTOItem[UserResponse](user),
+		Token:        tokens.AccessToken,
+		ExpiresAt:    tokens.AccessTokenExpiresAt,
+		RefreshToken: tokens.RefreshToken,
+		SessionID:    tokens.SessionID,
+		User:         transform.ToDTOItem[UserResponse](user),
+	}
+}
+
+// RefreshRequest swagger:model RefreshRequest
+// Represents the request body for exchanging a refresh token.
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken" example:"3f1c..."`
+}
+
+// SessionResponse swagger:model SessionResponse
+// Represents a signed-in device of the authenticated user.
+type SessionResponse struct {
+	ID         uuid.UUID `json:"id"`
+	UserAgent  *string   `json:"userAgent"`
+	IPAddress  *string   `json:"ipAddress"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	// Current marks the session the request was made with
+	Current bool `json:"current"`
+	// ImpersonatorUserID is set for sessions in which a sysadmin acts as the user
+	ImpersonatorUserID *uuid.UUID `json:"impersonatorUserId,omitempty"`
+}
+
+func (r SessionResponse) FromEntity(e identity.Session) SessionResponse {
+	return SessionResponse{
+		ID:         e.ID,
+		UserAgent:  e.UserAgent,
+		IPAddress:  e.IPAddress,
+		CreatedAt:  e.CreatedAt,
+		LastUsedAt: e.LastUsedAt,
+		ExpiresAt:  e.ExpiresAt,
+
+		ImpersonatorUserID: e.ImpersonatorUserID,
+	}
+}
+
+// ProfileResponse swagger:model ProfileResponse
+// Represents the authenticated user, flagged when a sysadmin is impersonating them.
+type ProfileResponse struct {
+	UserResponse
+	IsImpersonated bool                          `json:"is_impersonated"`
+	Impersonation  *ProfileImpersonationResponse `json:"impersonation,omitempty"`
+}
+
+// ProfileImpersonationResponse swagger:model ProfileImpersonationResponse
+// Represents the sysadmin acting as the user and until when.
+type ProfileImpersonationResponse struct {
+	SessionID          uuid.UUID `json:"sessionId"`
+	ImpersonatorUserID uuid.UUID `json:"impersonatorUserId"`
+	ImpersonatorName   string    `json:"impersonatorName"`
+	ImpersonatorEmail  string    `json:"impersonatorEmail"`
+	Reason             string    `json:"reason"`
+	StartedAt          time.Time `json:"startedAt"`
+	ExpiresAt          time.Time `json:"expiresAt"`
+}
+
+func (r ProfileImpersonationResponse) FromEntity(e readmodels.Impersonation) ProfileImpersonationResponse {
+	return ProfileImpersonationResponse{
+		SessionID:          e.SessionID,
+		ImpersonatorUserID: e.Impersonator.User.ID,
+		ImpersonatorName:   e.Impersonator.Person.Name,
+		ImpersonatorEmail:  e.Impersonator.Person.Email,
+		Reason:             e.Reason,
+		StartedAt:          e.StartedAt,
+		ExpiresAt:          e.ExpiresAt,
+	}
+}
+
+// ImpersonationRequest swagger:model ImpersonationRequest
+// Represents the request body for starting to impersonate a user.
+type ImpersonationRequest struct {
+	UserID uuid.UUID `json:"userId"`
+	// Reason is kept with the impersonation session for audit
+	Reason string `json:"reason" example:"Reproducing ticket #1234"`
+}
+
+// ImpersonationResponse swagger:model ImpersonationResponse
+// Represents an impersonation session, kept after it ends for audit.
+type ImpersonationResponse struct {
+	ID                 uuid.UUID  `json:"id"`
+	UserID             uuid.UUID  `json:"userId"`
+	ImpersonatorUserID uuid.UUID  `json:"impersonatorUserId"`
+	Reason             string     `json:"reason"`
+	UserAgent          *string    `json:"userAgent"`
+	IPAddress          *string    `json:"ipAddress"`
+	StartedAt          time.Time  `json:"startedAt"`
+	LastUsedAt         time.Time  `json:"lastUsedAt"`
+	ExpiresAt          time.Time  `json:"expiresAt"`
+	EndedAt            *time.Time `json:"endedAt"`
+}
+
+func (r ImpersonationResponse) FromEntity(e identity.Session) ImpersonationResponse {
+	return ImpersonationResponse{
+		ID:                 e.ID,
+		UserID:             e.UserID,
+		ImpersonatorUserID: lo.FromPtr(e.ImpersonatorUserID),
+		Reason:             lo.FromPtr(e.ImpersonationReason),
+		UserAgent:          e.UserAgent,
+		IPAddress:          e.IPAddress,
+		StartedAt:          e.CreatedAt,
+		LastUsedAt:         e.LastUsedAt,
+		ExpiresAt:          e.ExpiresAt,
+		EndedAt:            e.RevokedAt,
 	}
 }
 
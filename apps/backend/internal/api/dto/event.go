@@ -33,8 +33,14 @@ type Event struct {
 
 	Creator *PersonResponse `json:"creator,omitempty"`
 
+	// User ID of the sysadmin who executed the event while impersonating its creator
+	ImpersonatorID *uuid.UUID `json:"impersonatorId,omitempty"`
+
 	// The raw event data (input payload)
 	Data any `json:"data,omitempty"`
+
+	// Policies that matched the event and what they did
+	PolicyExecutions []PolicyExecutionResponse `json:"policyExecutions,omitempty"`
 }
 
 type EventResponse struct {
@@ -98,6 +104,7 @@ func (e Event) fromCore(ev events2.Event, projectTitle string) Event {
 		FriendlyName: ev.FriendlyName(),
 		Data:         ev,
 	}
+	dtoEvent.ImpersonatorID = ev.ImpersonatorID()
 
 	// Enrich with related IDs if available
 	if r, ok := ev.(events2.HasRelatedIDs); ok {
@@ -1,29 +1,43 @@
 package dto
 
 import (
+	"encoding/json"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/internal/app/eventpolicy"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/policy"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/webhook"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
 )
 
 // PolicyConditionDTO represents a condition for policy evaluation
 type PolicyConditionDTO struct {
-	Field    string `json:"field"`    // e.g. "event.title", "project.status"
-	Operator string `json:"operator"` // "equals", "contains", "greater_than", etc.
-	Value    any    `json:"value"`
+	Field      string `json:"field"`    // e.g. "event.title", "project.status"
+	Operator   string `json:"operator"` // "equals", "contains", "greater_than", ..., or "expression"
+	Value      any    `json:"value"`
+	Expression string `json:"expression,omitempty"` // CEL, e.g. "project.after.end_date - project.before.end_date > duration('2160h')"
 }
 
 // EventPolicyRequest is the request body for creating/updating an event policy
 type EventPolicyRequest struct {
 	Name                    string               `json:"name"`
 	Description             *string              `json:"description,omitempty"`
+	TriggerType             string               `json:"trigger_type,omitempty"` // "event" (default) | "schedule"
 	EventTypes              []string             `json:"event_types"`
+	ScheduleAnchor          *string              `json:"schedule_anchor,omitempty"`      // "project.start_date" | "project.end_date" | "event:<event type>"
+	ScheduleOffsetDays      *int                 `json:"schedule_offset_days,omitempty"` // negative = before the anchor
 	Conditions              []PolicyConditionDTO `json:"conditions,omitempty"`
-	ActionType              string               `json:"action_type"` // "notify" | "request_approval"
-	MessageTemplate         *string              `json:"message_template,omitempty"`
+	ActionType              string               `json:"action_type"`                // "notify" | "request_approval" | "webhook"
+	MessageTemplate         *string              `json:"message_template,omitempty"` // e.g. "{{#if person}}{{person.Name}}{{/if}} ends {{project.EndDate | date \"2 Jan 2006\"}}"
+	WebhookURL              *string              `json:"webhook_url,omitempty"`
+	WebhookSecret           *string              `json:"webhook_secret,omitempty"` // write-only; omit on update to keep the current secret
 	RecipientUserIDs        []string             `json:"recipient_user_ids,omitempty"`
 	RecipientProjectRoleIDs []string             `json:"recipient_project_role_ids,omitempty"`
 	RecipientOrgRoleIDs     []string             `json:"recipient_org_role_ids,omitempty"`
 	RecipientDynamic        []string             `json:"recipient_dynamic,omitempty"`
 	Enabled                 bool                 `json:"enabled"`
+	Locked                  bool                 `json:"locked"` // organisation policies only; mandatory for descendants
 }
 
 // EventPolicyResponse is the response body for an event policy
@@ -31,10 +45,15 @@ type EventPolicyResponse struct {
 	ID                      string               `json:"id"`
 	Name                    string               `json:"name"`
 	Description             *string              `json:"description,omitempty"`
+	TriggerType             string               `json:"trigger_type"`
 	EventTypes              []string             `json:"event_types"`
+	ScheduleAnchor          *string              `json:"schedule_anchor,omitempty"`
+	ScheduleOffsetDays      *int                 `json:"schedule_offset_days,omitempty"`
 	Conditions              []PolicyConditionDTO `json:"conditions,omitempty"`
 	ActionType              string               `json:"action_type"`
 	MessageTemplate         *string              `json:"message_template,omitempty"`
+	WebhookURL              *string              `json:"webhook_url,omitempty"`
+	HasWebhookSecret        bool                 `json:"has_webhook_secret"`
 	RecipientUserIDs        []string             `json:"recipient_user_ids,omitempty"`
 	RecipientProjectRoleIDs []string             `json:"recipient_project_role_ids,omitempty"`
 	RecipientOrgRoleIDs     []string             `json:"recipient_org_role_ids,omitempty"`
@@ -42,9 +61,11 @@ type EventPolicyResponse struct {
 	OrgNodeID               *string              `json:"org_node_id,omitempty"`
 	ProjectID               *string              `json:"project_id,omitempty"`
 	Enabled                 bool                 `json:"enabled"`
+	Locked                  bool                 `json:"locked"`
 	Inherited               bool                 `json:"inherited"`
 	SourceOrgNodeID         *string              `json:"source_org_node_id,omitempty"`
 	SourceOrgNodeName       *string              `json:"source_org_node_name,omitempty"`
+	Shadowed                bool                 `json:"shadowed"` // ignored because a locked policy with the same name applies
 }
 
 // FromEntity converts domain entity to DTO
@@ -56,20 +77,28 @@ func (r *EventPolicyResponse) FromEntity(e *policy.EventPolicy) {
 	r.ID = e.ID.String()
 	r.Name = e.Name
 	r.Description = e.Description
+	r.TriggerType = string(e.TriggerType)
 	r.EventTypes = e.EventTypes
+	r.ScheduleAnchor = e.ScheduleAnchor
+	r.ScheduleOffsetDays = e.ScheduleOffsetDays
 	r.ActionType = string(e.ActionType)
 	r.MessageTemplate = e.MessageTemplate
+	r.WebhookURL = e.WebhookURL
+	r.HasWebhookSecret = e.WebhookSecret != nil && *e.WebhookSecret != ""
 	r.RecipientDynamic = e.RecipientDynamic
 	r.Enabled = e.Enabled
+	r.Locked = e.Locked
 	r.Inherited = e.Inherited
+	r.Shadowed = e.Shadowed
 
 	// Convert conditions
 	r.Conditions = make([]PolicyConditionDTO, len(e.Conditions))
 	for i, c := range e.Conditions {
 		r.Conditions[i] = PolicyConditionDTO{
-			Field:    c.Field,
-			Operator: c.Operator,
-			Value:    c.Value,
+			Field:      c.Field,
+			Operator:   c.Operator,
+			Value:      c.Value,
+			Expression: c.Expression,
 		}
 	}
 
@@ -98,3 +127,172 @@ func (r *EventPolicyResponse) FromEntity(e *policy.EventPolicy) {
 	}
 	r.SourceOrgNodeName = e.SourceOrgNodeName
 }
+
+// PolicySimulationEventDTO describes a hypothetical event for a policy simulation
+type PolicySimulationEventDTO struct {
+	ProjectID uuid.UUID       `json:"project_id"`
+	Type      string          `json:"type"`
+	Input     json.RawMessage `json:"input,omitempty"`
+}
+
+// PolicySimulationRequest is the request body for a policy dry-run.
+// Either event or the from/to range must be provided.
+type PolicySimulationRequest struct {
+	Policy    EventPolicyRequest        `json:"policy"`
+	OrgNodeID *uuid.UUID                `json:"org_node_id,omitempty"` // scope of the draft policy
+	ProjectID *uuid.UUID                `json:"project_id,omitempty"`  // scope of the draft policy
+	Event     *PolicySimulationEventDTO `json:"event,omitempty"`
+	From      *time.Time                `json:"from,omitempty"`
+	To        *time.Time                `json:"to,omitempty"`
+}
+
+// PolicySimulationMatch describes an event the draft policy would have matched
+type PolicySimulationMatch struct {
+	EventID          *uuid.UUID  `json:"event_id,omitempty"` // absent for hypothetical events
+	EventType        string      `json:"event_type"`
+	ProjectID        uuid.UUID   `json:"project_id"`
+	OccurredAt       time.Time   `json:"occurred_at"`
+	RequiresApproval bool        `json:"requires_approval"`
+	RecipientUserIDs []uuid.UUID `json:"recipient_user_ids"`
+	Message          string      `json:"message"`
+}
+
+// PolicySimulationResponse is the response body for a policy dry-run
+type PolicySimulationResponse struct {
+	EvaluatedEvents int                     `json:"evaluated_events"`
+	Truncated       bool                    `json:"truncated"`
+	Matches         []PolicySimulationMatch `json:"matches"`
+}
+
+// FromEntity converts a simulation result to DTO
+func (r *PolicySimulationResponse) FromEntity(e *eventpolicy.SimulationResult) {
+	if e == nil {
+		return
+	}
+
+	r.EvaluatedEvents = e.EvaluatedEvents
+	r.Truncated = e.Truncated
+	r.Matches = make([]PolicySimulationMatch, len(e.Matches))
+	for i, m := range e.Matches {
+		match := PolicySimulationMatch{
+			EventType:        m.EventType,
+			ProjectID:        m.ProjectID,
+			OccurredAt:       m.OccurredAt,
+			RequiresApproval: m.RequiresApproval,
+			RecipientUserIDs: m.RecipientUserIDs,
+			Message:          m.Message,
+		}
+		if m.EventID != uuid.Nil {
+			id := m.EventID
+			match.EventID = &id
+		}
+		r.Matches[i] = match
+	}
+}
+
+// WebhookDeliveryResponse is an entry in a webhook endpoint's delivery log
+type WebhookDeliveryResponse struct {
+	ID             uuid.UUID       `json:"id"`
+	PolicyID       *uuid.UUID      `json:"policy_id,omitempty"`
+	SubscriptionID *uuid.UUID      `json:"subscription_id,omitempty"`
+	EventID        *uuid.UUID      `json:"event_id,omitempty"`
+	EventType      string          `json:"event_type"`
+	URL            string          `json:"url"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	LastAttemptAt  *time.Time      `json:"last_attempt_at,omitempty"`
+	LastStatusCode *int            `json:"last_status_code,omitempty"`
+	LastError      *string         `json:"last_error,omitempty"`
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// FromEntity converts domain entity to DTO
+func (r *WebhookDeliveryResponse) FromEntity(e *webhook.Delivery) {
+	if e == nil {
+		return
+	}
+
+	r.ID = e.ID
+	r.PolicyID = e.PolicyID
+	r.SubscriptionID = e.SubscriptionID
+	r.EventID = e.EventID
+	r.EventType = e.EventType
+	r.URL = e.URL
+	r.Status = string(e.Status)
+	r.Attempts = e.Attempts
+	r.NextAttemptAt = e.NextAttemptAt
+	r.LastAttemptAt = e.LastAttemptAt
+	r.LastStatusCode = e.LastStatusCode
+	r.LastError = e.LastError
+	r.Payload = e.Payload
+	r.CreatedAt = e.CreatedAt
+}
+
+// WebhookReplayResponse reports how many deliveries were re-queued
+type WebhookReplayResponse struct {
+	Requeued int `json:"requeued"`
+}
+
+// PolicyConditionResultResponse shows how a single condition evaluated
+type PolicyConditionResultResponse struct {
+	Field    string `json:"field"`
+	Operator string `json:"operator"`
+	Expected any    `json:"expected,omitempty"`
+	Actual   string `json:"actual"`
+	Passed   bool   `json:"passed"`
+}
+
+// PolicyExecutionResponse is an entry in the policy execution log
+type PolicyExecutionResponse struct {
+	ID               uuid.UUID                       `json:"id"`
+	PolicyID         uuid.UUID                       `json:"policy_id"`
+	PolicyVersion    int                             `json:"policy_version"`
+	PolicyName       string                          `json:"policy_name"`
+	Trigger          string                          `json:"trigger"`
+	EventID          *uuid.UUID                      `json:"event_id,omitempty"`
+	EventType        string                          `json:"event_type,omitempty"`
+	ProjectID        uuid.UUID                       `json:"project_id"`
+	ActionType       string                          `json:"action_type"`
+	Outcome          string                          `json:"outcome"`
+	ConditionResults []PolicyConditionResultResponse `json:"condition_results"`
+	RecipientUserIDs []uuid.UUID                     `json:"recipient_user_ids"`
+	Message          *string                         `json:"message,omitempty"`
+	Detail           *string                         `json:"detail,omitempty"`
+	CreatedAt        time.Time                       `json:"created_at"`
+}
+
+// FromEntity converts domain entity to DTO
+func (r *PolicyExecutionResponse) FromEntity(e *policy.Execution) {
+	if e == nil {
+		return
+	}
+
+	r.ID = e.ID
+	r.PolicyID = e.PolicyID
+	r.PolicyVersion = e.PolicyVersion
+	r.PolicyName = e.PolicyName
+	r.Trigger = string(e.Trigger)
+	r.EventID = e.EventID
+	r.EventType = e.EventType
+	r.ProjectID = e.ProjectID
+	r.ActionType = string(e.ActionType)
+	r.Outcome = string(e.Outcome)
+	r.ConditionResults = lo.Map(e.ConditionResults, func(c policy.ConditionResult, _ int) PolicyConditionResultResponse {
+		return PolicyConditionResultResponse(c)
+	})
+	r.RecipientUserIDs = lo.CoalesceSliceOrEmpty(e.RecipientUserIDs)
+	r.Message = e.Message
+	r.Detail = e.Detail
+	r.CreatedAt = e.CreatedAt
+}
+
+// PolicyExecutionPaginatedResponse is a page of a policy's execution history
+type PolicyExecutionPaginatedResponse struct {
+	Data       []PolicyExecutionResponse `json:"data"`
+	Total      int                       `json:"total"`
+	Page       int                       `json:"page"`
+	PageSize   int                       `json:"page_size"`
+	TotalPages int                       `json:"total_pages"`
+}
@@ -13,26 +13,97 @@ type NotificationResponse struct {
 	EventID   *uuid.UUID `json:"event_id,omitempty"`
 	ProjectID *uuid.UUID `json:"project_id,omitempty"`
 
-	Message           string    `json:"message"`
-	Type              string    `json:"type"`
-	Read              bool      `json:"read"`
-	SentAt            time.Time `json:"sent_at"`
-	EventFriendlyName string    `json:"event_friendly_name,omitempty"`
+	Message           string     `json:"message"`
+	Type              string     `json:"type"`
+	Read              bool       `json:"read"`
+	SentAt            time.Time  `json:"sent_at"`
+	ArchivedAt        *time.Time `json:"archived_at,omitempty"`
+	EventFriendlyName string     `json:"event_friendly_name,omitempty"`
+
+	EmailStatus *string    `json:"email_status,omitempty"`
+	EmailError  *string    `json:"email_error,omitempty"`
+	EmailSentAt *time.Time `json:"email_sent_at,omitempty"`
 }
 
 func (r NotificationResponse) FromEntity(n notification.Notification) NotificationResponse {
 	resp := NotificationResponse{
-		ID:        n.ID,
-		UserID:    n.UserID,
-		EventID:   n.EventID,
-		ProjectID: n.ProjectID,
-		Message:   n.Message,
-		Type:      string(n.Type),
-		Read:      n.Read,
-		SentAt:    n.SentAt,
+		ID:          n.ID,
+		UserID:      n.UserID,
+		EventID:     n.EventID,
+		ProjectID:   n.ProjectID,
+		Message:     n.Message,
+		Type:        string(n.Type),
+		Read:        n.Read,
+		SentAt:      n.SentAt,
+		ArchivedAt:  n.ArchivedAt,
+		EmailError:  n.EmailError,
+		EmailSentAt: n.EmailSentAt,
+	}
+	if n.EmailStatus != nil {
+		status := string(*n.EmailStatus)
+		resp.EmailStatus = &status
 	}
 	if n.EventFriendlyName != nil {
 		resp.EventFriendlyName = *n.EventFriendlyName
 	}
 	return resp
 }
+
+// NotificationPageResponse is a page of the inbox; pass next_cursor as cursor to fetch the next page
+type NotificationPageResponse struct {
+	Data       []NotificationResponse `json:"data"`
+	NextCursor *string                `json:"next_cursor,omitempty"`
+}
+
+type NotificationUnreadCountResponse struct {
+	Total  int            `json:"total"`
+	ByType map[string]int `json:"by_type"`
+}
+
+type NotificationsUpdatedResponse struct {
+	Updated int `json:"updated"`
+}
+
+// NotificationPreferenceDTO is a delivery choice for a notification type, an event type or,
+// when neither is set, for all notifications
+type NotificationPreferenceDTO struct {
+	ID               uuid.UUID `json:"id,omitempty"`
+	NotificationType *string   `json:"notification_type,omitempty"`
+	EventType        *string   `json:"event_type,omitempty"`
+	Channel          string    `json:"channel"`             // in_app, email or none
+	Frequency        string    `json:"frequency,omitempty"` // immediate (default), daily or weekly
+}
+
+func (r NotificationPreferenceDTO) FromEntity(p notification.Preference) NotificationPreferenceDTO {
+	out := NotificationPreferenceDTO{
+		ID:        p.ID,
+		EventType: p.EventType,
+		Channel:   string(p.Channel),
+		Frequency: string(p.Frequency),
+	}
+	if p.NotificationType != nil {
+		t := string(*p.NotificationType)
+		out.NotificationType = &t
+	}
+	return out
+}
+
+func (r NotificationPreferenceDTO) ToEntity() notification.Preference {
+	p := notification.Preference{
+		EventType: r.EventType,
+		Channel:   notification.Channel(r.Channel),
+		Frequency: notification.DigestFrequency(r.Frequency),
+	}
+	if p.Frequency == "" {
+		p.Frequency = notification.DigestImmediate
+	}
+	if r.NotificationType != nil {
+		t := notification.NotificationType(*r.NotificationType)
+		p.NotificationType = &t
+	}
+	return p
+}
+
+type NotificationPreferencesRequest struct {
+	Preferences []NotificationPreferenceDTO `json:"preferences"`
+}
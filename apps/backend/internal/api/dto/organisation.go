@@ -38,6 +38,7 @@ type OrganisationResponse struct {
 	Description *string    `json:"description"`
 	AvatarURL   *string    `json:"avatarUrl"`
 	RorID       *string    `json:"rorId"`
+	RorStatus   *string    `json:"rorStatus,omitempty" enums:"active,inactive,withdrawn"`
 }
 
 func (r OrganisationResponse) FromEntity(n organisation.OrganisationNode) OrganisationResponse {
@@ -48,6 +49,7 @@ func (r OrganisationResponse) FromEntity(n organisation.OrganisationNode) Organi
 		Description: n.Description,
 		AvatarURL:   n.AvatarURL,
 		RorID:       n.RorID,
+		RorStatus:   n.RorStatus,
 	}
 }
 
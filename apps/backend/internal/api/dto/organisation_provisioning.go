@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation/rbac"
+	"github.com/google/uuid"
+)
+
+type OrganisationProvisioningRuleRequest struct {
+	RoleScopeID           uuid.UUID `json:"roleScopeId"`
+	SchacHomeOrganization string    `json:"schacHomeOrganization" example:"uva.nl"`
+	// Affiliation, when set, must be one of the eduPersonAffiliation values, e.g. "employee"
+	Affiliation *string `json:"affiliation" example:"employee"`
+	// Entitlement, when set, must be one of the eduPersonEntitlement values
+	Entitlement *string `json:"entitlement" example:"urn:mace:surf.nl:entitlement:moris:admin"`
+}
+
+type OrganisationProvisioningRuleResponse struct {
+	ID                    uuid.UUID `json:"id"`
+	RootNodeID            uuid.UUID `json:"rootNodeId"`
+	RoleScopeID           uuid.UUID `json:"roleScopeId"`
+	SchacHomeOrganization string    `json:"schacHomeOrganization"`
+	Affiliation           *string   `json:"affiliation"`
+	Entitlement           *string   `json:"entitlement"`
+	CreatedAt             time.Time `json:"createdAt"`
+}
+
+func (r OrganisationProvisioningRuleResponse) FromEntity(e rbac.ProvisioningRule) OrganisationProvisioningRuleResponse {
+	return OrganisationProvisioningRuleResponse{
+		ID:                    e.ID,
+		RootNodeID:            e.RootNodeID,
+		RoleScopeID:           e.RoleScopeID,
+		SchacHomeOrganization: e.SchacHomeOrganization,
+		Affiliation:           e.Affiliation,
+		Entitlement:           e.Entitlement,
+		CreatedAt:             e.CreatedAt,
+	}
+}
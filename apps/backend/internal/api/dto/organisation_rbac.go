@@ -1,11 +1,14 @@
 package dto
 
 import (
+	"time"
+
 	organisationrbac "github.com/SURF-Innovatie/MORIS/internal/app/organisation/rbac"
 	"github.com/SURF-Innovatie/MORIS/internal/common/transform"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation/rbac"
 	"github.com/google/uuid"
+	"github.com/samber/lo"
 )
 
 type OrganisationRoleResponse struct {
@@ -73,14 +76,24 @@ func (r OrganisationRoleScopeResponse) FromEntity(e *rbac.RoleScope) Organisatio
 }
 
 type OrganisationAddMembershipRequest struct {
-	PersonID    uuid.UUID `json:"personId"`
-	RoleScopeID uuid.UUID `json:"roleScopeId"`
+	PersonID    uuid.UUID  `json:"personId"`
+	RoleScopeID uuid.UUID  `json:"roleScopeId"`
+	StartDate   *time.Time `json:"startDate"`
+	EndDate     *time.Time `json:"endDate"`
+}
+
+type OrganisationMembershipPeriodRequest struct {
+	StartDate *time.Time `json:"startDate"` // null => valid from the start
+	EndDate   *time.Time `json:"endDate"`   // null => no end
 }
 
 type OrganisationMembershipResponse struct {
-	ID          uuid.UUID `json:"id"`
-	PersonID    uuid.UUID `json:"personId"`
-	RoleScopeID uuid.UUID `json:"roleScopeId"`
+	ID          uuid.UUID  `json:"id"`
+	PersonID    uuid.UUID  `json:"personId"`
+	RoleScopeID uuid.UUID  `json:"roleScopeId"`
+	StartDate   *time.Time `json:"startDate"`
+	EndDate     *time.Time `json:"endDate"`
+	Provisioned bool       `json:"provisioned"`
 }
 
 func (r OrganisationMembershipResponse) FromEntity(e *rbac.Membership) OrganisationMembershipResponse {
@@ -88,6 +101,9 @@ func (r OrganisationMembershipResponse) FromEntity(e *rbac.Membership) Organisat
 		ID:          e.ID,
 		PersonID:    e.PersonID,
 		RoleScopeID: e.RoleScopeID,
+		StartDate:   e.StartDate,
+		EndDate:     e.EndDate,
+		Provisioned: e.Provisioned,
 	}
 }
 
@@ -97,6 +113,8 @@ type OrganisationEffectiveMembershipResponse struct {
 
 	RoleScopeID           uuid.UUID            `json:"roleScopeId"`
 	ScopeRootOrganisation OrganisationResponse `json:"scopeRootOrganisation"`
+	StartDate             *time.Time           `json:"startDate"`
+	EndDate               *time.Time           `json:"endDate"`
 
 	RoleID         uuid.UUID      `json:"roleId"`
 	RoleKey        string         `json:"roleKey"`
@@ -115,6 +133,8 @@ func (r OrganisationEffectiveMembershipResponse) FromEntity(e organisationrbac.E
 		Person:                transform.ToDTOItem[PersonResponse](e.Person),
 		RoleScopeID:           e.RoleScopeID,
 		ScopeRootOrganisation: transform.ToDTOItem[OrganisationResponse](*e.ScopeRootOrganisation),
+		StartDate:             e.StartDate,
+		EndDate:               e.EndDate,
 		RoleID:                e.RoleID,
 		RoleKey:               e.RoleKey,
 		Permissions:           perms,
@@ -132,3 +152,84 @@ func (r OrganisationApprovalNodeResponse) FromEntity(e *organisation.Organisatio
 		ApprovalNodeID: e.ID,
 	}
 }
+
+type PermissionAncestorResponse struct {
+	NodeID uuid.UUID `json:"nodeId"`
+	Name   string    `json:"name"`
+	Depth  int       `json:"depth"`
+}
+
+type PermissionGrantPathResponse struct {
+	MembershipID  uuid.UUID  `json:"membershipId"`
+	RoleScopeID   uuid.UUID  `json:"roleScopeId"`
+	RoleID        uuid.UUID  `json:"roleId"`
+	RoleKey       string     `json:"roleKey"`
+	ScopeNodeID   uuid.UUID  `json:"scopeNodeId"`
+	ScopeNodeName string     `json:"scopeNodeName"`
+	Depth         int        `json:"depth"`
+	Permissions   []string   `json:"permissions"`
+	StartDate     *time.Time `json:"startDate"`
+	EndDate       *time.Time `json:"endDate"`
+	Active        bool       `json:"active"`
+	Grants        bool       `json:"grants"`
+}
+
+type ProjectRoleDecisionResponse struct {
+	RoleID            uuid.UUID `json:"roleId"`
+	Key               string    `json:"key"`
+	Name              string    `json:"name"`
+	AllowedEventTypes []string  `json:"allowedEventTypes"`
+	Allowed           bool      `json:"allowed"`
+}
+
+type EventExecutionExplanationResponse struct {
+	ProjectID uuid.UUID                     `json:"projectId"`
+	EventType string                        `json:"eventType"`
+	Member    bool                          `json:"member"`
+	Roles     []ProjectRoleDecisionResponse `json:"roles"`
+	// Allowed includes the sysadmin bypass
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason"`
+}
+
+type PermissionExplanationResponse struct {
+	PersonID       uuid.UUID                          `json:"personId"`
+	NodeID         uuid.UUID                          `json:"nodeId"`
+	Permission     string                             `json:"permission"`
+	Granted        bool                               `json:"granted"`
+	SysAdmin       bool                               `json:"sysAdmin"`
+	Ancestors      []PermissionAncestorResponse       `json:"ancestors"`
+	Grants         []PermissionGrantPathResponse      `json:"grants"`
+	Reason         string                             `json:"reason"`
+	EventExecution *EventExecutionExplanationResponse `json:"eventExecution,omitempty"`
+}
+
+func (r PermissionExplanationResponse) FromEntity(e *rbac.PermissionExplanation) PermissionExplanationResponse {
+	return PermissionExplanationResponse{
+		PersonID:   e.PersonID,
+		NodeID:     e.NodeID,
+		Permission: string(e.Permission),
+		Granted:    e.Granted,
+		SysAdmin:   e.SysAdmin,
+		Ancestors: lo.Map(e.Ancestors, func(a rbac.AncestorNode, _ int) PermissionAncestorResponse {
+			return PermissionAncestorResponse{NodeID: a.NodeID, Name: a.Name, Depth: a.Depth}
+		}),
+		Grants: lo.Map(e.Grants, func(g rbac.GrantPath, _ int) PermissionGrantPathResponse {
+			return PermissionGrantPathResponse{
+				MembershipID:  g.MembershipID,
+				RoleScopeID:   g.RoleScopeID,
+				RoleID:        g.RoleID,
+				RoleKey:       g.RoleKey,
+				ScopeNodeID:   g.ScopeNodeID,
+				ScopeNodeName: g.ScopeNodeName,
+				Depth:         g.Depth,
+				Permissions:   lo.Map(g.Permissions, func(p rbac.Permission, _ int) string { return string(p) }),
+				StartDate:     g.StartDate,
+				EndDate:       g.EndDate,
+				Active:        g.Active,
+				Grants:        g.Grants,
+			}
+		}),
+		Reason: e.Reason,
+	}
+}
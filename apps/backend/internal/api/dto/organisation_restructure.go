@@ -0,0 +1,96 @@
+package dto
+
+import (
+	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation"
+	"github.com/google/uuid"
+)
+
+type OrganisationMergeRequest struct {
+	TargetID uuid.UUID `json:"targetId"`
+	// DryRun reports what would change without changing anything
+	DryRun bool `json:"dryRun"`
+}
+
+type OrganisationMoveRequest struct {
+	ParentID *uuid.UUID `json:"parentId"` // null => root
+	DryRun   bool       `json:"dryRun"`
+}
+
+type OrganisationNodeUsageResponse struct {
+	ChildIDs             []uuid.UUID `json:"childIds"`
+	ProjectIDs           []uuid.UUID `json:"projectIds"`
+	ProjectRoles         int         `json:"projectRoles"`
+	OrganisationRoles    int         `json:"organisationRoles"`
+	CustomFields         int         `json:"customFields"`
+	RoleScopes           int         `json:"roleScopes"`
+	Memberships          int         `json:"memberships"`
+	EventPolicies        int         `json:"eventPolicies"`
+	ProvisioningRules    int         `json:"provisioningRules"`
+	ScimTokens           int         `json:"scimTokens"`
+	ScimUsers            int         `json:"scimUsers"`
+	APITokens            int         `json:"apiTokens"`
+	WebhookSubscriptions int         `json:"webhookSubscriptions"`
+	InUse                bool        `json:"inUse"`
+}
+
+type OrganisationSubtreeMoveResponse struct {
+	NodeIDs            []uuid.UUID `json:"nodeIds"`
+	ClosureRowsRemoved int         `json:"closureRowsRemoved"`
+	ClosureRowsAdded   int         `json:"closureRowsAdded"`
+}
+
+type OrganisationRestructureResponse struct {
+	Operation                   string                            `json:"operation" enums:"delete,merge,move"`
+	NodeID                      uuid.UUID                         `json:"nodeId"`
+	TargetID                    *uuid.UUID                        `json:"targetId"`
+	DryRun                      bool                              `json:"dryRun"`
+	Usage                       OrganisationNodeUsageResponse     `json:"usage"`
+	RenamedProjectRoles         map[string]string                 `json:"renamedProjectRoles"`
+	MergedOrganisationRoles     []string                          `json:"mergedOrganisationRoles"`
+	MergedMemberships           int                               `json:"mergedMemberships"`
+	MovedSubtrees               []OrganisationSubtreeMoveResponse `json:"movedSubtrees"`
+	ReassignedProjectIDs        []uuid.UUID                       `json:"reassignedProjectIds"`
+	DeletedWebhookSubscriptions int                               `json:"deletedWebhookSubscriptions"`
+	DeletedClosureRows          int                               `json:"deletedClosureRows"`
+}
+
+func (r OrganisationRestructureResponse) FromEntity(e organisation.RestructureReport) OrganisationRestructureResponse {
+	moves := make([]OrganisationSubtreeMoveResponse, 0, len(e.MovedChildren))
+	for _, m := range e.MovedChildren {
+		moves = append(moves, OrganisationSubtreeMoveResponse{
+			NodeIDs:            m.NodeIDs,
+			ClosureRowsRemoved: m.ClosureRowsRemoved,
+			ClosureRowsAdded:   m.ClosureRowsAdded,
+		})
+	}
+
+	return OrganisationRestructureResponse{
+		Operation: string(e.Operation),
+		NodeID:    e.NodeID,
+		TargetID:  e.TargetID,
+		DryRun:    e.DryRun,
+		Usage: OrganisationNodeUsageResponse{
+			ChildIDs:             e.Usage.ChildIDs,
+			ProjectIDs:           e.Usage.ProjectIDs,
+			ProjectRoles:         e.Usage.ProjectRoles,
+			OrganisationRoles:    e.Usage.OrganisationRoles,
+			CustomFields:         e.Usage.CustomFields,
+			RoleScopes:           e.Usage.RoleScopes,
+			Memberships:          e.Usage.Memberships,
+			EventPolicies:        e.Usage.EventPolicies,
+			ProvisioningRules:    e.Usage.ProvisioningRules,
+			ScimTokens:           e.Usage.ScimTokens,
+			ScimUsers:            e.Usage.ScimUsers,
+			APITokens:            e.Usage.APITokens,
+			WebhookSubscriptions: e.Usage.WebhookSubscriptions,
+			InUse:                e.Usage.InUse(),
+		},
+		RenamedProjectRoles:         e.Reassignment.RenamedProjectRoles,
+		MergedOrganisationRoles:     e.Reassignment.MergedOrganisationRoles,
+		MergedMemberships:           e.Reassignment.MergedMemberships,
+		MovedSubtrees:               moves,
+		ReassignedProjectIDs:        e.ReassignedProjectIDs,
+		DeletedWebhookSubscriptions: e.DeletedWebhookSubscriptions,
+		DeletedClosureRows:          e.DeletedClosureRows,
+	}
+}
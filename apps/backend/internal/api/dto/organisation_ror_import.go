@@ -0,0 +1,66 @@
+package dto
+
+import (
+	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+type OrganisationRORImportRequest struct {
+	// RorID accepts the bare ID as well as the https://ror.org/ URL
+	RorID string `json:"rorId"`
+	// ParentID places the imported organisation under an existing node
+	ParentID *uuid.UUID `json:"parentId"`
+	// DryRun reports what would change without changing anything
+	DryRun bool `json:"dryRun"`
+}
+
+type OrganisationRORImportItemResponse struct {
+	RorID            string     `json:"rorId"`
+	Name             string     `json:"name"`
+	Status           string     `json:"status" enums:"active,inactive,withdrawn"`
+	ParentRorID      *string    `json:"parentRorId"`
+	Action           string     `json:"action" enums:"create,update,unchanged,skip"`
+	Changes          []string   `json:"changes" enums:"name,parent,status"`
+	NodeID           *uuid.UUID `json:"nodeId"`
+	ParentID         *uuid.UUID `json:"parentId"`
+	PreviousName     *string    `json:"previousName,omitempty"`
+	PreviousParentID *uuid.UUID `json:"previousParentId,omitempty"`
+	PreviousStatus   *string    `json:"previousStatus,omitempty"`
+}
+
+type OrganisationRORImportResponse struct {
+	RorID     string                              `json:"rorId"`
+	DryRun    bool                                `json:"dryRun"`
+	Items     []OrganisationRORImportItemResponse `json:"items"`
+	Created   int                                 `json:"created"`
+	Updated   int                                 `json:"updated"`
+	Unchanged int                                 `json:"unchanged"`
+	Skipped   int                                 `json:"skipped"`
+}
+
+func (r OrganisationRORImportResponse) FromEntity(rep organisation.RORImportReport) OrganisationRORImportResponse {
+	return OrganisationRORImportResponse{
+		RorID:  rep.RorID,
+		DryRun: rep.DryRun,
+		Items: lo.Map(rep.Items, func(it organisation.RORImportItem, _ int) OrganisationRORImportItemResponse {
+			return OrganisationRORImportItemResponse{
+				RorID:            it.RorID,
+				Name:             it.Name,
+				Status:           it.Status,
+				ParentRorID:      it.ParentRorID,
+				Action:           string(it.Action),
+				Changes:          lo.Map(it.Changes, func(c organisation.RORImportChange, _ int) string { return string(c) }),
+				NodeID:           it.NodeID,
+				ParentID:         it.ParentID,
+				PreviousName:     it.PreviousName,
+				PreviousParentID: it.PreviousParentID,
+				PreviousStatus:   it.PreviousStatus,
+			}
+		}),
+		Created:   rep.Created,
+		Updated:   rep.Updated,
+		Unchanged: rep.Unchanged,
+		Skipped:   rep.Skipped,
+	}
+}
@@ -51,9 +51,11 @@ func (r ProjectRoleResponse) FromEntity(e role.ProjectRole) ProjectRoleResponse
 
 type ProjectMemberResponse struct {
 	PersonResponse
-	RoleID   uuid.UUID `json:"role_id"`
-	Role     string    `json:"role"`
-	RoleName string    `json:"role_name"`
+	RoleID    uuid.UUID  `json:"role_id"`
+	Role      string     `json:"role"`
+	RoleName  string     `json:"role_name"`
+	StartDate *time.Time `json:"start_date"`
+	EndDate   *time.Time `json:"end_date"`
 }
 
 func (r ProjectMemberResponse) FromEntity(e project.MemberDetail) ProjectMemberResponse {
@@ -62,6 +64,8 @@ func (r ProjectMemberResponse) FromEntity(e project.MemberDetail) ProjectMemberR
 		RoleID:         e.Role.ID,
 		Role:           e.Role.Key,
 		RoleName:       e.Role.Name,
+		StartDate:      e.StartDate,
+		EndDate:        e.EndDate,
 	}
 }
 
@@ -77,6 +81,7 @@ type ProjectResponse struct {
 	Products                []ProductResponse                `json:"products"`
 	AffiliatedOrganisations []AffiliatedOrganisationResponse `json:"affiliated_organisations"`
 	CustomFields            map[string]any                   `json:"custom_fields"`
+	Visibility              project.Visibility               `json:"visibility" enums:"private,organisation,public" example:"organisation"`
 }
 
 func (r ProjectResponse) FromEntity(d *queries.ProjectDetails) ProjectResponse {
@@ -92,5 +97,6 @@ func (r ProjectResponse) FromEntity(d *queries.ProjectDetails) ProjectResponse {
 		Products:                transform.ToDTOs[ProductResponse](d.Products),
 		AffiliatedOrganisations: transform.ToDTOs[AffiliatedOrganisationResponse](d.AffiliatedOrganisations),
 		CustomFields:            d.Project.CustomFields,
+		Visibility:              d.Project.EffectiveVisibility(),
 	}
 }
@@ -0,0 +1,210 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/internal/domain/scim"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// SCIM schema URNs (RFC 7643, RFC 7644)
+const (
+	ScimUserSchema          = "urn:ietf:params:scim:schemas:core:2.0:User"
+	ScimGroupSchema         = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	ScimListResponseSchema  = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	ScimPatchOpSchema       = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	ScimErrorSchema         = "urn:ietf:params:scim:api:messages:2.0:Error"
+	ScimServiceConfigSchema = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+)
+
+type ScimTokenRequest struct {
+	Name string `json:"name" example:"Azure AD"`
+}
+
+type ScimTokenResponse struct {
+	ID              uuid.UUID  `json:"id"`
+	RootNodeID      uuid.UUID  `json:"rootNodeId"`
+	Name            string     `json:"name"`
+	CreatedByUserID *uuid.UUID `json:"createdByUserId"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	LastUsedAt      *time.Time `json:"lastUsedAt"`
+	// Token is the bearer secret, only returned when the token is created
+	Token string `json:"token,omitempty"`
+}
+
+func (r ScimTokenResponse) FromEntity(e scim.Token) ScimTokenResponse {
+	return ScimTokenResponse{
+		ID:              e.ID,
+		RootNodeID:      e.RootNodeID,
+		Name:            e.Name,
+		CreatedByUserID: e.CreatedByUserID,
+		CreatedAt:       e.CreatedAt,
+		LastUsedAt:      e.LastUsedAt,
+	}
+}
+
+type ScimMeta struct {
+	ResourceType string     `json:"resourceType"`
+	Created      *time.Time `json:"created,omitempty"`
+	Location     string     `json:"location"`
+}
+
+type ScimName struct {
+	Formatted  string  `json:"formatted,omitempty"`
+	GivenName  *string `json:"givenName,omitempty"`
+	FamilyName *string `json:"familyName,omitempty"`
+}
+
+type ScimEmail struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// ScimUser is a User resource. userName is the email address of the person.
+type ScimUser struct {
+	Schemas     []string    `json:"schemas"`
+	ID          string      `json:"id,omitempty"`
+	ExternalID  *string     `json:"externalId,omitempty"`
+	UserName    string      `json:"userName"`
+	DisplayName string      `json:"displayName,omitempty"`
+	Name        *ScimName   `json:"name,omitempty"`
+	Emails      []ScimEmail `json:"emails,omitempty"`
+	// Active defaults to true when a request leaves it out
+	Active *bool     `json:"active,omitempty"`
+	Meta   *ScimMeta `json:"meta,omitempty"`
+}
+
+func (r ScimUser) FromEntity(e scim.User, location string) ScimUser {
+	return ScimUser{
+		Schemas:     []string{ScimUserSchema},
+		ID:          e.ID.String(),
+		ExternalID:  e.ExternalID,
+		UserName:    e.UserName,
+		DisplayName: e.DisplayName,
+		Name: &ScimName{
+			Formatted:  e.DisplayName,
+			GivenName:  e.GivenName,
+			FamilyName: e.FamilyName,
+		},
+		Emails: []ScimEmail{{Value: e.Email, Type: "work", Primary: true}},
+		Active: lo.ToPtr(e.Active),
+		Meta:   &ScimMeta{ResourceType: "User", Created: &e.Created, Location: location},
+	}
+}
+
+// ToEntity returns the attributes of a create or replace request
+func (r ScimUser) ToEntity() scim.User {
+	u := scim.User{
+		ExternalID:  r.ExternalID,
+		UserName:    r.UserName,
+		DisplayName: r.DisplayName,
+		Active:      lo.FromPtrOr(r.Active, true),
+	}
+	if r.Name != nil {
+		if u.DisplayName == "" {
+			u.DisplayName = r.Name.Formatted
+		}
+		u.GivenName = r.Name.GivenName
+		u.FamilyName = r.Name.FamilyName
+	}
+	for i, e := range r.Emails {
+		if e.Primary || i == 0 {
+			u.Email = e.Value
+		}
+	}
+	return u
+}
+
+type ScimMember struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+// ScimGroup is a Group resource: a role on an organisation node
+type ScimGroup struct {
+	Schemas     []string     `json:"schemas"`
+	ID          string       `json:"id,omitempty"`
+	DisplayName string       `json:"displayName"`
+	Members     []ScimMember `json:"members"`
+	Meta        *ScimMeta    `json:"meta,omitempty"`
+}
+
+func (r ScimGroup) FromEntity(e scim.Group, location, usersLocation string) ScimGroup {
+	return ScimGroup{
+		Schemas:     []string{ScimGroupSchema},
+		ID:          e.ID.String(),
+		DisplayName: e.DisplayName,
+		Members: lo.Map(e.Members, func(m scim.Member, _ int) ScimMember {
+			return ScimMember{Value: m.UserID.String(), Display: m.Display, Ref: usersLocation + "/" + m.UserID.String()}
+		}),
+		Meta: &ScimMeta{ResourceType: "Group", Location: location},
+	}
+}
+
+// ToEntity returns the attributes of a replace request; members that are not user IDs are invalid
+func (r ScimGroup) ToEntity() (scim.Group, error) {
+	g := scim.Group{DisplayName: r.DisplayName, Members: make([]scim.Member, 0, len(r.Members))}
+	for _, m := range r.Members {
+		id, err := uuid.Parse(m.Value)
+		if err != nil {
+			return g, scim.ErrInvalidValue
+		}
+		g.Members = append(g.Members, scim.Member{UserID: id})
+	}
+	return g, nil
+}
+
+type ScimListResponse[T any] struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    []T      `json:"Resources"`
+}
+
+type ScimPatchRequest struct {
+	Schemas    []string              `json:"schemas"`
+	Operations []scim.PatchOperation `json:"Operations"`
+}
+
+type ScimError struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+type ScimSupported struct {
+	Supported bool `json:"supported"`
+}
+
+type ScimFilterSupport struct {
+	Supported  bool `json:"supported"`
+	MaxResults int  `json:"maxResults"`
+}
+
+type ScimBulkSupport struct {
+	Supported      bool `json:"supported"`
+	MaxOperations  int  `json:"maxOperations"`
+	MaxPayloadSize int  `json:"maxPayloadSize"`
+}
+
+type ScimAuthenticationScheme struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type ScimServiceProviderConfig struct {
+	Schemas               []string                   `json:"schemas"`
+	Patch                 ScimSupported              `json:"patch"`
+	Bulk                  ScimBulkSupport            `json:"bulk"`
+	Filter                ScimFilterSupport          `json:"filter"`
+	ChangePassword        ScimSupported              `json:"changePassword"`
+	Sort                  ScimSupported              `json:"sort"`
+	Etag                  ScimSupported              `json:"etag"`
+	AuthenticationSchemes []ScimAuthenticationScheme `json:"authenticationSchemes"`
+}
@@ -24,6 +24,10 @@ type UserToggleActiveRequest struct {
 	IsActive bool `json:"is_active"`
 }
 
+type UserLanguageRequest struct {
+	Language string `json:"language" example:"nl"`
+}
+
 type UserResponse struct {
 	ID          uuid.UUID `json:"id"`
 	PersonID    uuid.UUID `json:"person_id"`
@@ -36,6 +40,7 @@ type UserResponse struct {
 	Description *string   `json:"description"`
 	IsSysAdmin  bool      `json:"is_sys_admin"`
 	IsActive    bool      `json:"is_active"`
+	Language    string    `json:"language"`
 }
 
 func (r UserResponse) FromEntity(acc *readmodels.UserAccount) UserResponse {
@@ -54,6 +59,7 @@ func (r UserResponse) FromEntity(acc *readmodels.UserAccount) UserResponse {
 		Description: p.Description,
 		IsSysAdmin:  u.IsSysAdmin,
 		IsActive:    u.IsActive,
+		Language:    string(u.Language),
 	}
 }
 
@@ -0,0 +1,57 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/internal/domain/webhook"
+	"github.com/google/uuid"
+)
+
+// WebhookSubscriptionRequest is the request body for creating/updating a webhook subscription
+type WebhookSubscriptionRequest struct {
+	Name               string    `json:"name"`
+	OrgNodeID          uuid.UUID `json:"org_node_id"`                   // create only; the scope of a subscription cannot be changed
+	IncludeDescendants *bool     `json:"include_descendants,omitempty"` // default true
+	EventTypes         []string  `json:"event_types,omitempty"`         // empty means all event types
+	URL                string    `json:"url"`
+	Enabled            *bool     `json:"enabled,omitempty"` // default true
+}
+
+// WebhookSubscriptionResponse is the response body for a webhook subscription. The secret is
+// only included when it was just generated, i.e. on creation and secret rotation.
+type WebhookSubscriptionResponse struct {
+	ID                 uuid.UUID  `json:"id"`
+	Name               string     `json:"name"`
+	OrgNodeID          uuid.UUID  `json:"org_node_id"`
+	IncludeDescendants bool       `json:"include_descendants"`
+	EventTypes         []string   `json:"event_types"`
+	URL                string     `json:"url"`
+	Enabled            bool       `json:"enabled"`
+	Secret             *string    `json:"secret,omitempty"`
+	SecretRotatedAt    time.Time  `json:"secret_rotated_at"`
+	CreatedBy          *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+// FromEntity converts domain entity to DTO, leaving out the secret
+func (r *WebhookSubscriptionResponse) FromEntity(e *webhook.Subscription) {
+	if e == nil {
+		return
+	}
+
+	r.ID = e.ID
+	r.Name = e.Name
+	r.OrgNodeID = e.OrgNodeID
+	r.IncludeDescendants = e.IncludeDescendants
+	r.EventTypes = e.EventTypes
+	if r.EventTypes == nil {
+		r.EventTypes = []string{}
+	}
+	r.URL = e.URL
+	r.Enabled = e.Enabled
+	r.SecretRotatedAt = e.SecretRotatedAt
+	r.CreatedBy = e.CreatedBy
+	r.CreatedAt = e.CreatedAt
+	r.UpdatedAt = e.UpdatedAt
+}
@@ -9,9 +9,14 @@ import (
 	_ "github.com/SURF-Innovatie/MORIS/api/swag-docs"
 	coreauth "github.com/SURF-Innovatie/MORIS/internal/app/auth"
 	"github.com/SURF-Innovatie/MORIS/internal/app/errorlog"
+	"github.com/SURF-Innovatie/MORIS/internal/app/eventpolicy"
+	"github.com/SURF-Innovatie/MORIS/internal/app/membershipexpiry"
+	"github.com/SURF-Innovatie/MORIS/internal/app/notification"
 	"github.com/SURF-Innovatie/MORIS/internal/app/project/cachewarmup"
+	"github.com/SURF-Innovatie/MORIS/internal/app/webhook"
 	adapterhandler "github.com/SURF-Innovatie/MORIS/internal/handler/adapter"
 	affiliatedorganisationhandler "github.com/SURF-Innovatie/MORIS/internal/handler/affiliatedorganisation"
+	apitokenhandler "github.com/SURF-Innovatie/MORIS/internal/handler/apitoken"
 	authhandler "github.com/SURF-Innovatie/MORIS/internal/handler/auth"
 	crossrefhandler "github.com/SURF-Innovatie/MORIS/internal/handler/crossref"
 	doihandler "github.com/SURF-Innovatie/MORIS/internal/handler/doi"
@@ -27,8 +32,10 @@ import (
 	producthandler "github.com/SURF-Innovatie/MORIS/internal/handler/product"
 	projecthandler "github.com/SURF-Innovatie/MORIS/internal/handler/project"
 	commandHandler "github.com/SURF-Innovatie/MORIS/internal/handler/project/command"
+	scimhandler "github.com/SURF-Innovatie/MORIS/internal/handler/scim"
 	systemhandler "github.com/SURF-Innovatie/MORIS/internal/handler/system"
 	userhandler "github.com/SURF-Innovatie/MORIS/internal/handler/user"
+	webhookhandler "github.com/SURF-Innovatie/MORIS/internal/handler/webhook"
 	zenodohandler "github.com/SURF-Innovatie/MORIS/internal/handler/zenodo"
 	"github.com/SURF-Innovatie/MORIS/internal/infra/env"
 	"github.com/go-chi/chi/v5"
@@ -45,6 +52,10 @@ func SetupRouter(injector do.Injector) *chi.Mux {
 	authSvc := do.MustInvoke[coreauth.Service](injector)
 	errorLogSvc := do.MustInvoke[errorlog.Service](injector)
 	warmup := do.MustInvoke[cachewarmup.Service](injector)
+	webhookSvc := do.MustInvoke[webhook.Service](injector)
+	policyScheduler := do.MustInvoke[eventpolicy.Scheduler](injector)
+	membershipExpiry := do.MustInvoke[membershipexpiry.Job](injector)
+	notificationSvc := do.MustInvoke[notification.Service](injector)
 
 	// Get HTTP Handlers from DI Container
 	personHandler := do.MustInvoke[*personhandler.Handler](injector)
@@ -57,6 +68,10 @@ func SetupRouter(injector do.Injector) *chi.Mux {
 	organisationHandler := do.MustInvoke[*organisationhandler.Handler](injector)
 	rbacHandler := do.MustInvoke[*organisationhandler.RBACHandler](injector)
 	roleHandler := do.MustInvoke[*organisationhandler.RoleHandler](injector)
+	restructureHandler := do.MustInvoke[*organisationhandler.RestructureHandler](injector)
+	auditHandler := do.MustInvoke[*organisationhandler.AuditHandler](injector)
+	rorImportHandler := do.MustInvoke[*organisationhandler.RORImportHandler](injector)
+	provisioningHandler := do.MustInvoke[*organisationhandler.ProvisioningHandler](injector)
 	productHandler := do.MustInvoke[*producthandler.Handler](injector)
 	portfolioHandler := do.MustInvoke[*portfoliohandler.Handler](injector)
 	notificationHandler := do.MustInvoke[*notificationhandler.Handler](injector)
@@ -68,6 +83,9 @@ func SetupRouter(injector do.Injector) *chi.Mux {
 	doiHandler := do.MustInvoke[*doihandler.Handler](injector)
 	adapterHandler := do.MustInvoke[*adapterhandler.Handler](injector)
 	affiliatedOrgHandler := do.MustInvoke[*affiliatedorganisationhandler.Handler](injector)
+	webhookHandler := do.MustInvoke[*webhookhandler.Handler](injector)
+	scimHandler := do.MustInvoke[*scimhandler.Handler](injector)
+	apiTokenHandler := do.MustInvoke[*apitokenhandler.Handler](injector)
 
 	// Setup Router
 	r := chi.NewRouter()
@@ -84,6 +102,8 @@ func SetupRouter(injector do.Injector) *chi.Mux {
 		r.Use(authmiddleware.ErrorLoggingMiddleware(errorLogSvc))
 		authhandler.MountRoutes(r, authSvc, authHandler)
 		systemhandler.MountRoutes(r, systemHandler)
+		// SCIM provisioning authenticates with SCIM tokens instead of user sessions
+		scimhandler.MountRoutes(r, scimHandler)
 		r.Group(func(r chi.Router) {
 			r.Use(authmiddleware.AuthMiddleware(authSvc))
 			r.Route("/projects", func(r chi.Router) {
@@ -93,7 +113,7 @@ func SetupRouter(injector do.Injector) *chi.Mux {
 				// Event policy routes for projects
 				eventPolicyHandler.RegisterProjectRoutes(r)
 			})
-			organisationhandler.MountOrganisationRoutes(r, organisationHandler, rbacHandler, roleHandler)
+			organisationhandler.MountOrganisationRoutes(r, organisationHandler, rbacHandler, roleHandler, restructureHandler, auditHandler, rorImportHandler, provisioningHandler)
 			eventHandler.MountEventRoutes(r, evtHandler)
 			personhandler.MountPersonRoutes(r, personHandler)
 			orcidhandler.MountRoutes(r, orcidHandler)
@@ -114,6 +134,9 @@ func SetupRouter(injector do.Injector) *chi.Mux {
 				r.Post("/", eventPolicyHandler.CreateForOrgNode)
 			})
 			affiliatedorganisationhandler.MountRoutes(r, affiliatedOrgHandler)
+			webhookhandler.MountRoutes(r, webhookHandler)
+			scimhandler.MountTokenRoutes(r, scimHandler)
+			apitokenhandler.MountRoutes(r, apiTokenHandler)
 		})
 	})
 
@@ -127,6 +150,20 @@ func SetupRouter(injector do.Injector) *chi.Mux {
 		}
 	}()
 
+	// Deliver queued webhooks in background
+	go webhookSvc.Run(context.Background(), 15*time.Second)
+
+	// Email pending notifications in background
+	go notificationSvc.RunEmailDelivery(context.Background(), 30*time.Second)
+	go notificationSvc.RunDigests(context.Background(), 15*time.Minute)
+	go notificationSvc.RunRetention(context.Background(), 24*time.Hour)
+
+	// Evaluate scheduled (date-driven) policies in background
+	go policyScheduler.Run(context.Background(), time.Hour)
+
+	// End expired project roles and announce upcoming end dates in background
+	go membershipExpiry.Run(context.Background(), time.Hour)
+
 	// Serve the generated swagger JSON and assets and the Swagger UI at /swagger/
 	r.Get("/swagger/swagger.json", func(w http.ResponseWriter, r *http.Request) {
 		http.ServeFile(w, r, "api/swag-docs/swagger.json")
@@ -2,10 +2,14 @@ package di
 
 import (
 	"github.com/SURF-Innovatie/MORIS/internal/app/affiliatedorganisation"
+	appauth "github.com/SURF-Innovatie/MORIS/internal/app/auth"
+	organisationrbac "github.com/SURF-Innovatie/MORIS/internal/app/organisation/rbac"
 	"github.com/samber/do/v2"
 )
 
 var Package = do.Lazy(func(i do.Injector) (affiliatedorganisation.Service, error) {
 	repo := do.MustInvoke[affiliatedorganisation.Repository](i)
-	return affiliatedorganisation.NewService(repo), nil
+	curUser := do.MustInvoke[appauth.CurrentUserProvider](i)
+	rbacSvc := do.MustInvoke[organisationrbac.Service](i)
+	return affiliatedorganisation.NewService(repo, curUser, rbacSvc), nil
 })
@@ -2,8 +2,12 @@ package affiliatedorganisation
 
 import (
 	"context"
+	"fmt"
 
+	appauth "github.com/SURF-Innovatie/MORIS/internal/app/auth"
+	organisation_rbac "github.com/SURF-Innovatie/MORIS/internal/app/organisation/rbac"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/affiliatedorganisation"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation/rbac"
 	"github.com/google/uuid"
 )
 
@@ -18,12 +22,14 @@ type Service interface {
 }
 
 type service struct {
-	repo Repository
+	repo        Repository
+	currentUser appauth.CurrentUserProvider
+	rbac        organisation_rbac.Service
 }
 
 // NewService creates a new AffiliatedOrganisation service.
-func NewService(repo Repository) Service {
-	return &service{repo: repo}
+func NewService(repo Repository, currentUser appauth.CurrentUserProvider, rbacSvc organisation_rbac.Service) Service {
+	return &service{repo: repo, currentUser: currentUser, rbac: rbacSvc}
 }
 
 func (s *service) Get(ctx context.Context, id uuid.UUID) (*affiliatedorganisation.AffiliatedOrganisation, error) {
@@ -35,17 +41,47 @@ func (s *service) GetAll(ctx context.Context) ([]*affiliatedorganisation.Affilia
 }
 
 func (s *service) Create(ctx context.Context, org affiliatedorganisation.AffiliatedOrganisation) (*affiliatedorganisation.AffiliatedOrganisation, error) {
+	if err := s.authorizeManage(ctx); err != nil {
+		return nil, err
+	}
 	return s.repo.Create(ctx, org)
 }
 
 func (s *service) Update(ctx context.Context, id uuid.UUID, org affiliatedorganisation.AffiliatedOrganisation) (*affiliatedorganisation.AffiliatedOrganisation, error) {
+	if err := s.authorizeManage(ctx); err != nil {
+		return nil, err
+	}
 	return s.repo.Update(ctx, id, org)
 }
 
 func (s *service) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := s.authorizeManage(ctx); err != nil {
+		return err
+	}
 	return s.repo.Delete(ctx, id)
 }
 
 func (s *service) GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]affiliatedorganisation.AffiliatedOrganisation, error) {
 	return s.repo.GetByIDs(ctx, ids)
 }
+
+// authorizeManage checks that the current user holds the manage_affiliated_orgs permission.
+// Affiliated organisations are shared by all nodes, so the permission on any node suffices.
+func (s *service) authorizeManage(ctx context.Context) error {
+	u, err := s.currentUser.Current(ctx)
+	if err != nil {
+		return err
+	}
+	if u.IsSysAdmin {
+		return nil
+	}
+
+	ok, err := s.rbac.HasPermissionAnywhere(ctx, u.PersonID, rbac.PermissionManageAffiliatedOrgs)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: %s permission required", rbac.ErrForbidden, rbac.PermissionManageAffiliatedOrgs)
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package di
+
+import (
+	"github.com/SURF-Innovatie/MORIS/internal/app/apitoken"
+	coreauth "github.com/SURF-Innovatie/MORIS/internal/app/auth"
+	"github.com/SURF-Innovatie/MORIS/internal/app/person"
+	"github.com/SURF-Innovatie/MORIS/internal/app/user"
+	apitokenrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/apitoken"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/persistence/enttx"
+	"github.com/samber/do/v2"
+)
+
+var Package = do.Package(
+	do.Lazy(provideService),
+)
+
+func provideService(i do.Injector) (apitoken.Service, error) {
+	repo := do.MustInvoke[*apitokenrepo.EntRepo](i)
+	userSvc := do.MustInvoke[user.Service](i)
+	personSvc := do.MustInvoke[person.Service](i)
+	curUser := do.MustInvoke[coreauth.CurrentUserProvider](i)
+	txManager := do.MustInvoke[*enttx.Manager](i)
+	return apitoken.NewService(repo, userSvc, personSvc, curUser, txManager), nil
+}
@@ -0,0 +1,31 @@
+package apitoken
+
+import (
+	"context"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/internal/domain/apitoken"
+	"github.com/google/uuid"
+)
+
+type repository interface {
+	CreateToken(ctx context.Context, t apitoken.Token, hash string) (*apitoken.Token, error)
+	// ListTokens returns the tokens of a user, newest first
+	ListTokens(ctx context.Context, userID uuid.UUID) ([]apitoken.Token, error)
+	// DeleteToken removes a token of the user and reports whether it existed
+	DeleteToken(ctx context.Context, userID, id uuid.UUID) (bool, error)
+	// DeleteUserTokens removes all tokens of a user
+	DeleteUserTokens(ctx context.Context, userID uuid.UUID) error
+	// TokenByHash returns nil for an unknown token
+	TokenByHash(ctx context.Context, hash string) (*apitoken.Token, error)
+	TouchToken(ctx context.Context, id uuid.UUID, at time.Time) error
+
+	// NodesExist reports whether all of the organisation nodes exist
+	NodesExist(ctx context.Context, nodeIDs []uuid.UUID) (bool, error)
+	// Descendants returns the nodes and all of their descendants
+	Descendants(ctx context.Context, nodeIDs []uuid.UUID) ([]uuid.UUID, error)
+
+	ListServiceAccounts(ctx context.Context) ([]apitoken.ServiceAccount, error)
+	// GetServiceAccount returns nil when the user does not exist or is not a service account
+	GetServiceAccount(ctx context.Context, userID uuid.UUID) (*apitoken.ServiceAccount, error)
+}
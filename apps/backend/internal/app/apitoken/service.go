@@ -0,0 +1,259 @@
+package apitoken
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	appauth "github.com/SURF-Innovatie/MORIS/internal/app/auth"
+	"github.com/SURF-Innovatie/MORIS/internal/app/person"
+	"github.com/SURF-Innovatie/MORIS/internal/app/tx"
+	"github.com/SURF-Innovatie/MORIS/internal/app/user"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/apitoken"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/identity"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// touchInterval limits how often the last use of a token is written
+const touchInterval = time.Minute
+
+// TokenInput holds the attributes of a new token
+type TokenInput struct {
+	Name      string
+	Scopes    []string
+	NodeIDs   []uuid.UUID
+	ExpiresAt time.Time
+}
+
+// ServiceAccountInput holds the editable attributes of a service account
+type ServiceAccountInput struct {
+	Name        string
+	Description *string
+	Active      bool
+}
+
+// Service manages personal access tokens and service accounts. Tokens let scripts call the API
+// without a password; they act as their user, narrowed to their scopes and organisation nodes.
+type Service interface {
+	// ListTokens returns the tokens of a user, newest first
+	ListTokens(ctx context.Context, userID uuid.UUID) ([]apitoken.Token, error)
+	// CreateToken issues a token for a user and returns it with its secret, which is not stored
+	CreateToken(ctx context.Context, userID uuid.UUID, in TokenInput) (*apitoken.Token, string, error)
+	DeleteToken(ctx context.Context, userID, id uuid.UUID) error
+	// Authenticate returns the grant of a bearer secret
+	Authenticate(ctx context.Context, secret string) (*apitoken.Grant, error)
+
+	ListServiceAccounts(ctx context.Context) ([]apitoken.ServiceAccount, error)
+	GetServiceAccount(ctx context.Context, userID uuid.UUID) (*apitoken.ServiceAccount, error)
+	CreateServiceAccount(ctx context.Context, in ServiceAccountInput) (*apitoken.ServiceAccount, error)
+	// UpdateServiceAccount renames or (de)activates a service account. Deactivating it deletes its tokens.
+	UpdateServiceAccount(ctx context.Context, userID uuid.UUID, in ServiceAccountInput) (*apitoken.ServiceAccount, error)
+}
+
+type service struct {
+	repo        repository
+	users       user.Service
+	people      person.Service
+	currentUser appauth.CurrentUserProvider
+	tx          tx.Manager
+	now         func() time.Time
+}
+
+func NewService(
+	repo repository,
+	users user.Service,
+	people person.Service,
+	currentUser appauth.CurrentUserProvider,
+	tx tx.Manager,
+) Service {
+	return &service{
+		repo:        repo,
+		users:       users,
+		people:      people,
+		currentUser: currentUser,
+		tx:          tx,
+		now:         time.Now,
+	}
+}
+
+func (s *service) ListTokens(ctx context.Context, userID uuid.UUID) ([]apitoken.Token, error) {
+	return s.repo.ListTokens(ctx, userID)
+}
+
+func (s *service) CreateToken(ctx context.Context, userID uuid.UUID, in TokenInput) (*apitoken.Token, string, error) {
+	name := strings.TrimSpace(in.Name)
+	if name == "" {
+		return nil, "", fmt.Errorf("%w: name is required", apitoken.ErrInvalidValue)
+	}
+	scopes, err := apitoken.ParseScopes(in.Scopes)
+	if err != nil {
+		return nil, "", err
+	}
+	now := s.now()
+	if !in.ExpiresAt.After(now) || in.ExpiresAt.Sub(now) > apitoken.MaxLifetime {
+		return nil, "", apitoken.ErrInvalidExpiry
+	}
+	var createdBy *uuid.UUID
+	if p, err := s.currentUser.Current(ctx); err == nil {
+		if p.Impersonated() {
+			return nil, "", apitoken.ErrImpersonating
+		}
+		createdBy = &p.UserID
+	}
+	nodeIDs := lo.Uniq(in.NodeIDs)
+	if len(nodeIDs) > 0 {
+		ok, err := s.repo.NodesExist(ctx, nodeIDs)
+		if err != nil {
+			return nil, "", err
+		}
+		if !ok {
+			return nil, "", apitoken.ErrUnknownNode
+		}
+	}
+
+	secret, err := apitoken.NewSecret()
+	if err != nil {
+		return nil, "", err
+	}
+	t := apitoken.Token{
+		UserID:          userID,
+		Name:            name,
+		Scopes:          scopes,
+		NodeIDs:         nodeIDs,
+		CreatedByUserID: createdBy,
+		ExpiresAt:       in.ExpiresAt,
+	}
+
+	var created *apitoken.Token
+	err = s.tx.WithTx(ctx, func(ctx context.Context) error {
+		created, err = s.repo.CreateToken(ctx, t, apitoken.HashSecret(secret))
+		return err
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return created, secret, nil
+}
+
+func (s *service) DeleteToken(ctx context.Context, userID, id uuid.UUID) error {
+	ok, err := s.repo.DeleteToken(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return apitoken.ErrNotFound
+	}
+	return nil
+}
+
+func (s *service) Authenticate(ctx context.Context, secret string) (*apitoken.Grant, error) {
+	if secret == "" {
+		return nil, apitoken.ErrUnauthorized
+	}
+	t, err := s.repo.TokenByHash(ctx, apitoken.HashSecret(secret))
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	if t == nil || !t.ActiveAt(now) {
+		return nil, apitoken.ErrUnauthorized
+	}
+	if t.LastUsedAt == nil || now.Sub(*t.LastUsedAt) >= touchInterval {
+		if err := s.repo.TouchToken(ctx, t.ID, now); err != nil {
+			return nil, err
+		}
+		t.LastUsedAt = &now
+	}
+
+	grant := &apitoken.Grant{Token: *t}
+	if len(t.NodeIDs) > 0 {
+		if grant.NodeIDs, err = s.repo.Descendants(ctx, t.NodeIDs); err != nil {
+			return nil, err
+		}
+	}
+	return grant, nil
+}
+
+func (s *service) ListServiceAccounts(ctx context.Context) ([]apitoken.ServiceAccount, error) {
+	return s.repo.ListServiceAccounts(ctx)
+}
+
+func (s *service) GetServiceAccount(ctx context.Context, userID uuid.UUID) (*apitoken.ServiceAccount, error) {
+	sa, err := s.repo.GetServiceAccount(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if sa == nil {
+		return nil, apitoken.ErrNotServiceAccount
+	}
+	return sa, nil
+}
+
+func (s *service) CreateServiceAccount(ctx context.Context, in ServiceAccountInput) (*apitoken.ServiceAccount, error) {
+	name := strings.TrimSpace(in.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", apitoken.ErrInvalidValue)
+	}
+
+	var userID uuid.UUID
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		// The person only exists to hold roles, so it gets an address that cannot receive mail
+		p, err := s.people.Create(ctx, identity.Person{
+			Name:        name,
+			Email:       apitoken.ServiceAccountEmail(uuid.New()),
+			Description: in.Description,
+		})
+		if err != nil {
+			return err
+		}
+		created, err := s.users.Create(ctx, identity.User{PersonID: p.ID, IsServiceAccount: true})
+		if err != nil {
+			return err
+		}
+		userID = created.ID
+		if !in.Active {
+			return s.users.ToggleActive(ctx, userID, false)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetServiceAccount(ctx, userID)
+}
+
+func (s *service) UpdateServiceAccount(ctx context.Context, userID uuid.UUID, in ServiceAccountInput) (*apitoken.ServiceAccount, error) {
+	name := strings.TrimSpace(in.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", apitoken.ErrInvalidValue)
+	}
+	sa, err := s.GetServiceAccount(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.tx.WithTx(ctx, func(ctx context.Context) error {
+		p, err := s.people.Get(ctx, sa.PersonID)
+		if err != nil {
+			return err
+		}
+		p.Name = name
+		p.Description = in.Description
+		if _, err := s.people.Update(ctx, sa.PersonID, *p); err != nil {
+			return err
+		}
+		if err := s.users.ToggleActive(ctx, userID, in.Active); err != nil {
+			return err
+		}
+		if !in.Active {
+			return s.repo.DeleteUserTokens(ctx, userID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetServiceAccount(ctx, userID)
+}
@@ -0,0 +1,17 @@
+package di
+
+import (
+	"github.com/SURF-Innovatie/MORIS/internal/app/audit"
+	appauth "github.com/SURF-Innovatie/MORIS/internal/app/auth"
+	"github.com/samber/do/v2"
+)
+
+var Package = do.Package(
+	do.Lazy(provideAuditService),
+)
+
+func provideAuditService(i do.Injector) (audit.Service, error) {
+	repo := do.MustInvoke[audit.Repository](i)
+	curUser := do.MustInvoke[appauth.CurrentUserProvider](i)
+	return audit.NewService(repo, curUser), nil
+}
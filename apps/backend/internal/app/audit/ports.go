@@ -0,0 +1,15 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/SURF-Innovatie/MORIS/internal/domain/audit"
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	// Append stores an entry; it joins the transaction in the context, if any
+	Append(ctx context.Context, e audit.Entry) error
+	// ListPage returns a page of the entries of a node and its descendants, newest first
+	ListPage(ctx context.Context, nodeID uuid.UUID, f audit.ListFilter) (*audit.Page, error)
+}
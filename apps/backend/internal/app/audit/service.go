@@ -0,0 +1,74 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	appauth "github.com/SURF-Innovatie/MORIS/internal/app/auth"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/audit"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+)
+
+// Service keeps the append-only audit trail of organisation configuration changes.
+// Callers record a change in the same transaction as the change itself.
+type Service interface {
+	Record(ctx context.Context, c audit.Change) error
+	List(ctx context.Context, nodeID uuid.UUID, f audit.ListFilter) (*audit.Page, error)
+}
+
+type service struct {
+	repo        Repository
+	currentUser appauth.CurrentUserProvider
+}
+
+func NewService(repo Repository, currentUser appauth.CurrentUserProvider) Service {
+	return &service{repo: repo, currentUser: currentUser}
+}
+
+func (s *service) Record(ctx context.Context, c audit.Change) error {
+	before, err := marshalState(c.Before)
+	if err != nil {
+		return fmt.Errorf("encoding state before %s %s: %w", c.EntityType, c.EntityID, err)
+	}
+	after, err := marshalState(c.After)
+	if err != nil {
+		return fmt.Errorf("encoding state after %s %s: %w", c.EntityType, c.EntityID, err)
+	}
+
+	e := audit.Entry{
+		OrgNodeID:  c.OrgNodeID,
+		EntityType: c.EntityType,
+		EntityID:   c.EntityID,
+		Action:     c.Action,
+		Before:     before,
+		After:      after,
+	}
+	// Without an authenticated user the change was made by the system, e.g. when seeding defaults.
+	// Changes made while impersonating are attributed to the sysadmin.
+	if p, err := s.currentUser.Current(ctx); err == nil {
+		actor := p.ActorUserID()
+		e.ActorUserID = &actor
+	}
+	return s.repo.Append(ctx, e)
+}
+
+func (s *service) List(ctx context.Context, nodeID uuid.UUID, f audit.ListFilter) (*audit.Page, error) {
+	if f.Limit <= 0 {
+		f.Limit = defaultPageSize
+	}
+	f.Limit = min(f.Limit, maxPageSize)
+	return s.repo.ListPage(ctx, nodeID, f)
+}
+
+func marshalState(v any) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
@@ -1,6 +1,7 @@
 package di
 
 import (
+	"github.com/SURF-Innovatie/MORIS/internal/app/apitoken"
 	authapp "github.com/SURF-Innovatie/MORIS/internal/app/auth"
 	"github.com/SURF-Innovatie/MORIS/internal/infra/env"
 	"github.com/samber/do/v2"
@@ -12,7 +13,12 @@ var Package = do.Package(
 
 func ProvideService(i do.Injector) (authapp.Service, error) {
 	repo := do.MustInvoke[authapp.Repository](i)
-	return authapp.NewService(repo, authapp.Options{
-		JWTSecret: env.Global.JWTSecret,
+	revocations := do.MustInvoke[authapp.RevocationStore](i)
+	apiTokens := do.MustInvoke[apitoken.Service](i)
+	return authapp.NewService(repo, revocations, apiTokens, authapp.Options{
+		JWTSecret:        env.Global.JWTSecret,
+		AccessTTL:        env.Global.AccessTokenTTL,
+		RefreshTTL:       env.Global.RefreshTokenTTL,
+		ImpersonationTTL: env.Global.ImpersonationTTL,
 	}), nil
 }
@@ -2,7 +2,9 @@ package auth
 
 import (
 	"context"
+	"time"
 
+	"github.com/SURF-Innovatie/MORIS/internal/domain/apitoken"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/identity"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/identity/readmodels"
 	"github.com/google/uuid"
@@ -23,4 +25,42 @@ type Repository interface {
 
 	// GetAccountByID returns the full account for token validation.
 	GetAccountByID(ctx context.Context, userID uuid.UUID) (*readmodels.UserAccount, error)
+
+	// CreateSession stores a new session with the hash of its first refresh token.
+	CreateSession(ctx context.Context, s identity.Session, tokenHash string) (*identity.Session, error)
+
+	// SessionByTokenHash returns the session whose current refresh token has the hash, or nil.
+	SessionByTokenHash(ctx context.Context, tokenHash string) (*identity.Session, error)
+
+	// SessionByPreviousTokenHash returns the session whose replaced refresh token has the hash, or nil.
+	SessionByPreviousTokenHash(ctx context.Context, tokenHash string) (*identity.Session, error)
+
+	// RotateSession replaces the refresh token of a session, provided currentHash is still its
+	// current token. It reports false when another request rotated it first.
+	RotateSession(ctx context.Context, id uuid.UUID, currentHash, newHash string, usedAt, expiresAt time.Time) (bool, error)
+
+	// GetSession returns a session by ID, or nil.
+	GetSession(ctx context.Context, id uuid.UUID) (*identity.Session, error)
+
+	// ListSessions returns the sessions of a user that are active at the given time, most recently used first.
+	ListSessions(ctx context.Context, userID uuid.UUID, at time.Time) ([]identity.Session, error)
+
+	// ListImpersonations returns all impersonation sessions, newest first.
+	ListImpersonations(ctx context.Context) ([]identity.Session, error)
+
+	// RevokeSession marks a session as revoked, unless it already is.
+	RevokeSession(ctx context.Context, id uuid.UUID, at time.Time) error
+}
+
+// RevocationStore remembers revoked sessions for as long as their access tokens remain valid,
+// so authenticated requests are checked without a database lookup. Every revocation is recorded
+// here before the session is revoked in the database; the database is only consulted when the store fails.
+type RevocationStore interface {
+	Revoke(ctx context.Context, sessionID uuid.UUID, ttl time.Duration) error
+	IsRevoked(ctx context.Context, sessionID uuid.UUID) (bool, error)
+}
+
+// APITokenAuthenticator authenticates personal access tokens and service account tokens
+type APITokenAuthenticator interface {
+	Authenticate(ctx context.Context, secret string) (*apitoken.Grant, error)
 }
@@ -2,38 +2,112 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/SURF-Innovatie/MORIS/internal/domain/apitoken"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/identity"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/identity/readmodels"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
 	"golang.org/x/crypto/bcrypt"
 )
 
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrSessionNotFound     = errors.New("session not found")
+	ErrSessionRevoked      = errors.New("session has been revoked")
+	ErrCannotImpersonate   = errors.New("user cannot be impersonated")
+	ErrReasonRequired      = errors.New("a reason is required")
+)
+
 type Service interface {
-	Login(ctx context.Context, email, password string) (string, *readmodels.UserAccount, error)
-	LoginByEmail(ctx context.Context, email string) (string, *readmodels.UserAccount, error)
-	ValidateToken(tokenString string) (*readmodels.UserAccount, error)
+	Login(ctx context.Context, email, password string, client ClientInfo) (*Tokens, *readmodels.UserAccount, error)
+	LoginByEmail(ctx context.Context, email string, client ClientInfo) (*Tokens, *readmodels.UserAccount, error)
+	// Refresh exchanges a refresh token for new tokens. The refresh token is rotated; presenting
+	// a replaced token again revokes the session, as it has leaked.
+	Refresh(ctx context.Context, refreshToken string) (*Tokens, *readmodels.UserAccount, error)
+	ValidateToken(ctx context.Context, tokenString string) (*Authentication, error)
+
+	// Logout and RevokeSession fail, leaving the session as it is, when the revocation store cannot
+	// record the revocation
+	Logout(ctx context.Context, sessionID uuid.UUID) error
+	ListSessions(ctx context.Context, userID uuid.UUID) ([]identity.Session, error)
+	RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error
+
+	// Impersonate starts a session in which a sysadmin acts as another user. It ends after the
+	// impersonation time limit, also when refreshed, and its access tokens carry both identities.
+	Impersonate(ctx context.Context, impersonatorID, userID uuid.UUID, reason string, client ClientInfo) (*Tokens, *readmodels.UserAccount, error)
+	// ListImpersonations returns every impersonation session, ended ones included, newest first
+	ListImpersonations(ctx context.Context) ([]identity.Session, error)
+	EndImpersonation(ctx context.Context, sessionID uuid.UUID) error
+}
+
+// Tokens are issued at login and on refresh
+type Tokens struct {
+	AccessToken          string
+	AccessTokenExpiresAt time.Time
+	RefreshToken         string
+	SessionID            uuid.UUID
+}
+
+// ClientInfo describes the device a session is created from
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+// Authentication is the outcome of a valid access token or API token
+type Authentication struct {
+	Account *readmodels.UserAccount
+	// SessionID is the session of an access token, uuid.Nil for API tokens
+	SessionID uuid.UUID
+	// Token is the grant of an API token, nil for access tokens
+	Token *apitoken.Grant
+	// Impersonation is set when a sysadmin acts as the account
+	Impersonation *readmodels.Impersonation
 }
 
 type service struct {
-	repo      Repository
-	jwtSecret string
-	ttl       time.Duration
-	now       func() time.Time
+	repo        Repository
+	revocations RevocationStore
+	apiTokens   APITokenAuthenticator
+	jwtSecret   string
+	accessTTL   time.Duration
+	refreshTTL  time.Duration
+	// impersonationTTL is how long an impersonation session lasts
+	impersonationTTL time.Duration
+	now              func() time.Time
 }
 
 type Options struct {
 	JWTSecret string
-	TTL       time.Duration
-	Now       func() time.Time
+	// AccessTTL is the lifetime of access tokens, and how long the revocation store remembers a
+	// revoked session.
+	AccessTTL time.Duration
+	// RefreshTTL is how long a session lasts without being refreshed.
+	RefreshTTL time.Duration
+	// ImpersonationTTL is how long a sysadmin may act as another user before signing in as them again.
+	ImpersonationTTL time.Duration
+	Now              func() time.Time
 }
 
-func NewService(repo Repository, opts Options) Service {
-	ttl := opts.TTL
-	if ttl == 0 {
-		ttl = 7 * 24 * time.Hour
+func NewService(repo Repository, revocations RevocationStore, apiTokens APITokenAuthenticator, opts Options) Service {
+	accessTTL := opts.AccessTTL
+	if accessTTL == 0 {
+		accessTTL = 15 * time.Minute
+	}
+	refreshTTL := opts.RefreshTTL
+	if refreshTTL == 0 {
+		refreshTTL = 30 * 24 * time.Hour
+	}
+	impersonationTTL := opts.ImpersonationTTL
+	if impersonationTTL == 0 {
+		impersonationTTL = 30 * time.Minute
 	}
 	now := opts.Now
 	if now == nil {
@@ -41,69 +115,156 @@ func NewService(repo Repository, opts Options) Service {
 	}
 
 	return &service{
-		repo:      repo,
-		jwtSecret: opts.JWTSecret,
-		ttl:       ttl,
-		now:       now,
+		repo:        repo,
+		revocations: revocations,
+		apiTokens:   apiTokens,
+		jwtSecret:   opts.JWTSecret,
+		accessTTL:   accessTTL,
+		refreshTTL:  refreshTTL,
+
+		impersonationTTL: impersonationTTL,
+		now:              now,
 	}
 }
 
-func (s *service) Login(ctx context.Context, email, password string) (string, *readmodels.UserAccount, error) {
+func (s *service) Login(ctx context.Context, email, password string, client ClientInfo) (*Tokens, *readmodels.UserAccount, error) {
 	usr, err := s.repo.GetAccountByEmail(ctx, email)
 	if err != nil {
 		// Keep the exact error mapping consistent across repos.
-		return "", nil, fmt.Errorf("invalid credentials")
+		return nil, nil, fmt.Errorf("invalid credentials")
 	}
 
 	if !usr.User.IsActive {
-		return "", nil, fmt.Errorf("user account is inactive")
+		return nil, nil, fmt.Errorf("user account is inactive")
+	}
+	if usr.User.IsServiceAccount {
+		return nil, nil, fmt.Errorf("invalid credentials")
 	}
 
 	hash, err := s.repo.GetPasswordHash(ctx, usr.User.ID)
 	if err != nil {
-		return "", nil, fmt.Errorf("failed to query user: %w", err)
+		return nil, nil, fmt.Errorf("failed to query user: %w", err)
 	}
 	if hash == "" {
-		return "", nil, fmt.Errorf("invalid credentials")
+		return nil, nil, fmt.Errorf("invalid credentials")
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
-		return "", nil, fmt.Errorf("invalid credentials")
+		return nil, nil, fmt.Errorf("invalid credentials")
 	}
 
-	token, err := s.generateJWT(usr)
+	tokens, err := s.startSession(ctx, usr, client)
 	if err != nil {
-		return "", nil, fmt.Errorf("failed to generate token: %w", err)
+		return nil, nil, err
 	}
 
-	return token, usr, nil
+	return tokens, usr, nil
 }
 
-func (s *service) LoginByEmail(ctx context.Context, email string) (string, *readmodels.UserAccount, error) {
+func (s *service) LoginByEmail(ctx context.Context, email string, client ClientInfo) (*Tokens, *readmodels.UserAccount, error) {
 	usr, err := s.repo.GetAccountByEmail(ctx, email)
 	if err != nil {
-		return "", nil, fmt.Errorf("invalid credentials")
+		return nil, nil, fmt.Errorf("invalid credentials")
+	}
+
+	if !usr.User.IsActive {
+		return nil, nil, fmt.Errorf("user account is inactive")
+	}
+	// Service accounts authenticate with API tokens only
+	if usr.User.IsServiceAccount {
+		return nil, nil, fmt.Errorf("invalid credentials")
+	}
+
+	tokens, err := s.startSession(ctx, usr, client)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tokens, usr, nil
+}
+
+func (s *service) Refresh(ctx context.Context, refreshToken string) (*Tokens, *readmodels.UserAccount, error) {
+	if refreshToken == "" {
+		return nil, nil, ErrInvalidRefreshToken
 	}
+	hash := identity.HashRefreshToken(refreshToken)
 
+	sess, err := s.repo.SessionByTokenHash(ctx, hash)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query session: %w", err)
+	}
+	if sess == nil {
+		// A replaced token is only presented again by whoever copied it
+		reused, err := s.repo.SessionByPreviousTokenHash(ctx, hash)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to query session: %w", err)
+		}
+		if reused != nil {
+			if err := s.revoke(ctx, reused.ID); err != nil {
+				return nil, nil, err
+			}
+		}
+		return nil, nil, ErrInvalidRefreshToken
+	}
+
+	now := s.now()
+	if !sess.ActiveAt(now) {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+
+	usr, err := s.repo.GetAccountByID(ctx, sess.UserID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get user: %w", err)
+	}
 	if !usr.User.IsActive {
-		return "", nil, fmt.Errorf("user account is inactive")
+		if err := s.revoke(ctx, sess.ID); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, fmt.Errorf("user account is inactive")
+	}
+	expiresAt := now.Add(s.refreshTTL)
+	if sess.Impersonated() {
+		// Impersonation is time-limited, so refreshing does not extend it
+		if _, err := s.impersonator(ctx, *sess); err != nil {
+			if err := s.revoke(ctx, sess.ID); err != nil {
+				return nil, nil, err
+			}
+			return nil, nil, err
+		}
+		expiresAt = sess.ExpiresAt
 	}
 
-	token, err := s.generateJWT(usr)
+	next, err := identity.NewRefreshToken()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+	ok, err := s.repo.RotateSession(ctx, sess.ID, hash, identity.HashRefreshToken(next), now, expiresAt)
 	if err != nil {
-		return "", nil, fmt.Errorf("failed to generate token: %w", err)
+		return nil, nil, fmt.Errorf("failed to rotate session: %w", err)
+	}
+	if !ok {
+		return nil, nil, ErrInvalidRefreshToken
 	}
+	sess.ExpiresAt = expiresAt
 
-	return token, usr, nil
+	tokens, err := s.issue(usr, *sess, next)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tokens, usr, nil
 }
 
-func (s *service) ValidateToken(tokenString string) (*readmodels.UserAccount, error) {
+func (s *service) ValidateToken(ctx context.Context, tokenString string) (*Authentication, error) {
+	if apitoken.IsSecret(tokenString) && s.apiTokens != nil {
+		return s.validateAPIToken(ctx, tokenString)
+	}
+
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (any, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
 		return []byte(s.jwtSecret), nil
-	})
+	}, jwt.WithTimeFunc(s.now))
 	if err != nil {
 		return nil, err
 	}
@@ -116,36 +277,287 @@ func (s *service) ValidateToken(tokenString string) (*readmodels.UserAccount, er
 		return nil, fmt.Errorf("invalid token claims")
 	}
 
-	userIDStr, ok := claims["user_id"].(string)
-	if !ok {
-		return nil, fmt.Errorf("invalid user_id in token")
+	userID, err := uuidClaim(claims, "user_id")
+	if err != nil {
+		return nil, err
+	}
+	// Every access token is bound to a session; tokens without one are rejected
+	sessionID, err := uuidClaim(claims, "sid")
+	if err != nil {
+		return nil, err
+	}
+
+	authn := &Authentication{SessionID: sessionID}
+	if _, ok := claims["impersonator_id"]; ok {
+		if authn.Impersonation, err = s.validateImpersonation(ctx, claims, sessionID); err != nil {
+			return nil, err
+		}
+	} else {
+		revoked, err := s.isRevoked(ctx, sessionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check session: %w", err)
+		}
+		if revoked {
+			return nil, ErrSessionRevoked
+		}
+	}
+
+	if authn.Account, err = s.repo.GetAccountByID(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
-	userID, err := uuid.Parse(userIDStr)
+	return authn, nil
+}
+
+// validateImpersonation checks the session of an impersonation token in the database rather than
+// the revocation store, so that ending an impersonation takes effect immediately
+func (s *service) validateImpersonation(ctx context.Context, claims jwt.MapClaims, sessionID uuid.UUID) (*readmodels.Impersonation, error) {
+	impersonatorID, err := uuidClaim(claims, "impersonator_id")
+	if err != nil {
+		return nil, err
+	}
+	sess, err := s.repo.GetSession(ctx, sessionID)
 	if err != nil {
-		return nil, fmt.Errorf("invalid user_id in token")
+		return nil, fmt.Errorf("failed to check session: %w", err)
 	}
+	if sess == nil || !sess.ActiveAt(s.now()) || sess.ImpersonatorUserID == nil || *sess.ImpersonatorUserID != impersonatorID {
+		return nil, ErrSessionRevoked
+	}
+	impersonator, err := s.impersonator(ctx, *sess)
+	if err != nil {
+		return nil, err
+	}
+	return &readmodels.Impersonation{
+		SessionID:    sess.ID,
+		Impersonator: *impersonator,
+		Reason:       lo.FromPtr(sess.ImpersonationReason),
+		StartedAt:    sess.CreatedAt,
+		ExpiresAt:    sess.ExpiresAt,
+	}, nil
+}
 
-	usr, err := s.repo.GetAccountByID(context.Background(), userID)
+// impersonator returns the account impersonating in a session, provided it may still do so
+func (s *service) impersonator(ctx context.Context, sess identity.Session) (*readmodels.UserAccount, error) {
+	acc, err := s.repo.GetAccountByID(ctx, *sess.ImpersonatorUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get impersonator: %w", err)
+	}
+	if !acc.User.IsActive || !acc.User.IsSysAdmin {
+		return nil, ErrSessionRevoked
+	}
+	return acc, nil
+}
+
+func (s *service) validateAPIToken(ctx context.Context, secret string) (*Authentication, error) {
+	grant, err := s.apiTokens.Authenticate(ctx, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	usr, err := s.repo.GetAccountByID(ctx, grant.Token.UserID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
-	return usr, nil
+	return &Authentication{Account: usr, Token: grant}, nil
 }
 
-func (s *service) generateJWT(usr *readmodels.UserAccount) (string, error) {
+func (s *service) Logout(ctx context.Context, sessionID uuid.UUID) error {
+	return s.revoke(ctx, sessionID)
+}
+
+func (s *service) ListSessions(ctx context.Context, userID uuid.UUID) ([]identity.Session, error) {
+	return s.repo.ListSessions(ctx, userID, s.now())
+}
+
+func (s *service) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	sess, err := s.repo.GetSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if sess == nil || sess.UserID != userID {
+		return ErrSessionNotFound
+	}
+	return s.revoke(ctx, sessionID)
+}
+
+func (s *service) Impersonate(ctx context.Context, impersonatorID, userID uuid.UUID, reason string, client ClientInfo) (*Tokens, *readmodels.UserAccount, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, nil, ErrReasonRequired
+	}
+	if impersonatorID == userID {
+		return nil, nil, fmt.Errorf("%w: you cannot impersonate yourself", ErrCannotImpersonate)
+	}
+
+	impersonator, err := s.repo.GetAccountByID(ctx, impersonatorID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get impersonator: %w", err)
+	}
+	if !impersonator.User.IsActive || !impersonator.User.IsSysAdmin {
+		return nil, nil, fmt.Errorf("%w: only sysadmins may impersonate", ErrCannotImpersonate)
+	}
+
+	usr, err := s.repo.GetAccountByID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch {
+	case !usr.User.IsActive:
+		return nil, nil, fmt.Errorf("%w: the account is inactive", ErrCannotImpersonate)
+	case usr.User.IsSysAdmin:
+		return nil, nil, fmt.Errorf("%w: sysadmins cannot be impersonated", ErrCannotImpersonate)
+	case usr.User.IsServiceAccount:
+		return nil, nil, fmt.Errorf("%w: service accounts cannot be impersonated", ErrCannotImpersonate)
+	}
+
 	now := s.now()
+	tokens, err := s.openSession(ctx, usr, identity.Session{
+		UserID:              usr.User.ID,
+		UserAgent:           lo.EmptyableToPtr(client.UserAgent),
+		IPAddress:           lo.EmptyableToPtr(client.IPAddress),
+		CreatedAt:           now,
+		LastUsedAt:          now,
+		ExpiresAt:           now.Add(s.impersonationTTL),
+		ImpersonatorUserID:  &impersonatorID,
+		ImpersonationReason: &reason,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
 
+	log.Info().
+		Str("impersonator_user_id", impersonatorID.String()).
+		Str("user_id", userID.String()).
+		Str("session_id", tokens.SessionID.String()).
+		Msg("impersonation started")
+	return tokens, usr, nil
+}
+
+func (s *service) ListImpersonations(ctx context.Context) ([]identity.Session, error) {
+	return s.repo.ListImpersonations(ctx)
+}
+
+func (s *service) EndImpersonation(ctx context.Context, sessionID uuid.UUID) error {
+	sess, err := s.repo.GetSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if sess == nil || !sess.Impersonated() {
+		return ErrSessionNotFound
+	}
+	return s.endSession(ctx, sessionID)
+}
+
+func (s *service) startSession(ctx context.Context, usr *readmodels.UserAccount, client ClientInfo) (*Tokens, error) {
+	now := s.now()
+	return s.openSession(ctx, usr, identity.Session{
+		UserID:     usr.User.ID,
+		UserAgent:  lo.EmptyableToPtr(client.UserAgent),
+		IPAddress:  lo.EmptyableToPtr(client.IPAddress),
+		CreatedAt:  now,
+		LastUsedAt: now,
+		ExpiresAt:  now.Add(s.refreshTTL),
+	})
+}
+
+// openSession stores a session and issues its first tokens
+func (s *service) openSession(ctx context.Context, usr *readmodels.UserAccount, sess identity.Session) (*Tokens, error) {
+	refresh, err := identity.NewRefreshToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	created, err := s.repo.CreateSession(ctx, sess, identity.HashRefreshToken(refresh))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return s.issue(usr, *created, refresh)
+}
+
+// issue signs an access token for the session. It never outlives the session.
+func (s *service) issue(usr *readmodels.UserAccount, sess identity.Session, refresh string) (*Tokens, error) {
+	expiresAt := s.now().Add(s.accessTTL)
+	if sess.ExpiresAt.Before(expiresAt) {
+		expiresAt = sess.ExpiresAt
+	}
+	token, err := s.generateJWT(usr, sess, expiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+	return &Tokens{
+		AccessToken:          token,
+		AccessTokenExpiresAt: expiresAt,
+		RefreshToken:         refresh,
+		SessionID:            sess.ID,
+	}, nil
+}
+
+// revoke ends a session. The revocation is recorded in the revocation store first, which ValidateToken
+// trusts, so a session is only revoked once its access tokens are rejected until they expire as well.
+func (s *service) revoke(ctx context.Context, sessionID uuid.UUID) error {
+	if s.revocations != nil {
+		if err := s.revocations.Revoke(ctx, sessionID, s.accessTTL); err != nil {
+			return fmt.Errorf("failed to record session revocation: %w", err)
+		}
+	}
+	return s.endSession(ctx, sessionID)
+}
+
+// endSession revokes a session in the database only. That suffices for impersonation sessions, whose
+// access tokens are always checked against the database.
+func (s *service) endSession(ctx context.Context, sessionID uuid.UUID) error {
+	if err := s.repo.RevokeSession(ctx, sessionID, s.now()); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return nil
+}
+
+// isRevoked trusts the revocation store, which records every revocation before the session is revoked in
+// the database. The session itself is only checked when the store is unavailable or not configured.
+func (s *service) isRevoked(ctx context.Context, sessionID uuid.UUID) (bool, error) {
+	if s.revocations != nil {
+		revoked, err := s.revocations.IsRevoked(ctx, sessionID)
+		if err == nil {
+			return revoked, nil
+		}
+		log.Warn().Err(err).Msg("revocation store unavailable, checking session in database")
+	}
+
+	sess, err := s.repo.GetSession(ctx, sessionID)
+	if err != nil {
+		return false, err
+	}
+	return sess == nil || sess.RevokedAt != nil, nil
+}
+
+func (s *service) generateJWT(usr *readmodels.UserAccount, sess identity.Session, expiresAt time.Time) (string, error) {
 	claims := jwt.MapClaims{
 		"user_id":   usr.User.ID.String(),
+		"sid":       sess.ID.String(),
 		"email":     usr.Person.Email,
 		"orcid_id":  usr.Person.ORCiD,
 		"is_active": usr.User.IsActive,
-		"exp":       now.Add(s.ttl).Unix(),
-		"iat":       now.Unix(),
+		"exp":       expiresAt.Unix(),
+		"iat":       s.now().Unix(),
+	}
+	if sess.ImpersonatorUserID != nil {
+		claims["impersonator_id"] = sess.ImpersonatorUserID.String()
 	}
 
 	t := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return t.SignedString([]byte(s.jwtSecret))
 }
+
+func uuidClaim(claims jwt.MapClaims, name string) (uuid.UUID, error) {
+	str, ok := claims[name].(string)
+	if !ok {
+		return uuid.Nil, fmt.Errorf("invalid %s in token", name)
+	}
+	id, err := uuid.Parse(str)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid %s in token", name)
+	}
+	return id, nil
+}
@@ -1,7 +1,9 @@
 package di
 
 import (
+	"github.com/SURF-Innovatie/MORIS/internal/app/audit"
 	"github.com/SURF-Innovatie/MORIS/internal/app/customfield"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/persistence/enttx"
 	"github.com/samber/do/v2"
 )
 
@@ -11,5 +13,7 @@ var Package = do.Package(
 
 func provideCustomFieldService(i do.Injector) (customfield.Service, error) {
 	repo := do.MustInvoke[customfield.Repository](i)
-	return customfield.NewService(repo), nil
+	auditSvc := do.MustInvoke[audit.Service](i)
+	txManager := do.MustInvoke[*enttx.Manager](i)
+	return customfield.NewService(repo, auditSvc, txManager), nil
 }
@@ -9,6 +9,7 @@ import (
 
 type Repository interface {
 	Create(ctx context.Context, in CreateDefinitionInput) (*customfield.Definition, error)
+	Get(ctx context.Context, id uuid.UUID) (*customfield.Definition, error)
 	Delete(ctx context.Context, id uuid.UUID) error
 	ExistsInOrg(ctx context.Context, id uuid.UUID, orgID uuid.UUID) (bool, error)
 	ListAvailableForNode(ctx context.Context, orgID uuid.UUID, category *customfield.Category) ([]customfield.Definition, error)
@@ -5,6 +5,9 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/SURF-Innovatie/MORIS/internal/app/audit"
+	"github.com/SURF-Innovatie/MORIS/internal/app/tx"
+	auditdomain "github.com/SURF-Innovatie/MORIS/internal/domain/audit"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/customfield"
 	"github.com/google/uuid"
 )
@@ -32,11 +35,13 @@ type CreateDefinitionInput struct {
 }
 
 type service struct {
-	repo Repository
+	repo  Repository
+	audit audit.Service
+	tx    tx.Manager
 }
 
-func NewService(repo Repository) Service {
-	return &service{repo: repo}
+func NewService(repo Repository, auditSvc audit.Service, tx tx.Manager) Service {
+	return &service{repo: repo, audit: auditSvc, tx: tx}
 }
 
 func (s *service) Create(
@@ -48,16 +53,34 @@ func (s *service) Create(
 	description, validationRegex, exampleValue *string,
 	required bool,
 ) (*customfield.Definition, error) {
-	return s.repo.Create(ctx, CreateDefinitionInput{
-		OrgID:           orgID,
-		Name:            name,
-		Type:            fieldType,
-		Category:        category,
-		Description:     description,
-		ValidationRegex: validationRegex,
-		ExampleValue:    exampleValue,
-		Required:        required,
+	var def *customfield.Definition
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		def, err = s.repo.Create(ctx, CreateDefinitionInput{
+			OrgID:           orgID,
+			Name:            name,
+			Type:            fieldType,
+			Category:        category,
+			Description:     description,
+			ValidationRegex: validationRegex,
+			ExampleValue:    exampleValue,
+			Required:        required,
+		})
+		if err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, auditdomain.Change{
+			OrgNodeID:  orgID,
+			EntityType: auditdomain.EntityCustomFieldDefinition,
+			EntityID:   def.ID,
+			Action:     auditdomain.ActionCreate,
+			After:      def,
+		})
 	})
+	if err != nil {
+		return nil, err
+	}
+	return def, nil
 }
 
 func (s *service) Delete(ctx context.Context, id uuid.UUID, orgID uuid.UUID) error {
@@ -68,7 +91,22 @@ func (s *service) Delete(ctx context.Context, id uuid.UUID, orgID uuid.UUID) err
 	if !exists {
 		return fmt.Errorf("%w", ErrNotFoundInOrg)
 	}
-	return s.repo.Delete(ctx, id)
+	return s.tx.WithTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, auditdomain.Change{
+			OrgNodeID:  orgID,
+			EntityType: auditdomain.EntityCustomFieldDefinition,
+			EntityID:   id,
+			Action:     auditdomain.ActionDelete,
+			Before:     before,
+		})
+	})
 }
 
 func (s *service) ListAvailableForNode(ctx context.Context, orgID uuid.UUID, category *customfield.Category) ([]customfield.Definition, error) {
@@ -1,8 +1,10 @@
 package di
 
 import (
+	appauth "github.com/SURF-Innovatie/MORIS/internal/app/auth"
 	"github.com/SURF-Innovatie/MORIS/internal/app/event"
 	"github.com/SURF-Innovatie/MORIS/internal/app/notification"
+	organisationrbac "github.com/SURF-Innovatie/MORIS/internal/app/organisation/rbac"
 	eventrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/event"
 	"github.com/samber/do/v2"
 )
@@ -16,6 +18,8 @@ func provideEventService(i do.Injector) (event.Service, error) {
 	repo := do.MustInvoke[*eventrepo.EntRepo](i)
 
 	evtPub := do.MustInvoke[event.Publisher](i)
+	curUser := do.MustInvoke[appauth.CurrentUserProvider](i)
+	rbacSvc := do.MustInvoke[organisationrbac.Service](i)
 
-	return event.NewService(repo, notifSvc, evtPub), nil
+	return event.NewService(repo, notifSvc, evtPub, curUser, rbacSvc), nil
 }
@@ -2,9 +2,14 @@ package event
 
 import (
 	"context"
+	"fmt"
 
+	appauth "github.com/SURF-Innovatie/MORIS/internal/app/auth"
 	"github.com/SURF-Innovatie/MORIS/internal/app/notification"
+	organisation_rbac "github.com/SURF-Innovatie/MORIS/internal/app/organisation/rbac"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation/rbac"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/project/events"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/project/projection"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 )
@@ -27,16 +32,22 @@ type NotificationHandler interface {
 }
 
 type service struct {
-	repo      repository
-	notifier  notification.Service
-	publisher Publisher
+	repo        repository
+	notifier    notification.Service
+	publisher   Publisher
+	currentUser appauth.CurrentUserProvider
+	rbac        organisation_rbac.Service
 }
 
-func NewService(repo repository, notifier notification.Service, publisher Publisher) Service {
-	return &service{repo: repo, notifier: notifier, publisher: publisher}
+func NewService(repo repository, notifier notification.Service, publisher Publisher, currentUser appauth.CurrentUserProvider, rbacSvc organisation_rbac.Service) Service {
+	return &service{repo: repo, notifier: notifier, publisher: publisher, currentUser: currentUser, rbac: rbacSvc}
 }
 
 func (s *service) ApproveEvent(ctx context.Context, eventID uuid.UUID) error {
+	if err := s.authorizeDecision(ctx, eventID); err != nil {
+		return err
+	}
+
 	if err := s.repo.UpdateStatus(ctx, eventID, "approved"); err != nil {
 		return err
 	}
@@ -57,6 +68,10 @@ func (s *service) ApproveEvent(ctx context.Context, eventID uuid.UUID) error {
 }
 
 func (s *service) RejectEvent(ctx context.Context, eventID uuid.UUID) error {
+	if err := s.authorizeDecision(ctx, eventID); err != nil {
+		return err
+	}
+
 	if err := s.repo.UpdateStatus(ctx, eventID, "rejected"); err != nil {
 		return err
 	}
@@ -77,6 +92,42 @@ func (s *service) RejectEvent(ctx context.Context, eventID uuid.UUID) error {
 	return nil
 }
 
+// authorizeDecision checks that the current user holds the approve_events permission
+// on the organisation node owning the event's project, directly or through an ancestor
+func (s *service) authorizeDecision(ctx context.Context, eventID uuid.UUID) error {
+	u, err := s.currentUser.Current(ctx)
+	if err != nil {
+		return err
+	}
+	if u.IsSysAdmin {
+		return nil
+	}
+
+	evt, err := s.repo.LoadEvent(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	evts, _, err := s.repo.Load(ctx, evt.AggregateID())
+	if err != nil {
+		return err
+	}
+
+	// A project that is itself awaiting approval has no owning node in its projection yet
+	orgNodeID := projection.Reduce(evt.AggregateID(), evts).OwningOrgNodeID
+	if started, ok := evt.(*events.ProjectStarted); ok && orgNodeID == uuid.Nil {
+		orgNodeID = started.OwningOrgNodeID
+	}
+
+	ok, err := s.rbac.HasPermission(ctx, u.PersonID, orgNodeID, rbac.PermissionApproveEvents)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: %s permission required", rbac.ErrForbidden, rbac.PermissionApproveEvents)
+	}
+	return nil
+}
+
 func (s *service) GetEvent(ctx context.Context, eventID uuid.UUID) (events.Event, error) {
 	return s.repo.LoadEvent(ctx, eventID)
 }
@@ -96,6 +147,12 @@ func (s *service) Load(ctx context.Context, id uuid.UUID) ([]events.Event, int,
 }
 
 func (s *service) Append(ctx context.Context, id uuid.UUID, expectedVersion int, newEvents ...events.Event) error {
+	// Events appended while impersonating keep the impersonated user as creator and record who really acted
+	if p, err := s.currentUser.Current(ctx); err == nil && p.Impersonated() {
+		for _, e := range newEvents {
+			e.SetImpersonator(*p.ImpersonatorUserID)
+		}
+	}
 	return s.repo.Append(ctx, id, expectedVersion, newEvents...)
 }
 
@@ -4,15 +4,20 @@ import (
 	"github.com/SURF-Innovatie/MORIS/internal/app/eventpolicy"
 	"github.com/SURF-Innovatie/MORIS/internal/app/notification"
 	organisationhierarchy "github.com/SURF-Innovatie/MORIS/internal/app/organisation/hierarchy"
+	"github.com/SURF-Innovatie/MORIS/internal/app/webhook"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/project/events/hydrator"
 	eventpolicyadapter "github.com/SURF-Innovatie/MORIS/internal/infra/adapters/eventpolicy"
+	eventrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/event"
 	eventpolicyrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/eventpolicy"
+	projectrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/project"
 	"github.com/samber/do/v2"
 )
 
 var Package = do.Package(
 	do.Lazy(provideEventPolicyService),
 	do.Lazy(providePolicyEvaluator),
+	do.Lazy(providePolicySimulator),
+	do.Lazy(providePolicyScheduler),
 )
 
 func provideEventPolicyService(i do.Injector) (eventpolicy.Service, error) {
@@ -26,6 +31,24 @@ func providePolicyEvaluator(i do.Injector) (eventpolicy.Evaluator, error) {
 	orgHierarchySvc := do.MustInvoke[organisationhierarchy.Service](i)
 	recipient := do.MustInvoke[*eventpolicyadapter.RecipientAdapter](i)
 	notifSvc := do.MustInvoke[notification.Service](i)
+	webhookSvc := do.MustInvoke[webhook.Service](i)
 	h := do.MustInvoke[*hydrator.Hydrator](i)
-	return eventpolicy.NewEvaluator(repo, orgHierarchySvc, recipient, notifSvc, h), nil
+	events := do.MustInvoke[*eventrepo.EntRepo](i)
+	return eventpolicy.NewEvaluator(repo, orgHierarchySvc, recipient, notifSvc, webhookSvc, h, events), nil
+}
+
+func providePolicySimulator(i do.Injector) (eventpolicy.Simulator, error) {
+	evaluator := do.MustInvoke[eventpolicy.Evaluator](i)
+	events := do.MustInvoke[*eventrepo.EntRepo](i)
+	orgHierarchySvc := do.MustInvoke[organisationhierarchy.Service](i)
+	return eventpolicy.NewSimulator(evaluator, events, orgHierarchySvc), nil
+}
+
+func providePolicyScheduler(i do.Injector) (eventpolicy.Scheduler, error) {
+	evaluator := do.MustInvoke[eventpolicy.Evaluator](i)
+	repo := do.MustInvoke[*eventpolicyrepo.EntRepo](i)
+	projects := do.MustInvoke[*projectrepo.EntRepo](i)
+	events := do.MustInvoke[*eventrepo.EntRepo](i)
+	orgHierarchySvc := do.MustInvoke[organisationhierarchy.Service](i)
+	return eventpolicy.NewScheduler(evaluator, repo, projects, events, orgHierarchySvc), nil
 }
@@ -6,14 +6,20 @@ import (
 	"reflect"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/SURF-Innovatie/MORIS/internal/app/notification"
 	organisationhierarchy "github.com/SURF-Innovatie/MORIS/internal/app/organisation/hierarchy"
+	"github.com/SURF-Innovatie/MORIS/internal/app/webhook"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/locale"
 	notificationdomain "github.com/SURF-Innovatie/MORIS/internal/domain/notification"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/policy"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/policy/expression"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/project"
 	internalevents "github.com/SURF-Innovatie/MORIS/internal/domain/project/events"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/project/events/hydrator"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/project/projection"
+	webhookdomain "github.com/SURF-Innovatie/MORIS/internal/domain/webhook"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 	"github.com/samber/lo"
@@ -25,6 +31,11 @@ type Evaluator interface {
 	EvaluateAndExecute(ctx context.Context, event internalevents.Event, project *project.Project) error
 	// CheckApprovalRequired checks if any policy requires approval for the event
 	CheckApprovalRequired(ctx context.Context, event internalevents.Event, project *project.Project) (bool, error)
+	// Simulate evaluates a single policy against an event without executing its action
+	Simulate(ctx context.Context, eventPolicy policy.EventPolicy, event internalevents.Event, project *project.Project) (SimulationOutcome, error)
+	// RunScheduled evaluates a due scheduled policy against the current project state and executes its action.
+	// anchor is the event the schedule is anchored on, or nil for date anchors. It reports whether the conditions matched.
+	RunScheduled(ctx context.Context, eventPolicy policy.EventPolicy, project *project.Project, anchor internalevents.Event) (bool, error)
 }
 
 type evaluator struct {
@@ -32,7 +43,10 @@ type evaluator struct {
 	orgHierarchySvc   organisationhierarchy.Service
 	recipientResolver RecipientResolver
 	notificationSvc   notification.Service
+	webhookSvc        webhook.Service
 	hydrator          *hydrator.Hydrator
+	events            EventStore
+	expressions       *expression.Cache
 }
 
 // NewEvaluator creates a new policy evaluator
@@ -41,14 +55,19 @@ func NewEvaluator(
 	orgHierarchySvc organisationhierarchy.Service,
 	recipientResolver RecipientResolver,
 	notificationSvc notification.Service,
+	webhookSvc webhook.Service,
 	hydrator *hydrator.Hydrator,
+	events EventStore,
 ) Evaluator {
 	return &evaluator{
 		repo:              repo,
 		orgHierarchySvc:   orgHierarchySvc,
 		recipientResolver: recipientResolver,
 		notificationSvc:   notificationSvc,
+		webhookSvc:        webhookSvc,
 		hydrator:          hydrator,
+		events:            events,
+		expressions:       expression.NewCache(),
 	}
 }
 
@@ -67,6 +86,7 @@ func (e *evaluator) CheckApprovalRequired(ctx context.Context, event internaleve
 	log.Info().Msgf("CheckApprovalRequired: Found %d policies for event %s (Project: %s)", len(policies), event.Type(), project.Id)
 
 	// 2. Filter policies that match this event type and pass conditions
+	subj := &subject{event: event, project: project}
 	for _, p := range policies {
 		if !p.Enabled {
 			log.Info().Msgf("Policy %s disabled", p.Name)
@@ -77,7 +97,7 @@ func (e *evaluator) CheckApprovalRequired(ctx context.Context, event internaleve
 			continue
 		}
 
-		matches := e.evaluateConditions(p.Conditions, event, project)
+		matches := e.evaluateConditions(ctx, p, subj)
 		log.Info().Msgf("Policy %s (Action: %s) match result: %v", p.Name, p.ActionType, matches)
 
 		if p.ActionType == policy.ActionTypeRequestApproval && matches {
@@ -102,6 +122,7 @@ func (e *evaluator) EvaluateAndExecute(ctx context.Context, event internalevents
 	}
 
 	// 2. Filter policies that match this event type and pass conditions
+	subj := &subject{event: event, project: project}
 	matchingPolicies := lo.Filter(policies, func(p policy.EventPolicy, _ int) bool {
 		if !p.Enabled {
 			return false
@@ -109,7 +130,7 @@ func (e *evaluator) EvaluateAndExecute(ctx context.Context, event internalevents
 		if !p.MatchesEventType(event.Type()) {
 			return false
 		}
-		return e.evaluateConditions(p.Conditions, event, project)
+		return e.evaluateConditions(ctx, p, subj)
 	})
 
 	log.Info().Msgf("EvaluateAndExecute: Event %s matches %d policies", event.Type(), len(matchingPolicies))
@@ -121,8 +142,11 @@ func (e *evaluator) EvaluateAndExecute(ctx context.Context, event internalevents
 	notificationPolicies := lo.Filter(matchingPolicies, func(p policy.EventPolicy, _ int) bool {
 		return p.ActionType == policy.ActionTypeNotify
 	})
+	webhookPolicies := lo.Filter(matchingPolicies, func(p policy.EventPolicy, _ int) bool {
+		return p.ActionType == policy.ActionTypeWebhook
+	})
 
-	log.Info().Msgf("EvaluateAndExecute: Found %d approval polices and %d notification policies", len(approvalPolicies), len(notificationPolicies))
+	log.Info().Msgf("EvaluateAndExecute: Found %d approval polices, %d notification policies and %d webhook policies", len(approvalPolicies), len(notificationPolicies), len(webhookPolicies))
 
 	// 4. Determine execution strategy based on event status
 	status := event.GetStatus()
@@ -133,39 +157,133 @@ func (e *evaluator) EvaluateAndExecute(ctx context.Context, event internalevents
 		// 1. Execute approval policies first
 		approvalSent := false
 		for _, policy := range approvalPolicies {
-			if err := e.executeAction(ctx, policy, event, project); err != nil {
-				log.Error().Err(err).Msgf("policy action error for %s", policy.ID)
-			} else {
+			if e.executeAndRecord(ctx, policy, subj) {
 				approvalSent = true
 			}
 		}
 
+		// Webhooks wait until the event is approved
+		for _, policy := range webhookPolicies {
+			e.recordSkipped(ctx, policy, subj, "deferred until approval")
+		}
+
 		// 2. Skip notification policies if an approval was already sent (it will be sent on approval)
 		if approvalSent {
 			log.Info().Msgf("skipping notification policies - approval already sent for event %s", event.GetID())
+			for _, policy := range notificationPolicies {
+				e.recordSkipped(ctx, policy, subj, "deferred until approval")
+			}
 			return nil
 		}
 
 		// 3. Execute notification policies (no approval required by any policy)
 		for _, policy := range notificationPolicies {
-			if err := e.executeAction(ctx, policy, event, project); err != nil {
-				log.Error().Err(err).Msgf("policy action error for %s", policy.ID)
-			}
+			e.executeAndRecord(ctx, policy, subj)
 		}
 	} else if status == internalevents.StatusApproved {
 		// For approved events:
 		// Only execute notification policies. Approval policies were already handled.
 		// We send notifications now because they were likely skipped during the 'pending' phase.
 		for _, policy := range notificationPolicies {
-			if err := e.executeAction(ctx, policy, event, project); err != nil {
-				log.Error().Err(err).Msgf("policy action error for %s", policy.ID)
-			}
+			e.executeAndRecord(ctx, policy, subj)
+		}
+
+		// Webhooks are only delivered for events that have actually been applied to the project
+		for _, policy := range webhookPolicies {
+			e.executeAndRecord(ctx, policy, subj)
 		}
 	}
 
 	return nil
 }
 
+// Simulate evaluates a single policy against an event without executing its action.
+// The policy's Enabled flag is ignored so that drafts can be tried out before they are switched on.
+func (e *evaluator) Simulate(ctx context.Context, eventPolicy policy.EventPolicy, event internalevents.Event, project *project.Project) (SimulationOutcome, error) {
+	var out SimulationOutcome
+	if project == nil || !eventPolicy.MatchesEventType(event.Type()) {
+		return out, nil
+	}
+	if !e.evaluateConditions(ctx, eventPolicy, &subject{event: event, project: project}) {
+		return out, nil
+	}
+
+	out.Matched = true
+	out.RequiresApproval = eventPolicy.ActionType == policy.ActionTypeRequestApproval
+	if eventPolicy.ActionType == policy.ActionTypeWebhook {
+		// Webhooks have no recipients or message; the match itself is the outcome
+		return out, nil
+	}
+
+	userIDs, err := e.resolveAllRecipients(ctx, eventPolicy, event.AggregateID(), project.OwningOrgNodeID)
+	if err != nil {
+		return out, fmt.Errorf("resolving recipients: %w", err)
+	}
+	out.RecipientUserIDs = userIDs
+	out.Message, _ = e.buildMessage(ctx, eventPolicy, event, project, locale.Default)
+
+	return out, nil
+}
+
+// RunScheduled evaluates a due scheduled policy and executes its action
+func (e *evaluator) RunScheduled(ctx context.Context, eventPolicy policy.EventPolicy, project *project.Project, anchor internalevents.Event) (bool, error) {
+	if project == nil {
+		return false, nil
+	}
+	subj := &subject{event: anchor, project: project, scheduled: true}
+	if !e.evaluateConditions(ctx, eventPolicy, subj) {
+		return false, nil
+	}
+
+	var eventID uuid.UUID
+	if anchor != nil {
+		eventID = anchor.GetID()
+	}
+
+	execution := e.newExecution(ctx, eventPolicy, subj)
+	execution.Trigger = policy.TriggerTypeSchedule
+	execution.Outcome = policy.ExecutionExecuted
+
+	var err error
+	switch eventPolicy.ActionType {
+	case policy.ActionTypeNotify:
+		var userIDs []uuid.UUID
+		userIDs, err = e.resolveAllRecipients(ctx, eventPolicy, project.Id, project.OwningOrgNodeID)
+		if err != nil {
+			err = fmt.Errorf("resolving recipients: %w", err)
+			break
+		}
+		if len(userIDs) == 0 {
+			log.Info().Msgf("RunScheduled: No recipients found for eventPolicy %s", eventPolicy.Name)
+			execution.Outcome = policy.ExecutionSkipped
+			execution.Detail = lo.ToPtr("no recipients")
+			break
+		}
+		execution.RecipientUserIDs = userIDs
+		var message string
+		message, err = e.sendLocalized(ctx, userIDs, eventID, notificationdomain.NotificationInfo, func(lang locale.Language) (string, map[string]string) {
+			return e.buildScheduledMessage(ctx, eventPolicy, anchor, project, lang)
+		})
+		execution.Message = &message
+	case policy.ActionTypeWebhook:
+		if anchor == nil {
+			err = fmt.Errorf("webhook policy %s requires an event anchor", eventPolicy.ID)
+			break
+		}
+		err = e.enqueueWebhook(ctx, eventPolicy, anchor, project)
+	default:
+		err = fmt.Errorf("unsupported action type for scheduled policy: %s", eventPolicy.ActionType)
+	}
+
+	if err != nil {
+		execution.Outcome = policy.ExecutionFailed
+		execution.Detail = lo.ToPtr(err.Error())
+	}
+	e.recordExecution(ctx, execution)
+
+	return true, err
+}
+
 // getApplicablePolicies returns all policies that could apply to a project
 func (e *evaluator) getApplicablePolicies(ctx context.Context, projectID uuid.UUID, orgNodeID uuid.UUID) ([]policy.EventPolicy, error) {
 	// Get project-level policies
@@ -186,19 +304,141 @@ func (e *evaluator) getApplicablePolicies(ctx context.Context, projectID uuid.UU
 		return nil, err
 	}
 
-	return append(projectPolicies, orgPolicies...), nil
+	// Policies that shadow a locked policy cannot replace it, so they are ignored
+	all := policy.MarkShadowed(append(projectPolicies, orgPolicies...))
+	return lo.Reject(all, func(p policy.EventPolicy, _ int) bool {
+		if p.Shadowed {
+			log.Info().Msgf("Policy %s (%s) ignored: it shadows a locked policy", p.Name, p.ID)
+		}
+		return p.Shadowed
+	}), nil
+}
+
+// subject is what a policy's conditions are evaluated against. The expression
+// input is built on first use and shared by all policies evaluated for the event.
+type subject struct {
+	event     internalevents.Event
+	project   *project.Project
+	scheduled bool // evaluated by the scheduler against the current project state
+	input     *expression.Input
 }
 
 // evaluateConditions checks if all conditions pass (AND logic)
-func (e *evaluator) evaluateConditions(conditions []policy.Condition, event internalevents.Event, project *project.Project) bool {
-	for _, cond := range conditions {
-		if !e.checkCondition(cond, event, project) {
+func (e *evaluator) evaluateConditions(ctx context.Context, eventPolicy policy.EventPolicy, subj *subject) bool {
+	for _, cond := range eventPolicy.Conditions {
+		if passed, _ := e.evaluateCondition(ctx, eventPolicy, cond, subj); !passed {
 			return false
 		}
 	}
 	return true // All conditions passed (empty conditions = always true)
 }
 
+// evaluateCondition evaluates a field comparison or an expression condition
+// and returns whether it passed together with the value it was decided on
+func (e *evaluator) evaluateCondition(ctx context.Context, eventPolicy policy.EventPolicy, cond policy.Condition, subj *subject) (bool, string) {
+	if !cond.IsExpression() {
+		actual := e.extractValue(cond.Field, subj.event, subj.project)
+		return e.checkCondition(cond, subj.event, subj.project), fmt.Sprint(lo.Ternary(actual == nil, any(""), actual))
+	}
+
+	x, err := e.expressions.Get(eventPolicy.ID, eventPolicy.Version, cond.Expression)
+	if err != nil {
+		log.Error().Err(err).Msgf("compiling expression of policy %s", eventPolicy.ID)
+		return false, err.Error()
+	}
+	passed, err := x.Eval(ctx, e.expressionInput(ctx, subj))
+	if err != nil {
+		log.Info().Err(err).Msgf("evaluating expression of policy %s", eventPolicy.ID)
+		return false, err.Error()
+	}
+	return passed, fmt.Sprint(passed)
+}
+
+// conditionResults evaluates every condition and records its outcome for the execution log
+func (e *evaluator) conditionResults(ctx context.Context, eventPolicy policy.EventPolicy, subj *subject) []policy.ConditionResult {
+	return lo.Map(eventPolicy.Conditions, func(cond policy.Condition, _ int) policy.ConditionResult {
+		passed, actual := e.evaluateCondition(ctx, eventPolicy, cond, subj)
+		expected := cond.Value
+		if cond.IsExpression() {
+			expected = cond.Expression
+		}
+		return policy.ConditionResult{
+			Field:    cond.Field,
+			Operator: cond.Operator,
+			Expected: expected,
+			Actual:   actual,
+			Passed:   passed,
+		}
+	})
+}
+
+// expressionInput builds the variables of expression conditions: the event, the project
+// before and after the event, the actor and the project's custom fields
+func (e *evaluator) expressionInput(ctx context.Context, subj *subject) expression.Input {
+	if subj.input != nil {
+		return *subj.input
+	}
+
+	before, after := e.projectStates(ctx, subj)
+	in := expression.Input{
+		Before:       expression.ToMap(before),
+		After:        expression.ToMap(after),
+		CustomFields: after.CustomFields,
+	}
+
+	if subj.event != nil {
+		in.Event = expression.ToMap(subj.event)
+		in.Event["id"] = subj.event.GetID().String()
+		in.Event["type"] = subj.event.Type()
+		in.Event["status"] = string(subj.event.GetStatus())
+		in.Event["at"] = subj.event.OccurredAt()
+		in.Event["created_by"] = subj.event.CreatedByID().String()
+
+		in.Actor = map[string]any{"id": subj.event.CreatedByID().String()}
+		if e.hydrator != nil {
+			if creator := e.hydrator.HydrateOne(ctx, subj.event).Creator; creator != nil {
+				in.Actor["person_id"] = creator.ID.String()
+				in.Actor["name"] = creator.Name
+				in.Actor["email"] = creator.Email
+			}
+		}
+	}
+
+	subj.input = &in
+	return in
+}
+
+// projectStates returns the project just before and just after the event. The states are
+// rebuilt from the event stream so that they do not depend on whether the event has been
+// applied yet; without an event store (or for scheduled runs) both are the given project.
+func (e *evaluator) projectStates(ctx context.Context, subj *subject) (before, after *project.Project) {
+	if subj.scheduled || subj.event == nil || e.events == nil {
+		return subj.project, subj.project
+	}
+
+	history, _, err := e.events.Load(ctx, subj.event.AggregateID())
+	if err != nil {
+		log.Error().Err(err).Msgf("loading history of project %s for expression conditions", subj.event.AggregateID())
+		return subj.project, subj.project
+	}
+
+	prior := history
+	for i, evt := range history {
+		if evt.GetID() == subj.event.GetID() {
+			prior = history[:i]
+			break
+		}
+	}
+
+	before = projection.Reduce(subj.event.AggregateID(), prior)
+	after = projection.Reduce(subj.event.AggregateID(), prior)
+	// Applied regardless of status, so pending events can be judged on their effect
+	if a, ok := subj.event.(internalevents.Applier); ok {
+		a.Apply(after)
+	}
+	return before, after
+}
+
 // checkCondition evaluates a single condition
 func (e *evaluator) checkCondition(cond policy.Condition, event internalevents.Event, project *project.Project) bool {
 	value := e.extractValue(cond.Field, event, project)
@@ -216,6 +456,8 @@ func (e *evaluator) checkCondition(cond policy.Condition, event internalevents.E
 		return e.toFloat(value) > e.toFloat(cond.Value)
 	case policy.OperatorLessThan:
 		return e.toFloat(value) < e.toFloat(cond.Value)
+	case policy.OperatorBetween:
+		return e.isBetween(value, cond.Value)
 	case policy.OperatorIn:
 		return e.isIn(value, cond.Value)
 	case policy.OperatorNotIn:
@@ -312,6 +554,16 @@ func (e *evaluator) toFloat(v any) float64 {
 	}
 }
 
+// isBetween checks min <= value <= max for a [min, max] bounds value
+func (e *evaluator) isBetween(value any, bounds any) bool {
+	b, ok := bounds.([]any)
+	if !ok || len(b) != 2 {
+		return false
+	}
+	v := e.toFloat(value)
+	return v >= e.toFloat(b[0]) && v <= e.toFloat(b[1])
+}
+
 func (e *evaluator) isIn(value any, collection any) bool {
 	switch c := collection.(type) {
 	case []any:
@@ -323,34 +575,162 @@ func (e *evaluator) isIn(value any, collection any) bool {
 	}
 }
 
-// executeAction executes the policy's action (notify or request_approval)
-func (e *evaluator) executeAction(ctx context.Context, eventPolicy policy.EventPolicy, event internalevents.Event, project *project.Project) error {
+// executeAndRecord executes the policy's action and stores the outcome in the execution log.
+// It reports whether the action succeeded.
+func (e *evaluator) executeAndRecord(ctx context.Context, eventPolicy policy.EventPolicy, subj *subject) bool {
+	execution := e.newExecution(ctx, eventPolicy, subj)
+	execution.Outcome = policy.ExecutionExecuted
+	if eventPolicy.ActionType == policy.ActionTypeRequestApproval {
+		execution.Outcome = policy.ExecutionApprovalRequired
+	}
+
+	userIDs, message, err := e.executeAction(ctx, eventPolicy, subj.event, subj.project)
+	execution.RecipientUserIDs = userIDs
+	if message != "" {
+		execution.Message = &message
+	}
+
+	switch {
+	case err != nil:
+		log.Error().Err(err).Msgf("policy action error for %s", eventPolicy.ID)
+		execution.Outcome = policy.ExecutionFailed
+		execution.Detail = lo.ToPtr(err.Error())
+	case eventPolicy.ActionType != policy.ActionTypeWebhook && len(userIDs) == 0:
+		execution.Outcome = policy.ExecutionSkipped
+		execution.Detail = lo.ToPtr("no recipients")
+	}
+
+	e.recordExecution(ctx, execution)
+	return err == nil
+}
+
+// recordSkipped stores a matched policy whose action was not carried out
+func (e *evaluator) recordSkipped(ctx context.Context, eventPolicy policy.EventPolicy, subj *subject, reason string) {
+	execution := e.newExecution(ctx, eventPolicy, subj)
+	execution.Outcome = policy.ExecutionSkipped
+	execution.Detail = &reason
+	e.recordExecution(ctx, execution)
+}
+
+// newExecution prepares the execution log entry of a matched policy
+func (e *evaluator) newExecution(ctx context.Context, eventPolicy policy.EventPolicy, subj *subject) policy.Execution {
+	execution := policy.Execution{
+		PolicyID:         eventPolicy.ID,
+		PolicyVersion:    eventPolicy.Version,
+		PolicyName:       eventPolicy.Name,
+		Trigger:          policy.TriggerTypeEvent,
+		ProjectID:        subj.project.Id,
+		ActionType:       eventPolicy.ActionType,
+		ConditionResults: e.conditionResults(ctx, eventPolicy, subj),
+	}
+	if subj.event != nil {
+		execution.EventID = lo.ToPtr(subj.event.GetID())
+		execution.EventType = subj.event.Type()
+	}
+	return execution
+}
+
+// recordExecution stores an execution log entry. Failures are logged so that they never block the action itself.
+func (e *evaluator) recordExecution(ctx context.Context, execution policy.Execution) {
+	if err := e.repo.RecordExecution(ctx, execution); err != nil {
+		log.Error().Err(err).Msgf("recording execution of policy %s", execution.PolicyID)
+	}
+}
+
+// executeAction executes the policy's action (notify, request_approval or webhook)
+// and returns the recipients and message that were used
+func (e *evaluator) executeAction(ctx context.Context, eventPolicy policy.EventPolicy, event internalevents.Event, project *project.Project) ([]uuid.UUID, string, error) {
+	if eventPolicy.ActionType == policy.ActionTypeWebhook {
+		return nil, "", e.enqueueWebhook(ctx, eventPolicy, event, project)
+	}
+
 	log.Info().Msgf("executeAction: Resolving recipients for eventPolicy %s", eventPolicy.Name)
 
 	// Resolve all recipients
 	userIDs, err := e.resolveAllRecipients(ctx, eventPolicy, event.AggregateID(), project.OwningOrgNodeID)
 	if err != nil {
-		return fmt.Errorf("resolving recipients: %w", err)
+		return nil, "", fmt.Errorf("resolving recipients: %w", err)
 	}
 
 	if len(userIDs) == 0 {
 		log.Info().Msgf("executeAction: No recipients found for eventPolicy %s", eventPolicy.Name)
-		return nil // No recipients to notify
+		return nil, "", nil // No recipients to notify
 	}
 
 	log.Info().Msgf("executeAction: Resolved %d recipients for eventPolicy %s. Sending %s...", len(userIDs), eventPolicy.Name, eventPolicy.ActionType)
 
-	// Build message
-	message := e.buildMessage(ctx, eventPolicy, event, project)
-
+	var notificationType notificationdomain.NotificationType
 	switch eventPolicy.ActionType {
 	case policy.ActionTypeNotify:
-		return e.notificationSvc.Send(ctx, userIDs, event.GetID(), message, notificationdomain.NotificationInfo)
+		notificationType = notificationdomain.NotificationInfo
 	case policy.ActionTypeRequestApproval:
-		return e.notificationSvc.Send(ctx, userIDs, event.GetID(), message, notificationdomain.NotificationApprovalRequest)
+		notificationType = notificationdomain.NotificationApprovalRequest
 	default:
-		return fmt.Errorf("unknown action type: %s", eventPolicy.ActionType)
+		return nil, "", fmt.Errorf("unknown action type: %s", eventPolicy.ActionType)
+	}
+
+	message, err := e.sendLocalized(ctx, userIDs, event.GetID(), notificationType, func(lang locale.Language) (string, map[string]string) {
+		return e.buildMessage(ctx, eventPolicy, event, project, lang)
+	})
+	return userIDs, message, err
+}
+
+// sendLocalized sends each recipient the message built in their preferred language.
+// It returns the message in the default language, which is the one execution logs record.
+func (e *evaluator) sendLocalized(
+	ctx context.Context,
+	userIDs []uuid.UUID,
+	eventID uuid.UUID,
+	notificationType notificationdomain.NotificationType,
+	build func(lang locale.Language) (string, map[string]string),
+) (string, error) {
+	languages, err := e.recipientResolver.ResolveLanguages(ctx, userIDs)
+	if err != nil {
+		log.Error().Err(err).Msg("error resolving recipient languages, falling back to the default language")
 	}
+	byLanguage := lo.GroupBy(userIDs, func(id uuid.UUID) locale.Language {
+		return locale.OrDefault(string(languages[id]))
+	})
+
+	message, _ := build(locale.Default)
+	for _, lang := range locale.Supported {
+		recipients := byLanguage[lang]
+		if len(recipients) == 0 {
+			continue
+		}
+		localized, vars := build(lang)
+		if err := e.notificationSvc.SendWithVariables(ctx, recipients, eventID, localized, notificationType, vars); err != nil {
+			return message, err
+		}
+	}
+	return message, nil
+}
+
+// enqueueWebhook queues a signed delivery of the event, project snapshot and policy to the policy's endpoint
+func (e *evaluator) enqueueWebhook(ctx context.Context, eventPolicy policy.EventPolicy, event internalevents.Event, project *project.Project) error {
+	if e.webhookSvc == nil {
+		return fmt.Errorf("webhook delivery is not configured")
+	}
+	if eventPolicy.WebhookURL == nil || *eventPolicy.WebhookURL == "" {
+		return fmt.Errorf("policy %s has no webhook url", eventPolicy.ID)
+	}
+
+	policyID := eventPolicy.ID
+	eventID := event.GetID()
+	_, err := e.webhookSvc.Enqueue(ctx, webhook.EnqueueInput{
+		PolicyID:  &policyID,
+		EventID:   &eventID,
+		EventType: event.Type(),
+		URL:       *eventPolicy.WebhookURL,
+		Envelope: webhookdomain.Envelope{
+			Type:       webhookdomain.EnvelopeTypePolicyTriggered,
+			OccurredAt: event.OccurredAt(),
+			Event:      webhookdomain.NewEventSnapshot(event),
+			Project:    webhookdomain.NewProjectSnapshot(project),
+			Policy:     webhookdomain.NewPolicySnapshot(eventPolicy),
+		},
+	})
+	return err
 }
 
 // resolveAllRecipients combines all recipient sources into unique user IDs
@@ -408,18 +788,19 @@ func (e *evaluator) resolveAllRecipients(ctx context.Context, policy policy.Even
 	return lo.Keys(userIDSet), nil
 }
 
-// buildMessage creates the notification message
-func (e *evaluator) buildMessage(ctx context.Context, eventPolicy policy.EventPolicy, event internalevents.Event, proj *project.Project) string {
-	// Build template variables from event and project
-	vars := e.buildTemplateVariables(ctx, event, proj)
+// buildMessage creates the notification message in the given language and returns it with the template
+// variables it was rendered from. Custom policy templates are used as written, in any language.
+func (e *evaluator) buildMessage(ctx context.Context, eventPolicy policy.EventPolicy, event internalevents.Event, proj *project.Project, lang locale.Language) (string, map[string]string) {
+	// Build template data from event, project and related entities
+	data, vars := e.buildTemplateData(ctx, event, proj)
 
 	if eventPolicy.MessageTemplate != nil && *eventPolicy.MessageTemplate != "" {
-		// Use policy's custom message template with variable substitution
-		return internalevents.ResolveTemplate(*eventPolicy.MessageTemplate, vars)
+		// Use policy's custom message template
+		return internalevents.ResolveTemplate(*eventPolicy.MessageTemplate, data), vars
 	}
 
 	// Check if event implements Notifier for default templates
-	if n, ok := event.(internalevents.Notifier); ok {
+	if n, ok := internalevents.Localize(event, lang); ok {
 		var template string
 		if eventPolicy.ActionType == policy.ActionTypeRequestApproval {
 			template = n.ApprovalRequestTemplate()
@@ -427,25 +808,61 @@ func (e *evaluator) buildMessage(ctx context.Context, eventPolicy policy.EventPo
 			template = n.NotificationTemplate()
 		}
 		if template != "" {
-			return internalevents.ResolveTemplate(template, vars)
+			return internalevents.ResolveTemplate(template, data), vars
 		}
 	}
 
 	// Fallback default message based on action type
+	texts := fallbackMessages[lang]
+	eventName := internalevents.FriendlyNameIn(event.Type(), lang)
 	if eventPolicy.ActionType == policy.ActionTypeRequestApproval {
-		return fmt.Sprintf("Approval requested for event '%s' on project '%s'", event.FriendlyName(), proj.Title)
+		return fmt.Sprintf(texts.approval, eventName, proj.Title), vars
+	}
+	return fmt.Sprintf(texts.notify, eventName, proj.Title), vars
+}
+
+// buildScheduledMessage creates the notification message of a scheduled policy in the given language
+// and returns it with its template variables
+func (e *evaluator) buildScheduledMessage(ctx context.Context, eventPolicy policy.EventPolicy, anchor internalevents.Event, proj *project.Project, lang locale.Language) (string, map[string]string) {
+	data, vars := e.buildTemplateData(ctx, anchor, proj)
+	if eventPolicy.MessageTemplate != nil && *eventPolicy.MessageTemplate != "" {
+		return internalevents.ResolveTemplate(*eventPolicy.MessageTemplate, data), vars
 	}
-	return fmt.Sprintf("Event '%s' occurred on project '%s'", event.FriendlyName(), proj.Title)
+	return fmt.Sprintf(fallbackMessages[lang].reminder, eventPolicy.Name, proj.Title), vars
+}
+
+// fallbackMessages are used when neither the policy nor the event provides a template
+var fallbackMessages = map[locale.Language]struct{ approval, notify, reminder string }{
+	locale.English: {
+		approval: "Approval requested for event '%s' on project '%s'",
+		notify:   "Event '%s' occurred on project '%s'",
+		reminder: "Reminder '%s' for project '%s'",
+	},
+	locale.Dutch: {
+		approval: "Goedkeuring gevraagd voor event '%s' op project '%s'",
+		notify:   "Event '%s' heeft plaatsgevonden op project '%s'",
+		reminder: "Herinnering '%s' voor project '%s'",
+	},
 }
 
-// buildTemplateVariables constructs a map of variables for template substitution
-func (e *evaluator) buildTemplateVariables(ctx context.Context, event internalevents.Event, proj *project.Project) map[string]string {
+// buildTemplateData constructs the data templates are rendered with: the event, the project and the
+// hydrated related entities. It also returns the flat variables that are stored with the notification.
+func (e *evaluator) buildTemplateData(ctx context.Context, event internalevents.Event, proj *project.Project) (map[string]any, map[string]string) {
+	data := make(map[string]any)
 	vars := make(map[string]string)
 
 	// Add project variables
 	if proj != nil {
+		data["project"] = proj
 		vars["project.Title"] = proj.Title
 		vars["project.Description"] = proj.Description
+		vars["project.StartDate"] = proj.StartDate.Format(time.DateOnly)
+		vars["project.EndDate"] = proj.EndDate.Format(time.DateOnly)
+	}
+
+	// Scheduled policies may run without an anchor event
+	if event == nil {
+		return data, vars
 	}
 
 	// Add event variables from Notifier if available
@@ -455,11 +872,32 @@ func (e *evaluator) buildTemplateVariables(ctx context.Context, event internalev
 		}
 	}
 
-	// Add hydrated entity variables from DetailedEvent
+	// Add the event and its hydrated related entities
+	de := internalevents.DetailedEvent{Event: event}
 	if e.hydrator != nil {
-		de := e.hydrator.HydrateOne(ctx, event)
-		vars = internalevents.AddDetailedEventVariables(vars, de)
+		de = e.hydrator.HydrateOne(ctx, event)
+		de.Event = event
+	}
+	for k, v := range internalevents.TemplateData(de) {
+		data[k] = v
+	}
+	if de.Person != nil {
+		vars["person.Name"] = de.Person.Name
+		vars["person.Email"] = de.Person.Email
+	}
+	if de.Product != nil {
+		vars["product.Name"] = de.Product.Name
+	}
+	if de.ProjectRole != nil {
+		vars["project_role.Name"] = de.ProjectRole.Name
+	}
+	if de.OrgNode != nil {
+		vars["org_node.Name"] = de.OrgNode.Name
+	}
+	if de.Creator != nil {
+		vars["creator.Name"] = de.Creator.Name
+		vars["creator.Email"] = de.Creator.Email
 	}
 
-	return vars
+	return data, vars
 }
@@ -0,0 +1,188 @@
+package eventpolicy_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/internal/app/eventpolicy"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/locale"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/policy"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/project"
+	events2 "github.com/SURF-Innovatie/MORIS/internal/domain/project/events"
+	"github.com/google/uuid"
+)
+
+// executionRepo serves fixed project policies and keeps the recorded executions
+type executionRepo struct {
+	fakePolicyRepo
+	policies   []policy.EventPolicy
+	executions []policy.Execution
+}
+
+func (r *executionRepo) ListForProject(context.Context, uuid.UUID) ([]policy.EventPolicy, error) {
+	return r.policies, nil
+}
+
+func (r *executionRepo) RecordExecution(_ context.Context, x policy.Execution) error {
+	r.executions = append(r.executions, x)
+	return nil
+}
+
+func TestEvaluator_RecordsExecutions(t *testing.T) {
+	ctx := context.Background()
+	projectID := uuid.New()
+	actor := uuid.New()
+	member := uuid.New()
+	occurred := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	secret := policy.Condition{Field: "event.Title", Operator: policy.OperatorStartsWith, Value: "Secret"}
+	repo := &executionRepo{policies: []policy.EventPolicy{
+		{
+			ID:               uuid.New(),
+			Name:             "Secret titles need approval",
+			EventTypes:       []string{events2.TitleChangedType},
+			Conditions:       []policy.Condition{secret},
+			ActionType:       policy.ActionTypeRequestApproval,
+			RecipientDynamic: []string{"project_members"},
+			ProjectID:        &projectID,
+			Enabled:          true,
+			Version:          3,
+		},
+		{
+			ID:               uuid.New(),
+			Name:             "Tell the team",
+			EventTypes:       []string{events2.TitleChangedType},
+			ActionType:       policy.ActionTypeNotify,
+			RecipientDynamic: []string{"project_members"},
+			ProjectID:        &projectID,
+			Enabled:          true,
+			Version:          1,
+		},
+	}}
+	notifier := &recordingNotifier{}
+	evaluator := eventpolicy.NewEvaluator(repo, fakeHierarchy{}, fakeResolver{members: []uuid.UUID{member}}, notifier, nil, nil, nil)
+
+	proj := &project.Project{Id: projectID, Title: "Alpha"}
+	pending := at(&events2.TitleChanged{Base: events2.NewBase(projectID, actor, events2.StatusPending), Title: "Secret Alpha"}, occurred)
+
+	if err := evaluator.EvaluateAndExecute(ctx, pending, proj); err != nil {
+		t.Fatalf("evaluate pending: %v", err)
+	}
+	if len(repo.executions) != 2 {
+		t.Fatalf("expected 2 executions, got %d", len(repo.executions))
+	}
+
+	approval := repo.executions[0]
+	if approval.Outcome != policy.ExecutionApprovalRequired || approval.PolicyVersion != 3 {
+		t.Fatalf("unexpected approval execution: %+v", approval)
+	}
+	if approval.EventID == nil || *approval.EventID != pending.GetID() || approval.EventType != events2.TitleChangedType {
+		t.Fatalf("approval execution not linked to the event: %+v", approval)
+	}
+	if len(approval.ConditionResults) != 1 || !approval.ConditionResults[0].Passed || approval.ConditionResults[0].Actual != "Secret Alpha" {
+		t.Fatalf("unexpected condition results: %+v", approval.ConditionResults)
+	}
+	if len(approval.RecipientUserIDs) != 1 || approval.RecipientUserIDs[0] != member || approval.Message == nil {
+		t.Fatalf("expected recipients and message to be recorded: %+v", approval)
+	}
+
+	deferred := repo.executions[1]
+	if deferred.Outcome != policy.ExecutionSkipped || deferred.Detail == nil || notifier.sent != 1 {
+		t.Fatalf("expected the notification to be deferred: %+v (sent %d)", deferred, notifier.sent)
+	}
+
+	approved := at(&events2.TitleChanged{Base: events2.NewBase(projectID, actor, events2.StatusApproved), Title: "Secret Alpha"}, occurred)
+	if err := evaluator.EvaluateAndExecute(ctx, approved, proj); err != nil {
+		t.Fatalf("evaluate approved: %v", err)
+	}
+	if len(repo.executions) != 3 || repo.executions[2].Outcome != policy.ExecutionExecuted || notifier.sent != 2 {
+		t.Fatalf("expected the notification to be executed on approval: %+v", repo.executions)
+	}
+}
+
+func TestEvaluator_LocalizesMessages(t *testing.T) {
+	ctx := context.Background()
+	projectID := uuid.New()
+	actor := uuid.New()
+	english, dutch, unknown := uuid.New(), uuid.New(), uuid.New()
+
+	repo := &executionRepo{policies: []policy.EventPolicy{{
+		ID:               uuid.New(),
+		Name:             "Tell the team",
+		EventTypes:       []string{events2.TitleChangedType},
+		ActionType:       policy.ActionTypeNotify,
+		RecipientDynamic: []string{"project_members"},
+		ProjectID:        &projectID,
+		Enabled:          true,
+	}}}
+	resolver := fakeResolver{
+		members:   []uuid.UUID{english, dutch, unknown},
+		languages: map[uuid.UUID]locale.Language{english: locale.English, dutch: locale.Dutch},
+	}
+	notifier := &recordingNotifier{}
+	evaluator := eventpolicy.NewEvaluator(repo, fakeHierarchy{}, resolver, notifier, nil, nil, nil)
+
+	proj := &project.Project{Id: projectID, Title: "Alpha"}
+	changed := at(&events2.TitleChanged{Base: events2.NewBase(projectID, actor, events2.StatusApproved), Title: "Beta"}, time.Now())
+	if err := evaluator.EvaluateAndExecute(ctx, changed, proj); err != nil {
+		t.Fatalf("evaluate: %v", err)
+	}
+
+	if notifier.sent != 2 {
+		t.Fatalf("expected one send per language, got %d", notifier.sent)
+	}
+	if got := notifier.messages[dutch]; got != "Projecttitel gewijzigd in 'Beta'" {
+		t.Fatalf("unexpected Dutch message: %q", got)
+	}
+	for _, id := range []uuid.UUID{english, unknown} {
+		if got := notifier.messages[id]; got != "Project title changed to 'Beta'" {
+			t.Fatalf("unexpected English message: %q", got)
+		}
+	}
+	if len(repo.executions) != 1 || repo.executions[0].Message == nil || *repo.executions[0].Message != "Project title changed to 'Beta'" {
+		t.Fatalf("expected the execution to record the English message: %+v", repo.executions)
+	}
+}
+
+func TestEvaluator_ExpressionSeesBeforeAndAfter(t *testing.T) {
+	ctx := context.Background()
+	projectID := uuid.New()
+	actor := uuid.New()
+	member := uuid.New()
+	day := func(m time.Month, d int) time.Time { return time.Date(2025, m, d, 0, 0, 0, 0, time.UTC) }
+
+	started := at(&events2.ProjectStarted{
+		Base:    events2.NewBase(projectID, actor, events2.StatusApproved),
+		Title:   "Alpha",
+		EndDate: day(6, 30),
+	}, day(1, 1))
+	store := fakeEventStore{byProject: map[uuid.UUID][]events2.Event{projectID: {started}}}
+
+	repo := &executionRepo{policies: []policy.EventPolicy{{
+		ID:         uuid.New(),
+		Name:       "Long extensions need approval",
+		EventTypes: []string{events2.EndDateChangedType},
+		Conditions: []policy.Condition{{
+			Operator:   policy.OperatorExpression,
+			Expression: "project.after.end_date - project.before.end_date > duration('2160h')",
+		}},
+		ActionType:       policy.ActionTypeRequestApproval,
+		RecipientDynamic: []string{"project_members"},
+		ProjectID:        &projectID,
+		Enabled:          true,
+		Version:          1,
+	}}}
+	evaluator := eventpolicy.NewEvaluator(repo, fakeHierarchy{}, fakeResolver{members: []uuid.UUID{member}}, &recordingNotifier{}, nil, nil, store)
+	cur := &project.Project{Id: projectID, Title: "Alpha", EndDate: day(6, 30)}
+
+	short := at(&events2.EndDateChanged{Base: events2.NewBase(projectID, actor, events2.StatusApproved), EndDate: day(8, 31)}, day(2, 1))
+	if required, err := evaluator.CheckApprovalRequired(ctx, short, cur); err != nil || required {
+		t.Fatalf("a two month extension must not require approval: %v %v", required, err)
+	}
+
+	long := at(&events2.EndDateChanged{Base: events2.NewBase(projectID, actor, events2.StatusApproved), EndDate: day(12, 31)}, day(2, 1))
+	if required, err := evaluator.CheckApprovalRequired(ctx, long, cur); err != nil || !required {
+		t.Fatalf("a six month extension must require approval: %v %v", required, err)
+	}
+}
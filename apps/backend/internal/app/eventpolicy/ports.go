@@ -2,8 +2,11 @@ package eventpolicy
 
 import (
 	"context"
+	"time"
 
+	"github.com/SURF-Innovatie/MORIS/internal/domain/locale"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/policy"
+	internalevents "github.com/SURF-Innovatie/MORIS/internal/domain/project/events"
 	"github.com/google/uuid"
 )
 
@@ -20,6 +23,13 @@ type repository interface {
 
 	// ListForProject returns policies directly attached to a project.
 	ListForProject(ctx context.Context, projectID uuid.UUID) ([]policy.EventPolicy, error)
+
+	// RecordExecution stores the audit record of a matched policy
+	RecordExecution(ctx context.Context, execution policy.Execution) error
+	// ListExecutionsForEvent returns the policies that matched an event
+	ListExecutionsForEvent(ctx context.Context, eventID uuid.UUID) ([]policy.Execution, error)
+	// ListExecutionsForPolicy returns a page of a policy's execution history and the total count
+	ListExecutionsForPolicy(ctx context.Context, policyID uuid.UUID, limit, offset int) ([]policy.Execution, int, error)
 }
 
 // RecipientResolver resolves recipient specifications to actual user IDs
@@ -36,4 +46,43 @@ type RecipientResolver interface {
 	// ResolveDynamic returns user IDs for dynamic recipient types
 	// dynType can be: "project_members", "project_owner", "org_admins"
 	ResolveDynamic(ctx context.Context, dynType string, projectID uuid.UUID, orgNodeID uuid.UUID) ([]uuid.UUID, error)
+
+	// ResolveLanguages returns the preferred language of each user; users without one are left out
+	ResolveLanguages(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]locale.Language, error)
+}
+
+// EventStore provides read access to the project event streams for policy simulation
+// and for the before/after project states of expression conditions
+type EventStore interface {
+	// Load returns all events for a project and the current version
+	Load(ctx context.Context, projectID uuid.UUID) ([]internalevents.Event, int, error)
+
+	// LoadBetween returns a page of the events of the given types that occurred within [from, to], oldest first,
+	// optionally limited to a single project
+	LoadBetween(ctx context.Context, from, to time.Time, eventTypes []string, projectID *uuid.UUID, offset, limit int) ([]internalevents.Event, error)
+}
+
+// ScheduleStore provides the scheduled policies and de-duplicates their runs
+type ScheduleStore interface {
+	// ListScheduled returns all enabled scheduled policies
+	ListScheduled(ctx context.Context) ([]policy.EventPolicy, error)
+
+	// ClaimScheduledRun records a run of a policy for a project and due date; it returns false if it already ran
+	ClaimScheduledRun(ctx context.Context, policyID, projectID uuid.UUID, dueAt time.Time) (bool, error)
+
+	// ReleaseScheduledRun removes a claimed run so that it is retried
+	ReleaseScheduledRun(ctx context.Context, policyID, projectID uuid.UUID, dueAt time.Time) error
+}
+
+// ProjectLister finds the projects a scheduled policy may be due for. Each method returns a superset
+// that the scheduler checks against the current state of the projects.
+type ProjectLister interface {
+	// ProjectIDsEverOwnedUnder returns the projects that were started under or moved to the node or one of its descendants
+	ProjectIDsEverOwnedUnder(ctx context.Context, nodeID uuid.UUID) ([]uuid.UUID, error)
+	// ProjectIDsWithStartDateBetween returns the projects whose start date was at some point set to a day within [from, to]
+	ProjectIDsWithStartDateBetween(ctx context.Context, from, to time.Time) ([]uuid.UUID, error)
+	// ProjectIDsWithEndDateBetween returns the projects whose end date was at some point set to a day within [from, to]
+	ProjectIDsWithEndDateBetween(ctx context.Context, from, to time.Time) ([]uuid.UUID, error)
+	// ProjectIDsWithEventBetween returns the projects with an event of the type that occurred within [from, to]
+	ProjectIDsWithEventBetween(ctx context.Context, eventType string, from, to time.Time) ([]uuid.UUID, error)
 }
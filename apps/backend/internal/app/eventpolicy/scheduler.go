@@ -0,0 +1,244 @@
+package eventpolicy
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	organisationhierarchy "github.com/SURF-Innovatie/MORIS/internal/app/organisation/hierarchy"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/policy"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/project"
+	internalevents "github.com/SURF-Innovatie/MORIS/internal/domain/project/events"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/project/projection"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+)
+
+// scheduleCatchUp is how long after its due date a scheduled policy may still fire.
+// It bounds the reminders sent for old projects when a policy is created or the scheduler was down.
+const scheduleCatchUp = 7 * 24 * time.Hour
+
+// Scheduler evaluates date-driven policies over the current state of all projects
+type Scheduler interface {
+	// RunDue fires every scheduled policy that is due at now and returns the number of actions executed
+	RunDue(ctx context.Context, now time.Time) (int, error)
+
+	// Run calls RunDue every interval until ctx is cancelled
+	Run(ctx context.Context, interval time.Duration)
+}
+
+type scheduler struct {
+	evaluator       Evaluator
+	store           ScheduleStore
+	projects        ProjectLister
+	events          EventStore
+	orgHierarchySvc organisationhierarchy.Service
+}
+
+// NewScheduler creates a new scheduled policy runner
+func NewScheduler(
+	evaluator Evaluator,
+	store ScheduleStore,
+	projects ProjectLister,
+	events EventStore,
+	orgHierarchySvc organisationhierarchy.Service,
+) Scheduler {
+	return &scheduler{
+		evaluator:       evaluator,
+		store:           store,
+		projects:        projects,
+		events:          events,
+		orgHierarchySvc: orgHierarchySvc,
+	}
+}
+
+func (s *scheduler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := s.RunDue(ctx, time.Now()); err != nil {
+			log.Error().Err(err).Msg("scheduler: failed to run scheduled policies")
+		} else if n > 0 {
+			log.Info().Msgf("scheduler: executed %d scheduled policies", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *scheduler) RunDue(ctx context.Context, now time.Time) (int, error) {
+	policies, err := s.store.ListScheduled(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if len(policies) == 0 {
+		return 0, nil
+	}
+
+	// Only the projects a policy may be due for are replayed, each with the policies concerned
+	byProject := map[uuid.UUID][]policy.EventPolicy{}
+	for _, p := range policies {
+		ids, err := s.candidates(ctx, p, now)
+		if err != nil {
+			return 0, err
+		}
+		for _, id := range ids {
+			byProject[id] = append(byProject[id], p)
+		}
+	}
+
+	executed := 0
+	for projectID, due := range byProject {
+		n, err := s.runForProject(ctx, projectID, due, now)
+		if err != nil {
+			log.Error().Err(err).Msgf("scheduler: failed to run scheduled policies for project %s", projectID)
+			continue
+		}
+		executed += n
+	}
+
+	return executed, nil
+}
+
+// candidates returns the projects in the scope of a policy whose anchor lies in the window in which the
+// policy may fire at now
+func (s *scheduler) candidates(ctx context.Context, p policy.EventPolicy, now time.Time) ([]uuid.UUID, error) {
+	var scope []uuid.UUID
+	switch {
+	case p.ProjectID != nil:
+		scope = []uuid.UUID{*p.ProjectID}
+	case p.OrgNodeID != nil:
+		var err error
+		if scope, err = s.projects.ProjectIDsEverOwnedUnder(ctx, *p.OrgNodeID); err != nil {
+			return nil, err
+		}
+	}
+	if len(scope) == 0 {
+		return nil, nil
+	}
+
+	// DueAt truncates the anchor to its day and adds the offset; a day of margin covers the truncation
+	offset := lo.FromPtr(p.ScheduleOffsetDays)
+	from := now.Add(-scheduleCatchUp).AddDate(0, 0, -offset-1)
+	to := now.AddDate(0, 0, -offset+1)
+
+	var window []uuid.UUID
+	var err error
+	if eventType, ok := p.AnchorEventType(); ok {
+		window, err = s.projects.ProjectIDsWithEventBetween(ctx, eventType, from, to)
+	} else {
+		switch lo.FromPtr(p.ScheduleAnchor) {
+		case policy.ScheduleAnchorStartDate:
+			window, err = s.projects.ProjectIDsWithStartDateBetween(ctx, from, to)
+		case policy.ScheduleAnchorEndDate:
+			window, err = s.projects.ProjectIDsWithEndDateBetween(ctx, from, to)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return lo.Intersect(scope, window), nil
+}
+
+func (s *scheduler) runForProject(ctx context.Context, projectID uuid.UUID, policies []policy.EventPolicy, now time.Time) (int, error) {
+	history, _, err := s.events.Load(ctx, projectID)
+	if err != nil {
+		return 0, err
+	}
+	cur := projection.Reduce(projectID, history)
+	if cur == nil {
+		return 0, nil
+	}
+
+	orgIDs, err := s.orgHierarchySvc.AncestorIDsInclusive(ctx, cur.OwningOrgNodeID)
+	if err != nil {
+		return 0, err
+	}
+
+	executed := 0
+	for _, p := range policies {
+		if !appliesTo(p, cur, orgIDs) {
+			continue
+		}
+
+		anchorAt, anchorEvent, ok := resolveAnchor(p, cur, history)
+		if !ok {
+			continue
+		}
+
+		dueAt := p.DueAt(anchorAt)
+		if now.Before(dueAt) || now.Sub(dueAt) > scheduleCatchUp {
+			continue
+		}
+
+		fired, err := s.fire(ctx, p, cur, anchorEvent, dueAt)
+		if err != nil {
+			log.Error().Err(err).Msgf("scheduler: policy %s failed for project %s", p.ID, projectID)
+			continue
+		}
+		if fired {
+			executed++
+		}
+	}
+
+	return executed, nil
+}
+
+// fire claims the run first so that concurrent schedulers never send the same reminder twice.
+// The claim is released when the conditions do not match (yet) or the action fails.
+func (s *scheduler) fire(ctx context.Context, p policy.EventPolicy, cur *project.Project, anchor internalevents.Event, dueAt time.Time) (bool, error) {
+	claimed, err := s.store.ClaimScheduledRun(ctx, p.ID, cur.Id, dueAt)
+	if err != nil || !claimed {
+		return false, err
+	}
+
+	matched, err := s.evaluator.RunScheduled(ctx, p, cur, anchor)
+	if err != nil || !matched {
+		if relErr := s.store.ReleaseScheduledRun(ctx, p.ID, cur.Id, dueAt); relErr != nil {
+			log.Error().Err(relErr).Msgf("scheduler: failed to release run of policy %s for project %s", p.ID, cur.Id)
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// appliesTo reports whether a policy is scoped to the project or one of its owning org nodes
+func appliesTo(p policy.EventPolicy, cur *project.Project, orgIDs []uuid.UUID) bool {
+	if p.ProjectID != nil {
+		return *p.ProjectID == cur.Id
+	}
+	if p.OrgNodeID != nil {
+		return slices.Contains(orgIDs, *p.OrgNodeID)
+	}
+	return false
+}
+
+// resolveAnchor returns the anchor time of a scheduled policy for a project, and the anchor event if any
+func resolveAnchor(p policy.EventPolicy, cur *project.Project, history []internalevents.Event) (time.Time, internalevents.Event, bool) {
+	if eventType, ok := p.AnchorEventType(); ok {
+		// Most recent applied occurrence of the event type
+		for i := len(history) - 1; i >= 0; i-- {
+			e := history[i]
+			status := e.GetStatus()
+			if e.Type() == eventType && status != internalevents.StatusPending && status != internalevents.StatusRejected {
+				return e.OccurredAt(), e, true
+			}
+		}
+		return time.Time{}, nil, false
+	}
+
+	var at time.Time
+	switch *p.ScheduleAnchor {
+	case policy.ScheduleAnchorStartDate:
+		at = cur.StartDate
+	case policy.ScheduleAnchorEndDate:
+		at = cur.EndDate
+	}
+	return at, nil, !at.IsZero()
+}
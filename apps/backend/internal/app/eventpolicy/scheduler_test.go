@@ -0,0 +1,187 @@
+package eventpolicy_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/internal/app/eventpolicy"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/policy"
+	events2 "github.com/SURF-Innovatie/MORIS/internal/domain/project/events"
+	"github.com/google/uuid"
+)
+
+type runKey struct {
+	policyID, projectID uuid.UUID
+	dueAt               time.Time
+}
+
+type fakeScheduleStore struct {
+	policies []policy.EventPolicy
+	runs     map[runKey]bool
+}
+
+func (s *fakeScheduleStore) ListScheduled(context.Context) ([]policy.EventPolicy, error) {
+	return s.policies, nil
+}
+
+func (s *fakeScheduleStore) ClaimScheduledRun(_ context.Context, policyID, projectID uuid.UUID, dueAt time.Time) (bool, error) {
+	k := runKey{policyID, projectID, dueAt}
+	if s.runs[k] {
+		return false, nil
+	}
+	s.runs[k] = true
+	return true, nil
+}
+
+func (s *fakeScheduleStore) ReleaseScheduledRun(_ context.Context, policyID, projectID uuid.UUID, dueAt time.Time) error {
+	delete(s.runs, runKey{policyID, projectID, dueAt})
+	return nil
+}
+
+// fakeProjectLister answers the candidate queries from the ProjectStarted events of a store
+type fakeProjectLister struct{ store fakeEventStore }
+
+func (l fakeProjectLister) started(match func(*events2.ProjectStarted) bool) []uuid.UUID {
+	var out []uuid.UUID
+	for id, evts := range l.store.byProject {
+		for _, e := range evts {
+			if ps, ok := e.(*events2.ProjectStarted); ok && match(ps) {
+				out = append(out, id)
+			}
+		}
+	}
+	return out
+}
+
+func (l fakeProjectLister) ProjectIDsEverOwnedUnder(_ context.Context, nodeID uuid.UUID) ([]uuid.UUID, error) {
+	return l.started(func(e *events2.ProjectStarted) bool { return e.OwningOrgNodeID == nodeID }), nil
+}
+
+func (l fakeProjectLister) ProjectIDsWithStartDateBetween(_ context.Context, from, to time.Time) ([]uuid.UUID, error) {
+	return l.started(func(e *events2.ProjectStarted) bool { return !e.StartDate.Before(from) && !e.StartDate.After(to) }), nil
+}
+
+func (l fakeProjectLister) ProjectIDsWithEndDateBetween(_ context.Context, from, to time.Time) ([]uuid.UUID, error) {
+	return l.started(func(e *events2.ProjectStarted) bool { return !e.EndDate.Before(from) && !e.EndDate.After(to) }), nil
+}
+
+func (l fakeProjectLister) ProjectIDsWithEventBetween(_ context.Context, eventType string, from, to time.Time) ([]uuid.UUID, error) {
+	var out []uuid.UUID
+	for id, evts := range l.store.byProject {
+		for _, e := range evts {
+			if e.Type() == eventType && !e.OccurredAt().Before(from) && !e.OccurredAt().After(to) {
+				out = append(out, id)
+			}
+		}
+	}
+	return out, nil
+}
+
+// loadCounter records which projects the scheduler replays
+type loadCounter struct {
+	fakeEventStore
+	loaded map[uuid.UUID]int
+}
+
+func (c loadCounter) Load(ctx context.Context, id uuid.UUID) ([]events2.Event, int, error) {
+	c.loaded[id]++
+	return c.fakeEventStore.Load(ctx, id)
+}
+
+func TestScheduler_FiresOncePerDueDate(t *testing.T) {
+	ctx := context.Background()
+	projectID := uuid.New()
+	orgID := uuid.New()
+	actor := uuid.New()
+	member := uuid.New()
+
+	startedAt := time.Date(2025, 1, 10, 9, 30, 0, 0, time.UTC)
+	started := at(&events2.ProjectStarted{
+		Base:            events2.NewBase(projectID, actor, events2.StatusApproved),
+		Title:           "Alpha",
+		StartDate:       time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:         time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+		OwningOrgNodeID: orgID,
+	}, startedAt)
+
+	// A project of the same organisation that ends long after the others is never replayed
+	laterID := uuid.New()
+	later := at(&events2.ProjectStarted{
+		Base:            events2.NewBase(laterID, actor, events2.StatusApproved),
+		Title:           "Beta",
+		StartDate:       time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:         time.Date(2030, 12, 31, 0, 0, 0, 0, time.UTC),
+		OwningOrgNodeID: orgID,
+	}, time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC))
+
+	store := fakeEventStore{byProject: map[uuid.UUID][]events2.Event{projectID: {started}, laterID: {later}}}
+	counter := loadCounter{fakeEventStore: store, loaded: map[uuid.UUID]int{}}
+	hierarchy := fakeHierarchy{}
+	notifier := &recordingNotifier{}
+	evaluator := eventpolicy.NewEvaluator(fakePolicyRepo{}, hierarchy, fakeResolver{members: []uuid.UUID{member}}, notifier, nil, nil, nil)
+
+	endAnchor, startedAnchor := policy.ScheduleAnchorEndDate, policy.ScheduleAnchorEventPrefix+events2.ProjectStartedType
+	before60, after14 := -60, 14
+	schedules := &fakeScheduleStore{
+		runs: map[runKey]bool{},
+		policies: []policy.EventPolicy{
+			{
+				ID:                 uuid.New(),
+				Name:               "End date approaching",
+				TriggerType:        policy.TriggerTypeSchedule,
+				ScheduleAnchor:     &endAnchor,
+				ScheduleOffsetDays: &before60,
+				ActionType:         policy.ActionTypeNotify,
+				RecipientDynamic:   []string{"project_members"},
+				OrgNodeID:          &orgID,
+				Enabled:            true,
+			},
+			{
+				ID:                 uuid.New(),
+				Name:               "Missing description",
+				TriggerType:        policy.TriggerTypeSchedule,
+				ScheduleAnchor:     &startedAnchor,
+				ScheduleOffsetDays: &after14,
+				Conditions:         []policy.Condition{{Field: "project.Description", Operator: policy.OperatorNotExists}},
+				ActionType:         policy.ActionTypeNotify,
+				RecipientDynamic:   []string{"project_owner"},
+				ProjectID:          &projectID,
+				Enabled:            true,
+			},
+		},
+	}
+
+	sched := eventpolicy.NewScheduler(evaluator, schedules, fakeProjectLister{store}, counter, hierarchy)
+
+	// Neither policy is due yet
+	if n, err := sched.RunDue(ctx, time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC)); err != nil || n != 0 {
+		t.Fatalf("expected nothing due, got n=%d err=%v", n, err)
+	}
+
+	// 14 days after the project started the description reminder fires, once
+	day24 := time.Date(2025, 1, 24, 8, 0, 0, 0, time.UTC)
+	if n, _ := sched.RunDue(ctx, day24); n != 1 {
+		t.Fatalf("expected description reminder to fire, got %d", n)
+	}
+	if n, _ := sched.RunDue(ctx, day24.Add(time.Hour)); n != 0 {
+		t.Fatalf("expected reminder not to fire twice, got %d", n)
+	}
+
+	// 60 days before the end date the end date reminder fires
+	if n, _ := sched.RunDue(ctx, time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)); n != 1 {
+		t.Fatalf("expected end date reminder to fire, got %d", n)
+	}
+
+	// Past the catch-up window nothing fires anymore
+	if n, _ := sched.RunDue(ctx, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)); n != 0 {
+		t.Fatalf("expected nothing after the catch-up window, got %d", n)
+	}
+
+	if notifier.sent != 2 {
+		t.Fatalf("expected 2 notifications, got %d", notifier.sent)
+	}
+	if counter.loaded[laterID] != 0 {
+		t.Fatalf("expected the project outside every window not to be replayed, got %d loads", counter.loaded[laterID])
+	}
+}
@@ -2,9 +2,11 @@ package eventpolicy
 
 import (
 	"context"
+	"fmt"
 
 	organisationhierarchy "github.com/SURF-Innovatie/MORIS/internal/app/organisation/hierarchy"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/policy"
+	internalevents "github.com/SURF-Innovatie/MORIS/internal/domain/project/events"
 	"github.com/google/uuid"
 )
 
@@ -14,9 +16,15 @@ type Service interface {
 	Update(ctx context.Context, id uuid.UUID, policy policy.EventPolicy) (*policy.EventPolicy, error)
 	Delete(ctx context.Context, id uuid.UUID) error
 	GetByID(ctx context.Context, id uuid.UUID) (*policy.EventPolicy, error)
+	// CheckProjectPolicy ensures that a project policy does not shadow a locked policy of the
+	// project's owning organisation or one of its ancestors
+	CheckProjectPolicy(ctx context.Context, eventPolicy policy.EventPolicy, owningOrgNodeID uuid.UUID) error
 
 	ListForOrgNode(ctx context.Context, orgNodeID uuid.UUID, includeInherited bool) ([]policy.EventPolicy, error)
 	ListForProject(ctx context.Context, projectID uuid.UUID, owningOrgNodeID uuid.UUID, includeInherited bool) ([]policy.EventPolicy, error)
+
+	ListExecutionsForEvent(ctx context.Context, eventID uuid.UUID) ([]policy.Execution, error)
+	ListExecutionsForPolicy(ctx context.Context, policyID uuid.UUID, limit, offset int) ([]policy.Execution, int, error)
 }
 
 type service struct {
@@ -32,12 +40,92 @@ func NewService(repo repository, orgRbacSvc organisationhierarchy.Service) Servi
 	}
 }
 
-func (s *service) Create(ctx context.Context, policy policy.EventPolicy) (*policy.EventPolicy, error) {
-	return s.repo.Create(ctx, policy)
+func (s *service) Create(ctx context.Context, eventPolicy policy.EventPolicy) (*policy.EventPolicy, error) {
+	if err := validate(eventPolicy); err != nil {
+		return nil, err
+	}
+	if eventPolicy.ActionType == policy.ActionTypeWebhook && eventPolicy.WebhookSecret == nil {
+		return nil, fmt.Errorf("%w: webhook_secret is required for webhook policies", policy.ErrInvalidPolicy)
+	}
+	if err := s.checkLocks(ctx, eventPolicy); err != nil {
+		return nil, err
+	}
+	return s.repo.Create(ctx, eventPolicy)
+}
+
+func (s *service) Update(ctx context.Context, id uuid.UUID, eventPolicy policy.EventPolicy) (*policy.EventPolicy, error) {
+	if err := validate(eventPolicy); err != nil {
+		return nil, err
+	}
+	existing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if eventPolicy.ActionType == policy.ActionTypeWebhook && eventPolicy.WebhookSecret == nil && existing.WebhookSecret == nil {
+		return nil, fmt.Errorf("%w: webhook_secret is required for webhook policies", policy.ErrInvalidPolicy)
+	}
+
+	// The scope of a policy cannot change, so the checks use the stored one
+	eventPolicy.ID = id
+	eventPolicy.OrgNodeID = existing.OrgNodeID
+	eventPolicy.ProjectID = existing.ProjectID
+	if err := s.checkLocks(ctx, eventPolicy); err != nil {
+		return nil, err
+	}
+	return s.repo.Update(ctx, id, eventPolicy)
 }
 
-func (s *service) Update(ctx context.Context, id uuid.UUID, policy policy.EventPolicy) (*policy.EventPolicy, error) {
-	return s.repo.Update(ctx, id, policy)
+// checkLocks ensures that only org node policies are locked and that a policy
+// does not shadow a locked policy of one of its ancestors
+func (s *service) checkLocks(ctx context.Context, eventPolicy policy.EventPolicy) error {
+	if eventPolicy.OrgNodeID == nil {
+		if eventPolicy.Locked {
+			return fmt.Errorf("%w: only organisation policies can be locked", policy.ErrInvalidPolicy)
+		}
+		// Project policies are checked when their event is decided, see CheckProjectPolicy
+		return nil
+	}
+
+	ancestorIDs, err := s.orgHierarchySvc.AncestorIDs(ctx, *eventPolicy.OrgNodeID)
+	if err != nil {
+		return err
+	}
+	return s.checkShadowing(ctx, eventPolicy, ancestorIDs)
+}
+
+func (s *service) CheckProjectPolicy(ctx context.Context, eventPolicy policy.EventPolicy, owningOrgNodeID uuid.UUID) error {
+	if eventPolicy.Locked {
+		return fmt.Errorf("%w: only organisation policies can be locked", policy.ErrInvalidPolicy)
+	}
+
+	ancestorIDs, err := s.orgHierarchySvc.AncestorIDs(ctx, owningOrgNodeID)
+	if err != nil {
+		return err
+	}
+	return s.checkShadowing(ctx, eventPolicy, append([]uuid.UUID{owningOrgNodeID}, ancestorIDs...))
+}
+
+// checkShadowing rejects a policy that shadows a locked policy of one of the given org nodes
+func (s *service) checkShadowing(ctx context.Context, eventPolicy policy.EventPolicy, nodeIDs []uuid.UUID) error {
+	if len(nodeIDs) == 0 {
+		return nil
+	}
+
+	inherited, err := s.repo.ListForOrgNode(ctx, nodeIDs[0], nodeIDs[1:])
+	if err != nil {
+		return err
+	}
+	for _, p := range inherited {
+		if eventPolicy.Shadows(p) {
+			return fmt.Errorf("%w: %q is locked by an ancestor organisation", policy.ErrPolicyLocked, p.Name)
+		}
+	}
+	return nil
+}
+
+// validate checks the policy definition, including its event types and condition field paths
+func validate(eventPolicy policy.EventPolicy) error {
+	return internalevents.ValidatePolicy(eventPolicy)
 }
 
 func (s *service) Delete(ctx context.Context, id uuid.UUID) error {
@@ -73,7 +161,7 @@ func (s *service) ListForOrgNode(ctx context.Context, orgNodeID uuid.UUID, inclu
 		}
 	}
 
-	return policies, nil
+	return policy.MarkShadowed(policies), nil
 }
 
 // ListForProject lists policies for a project, optionally including inherited org policies
@@ -109,5 +197,15 @@ func (s *service) ListForProject(ctx context.Context, projectID uuid.UUID, ownin
 	}
 
 	// Combine: project policies first, then org policies
-	return append(projectPolicies, orgPolicies...), nil
+	return policy.MarkShadowed(append(projectPolicies, orgPolicies...)), nil
+}
+
+// ListExecutionsForEvent lists the policies that matched an event and what they did
+func (s *service) ListExecutionsForEvent(ctx context.Context, eventID uuid.UUID) ([]policy.Execution, error) {
+	return s.repo.ListExecutionsForEvent(ctx, eventID)
+}
+
+// ListExecutionsForPolicy lists a page of a policy's execution history, newest first
+func (s *service) ListExecutionsForPolicy(ctx context.Context, policyID uuid.UUID, limit, offset int) ([]policy.Execution, int, error) {
+	return s.repo.ListExecutionsForPolicy(ctx, policyID, limit, offset)
 }
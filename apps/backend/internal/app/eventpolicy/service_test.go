@@ -0,0 +1,160 @@
+package eventpolicy_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/internal/app/eventpolicy"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/policy"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/project"
+	events2 "github.com/SURF-Innovatie/MORIS/internal/domain/project/events"
+	"github.com/google/uuid"
+)
+
+// lockRepo stores org node and project policies in memory
+type lockRepo struct {
+	executionRepo
+	created []policy.EventPolicy
+}
+
+func (r *lockRepo) ListForOrgNode(_ context.Context, orgNodeID uuid.UUID, ancestorIDs []uuid.UUID) ([]policy.EventPolicy, error) {
+	ids := append([]uuid.UUID{orgNodeID}, ancestorIDs...)
+	var out []policy.EventPolicy
+	for _, p := range r.policies {
+		for _, id := range ids {
+			if p.OrgNodeID != nil && *p.OrgNodeID == id {
+				out = append(out, p)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (r *lockRepo) ListForProject(_ context.Context, projectID uuid.UUID) ([]policy.EventPolicy, error) {
+	var out []policy.EventPolicy
+	for _, p := range r.policies {
+		if p.ProjectID != nil && *p.ProjectID == projectID {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+func (r *lockRepo) Create(_ context.Context, p policy.EventPolicy) (*policy.EventPolicy, error) {
+	r.created = append(r.created, p)
+	return &p, nil
+}
+
+// parentHierarchy models a faculty with a single department
+type parentHierarchy struct {
+	fakeHierarchy
+	faculty, department uuid.UUID
+}
+
+func (h parentHierarchy) AncestorIDs(_ context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	if id == h.department {
+		return []uuid.UUID{h.faculty}, nil
+	}
+	return nil, nil
+}
+
+func TestService_LockedPolicies(t *testing.T) {
+	ctx := context.Background()
+	faculty, department := uuid.New(), uuid.New()
+	projectID := uuid.New()
+	actor := uuid.New()
+	member := uuid.New()
+
+	mandatory := policy.EventPolicy{
+		ID:               uuid.New(),
+		Name:             "Title changes need approval",
+		EventTypes:       []string{events2.TitleChangedType},
+		ActionType:       policy.ActionTypeRequestApproval,
+		RecipientDynamic: []string{"project_members"},
+		OrgNodeID:        &faculty,
+		Enabled:          true,
+		Locked:           true,
+	}
+	shadow := policy.EventPolicy{
+		ID:               uuid.New(),
+		Name:             "Our own title review",
+		EventTypes:       []string{events2.TitleChangedType, events2.DescriptionChangedType},
+		ActionType:       policy.ActionTypeRequestApproval,
+		RecipientDynamic: []string{"project_members"},
+		ProjectID:        &projectID,
+		Enabled:          true,
+	}
+	repo := &lockRepo{executionRepo: executionRepo{policies: []policy.EventPolicy{mandatory, shadow}}}
+	hierarchy := parentHierarchy{faculty: faculty, department: department}
+	svc := eventpolicy.NewService(repo, hierarchy)
+
+	// A department cannot shadow the faculty's locked policy, whatever the policy is called
+	draft := shadow
+	draft.ID = uuid.Nil
+	draft.ProjectID = nil
+	draft.OrgNodeID = &department
+	if _, err := svc.Create(ctx, draft); !errors.Is(err, policy.ErrPolicyLocked) {
+		t.Fatalf("expected ErrPolicyLocked, got %v", err)
+	}
+
+	// Neither can a project, which is checked when its policy event is decided
+	if err := svc.CheckProjectPolicy(ctx, shadow, department); !errors.Is(err, policy.ErrPolicyLocked) {
+		t.Fatalf("expected ErrPolicyLocked for the project policy, got %v", err)
+	}
+
+	// Only organisation policies can be locked
+	draft.Name = "Something else"
+	draft.OrgNodeID = nil
+	draft.ProjectID = &projectID
+	draft.Locked = true
+	if _, err := svc.Create(ctx, draft); !errors.Is(err, policy.ErrInvalidPolicy) {
+		t.Fatalf("expected ErrInvalidPolicy, got %v", err)
+	}
+	if len(repo.created) != 0 {
+		t.Fatalf("expected nothing to be created, got %d", len(repo.created))
+	}
+
+	// Policies with another action or without a common event type do not shadow it, even with the same name
+	for _, other := range []policy.EventPolicy{
+		{Name: mandatory.Name, EventTypes: []string{events2.TitleChangedType}, ActionType: policy.ActionTypeNotify},
+		{Name: mandatory.Name, EventTypes: []string{events2.DescriptionChangedType}, ActionType: policy.ActionTypeRequestApproval},
+	} {
+		other.RecipientDynamic = []string{"project_members"}
+		other.OrgNodeID = &department
+		if _, err := svc.Create(ctx, other); err != nil {
+			t.Fatalf("expected %s on %v to be allowed, got %v", other.ActionType, other.EventTypes, err)
+		}
+		if err := svc.CheckProjectPolicy(ctx, other, department); err != nil {
+			t.Fatalf("expected the project policy to be allowed, got %v", err)
+		}
+	}
+	if len(repo.created) != 2 {
+		t.Fatalf("expected 2 policies to be created, got %d", len(repo.created))
+	}
+
+	// The listing shows the provenance and lock state, and flags the shadowing project policy
+	listed, err := svc.ListForProject(ctx, projectID, department, true)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(listed) != 2 || !listed[0].Shadowed || !listed[1].Inherited || !listed[1].Locked || *listed[1].SourceOrgNodeID != faculty {
+		t.Fatalf("unexpected listing: %+v", listed)
+	}
+
+	// The evaluator ignores the shadowing project policy
+	notifier := &recordingNotifier{}
+	evaluator := eventpolicy.NewEvaluator(repo, hierarchy, fakeResolver{members: []uuid.UUID{member}}, notifier, nil, nil, nil)
+	evt := at(&events2.TitleChanged{Base: events2.NewBase(projectID, actor, events2.StatusPending), Title: "Beta"}, time.Now())
+	required, err := evaluator.CheckApprovalRequired(ctx, evt, &project.Project{Id: projectID, OwningOrgNodeID: department})
+	if err != nil || !required {
+		t.Fatalf("expected the locked policy to require approval: %v %v", required, err)
+	}
+	if err := evaluator.EvaluateAndExecute(ctx, evt, &project.Project{Id: projectID, OwningOrgNodeID: department}); err != nil {
+		t.Fatalf("evaluate: %v", err)
+	}
+	if len(repo.executions) != 1 || repo.executions[0].PolicyID != mandatory.ID {
+		t.Fatalf("expected only the locked policy to run: %+v", repo.executions)
+	}
+}
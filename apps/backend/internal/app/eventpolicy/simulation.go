@@ -0,0 +1,278 @@
+package eventpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	organisationhierarchy "github.com/SURF-Innovatie/MORIS/internal/app/organisation/hierarchy"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/policy"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/project"
+	internalevents "github.com/SURF-Innovatie/MORIS/internal/domain/project/events"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/project/projection"
+	"github.com/google/uuid"
+)
+
+const (
+	// maxSimulatedEvents caps the number of historic events evaluated in a single simulation
+	maxSimulatedEvents = 1000
+	// maxScannedEvents caps the number of historic events loaded in a single simulation,
+	// including those of projects outside the policy's scope
+	maxScannedEvents = 10 * maxSimulatedEvents
+	// simulationPageSize is the number of historic events loaded at once
+	simulationPageSize = 500
+)
+
+var (
+	ErrInvalidSimulation = errors.New("invalid_policy_simulation")
+	ErrProjectNotFound   = errors.New("project_not_found")
+)
+
+// SimulationOutcome is the result of evaluating one policy against one event
+type SimulationOutcome struct {
+	Matched          bool
+	RequiresApproval bool
+	RecipientUserIDs []uuid.UUID
+	Message          string
+}
+
+// HypotheticalEvent describes an event that has not been executed, in the same shape as an execute-event request
+type HypotheticalEvent struct {
+	ProjectID uuid.UUID
+	Type      string
+	Input     json.RawMessage
+}
+
+// SimulationRequest describes a dry-run of a draft (unsaved) policy.
+// Either Event or the From/To range must be set.
+type SimulationRequest struct {
+	Policy policy.EventPolicy
+	Actor  uuid.UUID
+	Event  *HypotheticalEvent
+	From   *time.Time
+	To     *time.Time
+}
+
+// SimulatedMatch describes an event the draft policy would have matched
+type SimulatedMatch struct {
+	EventID          uuid.UUID // uuid.Nil for hypothetical events
+	EventType        string
+	ProjectID        uuid.UUID
+	OccurredAt       time.Time
+	RequiresApproval bool
+	RecipientUserIDs []uuid.UUID
+	Message          string
+}
+
+// SimulationResult is the outcome of a policy dry-run
+type SimulationResult struct {
+	EvaluatedEvents int
+	Truncated       bool
+	Matches         []SimulatedMatch
+}
+
+// Simulator performs dry-runs of draft policies without persisting them or executing their actions
+type Simulator interface {
+	Simulate(ctx context.Context, req SimulationRequest) (*SimulationResult, error)
+}
+
+type simulator struct {
+	evaluator       Evaluator
+	events          EventStore
+	orgHierarchySvc organisationhierarchy.Service
+}
+
+// NewSimulator creates a new policy simulator
+func NewSimulator(evaluator Evaluator, events EventStore, orgHierarchySvc organisationhierarchy.Service) Simulator {
+	return &simulator{
+		evaluator:       evaluator,
+		events:          events,
+		orgHierarchySvc: orgHierarchySvc,
+	}
+}
+
+func (s *simulator) Simulate(ctx context.Context, req SimulationRequest) (*SimulationResult, error) {
+	if len(req.Policy.EventTypes) == 0 {
+		return nil, fmt.Errorf("%w: policy has no event types", ErrInvalidSimulation)
+	}
+
+	switch {
+	case req.Event != nil:
+		return s.simulateHypothetical(ctx, req)
+	case req.From != nil && req.To != nil:
+		if req.To.Before(*req.From) {
+			return nil, fmt.Errorf("%w: 'to' is before 'from'", ErrInvalidSimulation)
+		}
+		return s.simulateHistoric(ctx, req)
+	default:
+		return nil, fmt.Errorf("%w: either an event or a date range is required", ErrInvalidSimulation)
+	}
+}
+
+// simulateHypothetical decides the hypothetical event against the current project state and evaluates the draft policy
+func (s *simulator) simulateHypothetical(ctx context.Context, req SimulationRequest) (*SimulationResult, error) {
+	in := req.Event
+	if in.ProjectID == uuid.Nil {
+		return nil, fmt.Errorf("%w: event project id is required", ErrInvalidSimulation)
+	}
+
+	decider, ok := internalevents.GetDecider(in.Type)
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown event type: %s", ErrInvalidSimulation, in.Type)
+	}
+
+	if req.Policy.ProjectID != nil && *req.Policy.ProjectID != in.ProjectID {
+		return nil, fmt.Errorf("%w: project is outside the policy's scope", ErrInvalidSimulation)
+	}
+
+	history, _, err := s.events.Load(ctx, in.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) == 0 {
+		return nil, ErrProjectNotFound
+	}
+	inScope, err := s.projectInScope(ctx, req.Policy, in.ProjectID, history)
+	if err != nil {
+		return nil, err
+	}
+	if !inScope {
+		return nil, fmt.Errorf("%w: project is outside the policy's scope", ErrInvalidSimulation)
+	}
+	cur := projection.Reduce(in.ProjectID, history)
+
+	input := in.Input
+	if len(input) == 0 {
+		input = json.RawMessage("{}")
+	}
+
+	evt, err := decider(ctx, in.ProjectID, req.Actor, cur, input, internalevents.StatusApproved)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSimulation, err)
+	}
+
+	result := &SimulationResult{}
+	if evt == nil {
+		// The decider considered the input a no-op, nothing would be appended
+		return result, nil
+	}
+	result.EvaluatedEvents = 1
+
+	if err := s.evaluate(ctx, req.Policy, evt, cur, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// simulateHistoric replays the events in the requested range that are within the policy's scope.
+// Events are loaded page by page so that neither the evaluated nor the loaded events are unbounded.
+func (s *simulator) simulateHistoric(ctx context.Context, req SimulationRequest) (*SimulationResult, error) {
+	result := &SimulationResult{}
+	histories := make(map[uuid.UUID][]internalevents.Event)
+	inScope := make(map[uuid.UUID]bool)
+
+	for offset := 0; ; offset += simulationPageSize {
+		if offset >= maxScannedEvents {
+			result.Truncated = true
+			return result, nil
+		}
+
+		evts, err := s.events.LoadBetween(ctx, *req.From, *req.To, req.Policy.EventTypes, req.Policy.ProjectID, offset, simulationPageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, evt := range evts {
+			if evt.GetStatus() == internalevents.StatusRejected {
+				continue
+			}
+
+			projectID := evt.AggregateID()
+			history, ok := histories[projectID]
+			if !ok {
+				history, _, err = s.events.Load(ctx, projectID)
+				if err != nil {
+					return nil, err
+				}
+				histories[projectID] = history
+
+				inScope[projectID], err = s.projectInScope(ctx, req.Policy, projectID, history)
+				if err != nil {
+					return nil, err
+				}
+			}
+			if !inScope[projectID] {
+				continue
+			}
+
+			if result.EvaluatedEvents >= maxSimulatedEvents {
+				result.Truncated = true
+				return result, nil
+			}
+			result.EvaluatedEvents++
+
+			if err := s.evaluate(ctx, req.Policy, evt, stateBefore(projectID, history, evt.GetID()), result); err != nil {
+				return nil, err
+			}
+		}
+
+		if len(evts) < simulationPageSize {
+			return result, nil
+		}
+	}
+}
+
+// projectInScope reports whether a project falls under the draft policy's org node (or its descendants).
+// Policies without an org node scope apply to every project.
+func (s *simulator) projectInScope(ctx context.Context, eventPolicy policy.EventPolicy, projectID uuid.UUID, history []internalevents.Event) (bool, error) {
+	if eventPolicy.OrgNodeID == nil {
+		return true, nil
+	}
+
+	cur := projection.Reduce(projectID, history)
+	if cur == nil {
+		return false, nil
+	}
+	if cur.OwningOrgNodeID == *eventPolicy.OrgNodeID {
+		return true, nil
+	}
+	return s.orgHierarchySvc.IsAncestor(ctx, *eventPolicy.OrgNodeID, cur.OwningOrgNodeID)
+}
+
+func (s *simulator) evaluate(ctx context.Context, eventPolicy policy.EventPolicy, evt internalevents.Event, cur *project.Project, result *SimulationResult) error {
+	outcome, err := s.evaluator.Simulate(ctx, eventPolicy, evt, cur)
+	if err != nil {
+		return err
+	}
+	if !outcome.Matched {
+		return nil
+	}
+
+	result.Matches = append(result.Matches, SimulatedMatch{
+		EventID:          evt.GetID(),
+		EventType:        evt.Type(),
+		ProjectID:        evt.AggregateID(),
+		OccurredAt:       evt.OccurredAt(),
+		RequiresApproval: outcome.RequiresApproval,
+		RecipientUserIDs: outcome.RecipientUserIDs,
+		Message:          outcome.Message,
+	})
+	return nil
+}
+
+// stateBefore reduces the project as it was just before the given event was appended,
+// mirroring the state CheckApprovalRequired sees during execution.
+func stateBefore(projectID uuid.UUID, history []internalevents.Event, eventID uuid.UUID) *project.Project {
+	for i, e := range history {
+		if e.GetID() == eventID {
+			if i == 0 {
+				// The genesis event has no prior state; use the project it creates
+				return projection.Reduce(projectID, history[:1])
+			}
+			return projection.Reduce(projectID, history[:i])
+		}
+	}
+	return projection.Reduce(projectID, history)
+}
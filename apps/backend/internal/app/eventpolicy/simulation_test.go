@@ -0,0 +1,256 @@
+package eventpolicy_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/internal/app/eventpolicy"
+	"github.com/SURF-Innovatie/MORIS/internal/app/notification"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/locale"
+	notificationdomain "github.com/SURF-Innovatie/MORIS/internal/domain/notification"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/policy"
+	events2 "github.com/SURF-Innovatie/MORIS/internal/domain/project/events"
+	"github.com/google/uuid"
+)
+
+type fakePolicyRepo struct{}
+
+func (fakePolicyRepo) Create(context.Context, policy.EventPolicy) (*policy.EventPolicy, error) {
+	return nil, errors.New("not implemented")
+}
+func (fakePolicyRepo) Update(context.Context, uuid.UUID, policy.EventPolicy) (*policy.EventPolicy, error) {
+	return nil, errors.New("not implemented")
+}
+func (fakePolicyRepo) Delete(context.Context, uuid.UUID) error { return nil }
+func (fakePolicyRepo) GetByID(context.Context, uuid.UUID) (*policy.EventPolicy, error) {
+	return nil, errors.New("not found")
+}
+func (fakePolicyRepo) ListForOrgNode(context.Context, uuid.UUID, []uuid.UUID) ([]policy.EventPolicy, error) {
+	return nil, nil
+}
+func (fakePolicyRepo) ListForProject(context.Context, uuid.UUID) ([]policy.EventPolicy, error) {
+	return nil, nil
+}
+
+func (fakePolicyRepo) RecordExecution(context.Context, policy.Execution) error { return nil }
+func (fakePolicyRepo) ListExecutionsForEvent(context.Context, uuid.UUID) ([]policy.Execution, error) {
+	return nil, nil
+}
+func (fakePolicyRepo) ListExecutionsForPolicy(context.Context, uuid.UUID, int, int) ([]policy.Execution, int, error) {
+	return nil, 0, nil
+}
+
+type fakeHierarchy struct {
+	descendants map[uuid.UUID][]uuid.UUID
+}
+
+func (f fakeHierarchy) AncestorIDsInclusive(_ context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	return []uuid.UUID{id}, nil
+}
+func (f fakeHierarchy) AncestorIDs(context.Context, uuid.UUID) ([]uuid.UUID, error) {
+	return nil, nil
+}
+func (f fakeHierarchy) IsAncestor(_ context.Context, ancestorID, descendantID uuid.UUID) (bool, error) {
+	for _, id := range f.descendants[ancestorID] {
+		if id == descendantID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type fakeResolver struct {
+	members   []uuid.UUID
+	languages map[uuid.UUID]locale.Language
+}
+
+func (f fakeResolver) ResolveUsers(_ context.Context, ids []uuid.UUID) ([]uuid.UUID, error) {
+	return ids, nil
+}
+func (f fakeResolver) ResolveRole(context.Context, uuid.UUID, uuid.UUID) ([]uuid.UUID, error) {
+	return nil, nil
+}
+func (f fakeResolver) ResolveOrgRole(context.Context, uuid.UUID, uuid.UUID) ([]uuid.UUID, error) {
+	return nil, nil
+}
+func (f fakeResolver) ResolveDynamic(context.Context, string, uuid.UUID, uuid.UUID) ([]uuid.UUID, error) {
+	return f.members, nil
+}
+func (f fakeResolver) ResolveLanguages(context.Context, []uuid.UUID) (map[uuid.UUID]locale.Language, error) {
+	return f.languages, nil
+}
+
+// recordingNotifier counts the notifications that are sent and keeps the last message per recipient
+type recordingNotifier struct {
+	notification.Service
+	sent     int
+	messages map[uuid.UUID]string
+}
+
+func (n *recordingNotifier) Send(context.Context, []uuid.UUID, uuid.UUID, string, notificationdomain.NotificationType) error {
+	n.sent++
+	return nil
+}
+
+func (n *recordingNotifier) SendWithVariables(_ context.Context, userIDs []uuid.UUID, _ uuid.UUID, message string, _ notificationdomain.NotificationType, _ map[string]string) error {
+	n.sent++
+	if n.messages == nil {
+		n.messages = make(map[uuid.UUID]string)
+	}
+	for _, id := range userIDs {
+		n.messages[id] = message
+	}
+	return nil
+}
+
+type fakeEventStore struct {
+	byProject map[uuid.UUID][]events2.Event
+}
+
+func (s fakeEventStore) Load(_ context.Context, id uuid.UUID) ([]events2.Event, int, error) {
+	evts := s.byProject[id]
+	return evts, len(evts), nil
+}
+
+func (s fakeEventStore) LoadBetween(_ context.Context, from, to time.Time, types []string, projectID *uuid.UUID, offset, limit int) ([]events2.Event, error) {
+	var out []events2.Event
+	for id, evts := range s.byProject {
+		if projectID != nil && *projectID != id {
+			continue
+		}
+		for _, e := range evts {
+			if e.OccurredAt().Before(from) || e.OccurredAt().After(to) {
+				continue
+			}
+			for _, t := range types {
+				if e.Type() == t {
+					out = append(out, e)
+				}
+			}
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].OccurredAt().Before(out[j].OccurredAt()) })
+	if offset >= len(out) {
+		return nil, nil
+	}
+	return out[offset:min(offset+limit, len(out))], nil
+}
+
+func at(e events2.Event, ts time.Time) events2.Event {
+	e.SetBase(events2.Base{
+		ID:              uuid.New(),
+		ProjectID:       e.AggregateID(),
+		At:              ts,
+		CreatedBy:       e.CreatedByID(),
+		Status:          e.GetStatus(),
+		FriendlyNameStr: e.FriendlyName(),
+	})
+	return e
+}
+
+func TestSimulator_HistoricAndHypothetical(t *testing.T) {
+	ctx := context.Background()
+	projectID := uuid.New()
+	otherProjectID := uuid.New()
+	orgID := uuid.New()
+	childOrgID := uuid.New()
+	unrelatedOrgID := uuid.New()
+	actor := uuid.New()
+	member := uuid.New()
+
+	day := func(d int) time.Time { return time.Date(2025, 3, d, 12, 0, 0, 0, time.UTC) }
+
+	started := at(&events2.ProjectStarted{
+		Base:            events2.NewBase(projectID, actor, events2.StatusApproved),
+		Title:           "Alpha",
+		OwningOrgNodeID: childOrgID,
+	}, day(1))
+	secret := at(&events2.TitleChanged{Base: events2.NewBase(projectID, actor, events2.StatusApproved), Title: "Secret Alpha"}, day(2))
+	plain := at(&events2.TitleChanged{Base: events2.NewBase(projectID, actor, events2.StatusApproved), Title: "Alpha 2"}, day(3))
+	rejected := at(&events2.TitleChanged{Base: events2.NewBase(projectID, actor, events2.StatusRejected), Title: "Secret Beta"}, day(4))
+
+	otherStarted := at(&events2.ProjectStarted{
+		Base:            events2.NewBase(otherProjectID, actor, events2.StatusApproved),
+		Title:           "Other",
+		OwningOrgNodeID: unrelatedOrgID,
+	}, day(1))
+	otherSecret := at(&events2.TitleChanged{Base: events2.NewBase(otherProjectID, actor, events2.StatusApproved), Title: "Secret Other"}, day(2))
+
+	store := fakeEventStore{byProject: map[uuid.UUID][]events2.Event{
+		projectID:      {started, secret, plain, rejected},
+		otherProjectID: {otherStarted, otherSecret},
+	}}
+	hierarchy := fakeHierarchy{descendants: map[uuid.UUID][]uuid.UUID{orgID: {childOrgID}}}
+	notifier := &recordingNotifier{}
+
+	evaluator := eventpolicy.NewEvaluator(fakePolicyRepo{}, hierarchy, fakeResolver{members: []uuid.UUID{member}}, notifier, nil, nil, nil)
+	sim := eventpolicy.NewSimulator(evaluator, store, hierarchy)
+
+	draft := policy.EventPolicy{
+		Name:             "Secret titles need approval",
+		EventTypes:       []string{events2.TitleChangedType},
+		Conditions:       []policy.Condition{{Field: "event.Title", Operator: policy.OperatorStartsWith, Value: "Secret"}},
+		ActionType:       policy.ActionTypeRequestApproval,
+		RecipientDynamic: []string{"project_members"},
+		OrgNodeID:        &orgID,
+	}
+
+	from, to := day(1), day(30)
+	res, err := sim.Simulate(ctx, eventpolicy.SimulationRequest{Policy: draft, Actor: actor, From: &from, To: &to})
+	if err != nil {
+		t.Fatalf("historic simulation: %v", err)
+	}
+	if res.EvaluatedEvents != 2 {
+		t.Fatalf("expected 2 evaluated events, got %d", res.EvaluatedEvents)
+	}
+	if len(res.Matches) != 1 {
+		t.Fatalf("expected 1 match, got %d", len(res.Matches))
+	}
+	m := res.Matches[0]
+	if m.EventID != secret.GetID() || !m.RequiresApproval {
+		t.Fatalf("unexpected match: %+v", m)
+	}
+	if len(m.RecipientUserIDs) != 1 || m.RecipientUserIDs[0] != member {
+		t.Fatalf("unexpected recipients: %v", m.RecipientUserIDs)
+	}
+	if m.Message != "Request to change project title to 'Secret Alpha' requires approval" {
+		t.Fatalf("unexpected message: %q", m.Message)
+	}
+
+	input, _ := json.Marshal(events2.TitleChangedInput{Title: "Secret Gamma"})
+	res, err = sim.Simulate(ctx, eventpolicy.SimulationRequest{
+		Policy: draft,
+		Actor:  actor,
+		Event:  &eventpolicy.HypotheticalEvent{ProjectID: projectID, Type: events2.TitleChangedType, Input: input},
+	})
+	if err != nil {
+		t.Fatalf("hypothetical simulation: %v", err)
+	}
+	if len(res.Matches) != 1 || res.Matches[0].EventID != uuid.Nil {
+		t.Fatalf("expected one hypothetical match, got %+v", res.Matches)
+	}
+
+	// Projects outside the policy's scope cannot be simulated against
+	for _, scoped := range []policy.EventPolicy{draft, {EventTypes: draft.EventTypes, ProjectID: &projectID}} {
+		_, err = sim.Simulate(ctx, eventpolicy.SimulationRequest{
+			Policy: scoped,
+			Actor:  actor,
+			Event:  &eventpolicy.HypotheticalEvent{ProjectID: otherProjectID, Type: events2.TitleChangedType, Input: input},
+		})
+		if !errors.Is(err, eventpolicy.ErrInvalidSimulation) {
+			t.Fatalf("expected ErrInvalidSimulation for a project outside the scope, got %v", err)
+		}
+	}
+
+	if notifier.sent != 0 {
+		t.Fatalf("simulation must not send notifications, sent %d", notifier.sent)
+	}
+
+	if _, err := sim.Simulate(ctx, eventpolicy.SimulationRequest{Policy: draft}); !errors.Is(err, eventpolicy.ErrInvalidSimulation) {
+		t.Fatalf("expected ErrInvalidSimulation, got %v", err)
+	}
+}
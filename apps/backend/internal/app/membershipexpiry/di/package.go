@@ -0,0 +1,25 @@
+package di
+
+import (
+	"github.com/SURF-Innovatie/MORIS/internal/app/event"
+	"github.com/SURF-Innovatie/MORIS/internal/app/membershipexpiry"
+	"github.com/SURF-Innovatie/MORIS/internal/app/notification"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/cache"
+	membershipexpiryrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/membershipexpiry"
+	projectrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/project"
+	"github.com/samber/do/v2"
+)
+
+var Package = do.Package(
+	do.Lazy(provideMembershipExpiryJob),
+)
+
+func provideMembershipExpiryJob(i do.Injector) (membershipexpiry.Job, error) {
+	repo := do.MustInvoke[*membershipexpiryrepo.EntRepo](i)
+	projects := do.MustInvoke[*projectrepo.EntRepo](i)
+	evtSvc := do.MustInvoke[event.Service](i)
+	evtPub := do.MustInvoke[event.Publisher](i)
+	pc := do.MustInvoke[cache.ProjectCache](i)
+	notifSvc := do.MustInvoke[notification.Service](i)
+	return membershipexpiry.NewJob(repo, projects, evtSvc, evtPub, pc, notifSvc, membershipexpiry.Options{}), nil
+}
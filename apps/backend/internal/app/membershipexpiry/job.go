@@ -0,0 +1,285 @@
+package membershipexpiry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/internal/app/commandbus"
+	"github.com/SURF-Innovatie/MORIS/internal/app/project/command"
+	"github.com/SURF-Innovatie/MORIS/internal/app/project/load"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/locale"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/notification"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/project"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/project/events"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/project/projection"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+)
+
+// defaultNoticePeriod is how long before an end date the admins are told about it
+const defaultNoticePeriod = 14 * 24 * time.Hour
+
+// unassignCatchUp is how long after its end date a project role is still found for removal.
+// It covers the runs missed while the job was down.
+const unassignCatchUp = 30 * 24 * time.Hour
+
+// Job ends project role assignments whose end date has passed and tells the admins of the
+// organisation node about organisation memberships and project roles that are about to end.
+// Organisation memberships need no clean-up: permission checks ignore them after their end date.
+type Job interface {
+	// RunDue handles everything that is due at now
+	RunDue(ctx context.Context, now time.Time) (Result, error)
+
+	// Run calls RunDue every interval until ctx is cancelled
+	Run(ctx context.Context, interval time.Duration)
+}
+
+// Options configures the expiry job
+type Options struct {
+	// NoticePeriod is how long before an end date the admins are told; defaults to two weeks
+	NoticePeriod time.Duration
+}
+
+type job struct {
+	repo         Repository
+	projects     ProjectLister
+	store        commandbus.EventStore
+	exec         *commandbus.Executor[project.Project]
+	cache        load.Cache
+	notifier     Notifier
+	noticePeriod time.Duration
+}
+
+func NewJob(
+	repo Repository,
+	projects ProjectLister,
+	store commandbus.EventStore,
+	pub commandbus.EventPublisher,
+	cache load.Cache,
+	notifier Notifier,
+	opts Options,
+) Job {
+	return &job{
+		repo:         repo,
+		projects:     projects,
+		store:        store,
+		exec:         commandbus.NewExecutor[project.Project](store, pub, command.Reducer{}, nil),
+		cache:        cache,
+		notifier:     notifier,
+		noticePeriod: lo.Ternary(opts.NoticePeriod > 0, opts.NoticePeriod, defaultNoticePeriod),
+	}
+}
+
+func (j *job) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if res, err := j.RunDue(ctx, time.Now()); err != nil {
+			log.Error().Err(err).Msg("membership expiry: run failed")
+		} else if res.Unassigned > 0 || res.Notices > 0 {
+			log.Info().Msgf("membership expiry: unassigned %d project roles, sent %d notices", res.Unassigned, res.Notices)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *job) RunDue(ctx context.Context, now time.Time) (Result, error) {
+	var res Result
+
+	// Only projects with a member whose end date lies in the window are replayed
+	projectIDs, err := j.projects.ProjectIDsWithMemberEndDateBetween(ctx, now.Add(-unassignCatchUp), now.Add(j.noticePeriod))
+	if err != nil {
+		return res, err
+	}
+	for _, projectID := range lo.Uniq(projectIDs) {
+		if err := j.runForProject(ctx, projectID, now, &res); err != nil {
+			log.Error().Err(err).Msgf("membership expiry: failed for project %s", projectID)
+		}
+	}
+
+	memberships, err := j.repo.ExpiringMemberships(ctx, now, now.Add(j.noticePeriod))
+	if err != nil {
+		return res, err
+	}
+	for _, m := range memberships {
+		sent, err := j.noticeMembership(ctx, m, now)
+		if err != nil {
+			log.Error().Err(err).Msgf("membership expiry: failed to announce the end of membership %s", m.MembershipID)
+			continue
+		}
+		if sent {
+			res.Notices++
+		}
+	}
+
+	return res, nil
+}
+
+func (j *job) runForProject(ctx context.Context, projectID uuid.UUID, now time.Time, res *Result) error {
+	history, _, err := j.store.Load(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	cur := projection.Reduce(projectID, history)
+	if cur == nil {
+		return nil
+	}
+
+	var expired []project.Member
+	for _, m := range cur.Members {
+		switch {
+		case m.EndDate == nil:
+		case !now.Before(*m.EndDate):
+			expired = append(expired, m)
+		case m.EndDate.Sub(now) <= j.noticePeriod:
+			sent, err := j.noticeProjectRole(ctx, cur, history, m, now)
+			if err != nil {
+				log.Error().Err(err).Msgf("membership expiry: failed to announce the end of a role in project %s", projectID)
+				continue
+			}
+			if sent {
+				res.Notices++
+			}
+		}
+	}
+	if len(expired) == 0 {
+		return nil
+	}
+
+	// The end date was decided when the role was assigned, so the removal bypasses approval
+	// policies; uuid.Nil marks the system as the actor
+	unassigned := 0
+	proj, err := j.exec.Execute(ctx, projectID, func(ctx context.Context, cur *project.Project) ([]events.Event, error) {
+		var out []events.Event
+		for _, m := range expired {
+			e, err := events.DecideProjectRoleUnassigned(projectID, uuid.Nil, cur, events.ProjectRoleUnassignedInput{
+				PersonID:      m.PersonID,
+				ProjectRoleID: m.ProjectRoleID,
+			}, events.StatusApproved)
+			if err != nil {
+				return nil, err
+			}
+			if e != nil {
+				out = append(out, e)
+			}
+		}
+		unassigned = len(out)
+		return out, nil
+	})
+	if err != nil {
+		return fmt.Errorf("unassigning expired roles: %w", err)
+	}
+	_ = j.cache.SetProject(ctx, proj)
+	res.Unassigned += unassigned
+	return nil
+}
+
+func (j *job) noticeProjectRole(ctx context.Context, cur *project.Project, history []events.Event, m project.Member, now time.Time) (bool, error) {
+	n := Notice{Kind: NoticeProjectRole, SubjectID: cur.Id, PersonID: m.PersonID, RoleID: m.ProjectRoleID, EndDate: *m.EndDate}
+	return j.notice(ctx, n, func() (func(locale.Language) string, []uuid.UUID, uuid.UUID, error) {
+		recipients, err := j.repo.MemberManagers(ctx, cur.OwningOrgNodeID, now)
+		if err != nil {
+			return nil, nil, uuid.Nil, err
+		}
+		personName, err := j.repo.PersonName(ctx, m.PersonID)
+		if err != nil {
+			return nil, nil, uuid.Nil, err
+		}
+		roleName, err := j.repo.ProjectRoleName(ctx, m.ProjectRoleID)
+		if err != nil {
+			return nil, nil, uuid.Nil, err
+		}
+		build := func(lang locale.Language) string {
+			return fmt.Sprintf(noticeTexts[lang].projectRole, roleName, personName, cur.Title, m.EndDate.Format(time.DateOnly))
+		}
+		return build, recipients, assignmentEventID(history, m), nil
+	})
+}
+
+func (j *job) noticeMembership(ctx context.Context, m ExpiringMembership, now time.Time) (bool, error) {
+	n := Notice{Kind: NoticeOrganisationMembership, SubjectID: m.MembershipID, PersonID: m.PersonID, RoleID: m.RoleScopeID, EndDate: m.EndDate}
+	return j.notice(ctx, n, func() (func(locale.Language) string, []uuid.UUID, uuid.UUID, error) {
+		recipients, err := j.repo.MemberManagers(ctx, m.ScopeNodeID, now)
+		if err != nil {
+			return nil, nil, uuid.Nil, err
+		}
+		build := func(lang locale.Language) string {
+			return fmt.Sprintf(noticeTexts[lang].membership, m.RoleKey, m.PersonName, m.ScopeNodeName, m.EndDate.Format(time.DateOnly))
+		}
+		return build, recipients, uuid.Nil, nil
+	})
+}
+
+// noticeTexts are the notices in each supported language
+var noticeTexts = map[locale.Language]struct{ projectRole, membership string }{
+	locale.English: {
+		projectRole: "The %s role of %s in project '%s' ends on %s.",
+		membership:  "The %s membership of %s at %s ends on %s.",
+	},
+	locale.Dutch: {
+		projectRole: "De rol %s van %s in project '%s' eindigt op %s.",
+		membership:  "Het lidmaatschap %s van %s bij %s eindigt op %s.",
+	},
+}
+
+// notice claims the notice first so that concurrent runs never announce the same end date twice.
+// The claim is released when the notice cannot be sent.
+func (j *job) notice(ctx context.Context, n Notice, compose func() (func(locale.Language) string, []uuid.UUID, uuid.UUID, error)) (bool, error) {
+	claimed, err := j.repo.ClaimNotice(ctx, n)
+	if err != nil || !claimed {
+		return false, err
+	}
+
+	build, recipients, eventID, err := compose()
+	if err == nil {
+		err = j.sendLocalized(ctx, recipients, eventID, build)
+	}
+	if err != nil {
+		if relErr := j.repo.ReleaseNotice(ctx, n); relErr != nil {
+			log.Error().Err(relErr).Msgf("membership expiry: failed to release notice for person %s", n.PersonID)
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// sendLocalized sends each recipient the notice in their preferred language
+func (j *job) sendLocalized(ctx context.Context, userIDs []uuid.UUID, eventID uuid.UUID, build func(locale.Language) string) error {
+	languages, err := j.repo.ResolveLanguages(ctx, userIDs)
+	if err != nil {
+		log.Error().Err(err).Msg("membership expiry: error resolving recipient languages, falling back to the default language")
+	}
+	byLanguage := lo.GroupBy(userIDs, func(id uuid.UUID) locale.Language {
+		return locale.OrDefault(string(languages[id]))
+	})
+
+	for _, lang := range locale.Supported {
+		recipients := byLanguage[lang]
+		if len(recipients) == 0 {
+			continue
+		}
+		if err := j.notifier.Send(ctx, recipients, eventID, build(lang), notification.NotificationInfo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// assignmentEventID returns the latest event that assigned the member's role, so that the notice
+// links to the project
+func assignmentEventID(history []events.Event, m project.Member) uuid.UUID {
+	for i := len(history) - 1; i >= 0; i-- {
+		if e, ok := history[i].(*events.ProjectRoleAssigned); ok && e.PersonID == m.PersonID && e.ProjectRoleID == m.ProjectRoleID {
+			return e.GetID()
+		}
+	}
+	return uuid.Nil
+}
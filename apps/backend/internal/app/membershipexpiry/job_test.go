@@ -0,0 +1,190 @@
+package membershipexpiry_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/internal/app/membershipexpiry"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/locale"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/notification"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/project"
+	events2 "github.com/SURF-Innovatie/MORIS/internal/domain/project/events"
+	"github.com/google/uuid"
+)
+
+type fakeRepo struct {
+	memberships []membershipexpiry.ExpiringMembership
+	managers    []uuid.UUID
+	languages   map[uuid.UUID]locale.Language
+	notices     map[membershipexpiry.Notice]bool
+}
+
+func (r *fakeRepo) ExpiringMemberships(_ context.Context, from, to time.Time) ([]membershipexpiry.ExpiringMembership, error) {
+	var out []membershipexpiry.ExpiringMembership
+	for _, m := range r.memberships {
+		if !m.EndDate.Before(from) && m.EndDate.Before(to) {
+			out = append(out, m)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeRepo) MemberManagers(context.Context, uuid.UUID, time.Time) ([]uuid.UUID, error) {
+	return r.managers, nil
+}
+
+func (r *fakeRepo) PersonName(context.Context, uuid.UUID) (string, error) { return "Ada", nil }
+
+func (r *fakeRepo) ProjectRoleName(context.Context, uuid.UUID) (string, error) {
+	return "Contributor", nil
+}
+
+func (r *fakeRepo) ResolveLanguages(context.Context, []uuid.UUID) (map[uuid.UUID]locale.Language, error) {
+	return r.languages, nil
+}
+
+func (r *fakeRepo) ClaimNotice(_ context.Context, n membershipexpiry.Notice) (bool, error) {
+	if r.notices[n] {
+		return false, nil
+	}
+	r.notices[n] = true
+	return true, nil
+}
+
+func (r *fakeRepo) ReleaseNotice(_ context.Context, n membershipexpiry.Notice) error {
+	delete(r.notices, n)
+	return nil
+}
+
+type fakeStore map[uuid.UUID][]events2.Event
+
+func (s fakeStore) Load(_ context.Context, id uuid.UUID) ([]events2.Event, int, error) {
+	return s[id], len(s[id]), nil
+}
+
+func (s fakeStore) Append(_ context.Context, id uuid.UUID, _ int, evts ...events2.Event) error {
+	s[id] = append(s[id], evts...)
+	return nil
+}
+
+// fakeProjects answers from the role assignments in a store
+type fakeProjects struct{ store fakeStore }
+
+func (p fakeProjects) ProjectIDsWithMemberEndDateBetween(_ context.Context, from, to time.Time) ([]uuid.UUID, error) {
+	var out []uuid.UUID
+	for id, evts := range p.store {
+		for _, e := range evts {
+			if a, ok := e.(*events2.ProjectRoleAssigned); ok && a.EndDate != nil && !a.EndDate.Before(from) && !a.EndDate.After(to) {
+				out = append(out, id)
+			}
+		}
+	}
+	return out, nil
+}
+
+type fakeCache struct{}
+
+func (fakeCache) GetProject(context.Context, uuid.UUID) (*project.Project, error) { return nil, nil }
+func (fakeCache) SetProject(context.Context, *project.Project) error              { return nil }
+func (fakeCache) DeleteProject(context.Context, uuid.UUID) error                  { return nil }
+
+type sent struct {
+	userIDs []uuid.UUID
+	eventID uuid.UUID
+	message string
+}
+
+type fakeNotifier struct{ sent []sent }
+
+func (n *fakeNotifier) Send(_ context.Context, userIDs []uuid.UUID, eventID uuid.UUID, message string, _ notification.NotificationType) error {
+	n.sent = append(n.sent, sent{userIDs, eventID, message})
+	return nil
+}
+
+func assigned(projectID, personID, roleID uuid.UUID, end *time.Time) *events2.ProjectRoleAssigned {
+	return &events2.ProjectRoleAssigned{
+		Base:          events2.NewBase(projectID, uuid.New(), events2.StatusApproved),
+		PersonID:      personID,
+		ProjectRoleID: roleID,
+		EndDate:       end,
+	}
+}
+
+func TestJob_UnassignsExpiredRolesAndAnnouncesUpcomingEndsOnce(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	projectID := uuid.New()
+	roleID := uuid.New()
+	left, leaving, staying := uuid.New(), uuid.New(), uuid.New()
+	manager, dutchManager := uuid.New(), uuid.New()
+
+	yesterday := now.AddDate(0, 0, -1)
+	nextWeek := now.AddDate(0, 0, 7)
+	leavingAssignment := assigned(projectID, leaving, roleID, &nextWeek)
+
+	store := fakeStore{projectID: {
+		&events2.ProjectStarted{
+			Base:            events2.NewBase(projectID, uuid.New(), events2.StatusApproved),
+			Title:           "Alpha",
+			OwningOrgNodeID: uuid.New(),
+		},
+		assigned(projectID, left, roleID, &yesterday),
+		leavingAssignment,
+		assigned(projectID, staying, roleID, nil),
+	}}
+	repo := &fakeRepo{
+		managers:  []uuid.UUID{manager, dutchManager},
+		languages: map[uuid.UUID]locale.Language{dutchManager: locale.Dutch},
+		notices:   map[membershipexpiry.Notice]bool{},
+		memberships: []membershipexpiry.ExpiringMembership{{
+			MembershipID:  uuid.New(),
+			PersonID:      uuid.New(),
+			PersonName:    "Grace",
+			RoleScopeID:   uuid.New(),
+			RoleKey:       "admin",
+			ScopeNodeID:   uuid.New(),
+			ScopeNodeName: "Physics",
+			EndDate:       now.AddDate(0, 0, 3),
+		}},
+	}
+	notifier := &fakeNotifier{}
+
+	job := membershipexpiry.NewJob(repo, fakeProjects{store}, store, nil, fakeCache{}, notifier, membershipexpiry.Options{})
+
+	res, err := job.RunDue(ctx, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Unassigned != 1 || res.Notices != 2 {
+		t.Fatalf("expected 1 unassignment and 2 notices, got %+v", res)
+	}
+
+	last, ok := store[projectID][len(store[projectID])-1].(*events2.ProjectRoleUnassigned)
+	if !ok || last.PersonID != left || last.CreatedByID() != uuid.Nil {
+		t.Fatalf("expected a system ProjectRoleUnassigned for the member who left, got %+v", store[projectID][len(store[projectID])-1])
+	}
+
+	// Each notice goes out once per language
+	if len(notifier.sent) != 4 {
+		t.Fatalf("expected 4 notifications, got %d", len(notifier.sent))
+	}
+	if notifier.sent[0].eventID != leavingAssignment.GetID() || notifier.sent[0].userIDs[0] != manager {
+		t.Fatalf("expected the project notice to link the assignment and go to the manager, got %+v", notifier.sent[0])
+	}
+	if dutch := notifier.sent[1]; dutch.userIDs[0] != dutchManager || !strings.HasPrefix(dutch.message, "De rol Contributor van Ada in project 'Alpha' eindigt op ") {
+		t.Fatalf("expected the Dutch manager to get the notice in Dutch, got %+v", dutch)
+	}
+	if notifier.sent[2].eventID != uuid.Nil || notifier.sent[2].message != "The admin membership of Grace at Physics ends on 2026-03-04." {
+		t.Fatalf("expected the membership notice without an event, got %+v", notifier.sent[2])
+	}
+
+	res, err = job.RunDue(ctx, now.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Unassigned != 0 || res.Notices != 0 {
+		t.Fatalf("expected nothing on the second run, got %+v", res)
+	}
+}
@@ -0,0 +1,44 @@
+package membershipexpiry
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExpiringMembership is an organisation membership that is about to end
+type ExpiringMembership struct {
+	MembershipID  uuid.UUID
+	PersonID      uuid.UUID
+	PersonName    string
+	RoleScopeID   uuid.UUID
+	RoleKey       string
+	ScopeNodeID   uuid.UUID
+	ScopeNodeName string
+	EndDate       time.Time
+}
+
+type NoticeKind string
+
+const (
+	NoticeOrganisationMembership NoticeKind = "organisation_membership"
+	NoticeProjectRole            NoticeKind = "project_role"
+)
+
+// Notice identifies one announcement of an end date. SubjectID is the membership for organisation
+// memberships and the project for project roles; RoleID is the role scope or the project role.
+type Notice struct {
+	Kind      NoticeKind
+	SubjectID uuid.UUID
+	PersonID  uuid.UUID
+	RoleID    uuid.UUID
+	EndDate   time.Time
+}
+
+// Result counts what a run of the job did
+type Result struct {
+	// Unassigned is the number of project roles removed because their end date passed
+	Unassigned int
+	// Notices is the number of upcoming end dates announced to admins
+	Notices int
+}
@@ -0,0 +1,43 @@
+package membershipexpiry
+
+import (
+	"context"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/internal/domain/locale"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/notification"
+	"github.com/google/uuid"
+)
+
+// Repository finds expiring organisation memberships and the admins to tell, and de-duplicates notices
+type Repository interface {
+	// ExpiringMemberships returns the organisation memberships whose end date lies in [from, to)
+	ExpiringMemberships(ctx context.Context, from, to time.Time) ([]ExpiringMembership, error)
+
+	// MemberManagers returns the users holding the manage_members permission on the node at t
+	MemberManagers(ctx context.Context, nodeID uuid.UUID, at time.Time) ([]uuid.UUID, error)
+
+	PersonName(ctx context.Context, personID uuid.UUID) (string, error)
+	ProjectRoleName(ctx context.Context, roleID uuid.UUID) (string, error)
+
+	// ResolveLanguages returns the preferred language of each user; users without one are left out
+	ResolveLanguages(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]locale.Language, error)
+
+	// ClaimNotice records a notice; it returns false if the end date was already announced
+	ClaimNotice(ctx context.Context, n Notice) (bool, error)
+
+	// ReleaseNotice removes a claimed notice so that the next run retries it
+	ReleaseNotice(ctx context.Context, n Notice) error
+}
+
+// ProjectLister finds the projects whose role assignments the job checks
+type ProjectLister interface {
+	// ProjectIDsWithMemberEndDateBetween returns the projects with a member whose end date was at some
+	// point set to a day within [from, to]; the job checks each against its current state
+	ProjectIDsWithMemberEndDateBetween(ctx context.Context, from, to time.Time) ([]uuid.UUID, error)
+}
+
+// Notifier sends the expiry notices
+type Notifier interface {
+	Send(ctx context.Context, userIDs []uuid.UUID, eventID uuid.UUID, message string, notificationType notification.NotificationType) error
+}
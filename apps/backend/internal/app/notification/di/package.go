@@ -1,7 +1,10 @@
 package di
 
 import (
+	exmailer "github.com/SURF-Innovatie/MORIS/external/mailer"
 	"github.com/SURF-Innovatie/MORIS/internal/app/notification"
+	"github.com/SURF-Innovatie/MORIS/internal/app/user"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/env"
 	notificationrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/notification"
 	"github.com/samber/do/v2"
 )
@@ -12,5 +15,14 @@ var Package = do.Package(
 
 func provideNotificationService(i do.Injector) (notification.Service, error) {
 	repo := do.MustInvoke[*notificationrepo.EntRepo](i)
-	return notification.NewService(repo), nil
+	userSvc := do.MustInvoke[user.Service](i)
+
+	var mailer notification.Mailer
+	if client := do.MustInvoke[exmailer.Client](i); client != nil {
+		mailer = client
+	}
+	return notification.NewService(repo, repo, mailer, userSvc, notification.Options{
+		FrontendURL: env.Global.FrontendURL,
+		DigestHour:  7,
+	}), nil
 }
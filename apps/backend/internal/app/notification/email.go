@@ -0,0 +1,242 @@
+package notification
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/SURF-Innovatie/MORIS/internal/domain/locale"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/notification"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/project/events"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+//go:embed templates/*/*.tmpl
+var emailTemplates embed.FS
+
+// templateSet holds the email templates of one language, read from templates/<language>/
+type templateSet struct {
+	htmlEmail  *htmltemplate.Template
+	textEmail  *texttemplate.Template
+	htmlDigest *htmltemplate.Template
+	textDigest *texttemplate.Template
+}
+
+var templateSets = lo.SliceToMap(locale.Supported, func(lang locale.Language) (locale.Language, templateSet) {
+	dir := "templates/" + string(lang) + "/"
+	return lang, templateSet{
+		htmlEmail:  htmltemplate.Must(htmltemplate.ParseFS(emailTemplates, dir+"email.html.tmpl")),
+		textEmail:  texttemplate.Must(texttemplate.ParseFS(emailTemplates, dir+"email.txt.tmpl")),
+		htmlDigest: htmltemplate.Must(htmltemplate.ParseFS(emailTemplates, dir+"digest.html.tmpl")),
+		textDigest: texttemplate.Must(texttemplate.ParseFS(emailTemplates, dir+"digest.txt.tmpl")),
+	}
+})
+
+// emailTexts are the texts that emails compose outside of the templates
+type emailTexts struct {
+	OpenInbox          string
+	ReviewRequest      string
+	ViewProject        string
+	ApprovalRequested  string
+	Notification       string
+	DigestSubject      string // formatted with the period and the number of notifications
+	DigestSubjectOne   string // the same, for a single notification
+	Periods            map[notification.DigestFrequency]string
+	OtherNotifications string
+	UntitledProject    string
+}
+
+var texts = map[locale.Language]emailTexts{
+	locale.English: {
+		OpenInbox:          "Open your inbox",
+		ReviewRequest:      "Review the request",
+		ViewProject:        "View the project",
+		ApprovalRequested:  "Approval requested",
+		Notification:       "Notification",
+		DigestSubject:      "Your %s digest: %d notifications",
+		DigestSubjectOne:   "Your %s digest: %d notification",
+		Periods:            map[notification.DigestFrequency]string{notification.DigestDaily: "daily", notification.DigestWeekly: "weekly"},
+		OtherNotifications: "Other notifications",
+		UntitledProject:    "Untitled project",
+	},
+	locale.Dutch: {
+		OpenInbox:          "Open je inbox",
+		ReviewRequest:      "Bekijk het verzoek",
+		ViewProject:        "Bekijk het project",
+		ApprovalRequested:  "Goedkeuring gevraagd",
+		Notification:       "Melding",
+		DigestSubject:      "Je %s overzicht: %d meldingen",
+		DigestSubjectOne:   "Je %s overzicht: %d melding",
+		Periods:            map[notification.DigestFrequency]string{notification.DigestDaily: "dagelijkse", notification.DigestWeekly: "wekelijkse"},
+		OtherNotifications: "Overige meldingen",
+		UntitledProject:    "Naamloos project",
+	},
+}
+
+// eventName returns the friendly name of the notification's event in the given language
+func eventName(n notification.Notification, lang locale.Language) string {
+	switch {
+	case n.EventType != nil:
+		return events.FriendlyNameIn(*n.EventType, lang)
+	case n.EventFriendlyName != nil:
+		return *n.EventFriendlyName
+	default:
+		return ""
+	}
+}
+
+// emailData is shared by the HTML and plain-text templates. Message is the notification
+// text rendered from the event's NotificationTemplate or ApprovalRequestTemplate, the other
+// fields come from the same template variables.
+type emailData struct {
+	RecipientName string
+	Subject       string
+	Message       string
+	ProjectTitle  string
+	EventName     string
+	Link          string
+	ActionLabel   string
+	Variables     map[string]string
+}
+
+// emailContent is a rendered notification email
+type emailContent struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// renderEmail renders the email of a notification in the recipient's language. The message itself was
+// already rendered in that language when the notification was sent.
+func renderEmail(n notification.Notification, recipientName, frontendURL string, lang locale.Language) (emailContent, error) {
+	lang = locale.OrDefault(string(lang))
+	data := emailData{
+		RecipientName: recipientName,
+		Message:       n.Message,
+		ProjectTitle:  n.Variables["project.Title"],
+		EventName:     eventName(n, lang),
+		Variables:     n.Variables,
+	}
+	data.Link, data.ActionLabel = deepLink(n, frontendURL, lang)
+	data.Subject = emailSubject(n.Type, data.ProjectTitle, data.EventName, lang)
+
+	tmpl := templateSets[lang]
+	var text, html bytes.Buffer
+	if err := tmpl.textEmail.Execute(&text, data); err != nil {
+		return emailContent{}, fmt.Errorf("render text email: %w", err)
+	}
+	if err := tmpl.htmlEmail.Execute(&html, data); err != nil {
+		return emailContent{}, fmt.Errorf("render html email: %w", err)
+	}
+	return emailContent{Subject: data.Subject, Text: text.String(), HTML: html.String()}, nil
+}
+
+// deepLink points approval requests to the project's edit screen, where pending changes
+// are reviewed, other project notifications to the project and everything else to the inbox
+func deepLink(n notification.Notification, frontendURL string, lang locale.Language) (link, label string) {
+	base := strings.TrimRight(frontendURL, "/")
+	t := texts[lang]
+	switch {
+	case n.ProjectID == nil:
+		return base + "/dashboard/inbox", t.OpenInbox
+	case n.Type == notification.NotificationApprovalRequest:
+		return fmt.Sprintf("%s/projects/%s/edit", base, *n.ProjectID), t.ReviewRequest
+	default:
+		return fmt.Sprintf("%s/projects/%s", base, *n.ProjectID), t.ViewProject
+	}
+}
+
+func emailSubject(nt notification.NotificationType, projectTitle, eventName string, lang locale.Language) string {
+	t := texts[lang]
+	var subject string
+	switch {
+	case nt == notification.NotificationApprovalRequest:
+		subject = t.ApprovalRequested
+	case eventName != "":
+		subject = eventName
+	default:
+		subject = t.Notification
+	}
+	if projectTitle != "" {
+		subject += ": " + projectTitle
+	}
+	return "[MORIS] " + subject
+}
+
+// digestData is shared by the HTML and plain-text digest templates
+type digestData struct {
+	RecipientName string
+	Subject       string
+	Period        string
+	Count         int
+	Projects      []digestProject
+	InboxLink     string
+}
+
+type digestProject struct {
+	Title     string
+	Link      string
+	LinkLabel string
+	Items     []digestItem
+}
+
+type digestItem struct {
+	Message   string
+	EventName string
+}
+
+// renderDigest renders the notifications of one recipient in their language, grouped by project in order of first appearance
+func renderDigest(ns []notification.Notification, frequency notification.DigestFrequency, recipientName, frontendURL string, lang locale.Language) (emailContent, error) {
+	lang = locale.OrDefault(string(lang))
+	t := texts[lang]
+	data := digestData{
+		RecipientName: recipientName,
+		Period:        lo.CoalesceOrEmpty(t.Periods[frequency], string(frequency)),
+		Count:         len(ns),
+		InboxLink:     strings.TrimRight(frontendURL, "/") + "/dashboard/inbox",
+	}
+	subject := t.DigestSubject
+	if len(ns) == 1 {
+		subject = t.DigestSubjectOne
+	}
+	data.Subject = "[MORIS] " + fmt.Sprintf(subject, data.Period, len(ns))
+
+	index := make(map[uuid.UUID]int)
+	for _, n := range ns {
+		var key uuid.UUID
+		if n.ProjectID != nil {
+			key = *n.ProjectID
+		}
+		i, ok := index[key]
+		if !ok {
+			link, label := deepLink(notification.Notification{ProjectID: n.ProjectID}, frontendURL, lang)
+			title := t.OtherNotifications
+			if n.ProjectID != nil {
+				title = t.UntitledProject
+			}
+			i = len(data.Projects)
+			index[key] = i
+			data.Projects = append(data.Projects, digestProject{Title: title, Link: link, LinkLabel: label})
+		}
+
+		p := &data.Projects[i]
+		if t := n.Variables["project.Title"]; t != "" {
+			p.Title = t
+		}
+		p.Items = append(p.Items, digestItem{Message: n.Message, EventName: eventName(n, lang)})
+	}
+
+	tmpl := templateSets[lang]
+	var text, html bytes.Buffer
+	if err := tmpl.textDigest.Execute(&text, data); err != nil {
+		return emailContent{}, fmt.Errorf("render text digest: %w", err)
+	}
+	if err := tmpl.htmlDigest.Execute(&html, data); err != nil {
+		return emailContent{}, fmt.Errorf("render html digest: %w", err)
+	}
+	return emailContent{Subject: data.Subject, Text: text.String(), HTML: html.String()}, nil
+}
@@ -2,7 +2,11 @@ package notification
 
 import (
 	"context"
+	"time"
 
+	exmailer "github.com/SURF-Innovatie/MORIS/external/mailer"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/identity"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/locale"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/notification"
 	"github.com/google/uuid"
 )
@@ -15,4 +19,46 @@ type NotificationRepository interface {
 	ListForUser(ctx context.Context, userID uuid.UUID) ([]notification.Notification, error)
 	MarkAsRead(ctx context.Context, id uuid.UUID) error
 	MarkAsReadByEventID(ctx context.Context, eventID uuid.UUID) error
+
+	// ClaimPendingEmails returns up to limit notifications, oldest first, whose email is due and
+	// does not wait for a digest, and holds them back from other workers for lease
+	ClaimPendingEmails(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]notification.Notification, error)
+	// ClaimPendingDigest returns the due notifications waiting for the given digest, oldest first,
+	// and holds them back from other workers for lease
+	ClaimPendingDigest(ctx context.Context, frequency notification.DigestFrequency, now time.Time, lease time.Duration) ([]notification.Notification, error)
+	// RecordEmailAttempt records the outcome of an email delivery attempt
+	RecordEmailAttempt(ctx context.Context, id uuid.UUID, attempt notification.EmailAttempt) error
+	// EventType returns the type of the event a notification is about
+	EventType(ctx context.Context, eventID uuid.UUID) (string, error)
+
+	// ListPage returns a page of a user's notifications, newest first
+	ListPage(ctx context.Context, userID uuid.UUID, f notification.ListFilter) (*notification.Page, error)
+	CountUnread(ctx context.Context, userID uuid.UUID) (*notification.UnreadCounts, error)
+	// The user scoped methods below return notification.ErrNotFound for notifications of other users
+	MarkAsReadForUser(ctx context.Context, userID, id uuid.UUID) error
+	MarkAllAsRead(ctx context.Context, userID uuid.UUID) (int, error)
+	MarkAsReadByProject(ctx context.Context, userID, projectID uuid.UUID) (int, error)
+	Archive(ctx context.Context, userID, id uuid.UUID) error
+	Unarchive(ctx context.Context, userID, id uuid.UUID) error
+	DeleteForUser(ctx context.Context, userID, id uuid.UUID) error
+	// DeleteReadBefore deletes read notifications sent before cutoff and returns the number deleted
+	DeleteReadBefore(ctx context.Context, cutoff time.Time) (int, error)
+}
+
+type PreferenceRepository interface {
+	ListPreferences(ctx context.Context, userIDs []uuid.UUID) ([]notification.Preference, error)
+	// ReplacePreferences replaces all notification preferences of a user
+	ReplacePreferences(ctx context.Context, userID uuid.UUID, prefs []notification.Preference) ([]notification.Preference, error)
+}
+
+// Mailer sends a rendered email
+type Mailer interface {
+	// Send delivers the message; permanent rejections wrap exmailer.ErrRejected
+	Send(ctx context.Context, msg exmailer.Message) error
+}
+
+// PeopleResolver looks up the person (and so the email address) and the preferred language behind user accounts
+type PeopleResolver interface {
+	GetPeopleByUserIDs(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]identity.Person, error)
+	GetLanguagesByUserIDs(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]locale.Language, error)
 }
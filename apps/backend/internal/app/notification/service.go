@@ -2,28 +2,130 @@ package notification
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"time"
 
+	exmailer "github.com/SURF-Innovatie/MORIS/external/mailer"
+	"github.com/SURF-Innovatie/MORIS/internal/common/backoff"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/identity"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/locale"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/notification"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/project/events"
 	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
 )
 
 type Service interface {
 	Create(ctx context.Context, n notification.Notification) (*notification.Notification, error)
 	Send(ctx context.Context, userIDs []uuid.UUID, eventID uuid.UUID, message string, notificationType notification.NotificationType) error
+	// SendWithVariables is Send for messages rendered from template variables; the variables are
+	// stored with the notification so that its email can be rendered from them as well
+	SendWithVariables(ctx context.Context, userIDs []uuid.UUID, eventID uuid.UUID, message string, notificationType notification.NotificationType, vars map[string]string) error
 	Get(ctx context.Context, id uuid.UUID) (*notification.Notification, error)
 	Update(ctx context.Context, id uuid.UUID, n notification.Notification) (*notification.Notification, error)
 	List(ctx context.Context) ([]notification.Notification, error)
 	ListForUser(ctx context.Context, userID uuid.UUID) ([]notification.Notification, error)
 	MarkAsRead(ctx context.Context, id uuid.UUID) error
 	MarkAsReadByEventID(ctx context.Context, eventID uuid.UUID) error
+
+	// ProcessPendingEmails emails pending notifications and returns the number attempted
+	ProcessPendingEmails(ctx context.Context) (int, error)
+
+	// RunEmailDelivery processes pending emails every interval until ctx is cancelled
+	RunEmailDelivery(ctx context.Context, interval time.Duration)
+
+	// ProcessDigests sends one digest per recipient of the notifications waiting for the
+	// given digest and returns the number of digests attempted
+	ProcessDigests(ctx context.Context, frequency notification.DigestFrequency) (int, error)
+
+	// RunDigests sends the daily digests (and on Mondays the weekly digests) once a day
+	// after DigestHour, checking every interval until ctx is cancelled
+	RunDigests(ctx context.Context, interval time.Duration)
+
+	// ListInbox returns a page of the user's notifications, newest first
+	ListInbox(ctx context.Context, userID uuid.UUID, f notification.ListFilter) (*notification.Page, error)
+	UnreadCounts(ctx context.Context, userID uuid.UUID) (*notification.UnreadCounts, error)
+	MarkAsReadForUser(ctx context.Context, userID, id uuid.UUID) error
+	// MarkAllAsRead and MarkAsReadByProject return the number of notifications marked as read
+	MarkAllAsRead(ctx context.Context, userID uuid.UUID) (int, error)
+	MarkAsReadByProject(ctx context.Context, userID, projectID uuid.UUID) (int, error)
+	Archive(ctx context.Context, userID, id uuid.UUID) error
+	Unarchive(ctx context.Context, userID, id uuid.UUID) error
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+
+	// PurgeRead deletes read notifications older than the retention period and returns the number deleted
+	PurgeRead(ctx context.Context) (int, error)
+
+	// RunRetention purges old read notifications every interval until ctx is cancelled
+	RunRetention(ctx context.Context, interval time.Duration)
+
+	GetPreferences(ctx context.Context, userID uuid.UUID) ([]notification.Preference, error)
+	// UpdatePreferences replaces all notification preferences of a user
+	UpdatePreferences(ctx context.Context, userID uuid.UUID, prefs []notification.Preference) ([]notification.Preference, error)
 }
 
+// Options configures the email delivery of notifications
+type Options struct {
+	// FrontendURL is the base of the deep links in emails
+	FrontendURL string
+	// BatchSize is the number of emails sent per ProcessPendingEmails call
+	BatchSize int
+	// DigestHour is the hour of the day (UTC) after which digests are sent
+	DigestHour int
+	// MaxEmailAttempts is the number of attempts after which an email that keeps failing
+	// transiently is marked failed
+	MaxEmailAttempts int
+	// EmailBackoff is the delay before the first retry of an email; it doubles on every further attempt
+	EmailBackoff time.Duration
+	// MaxEmailBackoff caps the delay between attempts
+	MaxEmailBackoff time.Duration
+	// EmailLease is how long claimed emails are withheld from other workers; it must outlast a batch
+	EmailLease time.Duration
+	// Retention is how long read notifications are kept
+	Retention time.Duration
+	Now       func() time.Time
+}
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
 type service struct {
-	repo NotificationRepository
+	repo   NotificationRepository
+	prefs  PreferenceRepository
+	mailer Mailer
+	people PeopleResolver
+	opts   Options
 }
 
-func NewService(repo NotificationRepository) Service {
-	return &service{repo: repo}
+// NewService creates the notification service. Notifications are only emailed when mailer is not nil.
+func NewService(repo NotificationRepository, prefs PreferenceRepository, mailer Mailer, people PeopleResolver, opts Options) Service {
+	if opts.BatchSize == 0 {
+		opts.BatchSize = 50
+	}
+	if opts.MaxEmailAttempts == 0 {
+		opts.MaxEmailAttempts = 6
+	}
+	if opts.EmailBackoff == 0 {
+		opts.EmailBackoff = time.Minute
+	}
+	if opts.MaxEmailBackoff == 0 {
+		opts.MaxEmailBackoff = 6 * time.Hour
+	}
+	if opts.EmailLease == 0 {
+		opts.EmailLease = 10 * time.Minute
+	}
+	if opts.Retention == 0 {
+		opts.Retention = 90 * 24 * time.Hour
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	return &service{repo: repo, prefs: prefs, mailer: mailer, people: people, opts: opts}
 }
 
 func (s *service) Create(ctx context.Context, n notification.Notification) (*notification.Notification, error) {
@@ -31,16 +133,46 @@ func (s *service) Create(ctx context.Context, n notification.Notification) (*not
 }
 
 func (s *service) Send(ctx context.Context, userIDs []uuid.UUID, eventID uuid.UUID, message string, notificationType notification.NotificationType) error {
+	return s.SendWithVariables(ctx, userIDs, eventID, message, notificationType, nil)
+}
+
+func (s *service) SendWithVariables(ctx context.Context, userIDs []uuid.UUID, eventID uuid.UUID, message string, notificationType notification.NotificationType, vars map[string]string) error {
 	if len(userIDs) == 0 {
 		return nil
 	}
 
+	prefs, err := s.prefs.ListPreferences(ctx, userIDs)
+	if err != nil {
+		return err
+	}
+	byUser := lo.GroupBy(prefs, func(p notification.Preference) uuid.UUID { return p.UserID })
+
+	// The event type is only needed when someone has an event type specific preference
+	var eventType string
+	if eventID != uuid.Nil && lo.SomeBy(prefs, func(p notification.Preference) bool { return p.EventType != nil }) {
+		if eventType, err = s.repo.EventType(ctx, eventID); err != nil {
+			return err
+		}
+	}
+
 	for _, userID := range userIDs {
+		pref := notification.ResolvePreference(byUser[userID], notificationType, eventType)
+		if pref.Channel == notification.ChannelNone {
+			continue
+		}
+
 		n := notification.Notification{
-			UserID:  userID,
-			Message: message,
-			EventID: &eventID,
-			Type:    notificationType,
+			UserID:    userID,
+			Message:   message,
+			EventID:   &eventID,
+			Type:      notificationType,
+			Variables: vars,
+		}
+		if s.mailer != nil && pref.Channel == notification.ChannelEmail {
+			n.EmailStatus = lo.ToPtr(notification.EmailPending)
+			if pref.Frequency != notification.DigestImmediate {
+				n.EmailDigest = lo.ToPtr(pref.Frequency)
+			}
 		}
 
 		if _, err := s.repo.Create(ctx, n); err != nil {
@@ -73,3 +205,270 @@ func (s *service) MarkAsRead(ctx context.Context, id uuid.UUID) error {
 func (s *service) MarkAsReadByEventID(ctx context.Context, eventID uuid.UUID) error {
 	return s.repo.MarkAsReadByEventID(ctx, eventID)
 }
+
+func (s *service) ProcessPendingEmails(ctx context.Context) (int, error) {
+	if s.mailer == nil {
+		return 0, nil
+	}
+
+	pending, err := s.repo.ClaimPendingEmails(ctx, s.opts.Now(), s.opts.EmailLease, s.opts.BatchSize)
+	if err != nil || len(pending) == 0 {
+		return 0, err
+	}
+
+	userIDs := lo.Uniq(lo.Map(pending, func(n notification.Notification, _ int) uuid.UUID { return n.UserID }))
+	people, err := s.people.GetPeopleByUserIDs(ctx, userIDs)
+	if err != nil {
+		return 0, err
+	}
+	languages, err := s.people.GetLanguagesByUserIDs(ctx, userIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, n := range pending {
+		status, errMsg := s.deliverEmail(ctx, n, people, languages[n.UserID])
+		if err := s.repo.RecordEmailAttempt(ctx, n.ID, s.emailAttempt(n, status, errMsg)); err != nil {
+			return 0, err
+		}
+	}
+	return len(pending), nil
+}
+
+// deliverEmail renders and sends the email of a notification and returns the resulting status
+func (s *service) deliverEmail(ctx context.Context, n notification.Notification, people map[uuid.UUID]identity.Person, lang locale.Language) (notification.EmailStatus, *string) {
+	person, ok := people[n.UserID]
+	if !ok || person.Email == "" {
+		return notification.EmailFailed, lo.ToPtr("recipient has no email address")
+	}
+
+	content, err := renderEmail(n, person.Name, s.opts.FrontendURL, lang)
+	if err != nil {
+		return notification.EmailFailed, lo.ToPtr(err.Error())
+	}
+	return s.sendEmail(ctx, person, content)
+}
+
+// sendEmail sends rendered content to a person and maps the result to an email status. Permanent
+// (5xx) rejections bounce; other failures, such as 4xx replies and network errors, stay pending.
+func (s *service) sendEmail(ctx context.Context, person identity.Person, content emailContent) (notification.EmailStatus, *string) {
+	to := mail.Address{Name: person.Name, Address: person.Email}
+	err := s.mailer.Send(ctx, exmailer.Message{
+		To:      to.String(),
+		Subject: content.Subject,
+		Text:    content.Text,
+		HTML:    content.HTML,
+	})
+	switch {
+	case err == nil:
+		return notification.EmailSent, nil
+	case errors.Is(err, exmailer.ErrRejected):
+		return notification.EmailBounced, lo.ToPtr(err.Error())
+	default:
+		log.Warn().Err(err).Msgf("sending email to %s", person.Email)
+		return notification.EmailPending, lo.ToPtr(err.Error())
+	}
+}
+
+// emailAttempt computes the next state of a notification after an attempt to email it. Pending
+// emails are retried with exponential backoff until MaxEmailAttempts is reached.
+func (s *service) emailAttempt(n notification.Notification, status notification.EmailStatus, errMsg *string) notification.EmailAttempt {
+	now := s.opts.Now()
+	out := notification.EmailAttempt{Status: status, Error: errMsg}
+	switch status {
+	case notification.EmailSent:
+		out.SentAt = &now
+	case notification.EmailPending:
+		attempts := n.EmailAttempts + 1
+		if attempts >= s.opts.MaxEmailAttempts {
+			out.Status = notification.EmailFailed
+		} else {
+			out.NextAttemptAt = lo.ToPtr(now.Add(backoff.Exponential(s.opts.EmailBackoff, s.opts.MaxEmailBackoff, attempts)))
+		}
+	}
+	return out
+}
+
+func (s *service) ProcessDigests(ctx context.Context, frequency notification.DigestFrequency) (int, error) {
+	if s.mailer == nil {
+		return 0, nil
+	}
+
+	pending, err := s.repo.ClaimPendingDigest(ctx, frequency, s.opts.Now(), s.opts.EmailLease)
+	if err != nil || len(pending) == 0 {
+		return 0, err
+	}
+
+	byUser := lo.GroupBy(pending, func(n notification.Notification) uuid.UUID { return n.UserID })
+	userIDs := lo.Keys(byUser)
+	people, err := s.people.GetPeopleByUserIDs(ctx, userIDs)
+	if err != nil {
+		return 0, err
+	}
+	languages, err := s.people.GetLanguagesByUserIDs(ctx, userIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	for userID, ns := range byUser {
+		status, errMsg := s.deliverDigest(ctx, ns, frequency, people[userID], languages[userID])
+		for _, n := range ns {
+			if err := s.repo.RecordEmailAttempt(ctx, n.ID, s.emailAttempt(n, status, errMsg)); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return len(byUser), nil
+}
+
+// deliverDigest renders and sends the digest of one recipient and returns the resulting status
+func (s *service) deliverDigest(ctx context.Context, ns []notification.Notification, frequency notification.DigestFrequency, person identity.Person, lang locale.Language) (notification.EmailStatus, *string) {
+	if person.Email == "" {
+		return notification.EmailFailed, lo.ToPtr("recipient has no email address")
+	}
+
+	content, err := renderDigest(ns, frequency, person.Name, s.opts.FrontendURL, lang)
+	if err != nil {
+		return notification.EmailFailed, lo.ToPtr(err.Error())
+	}
+	return s.sendEmail(ctx, person, content)
+}
+
+func (s *service) RunDigests(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lastRun string
+	for {
+		now := s.opts.Now().UTC()
+		if today := now.Format(time.DateOnly); now.Hour() >= s.opts.DigestHour && today != lastRun {
+			lastRun = today
+			if _, err := s.ProcessDigests(ctx, notification.DigestDaily); err != nil {
+				log.Error().Err(err).Msg("notification: failed to send daily digests")
+			}
+			if now.Weekday() == time.Monday {
+				if _, err := s.ProcessDigests(ctx, notification.DigestWeekly); err != nil {
+					log.Error().Err(err).Msg("notification: failed to send weekly digests")
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *service) ListInbox(ctx context.Context, userID uuid.UUID, f notification.ListFilter) (*notification.Page, error) {
+	if f.Limit <= 0 {
+		f.Limit = defaultPageSize
+	}
+	f.Limit = min(f.Limit, maxPageSize)
+	page, err := s.repo.ListPage(ctx, userID, f)
+	if err != nil {
+		return nil, err
+	}
+
+	// Show event names in the user's language; the messages were rendered in it when they were sent
+	languages, err := s.people.GetLanguagesByUserIDs(ctx, []uuid.UUID{userID})
+	if err != nil {
+		return nil, err
+	}
+	if lang := languages[userID]; lang != locale.Default {
+		for i, n := range page.Items {
+			if n.EventType != nil {
+				page.Items[i].EventFriendlyName = lo.ToPtr(events.FriendlyNameIn(*n.EventType, lang))
+			}
+		}
+	}
+	return page, nil
+}
+
+func (s *service) UnreadCounts(ctx context.Context, userID uuid.UUID) (*notification.UnreadCounts, error) {
+	return s.repo.CountUnread(ctx, userID)
+}
+
+func (s *service) MarkAsReadForUser(ctx context.Context, userID, id uuid.UUID) error {
+	return s.repo.MarkAsReadForUser(ctx, userID, id)
+}
+
+func (s *service) MarkAllAsRead(ctx context.Context, userID uuid.UUID) (int, error) {
+	return s.repo.MarkAllAsRead(ctx, userID)
+}
+
+func (s *service) MarkAsReadByProject(ctx context.Context, userID, projectID uuid.UUID) (int, error) {
+	return s.repo.MarkAsReadByProject(ctx, userID, projectID)
+}
+
+func (s *service) Archive(ctx context.Context, userID, id uuid.UUID) error {
+	return s.repo.Archive(ctx, userID, id)
+}
+
+func (s *service) Unarchive(ctx context.Context, userID, id uuid.UUID) error {
+	return s.repo.Unarchive(ctx, userID, id)
+}
+
+func (s *service) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	return s.repo.DeleteForUser(ctx, userID, id)
+}
+
+func (s *service) PurgeRead(ctx context.Context) (int, error) {
+	return s.repo.DeleteReadBefore(ctx, s.opts.Now().Add(-s.opts.Retention))
+}
+
+func (s *service) RunRetention(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := s.PurgeRead(ctx); err != nil {
+			log.Error().Err(err).Msg("notification: failed to purge read notifications")
+		} else if n > 0 {
+			log.Info().Msgf("notification: purged %d read notifications", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *service) GetPreferences(ctx context.Context, userID uuid.UUID) ([]notification.Preference, error) {
+	return s.prefs.ListPreferences(ctx, []uuid.UUID{userID})
+}
+
+func (s *service) UpdatePreferences(ctx context.Context, userID uuid.UUID, prefs []notification.Preference) ([]notification.Preference, error) {
+	if err := notification.ValidatePreferences(prefs); err != nil {
+		return nil, err
+	}
+	for _, p := range prefs {
+		if p.EventType == nil {
+			continue
+		}
+		if _, err := events.Create(*p.EventType); err != nil {
+			return nil, fmt.Errorf("%w: %s", notification.ErrInvalidPreference, err)
+		}
+	}
+	return s.prefs.ReplacePreferences(ctx, userID, prefs)
+}
+
+func (s *service) RunEmailDelivery(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.ProcessPendingEmails(ctx); err != nil {
+			log.Error().Err(err).Msg("notification: failed to process pending emails")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,349 @@
+package notification_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	exmailer "github.com/SURF-Innovatie/MORIS/external/mailer"
+	"github.com/SURF-Innovatie/MORIS/internal/app/notification"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/identity"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/locale"
+	notificationdomain "github.com/SURF-Innovatie/MORIS/internal/domain/notification"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+type memRepo struct {
+	notification.NotificationRepository
+	rows      []notificationdomain.Notification
+	projectID uuid.UUID
+	eventType string
+	prefs     []notificationdomain.Preference
+}
+
+func (r *memRepo) Create(_ context.Context, n notificationdomain.Notification) (*notificationdomain.Notification, error) {
+	n.ID = uuid.New()
+	r.rows = append(r.rows, n)
+	return &n, nil
+}
+
+// due reports whether the email of n is pending and not held back at now
+func due(n notificationdomain.Notification, now time.Time) bool {
+	return n.EmailStatus != nil && *n.EmailStatus == notificationdomain.EmailPending &&
+		(n.EmailNextAttemptAt == nil || !n.EmailNextAttemptAt.After(now))
+}
+
+func (r *memRepo) ClaimPendingEmails(_ context.Context, now time.Time, lease time.Duration, limit int) ([]notificationdomain.Notification, error) {
+	var out []notificationdomain.Notification
+	for i, n := range r.rows {
+		if due(n, now) && n.EmailDigest == nil && len(out) < limit {
+			r.rows[i].EmailNextAttemptAt = lo.ToPtr(now.Add(lease))
+			n.ProjectID = &r.projectID
+			if r.eventType != "" {
+				n.EventType = &r.eventType
+			}
+			out = append(out, n)
+		}
+	}
+	return out, nil
+}
+
+func (r *memRepo) ClaimPendingDigest(_ context.Context, frequency notificationdomain.DigestFrequency, now time.Time, lease time.Duration) ([]notificationdomain.Notification, error) {
+	var out []notificationdomain.Notification
+	for i, n := range r.rows {
+		if due(n, now) && n.EmailDigest != nil && *n.EmailDigest == frequency {
+			r.rows[i].EmailNextAttemptAt = lo.ToPtr(now.Add(lease))
+			if n.Variables["project.Title"] != "" {
+				n.ProjectID = lo.ToPtr(uuid.NewSHA1(uuid.Nil, []byte(n.Variables["project.Title"])))
+			}
+			out = append(out, n)
+		}
+	}
+	return out, nil
+}
+
+func (r *memRepo) EventType(context.Context, uuid.UUID) (string, error) {
+	return r.eventType, nil
+}
+
+func (r *memRepo) ListPreferences(_ context.Context, userIDs []uuid.UUID) ([]notificationdomain.Preference, error) {
+	return lo.Filter(r.prefs, func(p notificationdomain.Preference, _ int) bool { return lo.Contains(userIDs, p.UserID) }), nil
+}
+
+func (r *memRepo) ReplacePreferences(_ context.Context, userID uuid.UUID, prefs []notificationdomain.Preference) ([]notificationdomain.Preference, error) {
+	r.prefs = lo.Reject(r.prefs, func(p notificationdomain.Preference, _ int) bool { return p.UserID == userID })
+	for _, p := range prefs {
+		p.UserID = userID
+		r.prefs = append(r.prefs, p)
+	}
+	return prefs, nil
+}
+
+func (r *memRepo) RecordEmailAttempt(_ context.Context, id uuid.UUID, attempt notificationdomain.EmailAttempt) error {
+	for i := range r.rows {
+		if r.rows[i].ID == id {
+			r.rows[i].EmailAttempts++
+			r.rows[i].EmailStatus = &attempt.Status
+			r.rows[i].EmailError = attempt.Error
+			r.rows[i].EmailSentAt = attempt.SentAt
+			r.rows[i].EmailNextAttemptAt = attempt.NextAttemptAt
+			return nil
+		}
+	}
+	return fmt.Errorf("notification %s not found", id)
+}
+
+type people map[uuid.UUID]identity.Person
+
+func (p people) GetPeopleByUserIDs(context.Context, []uuid.UUID) (map[uuid.UUID]identity.Person, error) {
+	return p, nil
+}
+
+// GetLanguagesByUserIDs prefers Dutch for people with a .nl email address
+func (p people) GetLanguagesByUserIDs(context.Context, []uuid.UUID) (map[uuid.UUID]locale.Language, error) {
+	out := make(map[uuid.UUID]locale.Language, len(p))
+	for id, person := range p {
+		out[id] = locale.English
+		if strings.HasSuffix(person.Email, ".nl") {
+			out[id] = locale.Dutch
+		}
+	}
+	return out, nil
+}
+
+type fakeMailer struct {
+	sent []exmailer.Message
+}
+
+func (m *fakeMailer) Send(_ context.Context, msg exmailer.Message) error {
+	switch {
+	case strings.Contains(msg.To, "bounce@"):
+		return fmt.Errorf("%w: 550 mailbox unavailable", exmailer.ErrRejected)
+	case strings.Contains(msg.To, "down@"):
+		return errors.New("dial: connection refused")
+	}
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func TestService_EmailDelivery(t *testing.T) {
+	ctx := context.Background()
+	ok, bounce, down, noEmail := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	repo := &memRepo{projectID: uuid.New()}
+	mailer := &fakeMailer{}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc := notification.NewService(repo, repo, mailer, people{
+		ok:      {Name: "Ada", Email: "ada@example.org"},
+		bounce:  {Name: "Bo", Email: "bounce@example.org"},
+		down:    {Name: "Di", Email: "down@example.org"},
+		noEmail: {Name: "Ne"},
+	}, notification.Options{
+		FrontendURL:      "https://moris.example.org/",
+		MaxEmailAttempts: 3,
+		EmailBackoff:     time.Minute,
+		Now:              func() time.Time { return now },
+	})
+
+	vars := map[string]string{"project.Title": "Alpha <beta>", "event.Title": "Alpha 2"}
+	message := "Request to change project title to 'Alpha 2' requires approval"
+	if err := svc.SendWithVariables(ctx, []uuid.UUID{ok, bounce, down, noEmail}, uuid.New(), message, notificationdomain.NotificationApprovalRequest, vars); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	n, err := svc.ProcessPendingEmails(ctx)
+	if err != nil || n != 4 {
+		t.Fatalf("expected 4 emails processed, got %d (%v)", n, err)
+	}
+
+	want := map[uuid.UUID]notificationdomain.EmailStatus{
+		ok:      notificationdomain.EmailSent,
+		bounce:  notificationdomain.EmailBounced,
+		down:    notificationdomain.EmailPending,
+		noEmail: notificationdomain.EmailFailed,
+	}
+	for _, row := range repo.rows {
+		if row.EmailStatus == nil || *row.EmailStatus != want[row.UserID] {
+			t.Fatalf("user %s: expected %s, got %v", row.UserID, want[row.UserID], row.EmailStatus)
+		}
+		if (row.EmailSentAt != nil) != (*row.EmailStatus == notificationdomain.EmailSent) {
+			t.Fatalf("user %s: unexpected sent at %v", row.UserID, row.EmailSentAt)
+		}
+	}
+
+	if len(mailer.sent) != 1 {
+		t.Fatalf("expected 1 email, got %d", len(mailer.sent))
+	}
+	msg := mailer.sent[0]
+	link := fmt.Sprintf("https://moris.example.org/projects/%s/edit", repo.projectID)
+	if msg.Subject != "[MORIS] Approval requested: Alpha <beta>" {
+		t.Fatalf("unexpected subject %q", msg.Subject)
+	}
+	if !strings.Contains(msg.Text, message) || !strings.Contains(msg.Text, link) || !strings.Contains(msg.Text, "Alpha <beta>") {
+		t.Fatalf("unexpected text body:\n%s", msg.Text)
+	}
+	if !strings.Contains(msg.HTML, `href="`+link+`"`) || !strings.Contains(msg.HTML, "Alpha &lt;beta&gt;") {
+		t.Fatalf("unexpected html body:\n%s", msg.HTML)
+	}
+
+	// The unreachable server is retried after the backoff, which doubles, until the attempts run out
+	if n, _ := svc.ProcessPendingEmails(ctx); n != 0 {
+		t.Fatalf("expected no due emails during the backoff, got %d", n)
+	}
+	downRow := func() notificationdomain.Notification {
+		row, _ := lo.Find(repo.rows, func(n notificationdomain.Notification) bool { return n.UserID == down })
+		return row
+	}
+	if next := downRow().EmailNextAttemptAt; next == nil || !next.Equal(now.Add(time.Minute)) {
+		t.Fatalf("expected a retry after a minute, got %v", next)
+	}
+	now = now.Add(time.Minute)
+	if n, _ := svc.ProcessPendingEmails(ctx); n != 1 {
+		t.Fatalf("expected the retry to be processed, got %d", n)
+	}
+	if row := downRow(); *row.EmailStatus != notificationdomain.EmailPending || !row.EmailNextAttemptAt.Equal(now.Add(2*time.Minute)) {
+		t.Fatalf("expected a retry after two minutes, got %s at %v", *row.EmailStatus, row.EmailNextAttemptAt)
+	}
+	now = now.Add(2 * time.Minute)
+	if n, _ := svc.ProcessPendingEmails(ctx); n != 1 {
+		t.Fatalf("expected the last retry to be processed, got %d", n)
+	}
+	if row := downRow(); *row.EmailStatus != notificationdomain.EmailFailed || row.EmailAttempts != 3 {
+		t.Fatalf("expected the email to fail after 3 attempts, got %s after %d", *row.EmailStatus, row.EmailAttempts)
+	}
+
+	// Nothing is left to send
+	now = now.Add(time.Hour)
+	if n, _ := svc.ProcessPendingEmails(ctx); n != 0 {
+		t.Fatalf("expected no pending emails, got %d", n)
+	}
+}
+
+func TestService_EmailInPreferredLanguage(t *testing.T) {
+	ctx := context.Background()
+	dutch, english := uuid.New(), uuid.New()
+	repo := &memRepo{projectID: uuid.New(), eventType: "project.title_changed"}
+	mailer := &fakeMailer{}
+	svc := notification.NewService(repo, repo, mailer, people{
+		dutch:   {Name: "Daan", Email: "daan@example.nl"},
+		english: {Name: "Ada", Email: "ada@example.org"},
+	}, notification.Options{FrontendURL: "https://moris.example.org"})
+
+	vars := map[string]string{"project.Title": "Alpha"}
+	if err := svc.SendWithVariables(ctx, []uuid.UUID{dutch}, uuid.New(), "Projecttitel gewijzigd in 'Beta'", notificationdomain.NotificationInfo, vars); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if err := svc.SendWithVariables(ctx, []uuid.UUID{english}, uuid.New(), "Project title changed to 'Beta'", notificationdomain.NotificationInfo, vars); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if n, err := svc.ProcessPendingEmails(ctx); err != nil || n != 2 {
+		t.Fatalf("expected 2 emails processed, got %d (%v)", n, err)
+	}
+
+	bySubject := lo.KeyBy(mailer.sent, func(m exmailer.Message) string { return m.Subject })
+	nl, ok := bySubject["[MORIS] Titelwijziging: Alpha"]
+	if !ok {
+		t.Fatalf("expected a Dutch subject, got %v", lo.Keys(bySubject))
+	}
+	for _, want := range []string{"Beste Daan", "Wijziging: Titelwijziging", "Bekijk het project"} {
+		if !strings.Contains(nl.Text, want) {
+			t.Fatalf("expected %q in the Dutch email:\n%s", want, nl.Text)
+		}
+	}
+	if !strings.Contains(nl.HTML, `lang="nl"`) {
+		t.Fatalf("expected a Dutch html body:\n%s", nl.HTML)
+	}
+	if en, ok := bySubject["[MORIS] Title Change: Alpha"]; !ok || !strings.Contains(en.Text, "Hello Ada") {
+		t.Fatalf("expected an English email, got %v", lo.Keys(bySubject))
+	}
+}
+
+func TestService_EmailDisabled(t *testing.T) {
+	repo := &memRepo{}
+	svc := notification.NewService(repo, repo, nil, people{}, notification.Options{})
+
+	if err := svc.Send(context.Background(), []uuid.UUID{uuid.New()}, uuid.New(), "hello", notificationdomain.NotificationInfo); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if repo.rows[0].EmailStatus != nil {
+		t.Fatalf("expected no email status without a mailer, got %v", *repo.rows[0].EmailStatus)
+	}
+}
+
+func TestService_PreferencesAndDigest(t *testing.T) {
+	ctx := context.Background()
+	user := uuid.New()
+	repo := &memRepo{eventType: "project.title_changed"}
+	mailer := &fakeMailer{}
+	svc := notification.NewService(repo, repo, mailer, people{user: {Name: "Ada", Email: "ada@example.org"}}, notification.Options{})
+
+	_, err := svc.UpdatePreferences(ctx, user, []notificationdomain.Preference{
+		{Channel: notificationdomain.ChannelEmail, Frequency: notificationdomain.DigestDaily},
+		{EventType: lo.ToPtr("project.title_changed"), Channel: notificationdomain.ChannelNone, Frequency: notificationdomain.DigestImmediate},
+		{NotificationType: lo.ToPtr(notificationdomain.NotificationStatusUpdate), Channel: notificationdomain.ChannelInApp, Frequency: notificationdomain.DigestImmediate},
+	})
+	if err != nil {
+		t.Fatalf("update preferences: %v", err)
+	}
+
+	_, err = svc.UpdatePreferences(ctx, user, []notificationdomain.Preference{
+		{EventType: lo.ToPtr("project.unknown"), Channel: notificationdomain.ChannelNone, Frequency: notificationdomain.DigestImmediate},
+	})
+	if !errors.Is(err, notificationdomain.ErrInvalidPreference) {
+		t.Fatalf("expected ErrInvalidPreference for an unknown event type, got %v", err)
+	}
+
+	send := func(message string, nt notificationdomain.NotificationType, project string) {
+		t.Helper()
+		if err := svc.SendWithVariables(ctx, []uuid.UUID{user}, uuid.New(), message, nt, map[string]string{"project.Title": project}); err != nil {
+			t.Fatalf("send: %v", err)
+		}
+	}
+
+	// Muted for the event type, but approval requests always stay in-app
+	send("title changed", notificationdomain.NotificationInfo, "Alpha")
+	send("approve title", notificationdomain.NotificationApprovalRequest, "Alpha")
+	if len(repo.rows) != 1 || repo.rows[0].Type != notificationdomain.NotificationApprovalRequest || repo.rows[0].EmailStatus != nil {
+		t.Fatalf("expected only an in-app approval request, got %+v", repo.rows)
+	}
+
+	repo.eventType = "project.description_changed"
+	send("status changed", notificationdomain.NotificationStatusUpdate, "Alpha")
+	send("description changed", notificationdomain.NotificationInfo, "Alpha")
+	send("budget changed", notificationdomain.NotificationInfo, "Beta")
+	send("end date changed", notificationdomain.NotificationInfo, "Alpha")
+
+	if n, _ := svc.ProcessPendingEmails(ctx); n != 0 {
+		t.Fatalf("digest notifications must not be emailed immediately, got %d", n)
+	}
+	if n, _ := svc.ProcessDigests(ctx, notificationdomain.DigestWeekly); n != 0 {
+		t.Fatalf("expected no weekly digest, got %d", n)
+	}
+	n, err := svc.ProcessDigests(ctx, notificationdomain.DigestDaily)
+	if err != nil || n != 1 {
+		t.Fatalf("expected 1 daily digest, got %d (%v)", n, err)
+	}
+
+	if len(mailer.sent) != 1 {
+		t.Fatalf("expected 1 email, got %d", len(mailer.sent))
+	}
+	digest := mailer.sent[0]
+	if digest.Subject != "[MORIS] Your daily digest: 3 notifications" {
+		t.Fatalf("unexpected subject %q", digest.Subject)
+	}
+	alpha, beta := strings.Index(digest.Text, "== Alpha =="), strings.Index(digest.Text, "== Beta ==")
+	if alpha < 0 || beta < alpha || strings.Count(digest.Text, "==") != 4 {
+		t.Fatalf("expected the digest to group by project:\n%s", digest.Text)
+	}
+	if strings.Index(digest.Text, "end date changed") > beta {
+		t.Fatalf("expected Alpha's notifications together:\n%s", digest.Text)
+	}
+	for _, row := range repo.rows {
+		if row.EmailDigest != nil && *row.EmailStatus != notificationdomain.EmailSent {
+			t.Fatalf("expected digest notifications to be marked sent, got %s", *row.EmailStatus)
+		}
+	}
+}
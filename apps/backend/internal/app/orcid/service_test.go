@@ -8,6 +8,7 @@ import (
 	ext "github.com/SURF-Innovatie/MORIS/external/orcid"
 	"github.com/SURF-Innovatie/MORIS/internal/app/orcid"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/identity"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/locale"
 	"github.com/google/uuid"
 )
 
@@ -123,6 +124,14 @@ func (r *fakeUserRepo) ClearZenodoTokens(context.Context, uuid.UUID) error {
 	return errors.New("not implemented")
 }
 
+func (r *fakeUserRepo) SetLanguage(context.Context, uuid.UUID, locale.Language) error {
+	return errors.New("not implemented")
+}
+
+func (r *fakeUserRepo) LanguagesByIDs(context.Context, []uuid.UUID) (map[uuid.UUID]locale.Language, error) {
+	return nil, errors.New("not implemented")
+}
+
 type fakePersonRepo struct {
 	byID map[uuid.UUID]identity.Person
 }
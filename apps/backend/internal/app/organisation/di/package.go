@@ -1,17 +1,30 @@
 package di
 
 import (
+	"github.com/SURF-Innovatie/MORIS/external/ror"
+	"github.com/SURF-Innovatie/MORIS/internal/app/audit"
+	coreauth "github.com/SURF-Innovatie/MORIS/internal/app/auth"
+	"github.com/SURF-Innovatie/MORIS/internal/app/event"
 	"github.com/SURF-Innovatie/MORIS/internal/app/organisation"
 	"github.com/SURF-Innovatie/MORIS/internal/app/organisation/hierarchy"
 	organisationhierarchy "github.com/SURF-Innovatie/MORIS/internal/app/organisation/hierarchy"
+	organisationprovisioning "github.com/SURF-Innovatie/MORIS/internal/app/organisation/provisioning"
 	organisationrbac "github.com/SURF-Innovatie/MORIS/internal/app/organisation/rbac"
+	organisationrestructure "github.com/SURF-Innovatie/MORIS/internal/app/organisation/restructure"
 	organisationrole "github.com/SURF-Innovatie/MORIS/internal/app/organisation/role"
+	organisationrorimport "github.com/SURF-Innovatie/MORIS/internal/app/organisation/rorimport"
+	"github.com/SURF-Innovatie/MORIS/internal/app/project/load"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/cache"
 	"github.com/SURF-Innovatie/MORIS/internal/infra/persistence/enttx"
 	organisationrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/organisation"
 	organisationhierarchyrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/organisation/hierarchy"
+	organisationprovisioningrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/organisation/provisioning"
 	organisationrbacrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/organisation/rbac"
+	organisationrestructurerepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/organisation/restructure"
 	organisationrolerepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/organisation/role"
+	organisationrorimportrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/organisation/rorimport"
 	personrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/person"
+	projectrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/project"
 	"github.com/samber/do/v2"
 )
 
@@ -20,6 +33,9 @@ var Package = do.Package(
 	do.Lazy(provideOrgRBACService),
 	do.Lazy(provideOrgRoleService),
 	do.Lazy(provideOrgHierarchyService),
+	do.Lazy(provideOrgRestructureService),
+	do.Lazy(provideOrgRORImportService),
+	do.Lazy(provideOrgProvisioningService),
 )
 
 func provideOrganisationService(i do.Injector) (organisation.Service, error) {
@@ -37,10 +53,43 @@ func provideOrgRBACService(i do.Injector) (organisationrbac.Service, error) {
 
 func provideOrgRoleService(i do.Injector) (organisationrole.Service, error) {
 	repo := do.MustInvoke[*organisationrolerepo.EntRepo](i)
-	return organisationrole.NewService(repo), nil
+	auditSvc := do.MustInvoke[audit.Service](i)
+	txManager := do.MustInvoke[*enttx.Manager](i)
+	return organisationrole.NewService(repo, auditSvc, txManager), nil
 }
 
 func provideOrgHierarchyService(i do.Injector) (hierarchy.Service, error) {
 	repo := do.MustInvoke[*organisationhierarchyrepo.EntRepo](i)
 	return organisationhierarchy.NewService(repo), nil
 }
+
+func provideOrgRestructureService(i do.Injector) (organisationrestructure.Service, error) {
+	repo := do.MustInvoke[*organisationrestructurerepo.EntRepo](i)
+	orgSvc := do.MustInvoke[organisation.Service](i)
+	hierarchySvc := do.MustInvoke[hierarchy.Service](i)
+	projects := do.MustInvoke[*projectrepo.EntRepo](i)
+	ldr := do.MustInvoke[*load.Loader](i)
+	pc := do.MustInvoke[cache.ProjectCache](i)
+	evtSvc := do.MustInvoke[event.Service](i)
+	evtPub := do.MustInvoke[event.Publisher](i)
+	curUser := do.MustInvoke[coreauth.CurrentUserProvider](i)
+	txManager := do.MustInvoke[*enttx.Manager](i)
+	return organisationrestructure.NewService(repo, orgSvc, hierarchySvc, projects, ldr, pc, evtSvc, evtPub, curUser, txManager), nil
+}
+
+func provideOrgRORImportService(i do.Injector) (organisationrorimport.Service, error) {
+	repo := do.MustInvoke[*organisationrorimportrepo.EntRepo](i)
+	client := do.MustInvoke[*ror.Client](i)
+	orgSvc := do.MustInvoke[organisation.Service](i)
+	txManager := do.MustInvoke[*enttx.Manager](i)
+	return organisationrorimport.NewService(repo, client, orgSvc, txManager), nil
+}
+
+func provideOrgProvisioningService(i do.Injector) (organisationprovisioning.Service, error) {
+	repo := do.MustInvoke[*organisationprovisioningrepo.EntRepo](i)
+	orgSvc := do.MustInvoke[organisation.Service](i)
+	hierarchySvc := do.MustInvoke[hierarchy.Service](i)
+	auditSvc := do.MustInvoke[audit.Service](i)
+	txManager := do.MustInvoke[*enttx.Manager](i)
+	return organisationprovisioning.NewService(repo, orgSvc, hierarchySvc, auditSvc, txManager), nil
+}
@@ -12,6 +12,7 @@ type repository interface {
 	CreateNode(ctx context.Context, name string, parentID *uuid.UUID, rorID *string, description *string, avatarURL *string) (*organisation.OrganisationNode, error)
 	GetNode(ctx context.Context, id uuid.UUID) (*organisation.OrganisationNode, error)
 	UpdateNode(ctx context.Context, id uuid.UUID, name string, parentID *uuid.UUID, rorID *string, description *string, avatarURL *string) (*organisation.OrganisationNode, error)
+	SetParent(ctx context.Context, id uuid.UUID, parentID *uuid.UUID) error
 
 	ListRoots(ctx context.Context) ([]organisation.OrganisationNode, error)
 	ListChildren(ctx context.Context, parentID uuid.UUID) ([]organisation.OrganisationNode, error)
@@ -0,0 +1,24 @@
+package provisioning
+
+import (
+	"context"
+
+	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation/rbac"
+	"github.com/google/uuid"
+)
+
+type repository interface {
+	ListRules(ctx context.Context, rootNodeID uuid.UUID) ([]rbac.ProvisioningRule, error)
+	GetRule(ctx context.Context, id uuid.UUID) (*rbac.ProvisioningRule, error)
+	CreateRule(ctx context.Context, rule rbac.ProvisioningRule) (*rbac.ProvisioningRule, error)
+	UpdateRule(ctx context.Context, rule rbac.ProvisioningRule) (*rbac.ProvisioningRule, error)
+	DeleteRule(ctx context.Context, id uuid.UUID) error
+	// RulesForHomeOrganization returns the rules of all root nodes for a normalized schacHomeOrganization
+	RulesForHomeOrganization(ctx context.Context, schacHomeOrganization string) ([]rbac.ProvisioningRule, error)
+
+	GetScope(ctx context.Context, id uuid.UUID) (*rbac.RoleScope, error)
+	ListMemberships(ctx context.Context, personID uuid.UUID) ([]rbac.Membership, error)
+	AddProvisionedMembership(ctx context.Context, personID, roleScopeID uuid.UUID) (*rbac.Membership, error)
+	ClearMembershipPeriod(ctx context.Context, membershipID uuid.UUID) (*rbac.Membership, error)
+	RemoveMembership(ctx context.Context, membershipID uuid.UUID) error
+}
@@ -0,0 +1,266 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/internal/app/audit"
+	orgsvc "github.com/SURF-Innovatie/MORIS/internal/app/organisation"
+	"github.com/SURF-Innovatie/MORIS/internal/app/organisation/hierarchy"
+	"github.com/SURF-Innovatie/MORIS/internal/app/tx"
+	auditdomain "github.com/SURF-Innovatie/MORIS/internal/domain/audit"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation/rbac"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// RuleInput holds the configurable fields of a provisioning rule
+type RuleInput struct {
+	RoleScopeID           uuid.UUID
+	SchacHomeOrganization string
+	Affiliation           *string
+	Entitlement           *string
+}
+
+// Service manages the provisioning rules of root nodes and applies them at SURFconext login.
+// Every change to a rule or to a membership it grants is recorded in the audit log.
+type Service interface {
+	ListRules(ctx context.Context, rootNodeID uuid.UUID) ([]rbac.ProvisioningRule, error)
+	GetRule(ctx context.Context, id uuid.UUID) (*rbac.ProvisioningRule, error)
+	CreateRule(ctx context.Context, rootNodeID uuid.UUID, in RuleInput) (*rbac.ProvisioningRule, error)
+	UpdateRule(ctx context.Context, id uuid.UUID, in RuleInput) (*rbac.ProvisioningRule, error)
+	DeleteRule(ctx context.Context, id uuid.UUID) error
+
+	// Sync brings the provisioned memberships of a person in line with the rules matching the claims:
+	// it creates missing memberships, reactivates provisioned memberships whose period has ended and
+	// revokes provisioned memberships no rule grants anymore. Memberships added by hand are left alone.
+	// Without a schacHomeOrganization claim no rule matches, so all provisioned memberships are revoked.
+	Sync(ctx context.Context, personID uuid.UUID, claims rbac.ProvisioningClaims) (*rbac.ProvisioningResult, error)
+}
+
+type service struct {
+	repo      repository
+	orgSvc    orgsvc.Service
+	hierarchy hierarchy.Service
+	audit     audit.Service
+	tx        tx.Manager
+	now       func() time.Time
+}
+
+func NewService(repo repository, orgSvc orgsvc.Service, hierarchySvc hierarchy.Service, auditSvc audit.Service, tx tx.Manager) Service {
+	return &service{repo: repo, orgSvc: orgSvc, hierarchy: hierarchySvc, audit: auditSvc, tx: tx, now: time.Now}
+}
+
+func (s *service) ListRules(ctx context.Context, rootNodeID uuid.UUID) ([]rbac.ProvisioningRule, error) {
+	return s.repo.ListRules(ctx, rootNodeID)
+}
+
+func (s *service) GetRule(ctx context.Context, id uuid.UUID) (*rbac.ProvisioningRule, error) {
+	return s.repo.GetRule(ctx, id)
+}
+
+func (s *service) CreateRule(ctx context.Context, rootNodeID uuid.UUID, in RuleInput) (*rbac.ProvisioningRule, error) {
+	rule := rbac.ProvisioningRule{RootNodeID: rootNodeID}
+	if err := s.apply(ctx, &rule, in); err != nil {
+		return nil, err
+	}
+
+	var created *rbac.ProvisioningRule
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = s.repo.CreateRule(ctx, rule)
+		if err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, auditdomain.Change{
+			OrgNodeID:  rootNodeID,
+			EntityType: auditdomain.EntityProvisioningRule,
+			EntityID:   created.ID,
+			Action:     auditdomain.ActionCreate,
+			After:      created,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (s *service) UpdateRule(ctx context.Context, id uuid.UUID, in RuleInput) (*rbac.ProvisioningRule, error) {
+	var updated *rbac.ProvisioningRule
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetRule(ctx, id)
+		if err != nil {
+			return err
+		}
+		rule := *before
+		if err := s.apply(ctx, &rule, in); err != nil {
+			return err
+		}
+		updated, err = s.repo.UpdateRule(ctx, rule)
+		if err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, auditdomain.Change{
+			OrgNodeID:  before.RootNodeID,
+			EntityType: auditdomain.EntityProvisioningRule,
+			EntityID:   id,
+			Action:     auditdomain.ActionUpdate,
+			Before:     before,
+			After:      updated,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (s *service) DeleteRule(ctx context.Context, id uuid.UUID) error {
+	return s.tx.WithTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetRule(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.DeleteRule(ctx, id); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, auditdomain.Change{
+			OrgNodeID:  before.RootNodeID,
+			EntityType: auditdomain.EntityProvisioningRule,
+			EntityID:   id,
+			Action:     auditdomain.ActionDelete,
+			Before:     before,
+		})
+	})
+}
+
+// apply validates in against the rule's root node and copies it onto the rule
+func (s *service) apply(ctx context.Context, rule *rbac.ProvisioningRule, in RuleInput) error {
+	home := rbac.NormalizeHomeOrganization(in.SchacHomeOrganization)
+	if home == "" {
+		return fmt.Errorf("%w: schacHomeOrganization is required", rbac.ErrInvalidProvisioningRule)
+	}
+
+	root, err := s.orgSvc.Get(ctx, rule.RootNodeID)
+	if err != nil {
+		return err
+	}
+	if root.ParentID != nil {
+		return fmt.Errorf("%w: rules can only be configured on a root node", rbac.ErrInvalidProvisioningRule)
+	}
+	scope, err := s.repo.GetScope(ctx, in.RoleScopeID)
+	if err != nil {
+		return err
+	}
+	inTree, err := s.hierarchy.IsAncestor(ctx, rule.RootNodeID, scope.RootNodeID)
+	if err != nil {
+		return err
+	}
+	if !inTree {
+		return fmt.Errorf("%w: the role scope lies outside the tree of the root node", rbac.ErrInvalidProvisioningRule)
+	}
+
+	rule.RoleScopeID = in.RoleScopeID
+	rule.SchacHomeOrganization = home
+	rule.Affiliation = emptyToNil(in.Affiliation)
+	rule.Entitlement = emptyToNil(in.Entitlement)
+	return nil
+}
+
+func (s *service) Sync(ctx context.Context, personID uuid.UUID, claims rbac.ProvisioningClaims) (*rbac.ProvisioningResult, error) {
+	result := &rbac.ProvisioningResult{}
+	// Without a home organisation no rule grants anything, but earlier provisioned memberships are still revoked
+	granted := map[uuid.UUID]bool{}
+	if home := rbac.NormalizeHomeOrganization(claims.SchacHomeOrganization); home != "" {
+		rules, err := s.repo.RulesForHomeOrganization(ctx, home)
+		if err != nil {
+			return nil, err
+		}
+		for _, rule := range rules {
+			if rule.Matches(claims) {
+				granted[rule.RoleScopeID] = true
+			}
+		}
+	}
+
+	now := s.now()
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		memberships, err := s.repo.ListMemberships(ctx, personID)
+		if err != nil {
+			return err
+		}
+		byScope := lo.KeyBy(memberships, func(m rbac.Membership) uuid.UUID { return m.RoleScopeID })
+
+		for scopeID := range granted {
+			m, ok := byScope[scopeID]
+			switch {
+			case !ok:
+				created, err := s.repo.AddProvisionedMembership(ctx, personID, scopeID)
+				if err != nil {
+					return err
+				}
+				if err := s.record(ctx, auditdomain.ActionCreate, scopeID, created.ID, nil, created); err != nil {
+					return err
+				}
+				result.Created = append(result.Created, *created)
+			case m.Provisioned && !m.ActiveAt(now):
+				updated, err := s.repo.ClearMembershipPeriod(ctx, m.ID)
+				if err != nil {
+					return err
+				}
+				if err := s.record(ctx, auditdomain.ActionUpdate, scopeID, m.ID, &m, updated); err != nil {
+					return err
+				}
+				result.Updated = append(result.Updated, *updated)
+			}
+		}
+
+		for _, m := range memberships {
+			if !m.Provisioned || granted[m.RoleScopeID] {
+				continue
+			}
+			if err := s.repo.RemoveMembership(ctx, m.ID); err != nil {
+				return err
+			}
+			if err := s.record(ctx, auditdomain.ActionDelete, m.RoleScopeID, m.ID, &m, nil); err != nil {
+				return err
+			}
+			result.Revoked = append(result.Revoked, m)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// record files a membership change under the root node of its role scope, like manual changes
+func (s *service) record(ctx context.Context, action auditdomain.Action, roleScopeID, membershipID uuid.UUID, before, after *rbac.Membership) error {
+	scope, err := s.repo.GetScope(ctx, roleScopeID)
+	if err != nil {
+		return err
+	}
+	c := auditdomain.Change{
+		OrgNodeID:  scope.RootNodeID,
+		EntityType: auditdomain.EntityOrganisationMembership,
+		EntityID:   membershipID,
+		Action:     action,
+	}
+	if before != nil {
+		c.Before = before
+	}
+	if after != nil {
+		c.After = after
+	}
+	return s.audit.Record(ctx, c)
+}
+
+func emptyToNil(s *string) *string {
+	if s == nil || *s == "" {
+		return nil
+	}
+	return s
+}
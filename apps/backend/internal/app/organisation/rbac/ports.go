@@ -2,6 +2,7 @@ package organisation_rbac
 
 import (
 	"context"
+	"time"
 
 	"github.com/SURF-Innovatie/MORIS/internal/domain/identity"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation"
@@ -17,6 +18,11 @@ type repository interface {
 	GetApprovalNode(ctx context.Context, nodeID uuid.UUID) (*organisation.OrganisationNode, error)
 	HasAdminAccess(ctx context.Context, personID uuid.UUID, nodeID uuid.UUID) (bool, error)
 	HasPermission(ctx context.Context, personID uuid.UUID, nodeID uuid.UUID, permission rbac.Permission) (bool, error)
+	HasPermissionAnywhere(ctx context.Context, personID uuid.UUID, permission rbac.Permission) (bool, error)
+	// NodeIDsWithPermission returns the nodes on which the person holds the permission through an active membership,
+	// including the descendants of the nodes the memberships are scoped to
+	NodeIDsWithPermission(ctx context.Context, personID uuid.UUID, permission rbac.Permission) ([]uuid.UUID, error)
+	ExplainPermission(ctx context.Context, personID uuid.UUID, nodeID uuid.UUID, permission rbac.Permission) (*rbac.PermissionExplanation, error)
 }
 
 type EffectiveMembership struct {
@@ -25,6 +31,8 @@ type EffectiveMembership struct {
 
 	RoleScopeID           uuid.UUID
 	ScopeRootOrganisation *organisation.OrganisationNode
+	StartDate             *time.Time
+	EndDate               *time.Time
 
 	RoleID         uuid.UUID
 	RoleKey        string
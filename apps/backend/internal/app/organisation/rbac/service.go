@@ -16,6 +16,9 @@ type Service interface {
 	GetApprovalNode(ctx context.Context, nodeID uuid.UUID) (*organisation.OrganisationNode, error)
 	HasAdminAccess(ctx context.Context, personID uuid.UUID, nodeID uuid.UUID) (bool, error)
 	HasPermission(ctx context.Context, personID uuid.UUID, nodeID uuid.UUID, permission rbac.Permission) (bool, error)
+	HasPermissionAnywhere(ctx context.Context, personID uuid.UUID, permission rbac.Permission) (bool, error)
+	NodeIDsWithPermission(ctx context.Context, personID uuid.UUID, permission rbac.Permission) ([]uuid.UUID, error)
+	ExplainPermission(ctx context.Context, personID uuid.UUID, nodeID uuid.UUID, permission rbac.Permission) (*rbac.PermissionExplanation, error)
 }
 
 type service struct {
@@ -43,6 +46,18 @@ func (s *service) HasPermission(ctx context.Context, personID uuid.UUID, nodeID
 	return s.repo.HasPermission(ctx, personID, nodeID, permission)
 }
 
+func (s *service) HasPermissionAnywhere(ctx context.Context, personID uuid.UUID, permission rbac.Permission) (bool, error) {
+	return s.repo.HasPermissionAnywhere(ctx, personID, permission)
+}
+
+func (s *service) NodeIDsWithPermission(ctx context.Context, personID uuid.UUID, permission rbac.Permission) ([]uuid.UUID, error) {
+	return s.repo.NodeIDsWithPermission(ctx, personID, permission)
+}
+
 func (s *service) GetMyPermissions(ctx context.Context, userID, nodeID uuid.UUID) ([]rbac.Permission, error) {
 	return s.repo.GetMyPermissions(ctx, userID, nodeID)
 }
+
+func (s *service) ExplainPermission(ctx context.Context, personID uuid.UUID, nodeID uuid.UUID, permission rbac.Permission) (*rbac.PermissionExplanation, error) {
+	return s.repo.ExplainPermission(ctx, personID, nodeID, permission)
+}
@@ -0,0 +1,21 @@
+package restructure
+
+import (
+	"context"
+
+	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation"
+	"github.com/google/uuid"
+)
+
+type repository interface {
+	Usage(ctx context.Context, nodeID uuid.UUID) (organisation.NodeUsage, error)
+	Reassign(ctx context.Context, fromID, toID uuid.UUID) (organisation.Reassignment, error)
+	DeleteWebhookSubscriptions(ctx context.Context, nodeID uuid.UUID) (int, error)
+	DeleteNode(ctx context.Context, nodeID uuid.UUID) (int, error)
+}
+
+// ProjectRepository finds the projects that may be owned by a node
+type ProjectRepository interface {
+	// ProjectIDsEverOwnedBy returns the projects that were started under or moved to one of the nodes
+	ProjectIDsEverOwnedBy(ctx context.Context, nodeIDs []uuid.UUID) ([]uuid.UUID, error)
+}
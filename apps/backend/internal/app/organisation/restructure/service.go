@@ -0,0 +1,243 @@
+package restructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	appauth "github.com/SURF-Innovatie/MORIS/internal/app/auth"
+	"github.com/SURF-Innovatie/MORIS/internal/app/commandbus"
+	"github.com/SURF-Innovatie/MORIS/internal/app/event"
+	orgsvc "github.com/SURF-Innovatie/MORIS/internal/app/organisation"
+	"github.com/SURF-Innovatie/MORIS/internal/app/organisation/hierarchy"
+	"github.com/SURF-Innovatie/MORIS/internal/app/project/command"
+	"github.com/SURF-Innovatie/MORIS/internal/app/project/load"
+	"github.com/SURF-Innovatie/MORIS/internal/app/tx"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/project"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/project/events"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// DeleteOptions controls a node deletion. Without ReassignTo a node that is still in use is not deleted.
+type DeleteOptions struct {
+	ReassignTo *uuid.UUID
+	DryRun     bool
+}
+
+// Service deletes, merges and moves organisation nodes. A dry run performs the database changes in a
+// transaction that is rolled back, so its report is exactly what the real operation would do.
+type Service interface {
+	// Delete removes a node. Without a reassignment target it fails with organisation.ErrNodeInUse
+	// while children, projects, roles, custom fields, memberships, policies or provisioning rules still reference it;
+	// with a target the node is merged into it.
+	Delete(ctx context.Context, id uuid.UUID, opts DeleteOptions) (*organisation.RestructureReport, error)
+	// Merge re-points everything from sourceID to targetID, moves the children of sourceID under
+	// targetID and removes sourceID
+	Merge(ctx context.Context, sourceID, targetID uuid.UUID, dryRun bool) (*organisation.RestructureReport, error)
+	// Move re-parents a subtree (nil makes it a root) and rebuilds its closure rows
+	Move(ctx context.Context, id uuid.UUID, newParentID *uuid.UUID, dryRun bool) (*organisation.RestructureReport, error)
+}
+
+type service struct {
+	repo        repository
+	orgSvc      orgsvc.Service
+	hierarchy   hierarchy.Service
+	projects    ProjectRepository
+	loader      *load.Loader
+	cache       load.Cache
+	exec        *commandbus.Executor[project.Project]
+	currentUser appauth.CurrentUserProvider
+	tx          tx.Manager
+}
+
+func NewService(
+	repo repository,
+	orgSvc orgsvc.Service,
+	hierarchySvc hierarchy.Service,
+	projects ProjectRepository,
+	loader *load.Loader,
+	cache load.Cache,
+	evtSvc event.Service,
+	evtPub event.Publisher,
+	currentUser appauth.CurrentUserProvider,
+	tx tx.Manager,
+) Service {
+	return &service{
+		repo:        repo,
+		orgSvc:      orgSvc,
+		hierarchy:   hierarchySvc,
+		projects:    projects,
+		loader:      loader,
+		cache:       cache,
+		exec:        commandbus.NewExecutor[project.Project](evtSvc, evtPub, command.Reducer{}, nil),
+		currentUser: currentUser,
+		tx:          tx,
+	}
+}
+
+func (s *service) Delete(ctx context.Context, id uuid.UUID, opts DeleteOptions) (*organisation.RestructureReport, error) {
+	if opts.ReassignTo != nil {
+		return s.merge(ctx, organisation.RestructureDelete, id, *opts.ReassignTo, opts.DryRun)
+	}
+
+	if _, err := s.orgSvc.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	report := &organisation.RestructureReport{Operation: organisation.RestructureDelete, NodeID: id, DryRun: opts.DryRun}
+
+	// The usage is checked in the transaction, so that nothing can start using the node in between
+	err := tx.WithDryRun(ctx, s.tx, opts.DryRun, func(ctx context.Context) error {
+		var err error
+		if report.Usage, err = s.usage(ctx, id); err != nil {
+			return err
+		}
+		if report.Usage.InUse() {
+			return fmt.Errorf("%w: reassign its data to another node first", organisation.ErrNodeInUse)
+		}
+		if report.DeletedWebhookSubscriptions, err = s.repo.DeleteWebhookSubscriptions(ctx, id); err != nil {
+			return err
+		}
+		report.DeletedClosureRows, err = s.repo.DeleteNode(ctx, id)
+		return err
+	})
+	if errors.Is(err, organisation.ErrNodeInUse) {
+		return report, err
+	}
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (s *service) Merge(ctx context.Context, sourceID, targetID uuid.UUID, dryRun bool) (*organisation.RestructureReport, error) {
+	return s.merge(ctx, organisation.RestructureMerge, sourceID, targetID, dryRun)
+}
+
+func (s *service) merge(ctx context.Context, op organisation.RestructureOperation, sourceID, targetID uuid.UUID, dryRun bool) (*organisation.RestructureReport, error) {
+	if sourceID == targetID {
+		return nil, fmt.Errorf("%w: a node cannot be merged into itself", organisation.ErrInvalidRestructure)
+	}
+	if _, err := s.orgSvc.Get(ctx, sourceID); err != nil {
+		return nil, err
+	}
+	if _, err := s.orgSvc.Get(ctx, targetID); err != nil {
+		return nil, err
+	}
+	inSubtree, err := s.hierarchy.IsAncestor(ctx, sourceID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if inSubtree {
+		return nil, fmt.Errorf("%w: the target lies within the subtree of the node", organisation.ErrInvalidRestructure)
+	}
+
+	report := &organisation.RestructureReport{Operation: op, NodeID: sourceID, TargetID: &targetID, DryRun: dryRun}
+	if report.Usage, err = s.usage(ctx, sourceID); err != nil {
+		return nil, err
+	}
+
+	// Projects live in the event store, outside the transaction below. They are moved first so that a
+	// failure never leaves a project owned by a removed node; repeating the merge finishes the job.
+	report.ReassignedProjectIDs = report.Usage.ProjectIDs
+	if !dryRun {
+		if err := s.reassignProjects(ctx, report.Usage.ProjectIDs, targetID); err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.WithDryRun(ctx, s.tx, dryRun, func(ctx context.Context) error {
+		// Children added since the projects were moved are moved as well
+		usage, err := s.repo.Usage(ctx, sourceID)
+		if err != nil {
+			return err
+		}
+		usage.ProjectIDs = report.Usage.ProjectIDs
+		report.Usage = usage
+
+		for _, childID := range report.Usage.ChildIDs {
+			move, err := s.orgSvc.Move(ctx, childID, &targetID)
+			if err != nil {
+				return err
+			}
+			report.MovedChildren = append(report.MovedChildren, *move)
+		}
+
+		if report.Reassignment, err = s.repo.Reassign(ctx, sourceID, targetID); err != nil {
+			return err
+		}
+		report.DeletedClosureRows, err = s.repo.DeleteNode(ctx, sourceID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (s *service) Move(ctx context.Context, id uuid.UUID, newParentID *uuid.UUID, dryRun bool) (*organisation.RestructureReport, error) {
+	if newParentID != nil {
+		if _, err := s.orgSvc.Get(ctx, *newParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &organisation.RestructureReport{Operation: organisation.RestructureMove, NodeID: id, TargetID: newParentID, DryRun: dryRun}
+	err := tx.WithDryRun(ctx, s.tx, dryRun, func(ctx context.Context) error {
+		move, err := s.orgSvc.Move(ctx, id, newParentID)
+		if err != nil {
+			return err
+		}
+		report.MovedChildren = []organisation.SubtreeMove{*move}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// run executes fn in a transaction, which is rolled back for a dry run
+// usage returns what references the node, including the projects it currently owns
+func (s *service) usage(ctx context.Context, nodeID uuid.UUID) (organisation.NodeUsage, error) {
+	usage, err := s.repo.Usage(ctx, nodeID)
+	if err != nil {
+		return usage, err
+	}
+
+	ids, err := s.projects.ProjectIDsEverOwnedBy(ctx, []uuid.UUID{nodeID})
+	if err != nil {
+		return usage, err
+	}
+	usage.ProjectIDs = lo.Filter(ids, func(id uuid.UUID, _ int) bool {
+		proj, err := s.loader.Load(ctx, id)
+		return err == nil && proj.OwningOrgNodeID == nodeID
+	})
+	return usage, nil
+}
+
+// reassignProjects records an owning organisation change on each project. These events bypass
+// approval policies: the restructure itself is the authorised decision.
+func (s *service) reassignProjects(ctx context.Context, projectIDs []uuid.UUID, targetID uuid.UUID) error {
+	if len(projectIDs) == 0 {
+		return nil
+	}
+	u, err := s.currentUser.Current(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range projectIDs {
+		proj, err := s.exec.Execute(ctx, id, func(ctx context.Context, cur *project.Project) ([]events.Event, error) {
+			return commandbus.One(events.DecideOwningOrgNodeChanged(id, u.UserID, cur, events.OwningOrgNodeChangedInput{
+				OwningOrgNodeID: targetID,
+			}, events.StatusApproved))
+		})
+		if err != nil {
+			return fmt.Errorf("reassigning project %s: %w", id, err)
+		}
+		_ = s.cache.SetProject(ctx, proj)
+	}
+	return nil
+}
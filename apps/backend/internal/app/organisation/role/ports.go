@@ -2,6 +2,7 @@ package role
 
 import (
 	"context"
+	"time"
 
 	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation/rbac"
 	"github.com/google/uuid"
@@ -18,8 +19,11 @@ type repository interface {
 
 	CreateScope(ctx context.Context, roleKey string, rootNodeID uuid.UUID) (*rbac.RoleScope, error)
 	GetScope(ctx context.Context, id uuid.UUID) (*rbac.RoleScope, error)
+	// FindScope returns nil when the role has no scope on the node yet
+	FindScope(ctx context.Context, roleKey string, rootNodeID uuid.UUID) (*rbac.RoleScope, error)
 
-	AddMembership(ctx context.Context, personID uuid.UUID, roleScopeID uuid.UUID) (*rbac.Membership, error)
+	AddMembership(ctx context.Context, personID uuid.UUID, roleScopeID uuid.UUID, startDate, endDate *time.Time) (*rbac.Membership, error)
 	GetMembership(ctx context.Context, membershipID uuid.UUID) (*rbac.Membership, error)
+	SetMembershipPeriod(ctx context.Context, membershipID uuid.UUID, startDate, endDate *time.Time) (*rbac.Membership, error)
 	RemoveMembership(ctx context.Context, membershipID uuid.UUID) error
 }
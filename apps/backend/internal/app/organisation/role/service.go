@@ -2,7 +2,11 @@ package role
 
 import (
 	"context"
+	"time"
 
+	"github.com/SURF-Innovatie/MORIS/internal/app/audit"
+	"github.com/SURF-Innovatie/MORIS/internal/app/tx"
+	auditdomain "github.com/SURF-Innovatie/MORIS/internal/domain/audit"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation/rbac"
 	"github.com/google/uuid"
 )
@@ -17,19 +21,22 @@ type Service interface {
 	CreateScope(ctx context.Context, roleKey string, rootNodeID uuid.UUID) (*rbac.RoleScope, error)
 	GetScope(ctx context.Context, id uuid.UUID) (*rbac.RoleScope, error)
 
-	AddMembership(ctx context.Context, personID uuid.UUID, roleScopeID uuid.UUID) (*rbac.Membership, error)
+	AddMembership(ctx context.Context, personID uuid.UUID, roleScopeID uuid.UUID, startDate, endDate *time.Time) (*rbac.Membership, error)
 	GetMembership(ctx context.Context, membershipID uuid.UUID) (*rbac.Membership, error)
+	SetMembershipPeriod(ctx context.Context, membershipID uuid.UUID, startDate, endDate *time.Time) (*rbac.Membership, error)
 	RemoveMembership(ctx context.Context, membershipID uuid.UUID) error
 
 	EnsureDefaultRoles(ctx context.Context) error
 }
 
 type service struct {
-	repo repository
+	repo  repository
+	audit audit.Service
+	tx    tx.Manager
 }
 
-func NewService(repo repository) Service {
-	return &service{repo: repo}
+func NewService(repo repository, auditSvc audit.Service, tx tx.Manager) Service {
+	return &service{repo: repo, audit: auditSvc, tx: tx}
 }
 
 func (s *service) EnsureDefaultRoles(ctx context.Context) error {
@@ -41,13 +48,29 @@ func (s *service) ListRoles(ctx context.Context, orgID *uuid.UUID) ([]*rbac.Orga
 }
 
 func (s *service) CreateRole(ctx context.Context, orgID uuid.UUID, key, displayName string, permissions []rbac.Permission) (*rbac.OrganisationRole, error) {
-	// TODO: Add logic to create RoleScope automatically if needed, or leave it to handler?
-	role, err := s.repo.CreateRole(ctx, orgID, key, displayName, permissions)
+	var role *rbac.OrganisationRole
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		role, err = s.repo.CreateRole(ctx, orgID, key, displayName, permissions)
+		if err != nil {
+			return err
+		}
+		if err := s.audit.Record(ctx, auditdomain.Change{
+			OrgNodeID:  role.OrganisationNodeID,
+			EntityType: auditdomain.EntityOrganisationRole,
+			EntityID:   role.ID,
+			Action:     auditdomain.ActionCreate,
+			After:      role,
+		}); err != nil {
+			return err
+		}
+		// Auto-create scope for this role on the same org
+		_, err = s.createScope(ctx, key, orgID)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
-	// Auto-create scope for this role on the same org
-	_, err = s.repo.CreateScope(ctx, key, orgID)
 	return role, nil
 }
 
@@ -56,31 +79,170 @@ func (s *service) GetRole(ctx context.Context, roleID uuid.UUID) (*rbac.Organisa
 }
 
 func (s *service) UpdateRole(ctx context.Context, roleID uuid.UUID, displayName string, permissions []rbac.Permission) (*rbac.OrganisationRole, error) {
-	return s.repo.UpdateRole(ctx, roleID, displayName, permissions)
+	var updated *rbac.OrganisationRole
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetRole(ctx, roleID)
+		if err != nil {
+			return err
+		}
+		updated, err = s.repo.UpdateRole(ctx, roleID, displayName, permissions)
+		if err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, auditdomain.Change{
+			OrgNodeID:  before.OrganisationNodeID,
+			EntityType: auditdomain.EntityOrganisationRole,
+			EntityID:   roleID,
+			Action:     auditdomain.ActionUpdate,
+			Before:     before,
+			After:      updated,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
 }
 
 func (s *service) DeleteRole(ctx context.Context, roleID uuid.UUID) error {
 	// TODO: Check if role has active memberships?
 	// The plan said "Block if used".
 	// Implementation should be in Repo or here.
-	return s.repo.DeleteRole(ctx, roleID)
+	return s.tx.WithTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetRole(ctx, roleID)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.DeleteRole(ctx, roleID); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, auditdomain.Change{
+			OrgNodeID:  before.OrganisationNodeID,
+			EntityType: auditdomain.EntityOrganisationRole,
+			EntityID:   roleID,
+			Action:     auditdomain.ActionDelete,
+			Before:     before,
+		})
+	})
 }
 
 func (s *service) CreateScope(ctx context.Context, roleKey string, rootNodeID uuid.UUID) (*rbac.RoleScope, error) {
-	return s.repo.CreateScope(ctx, roleKey, rootNodeID)
+	var scope *rbac.RoleScope
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		scope, err = s.createScope(ctx, roleKey, rootNodeID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return scope, nil
+}
+
+// createScope records the scope only when it is new; the repository returns an existing scope as is
+func (s *service) createScope(ctx context.Context, roleKey string, rootNodeID uuid.UUID) (*rbac.RoleScope, error) {
+	existing, err := s.repo.FindScope(ctx, roleKey, rootNodeID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, nil
+	}
+	scope, err := s.repo.CreateScope(ctx, roleKey, rootNodeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.audit.Record(ctx, auditdomain.Change{
+		OrgNodeID:  scope.RootNodeID,
+		EntityType: auditdomain.EntityRoleScope,
+		EntityID:   scope.ID,
+		Action:     auditdomain.ActionCreate,
+		After:      scope,
+	}); err != nil {
+		return nil, err
+	}
+	return scope, nil
 }
 
 func (s *service) GetScope(ctx context.Context, id uuid.UUID) (*rbac.RoleScope, error) {
 	return s.repo.GetScope(ctx, id)
 }
 
-func (s *service) AddMembership(ctx context.Context, personID uuid.UUID, roleScopeID uuid.UUID) (*rbac.Membership, error) {
-	return s.repo.AddMembership(ctx, personID, roleScopeID)
+func (s *service) AddMembership(ctx context.Context, personID uuid.UUID, roleScopeID uuid.UUID, startDate, endDate *time.Time) (*rbac.Membership, error) {
+	if err := rbac.ValidateMembershipPeriod(startDate, endDate); err != nil {
+		return nil, err
+	}
+	var m *rbac.Membership
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		m, err = s.repo.AddMembership(ctx, personID, roleScopeID, startDate, endDate)
+		if err != nil {
+			return err
+		}
+		return s.recordMembership(ctx, auditdomain.ActionCreate, m.RoleScopeID, m.ID, nil, m)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
 }
 
 func (s *service) GetMembership(ctx context.Context, membershipID uuid.UUID) (*rbac.Membership, error) {
 	return s.repo.GetMembership(ctx, membershipID)
 }
+
+func (s *service) SetMembershipPeriod(ctx context.Context, membershipID uuid.UUID, startDate, endDate *time.Time) (*rbac.Membership, error) {
+	if err := rbac.ValidateMembershipPeriod(startDate, endDate); err != nil {
+		return nil, err
+	}
+	var updated *rbac.Membership
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetMembership(ctx, membershipID)
+		if err != nil {
+			return err
+		}
+		updated, err = s.repo.SetMembershipPeriod(ctx, membershipID, startDate, endDate)
+		if err != nil {
+			return err
+		}
+		return s.recordMembership(ctx, auditdomain.ActionUpdate, before.RoleScopeID, membershipID, before, updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
 func (s *service) RemoveMembership(ctx context.Context, membershipID uuid.UUID) error {
-	return s.repo.RemoveMembership(ctx, membershipID)
+	return s.tx.WithTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetMembership(ctx, membershipID)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.RemoveMembership(ctx, membershipID); err != nil {
+			return err
+		}
+		return s.recordMembership(ctx, auditdomain.ActionDelete, before.RoleScopeID, membershipID, before, nil)
+	})
+}
+
+// recordMembership files a membership change under the root node of its role scope
+func (s *service) recordMembership(ctx context.Context, action auditdomain.Action, roleScopeID, membershipID uuid.UUID, before, after *rbac.Membership) error {
+	scope, err := s.repo.GetScope(ctx, roleScopeID)
+	if err != nil {
+		return err
+	}
+	c := auditdomain.Change{
+		OrgNodeID:  scope.RootNodeID,
+		EntityType: auditdomain.EntityOrganisationMembership,
+		EntityID:   membershipID,
+		Action:     action,
+	}
+	if before != nil {
+		c.Before = before
+	}
+	if after != nil {
+		c.After = after
+	}
+	return s.audit.Record(ctx, c)
 }
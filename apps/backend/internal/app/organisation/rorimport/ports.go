@@ -0,0 +1,21 @@
+package rorimport
+
+import (
+	"context"
+
+	"github.com/SURF-Innovatie/MORIS/external/ror"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation"
+	"github.com/google/uuid"
+)
+
+// RORClient fetches organisations from the Research Organization Registry
+type RORClient interface {
+	// GetOrganization returns nil when the registry does not know the ID
+	GetOrganization(ctx context.Context, id string) (*ror.Organization, error)
+}
+
+type repository interface {
+	// FindByRORIDs returns the nodes whose ROR ID is one of ids, whether stored as URL or as bare ID
+	FindByRORIDs(ctx context.Context, ids []string) ([]organisation.OrganisationNode, error)
+	SetRORStatus(ctx context.Context, nodeID uuid.UUID, status string) error
+}
@@ -0,0 +1,285 @@
+package rorimport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/SURF-Innovatie/MORIS/external/ror"
+	orgsvc "github.com/SURF-Innovatie/MORIS/internal/app/organisation"
+	"github.com/SURF-Innovatie/MORIS/internal/app/tx"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// maxImportSize bounds the number of organisations fetched for one import
+const maxImportSize = 500
+
+// ErrRORUnavailable wraps failures to reach the registry
+var ErrRORUnavailable = errors.New("ror unavailable")
+
+// ImportOptions configures a ROR import
+type ImportOptions struct {
+	// ParentID places the imported organisation under an existing node. Without it the organisation
+	// goes under the node of its ROR parent if there is one, keeps its place if it was imported
+	// before, or becomes a root.
+	ParentID *uuid.UUID
+	// DryRun reports what would change without changing anything
+	DryRun bool
+}
+
+// Service imports an organisation and its descendants from ROR into the organisation tree.
+// Nodes are matched on their ROR ID, so running an import again renames, re-parents and updates
+// the status of the nodes it created before instead of creating them again.
+type Service interface {
+	Import(ctx context.Context, rorID string, opts ImportOptions) (*organisation.RORImportReport, error)
+}
+
+type service struct {
+	repo   repository
+	client RORClient
+	orgSvc orgsvc.Service
+	tx     tx.Manager
+}
+
+func NewService(repo repository, client RORClient, orgSvc orgsvc.Service, tx tx.Manager) Service {
+	return &service{repo: repo, client: client, orgSvc: orgSvc, tx: tx}
+}
+
+// fetched is an organisation of the hierarchy with the organisation that lists it as its child
+type fetched struct {
+	org         ror.Organization
+	rorID       string
+	parentRorID *string
+}
+
+func (s *service) Import(ctx context.Context, rorID string, opts ImportOptions) (*organisation.RORImportReport, error) {
+	rootID, err := organisation.NormalizeRORID(rorID)
+	if err != nil {
+		return nil, err
+	}
+	if opts.ParentID != nil {
+		if _, err := s.orgSvc.Get(ctx, *opts.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	orgs, err := s.walk(ctx, rootID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Look up the ROR parents of the imported organisation as well, to attach it to one of them
+	rootParents := normalizeAll(orgs[0].org.RelatedIds(ror.OrganizationRelationshipTypeParent))
+	lookup := append(lo.Map(orgs, func(f fetched, _ int) string { return f.rorID }), rootParents...)
+	nodes, err := s.repo.FindByRORIDs(ctx, withBareIDs(lookup))
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]organisation.OrganisationNode, len(nodes))
+	for _, n := range nodes {
+		if id, err := organisation.NormalizeRORID(lo.FromPtr(n.RorID)); err == nil {
+			if _, dup := existing[id]; !dup {
+				existing[id] = n
+			}
+		}
+	}
+
+	report := &organisation.RORImportReport{RorID: rootID, DryRun: opts.DryRun}
+	created := map[uuid.UUID]bool{}
+	err = tx.WithDryRun(ctx, s.tx, opts.DryRun, func(ctx context.Context) error {
+		nodeIDs := map[string]uuid.UUID{}
+		for i, f := range orgs {
+			var parentID *uuid.UUID
+			if i == 0 {
+				parentID = s.rootParent(opts, existing, rootParents, rootID)
+			} else if id, ok := nodeIDs[*f.parentRorID]; ok {
+				parentID = &id
+			} else {
+				// The parent was skipped, so its children are not imported either
+				continue
+			}
+
+			item, err := s.importOne(ctx, f, parentID, existing)
+			if err != nil {
+				return fmt.Errorf("importing %s: %w", f.rorID, err)
+			}
+			if item.NodeID != nil {
+				nodeIDs[f.rorID] = *item.NodeID
+			}
+			if item.Action == organisation.RORImportCreate {
+				created[*item.NodeID] = true
+			}
+			report.Items = append(report.Items, item)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, item := range report.Items {
+		switch item.Action {
+		case organisation.RORImportCreate:
+			report.Created++
+		case organisation.RORImportUpdate:
+			report.Updated++
+		case organisation.RORImportUnchanged:
+			report.Unchanged++
+		case organisation.RORImportSkip:
+			report.Skipped++
+		}
+		// Nodes created by a dry run were rolled back, so their IDs mean nothing
+		if opts.DryRun {
+			if item.NodeID != nil && created[*item.NodeID] {
+				report.Items[i].NodeID = nil
+			}
+			if item.ParentID != nil && created[*item.ParentID] {
+				report.Items[i].ParentID = nil
+			}
+		}
+	}
+	return report, nil
+}
+
+// walk fetches the organisation and, breadth first, the organisations it lists as children.
+// Withdrawn organisations that were never imported are not descended into.
+func (s *service) walk(ctx context.Context, rootID string) ([]fetched, error) {
+	queue := []fetched{{rorID: rootID}}
+	seen := map[string]bool{rootID: true}
+	var out []fetched
+
+	for len(queue) > 0 {
+		f := queue[0]
+		queue = queue[1:]
+		if len(out) == maxImportSize {
+			return nil, fmt.Errorf("%w: more than %d organisations", organisation.ErrRORImportTooLarge, maxImportSize)
+		}
+
+		org, err := s.client.GetOrganization(ctx, organisation.BareRORID(f.rorID))
+		if err != nil {
+			return nil, fmt.Errorf("%w: fetching %s: %v", ErrRORUnavailable, f.rorID, err)
+		}
+		if org == nil {
+			if f.parentRorID == nil {
+				return nil, fmt.Errorf("%w: %s", organisation.ErrRORNotFound, f.rorID)
+			}
+			// A dangling child link; nothing to import
+			continue
+		}
+		f.org = *org
+		out = append(out, f)
+
+		for _, childID := range normalizeAll(org.RelatedIds(ror.OrganizationRelationshipTypeChild)) {
+			if seen[childID] {
+				continue
+			}
+			seen[childID] = true
+			queue = append(queue, fetched{rorID: childID, parentRorID: lo.ToPtr(f.rorID)})
+		}
+	}
+	return out, nil
+}
+
+func (s *service) rootParent(opts ImportOptions, existing map[string]organisation.OrganisationNode, rorParents []string, rootID string) *uuid.UUID {
+	if opts.ParentID != nil {
+		return opts.ParentID
+	}
+	for _, id := range rorParents {
+		if n, ok := existing[id]; ok {
+			return &n.ID
+		}
+	}
+	if n, ok := existing[rootID]; ok {
+		return n.ParentID
+	}
+	return nil
+}
+
+func (s *service) importOne(ctx context.Context, f fetched, parentID *uuid.UUID, existing map[string]organisation.OrganisationNode) (organisation.RORImportItem, error) {
+	name := f.org.DisplayName()
+	status := string(f.org.OrganizationStatus)
+	item := organisation.RORImportItem{
+		RorID:       f.rorID,
+		Name:        name,
+		Status:      status,
+		ParentRorID: f.parentRorID,
+		ParentID:    parentID,
+	}
+
+	node, ok := existing[f.rorID]
+	if !ok {
+		if f.org.OrganizationStatus == ror.OrganizationStatusWithdrawn {
+			item.Action = organisation.RORImportSkip
+			item.ParentID = nil
+			return item, nil
+		}
+
+		var (
+			createdNode *organisation.OrganisationNode
+			err         error
+		)
+		if parentID == nil {
+			createdNode, err = s.orgSvc.CreateRoot(ctx, name, &f.rorID, nil, nil)
+		} else {
+			createdNode, err = s.orgSvc.CreateChild(ctx, *parentID, name, &f.rorID, nil, nil)
+		}
+		if err != nil {
+			return item, err
+		}
+		if err := s.repo.SetRORStatus(ctx, createdNode.ID, status); err != nil {
+			return item, err
+		}
+		item.Action = organisation.RORImportCreate
+		item.NodeID = &createdNode.ID
+		return item, nil
+	}
+
+	item.NodeID = &node.ID
+	item.PreviousName = &node.Name
+	item.PreviousParentID = node.ParentID
+	item.PreviousStatus = node.RorStatus
+
+	if node.Name != name {
+		item.Changes = append(item.Changes, organisation.RORImportChangeName)
+	}
+	if !node.HasParent(parentID) {
+		item.Changes = append(item.Changes, organisation.RORImportChangeParent)
+	}
+	if lo.FromPtr(node.RorStatus) != status {
+		item.Changes = append(item.Changes, organisation.RORImportChangeStatus)
+	}
+
+	if slices.Contains(item.Changes, organisation.RORImportChangeName) || slices.Contains(item.Changes, organisation.RORImportChangeParent) {
+		// Storing the normalized ROR ID as well keeps later lookups exact
+		if _, err := s.orgSvc.Update(ctx, node.ID, name, parentID, &f.rorID, node.Description, node.AvatarURL); err != nil {
+			return item, err
+		}
+	}
+	if slices.Contains(item.Changes, organisation.RORImportChangeStatus) {
+		if err := s.repo.SetRORStatus(ctx, node.ID, status); err != nil {
+			return item, err
+		}
+	}
+
+	item.Action = lo.Ternary(len(item.Changes) > 0, organisation.RORImportUpdate, organisation.RORImportUnchanged)
+	return item, nil
+}
+
+// run executes fn in a transaction that is rolled back for a dry run
+// normalizeAll normalizes ROR IDs, dropping the ones that are malformed
+func normalizeAll(ids []string) []string {
+	return lo.FilterMap(ids, func(id string, _ int) (string, bool) {
+		n, err := organisation.NormalizeRORID(id)
+		return n, err == nil
+	})
+}
+
+// withBareIDs adds the bare form of each ROR ID, since nodes created by hand may store either
+func withBareIDs(ids []string) []string {
+	return lo.Uniq(lo.FlatMap(ids, func(id string, _ int) []string {
+		return []string{id, organisation.BareRORID(id)}
+	}))
+}
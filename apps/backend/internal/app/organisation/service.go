@@ -27,6 +27,9 @@ type Service interface {
 	Search(ctx context.Context, query string) ([]organisation.OrganisationNode, error)
 	SearchForProjectCreation(ctx context.Context, query string, actorID uuid.UUID) ([]organisation.OrganisationNode, error)
 	UpdateMemberCustomFields(ctx context.Context, orgID uuid.UUID, personID uuid.UUID, values map[string]any) error
+
+	// Move re-parents a node (nil makes it a root) and rebuilds the closure rows of its subtree
+	Move(ctx context.Context, id uuid.UUID, newParentID *uuid.UUID) (*organisation.SubtreeMove, error)
 }
 
 type service struct {
@@ -168,86 +171,109 @@ func (s *service) Update(ctx context.Context, id uuid.UUID, name string, parentI
 			return err
 		}
 
-		// If parent didn't change -> done
-		oldParentID := cur.ParentID
-		newParentID := parentID
-
-		same :=
-			(oldParentID == nil && newParentID == nil) ||
-				(oldParentID != nil && newParentID != nil && *oldParentID == *newParentID)
-		if same {
-			out = row
-			return nil
+		// Rebuild the closure rows when the node moved
+		if !cur.HasParent(parentID) {
+			if _, err := s.rebuildClosures(ctx, id, parentID); err != nil {
+				return err
+			}
 		}
 
-		// subtree: closures where ancestor=id
-		subRows, err := s.repo.ListClosuresByAncestor(ctx, id)
+		out = row
+		return nil
+	})
+	return out, err
+}
+
+func (s *service) Move(ctx context.Context, id uuid.UUID, newParentID *uuid.UUID) (*organisation.SubtreeMove, error) {
+	var out *organisation.SubtreeMove
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		cur, err := s.repo.GetNode(ctx, id)
 		if err != nil {
 			return err
 		}
-		subDepth := lo.Associate(subRows, func(r readmodels.OrganisationNodeClosure) (uuid.UUID, int) {
-			return r.DescendantID, r.Depth
-		})
-		subIDs := lo.Keys(subDepth)
-
-		// cycle check: cannot move under own subtree
-		if newParentID != nil {
-			if _, ok := subDepth[*newParentID]; ok {
-				return fmt.Errorf("cannot move node under its own subtree")
-			}
+		if newParentID != nil && *newParentID == id {
+			return fmt.Errorf("%w: node cannot be its own parent", organisation.ErrInvalidRestructure)
+		}
+		if cur.HasParent(newParentID) {
+			out = &organisation.SubtreeMove{NodeIDs: []uuid.UUID{id}}
+			return nil
 		}
 
-		// old external ancestors: closures where descendant=id AND ancestor NOT IN subtree
-		oldAncRows, err := s.repo.ListClosuresByDescendant(ctx, id)
-		if err != nil {
+		if err := s.repo.SetParent(ctx, id, newParentID); err != nil {
 			return err
 		}
-		subSet := lo.SliceToMap(subIDs, func(sid uuid.UUID) (uuid.UUID, struct{}) {
-			return sid, struct{}{}
-		})
-		oldAncIDs := lo.FilterMap(oldAncRows, func(a readmodels.OrganisationNodeClosure, _ int) (uuid.UUID, bool) {
-			if _, inSub := subSet[a.AncestorID]; !inSub {
-				return a.AncestorID, true
-			}
-			return uuid.Nil, false
-		})
+		out, err = s.rebuildClosures(ctx, id, newParentID)
+		return err
+	})
+	return out, err
+}
 
-		// delete: (old external ancestors) -> (subtree descendants)
-		if len(oldAncIDs) > 0 && len(subIDs) > 0 {
-			if err := s.repo.DeleteClosures(ctx, oldAncIDs, subIDs); err != nil {
-				return err
-			}
-		}
+// rebuildClosures replaces the closure rows that connect the subtree of id to its old ancestors
+// with rows to the ancestors of newParentID. It must run inside a transaction.
+func (s *service) rebuildClosures(ctx context.Context, id uuid.UUID, newParentID *uuid.UUID) (*organisation.SubtreeMove, error) {
+	// subtree: closures where ancestor=id
+	subRows, err := s.repo.ListClosuresByAncestor(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	subDepth := lo.Associate(subRows, func(r readmodels.OrganisationNodeClosure) (uuid.UUID, int) {
+		return r.DescendantID, r.Depth
+	})
+	subIDs := lo.Keys(subDepth)
+	move := &organisation.SubtreeMove{NodeIDs: subIDs}
 
-		// new root? done
-		if newParentID == nil {
-			out = row
-			return nil
+	// cycle check: cannot move under own subtree
+	if newParentID != nil {
+		if _, ok := subDepth[*newParentID]; ok {
+			return nil, fmt.Errorf("%w: cannot move node under its own subtree", organisation.ErrInvalidRestructure)
 		}
+	}
 
-		// new external ancestors: closures where descendant=newParent
-		newAncRows, err := s.repo.ListClosuresByDescendant(ctx, *newParentID)
-		if err != nil {
-			return err
+	// old external ancestors: closures where descendant=id AND ancestor NOT IN subtree
+	oldAncRows, err := s.repo.ListClosuresByDescendant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	oldAncIDs := lo.FilterMap(oldAncRows, func(a readmodels.OrganisationNodeClosure, _ int) (uuid.UUID, bool) {
+		if _, inSub := subDepth[a.AncestorID]; !inSub {
+			return a.AncestorID, true
 		}
+		return uuid.Nil, false
+	})
 
-		bulk := lo.FlatMap(newAncRows, func(na readmodels.OrganisationNodeClosure, _ int) []readmodels.OrganisationNodeClosure {
-			return lo.Map(subIDs, func(descID uuid.UUID, _ int) readmodels.OrganisationNodeClosure {
-				return readmodels.OrganisationNodeClosure{
-					AncestorID:   na.AncestorID,
-					DescendantID: descID,
-					Depth:        na.Depth + 1 + subDepth[descID],
-				}
-			})
-		})
-		if len(bulk) > 0 {
-			if err := s.repo.CreateClosuresBulk(ctx, bulk); err != nil {
-				return err
-			}
+	// delete: (old external ancestors) -> (subtree descendants)
+	if len(oldAncIDs) > 0 && len(subIDs) > 0 {
+		if err := s.repo.DeleteClosures(ctx, oldAncIDs, subIDs); err != nil {
+			return nil, err
 		}
+		move.ClosureRowsRemoved = len(oldAncIDs) * len(subIDs)
+	}
 
-		out = row
-		return nil
+	// new root? done
+	if newParentID == nil {
+		return move, nil
+	}
+
+	// new external ancestors: closures where descendant=newParent
+	newAncRows, err := s.repo.ListClosuresByDescendant(ctx, *newParentID)
+	if err != nil {
+		return nil, err
+	}
+
+	bulk := lo.FlatMap(newAncRows, func(na readmodels.OrganisationNodeClosure, _ int) []readmodels.OrganisationNodeClosure {
+		return lo.Map(subIDs, func(descID uuid.UUID, _ int) readmodels.OrganisationNodeClosure {
+			return readmodels.OrganisationNodeClosure{
+				AncestorID:   na.AncestorID,
+				DescendantID: descID,
+				Depth:        na.Depth + 1 + subDepth[descID],
+			}
+		})
 	})
-	return out, err
+	if len(bulk) > 0 {
+		if err := s.repo.CreateClosuresBulk(ctx, bulk); err != nil {
+			return nil, err
+		}
+	}
+	move.ClosureRowsAdded = len(bulk)
+	return move, nil
 }
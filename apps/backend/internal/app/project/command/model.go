@@ -2,11 +2,17 @@ package command
 
 import (
 	"encoding/json"
+	"errors"
 
 	"github.com/SURF-Innovatie/MORIS/internal/domain/project/events"
 	"github.com/google/uuid"
 )
 
+var (
+	ErrUnknownEventType = errors.New("unknown_event_type")
+	ErrProjectNotFound  = errors.New("project_not_found")
+)
+
 type AvailableEvent struct {
 	Type          string
 	FriendlyName  string
@@ -21,3 +27,22 @@ type ExecuteEventRequest struct {
 	Status    events.Status
 	Input     json.RawMessage
 }
+
+// EventExecutionExplanation shows whether a person's project roles allow an event type
+type EventExecutionExplanation struct {
+	ProjectID uuid.UUID
+	EventType string
+	Member    bool
+	Roles     []ProjectRoleDecision
+	Allowed   bool
+	Reason    string
+}
+
+// ProjectRoleDecision is the AllowedEventTypes check of one of the person's project roles
+type ProjectRoleDecision struct {
+	RoleID            uuid.UUID
+	Key               string
+	Name              string
+	AllowedEventTypes []string
+	Allowed           bool
+}
@@ -4,17 +4,21 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	appauth "github.com/SURF-Innovatie/MORIS/internal/app/auth"
 	"github.com/SURF-Innovatie/MORIS/internal/app/commandbus"
 	"github.com/SURF-Innovatie/MORIS/internal/app/event"
 	"github.com/SURF-Innovatie/MORIS/internal/app/eventpolicy"
 	"github.com/SURF-Innovatie/MORIS/internal/app/organisation"
+	organisationhierarchy "github.com/SURF-Innovatie/MORIS/internal/app/organisation/hierarchy"
 	rbacsvc "github.com/SURF-Innovatie/MORIS/internal/app/organisation/rbac"
 	"github.com/SURF-Innovatie/MORIS/internal/app/project/role"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation/rbac"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/policy"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/project"
 	events2 "github.com/SURF-Innovatie/MORIS/internal/domain/project/events"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/project/projection"
 	role2 "github.com/SURF-Innovatie/MORIS/internal/domain/project/role"
 	"github.com/SURF-Innovatie/MORIS/internal/infra/cache"
 	"github.com/google/uuid"
@@ -25,6 +29,10 @@ import (
 type Service interface {
 	ListAvailableEvents(ctx context.Context, projectID *uuid.UUID) ([]AvailableEvent, error)
 	ExecuteEvent(ctx context.Context, req ExecuteEventRequest) (*project.Project, error)
+	// ExplainEventExecution shows how the role check of ExecuteEvent decides for the person. Only projects
+	// owned by the node or one of its descendants are explained; others are reported as not found.
+	// Sysadmins bypass that check, which the caller reports separately.
+	ExplainEventExecution(ctx context.Context, nodeID, projectID, personID uuid.UUID, eventType string) (*EventExecutionExplanation, error)
 }
 
 type service struct {
@@ -35,8 +43,10 @@ type service struct {
 	entClient   EntClientProvider
 	roleSvc     role.Service
 	evaluator   eventpolicy.Evaluator
+	policySvc   eventpolicy.Service
 	orgSvc      organisation.Service
 	rbacSvc     rbacsvc.Service
+	hierarchy   organisationhierarchy.Service
 }
 
 func NewService(
@@ -46,8 +56,10 @@ func NewService(
 	entClient EntClientProvider,
 	roleSvc role.Service,
 	evaluator eventpolicy.Evaluator,
+	policySvc eventpolicy.Service,
 	orgSvc organisation.Service,
 	rbacSvc rbacsvc.Service,
+	hierarchy organisationhierarchy.Service,
 	evtPub event.Publisher,
 ) Service {
 	return &service{
@@ -57,8 +69,10 @@ func NewService(
 		entClient:   entClient,
 		roleSvc:     roleSvc,
 		evaluator:   evaluator,
+		policySvc:   policySvc,
 		orgSvc:      orgSvc,
 		rbacSvc:     rbacSvc,
+		hierarchy:   hierarchy,
 		exec: commandbus.NewExecutor[project.Project](
 			evtSvc,
 			evtPub,
@@ -77,15 +91,15 @@ func (s *service) ListAvailableEvents(ctx context.Context, projectID *uuid.UUID)
 	metas := events2.GetAllMetas()
 
 	// If projectID provided, get user's role and filter based on allowed events
-	var userRole *role2.ProjectRole
-	if projectID != nil && *projectID != uuid.Nil {
-		userRole = s.getUserProjectRole(ctx, *projectID, u.PersonID)
+	var proj *project.Project
+	if projectID != nil && *projectID != uuid.Nil && !u.IsSysAdmin {
+		proj, _ = s.cache.GetProject(ctx, *projectID)
 	}
 
 	out := lo.Map(metas, func(m events2.EventMeta, _ int) AvailableEvent {
 		allowed := true
-		if userRole != nil {
-			allowed = userRole.CanUseEventType(m.Type)
+		if proj != nil {
+			allowed = s.memberCanUseEventType(ctx, proj, u.PersonID, m.Type)
 		}
 		return AvailableEvent{
 			Type:          m.Type,
@@ -153,6 +167,18 @@ func (s *service) ExecuteEvent(ctx context.Context, req ExecuteEventRequest) (*p
 			return nil, nil
 		}
 
+		// API tokens limited to organisation nodes only act on the projects those nodes own
+		owningOrgNodeID := uuid.Nil
+		if cur != nil {
+			owningOrgNodeID = cur.OwningOrgNodeID
+		}
+		if started, ok := e.(*events2.ProjectStarted); ok {
+			owningOrgNodeID = started.OwningOrgNodeID
+		}
+		if !u.CanAccessNode(owningOrgNodeID) {
+			return nil, fmt.Errorf("%w: the token is not allowed to act on this project", rbac.ErrForbidden)
+		}
+
 		// Auto-role assignment for ProjectStarted
 		if e.Type() == events2.ProjectStartedType {
 			if started, ok := e.(*events2.ProjectStarted); ok {
@@ -174,18 +200,21 @@ func (s *service) ExecuteEvent(ctx context.Context, req ExecuteEventRequest) (*p
 			}
 		}
 
-		// Check role-based permissions (EBAC)
-		if cur != nil {
-			userRole := s.getUserProjectRole(ctx, req.ProjectID, u.PersonID)
-			if userRole != nil && !userRole.CanUseEventType(req.Type) && !u.IsSysAdmin {
-				return nil, fmt.Errorf("your role does not allow executing %s events", req.Type)
-			}
+		// Check role-based permissions (EBAC). Being able to read a project does not allow changing it:
+		// only members whose role allows the event type, and sysadmins, may execute events.
+		if cur != nil && !u.IsSysAdmin && !s.memberCanUseEventType(ctx, cur, u.PersonID, req.Type) {
+			return nil, fmt.Errorf("%w: your role does not allow executing %s events", rbac.ErrForbidden, req.Type)
 		}
 
 		if !meta.IsAllowed(ctx, e, cli) {
 			return nil, fmt.Errorf("not allowed to execute %s", req.Type)
 		}
 
+		// Project policies may not shadow a locked policy of the owning organisation or its ancestors
+		if err := s.checkProjectPolicy(ctx, e, cur); err != nil {
+			return nil, err
+		}
+
 		// Check if any policy requires approval
 		needsApproval, err := s.evaluator.CheckApprovalRequired(ctx, e, cur)
 		if err != nil {
@@ -219,26 +248,94 @@ func (s *service) ExecuteEvent(ctx context.Context, req ExecuteEventRequest) (*p
 	return proj, nil
 }
 
-// getUserProjectRole returns the user's role on the project, if any
-func (s *service) getUserProjectRole(ctx context.Context, projectID, personID uuid.UUID) *role2.ProjectRole {
-	// Get project from cache to find user's role
-	proj, err := s.cache.GetProject(ctx, projectID)
-	if err != nil || proj == nil {
+// checkProjectPolicy checks the policy added or updated by a project event against the locked
+// policies of the project's owning organisation
+func (s *service) checkProjectPolicy(ctx context.Context, e events2.Event, cur *project.Project) error {
+	var p policy.EventPolicy
+	switch evt := e.(type) {
+	case *events2.EventPolicyAdded:
+		p = evt.ToPolicy()
+	case *events2.EventPolicyUpdated:
+		p = evt.ToPolicy()
+	default:
 		return nil
 	}
+	if cur == nil {
+		return fmt.Errorf("project %s not found", e.AggregateID())
+	}
+	return s.policySvc.CheckProjectPolicy(ctx, p, cur.OwningOrgNodeID)
+}
 
-	// Find user's membership and role
-	m, ok := lo.Find(proj.Members, func(m project.Member) bool {
-		return m.PersonID == personID
-	})
-	if ok {
-		// Get the role details
-		role, err := s.roleSvc.GetByID(ctx, m.ProjectRoleID)
-		if err == nil {
-			return role
+func (s *service) ExplainEventExecution(ctx context.Context, nodeID, projectID, personID uuid.UUID, eventType string) (*EventExecutionExplanation, error) {
+	if _, ok := events2.GetDecider(eventType); !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEventType, eventType)
+	}
+
+	history, _, err := s.evtSvc.Load(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) == 0 {
+		return nil, ErrProjectNotFound
+	}
+	proj := projection.Reduce(projectID, history)
+	if proj.OwningOrgNodeID != nodeID {
+		owned, err := s.hierarchy.IsAncestor(ctx, nodeID, proj.OwningOrgNodeID)
+		if err != nil {
+			return nil, err
+		}
+		if !owned {
+			return nil, ErrProjectNotFound
+		}
+	}
+
+	out := &EventExecutionExplanation{ProjectID: projectID, EventType: eventType, Roles: []ProjectRoleDecision{}}
+	now := time.Now()
+	for _, m := range proj.Members {
+		if m.PersonID != personID || !m.ActiveAt(now) {
+			continue
+		}
+		out.Member = true
+		r, err := s.roleSvc.GetByID(ctx, m.ProjectRoleID)
+		if err != nil {
+			return nil, err
+		}
+		allowed := r.CanUseEventType(eventType)
+		out.Roles = append(out.Roles, ProjectRoleDecision{
+			RoleID:            r.ID,
+			Key:               r.Key,
+			Name:              r.Name,
+			AllowedEventTypes: r.AllowedEventTypes,
+			Allowed:           allowed,
+		})
+		out.Allowed = out.Allowed || allowed
+	}
+
+	switch {
+	case !out.Member:
+		out.Reason = "denied: the person is not a current member of the project"
+	case out.Allowed:
+		out.Reason = fmt.Sprintf("allowed: a project role lists %s in its allowed event types", eventType)
+	default:
+		out.Reason = fmt.Sprintf("denied: none of the person's project roles lists %s in its allowed event types", eventType)
+	}
+	return out, nil
+}
+
+// memberCanUseEventType reports whether any of the person's current roles on the project allows the
+// event type. Non-members, such as organisation viewers, cannot use any event type.
+func (s *service) memberCanUseEventType(ctx context.Context, proj *project.Project, personID uuid.UUID, eventType string) bool {
+	now := time.Now()
+	for _, m := range proj.Members {
+		if m.PersonID != personID || !m.ActiveAt(now) {
+			continue
+		}
+		r, err := s.roleSvc.GetByID(ctx, m.ProjectRoleID)
+		if err == nil && r.CanUseEventType(eventType) {
+			return true
 		}
 	}
-	return nil
+	return false
 }
 
 func (s *service) findPermissiveRole(ctx context.Context, orgID uuid.UUID) (*role2.ProjectRole, error) {
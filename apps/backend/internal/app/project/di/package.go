@@ -1,6 +1,7 @@
 package di
 
 import (
+	"github.com/SURF-Innovatie/MORIS/internal/app/audit"
 	coreauth "github.com/SURF-Innovatie/MORIS/internal/app/auth"
 	"github.com/SURF-Innovatie/MORIS/internal/app/event"
 	"github.com/SURF-Innovatie/MORIS/internal/app/eventpolicy"
@@ -15,6 +16,7 @@ import (
 	"github.com/SURF-Innovatie/MORIS/internal/app/user"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/project/events/hydrator"
 	"github.com/SURF-Innovatie/MORIS/internal/infra/cache"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/persistence/enttx"
 	projectrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/project"
 	"github.com/samber/do/v2"
 )
@@ -32,7 +34,9 @@ func provideProjectRoleService(i do.Injector) (projectrole2.Service, error) {
 	repo := do.MustInvoke[projectrole2.Repository](i)
 	orgSvc := do.MustInvoke[organisation.Service](i)
 	orgHierarchySvc := do.MustInvoke[organisationhierarchy.Service](i)
-	return projectrole2.NewService(repo, orgSvc, orgHierarchySvc), nil
+	auditSvc := do.MustInvoke[audit.Service](i)
+	txManager := do.MustInvoke[*enttx.Manager](i)
+	return projectrole2.NewService(repo, orgSvc, orgHierarchySvc, auditSvc, txManager), nil
 }
 
 func provideProjectLoader(i do.Injector) (*load.Loader, error) {
@@ -55,7 +59,8 @@ func provideProjectQueryService(i do.Injector) (queries.Service, error) {
 	curUser := do.MustInvoke[coreauth.CurrentUserProvider](i)
 	userSvc := do.MustInvoke[user.Service](i)
 	h := do.MustInvoke[*hydrator.Hydrator](i)
-	return queries.NewService(eventSvc, ldr, repo, roleRepo, curUser, userSvc, h), nil
+	rbacSvc := do.MustInvoke[organisationrbac.Service](i)
+	return queries.NewService(eventSvc, ldr, repo, roleRepo, curUser, userSvc, h, rbacSvc), nil
 }
 
 func provideProjectCommandService(i do.Injector) (command.Service, error) {
@@ -65,10 +70,12 @@ func provideProjectCommandService(i do.Injector) (command.Service, error) {
 	entProv := do.MustInvoke[command.EntClientProvider](i)
 	roleSvc := do.MustInvoke[projectrole2.Service](i)
 	evaluator := do.MustInvoke[eventpolicy.Evaluator](i)
+	policySvc := do.MustInvoke[eventpolicy.Service](i)
 	orgSvc := do.MustInvoke[organisation.Service](i)
 	rbacSvc := do.MustInvoke[organisationrbac.Service](i)
+	orgHierarchySvc := do.MustInvoke[organisationhierarchy.Service](i)
 	evtPub := do.MustInvoke[event.Publisher](i)
-	return command.NewService(eventSvc, pc, curUser, entProv, roleSvc, evaluator, orgSvc, rbacSvc, evtPub), nil
+	return command.NewService(eventSvc, pc, curUser, entProv, roleSvc, evaluator, policySvc, orgSvc, rbacSvc, orgHierarchySvc, evtPub), nil
 }
 
 func provideCacheWarmupService(i do.Injector) (cachewarmup.Service, error) {
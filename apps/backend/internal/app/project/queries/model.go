@@ -1,10 +1,15 @@
 package queries
 
 import (
+	"time"
+
 	"github.com/SURF-Innovatie/MORIS/internal/domain/affiliatedorganisation"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/product"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/project"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/project/role"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
 )
 
 type ProjectDetails struct {
@@ -14,3 +19,10 @@ type ProjectDetails struct {
 	Products                []product.Product
 	AffiliatedOrganisations []affiliatedorganisation.AffiliatedOrganisation
 }
+
+// IsActiveLead reports whether the person holds the project lead role on the project at t
+func (d *ProjectDetails) IsActiveLead(personID uuid.UUID, t time.Time) bool {
+	return lo.ContainsBy(d.Members, func(m project.MemberDetail) bool {
+		return m.Person.ID == personID && m.Role.Key == role.KeyLead && m.ActiveAt(t)
+	})
+}
@@ -0,0 +1,40 @@
+package queries_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/internal/app/project/queries"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/identity"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/project"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/project/role"
+	"github.com/google/uuid"
+)
+
+func TestIsActiveLead(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	past, future := now.AddDate(0, 0, -1), now.AddDate(0, 0, 1)
+	lead := role.ProjectRole{ID: uuid.New(), Key: role.KeyLead}
+	contributor := role.ProjectRole{ID: uuid.New(), Key: "contributor"}
+
+	leadID, formerLeadID, futureLeadID, contributorID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	details := queries.ProjectDetails{Members: []project.MemberDetail{
+		{Person: identity.Person{ID: leadID}, Role: lead, StartDate: &past},
+		{Person: identity.Person{ID: formerLeadID}, Role: lead, EndDate: &now},
+		{Person: identity.Person{ID: futureLeadID}, Role: lead, StartDate: &future},
+		{Person: identity.Person{ID: contributorID}, Role: contributor},
+	}}
+
+	cases := map[uuid.UUID]bool{
+		leadID:        true,
+		formerLeadID:  false,
+		futureLeadID:  false,
+		contributorID: false,
+		uuid.New():    false,
+	}
+	for personID, want := range cases {
+		if got := details.IsActiveLead(personID, now); got != want {
+			t.Errorf("%s: expected %v, got %v", personID, want, got)
+		}
+	}
+}
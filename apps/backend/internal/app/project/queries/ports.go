@@ -22,6 +22,9 @@ type ProjectReadRepository interface {
 	ProjectIDsForPerson(ctx context.Context, personID uuid.UUID) ([]uuid.UUID, error)
 
 	ProjectIDsStarted(ctx context.Context) ([]uuid.UUID, error)
+	// CandidateProjectIDs returns a superset of the projects readable by the person: those they were assigned to,
+	// those that were public and those that were owned by one of the nodes
+	CandidateProjectIDs(ctx context.Context, personID uuid.UUID, ownerNodeIDs []uuid.UUID) ([]uuid.UUID, error)
 	ListAncestors(ctx context.Context, orgID uuid.UUID) ([]uuid.UUID, error)
 }
 
@@ -3,14 +3,18 @@ package queries
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sort"
+	"time"
 
 	appauth "github.com/SURF-Innovatie/MORIS/internal/app/auth"
 	"github.com/SURF-Innovatie/MORIS/internal/app/event"
+	organisation_rbac "github.com/SURF-Innovatie/MORIS/internal/app/organisation/rbac"
 	"github.com/SURF-Innovatie/MORIS/internal/app/project/load"
 	"github.com/SURF-Innovatie/MORIS/internal/app/user"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/affiliatedorganisation"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/identity"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation/rbac"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/product"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/project"
 	events2 "github.com/SURF-Innovatie/MORIS/internal/domain/project/events"
@@ -25,6 +29,8 @@ var ErrNotFound = errors.New("project not found")
 
 type Service interface {
 	GetProject(ctx context.Context, id uuid.UUID) (*ProjectDetails, error)
+	// AuthorizeView returns an rbac.ErrForbidden error when the current user may not read the project
+	AuthorizeView(ctx context.Context, projectID uuid.UUID) error
 	GetAllProjects(ctx context.Context) ([]*ProjectDetails, error)
 	GetChangeLog(ctx context.Context, id uuid.UUID) ([]events2.DetailedEvent, error)
 	GetPendingEvents(ctx context.Context, projectID uuid.UUID) ([]events2.DetailedEvent, error)
@@ -45,6 +51,7 @@ type service struct {
 	roleRepo    ProjectRoleRepository
 	userSvc     user.Service
 	hydrator    *hydrator.Hydrator
+	rbac        organisation_rbac.Service
 }
 
 func NewService(
@@ -55,6 +62,7 @@ func NewService(
 	currentUser appauth.CurrentUserProvider,
 	userSvc user.Service,
 	h *hydrator.Hydrator,
+	rbacSvc organisation_rbac.Service,
 ) Service {
 	return &service{
 		eventSvc:    eventSvc,
@@ -64,11 +72,12 @@ func NewService(
 		currentUser: currentUser,
 		userSvc:     userSvc,
 		hydrator:    h,
+		rbac:        rbacSvc,
 	}
 }
 
 func (s *service) GetProject(ctx context.Context, id uuid.UUID) (*ProjectDetails, error) {
-	proj, err := s.loader.Load(ctx, id)
+	proj, err := s.loadVisible(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -76,19 +85,90 @@ func (s *service) GetProject(ctx context.Context, id uuid.UUID) (*ProjectDetails
 	return s.buildProjectDetails(ctx, proj)
 }
 
+// loadVisible loads a project and checks that the current user may read it
+func (s *service) loadVisible(ctx context.Context, id uuid.UUID) (*project.Project, error) {
+	u, err := s.currentUser.Current(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	proj, err := s.loader.Load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := s.canView(ctx, u, proj)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %s permission required", rbac.ErrForbidden, rbac.PermissionViewProjects)
+	}
+	return proj, nil
+}
+
+func (s *service) AuthorizeView(ctx context.Context, projectID uuid.UUID) error {
+	_, err := s.loadVisible(ctx, projectID)
+	return err
+}
+
+// canView reports whether the user may read the project. Sysadmins and current members always may;
+// everyone else depends on the project's visibility: any signed-in user for public projects,
+// holders of the view_projects permission on the owning organisation node or one of its
+// ancestors for organisation projects, and nobody for private projects. API tokens limited to
+// organisation nodes only see the projects those nodes own.
+func (s *service) canView(ctx context.Context, u identity.Principal, proj *project.Project) (bool, error) {
+	return visibleTo(u, proj, func() (bool, error) {
+		return s.rbac.HasPermission(ctx, u.PersonID, proj.OwningOrgNodeID, rbac.PermissionViewProjects)
+	})
+}
+
+// visibleTo implements canView; viewProjects reports whether the user holds view_projects on the owning node
+func visibleTo(u identity.Principal, proj *project.Project, viewProjects func() (bool, error)) (bool, error) {
+	if !u.CanAccessNode(proj.OwningOrgNodeID) {
+		return false, nil
+	}
+	if u.IsSysAdmin {
+		return true, nil
+	}
+	if proj.IsActiveMember(u.PersonID, time.Now()) {
+		return true, nil
+	}
+
+	switch proj.EffectiveVisibility() {
+	case project.VisibilityPublic:
+		return true, nil
+	case project.VisibilityOrganisation:
+		if proj.OwningOrgNodeID == uuid.Nil {
+			return false, nil
+		}
+		return viewProjects()
+	default:
+		return false, nil
+	}
+}
+
 func (s *service) GetAllProjects(ctx context.Context) ([]*ProjectDetails, error) {
 	u, err := s.currentUser.Current(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	// Only the projects the user may possibly read are loaded; the nodes they hold view_projects on
+	// are resolved once rather than for every project
 	var ids []uuid.UUID
-
-	// Sysadmins can see all projects
+	viewable := make(map[uuid.UUID]bool)
 	if u.IsSysAdmin {
 		ids, err = s.repo.ProjectIDsStarted(ctx)
 	} else {
-		ids, err = s.repo.ProjectIDsForPerson(ctx, u.PersonID)
+		var nodeIDs []uuid.UUID
+		if nodeIDs, err = s.rbac.NodeIDsWithPermission(ctx, u.PersonID, rbac.PermissionViewProjects); err != nil {
+			return nil, err
+		}
+		for _, id := range nodeIDs {
+			viewable[id] = true
+		}
+		ids, err = s.repo.CandidateProjectIDs(ctx, u.PersonID, nodeIDs)
 	}
 	if err != nil {
 		return nil, err
@@ -100,6 +180,11 @@ func (s *service) GetAllProjects(ctx context.Context) ([]*ProjectDetails, error)
 			return nil, false
 		}
 
+		ok, _ := visibleTo(u, proj, func() (bool, error) { return viewable[proj.OwningOrgNodeID], nil })
+		if !ok {
+			return nil, false
+		}
+
 		details, err := s.buildProjectDetails(ctx, proj)
 		if err != nil {
 			return nil, false
@@ -117,6 +202,10 @@ func (s *service) GetAllProjects(ctx context.Context) ([]*ProjectDetails, error)
 }
 
 func (s *service) GetPendingEvents(ctx context.Context, projectID uuid.UUID) ([]events2.DetailedEvent, error) {
+	if _, err := s.loadVisible(ctx, projectID); err != nil {
+		return nil, err
+	}
+
 	evts, _, err := s.eventSvc.Load(ctx, projectID)
 	if err != nil {
 		return nil, err
@@ -156,8 +245,10 @@ func (s *service) buildProjectDetails(ctx context.Context, proj *project.Project
 		r, okR := rolesMap[m.ProjectRoleID]
 		if okP && okR {
 			return project.MemberDetail{
-				Person: p,
-				Role:   r,
+				Person:    p,
+				Role:      r,
+				StartDate: m.StartDate,
+				EndDate:   m.EndDate,
 			}, true
 		}
 		return project.MemberDetail{}, false
@@ -193,6 +284,10 @@ func (s *service) buildProjectDetails(ctx context.Context, proj *project.Project
 }
 
 func (s *service) GetChangeLog(ctx context.Context, id uuid.UUID) ([]events2.DetailedEvent, error) {
+	if _, err := s.loadVisible(ctx, id); err != nil {
+		return nil, err
+	}
+
 	evts, _, err := s.eventSvc.Load(ctx, id)
 	if err != nil {
 		return nil, err
@@ -221,7 +316,7 @@ func (s *service) GetProjectRoles(ctx context.Context) ([]role.ProjectRole, erro
 }
 
 func (s *service) ListAvailableRoles(ctx context.Context, projectID uuid.UUID) ([]role.ProjectRole, error) {
-	proj, err := s.loader.Load(ctx, projectID)
+	proj, err := s.loadVisible(ctx, projectID)
 	if err != nil {
 		return nil, err
 	}
@@ -235,6 +330,10 @@ func (s *service) ListAvailableRoles(ctx context.Context, projectID uuid.UUID) (
 }
 
 func (s *service) GetEvents(ctx context.Context, id uuid.UUID) ([]events2.Event, error) {
+	if _, err := s.loadVisible(ctx, id); err != nil {
+		return nil, err
+	}
+
 	evts, _, err := s.eventSvc.Load(ctx, id)
 	return evts, err
 }
@@ -255,10 +354,11 @@ func (s *service) GetAllowedEventTypes(ctx context.Context, projectID uuid.UUID)
 		return nil, err
 	}
 
-	// Find user's role in this project
+	// Find user's current role in this project
 	var userRoleID *uuid.UUID
+	now := time.Now()
 	for _, m := range proj.Members {
-		if m.PersonID == u.PersonID {
+		if m.PersonID == u.PersonID && m.ActiveAt(now) {
 			userRoleID = &m.ProjectRoleID
 			break
 		}
@@ -14,7 +14,8 @@ type Repository interface {
 	GetByKeyAndOrg(ctx context.Context, key string, orgNodeID uuid.UUID) (*role.ProjectRole, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*role.ProjectRole, error)
 	Exists(ctx context.Context, key string, orgNodeID uuid.UUID) (bool, error)
-	Unarchive(ctx context.Context, key string, orgNodeID uuid.UUID) error
+	// Unarchive reports whether an archived role was restored
+	Unarchive(ctx context.Context, key string, orgNodeID uuid.UUID) (bool, error)
 	Delete(ctx context.Context, id uuid.UUID, orgNodeID uuid.UUID) error
 	ListByOrgIDs(ctx context.Context, orgIDs []uuid.UUID) ([]role.ProjectRole, error)
 	List(ctx context.Context) ([]role.ProjectRole, error)
@@ -4,8 +4,11 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/SURF-Innovatie/MORIS/internal/app/audit"
 	"github.com/SURF-Innovatie/MORIS/internal/app/organisation"
 	organisationhierarchy "github.com/SURF-Innovatie/MORIS/internal/app/organisation/hierarchy"
+	"github.com/SURF-Innovatie/MORIS/internal/app/tx"
+	auditdomain "github.com/SURF-Innovatie/MORIS/internal/domain/audit"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/project/events"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/project/role"
 	"github.com/google/uuid"
@@ -24,10 +27,12 @@ type service struct {
 	repo            Repository
 	orgSvc          organisation.Service
 	orgHierarchySvc organisationhierarchy.Service
+	audit           audit.Service
+	tx              tx.Manager
 }
 
-func NewService(repo Repository, orgSvc organisation.Service, orgHierarchySvc organisationhierarchy.Service) Service {
-	return &service{repo: repo, orgSvc: orgSvc, orgHierarchySvc: orgHierarchySvc}
+func NewService(repo Repository, orgSvc organisation.Service, orgHierarchySvc organisationhierarchy.Service, auditSvc audit.Service, tx tx.Manager) Service {
+	return &service{repo: repo, orgSvc: orgSvc, orgHierarchySvc: orgHierarchySvc, audit: auditSvc, tx: tx}
 }
 
 func (s *service) EnsureDefaults(ctx context.Context) error {
@@ -39,12 +44,15 @@ func (s *service) EnsureDefaults(ctx context.Context) error {
 	// Get all registered event types for default roles
 	allEventTypes := events.GetRegisteredEventTypes()
 
+	// Viewers can read a project but not execute any of its events
 	defs := []struct {
-		key  string
-		name string
+		key        string
+		name       string
+		eventTypes []string
 	}{
-		{key: "contributor", name: "Contributor"},
-		{key: "lead", name: "Project lead"},
+		{key: "contributor", name: "Contributor", eventTypes: allEventTypes},
+		{key: role.KeyLead, name: "Project lead", eventTypes: allEventTypes},
+		{key: "viewer", name: "Viewer", eventTypes: []string{}},
 	}
 
 	for _, root := range roots {
@@ -54,17 +62,10 @@ func (s *service) EnsureDefaults(ctx context.Context) error {
 				return fmt.Errorf("checking existence of role %s for root %s: %w", d.key, root.ID, err)
 			}
 
-			if !exists {
-				// Create with all event types allowed
-				_, err := s.repo.CreateWithEventTypes(ctx, d.key, d.name, root.ID, allEventTypes)
-				if err != nil {
-					return fmt.Errorf("creating default role %s for root %s: %w", d.key, root.ID, err)
-				}
-			} else {
-				err := s.repo.Unarchive(ctx, d.key, root.ID)
-				if err != nil {
-					return fmt.Errorf("ensuring default role %s is unarchived for root %s: %w", d.key, root.ID, err)
-				}
+			if err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+				return s.ensureDefault(ctx, exists, d.key, d.name, root.ID, d.eventTypes)
+			}); err != nil {
+				return fmt.Errorf("ensuring default role %s for root %s: %w", d.key, root.ID, err)
 			}
 		}
 	}
@@ -72,12 +73,56 @@ func (s *service) EnsureDefaults(ctx context.Context) error {
 	return nil
 }
 
+// ensureDefault creates a missing default role or restores an archived one. Restoring is recorded
+// as a creation, just like restoring through Create, since deleting a role archives it.
+func (s *service) ensureDefault(ctx context.Context, exists bool, key, name string, rootID uuid.UUID, eventTypes []string) error {
+	var (
+		r   *role.ProjectRole
+		err error
+	)
+	if !exists {
+		if r, err = s.repo.CreateWithEventTypes(ctx, key, name, rootID, eventTypes); err != nil {
+			return err
+		}
+	} else {
+		restored, err := s.repo.Unarchive(ctx, key, rootID)
+		if err != nil || !restored {
+			return err
+		}
+		if r, err = s.repo.GetByKeyAndOrg(ctx, key, rootID); err != nil {
+			return err
+		}
+	}
+	return s.record(ctx, auditdomain.ActionCreate, r.OrganisationNodeID, r.ID, nil, r)
+}
+
 func (s *service) Create(ctx context.Context, key, name string, orgNodeID uuid.UUID) (*role.ProjectRole, error) {
-	return s.repo.CreateOrRestore(ctx, key, name, orgNodeID)
+	var created *role.ProjectRole
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = s.repo.CreateOrRestore(ctx, key, name, orgNodeID)
+		if err != nil {
+			return err
+		}
+		return s.record(ctx, auditdomain.ActionCreate, orgNodeID, created.ID, nil, created)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
 }
 
 func (s *service) Delete(ctx context.Context, id uuid.UUID, orgNodeID uuid.UUID) error {
-	return s.repo.Delete(ctx, id, orgNodeID)
+	return s.tx.WithTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.Delete(ctx, id, orgNodeID); err != nil {
+			return err
+		}
+		return s.record(ctx, auditdomain.ActionDelete, orgNodeID, id, before, nil)
+	})
 }
 
 func (s *service) ListAvailableForNode(ctx context.Context, orgNodeID uuid.UUID) ([]role.ProjectRole, error) {
@@ -94,5 +139,36 @@ func (s *service) GetByID(ctx context.Context, id uuid.UUID) (*role.ProjectRole,
 }
 
 func (s *service) UpdateAllowedEventTypes(ctx context.Context, roleID uuid.UUID, eventTypes []string) (*role.ProjectRole, error) {
-	return s.repo.UpdateAllowedEventTypes(ctx, roleID, eventTypes)
+	var updated *role.ProjectRole
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetByID(ctx, roleID)
+		if err != nil {
+			return err
+		}
+		updated, err = s.repo.UpdateAllowedEventTypes(ctx, roleID, eventTypes)
+		if err != nil {
+			return err
+		}
+		return s.record(ctx, auditdomain.ActionUpdate, before.OrganisationNodeID, roleID, before, updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (s *service) record(ctx context.Context, action auditdomain.Action, orgNodeID, roleID uuid.UUID, before, after *role.ProjectRole) error {
+	c := auditdomain.Change{
+		OrgNodeID:  orgNodeID,
+		EntityType: auditdomain.EntityProjectRole,
+		EntityID:   roleID,
+		Action:     action,
+	}
+	if before != nil {
+		c.Before = before
+	}
+	if after != nil {
+		c.After = after
+	}
+	return s.audit.Record(ctx, c)
 }
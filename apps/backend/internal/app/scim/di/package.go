@@ -0,0 +1,28 @@
+package di
+
+import (
+	coreauth "github.com/SURF-Innovatie/MORIS/internal/app/auth"
+	"github.com/SURF-Innovatie/MORIS/internal/app/organisation"
+	organisationrole "github.com/SURF-Innovatie/MORIS/internal/app/organisation/role"
+	"github.com/SURF-Innovatie/MORIS/internal/app/person"
+	"github.com/SURF-Innovatie/MORIS/internal/app/scim"
+	"github.com/SURF-Innovatie/MORIS/internal/app/user"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/persistence/enttx"
+	scimrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/scim"
+	"github.com/samber/do/v2"
+)
+
+var Package = do.Package(
+	do.Lazy(provideService),
+)
+
+func provideService(i do.Injector) (scim.Service, error) {
+	repo := do.MustInvoke[*scimrepo.EntRepo](i)
+	userSvc := do.MustInvoke[user.Service](i)
+	personSvc := do.MustInvoke[person.Service](i)
+	roleSvc := do.MustInvoke[organisationrole.Service](i)
+	orgSvc := do.MustInvoke[organisation.Service](i)
+	curUser := do.MustInvoke[coreauth.CurrentUserProvider](i)
+	txManager := do.MustInvoke[*enttx.Manager](i)
+	return scim.NewService(repo, userSvc, personSvc, roleSvc, orgSvc, curUser, txManager), nil
+}
@@ -0,0 +1,48 @@
+package scim
+
+import (
+	"context"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation/rbac"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/scim"
+	"github.com/google/uuid"
+)
+
+type repository interface {
+	CreateToken(ctx context.Context, t scim.Token, hash string) (*scim.Token, error)
+	ListTokens(ctx context.Context, rootNodeID uuid.UUID) ([]scim.Token, error)
+	// DeleteToken removes a token of the root node and reports whether it existed
+	DeleteToken(ctx context.Context, rootNodeID, id uuid.UUID) (bool, error)
+	// TokenByHash returns nil for an unknown token
+	TokenByHash(ctx context.Context, hash string) (*scim.Token, error)
+	TouchToken(ctx context.Context, id uuid.UUID, at time.Time) error
+
+	// ListUsers returns the users provisioned for the root node, oldest first
+	ListUsers(ctx context.Context, rootNodeID uuid.UUID) ([]scim.User, error)
+	// FindUsers returns at most limit of the provisioned users that match the filter, oldest first
+	// and skipping offset, together with the number of matching users
+	FindUsers(ctx context.Context, rootNodeID uuid.UUID, f scim.Filter, offset, limit int) ([]scim.User, int, error)
+	// ExternalIDInUse reports whether another user of the root node has the external ID
+	ExternalIDInUse(ctx context.Context, rootNodeID, userID uuid.UUID, externalID string) (bool, error)
+	// GetUser returns nil when the user is not provisioned for the root node
+	GetUser(ctx context.Context, rootNodeID, userID uuid.UUID) (*scim.User, error)
+	// LinkedRoot returns the root node that provisions the user, nil if there is none
+	LinkedRoot(ctx context.Context, userID uuid.UUID) (*uuid.UUID, error)
+	CreateLink(ctx context.Context, rootNodeID, userID uuid.UUID, externalID *string) error
+	SetExternalID(ctx context.Context, userID uuid.UUID, externalID *string) error
+	DeleteLink(ctx context.Context, userID uuid.UUID) error
+
+	// AccountByEmail returns the person with the address and, if it has one, its user
+	AccountByEmail(ctx context.Context, email string) (personID, userID *uuid.UUID, err error)
+	// HasMembershipUnder reports whether the person has a membership on the root node or one of its descendants
+	HasMembershipUnder(ctx context.Context, rootNodeID, personID uuid.UUID) (bool, error)
+
+	// ListGroups returns the role scopes on the root node and its descendants as groups whose
+	// members are the provisioned users of the root node with a membership active at the time,
+	// ordered by display name
+	ListGroups(ctx context.Context, rootNodeID uuid.UUID, at time.Time) ([]scim.Group, error)
+	// GetGroup returns nil when the role scope is not in the tree of the root node
+	GetGroup(ctx context.Context, rootNodeID, id uuid.UUID, at time.Time) (*scim.Group, error)
+	ScopeMemberships(ctx context.Context, scopeID uuid.UUID) ([]rbac.Membership, error)
+}
@@ -0,0 +1,491 @@
+package scim
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	appauth "github.com/SURF-Innovatie/MORIS/internal/app/auth"
+	orgsvc "github.com/SURF-Innovatie/MORIS/internal/app/organisation"
+	"github.com/SURF-Innovatie/MORIS/internal/app/organisation/role"
+	"github.com/SURF-Innovatie/MORIS/internal/app/person"
+	"github.com/SURF-Innovatie/MORIS/internal/app/tx"
+	"github.com/SURF-Innovatie/MORIS/internal/app/user"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/identity"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation/rbac"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/scim"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// touchInterval limits how often the last use of a token is written
+const touchInterval = time.Minute
+
+// ListQuery holds the filter and pagination parameters of a list request
+type ListQuery struct {
+	Filter     string
+	StartIndex int
+	Count      int
+}
+
+// Service is the SCIM 2.0 server of a root node. Users are the MORIS users the identity management
+// system of the root node provisioned; groups are the role scopes in the tree of the root node.
+// Deprovisioned users are deactivated rather than deleted so that their history stays intact.
+type Service interface {
+	ListTokens(ctx context.Context, rootNodeID uuid.UUID) ([]scim.Token, error)
+	// CreateToken issues a token for a root node and returns it with its secret, which is not stored
+	CreateToken(ctx context.Context, rootNodeID uuid.UUID, name string) (*scim.Token, string, error)
+	DeleteToken(ctx context.Context, rootNodeID, id uuid.UUID) error
+	// Authenticate returns the token of a bearer secret
+	Authenticate(ctx context.Context, secret string) (*scim.Token, error)
+
+	ListUsers(ctx context.Context, rootNodeID uuid.UUID, q ListQuery) (*scim.Page[scim.User], error)
+	GetUser(ctx context.Context, rootNodeID, id uuid.UUID) (*scim.User, error)
+	// CreateUser provisions a user. A person that already has the email address is adopted, unless
+	// another root node provisions it.
+	CreateUser(ctx context.Context, rootNodeID uuid.UUID, u scim.User) (*scim.User, error)
+	ReplaceUser(ctx context.Context, rootNodeID, id uuid.UUID, u scim.User) (*scim.User, error)
+	PatchUser(ctx context.Context, rootNodeID, id uuid.UUID, ops []scim.PatchOperation) (*scim.User, error)
+	// DeleteUser deactivates the user and ends its provisioning; the account and person remain
+	DeleteUser(ctx context.Context, rootNodeID, id uuid.UUID) error
+
+	ListGroups(ctx context.Context, rootNodeID uuid.UUID, q ListQuery) (*scim.Page[scim.Group], error)
+	GetGroup(ctx context.Context, rootNodeID, id uuid.UUID) (*scim.Group, error)
+	// ReplaceGroup sets the members of a group; the display name cannot be changed
+	ReplaceGroup(ctx context.Context, rootNodeID, id uuid.UUID, g scim.Group) (*scim.Group, error)
+	PatchGroup(ctx context.Context, rootNodeID, id uuid.UUID, ops []scim.PatchOperation) (*scim.Group, error)
+}
+
+type service struct {
+	repo        repository
+	users       user.Service
+	people      person.Service
+	roles       role.Service
+	orgSvc      orgsvc.Service
+	currentUser appauth.CurrentUserProvider
+	tx          tx.Manager
+	now         func() time.Time
+}
+
+func NewService(
+	repo repository,
+	users user.Service,
+	people person.Service,
+	roles role.Service,
+	orgSvc orgsvc.Service,
+	currentUser appauth.CurrentUserProvider,
+	tx tx.Manager,
+) Service {
+	return &service{
+		repo:        repo,
+		users:       users,
+		people:      people,
+		roles:       roles,
+		orgSvc:      orgSvc,
+		currentUser: currentUser,
+		tx:          tx,
+		now:         time.Now,
+	}
+}
+
+func (s *service) ListTokens(ctx context.Context, rootNodeID uuid.UUID) ([]scim.Token, error) {
+	return s.repo.ListTokens(ctx, rootNodeID)
+}
+
+func (s *service) CreateToken(ctx context.Context, rootNodeID uuid.UUID, name string) (*scim.Token, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", fmt.Errorf("%w: name is required", scim.ErrInvalidValue)
+	}
+	node, err := s.orgSvc.Get(ctx, rootNodeID)
+	if err != nil {
+		return nil, "", err
+	}
+	if node.ParentID != nil {
+		return nil, "", scim.ErrNotRootNode
+	}
+
+	secret, err := scim.NewTokenSecret()
+	if err != nil {
+		return nil, "", err
+	}
+	t := scim.Token{RootNodeID: rootNodeID, Name: name}
+	if p, err := s.currentUser.Current(ctx); err == nil {
+		t.CreatedByUserID = &p.UserID
+	}
+	created, err := s.repo.CreateToken(ctx, t, scim.HashToken(secret))
+	if err != nil {
+		return nil, "", err
+	}
+	return created, secret, nil
+}
+
+func (s *service) DeleteToken(ctx context.Context, rootNodeID, id uuid.UUID) error {
+	ok, err := s.repo.DeleteToken(ctx, rootNodeID, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return scim.ErrNotFound
+	}
+	return nil
+}
+
+func (s *service) Authenticate(ctx context.Context, secret string) (*scim.Token, error) {
+	if secret == "" {
+		return nil, scim.ErrUnauthorized
+	}
+	t, err := s.repo.TokenByHash(ctx, scim.HashToken(secret))
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, scim.ErrUnauthorized
+	}
+	now := s.now()
+	if t.LastUsedAt == nil || now.Sub(*t.LastUsedAt) >= touchInterval {
+		if err := s.repo.TouchToken(ctx, t.ID, now); err != nil {
+			return nil, err
+		}
+		t.LastUsedAt = &now
+	}
+	return t, nil
+}
+
+func (s *service) ListUsers(ctx context.Context, rootNodeID uuid.UUID, q ListQuery) (*scim.Page[scim.User], error) {
+	f, err := scim.ParseFilter(q.Filter)
+	if err != nil {
+		return nil, err
+	}
+	startIndex := max(q.StartIndex, 1)
+	users, total, err := s.repo.FindUsers(ctx, rootNodeID, f, startIndex-1, q.Count)
+	if err != nil {
+		return nil, err
+	}
+	return &scim.Page[scim.User]{Resources: users, TotalResults: total, StartIndex: startIndex}, nil
+}
+
+func (s *service) GetUser(ctx context.Context, rootNodeID, id uuid.UUID) (*scim.User, error) {
+	u, err := s.repo.GetUser(ctx, rootNodeID, id)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, scim.ErrNotFound
+	}
+	return u, nil
+}
+
+func (s *service) CreateUser(ctx context.Context, rootNodeID uuid.UUID, u scim.User) (*scim.User, error) {
+	if err := normalizeUser(&u); err != nil {
+		return nil, err
+	}
+	if err := s.checkExternalID(ctx, rootNodeID, uuid.Nil, u.ExternalID); err != nil {
+		return nil, err
+	}
+
+	var userID uuid.UUID
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		personID, existingUserID, err := s.repo.AccountByEmail(ctx, u.UserName)
+		if err != nil {
+			return err
+		}
+
+		if personID != nil {
+			ok, err := s.canAdopt(ctx, rootNodeID, *personID, existingUserID)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return fmt.Errorf("%w: %s belongs to an account outside this organisation", scim.ErrUniqueness, u.UserName)
+			}
+		} else {
+			p, err := s.people.Create(ctx, identity.Person{
+				Name:       u.DisplayName,
+				GivenName:  u.GivenName,
+				FamilyName: u.FamilyName,
+				Email:      u.UserName,
+			})
+			if err != nil {
+				return err
+			}
+			personID = &p.ID
+		}
+
+		if existingUserID != nil {
+			root, err := s.repo.LinkedRoot(ctx, *existingUserID)
+			if err != nil {
+				return err
+			}
+			if root != nil {
+				return fmt.Errorf("%w: %s is already provisioned", scim.ErrUniqueness, u.UserName)
+			}
+			userID = *existingUserID
+			if err := s.updatePerson(ctx, *personID, u); err != nil {
+				return err
+			}
+		} else {
+			created, err := s.users.Create(ctx, identity.User{PersonID: *personID})
+			if err != nil {
+				return err
+			}
+			userID = created.ID
+		}
+
+		if err := s.repo.CreateLink(ctx, rootNodeID, userID, u.ExternalID); err != nil {
+			return err
+		}
+		return s.users.ToggleActive(ctx, userID, u.Active)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetUser(ctx, rootNodeID, userID)
+}
+
+// canAdopt reports whether provisioning may take over an existing person and account. Only people
+// who already belong to the organisation qualify; administrators and service accounts never do.
+func (s *service) canAdopt(ctx context.Context, rootNodeID, personID uuid.UUID, userID *uuid.UUID) (bool, error) {
+	if userID != nil {
+		usr, err := s.users.Get(ctx, *userID)
+		if err != nil {
+			return false, err
+		}
+		if usr.IsSysAdmin || usr.IsServiceAccount {
+			return false, nil
+		}
+	}
+	return s.repo.HasMembershipUnder(ctx, rootNodeID, personID)
+}
+
+func (s *service) ReplaceUser(ctx context.Context, rootNodeID, id uuid.UUID, u scim.User) (*scim.User, error) {
+	existing, err := s.GetUser(ctx, rootNodeID, id)
+	if err != nil {
+		return nil, err
+	}
+	u.ID, u.PersonID = existing.ID, existing.PersonID
+	return s.save(ctx, rootNodeID, *existing, u)
+}
+
+func (s *service) PatchUser(ctx context.Context, rootNodeID, id uuid.UUID, ops []scim.PatchOperation) (*scim.User, error) {
+	existing, err := s.GetUser(ctx, rootNodeID, id)
+	if err != nil {
+		return nil, err
+	}
+	u := *existing
+	// userName and emails are one address: a changed email address is the new userName
+	if err := scim.ApplyUserPatch(&u, ops); err != nil {
+		return nil, err
+	}
+	if u.UserName == existing.UserName && u.Email != existing.Email {
+		u.UserName = u.Email
+	}
+	return s.save(ctx, rootNodeID, *existing, u)
+}
+
+// save writes the changes of a provisioned user to its person, link and account
+func (s *service) save(ctx context.Context, rootNodeID uuid.UUID, before, after scim.User) (*scim.User, error) {
+	if err := normalizeUser(&after); err != nil {
+		return nil, err
+	}
+	if err := s.checkExternalID(ctx, rootNodeID, before.ID, after.ExternalID); err != nil {
+		return nil, err
+	}
+
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		if !strings.EqualFold(after.UserName, before.UserName) {
+			personID, _, err := s.repo.AccountByEmail(ctx, after.UserName)
+			if err != nil {
+				return err
+			}
+			if personID != nil && *personID != before.PersonID {
+				return fmt.Errorf("%w: %s is in use", scim.ErrUniqueness, after.UserName)
+			}
+		}
+		if err := s.updatePerson(ctx, before.PersonID, after); err != nil {
+			return err
+		}
+		if lo.FromPtr(after.ExternalID) != lo.FromPtr(before.ExternalID) {
+			if err := s.repo.SetExternalID(ctx, before.ID, after.ExternalID); err != nil {
+				return err
+			}
+		}
+		if after.Active != before.Active {
+			return s.users.ToggleActive(ctx, before.ID, after.Active)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetUser(ctx, rootNodeID, before.ID)
+}
+
+// updatePerson writes the SCIM attributes to a person, keeping the attributes SCIM does not manage
+func (s *service) updatePerson(ctx context.Context, personID uuid.UUID, u scim.User) error {
+	p, err := s.people.Get(ctx, personID)
+	if err != nil {
+		return err
+	}
+	p.Name = u.DisplayName
+	p.GivenName = u.GivenName
+	p.FamilyName = u.FamilyName
+	p.Email = u.UserName
+	_, err = s.people.Update(ctx, personID, *p)
+	return err
+}
+
+func (s *service) DeleteUser(ctx context.Context, rootNodeID, id uuid.UUID) error {
+	if _, err := s.GetUser(ctx, rootNodeID, id); err != nil {
+		return err
+	}
+	return s.tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteLink(ctx, id); err != nil {
+			return err
+		}
+		return s.users.ToggleActive(ctx, id, false)
+	})
+}
+
+// checkExternalID rejects an external ID that another user of the root node already has. The
+// unique index on the root node and external ID catches concurrent requests.
+func (s *service) checkExternalID(ctx context.Context, rootNodeID, userID uuid.UUID, externalID *string) error {
+	if externalID == nil {
+		return nil
+	}
+	inUse, err := s.repo.ExternalIDInUse(ctx, rootNodeID, userID, *externalID)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return fmt.Errorf("%w: externalId %s is in use", scim.ErrUniqueness, *externalID)
+	}
+	return nil
+}
+
+// normalizeUser validates a user and derives the attributes MORIS requires
+func normalizeUser(u *scim.User) error {
+	u.UserName = strings.TrimSpace(u.UserName)
+	if u.UserName == "" {
+		u.UserName = strings.TrimSpace(u.Email)
+	}
+	if !strings.Contains(u.UserName, "@") {
+		return fmt.Errorf("%w: userName must be an email address", scim.ErrInvalidValue)
+	}
+	u.Email = u.UserName
+
+	u.DisplayName = strings.TrimSpace(u.DisplayName)
+	if u.DisplayName == "" {
+		u.DisplayName = strings.TrimSpace(lo.FromPtr(u.GivenName) + " " + lo.FromPtr(u.FamilyName))
+	}
+	if u.DisplayName == "" {
+		u.DisplayName = u.UserName
+	}
+	if u.ExternalID != nil && *u.ExternalID == "" {
+		u.ExternalID = nil
+	}
+	return nil
+}
+
+func (s *service) ListGroups(ctx context.Context, rootNodeID uuid.UUID, q ListQuery) (*scim.Page[scim.Group], error) {
+	f, err := scim.ParseFilter(q.Filter)
+	if err != nil {
+		return nil, err
+	}
+	all, err := s.repo.ListGroups(ctx, rootNodeID, s.now())
+	if err != nil {
+		return nil, err
+	}
+	page := scim.Paginate(scim.MatchAll(f, all), q.StartIndex, q.Count)
+	return &page, nil
+}
+
+func (s *service) GetGroup(ctx context.Context, rootNodeID, id uuid.UUID) (*scim.Group, error) {
+	g, err := s.repo.GetGroup(ctx, rootNodeID, id, s.now())
+	if err != nil {
+		return nil, err
+	}
+	if g == nil {
+		return nil, scim.ErrNotFound
+	}
+	return g, nil
+}
+
+func (s *service) ReplaceGroup(ctx context.Context, rootNodeID, id uuid.UUID, g scim.Group) (*scim.Group, error) {
+	existing, err := s.GetGroup(ctx, rootNodeID, id)
+	if err != nil {
+		return nil, err
+	}
+	if g.DisplayName != "" && g.DisplayName != existing.DisplayName {
+		return nil, fmt.Errorf("%w: displayName is derived from the role and node", scim.ErrMutability)
+	}
+	members := make(map[uuid.UUID]bool, len(g.Members))
+	for _, m := range g.Members {
+		members[m.UserID] = true
+	}
+	if err := s.setMembers(ctx, rootNodeID, *existing, members); err != nil {
+		return nil, err
+	}
+	return s.GetGroup(ctx, rootNodeID, id)
+}
+
+func (s *service) PatchGroup(ctx context.Context, rootNodeID, id uuid.UUID, ops []scim.PatchOperation) (*scim.Group, error) {
+	existing, err := s.GetGroup(ctx, rootNodeID, id)
+	if err != nil {
+		return nil, err
+	}
+	members, err := scim.ApplyGroupPatch(*existing, ops)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.setMembers(ctx, rootNodeID, *existing, members); err != nil {
+		return nil, err
+	}
+	return s.GetGroup(ctx, rootNodeID, id)
+}
+
+// setMembers brings the memberships of the provisioned users in a role scope in line with members.
+// A membership whose period has ended is reopened rather than duplicated. Memberships of people the
+// root node does not provision are left alone.
+func (s *service) setMembers(ctx context.Context, rootNodeID uuid.UUID, g scim.Group, members map[uuid.UUID]bool) error {
+	users, err := s.repo.ListUsers(ctx, rootNodeID)
+	if err != nil {
+		return err
+	}
+	personOf := make(map[uuid.UUID]uuid.UUID, len(users))
+	for _, u := range users {
+		personOf[u.ID] = u.PersonID
+	}
+	for id := range members {
+		if _, ok := personOf[id]; !ok {
+			return fmt.Errorf("%w: %s is not a provisioned user", scim.ErrInvalidValue, id)
+		}
+	}
+
+	return s.tx.WithTx(ctx, func(ctx context.Context) error {
+		memberships, err := s.repo.ScopeMemberships(ctx, g.ID)
+		if err != nil {
+			return err
+		}
+		now := s.now()
+		for userID, personID := range personOf {
+			m, has := lo.Find(memberships, func(m rbac.Membership) bool { return m.PersonID == personID })
+			switch {
+			case members[userID] && !has:
+				if _, err := s.roles.AddMembership(ctx, personID, g.ID, nil, nil); err != nil {
+					return err
+				}
+			case members[userID] && !m.ActiveAt(now):
+				if _, err := s.roles.SetMembershipPeriod(ctx, m.ID, nil, nil); err != nil {
+					return err
+				}
+			case !members[userID] && has:
+				if err := s.roles.RemoveMembership(ctx, m.ID); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
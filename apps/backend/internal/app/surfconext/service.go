@@ -6,49 +6,66 @@ import (
 	"fmt"
 
 	coreauth "github.com/SURF-Innovatie/MORIS/internal/app/auth"
+	"github.com/SURF-Innovatie/MORIS/internal/app/organisation/provisioning"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/identity/readmodels"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation/rbac"
 )
 
 var (
 	ErrMissingCode = errors.New("missing_authorization_code")
 	ErrNoEmail     = errors.New("no_email_claim")
+	// ErrProvisioning is returned when the memberships granted by the claims could not be updated
+	ErrProvisioning = errors.New("membership_provisioning_failed")
 )
 
 type Service interface {
 	AuthURL(ctx context.Context) (string, error)
-	LoginWithCode(ctx context.Context, code string) (string, *readmodels.UserAccount, error)
+	LoginWithCode(ctx context.Context, code string, client coreauth.ClientInfo) (*coreauth.Tokens, *readmodels.UserAccount, error)
 }
 
 type service struct {
-	client  Client
-	authSvc coreauth.Service
+	client       Client
+	authSvc      coreauth.Service
+	provisioning provisioning.Service
 }
 
-func NewService(client Client, authSvc coreauth.Service) Service {
-	return &service{client: client, authSvc: authSvc}
+func NewService(client Client, authSvc coreauth.Service, provisioningSvc provisioning.Service) Service {
+	return &service{client: client, authSvc: authSvc, provisioning: provisioningSvc}
 }
 
 func (s *service) AuthURL(ctx context.Context) (string, error) {
 	return s.client.AuthURL(ctx)
 }
 
-func (s *service) LoginWithCode(ctx context.Context, code string) (string, *readmodels.UserAccount, error) {
+func (s *service) LoginWithCode(ctx context.Context, code string, client coreauth.ClientInfo) (*coreauth.Tokens, *readmodels.UserAccount, error) {
 	if code == "" {
-		return "", nil, ErrMissingCode
+		return nil, nil, ErrMissingCode
 	}
 
 	claims, err := s.client.ExchangeCode(ctx, code)
 	if err != nil {
-		return "", nil, err
+		return nil, nil, err
 	}
 	if claims == nil || claims.Email == "" {
-		return "", nil, ErrNoEmail
+		return nil, nil, ErrNoEmail
 	}
 
-	token, user, err := s.authSvc.LoginByEmail(ctx, claims.Email)
+	tokens, user, err := s.authSvc.LoginByEmail(ctx, claims.Email, client)
 	if err != nil {
-		return "", nil, fmt.Errorf("login by email: %w", err)
+		return nil, nil, fmt.Errorf("login by email: %w", err)
 	}
 
-	return token, user, nil
+	// The claims reflect the person's current position at their institution, so the memberships
+	// they grant are brought up to date on every login. A failure must not leave revoked access in place.
+	if _, err := s.provisioning.Sync(ctx, user.Person.ID, rbac.ProvisioningClaims{
+		SchacHomeOrganization: claims.SchacHomeOrganization,
+		Affiliations:          claims.EduPersonAffiliation,
+		Entitlements:          claims.EduPersonEntitlement,
+	}); err != nil {
+		// The tokens are not handed out, so the session must not outlive this request
+		_ = s.authSvc.Logout(ctx, tokens.SessionID)
+		return nil, nil, fmt.Errorf("%w: %v", ErrProvisioning, err)
+	}
+
+	return tokens, user, nil
 }
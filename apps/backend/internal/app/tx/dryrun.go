@@ -0,0 +1,27 @@
+package tx
+
+import (
+	"context"
+	"errors"
+)
+
+// errDryRun rolls back the transaction of a dry run once fn has succeeded
+var errDryRun = errors.New("dry run")
+
+// WithDryRun runs fn in a transaction of m. With dryRun set the transaction is rolled back after fn
+// succeeds, so fn sees its own changes, for instance to report them, but none of them are kept.
+func WithDryRun(ctx context.Context, m Manager, dryRun bool, fn func(ctx context.Context) error) error {
+	err := m.WithTx(ctx, func(ctx context.Context) error {
+		if err := fn(ctx); err != nil {
+			return err
+		}
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if errors.Is(err, errDryRun) {
+		return nil
+	}
+	return err
+}
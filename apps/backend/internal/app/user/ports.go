@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/SURF-Innovatie/MORIS/internal/domain/identity"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/locale"
 	"github.com/google/uuid"
 )
 
@@ -18,6 +19,9 @@ type Repository interface {
 	GetByPersonID(ctx context.Context, personID uuid.UUID) (*identity.User, error)
 	SetZenodoTokens(ctx context.Context, userID uuid.UUID, access, refresh string) error
 	ClearZenodoTokens(ctx context.Context, userID uuid.UUID) error
+	SetLanguage(ctx context.Context, userID uuid.UUID, lang locale.Language) error
+	// LanguagesByIDs returns the preferred language of each existing user
+	LanguagesByIDs(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]locale.Language, error)
 }
 
 type ProjectMembershipRepository interface {
@@ -6,6 +6,7 @@ import (
 	"github.com/SURF-Innovatie/MORIS/internal/app/person"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/identity"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/identity/readmodels"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/locale"
 	"github.com/SURF-Innovatie/MORIS/internal/infra/cache"
 	"github.com/google/uuid"
 	"github.com/samber/lo"
@@ -21,6 +22,10 @@ type Service interface {
 	GetAccountByEmail(ctx context.Context, email string) (*readmodels.UserAccount, error)
 	ListAll(ctx context.Context, limit, offset int) ([]*readmodels.UserAccount, int, error)
 	ToggleActive(ctx context.Context, id uuid.UUID, isActive bool) error
+	// SetLanguage sets the user's preferred language for notifications and emails
+	SetLanguage(ctx context.Context, id uuid.UUID, lang locale.Language) error
+	// GetLanguagesByUserIDs returns the preferred language of each user, the default language for unknown users
+	GetLanguagesByUserIDs(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]locale.Language, error)
 
 	SearchPersons(ctx context.Context, query string, observerPersonID *uuid.UUID) ([]identity.Person, error)
 	GetPeopleByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]identity.Person, error)
@@ -87,6 +92,29 @@ func (s *service) ToggleActive(ctx context.Context, id uuid.UUID, isActive bool)
 	return s.cache.DeleteUser(ctx, id)
 }
 
+func (s *service) SetLanguage(ctx context.Context, id uuid.UUID, lang locale.Language) error {
+	if _, err := locale.Parse(string(lang)); err != nil {
+		return err
+	}
+	if err := s.users.SetLanguage(ctx, id, lang); err != nil {
+		return err
+	}
+	return s.cache.DeleteUser(ctx, id)
+}
+
+func (s *service) GetLanguagesByUserIDs(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]locale.Language, error) {
+	langs, err := s.users.LanguagesByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range userIDs {
+		if _, ok := langs[id]; !ok {
+			langs[id] = locale.Default
+		}
+	}
+	return langs, nil
+}
+
 func (s *service) GetAccount(ctx context.Context, id uuid.UUID) (*readmodels.UserAccount, error) {
 	u, err := s.Get(ctx, id)
 	if err != nil {
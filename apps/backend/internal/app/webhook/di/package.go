@@ -0,0 +1,29 @@
+package di
+
+import (
+	exwebhook "github.com/SURF-Innovatie/MORIS/external/webhook"
+	organisationhierarchy "github.com/SURF-Innovatie/MORIS/internal/app/organisation/hierarchy"
+	"github.com/SURF-Innovatie/MORIS/internal/app/webhook"
+	webhookadapter "github.com/SURF-Innovatie/MORIS/internal/infra/adapters/webhook"
+	webhookrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/webhook"
+	"github.com/samber/do/v2"
+)
+
+var Package = do.Package(
+	do.Lazy(provideWebhookService),
+	do.Lazy(provideSubscriptionService),
+)
+
+func provideWebhookService(i do.Injector) (webhook.Service, error) {
+	repo := do.MustInvoke[*webhookrepo.EntRepo](i)
+	secrets := do.MustInvoke[*webhookadapter.SecretAdapter](i)
+	client := do.MustInvoke[exwebhook.Client](i)
+	return webhook.NewService(repo, secrets, client, webhook.Options{}), nil
+}
+
+func provideSubscriptionService(i do.Injector) (webhook.SubscriptionService, error) {
+	repo := do.MustInvoke[*webhookrepo.EntSubscriptionRepo](i)
+	webhookSvc := do.MustInvoke[webhook.Service](i)
+	hierarchy := do.MustInvoke[organisationhierarchy.Service](i)
+	return webhook.NewSubscriptionService(repo, webhookSvc, hierarchy), nil
+}
@@ -0,0 +1,70 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/internal/domain/webhook"
+	"github.com/google/uuid"
+)
+
+// Repository persists the webhook delivery queue and its log
+type Repository interface {
+	Create(ctx context.Context, d webhook.Delivery) (*webhook.Delivery, error)
+	Get(ctx context.Context, id uuid.UUID) (*webhook.Delivery, error)
+
+	// ClaimDue returns up to limit pending deliveries whose next attempt is due at or before now, and leases
+	// them until now+lease so that no other worker attempts them in the meantime
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]webhook.Delivery, error)
+
+	// RecordAttempt stores the outcome of a delivery attempt and increments its attempt counter.
+	// The status code and error of an earlier attempt are cleared when the result has none.
+	RecordAttempt(ctx context.Context, id uuid.UUID, result webhook.AttemptResult) error
+
+	// Requeue resets a delivery to pending so it is attempted again at the given time
+	Requeue(ctx context.Context, id uuid.UUID, at time.Time) error
+
+	// ListForPolicy returns the delivery log for a policy's endpoint, newest first
+	ListForPolicy(ctx context.Context, policyID uuid.UUID, status *webhook.DeliveryStatus) ([]webhook.Delivery, error)
+
+	// ListForSubscription returns the delivery log of a subscription, newest first
+	ListForSubscription(ctx context.Context, subscriptionID uuid.UUID, status *webhook.DeliveryStatus) ([]webhook.Delivery, error)
+}
+
+// SubscriptionRepository persists webhook subscriptions
+type SubscriptionRepository interface {
+	Create(ctx context.Context, s webhook.Subscription) (*webhook.Subscription, error)
+	Get(ctx context.Context, id uuid.UUID) (*webhook.Subscription, error)
+
+	// Update stores the name, scope, event types, endpoint and enabled flag of a subscription
+	Update(ctx context.Context, s webhook.Subscription) (*webhook.Subscription, error)
+
+	// SetSecret replaces the signing secret of a subscription and keeps the replaced one until previousExpiresAt
+	SetSecret(ctx context.Context, id uuid.UUID, secret string, at time.Time, previous string, previousExpiresAt time.Time) (*webhook.Subscription, error)
+
+	// Delete removes a subscription together with its delivery log
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	ListForOrgNode(ctx context.Context, orgNodeID uuid.UUID) ([]webhook.Subscription, error)
+
+	// ListEnabledForOrgNodes returns the enabled subscriptions scoped to any of the given nodes
+	ListEnabledForOrgNodes(ctx context.Context, orgNodeIDs []uuid.UUID) ([]webhook.Subscription, error)
+}
+
+// OrgHierarchy resolves the ancestors of an organisation node
+type OrgHierarchy interface {
+	// AncestorIDsInclusive returns the node and all of its ancestors
+	AncestorIDsInclusive(ctx context.Context, nodeID uuid.UUID) ([]uuid.UUID, error)
+}
+
+// SecretResolver looks up the signing secrets for a delivery's endpoint
+type SecretResolver interface {
+	// SecretsFor returns the current secret first, followed by a rotated secret that is still in its grace period
+	SecretsFor(ctx context.Context, d webhook.Delivery) ([]string, error)
+}
+
+// Sender performs the HTTP POST of a signed delivery
+type Sender interface {
+	// Post sends body to url with the given headers and returns the HTTP status code
+	Post(ctx context.Context, url string, body []byte, headers map[string]string) (int, error)
+}
@@ -0,0 +1,264 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/internal/common/backoff"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/webhook"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+var ErrNotReplayable = errors.New("webhook_delivery_not_replayable")
+
+// EnqueueInput describes a webhook delivery to be queued
+type EnqueueInput struct {
+	PolicyID       *uuid.UUID
+	SubscriptionID *uuid.UUID
+	EventID        *uuid.UUID
+	EventType      string
+	URL            string
+	Envelope       webhook.Envelope
+}
+
+type Service interface {
+	// Enqueue persists a delivery; it is sent asynchronously by the delivery worker
+	Enqueue(ctx context.Context, in EnqueueInput) (*webhook.Delivery, error)
+
+	// ProcessDue attempts all deliveries that are due and returns the number attempted
+	ProcessDue(ctx context.Context) (int, error)
+
+	// Run processes due deliveries every interval until ctx is cancelled
+	Run(ctx context.Context, interval time.Duration)
+
+	Get(ctx context.Context, id uuid.UUID) (*webhook.Delivery, error)
+
+	ListForPolicy(ctx context.Context, policyID uuid.UUID, status *webhook.DeliveryStatus) ([]webhook.Delivery, error)
+
+	// Replay re-queues a failed delivery for immediate delivery
+	Replay(ctx context.Context, id uuid.UUID) (*webhook.Delivery, error)
+
+	// ReplayFailedForPolicy re-queues all failed deliveries of a policy and returns the number re-queued
+	ReplayFailedForPolicy(ctx context.Context, policyID uuid.UUID) (int, error)
+
+	ListForSubscription(ctx context.Context, subscriptionID uuid.UUID, status *webhook.DeliveryStatus) ([]webhook.Delivery, error)
+
+	// ReplayFailedForSubscription re-queues all failed deliveries of a subscription and returns the number re-queued
+	ReplayFailedForSubscription(ctx context.Context, subscriptionID uuid.UUID) (int, error)
+}
+
+type Options struct {
+	// MaxAttempts is the number of attempts after which a delivery is marked failed
+	MaxAttempts int
+	// BaseBackoff is the delay before the first retry; it doubles on every further attempt
+	BaseBackoff time.Duration
+	// MaxBackoff caps the delay between attempts
+	MaxBackoff time.Duration
+	// AttemptTimeout bounds a single attempt, including resolving its secrets and sending it
+	AttemptTimeout time.Duration
+	// Lease is how long a claimed delivery is withheld from other workers; it must outlast a batch of
+	// attempts and defaults to BatchSize attempts of AttemptTimeout plus a minute
+	Lease time.Duration
+	// BatchSize is the number of deliveries attempted per ProcessDue call
+	BatchSize int
+	Now       func() time.Time
+}
+
+type service struct {
+	repo    Repository
+	secrets SecretResolver
+	sender  Sender
+	opts    Options
+}
+
+func NewService(repo Repository, secrets SecretResolver, sender Sender, opts Options) Service {
+	if opts.MaxAttempts == 0 {
+		opts.MaxAttempts = 8
+	}
+	if opts.BaseBackoff == 0 {
+		opts.BaseBackoff = 30 * time.Second
+	}
+	if opts.MaxBackoff == 0 {
+		opts.MaxBackoff = 6 * time.Hour
+	}
+	if opts.BatchSize == 0 {
+		opts.BatchSize = 50
+	}
+	if opts.AttemptTimeout == 0 {
+		opts.AttemptTimeout = 15 * time.Second
+	}
+	if opts.Lease == 0 {
+		opts.Lease = time.Duration(opts.BatchSize)*opts.AttemptTimeout + time.Minute
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+
+	return &service{repo: repo, secrets: secrets, sender: sender, opts: opts}
+}
+
+func (s *service) Enqueue(ctx context.Context, in EnqueueInput) (*webhook.Delivery, error) {
+	id := uuid.New()
+	in.Envelope.ID = id
+
+	payload, err := json.Marshal(in.Envelope)
+	if err != nil {
+		return nil, fmt.Errorf("marshal webhook envelope: %w", err)
+	}
+
+	return s.repo.Create(ctx, webhook.Delivery{
+		ID:             id,
+		PolicyID:       in.PolicyID,
+		SubscriptionID: in.SubscriptionID,
+		EventID:        in.EventID,
+		EventType:      in.EventType,
+		URL:            in.URL,
+		Payload:        payload,
+		Status:         webhook.DeliveryPending,
+		NextAttemptAt:  s.opts.Now().UTC(),
+	})
+}
+
+func (s *service) ProcessDue(ctx context.Context) (int, error) {
+	due, err := s.repo.ClaimDue(ctx, s.opts.Now().UTC(), s.opts.Lease, s.opts.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, d := range due {
+		result := s.attempt(ctx, d)
+		if err := s.repo.RecordAttempt(ctx, d.ID, result); err != nil {
+			log.Error().Err(err).Msgf("webhook: failed to record attempt for delivery %s", d.ID)
+		}
+	}
+
+	return len(due), nil
+}
+
+// attempt signs and sends a delivery and computes its next state
+func (s *service) attempt(ctx context.Context, d webhook.Delivery) webhook.AttemptResult {
+	ctx, cancel := context.WithTimeout(ctx, s.opts.AttemptTimeout)
+	defer cancel()
+
+	now := s.opts.Now().UTC()
+	result := webhook.AttemptResult{At: now}
+
+	fail := func(msg string) webhook.AttemptResult {
+		result.Error = &msg
+		attempts := d.Attempts + 1
+		if attempts >= s.opts.MaxAttempts {
+			result.Status = webhook.DeliveryFailed
+			result.NextAttemptAt = now
+		} else {
+			result.Status = webhook.DeliveryPending
+			result.NextAttemptAt = now.Add(backoff.Exponential(s.opts.BaseBackoff, s.opts.MaxBackoff, attempts))
+		}
+		return result
+	}
+
+	secrets, err := s.secrets.SecretsFor(ctx, d)
+	if err != nil {
+		return fail(fmt.Sprintf("resolve secret: %v", err))
+	}
+
+	ts := now.Unix()
+	headers := map[string]string{
+		"Content-Type":           "application/json",
+		webhook.HeaderDeliveryID: d.ID.String(),
+		webhook.HeaderEventType:  d.EventType,
+		webhook.HeaderTimestamp:  fmt.Sprint(ts),
+		webhook.HeaderSignature:  webhook.SignAll(secrets, ts, d.Payload),
+	}
+
+	code, err := s.sender.Post(ctx, d.URL, d.Payload, headers)
+	if code != 0 {
+		result.StatusCode = &code
+	}
+	if err != nil {
+		return fail(err.Error())
+	}
+	if code < 200 || code >= 300 {
+		return fail(fmt.Sprintf("unexpected status code %d", code))
+	}
+
+	result.Status = webhook.DeliverySucceeded
+	result.NextAttemptAt = now
+	return result
+}
+
+func (s *service) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.ProcessDue(ctx); err != nil {
+			log.Error().Err(err).Msg("webhook: failed to process due deliveries")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *service) ListForPolicy(ctx context.Context, policyID uuid.UUID, status *webhook.DeliveryStatus) ([]webhook.Delivery, error) {
+	return s.repo.ListForPolicy(ctx, policyID, status)
+}
+
+func (s *service) Get(ctx context.Context, id uuid.UUID) (*webhook.Delivery, error) {
+	return s.repo.Get(ctx, id)
+}
+
+func (s *service) Replay(ctx context.Context, id uuid.UUID) (*webhook.Delivery, error) {
+	d, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if d.Status != webhook.DeliveryFailed {
+		return nil, fmt.Errorf("%w: delivery is %s", ErrNotReplayable, d.Status)
+	}
+
+	if err := s.repo.Requeue(ctx, id, s.opts.Now().UTC()); err != nil {
+		return nil, err
+	}
+	return s.repo.Get(ctx, id)
+}
+
+func (s *service) ReplayFailedForPolicy(ctx context.Context, policyID uuid.UUID) (int, error) {
+	failed := webhook.DeliveryFailed
+	deliveries, err := s.repo.ListForPolicy(ctx, policyID, &failed)
+	if err != nil {
+		return 0, err
+	}
+	return s.requeue(ctx, deliveries)
+}
+
+func (s *service) ListForSubscription(ctx context.Context, subscriptionID uuid.UUID, status *webhook.DeliveryStatus) ([]webhook.Delivery, error) {
+	return s.repo.ListForSubscription(ctx, subscriptionID, status)
+}
+
+func (s *service) ReplayFailedForSubscription(ctx context.Context, subscriptionID uuid.UUID) (int, error) {
+	failed := webhook.DeliveryFailed
+	deliveries, err := s.repo.ListForSubscription(ctx, subscriptionID, &failed)
+	if err != nil {
+		return 0, err
+	}
+	return s.requeue(ctx, deliveries)
+}
+
+// requeue re-queues deliveries for immediate delivery and returns the number re-queued
+func (s *service) requeue(ctx context.Context, deliveries []webhook.Delivery) (int, error) {
+	now := s.opts.Now().UTC()
+	for _, d := range deliveries {
+		if err := s.repo.Requeue(ctx, d.ID, now); err != nil {
+			return 0, err
+		}
+	}
+	return len(deliveries), nil
+}
@@ -0,0 +1,186 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	exwebhook "github.com/SURF-Innovatie/MORIS/external/webhook"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/webhook"
+	"github.com/google/uuid"
+)
+
+type memRepo struct {
+	byID  map[uuid.UUID]*webhook.Delivery
+	lease time.Duration
+}
+
+func (r *memRepo) Create(_ context.Context, d webhook.Delivery) (*webhook.Delivery, error) {
+	r.byID[d.ID] = &d
+	return &d, nil
+}
+
+func (r *memRepo) Get(_ context.Context, id uuid.UUID) (*webhook.Delivery, error) {
+	d := *r.byID[id]
+	return &d, nil
+}
+
+func (r *memRepo) ClaimDue(_ context.Context, now time.Time, lease time.Duration, _ int) ([]webhook.Delivery, error) {
+	r.lease = lease
+	var out []webhook.Delivery
+	for _, d := range r.byID {
+		if d.Status == webhook.DeliveryPending && !d.NextAttemptAt.After(now) {
+			out = append(out, *d)
+			d.NextAttemptAt = now.Add(lease)
+		}
+	}
+	return out, nil
+}
+
+func (r *memRepo) RecordAttempt(_ context.Context, id uuid.UUID, res webhook.AttemptResult) error {
+	d := r.byID[id]
+	d.Attempts++
+	d.Status = res.Status
+	d.LastAttemptAt = &res.At
+	d.LastStatusCode = res.StatusCode
+	d.LastError = res.Error
+	d.NextAttemptAt = res.NextAttemptAt
+	return nil
+}
+
+func (r *memRepo) Requeue(_ context.Context, id uuid.UUID, at time.Time) error {
+	d := r.byID[id]
+	d.Status = webhook.DeliveryPending
+	d.Attempts = 0
+	d.NextAttemptAt = at
+	return nil
+}
+
+func (r *memRepo) ListForPolicy(_ context.Context, policyID uuid.UUID, status *webhook.DeliveryStatus) ([]webhook.Delivery, error) {
+	var out []webhook.Delivery
+	for _, d := range r.byID {
+		if d.PolicyID != nil && *d.PolicyID == policyID && (status == nil || d.Status == *status) {
+			out = append(out, *d)
+		}
+	}
+	return out, nil
+}
+
+func (r *memRepo) ListForSubscription(_ context.Context, subscriptionID uuid.UUID, status *webhook.DeliveryStatus) ([]webhook.Delivery, error) {
+	var out []webhook.Delivery
+	for _, d := range r.byID {
+		if d.SubscriptionID != nil && *d.SubscriptionID == subscriptionID && (status == nil || d.Status == *status) {
+			out = append(out, *d)
+		}
+	}
+	return out, nil
+}
+
+type staticSecret string
+
+func (s staticSecret) SecretsFor(context.Context, webhook.Delivery) ([]string, error) {
+	return []string{string(s)}, nil
+}
+
+func TestService_RetriesWithBackoffAndReplay(t *testing.T) {
+	ctx := context.Background()
+	const secret = "0123456789abcdef"
+
+	failing := true
+	var lastSignatureValid bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ts, _ := strconv.ParseInt(r.Header.Get(webhook.HeaderTimestamp), 10, 64)
+		lastSignatureValid = webhook.Verify(secret, ts, body, r.Header.Get(webhook.HeaderSignature))
+		if failing {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := &memRepo{byID: map[uuid.UUID]*webhook.Delivery{}}
+	svc := NewService(repo, staticSecret(secret), exwebhook.NewClientWithHTTP(srv.Client()), Options{
+		MaxAttempts: 3,
+		BaseBackoff: time.Minute,
+		Now:         func() time.Time { return now },
+	})
+
+	policyID := uuid.New()
+	d, err := svc.Enqueue(ctx, EnqueueInput{
+		PolicyID:  &policyID,
+		EventType: "project.title_changed",
+		URL:       srv.URL,
+		Envelope:  webhook.Envelope{Type: webhook.EnvelopeTypePolicyTriggered},
+	})
+	if err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+
+	// First attempt fails and is retried after the base backoff
+	if n, _ := svc.ProcessDue(ctx); n != 1 {
+		t.Fatalf("expected 1 attempt, got %d", n)
+	}
+	if !lastSignatureValid {
+		t.Fatal("signature did not verify")
+	}
+	got := repo.byID[d.ID]
+	if got.Status != webhook.DeliveryPending || !got.NextAttemptAt.Equal(now.Add(time.Minute)) {
+		t.Fatalf("unexpected state after first attempt: %+v", got)
+	}
+	if got.LastStatusCode == nil || *got.LastStatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected status code to be recorded, got %v", got.LastStatusCode)
+	}
+
+	// Not due yet
+	if n, _ := svc.ProcessDue(ctx); n != 0 {
+		t.Fatalf("expected no due deliveries, got %d", n)
+	}
+
+	// Second attempt doubles the backoff, third marks the delivery failed
+	now = now.Add(time.Minute)
+	_, _ = svc.ProcessDue(ctx)
+	if want := now.Add(2 * time.Minute); !repo.byID[d.ID].NextAttemptAt.Equal(want) {
+		t.Fatalf("expected next attempt at %s, got %s", want, repo.byID[d.ID].NextAttemptAt)
+	}
+	now = now.Add(2 * time.Minute)
+	_, _ = svc.ProcessDue(ctx)
+	if repo.byID[d.ID].Status != webhook.DeliveryFailed {
+		t.Fatalf("expected delivery to be failed, got %s", repo.byID[d.ID].Status)
+	}
+
+	// Replaying the policy's failed deliveries succeeds once the endpoint recovers
+	failing = false
+	if n, err := svc.ReplayFailedForPolicy(ctx, policyID); err != nil || n != 1 {
+		t.Fatalf("replay: n=%d err=%v", n, err)
+	}
+	_, _ = svc.ProcessDue(ctx)
+	if repo.byID[d.ID].Status != webhook.DeliverySucceeded {
+		t.Fatalf("expected delivery to succeed after replay, got %s", repo.byID[d.ID].Status)
+	}
+
+	if _, err := svc.Replay(ctx, d.ID); err == nil {
+		t.Fatal("expected replay of a succeeded delivery to be rejected")
+	}
+}
+
+func TestService_LeaseOutlastsBatch(t *testing.T) {
+	repo := &memRepo{byID: map[uuid.UUID]*webhook.Delivery{}}
+	svc := NewService(repo, staticSecret("0123456789abcdef"), exwebhook.NewClient(), Options{
+		BatchSize:      20,
+		AttemptTimeout: 10 * time.Second,
+	})
+
+	if _, err := svc.ProcessDue(context.Background()); err != nil {
+		t.Fatalf("process: %v", err)
+	}
+	if want := 20*10*time.Second + time.Minute; repo.lease != want {
+		t.Fatalf("expected lease %s, got %s", want, repo.lease)
+	}
+}
@@ -0,0 +1,141 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/internal/domain/project"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/project/events"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/webhook"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// SubscriptionService manages webhook subscriptions and queues their deliveries
+type SubscriptionService interface {
+	// Create stores a subscription with a newly generated secret, which is returned only here and by RotateSecret
+	Create(ctx context.Context, s webhook.Subscription) (*webhook.Subscription, error)
+	Get(ctx context.Context, id uuid.UUID) (*webhook.Subscription, error)
+	Update(ctx context.Context, id uuid.UUID, s webhook.Subscription) (*webhook.Subscription, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	ListForOrgNode(ctx context.Context, orgNodeID uuid.UUID) ([]webhook.Subscription, error)
+
+	// RotateSecret replaces the signing secret. For webhook.SecretGracePeriod deliveries, including those still
+	// pending, carry a signature with the replaced secret as well, so receivers can switch without missing any.
+	RotateSecret(ctx context.Context, id uuid.UUID) (*webhook.Subscription, error)
+
+	// Dispatch queues a delivery of the event to every enabled subscription that covers the project
+	// and returns the number of deliveries queued. Private projects are never delivered: only their
+	// members may read them, which a subscription on an organisation node does not make anyone.
+	Dispatch(ctx context.Context, event events.Event, proj *project.Project) (int, error)
+}
+
+type subscriptionService struct {
+	repo      SubscriptionRepository
+	webhooks  Service
+	hierarchy OrgHierarchy
+	now       func() time.Time
+}
+
+func NewSubscriptionService(repo SubscriptionRepository, webhooks Service, hierarchy OrgHierarchy) SubscriptionService {
+	return &subscriptionService{repo: repo, webhooks: webhooks, hierarchy: hierarchy, now: time.Now}
+}
+
+func (s *subscriptionService) Create(ctx context.Context, sub webhook.Subscription) (*webhook.Subscription, error) {
+	if err := sub.Validate(); err != nil {
+		return nil, err
+	}
+
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		return nil, err
+	}
+	sub.ID = uuid.New()
+	sub.Secret = secret
+	sub.SecretRotatedAt = s.now().UTC()
+	return s.repo.Create(ctx, sub)
+}
+
+func (s *subscriptionService) Get(ctx context.Context, id uuid.UUID) (*webhook.Subscription, error) {
+	return s.repo.Get(ctx, id)
+}
+
+func (s *subscriptionService) Update(ctx context.Context, id uuid.UUID, sub webhook.Subscription) (*webhook.Subscription, error) {
+	existing, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// The scope and secret are not changed by an update
+	sub.ID = existing.ID
+	sub.OrgNodeID = existing.OrgNodeID
+	if err := sub.Validate(); err != nil {
+		return nil, err
+	}
+	return s.repo.Update(ctx, sub)
+}
+
+func (s *subscriptionService) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *subscriptionService) ListForOrgNode(ctx context.Context, orgNodeID uuid.UUID) ([]webhook.Subscription, error) {
+	return s.repo.ListForOrgNode(ctx, orgNodeID)
+}
+
+func (s *subscriptionService) RotateSecret(ctx context.Context, id uuid.UUID) (*webhook.Subscription, error) {
+	existing, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		return nil, err
+	}
+	now := s.now().UTC()
+	return s.repo.SetSecret(ctx, id, secret, now, existing.Secret, now.Add(webhook.SecretGracePeriod))
+}
+
+func (s *subscriptionService) Dispatch(ctx context.Context, event events.Event, proj *project.Project) (int, error) {
+	if proj == nil || proj.OwningOrgNodeID == uuid.Nil || proj.EffectiveVisibility() == project.VisibilityPrivate {
+		return 0, nil
+	}
+
+	ancestorIDs, err := s.hierarchy.AncestorIDsInclusive(ctx, proj.OwningOrgNodeID)
+	if err != nil {
+		return 0, err
+	}
+	subs, err := s.repo.ListEnabledForOrgNodes(ctx, ancestorIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	queued := 0
+	eventID := event.GetID()
+	for _, sub := range subs {
+		if !sub.Covers(proj.OwningOrgNodeID, ancestorIDs) || !sub.MatchesEventType(event.Type()) {
+			continue
+		}
+
+		subscriptionID := sub.ID
+		_, err := s.webhooks.Enqueue(ctx, EnqueueInput{
+			SubscriptionID: &subscriptionID,
+			EventID:        &eventID,
+			EventType:      event.Type(),
+			URL:            sub.URL,
+			Envelope: webhook.Envelope{
+				Type:         webhook.EnvelopeTypeSubscription,
+				OccurredAt:   event.OccurredAt(),
+				Event:        webhook.NewEventSnapshot(event),
+				Project:      webhook.NewProjectSnapshot(proj),
+				Subscription: webhook.NewSubscriptionSnapshot(sub),
+			},
+		})
+		if err != nil {
+			log.Error().Err(err).Msgf("webhook: failed to queue event %s for subscription %s", eventID, sub.ID)
+			continue
+		}
+		queued++
+	}
+	return queued, nil
+}
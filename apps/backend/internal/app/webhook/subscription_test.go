@@ -0,0 +1,200 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/internal/domain/project"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/project/events"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/webhook"
+	"github.com/google/uuid"
+)
+
+type memSubscriptionRepo struct {
+	byID map[uuid.UUID]*webhook.Subscription
+}
+
+func (r *memSubscriptionRepo) Create(_ context.Context, s webhook.Subscription) (*webhook.Subscription, error) {
+	stored := s
+	r.byID[s.ID] = &stored
+	return &s, nil
+}
+
+func (r *memSubscriptionRepo) Get(_ context.Context, id uuid.UUID) (*webhook.Subscription, error) {
+	s, ok := r.byID[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	cp := *s
+	return &cp, nil
+}
+
+func (r *memSubscriptionRepo) Update(_ context.Context, s webhook.Subscription) (*webhook.Subscription, error) {
+	existing := r.byID[s.ID]
+	s.Secret, s.SecretRotatedAt = existing.Secret, existing.SecretRotatedAt
+	stored := s
+	r.byID[s.ID] = &stored
+	return &s, nil
+}
+
+func (r *memSubscriptionRepo) SetSecret(_ context.Context, id uuid.UUID, secret string, at time.Time, previous string, previousExpiresAt time.Time) (*webhook.Subscription, error) {
+	s := r.byID[id]
+	s.Secret, s.SecretRotatedAt = secret, at
+	s.PreviousSecret, s.PreviousSecretExpiresAt = &previous, &previousExpiresAt
+	cp := *s
+	return &cp, nil
+}
+
+func (r *memSubscriptionRepo) Delete(_ context.Context, id uuid.UUID) error {
+	delete(r.byID, id)
+	return nil
+}
+
+func (r *memSubscriptionRepo) ListForOrgNode(_ context.Context, orgNodeID uuid.UUID) ([]webhook.Subscription, error) {
+	var out []webhook.Subscription
+	for _, s := range r.byID {
+		if s.OrgNodeID == orgNodeID {
+			out = append(out, *s)
+		}
+	}
+	return out, nil
+}
+
+func (r *memSubscriptionRepo) ListEnabledForOrgNodes(_ context.Context, orgNodeIDs []uuid.UUID) ([]webhook.Subscription, error) {
+	var out []webhook.Subscription
+	for _, s := range r.byID {
+		if s.Enabled && slices.Contains(orgNodeIDs, s.OrgNodeID) {
+			out = append(out, *s)
+		}
+	}
+	return out, nil
+}
+
+// staticHierarchy maps a node to its ancestors, excluding the node itself
+type staticHierarchy map[uuid.UUID][]uuid.UUID
+
+func (h staticHierarchy) AncestorIDsInclusive(_ context.Context, nodeID uuid.UUID) ([]uuid.UUID, error) {
+	return append([]uuid.UUID{nodeID}, h[nodeID]...), nil
+}
+
+func TestSubscriptionService_Dispatch(t *testing.T) {
+	ctx := context.Background()
+	root, faculty, department := uuid.New(), uuid.New(), uuid.New()
+	hierarchy := staticHierarchy{faculty: {root}, department: {faculty, root}}
+
+	deliveries := &memRepo{byID: map[uuid.UUID]*webhook.Delivery{}}
+	webhooks := NewService(deliveries, staticSecret("0123456789abcdef"), nil, Options{})
+	subs := &memSubscriptionRepo{byID: map[uuid.UUID]*webhook.Subscription{}}
+	svc := NewSubscriptionService(subs, webhooks, hierarchy)
+
+	create := func(name string, node uuid.UUID, descendants bool, types ...string) *webhook.Subscription {
+		t.Helper()
+		s, err := svc.Create(ctx, webhook.Subscription{
+			Name: name, OrgNodeID: node, IncludeDescendants: descendants,
+			EventTypes: types, URL: "https://example.org/hook", Enabled: true,
+		})
+		if err != nil {
+			t.Fatalf("create %s: %v", name, err)
+		}
+		return s
+	}
+
+	everything := create("root, all events", root, true)
+	titles := create("faculty titles", faculty, true, events.TitleChangedType)
+	create("root only", root, false)
+	create("department descriptions", department, true, events.DescriptionChangedType)
+	disabled := create("disabled", root, true)
+	disabled.Enabled = false
+	if _, err := svc.Update(ctx, disabled.ID, *disabled); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(everything.Secret, "whsec_") || everything.Secret == titles.Secret {
+		t.Fatalf("expected unique generated secrets, got %q and %q", everything.Secret, titles.Secret)
+	}
+
+	projectID := uuid.New()
+	evt := &events.TitleChanged{Title: "New"}
+	evt.ID, evt.ProjectID, evt.At, evt.Status = uuid.New(), projectID, time.Now(), events.StatusApproved
+	proj := &project.Project{Id: projectID, Title: "New", OwningOrgNodeID: department}
+
+	n, err := svc.Dispatch(ctx, evt, proj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatalf("expected deliveries for the root and faculty subscriptions, got %d", n)
+	}
+
+	for _, sub := range []*webhook.Subscription{everything, titles} {
+		got, _ := webhooks.ListForSubscription(ctx, sub.ID, nil)
+		if len(got) != 1 {
+			t.Fatalf("%s: expected 1 delivery, got %d", sub.Name, len(got))
+		}
+		var env struct {
+			Type         string
+			Event        struct{ ID uuid.UUID }
+			Project      *struct{ ID uuid.UUID }
+			Subscription *webhook.SubscriptionSnapshot
+		}
+		if err := json.Unmarshal(got[0].Payload, &env); err != nil {
+			t.Fatal(err)
+		}
+		if env.Type != webhook.EnvelopeTypeSubscription || env.Subscription == nil || env.Subscription.ID != sub.ID {
+			t.Fatalf("%s: unexpected envelope %+v", sub.Name, env)
+		}
+		if env.Project == nil || env.Project.ID != projectID || env.Event.ID != evt.ID {
+			t.Fatalf("%s: envelope does not describe the event and project", sub.Name)
+		}
+	}
+
+	// Private projects are not delivered to anyone
+	private := &project.Project{Id: uuid.New(), Title: "Secret", OwningOrgNodeID: department, Visibility: project.VisibilityPrivate}
+	if n, err := svc.Dispatch(ctx, evt, private); err != nil || n != 0 {
+		t.Fatalf("expected no deliveries for a private project, got %d (%v)", n, err)
+	}
+
+	rotated, err := svc.RotateSecret(ctx, titles.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rotated.Secret == titles.Secret {
+		t.Fatal("expected a new secret after rotation")
+	}
+
+	// The replaced secret keeps signing deliveries during the grace period only
+	rotatedAt := rotated.SecretRotatedAt
+	if got := rotated.SigningSecrets(rotatedAt.Add(time.Hour)); len(got) != 2 || got[0] != rotated.Secret || got[1] != titles.Secret {
+		t.Fatalf("expected the new and the old secret during the grace period, got %d secrets", len(got))
+	}
+	if got := rotated.SigningSecrets(rotatedAt.Add(webhook.SecretGracePeriod)); len(got) != 1 || got[0] != rotated.Secret {
+		t.Fatalf("expected only the new secret after the grace period, got %d secrets", len(got))
+	}
+	signature := webhook.SignAll(rotated.SigningSecrets(rotatedAt), 1, []byte("{}"))
+	if !webhook.Verify(titles.Secret, 1, []byte("{}"), signature) || !webhook.Verify(rotated.Secret, 1, []byte("{}"), signature) {
+		t.Fatalf("expected both secrets to verify %q", signature)
+	}
+}
+
+func TestSubscriptionService_Validates(t *testing.T) {
+	svc := NewSubscriptionService(&memSubscriptionRepo{byID: map[uuid.UUID]*webhook.Subscription{}}, nil, staticHierarchy{})
+
+	cases := map[string]webhook.Subscription{
+		"no name":            {OrgNodeID: uuid.New(), URL: "https://example.org"},
+		"no node":            {Name: "x", URL: "https://example.org"},
+		"relative url":       {Name: "x", OrgNodeID: uuid.New(), URL: "/hook"},
+		"plain http":         {Name: "x", OrgNodeID: uuid.New(), URL: "http://example.org/hook"},
+		"internal address":   {Name: "x", OrgNodeID: uuid.New(), URL: "https://169.254.169.254/latest"},
+		"unknown event type": {Name: "x", OrgNodeID: uuid.New(), URL: "https://example.org", EventTypes: []string{"project.nope"}},
+	}
+	for name, s := range cases {
+		if _, err := svc.Create(context.Background(), s); !errors.Is(err, webhook.ErrInvalidSubscription) {
+			t.Errorf("%s: expected ErrInvalidSubscription, got %v", name, err)
+		}
+	}
+}
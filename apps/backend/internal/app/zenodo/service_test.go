@@ -9,6 +9,7 @@ import (
 
 	ext "github.com/SURF-Innovatie/MORIS/external/zenodo"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/identity"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/locale"
 	"github.com/google/uuid"
 )
 
@@ -68,6 +69,14 @@ func (r *fakeUserRepo) ClearZenodoTokens(_ context.Context, userID uuid.UUID) er
 	return nil
 }
 
+func (r *fakeUserRepo) SetLanguage(context.Context, uuid.UUID, locale.Language) error {
+	return errors.New("not implemented")
+}
+
+func (r *fakeUserRepo) LanguagesByIDs(context.Context, []uuid.UUID) (map[uuid.UUID]locale.Language, error) {
+	return nil, errors.New("not implemented")
+}
+
 type fakeZenodoClient struct {
 	authURL string
 
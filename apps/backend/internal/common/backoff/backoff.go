@@ -0,0 +1,18 @@
+// Package backoff computes retry delays for work that is attempted again later, such as webhook
+// deliveries and notification emails.
+package backoff
+
+import "time"
+
+// Exponential returns the delay before the next attempt after the given number of attempts: base
+// after the first attempt, doubling on every further attempt and capped at max
+func Exponential(base, max time.Duration, attempts int) time.Duration {
+	d := base
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= max {
+			return max
+		}
+	}
+	return d
+}
@@ -0,0 +1,23 @@
+package backoff_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/internal/common/backoff"
+)
+
+func TestExponential(t *testing.T) {
+	cases := map[int]time.Duration{
+		1: time.Minute,
+		2: 2 * time.Minute,
+		3: 4 * time.Minute,
+		4: 5 * time.Minute,
+		9: 5 * time.Minute,
+	}
+	for attempts, want := range cases {
+		if got := backoff.Exponential(time.Minute, 5*time.Minute, attempts); got != want {
+			t.Errorf("attempt %d: expected %s, got %s", attempts, want, got)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package netguard_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/internal/common/netguard"
+)
+
+func TestValidateURL(t *testing.T) {
+	valid := []string{
+		"https://example.org/hook",
+		"https://93.184.216.34:8443/hook",
+		"https://[2606:2800:220:1::1]/hook",
+	}
+	for _, raw := range valid {
+		if err := netguard.ValidateURL(raw); err != nil {
+			t.Errorf("%s: unexpected error %v", raw, err)
+		}
+	}
+
+	invalid := map[string]error{
+		"http://example.org/hook":         netguard.ErrInvalidURL,
+		"/hook":                           netguard.ErrInvalidURL,
+		"https://user:pw@example.org":     netguard.ErrInvalidURL,
+		"https://localhost/hook":          netguard.ErrForbiddenAddress,
+		"https://127.0.0.1/hook":          netguard.ErrForbiddenAddress,
+		"https://10.0.0.8/hook":           netguard.ErrForbiddenAddress,
+		"https://192.168.1.1/hook":        netguard.ErrForbiddenAddress,
+		"https://169.254.169.254/latest":  netguard.ErrForbiddenAddress,
+		"https://[::1]/hook":              netguard.ErrForbiddenAddress,
+		"https://[fe80::1]/hook":          netguard.ErrForbiddenAddress,
+		"https://[::ffff:127.0.0.1]/hook": netguard.ErrForbiddenAddress,
+		"https://100.64.0.1/hook":         netguard.ErrForbiddenAddress,
+		"https://192.0.0.170/hook":        netguard.ErrForbiddenAddress,
+		"https://198.18.0.1/hook":         netguard.ErrForbiddenAddress,
+		"https://255.255.255.255/hook":    netguard.ErrForbiddenAddress,
+		"https://[64:ff9b::a00:1]/hook":   netguard.ErrForbiddenAddress,
+		"https://[2002:a00:1::1]/hook":    netguard.ErrForbiddenAddress,
+		"https://[2001:0:a00:1::1]/hook":  netguard.ErrForbiddenAddress,
+	}
+	for raw, want := range invalid {
+		if err := netguard.ValidateURL(raw); !errors.Is(err, want) {
+			t.Errorf("%s: expected %v, got %v", raw, want, err)
+		}
+	}
+}
+
+func TestHTTPClientRefusesInternalAddresses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodPost, srv.URL, nil)
+	_, err := netguard.NewHTTPClient(5 * time.Second).Do(req)
+	if !errors.Is(err, netguard.ErrForbiddenAddress) {
+		t.Fatalf("expected ErrForbiddenAddress dialing %s, got %v", srv.URL, err)
+	}
+}
@@ -0,0 +1,144 @@
+package audit
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/ent"
+	"github.com/google/uuid"
+)
+
+// ErrInvalidCursor is returned for a malformed pagination cursor
+var ErrInvalidCursor = errors.New("invalid_audit_cursor")
+
+// EntityType is the kind of organisation configuration an entry is about
+type EntityType string
+
+const (
+	EntityOrganisationRole       EntityType = "organisation_role"
+	EntityRoleScope              EntityType = "role_scope"
+	EntityOrganisationMembership EntityType = "organisation_membership"
+	EntityCustomFieldDefinition  EntityType = "custom_field_definition"
+	EntityProjectRole            EntityType = "project_role"
+	EntityProvisioningRule       EntityType = "provisioning_rule"
+)
+
+func (t EntityType) Valid() bool {
+	switch t {
+	case EntityOrganisationRole, EntityRoleScope, EntityOrganisationMembership, EntityCustomFieldDefinition, EntityProjectRole, EntityProvisioningRule:
+		return true
+	default:
+		return false
+	}
+}
+
+// Action is what happened to the entity
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+func (a Action) Valid() bool {
+	switch a {
+	case ActionCreate, ActionUpdate, ActionDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// Change describes a mutation to record. Before is nil for creations and After is nil for deletions.
+type Change struct {
+	OrgNodeID  uuid.UUID
+	EntityType EntityType
+	EntityID   uuid.UUID
+	Action     Action
+	Before     any
+	After      any
+}
+
+// Entry is a recorded change. ActorUserID is nil for changes made by the system.
+type Entry struct {
+	ID          uuid.UUID
+	OrgNodeID   uuid.UUID
+	ActorUserID *uuid.UUID
+	ActorName   string
+	EntityType  EntityType
+	EntityID    uuid.UUID
+	Action      Action
+	Before      json.RawMessage
+	After       json.RawMessage
+	CreatedAt   time.Time
+}
+
+func (e *Entry) FromEnt(row *ent.AuditLogEntry) *Entry {
+	return &Entry{
+		ID:          row.ID,
+		OrgNodeID:   row.OrganisationNodeID,
+		ActorUserID: row.ActorUserID,
+		EntityType:  EntityType(row.EntityType),
+		EntityID:    row.EntityID,
+		Action:      Action(row.Action),
+		Before:      row.Before,
+		After:       row.After,
+		CreatedAt:   row.CreatedAt,
+	}
+}
+
+// Cursor points at the last entry of a page. Entries are ordered newest first,
+// by creation time and then by ID so that the order is stable for equal times.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// Encode returns the opaque string form of the cursor
+func (c Cursor) Encode() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a cursor produced by Encode
+func DecodeCursor(s string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	at, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, at)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	uid, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &Cursor{CreatedAt: createdAt, ID: uid}, nil
+}
+
+// ListFilter selects a page of the audit log of an organisation node and its descendants
+type ListFilter struct {
+	EntityType  *EntityType
+	EntityID    *uuid.UUID
+	Action      *Action
+	ActorUserID *uuid.UUID
+	From        *time.Time // inclusive
+	To          *time.Time // exclusive
+	After       *Cursor
+	Limit       int
+}
+
+// Page is a page of entries; NextCursor is nil on the last page
+type Page struct {
+	Items      []Entry
+	NextCursor *Cursor
+}
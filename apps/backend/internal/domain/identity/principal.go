@@ -1,10 +1,38 @@
 package identity
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
 
 // Principal represents the authenticated actor for the current request.
 type Principal struct {
 	UserID     uuid.UUID
 	PersonID   uuid.UUID
 	IsSysAdmin bool
+	// NodeIDs limits the organisation nodes the request may act on, descendants included. It is
+	// nil unless the request authenticates with an API token that is limited to nodes.
+	NodeIDs []uuid.UUID
+	// ImpersonatorUserID and ImpersonatorPersonID identify the sysadmin acting as the user. They
+	// are nil unless the request authenticates with an impersonation token.
+	ImpersonatorUserID   *uuid.UUID
+	ImpersonatorPersonID *uuid.UUID
+}
+
+// Impersonated reports whether a sysadmin acts as the user
+func (p Principal) Impersonated() bool {
+	return p.ImpersonatorUserID != nil
+}
+
+// ActorUserID returns the user who really makes the request: the impersonator when impersonating
+func (p Principal) ActorUserID() uuid.UUID {
+	if p.ImpersonatorUserID != nil {
+		return *p.ImpersonatorUserID
+	}
+	return p.UserID
+}
+
+// CanAccessNode reports whether the request may act on the organisation node
+func (p Principal) CanAccessNode(nodeID uuid.UUID) bool {
+	return p.NodeIDs == nil || lo.Contains(p.NodeIDs, nodeID)
 }
@@ -0,0 +1,16 @@
+package readmodels
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Impersonation describes the session of a request in which a sysadmin acts as another user
+type Impersonation struct {
+	SessionID    uuid.UUID
+	Impersonator UserAccount
+	Reason       string
+	StartedAt    time.Time
+	ExpiresAt    time.Time
+}
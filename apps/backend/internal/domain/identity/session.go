@@ -0,0 +1,68 @@
+package identity
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/ent"
+	"github.com/google/uuid"
+)
+
+// Session is a signed-in device of a user. Access tokens carry the session ID, so revoking the
+// session invalidates them together with its refresh token.
+type Session struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	UserAgent  *string
+	IPAddress  *string
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+	ExpiresAt  time.Time
+	RevokedAt  *time.Time
+	// ImpersonatorUserID is the sysadmin acting as the user, for impersonation sessions
+	ImpersonatorUserID  *uuid.UUID
+	ImpersonationReason *string
+}
+
+func (s *Session) FromEnt(row *ent.Session) *Session {
+	return &Session{
+		ID:         row.ID,
+		UserID:     row.UserID,
+		UserAgent:  row.UserAgent,
+		IPAddress:  row.IPAddress,
+		CreatedAt:  row.CreatedAt,
+		LastUsedAt: row.LastUsedAt,
+		ExpiresAt:  row.ExpiresAt,
+		RevokedAt:  row.RevokedAt,
+
+		ImpersonatorUserID:  row.ImpersonatorUserID,
+		ImpersonationReason: row.ImpersonationReason,
+	}
+}
+
+// Impersonated reports whether a sysadmin acts as the user in the session
+func (s Session) Impersonated() bool {
+	return s.ImpersonatorUserID != nil
+}
+
+// ActiveAt reports whether the session is neither revoked nor expired at t
+func (s Session) ActiveAt(t time.Time) bool {
+	return s.RevokedAt == nil && t.Before(s.ExpiresAt)
+}
+
+// NewRefreshToken generates a random refresh token
+func NewRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// HashRefreshToken returns the stored form of a refresh token
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
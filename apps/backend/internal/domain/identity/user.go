@@ -2,6 +2,7 @@ package identity
 
 import (
 	"github.com/SURF-Innovatie/MORIS/ent"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/locale"
 	"github.com/google/uuid"
 )
 
@@ -9,8 +10,10 @@ type User struct {
 	ID                 uuid.UUID
 	PersonID           uuid.UUID
 	Password           string
-	IsSysAdmin         bool `json:"is_sys_admin"`
-	IsActive           bool `json:"is_active"`
+	IsSysAdmin         bool            `json:"is_sys_admin"`
+	IsActive           bool            `json:"is_active"`
+	IsServiceAccount   bool            `json:"is_service_account"`
+	Language           locale.Language `json:"language"`
 	ZenodoAccessToken  *string
 	ZenodoRefreshToken *string
 }
@@ -21,6 +24,8 @@ func (u *User) FromEnt(row *ent.User) *User {
 		PersonID:           row.PersonID,
 		IsSysAdmin:         row.IsSysAdmin,
 		IsActive:           row.IsActive,
+		IsServiceAccount:   row.IsServiceAccount,
+		Language:           locale.Language(row.Language),
 		ZenodoAccessToken:  &row.ZenodoAccessToken,
 		ZenodoRefreshToken: &row.ZenodoRefreshToken,
 	}
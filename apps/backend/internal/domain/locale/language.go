@@ -0,0 +1,75 @@
+package locale
+
+import (
+	"errors"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Language is a supported interface language, identified by its ISO 639-1 code
+type Language string
+
+const (
+	English Language = "en"
+	Dutch   Language = "nl"
+)
+
+// Default is used when no (supported) language is known. All texts exist in the default language.
+const Default = English
+
+// Supported lists the languages texts are available in
+var Supported = []Language{English, Dutch}
+
+// ErrUnsupportedLanguage is returned when a language code is not supported
+var ErrUnsupportedLanguage = errors.New("unsupported_language")
+
+// Parse returns the supported language for a code such as "nl" or "nl-BE"
+func Parse(code string) (Language, error) {
+	base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(code)), "-")
+	if l := Language(base); slices.Contains(Supported, l) {
+		return l, nil
+	}
+	return "", ErrUnsupportedLanguage
+}
+
+// OrDefault returns the language, or the default language when it is empty or unsupported
+func OrDefault(code string) Language {
+	if l, err := Parse(code); err == nil {
+		return l
+	}
+	return Default
+}
+
+// FromAcceptLanguage picks the supported language with the highest quality from an
+// Accept-Language header, e.g. "nl-NL,nl;q=0.9,en;q=0.8". It falls back to the default language.
+func FromAcceptLanguage(header string) Language {
+	type candidate struct {
+		lang    Language
+		quality float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			v, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = v
+		}
+		if l, err := Parse(tag); err == nil && quality > 0 {
+			candidates = append(candidates, candidate{lang: l, quality: quality})
+		}
+	}
+	if len(candidates) == 0 {
+		return Default
+	}
+
+	// Stable so that equal qualities keep the client's order
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].quality > candidates[j].quality })
+	return candidates[0].lang
+}
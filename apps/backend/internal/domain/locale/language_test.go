@@ -0,0 +1,36 @@
+package locale_test
+
+import (
+	"testing"
+
+	"github.com/SURF-Innovatie/MORIS/internal/domain/locale"
+)
+
+func TestFromAcceptLanguage(t *testing.T) {
+	cases := map[string]locale.Language{
+		"":                           locale.English,
+		"nl":                         locale.Dutch,
+		"nl-NL,nl;q=0.9,en;q=0.8":    locale.Dutch,
+		"en-US,en;q=0.9,nl;q=0.8":    locale.English,
+		"de-DE,de;q=0.9,nl;q=0.5":    locale.Dutch,
+		"fr;q=1.0,en;q=0.2,nl;q=0.7": locale.Dutch,
+		"nl;q=0,en":                  locale.English,
+		"de,fr":                      locale.English,
+		"NL-be":                      locale.Dutch,
+		"nl;q=oops,en;q=0.1":         locale.English,
+	}
+	for header, want := range cases {
+		if got := locale.FromAcceptLanguage(header); got != want {
+			t.Errorf("FromAcceptLanguage(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	if l, err := locale.Parse("nl-BE"); err != nil || l != locale.Dutch {
+		t.Fatalf("expected nl, got %q (%v)", l, err)
+	}
+	if _, err := locale.Parse("de"); err != locale.ErrUnsupportedLanguage {
+		t.Fatalf("expected ErrUnsupportedLanguage, got %v", err)
+	}
+}
@@ -15,6 +15,16 @@ const (
 	NotificationStatusUpdate    NotificationType = "status_update"
 )
 
+// EmailStatus tracks the email delivery of a notification
+type EmailStatus string
+
+const (
+	EmailPending EmailStatus = "pending"
+	EmailSent    EmailStatus = "sent"
+	EmailBounced EmailStatus = "bounced" // permanently rejected by the mail server
+	EmailFailed  EmailStatus = "failed"
+)
+
 type Notification struct {
 	ID                uuid.UUID
 	UserID            uuid.UUID
@@ -24,23 +34,57 @@ type Notification struct {
 	Type              NotificationType
 	Read              bool
 	SentAt            time.Time
+	ArchivedAt        *time.Time
+	EventType         *string // optional, derived from event
 	EventFriendlyName *string
+	Variables         map[string]string // template variables the message was rendered from
+	EmailStatus       *EmailStatus      // nil when the notification is not emailed
+	EmailDigest       *DigestFrequency  // set when the email waits for a daily or weekly digest
+	EmailError        *string
+	EmailSentAt       *time.Time
+	EmailAttempts     int
+	// EmailNextAttemptAt holds back a pending email that is being sent or is waiting for a retry
+	EmailNextAttemptAt *time.Time
+}
+
+// EmailAttempt records the outcome of an email delivery attempt
+type EmailAttempt struct {
+	Status        EmailStatus
+	Error         *string
+	SentAt        *time.Time
+	NextAttemptAt *time.Time // set when a transient failure is retried
 }
 
 func (n *Notification) FromEnt(row *ent.Notification) *Notification {
 	out := &Notification{
-		ID:      row.ID,
-		Message: row.Message,
-		Type:    NotificationType(row.Type.String()),
-		Read:    row.Read,
-		SentAt:  row.SentAt,
-		UserID:  row.UserID,
+		ID:          row.ID,
+		Message:     row.Message,
+		Type:        NotificationType(row.Type.String()),
+		Read:        row.Read,
+		SentAt:      row.SentAt,
+		ArchivedAt:  row.ArchivedAt,
+		UserID:      row.UserID,
+		Variables:   row.Variables,
+		EmailError:  row.EmailError,
+		EmailSentAt: row.EmailSentAt,
+
+		EmailAttempts:      row.EmailAttempts,
+		EmailNextAttemptAt: row.EmailNextAttemptAt,
+	}
+	if row.EmailStatus != nil {
+		status := EmailStatus(row.EmailStatus.String())
+		out.EmailStatus = &status
+	}
+	if row.EmailDigest != nil {
+		digest := DigestFrequency(row.EmailDigest.String())
+		out.EmailDigest = &digest
 	}
 	if row.EventID != nil {
 		out.EventID = row.EventID
 	}
 	if row.Edges.Event != nil {
 		out.ProjectID = &row.Edges.Event.ProjectID
+		out.EventType = &row.Edges.Event.Type
 	}
 	return out
 }
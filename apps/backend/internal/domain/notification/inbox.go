@@ -0,0 +1,73 @@
+package notification
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrNotFound is returned when a notification does not exist or belongs to another user
+	ErrNotFound = errors.New("notification_not_found")
+	// ErrInvalidCursor is returned for a malformed pagination cursor
+	ErrInvalidCursor = errors.New("invalid_notification_cursor")
+)
+
+// Cursor points at the last notification of a page. Inboxes are ordered newest first,
+// by sent time and then by ID so that the order is stable for equal times.
+type Cursor struct {
+	SentAt time.Time
+	ID     uuid.UUID
+}
+
+// Encode returns the opaque string form of the cursor
+func (c Cursor) Encode() string {
+	raw := c.SentAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a cursor produced by Encode
+func DecodeCursor(s string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	at, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	sentAt, err := time.Parse(time.RFC3339Nano, at)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	uid, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &Cursor{SentAt: sentAt, ID: uid}, nil
+}
+
+// ListFilter selects a page of a user's inbox
+type ListFilter struct {
+	Type      *NotificationType
+	ProjectID *uuid.UUID
+	Read      *bool
+	Archived  bool // list archived notifications instead of the inbox
+	After     *Cursor
+	Limit     int
+}
+
+// Page is a page of notifications; NextCursor is nil on the last page
+type Page struct {
+	Items      []Notification
+	NextCursor *Cursor
+}
+
+// UnreadCounts are the unread, non-archived notifications of a user
+type UnreadCounts struct {
+	Total  int
+	ByType map[NotificationType]int
+}
@@ -0,0 +1,141 @@
+package notification
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/SURF-Innovatie/MORIS/ent"
+	"github.com/google/uuid"
+)
+
+// Channel is where a user receives a notification
+type Channel string
+
+const (
+	ChannelInApp Channel = "in_app" // only in the notification inbox
+	ChannelEmail Channel = "email"  // in the inbox and by email
+	ChannelNone  Channel = "none"   // not at all
+)
+
+// DigestFrequency determines whether emails are sent one by one or bundled in a digest
+type DigestFrequency string
+
+const (
+	DigestImmediate DigestFrequency = "immediate"
+	DigestDaily     DigestFrequency = "daily"
+	DigestWeekly    DigestFrequency = "weekly"
+)
+
+// ErrInvalidPreference is returned when a preference cannot be saved
+var ErrInvalidPreference = errors.New("invalid_notification_preference")
+
+// Preference is a user's delivery choice for a notification type, an event type or, when
+// neither is set, for all notifications
+type Preference struct {
+	ID               uuid.UUID
+	UserID           uuid.UUID
+	NotificationType *NotificationType
+	EventType        *string
+	Channel          Channel
+	Frequency        DigestFrequency
+}
+
+// DefaultPreference applies when a user has no matching preference
+var DefaultPreference = Preference{Channel: ChannelEmail, Frequency: DigestImmediate}
+
+func (p *Preference) FromEnt(row *ent.NotificationPreference) *Preference {
+	out := &Preference{
+		ID:        row.ID,
+		UserID:    row.UserID,
+		EventType: row.EventType,
+		Channel:   Channel(row.Channel.String()),
+		Frequency: DigestFrequency(row.Frequency.String()),
+	}
+	if row.NotificationType != nil {
+		t := NotificationType(row.NotificationType.String())
+		out.NotificationType = &t
+	}
+	return out
+}
+
+// Validate checks the channel, frequency and notification type
+func (p Preference) Validate() error {
+	switch p.Channel {
+	case ChannelInApp, ChannelEmail, ChannelNone:
+	default:
+		return fmt.Errorf("%w: unknown channel %q", ErrInvalidPreference, p.Channel)
+	}
+	switch p.Frequency {
+	case DigestImmediate, DigestDaily, DigestWeekly:
+	default:
+		return fmt.Errorf("%w: unknown frequency %q", ErrInvalidPreference, p.Frequency)
+	}
+	if p.NotificationType != nil {
+		switch *p.NotificationType {
+		case NotificationInfo, NotificationApprovalRequest, NotificationStatusUpdate:
+		default:
+			return fmt.Errorf("%w: unknown notification type %q", ErrInvalidPreference, *p.NotificationType)
+		}
+	}
+	if p.EventType != nil && *p.EventType == "" {
+		return fmt.Errorf("%w: event type must not be empty", ErrInvalidPreference)
+	}
+	return nil
+}
+
+// scope identifies what a preference applies to; a user has at most one preference per scope
+func (p Preference) scope() string {
+	var t, e string
+	if p.NotificationType != nil {
+		t = string(*p.NotificationType)
+	}
+	if p.EventType != nil {
+		e = *p.EventType
+	}
+	return t + "|" + e
+}
+
+// ValidatePreferences validates a user's full set of preferences
+func ValidatePreferences(prefs []Preference) error {
+	seen := make(map[string]bool, len(prefs))
+	for _, p := range prefs {
+		if err := p.Validate(); err != nil {
+			return err
+		}
+		if seen[p.scope()] {
+			return fmt.Errorf("%w: duplicate preference for the same notification and event type", ErrInvalidPreference)
+		}
+		seen[p.scope()] = true
+	}
+	return nil
+}
+
+// ResolvePreference returns the most specific of the user's preferences for a notification.
+// A preference for both the notification and the event type beats one for the event type,
+// which beats one for the notification type, which beats the user's catch-all preference.
+// Approval requests always stay in the inbox, because they have to be acted upon there.
+func ResolvePreference(prefs []Preference, t NotificationType, eventType string) Preference {
+	best, bestScore := DefaultPreference, -1
+	for _, p := range prefs {
+		score := 0
+		if p.NotificationType != nil {
+			if *p.NotificationType != t {
+				continue
+			}
+			score++
+		}
+		if p.EventType != nil {
+			if *p.EventType != eventType {
+				continue
+			}
+			score += 2
+		}
+		if score > bestScore {
+			best, bestScore = p, score
+		}
+	}
+	if t == NotificationApprovalRequest && best.Channel == ChannelNone {
+		best.Channel = ChannelInApp
+	}
+	return best
+}
@@ -6,9 +6,11 @@ import (
 )
 
 type OrganisationNode struct {
-	ID          uuid.UUID
-	ParentID    *uuid.UUID
-	RorID       *string
+	ID       uuid.UUID
+	ParentID *uuid.UUID
+	RorID    *string
+	// RorStatus is the registry status as of the last ROR import
+	RorStatus   *string
 	Name        string
 	Description *string
 	AvatarURL   *string
@@ -29,7 +31,13 @@ func (o *OrganisationNode) FromEnt(row *ent.OrganisationNode) *OrganisationNode
 		ParentID:    parentID,
 		Name:        row.Name,
 		RorID:       row.RorID,
+		RorStatus:   row.RorStatus,
 		Description: row.Description,
 		AvatarURL:   row.AvatarURL,
 	}
 }
+
+// HasParent reports whether parentID is the parent of the node, where nil stands for no parent
+func (o *OrganisationNode) HasParent(parentID *uuid.UUID) bool {
+	return (o.ParentID == nil && parentID == nil) || (o.ParentID != nil && parentID != nil && *o.ParentID == *parentID)
+}
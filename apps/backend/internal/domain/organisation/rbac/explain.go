@@ -0,0 +1,49 @@
+package rbac
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PermissionExplanation shows why a person does or does not hold a permission on a node,
+// following the same path as a permission check: the node's ancestors, the role scopes rooted
+// at them, the person's memberships of those scopes and the permissions of the scoped roles.
+type PermissionExplanation struct {
+	PersonID   uuid.UUID
+	NodeID     uuid.UUID
+	Permission Permission
+	Granted    bool
+	// SysAdmin is set when the permission is granted because the person is a sysadmin
+	SysAdmin bool
+	// Ancestors are the node and its ancestors, nearest first
+	Ancestors []AncestorNode
+	// Grants are the person's memberships that apply to the node, granting or not, including
+	// memberships that have not started yet or have ended
+	Grants []GrantPath
+	Reason string
+}
+
+type AncestorNode struct {
+	NodeID uuid.UUID
+	Name   string
+	Depth  int
+}
+
+// GrantPath is one membership → role scope → organisation role → ancestor node chain
+type GrantPath struct {
+	MembershipID uuid.UUID
+	RoleScopeID  uuid.UUID
+	RoleID       uuid.UUID
+	RoleKey      string
+	// ScopeNodeID is the ancestor the role scope is rooted at
+	ScopeNodeID   uuid.UUID
+	ScopeNodeName string
+	Depth         int
+	Permissions   []Permission
+	StartDate     *time.Time
+	EndDate       *time.Time
+	// Active is set when the membership's validity period contains the current time
+	Active bool
+	Grants bool
+}
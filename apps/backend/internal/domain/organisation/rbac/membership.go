@@ -1,14 +1,27 @@
 package rbac
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/SURF-Innovatie/MORIS/ent"
 	"github.com/google/uuid"
 )
 
+// ErrInvalidMembershipPeriod is returned when a membership ends before it starts
+var ErrInvalidMembershipPeriod = errors.New("invalid_membership_period")
+
 type Membership struct {
 	ID          uuid.UUID
 	PersonID    uuid.UUID
 	RoleScopeID uuid.UUID
+	// StartDate and EndDate bound the period in which the membership grants its role;
+	// nil means unbounded. The end date itself is no longer part of the period.
+	StartDate *time.Time
+	EndDate   *time.Time
+	// Provisioned memberships were granted by a provisioning rule at login
+	Provisioned bool
 }
 
 func (p *Membership) FromEnt(row *ent.Membership) *Membership {
@@ -16,5 +29,29 @@ func (p *Membership) FromEnt(row *ent.Membership) *Membership {
 		ID:          row.ID,
 		PersonID:    row.PersonID,
 		RoleScopeID: row.RoleScopeID,
+		StartDate:   row.StartDate,
+		EndDate:     row.EndDate,
+		Provisioned: row.Provisioned,
+	}
+}
+
+// ActiveAt reports whether the membership grants its role at t
+func (p *Membership) ActiveAt(t time.Time) bool {
+	return MembershipPeriodContains(p.StartDate, p.EndDate, t)
+}
+
+// MembershipPeriodContains reports whether t lies in [start, end), where nil bounds are open
+func MembershipPeriodContains(start, end *time.Time, t time.Time) bool {
+	if start != nil && t.Before(*start) {
+		return false
+	}
+	return end == nil || t.Before(*end)
+}
+
+// ValidateMembershipPeriod checks that a period does not end before it starts
+func ValidateMembershipPeriod(start, end *time.Time) error {
+	if start != nil && end != nil && !end.After(*start) {
+		return fmt.Errorf("%w: end date must be after start date", ErrInvalidMembershipPeriod)
 	}
+	return nil
 }
@@ -1,5 +1,7 @@
 package rbac
 
+import "errors"
+
 type Permission string
 
 const (
@@ -9,8 +11,17 @@ const (
 	PermissionManageCustomFields      Permission = "manage_custom_fields"
 	PermissionManageDetails           Permission = "manage_details"
 	PermissionCreateProject           Permission = "create_project"
+	PermissionApproveEvents           Permission = "approve_events"
+	PermissionManagePolicies          Permission = "manage_policies"
+	PermissionViewProjects            Permission = "view_projects"
+	PermissionExportProjects          Permission = "export_projects"
+	PermissionManageAffiliatedOrgs    Permission = "manage_affiliated_orgs"
+	PermissionViewAuditLog            Permission = "view_audit_log"
 )
 
+// ErrForbidden is returned by services when the current user lacks a required permission
+var ErrForbidden = errors.New("forbidden")
+
 type PermissionDefinition struct {
 	Permission  Permission
 	Label       string
@@ -24,6 +35,12 @@ var Definitions = []PermissionDefinition{
 	{Permission: PermissionManageCustomFields, Label: "Manage Custom Fields", Description: "Can manage custom fields for projects and people"},
 	{Permission: PermissionManageDetails, Label: "Manage Details", Description: "Can update organisation details"},
 	{Permission: PermissionCreateProject, Label: "Create Project", Description: "Can create new projects"},
+	{Permission: PermissionApproveEvents, Label: "Approve Events", Description: "Can approve or reject pending project changes"},
+	{Permission: PermissionManagePolicies, Label: "Manage Policies", Description: "Can create and manage event policies and webhook subscriptions"},
+	{Permission: PermissionViewProjects, Label: "View Projects", Description: "Can view all projects owned by the organisation"},
+	{Permission: PermissionExportProjects, Label: "Export Projects", Description: "Can export projects through adapters"},
+	{Permission: PermissionManageAffiliatedOrgs, Label: "Manage Affiliated Organisations", Description: "Can create, update and delete affiliated organisations"},
+	{Permission: PermissionViewAuditLog, Label: "View Audit Log", Description: "Can view and export the history of role, membership, custom field and project role changes"},
 }
 
 var AllPermissions = []Permission{
@@ -33,6 +50,12 @@ var AllPermissions = []Permission{
 	PermissionManageCustomFields,
 	PermissionManageDetails,
 	PermissionCreateProject,
+	PermissionApproveEvents,
+	PermissionManagePolicies,
+	PermissionViewProjects,
+	PermissionExportProjects,
+	PermissionManageAffiliatedOrgs,
+	PermissionViewAuditLog,
 }
 
 func (p Permission) String() string {
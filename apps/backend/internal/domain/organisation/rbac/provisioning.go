@@ -0,0 +1,72 @@
+package rbac
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/ent"
+	"github.com/google/uuid"
+)
+
+// ErrInvalidProvisioningRule is returned for a rule that is not on a root node, or that grants a
+// role scope outside the tree of its root node
+var ErrInvalidProvisioningRule = errors.New("invalid_provisioning_rule")
+
+// ProvisioningRule grants a role scope to people who log in through SURFconext with matching claims.
+// Affiliation and Entitlement are optional; when set, the claims must contain them.
+type ProvisioningRule struct {
+	ID                    uuid.UUID
+	RootNodeID            uuid.UUID
+	RoleScopeID           uuid.UUID
+	SchacHomeOrganization string
+	Affiliation           *string
+	Entitlement           *string
+	CreatedAt             time.Time
+}
+
+func (p *ProvisioningRule) FromEnt(row *ent.ProvisioningRule) *ProvisioningRule {
+	return &ProvisioningRule{
+		ID:                    row.ID,
+		RootNodeID:            row.RootNodeID,
+		RoleScopeID:           row.RoleScopeID,
+		SchacHomeOrganization: row.SchacHomeOrganization,
+		Affiliation:           row.Affiliation,
+		Entitlement:           row.Entitlement,
+		CreatedAt:             row.CreatedAt,
+	}
+}
+
+// ProvisioningClaims are the SURFconext attributes provisioning rules are matched against
+type ProvisioningClaims struct {
+	SchacHomeOrganization string
+	Affiliations          []string
+	Entitlements          []string
+}
+
+// NormalizeHomeOrganization lowercases a schacHomeOrganization, which is a domain name
+func NormalizeHomeOrganization(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// Matches reports whether the rule applies to the claims. Home organisations and affiliations are
+// compared case-insensitively, entitlements (URNs) exactly.
+func (p *ProvisioningRule) Matches(c ProvisioningClaims) bool {
+	if NormalizeHomeOrganization(c.SchacHomeOrganization) != NormalizeHomeOrganization(p.SchacHomeOrganization) {
+		return false
+	}
+	if p.Affiliation != nil && !slices.ContainsFunc(c.Affiliations, func(a string) bool {
+		return strings.EqualFold(a, *p.Affiliation)
+	}) {
+		return false
+	}
+	return p.Entitlement == nil || slices.Contains(c.Entitlements, *p.Entitlement)
+}
+
+// ProvisioningResult lists the memberships a login created, reactivated and revoked
+type ProvisioningResult struct {
+	Created []Membership
+	Updated []Membership
+	Revoked []Membership
+}
@@ -0,0 +1,92 @@
+package organisation
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrNodeInUse is returned when a node is deleted while data still references it
+	ErrNodeInUse = errors.New("organisation node is still in use")
+	// ErrInvalidRestructure is returned for merges and moves that would break the tree
+	ErrInvalidRestructure = errors.New("invalid organisation restructure")
+)
+
+type RestructureOperation string
+
+const (
+	RestructureDelete RestructureOperation = "delete"
+	RestructureMerge  RestructureOperation = "merge"
+	RestructureMove   RestructureOperation = "move"
+)
+
+// NodeUsage lists what references an organisation node
+type NodeUsage struct {
+	ChildIDs             []uuid.UUID
+	ProjectIDs           []uuid.UUID
+	ProjectRoles         int
+	OrganisationRoles    int
+	CustomFields         int
+	RoleScopes           int
+	Memberships          int
+	EventPolicies        int
+	ProvisioningRules    int
+	ScimTokens           int
+	ScimUsers            int
+	APITokens            int
+	WebhookSubscriptions int
+}
+
+// InUse reports whether the node cannot be deleted without reassigning its data.
+// Webhook subscriptions do not count, they are removed together with the node.
+func (u NodeUsage) InUse() bool {
+	return len(u.ChildIDs) > 0 ||
+		len(u.ProjectIDs) > 0 ||
+		u.ProjectRoles > 0 ||
+		u.OrganisationRoles > 0 ||
+		u.CustomFields > 0 ||
+		u.Memberships > 0 ||
+		u.EventPolicies > 0 ||
+		u.ProvisioningRules > 0 ||
+		u.ScimTokens > 0 ||
+		u.ScimUsers > 0 ||
+		u.APITokens > 0
+}
+
+// SubtreeMove describes a re-parented subtree and the closure rows that were replaced
+type SubtreeMove struct {
+	NodeIDs            []uuid.UUID
+	ClosureRowsRemoved int
+	ClosureRowsAdded   int
+}
+
+// Reassignment describes the data that was re-pointed from one node to another
+type Reassignment struct {
+	// RenamedProjectRoles maps the old key of a moved project role to its new key, for keys the target already used
+	RenamedProjectRoles map[string]string
+	// MergedOrganisationRoles are role keys the target already had; their scopes now use the target's role
+	MergedOrganisationRoles []string
+	// MergedMemberships counts memberships dropped because the person already had the same role at the target
+	MergedMemberships int
+}
+
+// RestructureReport is the outcome of a delete, merge or move. For a dry run it describes what would happen.
+type RestructureReport struct {
+	Operation RestructureOperation
+	NodeID    uuid.UUID
+	// TargetID is the node that receives the data on a merge or a delete with reassignment,
+	// or the new parent on a move (nil for a move to the root)
+	TargetID *uuid.UUID
+	DryRun   bool
+
+	Usage        NodeUsage
+	Reassignment Reassignment
+	// MovedChildren are the moves of the node's children (merge) or of the node itself (move)
+	MovedChildren []SubtreeMove
+	// ReassignedProjectIDs are projects whose owning node changed
+	ReassignedProjectIDs []uuid.UUID
+
+	DeletedWebhookSubscriptions int
+	DeletedClosureRows          int
+}
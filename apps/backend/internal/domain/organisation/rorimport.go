@@ -0,0 +1,95 @@
+package organisation
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidRORID is returned for a ROR ID that is neither a bare ID nor a ror.org URL
+	ErrInvalidRORID = errors.New("invalid ror id")
+	// ErrRORNotFound is returned when the registry does not know the organisation to import
+	ErrRORNotFound = errors.New("ror organisation not found")
+	// ErrRORImportTooLarge is returned when the hierarchy has more organisations than an import may create
+	ErrRORImportTooLarge = errors.New("ror hierarchy too large")
+)
+
+// rorIDPattern matches the bare form of a ROR ID: a zero, six characters and a two-digit checksum
+var rorIDPattern = regexp.MustCompile(`^0[a-hj-km-np-tv-z0-9]{6}[0-9]{2}$`)
+
+const rorURLPrefix = "https://ror.org/"
+
+// NormalizeRORID returns the https://ror.org/ form in which nodes store their ROR ID.
+// It accepts the bare ID as well as http(s) URLs with or without the scheme.
+func NormalizeRORID(id string) (string, error) {
+	bare := strings.TrimSpace(strings.ToLower(id))
+	for _, prefix := range []string{"https://", "http://"} {
+		bare = strings.TrimPrefix(bare, prefix)
+	}
+	bare = strings.TrimPrefix(bare, "ror.org/")
+	if !rorIDPattern.MatchString(bare) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidRORID, id)
+	}
+	return rorURLPrefix + bare, nil
+}
+
+// BareRORID strips the URL prefix from a normalized ROR ID
+func BareRORID(normalized string) string {
+	return strings.TrimPrefix(normalized, rorURLPrefix)
+}
+
+type RORImportAction string
+
+const (
+	RORImportCreate    RORImportAction = "create"
+	RORImportUpdate    RORImportAction = "update"
+	RORImportUnchanged RORImportAction = "unchanged"
+	// RORImportSkip is used for withdrawn organisations that are not in the tree yet
+	RORImportSkip RORImportAction = "skip"
+)
+
+// RORImportChange names what an update changes on an existing node
+type RORImportChange string
+
+const (
+	RORImportChangeName   RORImportChange = "name"
+	RORImportChangeParent RORImportChange = "parent"
+	RORImportChangeStatus RORImportChange = "status"
+)
+
+// RORImportItem is the outcome for one organisation of the ROR hierarchy
+type RORImportItem struct {
+	RorID  string
+	Name   string
+	Status string
+	// ParentRorID is the organisation that lists this one as its child; nil for the imported organisation
+	ParentRorID *string
+	Action      RORImportAction
+	Changes     []RORImportChange
+
+	// NodeID is the matching node; for a dry run it is the ID the node would get
+	NodeID *uuid.UUID
+	// ParentID is the parent node after the import
+	ParentID *uuid.UUID
+	// PreviousName, PreviousParentID and PreviousStatus describe an existing node before the import
+	PreviousName     *string
+	PreviousParentID *uuid.UUID
+	PreviousStatus   *string
+}
+
+// RORImportReport is the outcome of a ROR hierarchy import, in the order the hierarchy was walked
+// (parents before children). For a dry run it describes what would happen.
+type RORImportReport struct {
+	RorID  string
+	DryRun bool
+	Items  []RORImportItem
+
+	Created   int
+	Updated   int
+	Unchanged int
+	Skipped   int
+}
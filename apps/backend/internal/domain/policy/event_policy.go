@@ -1,9 +1,16 @@
 package policy
 
 import (
+	"errors"
+	"fmt"
 	"slices"
+	"strings"
+	"time"
 
 	"github.com/SURF-Innovatie/MORIS/ent"
+	"github.com/SURF-Innovatie/MORIS/internal/common/netguard"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/policy/expression"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/policy/messagetemplate"
 	"github.com/google/uuid"
 )
 
@@ -13,14 +20,41 @@ type ActionType string
 const (
 	ActionTypeNotify          ActionType = "notify"
 	ActionTypeRequestApproval ActionType = "request_approval"
+	ActionTypeWebhook         ActionType = "webhook"
 )
 
+// TriggerType determines what causes a policy to be evaluated
+type TriggerType string
+
+const (
+	TriggerTypeEvent    TriggerType = "event"    // evaluated when a matching event is appended
+	TriggerTypeSchedule TriggerType = "schedule" // evaluated by the scheduler relative to a date
+)
+
+// Schedule anchors. Besides the project dates, a schedule can be anchored on the
+// most recent occurrence of an event type, e.g. "event:project.started".
+const (
+	ScheduleAnchorStartDate   = "project.start_date"
+	ScheduleAnchorEndDate     = "project.end_date"
+	ScheduleAnchorEventPrefix = "event:"
+)
+
+// MinWebhookSecretLength is the minimum length of a webhook signing secret
+const MinWebhookSecretLength = 16
+
+// ErrInvalidPolicy is returned when a policy definition cannot be saved
+var ErrInvalidPolicy = errors.New("invalid_event_policy")
+
+// ErrPolicyLocked is returned when a change would disable, remove or shadow a locked policy
+var ErrPolicyLocked = errors.New("event_policy_locked")
+
 // Condition represents a single condition for policy evaluation.
 // Conditions are AND-ed together; all must pass for the policy to trigger.
 type Condition struct {
-	Field    string `json:"field"`    // Path to field: "event.<field>", "project.<field>", "custom_field.<name>"
-	Operator string `json:"operator"` // Operator type (see constants below)
-	Value    any    `json:"value"`    // Comparison value
+	Field      string `json:"field"`                // Path to field: "event.<field>", "project.<field>", "custom_field.<name>"
+	Operator   string `json:"operator"`             // Operator type (see constants below)
+	Value      any    `json:"value"`                // Comparison value
+	Expression string `json:"expression,omitempty"` // CEL expression, only for the "expression" operator
 }
 
 // Supported condition operators (extensible)
@@ -36,17 +70,73 @@ const (
 	OperatorNotIn       = "not_in"
 	OperatorExists      = "exists"
 	OperatorNotExists   = "not_exists"
+	OperatorExpression  = "expression" // evaluates Expression instead of comparing Field with Value
 )
 
+// Condition field path sources
+const (
+	SourceEvent       = "event"
+	SourceProject     = "project"
+	SourceCustomField = "custom_field"
+)
+
+// supportedOperators are the operators the evaluator implements
+var supportedOperators = []string{
+	OperatorEquals, OperatorNotEquals, OperatorContains, OperatorStartsWith,
+	OperatorGreaterThan, OperatorLessThan, OperatorBetween,
+	OperatorIn, OperatorNotIn, OperatorExists, OperatorNotExists, OperatorExpression,
+}
+
+// Path splits the condition's field path into its source and field name
+func (c Condition) Path() (source, field string) {
+	source, field, _ = strings.Cut(c.Field, ".")
+	return source, field
+}
+
+// IsExpression reports whether the condition is a CEL expression
+func (c Condition) IsExpression() bool {
+	return c.Operator == OperatorExpression
+}
+
+// Validate checks the operator and the syntax of the field path, or compiles the expression.
+// Whether the field exists on the triggering events is checked by the events package.
+func (c Condition) Validate() error {
+	if !slices.Contains(supportedOperators, c.Operator) {
+		return fmt.Errorf("%w: unknown operator %q", ErrInvalidPolicy, c.Operator)
+	}
+	if c.IsExpression() {
+		if _, err := expression.Compile(c.Expression); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidPolicy, err)
+		}
+		return nil
+	}
+
+	source, field := c.Path()
+	switch source {
+	case SourceEvent, SourceProject, SourceCustomField:
+	default:
+		return fmt.Errorf("%w: field %q must start with event., project. or custom_field.", ErrInvalidPolicy, c.Field)
+	}
+	if field == "" || (source != SourceCustomField && strings.Contains(field, ".")) {
+		return fmt.Errorf("%w: invalid field %q", ErrInvalidPolicy, c.Field)
+	}
+	return nil
+}
+
 // EventPolicy represents a configurable event trigger with actions.
 type EventPolicy struct {
 	ID                      uuid.UUID
 	Name                    string
 	Description             *string
+	TriggerType             TriggerType
 	EventTypes              []string
+	ScheduleAnchor          *string
+	ScheduleOffsetDays      *int
 	Conditions              []Condition
 	ActionType              ActionType
 	MessageTemplate         *string
+	WebhookURL              *string
+	WebhookSecret           *string
 	RecipientUserIDs        []uuid.UUID
 	RecipientProjectRoleIDs []uuid.UUID
 	RecipientOrgRoleIDs     []uuid.UUID
@@ -54,11 +144,14 @@ type EventPolicy struct {
 	OrgNodeID               *uuid.UUID
 	ProjectID               *uuid.UUID
 	Enabled                 bool
+	Locked                  bool // mandatory for descendants; only set on org node policies
+	Version                 int
 
 	// Inheritance info (populated when querying with inheritance context)
 	Inherited         bool
 	SourceOrgNodeID   *uuid.UUID
 	SourceOrgNodeName *string
+	Shadowed          bool // ignored because an overlapping locked policy of an ancestor applies
 }
 
 // FromEnt converts an ent EventPolicy to domain entity
@@ -78,6 +171,9 @@ func (e *EventPolicy) FromEnt(row *ent.EventPolicy) *EventPolicy {
 			cond.Operator = op
 		}
 		cond.Value = c["value"]
+		if x, ok := c["expression"].(string); ok {
+			cond.Expression = x
+		}
 		conditions = append(conditions, cond)
 	}
 
@@ -85,10 +181,15 @@ func (e *EventPolicy) FromEnt(row *ent.EventPolicy) *EventPolicy {
 		ID:                      row.ID,
 		Name:                    row.Name,
 		Description:             row.Description,
+		TriggerType:             TriggerType(row.TriggerType.String()),
 		EventTypes:              row.EventTypes,
+		ScheduleAnchor:          row.ScheduleAnchor,
+		ScheduleOffsetDays:      row.ScheduleOffsetDays,
 		Conditions:              conditions,
 		ActionType:              ActionType(row.ActionType.String()),
 		MessageTemplate:         row.MessageTemplate,
+		WebhookURL:              row.WebhookURL,
+		WebhookSecret:           row.WebhookSecret,
 		RecipientUserIDs:        row.RecipientUserIds,
 		RecipientProjectRoleIDs: row.RecipientProjectRoleIds,
 		RecipientOrgRoleIDs:     row.RecipientOrgRoleIds,
@@ -96,6 +197,8 @@ func (e *EventPolicy) FromEnt(row *ent.EventPolicy) *EventPolicy {
 		OrgNodeID:               row.OrgNodeID,
 		ProjectID:               row.ProjectID,
 		Enabled:                 row.Enabled,
+		Locked:                  row.Locked,
+		Version:                 row.Version,
 	}
 }
 
@@ -108,11 +211,149 @@ func (e *EventPolicy) ConditionsToMap() []map[string]any {
 			"operator": c.Operator,
 			"value":    c.Value,
 		}
+		if c.IsExpression() {
+			result[i]["expression"] = c.Expression
+		}
 	}
 	return result
 }
 
-// MatchesEventType checks if the policy matches a given event type
+// MatchesEventType checks if the policy matches a given event type.
+// Scheduled policies never match appended events.
 func (e *EventPolicy) MatchesEventType(eventType string) bool {
+	if e.IsScheduled() {
+		return false
+	}
 	return slices.Contains(e.EventTypes, eventType)
 }
+
+// Shadows reports whether the policy would shadow the given locked policy: it is an unlocked policy
+// of another scope that performs the same action on a common trigger. Names are not compared, so
+// renaming a policy does not get it past a lock.
+func (e *EventPolicy) Shadows(locked EventPolicy) bool {
+	if !locked.Locked || e.Locked || e.ID == locked.ID || e.ActionType != locked.ActionType {
+		return false
+	}
+	// Policies of the locked policy's own node are not its descendants
+	if e.OrgNodeID != nil && locked.OrgNodeID != nil && *e.OrgNodeID == *locked.OrgNodeID {
+		return false
+	}
+	return e.overlaps(locked)
+}
+
+// overlaps reports whether both policies share an event type or, when scheduled, their schedule anchor
+func (e *EventPolicy) overlaps(other EventPolicy) bool {
+	if e.IsScheduled() != other.IsScheduled() {
+		return false
+	}
+	if e.IsScheduled() {
+		return e.ScheduleAnchor != nil && other.ScheduleAnchor != nil && *e.ScheduleAnchor == *other.ScheduleAnchor
+	}
+	return slices.ContainsFunc(e.EventTypes, func(t string) bool { return slices.Contains(other.EventTypes, t) })
+}
+
+// MarkShadowed flags every policy that shadows one of the locked policies in the set
+func MarkShadowed(policies []EventPolicy) []EventPolicy {
+	for i := range policies {
+		for _, other := range policies {
+			if policies[i].Shadows(other) {
+				policies[i].Shadowed = true
+				break
+			}
+		}
+	}
+	return policies
+}
+
+// IsScheduled reports whether the policy is triggered by the scheduler instead of by events
+func (e *EventPolicy) IsScheduled() bool {
+	return e.TriggerType == TriggerTypeSchedule
+}
+
+// AnchorEventType returns the event type a schedule is anchored on, if any
+func (e *EventPolicy) AnchorEventType() (string, bool) {
+	if e.ScheduleAnchor == nil {
+		return "", false
+	}
+	return strings.CutPrefix(*e.ScheduleAnchor, ScheduleAnchorEventPrefix)
+}
+
+// DueAt returns the day a scheduled policy is due for the given anchor time.
+// The result is truncated to the day so it is stable between scheduler runs.
+func (e *EventPolicy) DueAt(anchor time.Time) time.Time {
+	offset := 0
+	if e.ScheduleOffsetDays != nil {
+		offset = *e.ScheduleOffsetDays
+	}
+	y, m, d := anchor.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC).AddDate(0, 0, offset)
+}
+
+// Validate checks that the policy definition is complete and consistent
+func (e *EventPolicy) Validate() error {
+	if e.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidPolicy)
+	}
+
+	switch e.TriggerType {
+	case "", TriggerTypeEvent:
+		if len(e.EventTypes) == 0 {
+			return fmt.Errorf("%w: at least one event type is required", ErrInvalidPolicy)
+		}
+	case TriggerTypeSchedule:
+		if err := e.validateSchedule(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%w: unknown trigger type %q", ErrInvalidPolicy, e.TriggerType)
+	}
+
+	for _, c := range e.Conditions {
+		if err := c.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if e.MessageTemplate != nil && *e.MessageTemplate != "" {
+		if _, err := messagetemplate.Parse(*e.MessageTemplate); err != nil {
+			return fmt.Errorf("%w: message_template: %s", ErrInvalidPolicy, err)
+		}
+	}
+
+	switch e.ActionType {
+	case ActionTypeNotify, ActionTypeRequestApproval:
+	case ActionTypeWebhook:
+		if e.WebhookURL == nil || *e.WebhookURL == "" {
+			return fmt.Errorf("%w: webhook_url is required for webhook policies", ErrInvalidPolicy)
+		}
+		if err := netguard.ValidateURL(*e.WebhookURL); err != nil {
+			return fmt.Errorf("%w: webhook_url: %s", ErrInvalidPolicy, err)
+		}
+		if e.WebhookSecret != nil && len(*e.WebhookSecret) < MinWebhookSecretLength {
+			return fmt.Errorf("%w: webhook_secret must be at least %d characters", ErrInvalidPolicy, MinWebhookSecretLength)
+		}
+	default:
+		return fmt.Errorf("%w: unknown action type %q", ErrInvalidPolicy, e.ActionType)
+	}
+
+	return nil
+}
+
+func (e *EventPolicy) validateSchedule() error {
+	if e.ScheduleAnchor == nil || *e.ScheduleAnchor == "" {
+		return fmt.Errorf("%w: schedule_anchor is required for scheduled policies", ErrInvalidPolicy)
+	}
+	switch anchor := *e.ScheduleAnchor; {
+	case anchor == ScheduleAnchorStartDate, anchor == ScheduleAnchorEndDate:
+	case strings.HasPrefix(anchor, ScheduleAnchorEventPrefix) && len(anchor) > len(ScheduleAnchorEventPrefix):
+	default:
+		return fmt.Errorf("%w: unknown schedule_anchor %q", ErrInvalidPolicy, anchor)
+	}
+	if e.ActionType == ActionTypeRequestApproval {
+		return fmt.Errorf("%w: scheduled policies cannot request approval", ErrInvalidPolicy)
+	}
+	if _, ok := e.AnchorEventType(); e.ActionType == ActionTypeWebhook && !ok {
+		return fmt.Errorf("%w: scheduled webhook policies must be anchored on an event", ErrInvalidPolicy)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package policy
+
+import (
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/ent"
+	"github.com/google/uuid"
+)
+
+// ExecutionOutcome describes what happened after a policy matched
+type ExecutionOutcome string
+
+const (
+	ExecutionApprovalRequired ExecutionOutcome = "approval_required" // the event was held pending approval
+	ExecutionExecuted         ExecutionOutcome = "executed"          // the action was carried out
+	ExecutionSkipped          ExecutionOutcome = "skipped"           // the action was deferred or not needed, see Detail
+	ExecutionFailed           ExecutionOutcome = "failed"            // the action failed, see Detail
+)
+
+// ConditionResult records how a single condition evaluated
+type ConditionResult struct {
+	Field    string `json:"field"`
+	Operator string `json:"operator"`
+	Expected any    `json:"expected,omitempty"`
+	Actual   string `json:"actual"`
+	Passed   bool   `json:"passed"`
+}
+
+// Execution is the audit record of a policy that matched an event or a schedule
+type Execution struct {
+	ID               uuid.UUID
+	PolicyID         uuid.UUID
+	PolicyVersion    int
+	PolicyName       string
+	Trigger          TriggerType
+	EventID          *uuid.UUID
+	EventType        string
+	ProjectID        uuid.UUID
+	ActionType       ActionType
+	Outcome          ExecutionOutcome
+	ConditionResults []ConditionResult
+	RecipientUserIDs []uuid.UUID
+	Message          *string
+	Detail           *string
+	CreatedAt        time.Time
+}
+
+// FromEnt converts an ent PolicyExecution to domain entity
+func (x *Execution) FromEnt(row *ent.PolicyExecution) *Execution {
+	if row == nil {
+		return nil
+	}
+
+	results := make([]ConditionResult, 0, len(row.ConditionResults))
+	for _, r := range row.ConditionResults {
+		res := ConditionResult{Expected: r["expected"]}
+		res.Field, _ = r["field"].(string)
+		res.Operator, _ = r["operator"].(string)
+		res.Actual, _ = r["actual"].(string)
+		res.Passed, _ = r["passed"].(bool)
+		results = append(results, res)
+	}
+
+	return &Execution{
+		ID:               row.ID,
+		PolicyID:         row.PolicyID,
+		PolicyVersion:    row.PolicyVersion,
+		PolicyName:       row.PolicyName,
+		Trigger:          TriggerType(row.Trigger.String()),
+		EventID:          row.EventID,
+		EventType:        row.EventType,
+		ProjectID:        row.ProjectID,
+		ActionType:       ActionType(row.ActionType.String()),
+		Outcome:          ExecutionOutcome(row.Outcome.String()),
+		ConditionResults: results,
+		RecipientUserIDs: row.RecipientUserIds,
+		Message:          row.Message,
+		Detail:           row.Detail,
+		CreatedAt:        row.CreatedAt,
+	}
+}
+
+// ConditionResultsToMap converts condition results to []map[string]any for ent storage
+func (x *Execution) ConditionResultsToMap() []map[string]any {
+	result := make([]map[string]any, len(x.ConditionResults))
+	for i, r := range x.ConditionResults {
+		result[i] = map[string]any{
+			"field":    r.Field,
+			"operator": r.Operator,
+			"expected": r.Expected,
+			"actual":   r.Actual,
+			"passed":   r.Passed,
+		}
+	}
+	return result
+}
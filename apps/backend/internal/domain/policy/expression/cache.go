@@ -0,0 +1,51 @@
+package expression
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Cache keeps the compiled expressions of each policy version.
+// Compiling a newer version of a policy drops the programs of older versions.
+type Cache struct {
+	mu       sync.Mutex
+	policies map[uuid.UUID]*cachedPolicy
+}
+
+type cachedPolicy struct {
+	version  int
+	compiled map[string]*Expression
+}
+
+// NewCache creates an empty expression cache
+func NewCache() *Cache {
+	return &Cache{policies: make(map[uuid.UUID]*cachedPolicy)}
+}
+
+// Get returns the compiled expression for a policy version, compiling it on first use.
+// Unsaved policies (without an ID) are compiled on every call.
+func (c *Cache) Get(policyID uuid.UUID, version int, source string) (*Expression, error) {
+	if policyID == uuid.Nil {
+		return Compile(source)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	p, ok := c.policies[policyID]
+	if !ok || p.version != version {
+		p = &cachedPolicy{version: version, compiled: make(map[string]*Expression)}
+		c.policies[policyID] = p
+	}
+	if x, ok := p.compiled[source]; ok {
+		return x, nil
+	}
+
+	x, err := Compile(source)
+	if err != nil {
+		return nil, err
+	}
+	p.compiled[source] = x
+	return x, nil
+}
@@ -0,0 +1,176 @@
+// Package expression implements CEL expressions for policy conditions.
+//
+// Expressions are sandboxed: they can only read the variables below, cannot
+// perform I/O and are bounded in size and evaluation cost.
+//
+//	event         the triggering event: type, id, status, at, created_by and its own fields
+//	project       project.before and project.after, the project state around the event
+//	actor         the person that caused the event: id, person_id, name, email
+//	custom_field  the custom field values of the project after the event
+//
+// Field names are the snake_case form of the Go field names, e.g. event.end_date
+// or size(project.after.members) > 10.
+package expression
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/types"
+)
+
+// Variables available to expressions
+const (
+	VarEvent       = "event"
+	VarProject     = "project"
+	VarActor       = "actor"
+	VarCustomField = "custom_field"
+)
+
+// Project states available under the project variable
+const (
+	StateBefore = "before"
+	StateAfter  = "after"
+)
+
+// ActorFields are the fields of the actor variable
+var ActorFields = []string{"id", "person_id", "name", "email"}
+
+const (
+	maxLength = 2048
+	maxCost   = 100_000
+)
+
+// ErrInvalid is returned for expressions that do not compile or are not boolean
+var ErrInvalid = errors.New("invalid expression")
+
+var env = sync.OnceValues(func() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable(VarEvent, cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable(VarProject, cel.MapType(cel.StringType, cel.MapType(cel.StringType, cel.DynType))),
+		cel.Variable(VarActor, cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable(VarCustomField, cel.MapType(cel.StringType, cel.DynType)),
+		cel.ParserExpressionSizeLimit(maxLength),
+	)
+})
+
+// Input holds the values an expression is evaluated against
+type Input struct {
+	Event        map[string]any
+	Before       map[string]any
+	After        map[string]any
+	Actor        map[string]any
+	CustomFields map[string]any
+}
+
+// Expression is a compiled, type-checked expression
+type Expression struct {
+	source     string
+	program    cel.Program
+	references []string
+}
+
+// Compile parses and type-checks an expression. The result must be a boolean.
+func Compile(source string) (*Expression, error) {
+	if strings.TrimSpace(source) == "" {
+		return nil, fmt.Errorf("%w: expression is empty", ErrInvalid)
+	}
+
+	e, err := env()
+	if err != nil {
+		return nil, err
+	}
+
+	checked, issues := e.Compile(source)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalid, issues.Err())
+	}
+	if out := checked.OutputType(); !out.IsExactType(cel.BoolType) && !out.IsExactType(cel.DynType) {
+		return nil, fmt.Errorf("%w: expression must evaluate to a bool, not %s", ErrInvalid, out)
+	}
+
+	program, err := e.Program(checked, cel.CostLimit(maxCost))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalid, err)
+	}
+
+	return &Expression{
+		source:     source,
+		program:    program,
+		references: references(checked.NativeRep().Expr()),
+	}, nil
+}
+
+// Source returns the expression text
+func (x *Expression) Source() string {
+	return x.source
+}
+
+// References returns the field paths the expression selects, e.g. "project.after.end_date"
+func (x *Expression) References() []string {
+	return x.references
+}
+
+// Eval evaluates the expression. Missing fields and non-boolean results are errors.
+func (x *Expression) Eval(ctx context.Context, in Input) (bool, error) {
+	vars := map[string]any{
+		VarEvent:       orEmpty(in.Event),
+		VarProject:     map[string]any{StateBefore: orEmpty(in.Before), StateAfter: orEmpty(in.After)},
+		VarActor:       orEmpty(in.Actor),
+		VarCustomField: orEmpty(in.CustomFields),
+	}
+
+	out, _, err := x.program.ContextEval(ctx, vars)
+	if err != nil {
+		return false, err
+	}
+	b, ok := out.(types.Bool)
+	if !ok {
+		return false, fmt.Errorf("expression returned %s instead of a bool", out.Type())
+	}
+	return bool(b), nil
+}
+
+func orEmpty(m map[string]any) map[string]any {
+	if m == nil {
+		return map[string]any{}
+	}
+	return m
+}
+
+// references collects the dotted paths of all field selections rooted at a variable
+func references(root ast.Expr) []string {
+	seen := make(map[string]bool)
+	var out []string
+	ast.PostOrderVisit(root, ast.NewExprVisitor(func(e ast.Expr) {
+		if e.Kind() != ast.SelectKind {
+			return
+		}
+		if path, ok := selectPath(e); ok && !seen[path] {
+			seen[path] = true
+			out = append(out, path)
+		}
+	}))
+	return out
+}
+
+func selectPath(e ast.Expr) (string, bool) {
+	switch e.Kind() {
+	case ast.IdentKind:
+		return e.AsIdent(), true
+	case ast.SelectKind:
+		sel := e.AsSelect()
+		operand, ok := selectPath(sel.Operand())
+		if !ok {
+			return "", false
+		}
+		return operand + "." + sel.FieldName(), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,94 @@
+package expression_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/internal/domain/policy/expression"
+	"github.com/google/uuid"
+)
+
+func TestCompile_TypeChecks(t *testing.T) {
+	for _, src := range []string{
+		"",
+		"event.title ==",
+		"1 + 1",
+		"unknown.field == 1",
+		"project.after.title + 'x'",
+	} {
+		if _, err := expression.Compile(src); !errors.Is(err, expression.ErrInvalid) {
+			t.Errorf("Compile(%q): expected ErrInvalid, got %v", src, err)
+		}
+	}
+
+	x, err := expression.Compile("size(project.after.members) > 10 && has(custom_field.budget)")
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	want := []string{"project.after", "project.after.members", "custom_field.budget"}
+	for _, ref := range want {
+		found := false
+		for _, got := range x.References() {
+			found = found || got == ref
+		}
+		if !found {
+			t.Errorf("missing reference %q in %v", ref, x.References())
+		}
+	}
+}
+
+func TestEval_BeforeAfter(t *testing.T) {
+	type project struct {
+		EndDate time.Time
+		Members []uuid.UUID
+	}
+	end := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	before := expression.ToMap(project{EndDate: end})
+	after := expression.ToMap(project{EndDate: end.AddDate(0, 4, 0), Members: []uuid.UUID{uuid.New()}})
+
+	x, err := expression.Compile("project.after.end_date - project.before.end_date > duration('2160h') && size(project.after.members) == 1")
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	ok, err := x.Eval(context.Background(), expression.Input{Before: before, After: after})
+	if err != nil || !ok {
+		t.Fatalf("expected match, got %v %v", ok, err)
+	}
+
+	// Missing fields are evaluation errors rather than silent matches
+	x, _ = expression.Compile("event.title == 'x'")
+	if _, err := x.Eval(context.Background(), expression.Input{}); err == nil {
+		t.Fatal("expected an error for a missing field")
+	}
+}
+
+func TestCache_PerPolicyVersion(t *testing.T) {
+	cache := expression.NewCache()
+	id := uuid.New()
+
+	a, _ := cache.Get(id, 1, "true")
+	b, _ := cache.Get(id, 1, "true")
+	c, _ := cache.Get(id, 2, "true")
+	if a != b {
+		t.Error("expected the compiled expression to be reused within a version")
+	}
+	if a == c {
+		t.Error("expected a new version to be compiled again")
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	for in, want := range map[string]string{
+		"Title":                     "title",
+		"EndDate":                   "end_date",
+		"OwningOrgNodeID":           "owning_org_node_id",
+		"AffiliatedOrganisationIDs": "affiliated_organisation_ids",
+		"ID":                        "id",
+	} {
+		if got := expression.SnakeCase(in); got != want {
+			t.Errorf("SnakeCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package messagetemplate
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// filter transforms a value. Its arguments are checked when the template is parsed.
+type filter struct {
+	minArgs int
+	args    []tokenKind
+	apply   func(v any, args []any) any
+}
+
+func (f filter) arity() string {
+	switch {
+	case len(f.args) == 0:
+		return "no arguments"
+	case f.minArgs == len(f.args):
+		return fmt.Sprintf("%d argument(s)", len(f.args))
+	default:
+		return fmt.Sprintf("%d to %d argument(s)", f.minArgs, len(f.args))
+	}
+}
+
+// filters are the available filters:
+//
+//	date ["layout"]   formats a date with a Go layout, by default 2006-01-02
+//	truncate n        shortens text to at most n characters, ending in an ellipsis
+//	join ["sep"]      joins a list, by default with ", "
+//	upper, lower      changes the case of text
+//	default "text"    replaces a missing or empty value
+var filters = map[string]filter{
+	"date": {
+		args: []tokenKind{tokString},
+		apply: func(v any, args []any) any {
+			t, ok := toTime(v)
+			if !ok {
+				return v
+			}
+			if t.IsZero() {
+				return ""
+			}
+			return t.Format(stringArg(args, 0, time.DateOnly))
+		},
+	},
+	"truncate": {
+		minArgs: 1,
+		args:    []tokenKind{tokNumber},
+		apply: func(v any, args []any) any {
+			s, n := format(v), args[0].(int)
+			if utf8.RuneCountInString(s) <= n {
+				return s
+			}
+			if n == 0 {
+				return ""
+			}
+			return string([]rune(s)[:n-1]) + "…"
+		},
+	},
+	"join": {
+		args: []tokenKind{tokString},
+		apply: func(v any, args []any) any {
+			return join(list(v), stringArg(args, 0, ", "))
+		},
+	},
+	"upper": {
+		apply: func(v any, _ []any) any {
+			return strings.ToUpper(format(v))
+		},
+	},
+	"lower": {
+		apply: func(v any, _ []any) any {
+			return strings.ToLower(format(v))
+		},
+	},
+	"default": {
+		minArgs: 1,
+		args:    []tokenKind{tokString},
+		apply: func(v any, args []any) any {
+			if !truthy(v) {
+				return args[0]
+			}
+			return v
+		},
+	},
+}
+
+func stringArg(args []any, i int, fallback string) string {
+	if i < len(args) {
+		return args[i].(string)
+	}
+	return fallback
+}
+
+// toTime accepts times and the date strings of notification variables
+func toTime(v any) (time.Time, bool) {
+	switch x := v.(type) {
+	case time.Time:
+		return x, true
+	case *time.Time:
+		if x == nil {
+			return time.Time{}, true
+		}
+		return *x, true
+	case string:
+		for _, layout := range []string{time.RFC3339Nano, time.DateOnly} {
+			if t, err := time.Parse(layout, x); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,208 @@
+package messagetemplate
+
+import (
+	"fmt"
+	"go/token"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxOutput bounds the rendered message, lists are cut off beyond it
+const maxOutput = 64 * 1024
+
+// Render renders the template with the given data. A value is looked up by walking
+// maps by key and structs by exported field name; a key containing dots such as
+// "event.Title" is used when the walk finds nothing. Missing values render empty.
+func (t *Template) Render(data map[string]any) string {
+	r := &renderer{root: data}
+	r.nodes(t.nodes, nil)
+	return r.out.String()
+}
+
+type renderer struct {
+	root map[string]any
+	out  strings.Builder
+}
+
+// scope is the current item of an {{#each}} block
+type scope struct {
+	item  any
+	index int
+}
+
+func (r *renderer) nodes(nodes []node, s *scope) {
+	for _, n := range nodes {
+		if r.out.Len() > maxOutput {
+			return
+		}
+		switch n := n.(type) {
+		case textNode:
+			r.out.WriteString(n.text)
+		case valueNode:
+			v := r.lookup(n.path, s)
+			for _, f := range n.filters {
+				v = filters[f.name].apply(v, f.args)
+			}
+			r.out.WriteString(format(v))
+		case ifNode:
+			if truthy(r.lookup(n.path, s)) != n.negate {
+				r.nodes(n.then, s)
+			} else {
+				r.nodes(n.orElse, s)
+			}
+		case eachNode:
+			items := list(r.lookup(n.path, s))
+			if len(items) == 0 {
+				r.nodes(n.orElse, s)
+				continue
+			}
+			for i, item := range items {
+				r.nodes(n.body, &scope{item: item, index: i})
+			}
+		}
+	}
+}
+
+func (r *renderer) lookup(path []string, s *scope) any {
+	switch path[0] {
+	case "this":
+		if s == nil {
+			return nil
+		}
+		return walk(s.item, path[1:])
+	case "@index":
+		if s == nil || len(path) > 1 {
+			return nil
+		}
+		return s.index
+	}
+
+	if v := walk(r.root, path); v != nil {
+		return v
+	}
+	if v, ok := r.root[strings.Join(path, ".")]; ok {
+		return v
+	}
+	return nil
+}
+
+// walk follows a path through maps and structs and returns nil when a step is missing
+func walk(v any, path []string) any {
+	for _, key := range path {
+		rv := indirect(reflect.ValueOf(v))
+		if !rv.IsValid() {
+			return nil
+		}
+		switch rv.Kind() {
+		case reflect.Map:
+			if rv.Type().Key().Kind() != reflect.String {
+				return nil
+			}
+			e := rv.MapIndex(reflect.ValueOf(key).Convert(rv.Type().Key()))
+			if !e.IsValid() {
+				return nil
+			}
+			v = e.Interface()
+		case reflect.Struct:
+			// Only exported fields are reachable, methods are never called
+			if !token.IsExported(key) {
+				return nil
+			}
+			f := rv.FieldByName(key)
+			if !f.IsValid() || !f.CanInterface() {
+				return nil
+			}
+			v = f.Interface()
+		case reflect.Slice, reflect.Array:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= rv.Len() {
+				return nil
+			}
+			v = rv.Index(i).Interface()
+		default:
+			return nil
+		}
+	}
+	if rv := indirect(reflect.ValueOf(v)); !rv.IsValid() {
+		return nil
+	}
+	return v
+}
+
+// indirect dereferences pointers and interfaces; the result is invalid for nil
+func indirect(rv reflect.Value) reflect.Value {
+	for rv.IsValid() && (rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface) {
+		if rv.IsNil() {
+			return reflect.Value{}
+		}
+		rv = rv.Elem()
+	}
+	return rv
+}
+
+// truthy reports whether a value counts as set in {{#if}}: not nil, false, zero, empty or a zero time or UUID
+func truthy(v any) bool {
+	rv := indirect(reflect.ValueOf(v))
+	if !rv.IsValid() {
+		return false
+	}
+	if t, ok := rv.Interface().(time.Time); ok {
+		return !t.IsZero()
+	}
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Map, reflect.String:
+		return rv.Len() > 0
+	case reflect.Struct:
+		return true
+	default:
+		return !rv.IsZero()
+	}
+}
+
+// list returns the items of a slice or array value
+func list(v any) []any {
+	rv := indirect(reflect.ValueOf(v))
+	if !rv.IsValid() || (rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array) {
+		return nil
+	}
+	out := make([]any, rv.Len())
+	for i := range out {
+		out[i] = rv.Index(i).Interface()
+	}
+	return out
+}
+
+// format renders a value as text. Times without a date filter render as dates.
+func format(v any) string {
+	rv := indirect(reflect.ValueOf(v))
+	if !rv.IsValid() {
+		return ""
+	}
+	switch x := rv.Interface().(type) {
+	case string:
+		return x
+	case time.Time:
+		if x.IsZero() {
+			return ""
+		}
+		return x.Format(time.DateOnly)
+	case fmt.Stringer:
+		return x.String()
+	}
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		if _, ok := rv.Interface().([]byte); !ok {
+			return join(list(v), ", ")
+		}
+	}
+	return fmt.Sprint(rv.Interface())
+}
+
+func join(items []any, sep string) string {
+	parts := make([]string, len(items))
+	for i, item := range items {
+		parts[i] = format(item)
+	}
+	return strings.Join(parts, sep)
+}
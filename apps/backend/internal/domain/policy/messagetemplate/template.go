@@ -0,0 +1,396 @@
+// Package messagetemplate implements the templates of policy notification messages.
+//
+// Templates are sandboxed: they can only read the data they are rendered with, never
+// call methods, and are bounded in size and nesting. The syntax is:
+//
+//	{{project.Title}}                      a value, missing values render empty
+//	{{event.EndDate | date "2 Jan 2006"}}  a value passed through filters
+//	{{#if person}}...{{else}}...{{/if}}    a conditional, also {{#unless ...}}
+//	{{#each project.Members}}{{this.PersonID}}{{/each}}  a list, with {{@index}}
+//
+// Paths use the Go field names of the data, e.g. event.StartDate or person.Name.
+// The filters are date, truncate, join, upper, lower and default.
+package messagetemplate
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxLength = 8192
+	maxDepth  = 8
+)
+
+// ErrInvalid is returned for templates that do not parse
+var ErrInvalid = errors.New("invalid message template")
+
+// SyntaxError reports the position of a template error. Lines and columns start at 1.
+type SyntaxError struct {
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Msg)
+}
+
+func (e *SyntaxError) Unwrap() error {
+	return ErrInvalid
+}
+
+// Template is a parsed message template
+type Template struct {
+	source string
+	nodes  []node
+}
+
+// Parse parses a template, checking its syntax and the names and arguments of its filters
+func Parse(source string) (*Template, error) {
+	if len(source) > maxLength {
+		return nil, &SyntaxError{Line: 1, Column: 1, Msg: fmt.Sprintf("template is longer than %d characters", maxLength)}
+	}
+	p := &parser{src: source}
+	nodes, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	return &Template{source: source, nodes: nodes}, nil
+}
+
+// Source returns the template text
+func (t *Template) Source() string {
+	return t.source
+}
+
+// node is a part of a parsed template
+type node interface{ isNode() }
+
+type textNode struct{ text string }
+
+type valueNode struct {
+	path    []string
+	filters []filterCall
+}
+
+type ifNode struct {
+	path   []string
+	negate bool
+	then   []node
+	orElse []node
+}
+
+type eachNode struct {
+	path   []string
+	body   []node
+	orElse []node
+}
+
+func (textNode) isNode()  {}
+func (valueNode) isNode() {}
+func (ifNode) isNode()    {}
+func (eachNode) isNode()  {}
+
+type filterCall struct {
+	name string
+	args []any
+}
+
+// tag is the text between {{ and }} with its offset in the source
+type tag struct {
+	content string
+	offset  int // of the first byte of content
+	start   int // of the opening braces
+}
+
+type parser struct {
+	src string
+	pos int
+}
+
+func (p *parser) errorAt(offset int, format string, args ...any) error {
+	line, col := 1, 1
+	for _, r := range p.src[:offset] {
+		if r == '\n' {
+			line++
+			col = 1
+		} else {
+			col++
+		}
+	}
+	return &SyntaxError{Line: line, Column: col, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) parse() ([]node, error) {
+	nodes, closing, err := p.parseNodes(0)
+	if err != nil {
+		return nil, err
+	}
+	if closing != nil {
+		return nil, p.errorAt(closing.start, "unexpected {{%s}}", strings.TrimSpace(closing.content))
+	}
+	return nodes, nil
+}
+
+// parseNodes parses until the end of the source or a {{else}} or {{/...}} tag, which it returns
+func (p *parser) parseNodes(depth int) ([]node, *tag, error) {
+	var nodes []node
+	for p.pos < len(p.src) {
+		open := strings.Index(p.src[p.pos:], "{{")
+		if open < 0 {
+			nodes = append(nodes, textNode{text: p.src[p.pos:]})
+			p.pos = len(p.src)
+			break
+		}
+		if open > 0 {
+			nodes = append(nodes, textNode{text: p.src[p.pos : p.pos+open]})
+		}
+		start := p.pos + open
+		end := strings.Index(p.src[start+2:], "}}")
+		if end < 0 {
+			return nil, nil, p.errorAt(start, "unclosed {{")
+		}
+		t := tag{content: p.src[start+2 : start+2+end], offset: start + 2, start: start}
+		p.pos = start + 2 + end + 2
+
+		trimmed := strings.TrimSpace(t.content)
+		switch {
+		case trimmed == "else" || strings.HasPrefix(trimmed, "/"):
+			return nodes, &t, nil
+		case strings.HasPrefix(trimmed, "#"):
+			if depth >= maxDepth {
+				return nil, nil, p.errorAt(t.start, "blocks are nested more than %d levels deep", maxDepth)
+			}
+			n, err := p.parseBlock(t, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			nodes = append(nodes, n)
+		default:
+			n, err := p.parseValue(t)
+			if err != nil {
+				return nil, nil, err
+			}
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes, nil, nil
+}
+
+func (p *parser) parseBlock(t tag, depth int) (node, error) {
+	toks, err := p.tokenize(t)
+	if err != nil {
+		return nil, err
+	}
+	keyword := strings.TrimPrefix(toks[0].text, "#")
+	switch keyword {
+	case "if", "unless", "each":
+	default:
+		return nil, p.errorAt(toks[0].offset, "unknown block %q, expected #if, #unless or #each", toks[0].text)
+	}
+	if len(toks) < 2 || toks[1].kind != tokPath {
+		return nil, p.errorAt(t.start, "{{#%s}} requires a value", keyword)
+	}
+	if len(toks) > 2 {
+		return nil, p.errorAt(toks[2].offset, "unexpected %q after {{#%s %s}}", toks[2].text, keyword, toks[1].text)
+	}
+	path, err := p.path(toks[1])
+	if err != nil {
+		return nil, err
+	}
+
+	body, closing, err := p.parseNodes(depth)
+	if err != nil {
+		return nil, err
+	}
+	var orElse []node
+	if closing != nil && strings.TrimSpace(closing.content) == "else" {
+		orElse, closing, err = p.parseNodes(depth)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if closing == nil {
+		return nil, p.errorAt(t.start, "{{#%s}} is not closed, expected {{/%s}}", keyword, keyword)
+	}
+	if name := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(closing.content), "/")); name != keyword {
+		return nil, p.errorAt(closing.start, "{{/%s}} does not close {{#%s}}", name, keyword)
+	}
+
+	if keyword == "each" {
+		return eachNode{path: path, body: body, orElse: orElse}, nil
+	}
+	return ifNode{path: path, negate: keyword == "unless", then: body, orElse: orElse}, nil
+}
+
+func (p *parser) parseValue(t tag) (node, error) {
+	toks, err := p.tokenize(t)
+	if err != nil {
+		return nil, err
+	}
+	if toks[0].kind != tokPath {
+		return nil, p.errorAt(toks[0].offset, "expected a value, got %q", toks[0].text)
+	}
+	path, err := p.path(toks[0])
+	if err != nil {
+		return nil, err
+	}
+
+	n := valueNode{path: path}
+	rest := toks[1:]
+	for len(rest) > 0 {
+		if rest[0].kind != tokPipe {
+			return nil, p.errorAt(rest[0].offset, "unexpected %q, expected | before a filter", rest[0].text)
+		}
+		if len(rest) < 2 || rest[1].kind != tokPath {
+			return nil, p.errorAt(rest[0].offset, "expected a filter name after |")
+		}
+		nameTok := rest[1]
+		rest = rest[2:]
+
+		var args []lexeme
+		for len(rest) > 0 && rest[0].kind != tokPipe {
+			args = append(args, rest[0])
+			rest = rest[1:]
+		}
+		call, err := p.filter(nameTok, args)
+		if err != nil {
+			return nil, err
+		}
+		n.filters = append(n.filters, call)
+	}
+	return n, nil
+}
+
+func (p *parser) filter(name lexeme, args []lexeme) (filterCall, error) {
+	f, ok := filters[name.text]
+	if !ok {
+		return filterCall{}, p.errorAt(name.offset, "unknown filter %q", name.text)
+	}
+	if len(args) < f.minArgs || len(args) > len(f.args) {
+		return filterCall{}, p.errorAt(name.offset, "filter %q takes %s", name.text, f.arity())
+	}
+	call := filterCall{name: name.text}
+	for i, a := range args {
+		if a.kind != f.args[i] {
+			return filterCall{}, p.errorAt(a.offset, "argument %d of filter %q must be a %s", i+1, name.text, f.args[i])
+		}
+		call.args = append(call.args, a.value)
+	}
+	return call, nil
+}
+
+func (p *parser) path(t lexeme) ([]string, error) {
+	parts := strings.Split(t.text, ".")
+	for _, part := range parts {
+		if part == "" {
+			return nil, p.errorAt(t.offset, "invalid path %q", t.text)
+		}
+	}
+	return parts, nil
+}
+
+type tokenKind int
+
+const (
+	tokPath tokenKind = iota
+	tokString
+	tokNumber
+	tokPipe
+)
+
+func (k tokenKind) String() string {
+	switch k {
+	case tokString:
+		return "quoted string"
+	case tokNumber:
+		return "number"
+	case tokPipe:
+		return "|"
+	default:
+		return "name"
+	}
+}
+
+type lexeme struct {
+	kind   tokenKind
+	text   string
+	value  any
+	offset int
+}
+
+// tokenize splits the content of a tag into paths, quoted strings, numbers and pipes
+func (p *parser) tokenize(t tag) ([]lexeme, error) {
+	var toks []lexeme
+	s := t.content
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		offset := t.offset + i
+		switch {
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			i += size
+		case r == '|':
+			toks = append(toks, lexeme{kind: tokPipe, text: "|", offset: offset})
+			i++
+		case r == '"':
+			var b strings.Builder
+			j := i + 1
+			closed := false
+			for j < len(s) {
+				c := s[j]
+				if c == '\\' && j+1 < len(s) {
+					b.WriteByte(s[j+1])
+					j += 2
+					continue
+				}
+				if c == '"' {
+					closed = true
+					break
+				}
+				b.WriteByte(c)
+				j++
+			}
+			if !closed {
+				return nil, p.errorAt(offset, "unterminated string")
+			}
+			toks = append(toks, lexeme{kind: tokString, text: s[i : j+1], value: b.String(), offset: offset})
+			i = j + 1
+		case r >= '0' && r <= '9':
+			j := i
+			n := 0
+			for j < len(s) && s[j] >= '0' && s[j] <= '9' {
+				n = n*10 + int(s[j]-'0')
+				if n > 1_000_000 {
+					return nil, p.errorAt(offset, "number is too large")
+				}
+				j++
+			}
+			toks = append(toks, lexeme{kind: tokNumber, text: s[i:j], value: n, offset: offset})
+			i = j
+		case isNameRune(r) || r == '#' || r == '@':
+			j := i + size
+			for j < len(s) {
+				r2, size2 := utf8.DecodeRuneInString(s[j:])
+				if !isNameRune(r2) && r2 != '.' {
+					break
+				}
+				j += size2
+			}
+			toks = append(toks, lexeme{kind: tokPath, text: s[i:j], offset: offset})
+			i = j
+		default:
+			return nil, p.errorAt(offset, "unexpected character %q", r)
+		}
+	}
+	if len(toks) == 0 {
+		return nil, p.errorAt(t.start, "empty {{}}")
+	}
+	return toks, nil
+}
+
+func isNameRune(r rune) bool {
+	return r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
@@ -0,0 +1,122 @@
+package messagetemplate_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/internal/domain/policy/messagetemplate"
+	"github.com/google/uuid"
+)
+
+type person struct {
+	Name  string
+	Email string
+}
+
+type member struct {
+	Person person
+	Role   string
+}
+
+func render(t *testing.T, source string, data map[string]any) string {
+	t.Helper()
+	tmpl, err := messagetemplate.Parse(source)
+	if err != nil {
+		t.Fatalf("parse %q: %v", source, err)
+	}
+	return tmpl.Render(data)
+}
+
+func TestRender(t *testing.T) {
+	end := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	orgID := uuid.MustParse("6f1c3d4e-0000-4000-8000-000000000001")
+	data := map[string]any{
+		"event": struct {
+			EndDate         time.Time
+			OwningOrgNodeID uuid.UUID
+			secret          string
+		}{EndDate: end, OwningOrgNodeID: orgID, secret: "hidden"},
+		"event.Extra": "flat",
+		"project": map[string]any{
+			"Title": "A rather long project title",
+			"Members": []member{
+				{Person: person{Name: "Ada"}, Role: "lead"},
+				{Person: person{Name: "Bob"}, Role: "member"},
+			},
+			"Keywords": []string{"ai", "ethics"},
+		},
+		"person":  &person{Name: "Ada", Email: "ada@example.org"},
+		"product": (*person)(nil),
+	}
+
+	tests := map[string]struct{ source, want string }{
+		"value":             {"Ends {{event.EndDate}}", "Ends 2026-03-01"},
+		"date layout":       {`{{ event.EndDate | date "2 January 2006" }}`, "1 March 2026"},
+		"uuid":              {"{{event.OwningOrgNodeID}}", orgID.String()},
+		"flat key":          {"{{event.Extra}}", "flat"},
+		"missing":           {"[{{event.Nope}}]", "[]"},
+		"unexported":        {"[{{event.secret}}]", "[]"},
+		"truncate":          {"{{project.Title | truncate 10}}", "A rather …"},
+		"truncate short":    {"{{person.Name | truncate 10}}", "Ada"},
+		"join":              {`{{project.Keywords | join " & "}}`, "ai & ethics"},
+		"join default":      {"{{project.Keywords | join}}", "ai, ethics"},
+		"chained":           {`{{project.Keywords | join | upper}}`, "AI, ETHICS"},
+		"default":           {`{{product.Name | default "none"}}`, "none"},
+		"if":                {"{{#if person}}by {{person.Name}}{{/if}}", "by Ada"},
+		"if else":           {"{{#if product}}x{{else}}no product{{/if}}", "no product"},
+		"unless":            {"{{#unless product}}none{{/unless}}", "none"},
+		"each":              {"{{#each project.Members}}{{@index}}:{{this.Person.Name}} ({{this.Role}}) {{/each}}", "0:Ada (lead) 1:Bob (member) "},
+		"each this":         {"{{#each project.Keywords}}#{{this}} {{/each}}", "#ai #ethics "},
+		"each empty":        {"{{#each project.Nope}}x{{else}}empty{{/each}}", "empty"},
+		"each outer":        {"{{#each project.Keywords}}{{person.Name}}{{/each}}", "AdaAda"},
+		"index":             {"{{project.Members.1.Person.Name}}", "Bob"},
+		"lone braces":       {"a } b {c} }}", "a } b {c} }}"},
+		"multiline":         {"Hi {{person.Name}},\n{{#if person.Email}}mail {{person.Email | lower}}{{/if}}", "Hi Ada,\nmail ada@example.org"},
+		"date from string":  {`{{event.Extra | date}}`, "flat"},
+		"string date value": {`{{date | date "Jan 2006"}}`, "Mar 2026"},
+	}
+	data["date"] = "2026-03-01"
+
+	for name, tc := range tests {
+		if got := render(t, tc.source, data); got != tc.want {
+			t.Errorf("%s: got %q, want %q", name, got, tc.want)
+		}
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := map[string]struct {
+		source       string
+		line, column int
+	}{
+		"unclosed tag":     {"Hello {{person.Name", 1, 7},
+		"unknown filter":   {"{{person.Name | shout}}", 1, 17},
+		"missing argument": {"a\n  {{x | truncate}}", 2, 9},
+		"wrong argument":   {`{{x | truncate "ten"}}`, 1, 16},
+		"too many args":    {`{{x | upper "a"}}`, 1, 7},
+		"unclosed block":   {"ok\n{{#if x}}yes", 2, 1},
+		"mismatched close": {"{{#if x}}yes{{/each}}", 1, 13},
+		"stray close":      {"{{/if}}", 1, 1},
+		"unknown block":    {"{{#with x}}{{/with}}", 1, 3},
+		"empty":            {"{{ }}", 1, 1},
+		"bad character":    {"{{x + 1}}", 1, 5},
+		"unterminated":     {`{{x | default "abc}}`, 1, 15},
+		"empty path":       {"{{a..b}}", 1, 3},
+	}
+
+	for name, tc := range tests {
+		_, err := messagetemplate.Parse(tc.source)
+		var syntaxErr *messagetemplate.SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("%s: expected a syntax error, got %v", name, err)
+			continue
+		}
+		if !errors.Is(err, messagetemplate.ErrInvalid) {
+			t.Errorf("%s: expected ErrInvalid", name)
+		}
+		if syntaxErr.Line != tc.line || syntaxErr.Column != tc.column {
+			t.Errorf("%s: got %s, want line %d, column %d", name, err, tc.line, tc.column)
+		}
+	}
+}
@@ -11,6 +11,18 @@ import (
 type Member struct {
 	PersonID      uuid.UUID
 	ProjectRoleID uuid.UUID
+	// StartDate and EndDate bound the person's position in the project; nil means unbounded.
+	// The end date itself is no longer part of the position.
+	StartDate *time.Time
+	EndDate   *time.Time
+}
+
+// ActiveAt reports whether the member holds the role at t
+func (m Member) ActiveAt(t time.Time) bool {
+	if m.StartDate != nil && t.Before(*m.StartDate) {
+		return false
+	}
+	return m.EndDate == nil || t.Before(*m.EndDate)
 }
 
 type Project struct {
@@ -25,11 +37,31 @@ type Project struct {
 	ProductIDs                []uuid.UUID
 	AffiliatedOrganisationIDs []uuid.UUID
 	CustomFields              map[string]any
+	Visibility                Visibility
+	// PolicyIDs are the event policies added by the project itself
+	PolicyIDs []uuid.UUID
 }
 
 type MemberDetail struct {
-	Person identity.Person
-	Role   role.ProjectRole
+	Person    identity.Person
+	Role      role.ProjectRole
+	StartDate *time.Time
+	EndDate   *time.Time
+}
+
+// ActiveAt reports whether the member holds the role at t
+func (m MemberDetail) ActiveAt(t time.Time) bool {
+	return Member{StartDate: m.StartDate, EndDate: m.EndDate}.ActiveAt(t)
+}
+
+// IsActiveMember reports whether the person holds any role on the project at t
+func (p *Project) IsActiveMember(personID uuid.UUID, t time.Time) bool {
+	for _, m := range p.Members {
+		if m.PersonID == personID && m.ActiveAt(t) {
+			return true
+		}
+	}
+	return false
 }
 
 type ChangeLogEntry struct {
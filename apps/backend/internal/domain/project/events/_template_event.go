@@ -17,6 +17,7 @@ import (
 	"fmt"
 
 	"github.com/SURF-Innovatie/MORIS/ent"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/locale"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/project"
 	"github.com/google/uuid"
 )
@@ -153,6 +154,12 @@ func init() {
 	)
 
 	RegisterInputType(TemplateEventType, TemplateEventInput{})
+
+	// Texts in other languages; missing fields fall back to the English texts above
+	RegisterTranslation(TemplateEventType, locale.Dutch, Translation{
+		FriendlyName:         "Voorbeeldevent",
+		NotificationTemplate: "Project '{{project.Title}}' is bijgewerkt.",
+	})
 }
 
 // Unused import guard (remove when uncommenting init)
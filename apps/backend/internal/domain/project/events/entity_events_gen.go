@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/SURF-Innovatie/MORIS/internal/domain/locale"
 	projdomain "github.com/SURF-Innovatie/MORIS/internal/domain/project"
 	"github.com/google/uuid"
 )
@@ -383,6 +384,20 @@ func init() {
 			return DecideProductRemoved(projectID, actor, cur, in, status)
 		})
 	RegisterInputType(ProductRemovedType, ProductRemovedInput{})
+	RegisterTranslation(ProductAddedType, locale.Dutch, Translation{
+		FriendlyName:            "Producttoevoeging",
+		NotificationTemplate:    "Product '{{product.Name}}' is toegevoegd aan project '{{project.Title}}'",
+		ApprovalRequestTemplate: "Verzoek om product '{{product.Name}}' toe te voegen vereist goedkeuring",
+		ApprovedTemplate:        "Toevoegen van product '{{product.Name}}' goedgekeurd",
+		RejectedTemplate:        "Toevoegen van product '{{product.Name}}' afgewezen",
+	})
+	RegisterTranslation(ProductRemovedType, locale.Dutch, Translation{
+		FriendlyName:            "Productverwijdering",
+		NotificationTemplate:    "Product '{{product.Name}}' is verwijderd uit project '{{project.Title}}'",
+		ApprovalRequestTemplate: "Verzoek om product '{{product.Name}}' te verwijderen vereist goedkeuring",
+		ApprovedTemplate:        "Verwijderen van product '{{product.Name}}' goedgekeurd",
+		RejectedTemplate:        "Verwijderen van product '{{product.Name}}' afgewezen",
+	})
 	RegisterMeta(AffiliatedOrganisationAddedMeta, func() Event {
 		return &AffiliatedOrganisationAdded{Base: Base{FriendlyNameStr: AffiliatedOrganisationAddedMeta.FriendlyName}}
 	})
@@ -400,4 +415,18 @@ func init() {
 			return DecideAffiliatedOrganisationRemoved(projectID, actor, cur, in, status)
 		})
 	RegisterInputType(AffiliatedOrganisationRemovedType, AffiliatedOrganisationRemovedInput{})
+	RegisterTranslation(AffiliatedOrganisationAddedType, locale.Dutch, Translation{
+		FriendlyName:            "Toevoeging gelieerde organisatie",
+		NotificationTemplate:    "Gelieerde organisatie '{{affiliated_organisation.Name}}' is toegevoegd aan project '{{project.Title}}'",
+		ApprovalRequestTemplate: "Verzoek om gelieerde organisatie '{{affiliated_organisation.Name}}' toe te voegen vereist goedkeuring",
+		ApprovedTemplate:        "Toevoegen van gelieerde organisatie '{{affiliated_organisation.Name}}' goedgekeurd",
+		RejectedTemplate:        "Toevoegen van gelieerde organisatie '{{affiliated_organisation.Name}}' afgewezen",
+	})
+	RegisterTranslation(AffiliatedOrganisationRemovedType, locale.Dutch, Translation{
+		FriendlyName:            "Verwijdering gelieerde organisatie",
+		NotificationTemplate:    "Gelieerde organisatie '{{affiliated_organisation.Name}}' is verwijderd uit project '{{project.Title}}'",
+		ApprovalRequestTemplate: "Verzoek om gelieerde organisatie '{{affiliated_organisation.Name}}' te verwijderen vereist goedkeuring",
+		ApprovedTemplate:        "Verwijderen van gelieerde organisatie '{{affiliated_organisation.Name}}' goedgekeurd",
+		RejectedTemplate:        "Verwijderen van gelieerde organisatie '{{affiliated_organisation.Name}}' afgewezen",
+	})
 }
@@ -20,6 +20,9 @@ type Event interface {
 	FriendlyName() string
 	String() string
 	CreatedByID() uuid.UUID
+	// ImpersonatorID is the sysadmin who appended the event while acting as its creator, if any
+	ImpersonatorID() *uuid.UUID
+	SetImpersonator(uuid.UUID)
 	GetStatus() Status
 	SetBase(Base)
 }
@@ -85,6 +88,9 @@ type Base struct {
 	At              time.Time `json:"at"`
 	CreatedBy       uuid.UUID `json:"createdBy"`
 	Status          Status    `json:"status"`
+
+	// Impersonator is the sysadmin who appended the event while acting as CreatedBy
+	Impersonator *uuid.UUID `json:"impersonatorId,omitempty"`
 }
 
 func NewBase(projectID, actor uuid.UUID, status Status) Base {
@@ -104,6 +110,9 @@ func (b *Base) CreatedByID() uuid.UUID { return b.CreatedBy }
 func (b *Base) GetStatus() Status      { return b.Status }
 func (b *Base) SetBase(base Base)      { *b = base }
 
+func (b *Base) ImpersonatorID() *uuid.UUID       { return b.Impersonator }
+func (b *Base) SetImpersonator(userID uuid.UUID) { b.Impersonator = &userID }
+
 // Registry
 var (
 	eventRegistry = make(map[string]func() Event)
@@ -2,8 +2,12 @@ package events
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 
+	"github.com/SURF-Innovatie/MORIS/internal/domain/locale"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/policy"
 	projdomain "github.com/SURF-Innovatie/MORIS/internal/domain/project"
 	"github.com/google/uuid"
 )
@@ -15,16 +19,21 @@ const EventPolicyUpdatedType = "project.event_policy_updated"
 // EventPolicyAdded represents adding an event policy to a project
 type EventPolicyAdded struct {
 	Base
-	PolicyID                uuid.UUID   `json:"policy_id"`
-	Name                    string      `json:"name"`
-	Description             *string     `json:"description,omitempty"`
-	EventTypes              []string    `json:"event_types"`
-	ActionType              string      `json:"action_type"` // "notify" or "request_approval"
-	RecipientUserIDs        []uuid.UUID `json:"recipient_user_ids,omitempty"`
-	RecipientProjectRoleIDs []uuid.UUID `json:"recipient_project_role_ids,omitempty"`
-	RecipientOrgRoleIDs     []uuid.UUID `json:"recipient_org_role_ids,omitempty"`
-	RecipientDynamic        []string    `json:"recipient_dynamic,omitempty"`
-	Enabled                 bool        `json:"enabled"`
+	PolicyID                uuid.UUID          `json:"policy_id"`
+	Name                    string             `json:"name"`
+	Description             *string            `json:"description,omitempty"`
+	EventTypes              []string           `json:"event_types"`
+	ActionType              string             `json:"action_type"`            // "notify" or "request_approval"
+	TriggerType             string             `json:"trigger_type,omitempty"` // "event" (default) or "schedule"
+	ScheduleAnchor          *string            `json:"schedule_anchor,omitempty"`
+	ScheduleOffsetDays      *int               `json:"schedule_offset_days,omitempty"`
+	Conditions              []policy.Condition `json:"conditions,omitempty"`
+	MessageTemplate         *string            `json:"message_template,omitempty"`
+	RecipientUserIDs        []uuid.UUID        `json:"recipient_user_ids,omitempty"`
+	RecipientProjectRoleIDs []uuid.UUID        `json:"recipient_project_role_ids,omitempty"`
+	RecipientOrgRoleIDs     []uuid.UUID        `json:"recipient_org_role_ids,omitempty"`
+	RecipientDynamic        []string           `json:"recipient_dynamic,omitempty"`
+	Enabled                 bool               `json:"enabled"`
 }
 
 func (EventPolicyAdded) isEvent()     {}
@@ -34,7 +43,10 @@ func (e EventPolicyAdded) String() string {
 }
 
 func (e *EventPolicyAdded) Apply(p *projdomain.Project) {
-	// Policies are stored separately, not on project entity directly
+	// Policies are stored separately; the project only tracks which ones it owns
+	if !slices.Contains(p.PolicyIDs, e.PolicyID) {
+		p.PolicyIDs = append(p.PolicyIDs, e.PolicyID)
+	}
 }
 
 func (e *EventPolicyAdded) NotificationTemplate() string {
@@ -60,15 +72,20 @@ func (e *EventPolicyAdded) NotificationVariables() map[string]string {
 }
 
 type EventPolicyAddedInput struct {
-	Name                    string      `json:"name"`
-	Description             *string     `json:"description,omitempty"`
-	EventTypes              []string    `json:"event_types"`
-	ActionType              string      `json:"action_type"`
-	RecipientUserIDs        []uuid.UUID `json:"recipient_user_ids,omitempty"`
-	RecipientProjectRoleIDs []uuid.UUID `json:"recipient_project_role_ids,omitempty"`
-	RecipientOrgRoleIDs     []uuid.UUID `json:"recipient_org_role_ids,omitempty"`
-	RecipientDynamic        []string    `json:"recipient_dynamic,omitempty"`
-	Enabled                 bool        `json:"enabled"`
+	Name                    string             `json:"name"`
+	Description             *string            `json:"description,omitempty"`
+	EventTypes              []string           `json:"event_types"`
+	ActionType              string             `json:"action_type"`
+	TriggerType             string             `json:"trigger_type,omitempty"` // "event" (default) or "schedule"
+	ScheduleAnchor          *string            `json:"schedule_anchor,omitempty"`
+	ScheduleOffsetDays      *int               `json:"schedule_offset_days,omitempty"`
+	Conditions              []policy.Condition `json:"conditions,omitempty"`
+	MessageTemplate         *string            `json:"message_template,omitempty"`
+	RecipientUserIDs        []uuid.UUID        `json:"recipient_user_ids,omitempty"`
+	RecipientProjectRoleIDs []uuid.UUID        `json:"recipient_project_role_ids,omitempty"`
+	RecipientOrgRoleIDs     []uuid.UUID        `json:"recipient_org_role_ids,omitempty"`
+	RecipientDynamic        []string           `json:"recipient_dynamic,omitempty"`
+	Enabled                 bool               `json:"enabled"`
 }
 
 func DecideEventPolicyAdded(
@@ -80,19 +97,51 @@ func DecideEventPolicyAdded(
 	base := NewBase(projectID, actor, status)
 	base.FriendlyNameStr = EventPolicyAddedMeta.FriendlyName
 
-	return &EventPolicyAdded{
+	evt := &EventPolicyAdded{
 		Base:                    base,
 		PolicyID:                uuid.New(), // Generate new policy ID
 		Name:                    in.Name,
 		Description:             in.Description,
 		EventTypes:              in.EventTypes,
 		ActionType:              in.ActionType,
+		TriggerType:             in.TriggerType,
+		ScheduleAnchor:          in.ScheduleAnchor,
+		ScheduleOffsetDays:      in.ScheduleOffsetDays,
+		Conditions:              in.Conditions,
+		MessageTemplate:         in.MessageTemplate,
 		RecipientUserIDs:        in.RecipientUserIDs,
 		RecipientProjectRoleIDs: in.RecipientProjectRoleIDs,
 		RecipientOrgRoleIDs:     in.RecipientOrgRoleIDs,
 		RecipientDynamic:        in.RecipientDynamic,
 		Enabled:                 in.Enabled,
-	}, nil
+	}
+	if err := validateProjectPolicy(evt.ToPolicy()); err != nil {
+		return nil, err
+	}
+	return evt, nil
+}
+
+// ToPolicy returns the project policy defined by the event
+func (e *EventPolicyAdded) ToPolicy() policy.EventPolicy {
+	projectID := e.AggregateID()
+	return policy.EventPolicy{
+		ID:                      e.PolicyID,
+		Name:                    e.Name,
+		Description:             e.Description,
+		TriggerType:             triggerTypeOrDefault(e.TriggerType),
+		EventTypes:              e.EventTypes,
+		ScheduleAnchor:          e.ScheduleAnchor,
+		ScheduleOffsetDays:      e.ScheduleOffsetDays,
+		Conditions:              e.Conditions,
+		ActionType:              policy.ActionType(e.ActionType),
+		MessageTemplate:         e.MessageTemplate,
+		RecipientUserIDs:        e.RecipientUserIDs,
+		RecipientProjectRoleIDs: e.RecipientProjectRoleIDs,
+		RecipientOrgRoleIDs:     e.RecipientOrgRoleIDs,
+		RecipientDynamic:        e.RecipientDynamic,
+		ProjectID:               &projectID,
+		Enabled:                 e.Enabled,
+	}
 }
 
 // EventPolicyRemoved represents removing an event policy from a project
@@ -109,7 +158,7 @@ func (e EventPolicyRemoved) String() string {
 }
 
 func (e *EventPolicyRemoved) Apply(p *projdomain.Project) {
-	// Policies are stored separately
+	p.PolicyIDs = slices.DeleteFunc(p.PolicyIDs, func(id uuid.UUID) bool { return id == e.PolicyID })
 }
 
 func (e *EventPolicyRemoved) NotificationTemplate() string {
@@ -142,12 +191,17 @@ type EventPolicyRemovedInput struct {
 func DecideEventPolicyRemoved(
 	projectID uuid.UUID,
 	actor uuid.UUID,
+	cur *projdomain.Project,
 	in EventPolicyRemovedInput,
 	status Status,
 ) (Event, error) {
 	base := NewBase(projectID, actor, status)
 	base.FriendlyNameStr = EventPolicyRemovedMeta.FriendlyName
 
+	if err := checkProjectPolicy(cur, in.PolicyID); err != nil {
+		return nil, err
+	}
+
 	return &EventPolicyRemoved{
 		Base:     base,
 		PolicyID: in.PolicyID,
@@ -158,16 +212,21 @@ func DecideEventPolicyRemoved(
 // EventPolicyUpdated represents modifying an existing event policy on a project
 type EventPolicyUpdated struct {
 	Base
-	PolicyID                uuid.UUID   `json:"policy_id"`
-	Name                    string      `json:"name"`
-	Description             *string     `json:"description,omitempty"`
-	EventTypes              []string    `json:"event_types"`
-	ActionType              string      `json:"action_type"`
-	RecipientUserIDs        []uuid.UUID `json:"recipient_user_ids,omitempty"`
-	RecipientProjectRoleIDs []uuid.UUID `json:"recipient_project_role_ids,omitempty"`
-	RecipientOrgRoleIDs     []uuid.UUID `json:"recipient_org_role_ids,omitempty"`
-	RecipientDynamic        []string    `json:"recipient_dynamic,omitempty"`
-	Enabled                 bool        `json:"enabled"`
+	PolicyID                uuid.UUID          `json:"policy_id"`
+	Name                    string             `json:"name"`
+	Description             *string            `json:"description,omitempty"`
+	EventTypes              []string           `json:"event_types"`
+	ActionType              string             `json:"action_type"`
+	TriggerType             string             `json:"trigger_type,omitempty"` // "event" (default) or "schedule"
+	ScheduleAnchor          *string            `json:"schedule_anchor,omitempty"`
+	ScheduleOffsetDays      *int               `json:"schedule_offset_days,omitempty"`
+	Conditions              []policy.Condition `json:"conditions,omitempty"`
+	MessageTemplate         *string            `json:"message_template,omitempty"`
+	RecipientUserIDs        []uuid.UUID        `json:"recipient_user_ids,omitempty"`
+	RecipientProjectRoleIDs []uuid.UUID        `json:"recipient_project_role_ids,omitempty"`
+	RecipientOrgRoleIDs     []uuid.UUID        `json:"recipient_org_role_ids,omitempty"`
+	RecipientDynamic        []string           `json:"recipient_dynamic,omitempty"`
+	Enabled                 bool               `json:"enabled"`
 }
 
 func (EventPolicyUpdated) isEvent()     {}
@@ -203,40 +262,150 @@ func (e *EventPolicyUpdated) NotificationVariables() map[string]string {
 }
 
 type EventPolicyUpdatedInput struct {
-	PolicyID                uuid.UUID   `json:"policy_id"`
-	Name                    string      `json:"name"`
-	Description             *string     `json:"description,omitempty"`
-	EventTypes              []string    `json:"event_types"`
-	ActionType              string      `json:"action_type"`
-	RecipientUserIDs        []uuid.UUID `json:"recipient_user_ids,omitempty"`
-	RecipientProjectRoleIDs []uuid.UUID `json:"recipient_project_role_ids,omitempty"`
-	RecipientOrgRoleIDs     []uuid.UUID `json:"recipient_org_role_ids,omitempty"`
-	RecipientDynamic        []string    `json:"recipient_dynamic,omitempty"`
-	Enabled                 bool        `json:"enabled"`
+	PolicyID                uuid.UUID          `json:"policy_id"`
+	Name                    string             `json:"name"`
+	Description             *string            `json:"description,omitempty"`
+	EventTypes              []string           `json:"event_types"`
+	ActionType              string             `json:"action_type"`
+	TriggerType             string             `json:"trigger_type,omitempty"` // "event" (default) or "schedule"
+	ScheduleAnchor          *string            `json:"schedule_anchor,omitempty"`
+	ScheduleOffsetDays      *int               `json:"schedule_offset_days,omitempty"`
+	Conditions              []policy.Condition `json:"conditions,omitempty"`
+	MessageTemplate         *string            `json:"message_template,omitempty"`
+	RecipientUserIDs        []uuid.UUID        `json:"recipient_user_ids,omitempty"`
+	RecipientProjectRoleIDs []uuid.UUID        `json:"recipient_project_role_ids,omitempty"`
+	RecipientOrgRoleIDs     []uuid.UUID        `json:"recipient_org_role_ids,omitempty"`
+	RecipientDynamic        []string           `json:"recipient_dynamic,omitempty"`
+	Enabled                 bool               `json:"enabled"`
 }
 
 func DecideEventPolicyUpdated(
 	projectID uuid.UUID,
 	actor uuid.UUID,
+	cur *projdomain.Project,
 	in EventPolicyUpdatedInput,
 	status Status,
 ) (Event, error) {
 	base := NewBase(projectID, actor, status)
 	base.FriendlyNameStr = EventPolicyUpdatedMeta.FriendlyName
 
-	return &EventPolicyUpdated{
+	if err := checkProjectPolicy(cur, in.PolicyID); err != nil {
+		return nil, err
+	}
+
+	evt := &EventPolicyUpdated{
 		Base:                    base,
 		PolicyID:                in.PolicyID,
 		Name:                    in.Name,
 		Description:             in.Description,
 		EventTypes:              in.EventTypes,
 		ActionType:              in.ActionType,
+		TriggerType:             in.TriggerType,
+		ScheduleAnchor:          in.ScheduleAnchor,
+		ScheduleOffsetDays:      in.ScheduleOffsetDays,
+		Conditions:              in.Conditions,
+		MessageTemplate:         in.MessageTemplate,
 		RecipientUserIDs:        in.RecipientUserIDs,
 		RecipientProjectRoleIDs: in.RecipientProjectRoleIDs,
 		RecipientOrgRoleIDs:     in.RecipientOrgRoleIDs,
 		RecipientDynamic:        in.RecipientDynamic,
 		Enabled:                 in.Enabled,
-	}, nil
+	}
+	if err := validateProjectPolicy(evt.ToPolicy()); err != nil {
+		return nil, err
+	}
+	return evt, nil
+}
+
+// checkProjectPolicy ensures that a policy was added by the project itself, so that project events
+// cannot change or remove inherited organisation policies or the policies of other projects
+func checkProjectPolicy(cur *projdomain.Project, policyID uuid.UUID) error {
+	if policyID == uuid.Nil {
+		return errors.New("policy_id is required")
+	}
+	if cur == nil {
+		return errors.New("current project is required")
+	}
+	if !slices.Contains(cur.PolicyIDs, policyID) {
+		return fmt.Errorf("event policy %s not found in project", policyID)
+	}
+	return nil
+}
+
+// ToPolicy returns the project policy as defined after the update
+func (e *EventPolicyUpdated) ToPolicy() policy.EventPolicy {
+	projectID := e.AggregateID()
+	return policy.EventPolicy{
+		ID:                      e.PolicyID,
+		Name:                    e.Name,
+		Description:             e.Description,
+		TriggerType:             triggerTypeOrDefault(e.TriggerType),
+		EventTypes:              e.EventTypes,
+		ScheduleAnchor:          e.ScheduleAnchor,
+		ScheduleOffsetDays:      e.ScheduleOffsetDays,
+		Conditions:              e.Conditions,
+		ActionType:              policy.ActionType(e.ActionType),
+		MessageTemplate:         e.MessageTemplate,
+		RecipientUserIDs:        e.RecipientUserIDs,
+		RecipientProjectRoleIDs: e.RecipientProjectRoleIDs,
+		RecipientOrgRoleIDs:     e.RecipientOrgRoleIDs,
+		RecipientDynamic:        e.RecipientDynamic,
+		ProjectID:               &projectID,
+		Enabled:                 e.Enabled,
+	}
+}
+
+// validateProjectPolicy validates a policy definition carried by a project event.
+// Webhook policies are not allowed here because their signing secret would end up in the event store.
+func validateProjectPolicy(p policy.EventPolicy) error {
+	if p.ActionType == policy.ActionTypeWebhook {
+		return fmt.Errorf("%w: webhook policies cannot be added through project events", policy.ErrInvalidPolicy)
+	}
+	return ValidatePolicy(p)
+}
+
+func triggerTypeOrDefault(t string) policy.TriggerType {
+	if t == "" {
+		return policy.TriggerTypeEvent
+	}
+	return policy.TriggerType(t)
+}
+
+// ProjectPolicies replays the policy events of a project's history and returns the
+// project policies as they are after the last applied event. Pending and rejected
+// events are skipped, matching how the project itself is reduced.
+func ProjectPolicies(history []Event) []policy.EventPolicy {
+	var order []uuid.UUID
+	byID := make(map[uuid.UUID]policy.EventPolicy)
+
+	for _, e := range history {
+		if st := e.GetStatus(); st == StatusPending || st == StatusRejected {
+			continue
+		}
+
+		switch evt := e.(type) {
+		case *EventPolicyAdded:
+			if _, ok := byID[evt.PolicyID]; !ok {
+				order = append(order, evt.PolicyID)
+			}
+			byID[evt.PolicyID] = evt.ToPolicy()
+		case *EventPolicyUpdated:
+			if _, ok := byID[evt.PolicyID]; !ok {
+				order = append(order, evt.PolicyID)
+			}
+			byID[evt.PolicyID] = evt.ToPolicy()
+		case *EventPolicyRemoved:
+			delete(byID, evt.PolicyID)
+		}
+	}
+
+	out := make([]policy.EventPolicy, 0, len(byID))
+	for _, id := range order {
+		if p, ok := byID[id]; ok {
+			out = append(out, p)
+		}
+	}
+	return out
 }
 
 var EventPolicyAddedMeta = EventMeta{
@@ -276,7 +445,7 @@ func init() {
 
 	RegisterDecider[EventPolicyRemovedInput](EventPolicyRemovedType,
 		func(ctx context.Context, projectID uuid.UUID, actor uuid.UUID, cur *projdomain.Project, in EventPolicyRemovedInput, status Status) (Event, error) {
-			return DecideEventPolicyRemoved(projectID, actor, in, status)
+			return DecideEventPolicyRemoved(projectID, actor, cur, in, status)
 		})
 
 	RegisterInputType(EventPolicyRemovedType, EventPolicyRemovedInput{})
@@ -290,8 +459,30 @@ func init() {
 
 	RegisterDecider[EventPolicyUpdatedInput](EventPolicyUpdatedType,
 		func(ctx context.Context, projectID uuid.UUID, actor uuid.UUID, cur *projdomain.Project, in EventPolicyUpdatedInput, status Status) (Event, error) {
-			return DecideEventPolicyUpdated(projectID, actor, in, status)
+			return DecideEventPolicyUpdated(projectID, actor, cur, in, status)
 		})
 
 	RegisterInputType(EventPolicyUpdatedType, EventPolicyUpdatedInput{})
+
+	RegisterTranslation(EventPolicyAddedType, locale.Dutch, Translation{
+		FriendlyName:            "Eventbeleid toegevoegd",
+		NotificationTemplate:    "Eventbeleid '{{event.Name}}' is aan het project toegevoegd.",
+		ApprovalRequestTemplate: "Het toevoegen van eventbeleid '{{event.Name}}' vereist goedkeuring.",
+		ApprovedTemplate:        "Eventbeleid '{{event.Name}}' is goedgekeurd en toegevoegd.",
+		RejectedTemplate:        "Het toevoegen van eventbeleid '{{event.Name}}' is afgewezen.",
+	})
+	RegisterTranslation(EventPolicyRemovedType, locale.Dutch, Translation{
+		FriendlyName:            "Eventbeleid verwijderd",
+		NotificationTemplate:    "Eventbeleid '{{event.Name}}' is uit het project verwijderd.",
+		ApprovalRequestTemplate: "Het verwijderen van eventbeleid '{{event.Name}}' vereist goedkeuring.",
+		ApprovedTemplate:        "Het verwijderen van eventbeleid '{{event.Name}}' is goedgekeurd.",
+		RejectedTemplate:        "Het verwijderen van eventbeleid '{{event.Name}}' is afgewezen.",
+	})
+	RegisterTranslation(EventPolicyUpdatedType, locale.Dutch, Translation{
+		FriendlyName:            "Eventbeleid bijgewerkt",
+		NotificationTemplate:    "Eventbeleid '{{event.Name}}' is bijgewerkt.",
+		ApprovalRequestTemplate: "Het bijwerken van eventbeleid '{{event.Name}}' vereist goedkeuring.",
+		ApprovedTemplate:        "Het bijwerken van eventbeleid '{{event.Name}}' is goedgekeurd.",
+		RejectedTemplate:        "Het bijwerken van eventbeleid '{{event.Name}}' is afgewezen.",
+	})
 }
@@ -0,0 +1,184 @@
+package events_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/SURF-Innovatie/MORIS/internal/domain/policy"
+	events2 "github.com/SURF-Innovatie/MORIS/internal/domain/project/events"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/project/projection"
+	"github.com/google/uuid"
+)
+
+func Test_EventPolicy_ValidatesConditions(t *testing.T) {
+	projectID, actor := uuid.New(), uuid.New()
+	in := events2.EventPolicyAddedInput{
+		Name:       "Secret titles",
+		EventTypes: []string{events2.TitleChangedType},
+		ActionType: string(policy.ActionTypeNotify),
+		Conditions: []policy.Condition{{Field: "event.Title", Operator: policy.OperatorStartsWith, Value: "Secret"}},
+	}
+
+	if _, err := events2.DecideEventPolicyAdded(projectID, actor, in, events2.StatusApproved); err != nil {
+		t.Fatalf("valid policy rejected: %v", err)
+	}
+
+	withExpression := in
+	withExpression.Conditions = []policy.Condition{{
+		Operator:   policy.OperatorExpression,
+		Expression: "event.title != project.before.title && actor.email.endsWith('@example.org')",
+	}}
+	if _, err := events2.DecideEventPolicyAdded(projectID, actor, withExpression, events2.StatusApproved); err != nil {
+		t.Fatalf("valid expression rejected: %v", err)
+	}
+
+	cases := map[string]policy.Condition{
+		"unknown operator":    {Field: "event.Title", Operator: "like", Value: "x"},
+		"unknown source":      {Field: "person.Name", Operator: policy.OperatorEquals, Value: "x"},
+		"unknown event field": {Field: "event.Budget", Operator: policy.OperatorEquals, Value: "x"},
+		"unknown project fld": {Field: "project.Budget", Operator: policy.OperatorEquals, Value: "x"},
+		"invalid expression":  {Operator: policy.OperatorExpression, Expression: "event.title +"},
+		"non-bool expression": {Operator: policy.OperatorExpression, Expression: "size(project.after.members)"},
+		"expression event":    {Operator: policy.OperatorExpression, Expression: "event.budget > 10"},
+		"expression project":  {Operator: policy.OperatorExpression, Expression: "project.title == 'x'"},
+		"expression state":    {Operator: policy.OperatorExpression, Expression: "project.after.budget > 10"},
+		"expression actor":    {Operator: policy.OperatorExpression, Expression: "actor.role == 'x'"},
+	}
+	for name, cond := range cases {
+		bad := in
+		bad.Conditions = []policy.Condition{cond}
+		if _, err := events2.DecideEventPolicyAdded(projectID, actor, bad, events2.StatusApproved); !errors.Is(err, policy.ErrInvalidPolicy) {
+			t.Fatalf("%s: expected ErrInvalidPolicy, got %v", name, err)
+		}
+	}
+}
+
+func Test_ProjectPolicies_Replay(t *testing.T) {
+	projectID, actor := uuid.New(), uuid.New()
+	tmpl := "Title is now {{event.Title}}"
+
+	added, err := events2.DecideEventPolicyAdded(projectID, actor, events2.EventPolicyAddedInput{
+		Name:            "Secret titles",
+		EventTypes:      []string{events2.TitleChangedType},
+		ActionType:      string(policy.ActionTypeNotify),
+		Conditions:      []policy.Condition{{Field: "event.Title", Operator: policy.OperatorStartsWith, Value: "Secret"}},
+		MessageTemplate: &tmpl,
+	}, events2.StatusApproved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	policyID := added.(*events2.EventPolicyAdded).PolicyID
+
+	other, _ := events2.DecideEventPolicyAdded(projectID, actor, events2.EventPolicyAddedInput{
+		Name:       "Other",
+		EventTypes: []string{events2.TitleChangedType},
+		ActionType: string(policy.ActionTypeNotify),
+	}, events2.StatusApproved)
+	cur := projection.Reduce(projectID, []events2.Event{added, other})
+
+	updated, err := events2.DecideEventPolicyUpdated(projectID, actor, cur, events2.EventPolicyUpdatedInput{
+		PolicyID:   policyID,
+		Name:       "Secret titles (v2)",
+		EventTypes: []string{events2.TitleChangedType},
+		ActionType: string(policy.ActionTypeNotify),
+		Conditions: []policy.Condition{{Field: "event.Title", Operator: policy.OperatorContains, Value: "Secret"}},
+	}, events2.StatusApproved)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pendingUpdate, _ := events2.DecideEventPolicyUpdated(projectID, actor, cur, events2.EventPolicyUpdatedInput{
+		PolicyID:   policyID,
+		Name:       "Not yet approved",
+		EventTypes: []string{events2.TitleChangedType},
+		ActionType: string(policy.ActionTypeNotify),
+	}, events2.StatusPending)
+
+	removed, _ := events2.DecideEventPolicyRemoved(projectID, actor, cur, events2.EventPolicyRemovedInput{
+		PolicyID: other.(*events2.EventPolicyAdded).PolicyID,
+		Name:     "Other",
+	}, events2.StatusApproved)
+
+	got := events2.ProjectPolicies([]events2.Event{added, other, updated, pendingUpdate, removed})
+	if len(got) != 1 {
+		t.Fatalf("expected 1 policy, got %d", len(got))
+	}
+	p := got[0]
+	if p.ID != policyID || p.Name != "Secret titles (v2)" || p.ProjectID == nil || *p.ProjectID != projectID {
+		t.Fatalf("unexpected policy: %+v", p)
+	}
+	if len(p.Conditions) != 1 || p.Conditions[0].Operator != policy.OperatorContains {
+		t.Fatalf("conditions not carried through: %+v", p.Conditions)
+	}
+}
+
+func Test_ProjectPolicies_OnlyOwnPoliciesCanBeChanged(t *testing.T) {
+	projectID, actor := uuid.New(), uuid.New()
+
+	added, err := events2.DecideEventPolicyAdded(projectID, actor, events2.EventPolicyAddedInput{
+		Name:       "Own",
+		EventTypes: []string{events2.TitleChangedType},
+		ActionType: string(policy.ActionTypeNotify),
+	}, events2.StatusApproved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pending, _ := events2.DecideEventPolicyAdded(projectID, actor, events2.EventPolicyAddedInput{
+		Name:       "Pending",
+		EventTypes: []string{events2.TitleChangedType},
+		ActionType: string(policy.ActionTypeNotify),
+	}, events2.StatusPending)
+	cur := projection.Reduce(projectID, []events2.Event{added, pending})
+
+	own := added.(*events2.EventPolicyAdded).PolicyID
+	for name, policyID := range map[string]uuid.UUID{
+		"organisation policy": uuid.New(),
+		"pending policy":      pending.(*events2.EventPolicyAdded).PolicyID,
+		"missing policy id":   uuid.Nil,
+	} {
+		if _, err := events2.DecideEventPolicyUpdated(projectID, actor, cur, events2.EventPolicyUpdatedInput{
+			PolicyID:   policyID,
+			Name:       "Disabled",
+			EventTypes: []string{events2.TitleChangedType},
+			ActionType: string(policy.ActionTypeNotify),
+		}, events2.StatusApproved); err == nil {
+			t.Errorf("%s: expected the update to be rejected", name)
+		}
+		if _, err := events2.DecideEventPolicyRemoved(projectID, actor, cur, events2.EventPolicyRemovedInput{PolicyID: policyID}, events2.StatusApproved); err == nil {
+			t.Errorf("%s: expected the removal to be rejected", name)
+		}
+	}
+
+	removed, err := events2.DecideEventPolicyRemoved(projectID, actor, cur, events2.EventPolicyRemovedInput{PolicyID: own, Name: "Own"}, events2.StatusApproved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cur = projection.Reduce(projectID, []events2.Event{added, pending, removed}); len(cur.PolicyIDs) != 0 {
+		t.Fatalf("expected the removed policy to be gone, got %v", cur.PolicyIDs)
+	}
+}
+
+func Test_EventPolicy_ValidatesMessageTemplate(t *testing.T) {
+	projectID, actor := uuid.New(), uuid.New()
+	valid := "{{#if person}}{{person.Name}} joined{{else}}Someone joined{{/if}} on {{event.At | date \"2 Jan 2006\"}}"
+	in := events2.EventPolicyAddedInput{
+		Name:            "Joiners",
+		EventTypes:      []string{events2.TitleChangedType},
+		ActionType:      string(policy.ActionTypeNotify),
+		MessageTemplate: &valid,
+	}
+	if _, err := events2.DecideEventPolicyAdded(projectID, actor, in, events2.StatusApproved); err != nil {
+		t.Fatalf("valid template rejected: %v", err)
+	}
+
+	invalid := "Title:\n{{event.Title | shout}}"
+	in.MessageTemplate = &invalid
+	_, err := events2.DecideEventPolicyAdded(projectID, actor, in, events2.StatusApproved)
+	if !errors.Is(err, policy.ErrInvalidPolicy) {
+		t.Fatalf("expected ErrInvalidPolicy, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "line 2, column 17") {
+		t.Fatalf("expected the position of the error, got %v", err)
+	}
+}
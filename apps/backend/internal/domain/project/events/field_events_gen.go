@@ -6,6 +6,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/locale"
 	projdomain "github.com/SURF-Innovatie/MORIS/internal/domain/project"
 	"github.com/google/uuid"
 	"time"
@@ -405,6 +406,13 @@ func init() {
 			return DecideTitleChanged(projectID, actor, cur, in, status)
 		})
 	RegisterInputType(TitleChangedType, TitleChangedInput{})
+	RegisterTranslation(TitleChangedType, locale.Dutch, Translation{
+		FriendlyName:            "Titelwijziging",
+		NotificationTemplate:    "Projecttitel gewijzigd in '{{event.Title}}'",
+		ApprovalRequestTemplate: "Verzoek om de projecttitel te wijzigen in '{{event.Title}}' vereist goedkeuring",
+		ApprovedTemplate:        "Titelwijziging naar '{{event.Title}}' goedgekeurd",
+		RejectedTemplate:        "Titelwijziging naar '{{event.Title}}' afgewezen",
+	})
 	RegisterMeta(DescriptionChangedMeta, func() Event {
 		return &DescriptionChanged{Base: Base{FriendlyNameStr: DescriptionChangedMeta.FriendlyName}}
 	})
@@ -413,6 +421,13 @@ func init() {
 			return DecideDescriptionChanged(projectID, actor, cur, in, status)
 		})
 	RegisterInputType(DescriptionChangedType, DescriptionChangedInput{})
+	RegisterTranslation(DescriptionChangedType, locale.Dutch, Translation{
+		FriendlyName:            "Beschrijvingswijziging",
+		NotificationTemplate:    "Projectbeschrijving is bijgewerkt",
+		ApprovalRequestTemplate: "Het wijzigen van de projectbeschrijving vereist goedkeuring.",
+		ApprovedTemplate:        "Wijziging van de projectbeschrijving is goedgekeurd.",
+		RejectedTemplate:        "Wijziging van de projectbeschrijving is afgewezen.",
+	})
 	RegisterMeta(StartDateChangedMeta, func() Event {
 		return &StartDateChanged{Base: Base{FriendlyNameStr: StartDateChangedMeta.FriendlyName}}
 	})
@@ -421,6 +436,13 @@ func init() {
 			return DecideStartDateChanged(projectID, actor, cur, in, status)
 		})
 	RegisterInputType(StartDateChangedType, StartDateChangedInput{})
+	RegisterTranslation(StartDateChangedType, locale.Dutch, Translation{
+		FriendlyName:            "Wijziging startdatum",
+		NotificationTemplate:    "Startdatum van het project gewijzigd in {{event.StartDate}}",
+		ApprovalRequestTemplate: "Verzoek om de startdatum te wijzigen in {{event.StartDate}} vereist goedkeuring",
+		ApprovedTemplate:        "Wijziging startdatum naar {{event.StartDate}} goedgekeurd",
+		RejectedTemplate:        "Wijziging startdatum naar {{event.StartDate}} afgewezen",
+	})
 	RegisterMeta(EndDateChangedMeta, func() Event {
 		return &EndDateChanged{Base: Base{FriendlyNameStr: EndDateChangedMeta.FriendlyName}}
 	})
@@ -429,6 +451,13 @@ func init() {
 			return DecideEndDateChanged(projectID, actor, cur, in, status)
 		})
 	RegisterInputType(EndDateChangedType, EndDateChangedInput{})
+	RegisterTranslation(EndDateChangedType, locale.Dutch, Translation{
+		FriendlyName:            "Wijziging einddatum",
+		NotificationTemplate:    "Einddatum van het project gewijzigd in {{event.EndDate}}",
+		ApprovalRequestTemplate: "Verzoek om de einddatum te wijzigen in {{event.EndDate}} vereist goedkeuring",
+		ApprovedTemplate:        "Wijziging einddatum naar {{event.EndDate}} goedgekeurd",
+		RejectedTemplate:        "Wijziging einddatum naar {{event.EndDate}} afgewezen",
+	})
 	RegisterMeta(OwningOrgNodeChangedMeta, func() Event {
 		return &OwningOrgNodeChanged{Base: Base{FriendlyNameStr: OwningOrgNodeChangedMeta.FriendlyName}}
 	})
@@ -437,4 +466,11 @@ func init() {
 			return DecideOwningOrgNodeChanged(projectID, actor, cur, in, status)
 		})
 	RegisterInputType(OwningOrgNodeChangedType, OwningOrgNodeChangedInput{})
+	RegisterTranslation(OwningOrgNodeChangedType, locale.Dutch, Translation{
+		FriendlyName:            "Wijziging eigenaar-organisatie",
+		NotificationTemplate:    "Project overgedragen aan organisatie '{{org_node.Name}}'",
+		ApprovalRequestTemplate: "Verzoek om het project over te dragen aan '{{org_node.Name}}' vereist goedkeuring",
+		ApprovedTemplate:        "Overdracht aan '{{org_node.Name}}' goedgekeurd",
+		RejectedTemplate:        "Overdracht aan '{{org_node.Name}}' afgewezen",
+	})
 }
@@ -8,6 +8,7 @@ import (
 	"go/format"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"text/template"
 
@@ -28,6 +29,36 @@ type FieldEvent struct {
 	CompareFunc             string `yaml:"compare_func"` // e.g., "Equal" for time.Time
 	RelatedID               string `yaml:"related_id"`   // e.g., "OrgNodeID"
 	RequireNonNil           bool   `yaml:"require_non_nil"`
+	// Translations maps a language code to the event's texts in that language
+	Translations map[string]Texts `yaml:"translations"`
+}
+
+// Texts are the translatable texts of an event type
+type Texts struct {
+	FriendlyName            string `yaml:"friendly_name"`
+	NotificationTemplate    string `yaml:"notification_template"`
+	ApprovalRequestTemplate string `yaml:"approval_request_template"`
+	ApprovedTemplate        string `yaml:"approved_template"`
+	RejectedTemplate        string `yaml:"rejected_template"`
+}
+
+// CollectionTexts are the translatable texts of an entity collection's add and remove events
+type CollectionTexts struct {
+	AddFriendlyName               string `yaml:"add_friendly_name"`
+	RemoveFriendlyName            string `yaml:"remove_friendly_name"`
+	AddNotificationTemplate       string `yaml:"add_notification_template"`
+	RemoveNotificationTemplate    string `yaml:"remove_notification_template"`
+	AddApprovalRequestTemplate    string `yaml:"add_approval_request_template"`
+	RemoveApprovalRequestTemplate string `yaml:"remove_approval_request_template"`
+	AddApprovedTemplate           string `yaml:"add_approved_template"`
+	RemoveApprovedTemplate        string `yaml:"remove_approved_template"`
+	AddRejectedTemplate           string `yaml:"add_rejected_template"`
+	RemoveRejectedTemplate        string `yaml:"remove_rejected_template"`
+}
+
+// languageConsts maps the language codes used in events.yaml to the locale package constants
+var languageConsts = map[string]string{
+	"nl": "locale.Dutch",
 }
 
 type EntityRefEvent struct {
@@ -42,6 +73,8 @@ type EntityRefEvent struct {
 	ApprovalRequestTemplate string `yaml:"approval_request_template"`
 	ApprovedTemplate        string `yaml:"approved_template"`
 	RejectedTemplate        string `yaml:"rejected_template"`
+	// Translations maps a language code to the event's texts in that language
+	Translations map[string]Texts `yaml:"translations"`
 }
 
 type EntityCollectionEvent struct {
@@ -60,6 +93,8 @@ type EntityCollectionEvent struct {
 	RemoveApprovedTemplate        string `yaml:"remove_approved_template"`
 	AddRejectedTemplate           string `yaml:"add_rejected_template"`
 	RemoveRejectedTemplate        string `yaml:"remove_rejected_template"`
+	// Translations maps a language code to the add and remove events' texts in that language
+	Translations map[string]CollectionTexts `yaml:"translations"`
 }
 
 type Config struct {
@@ -97,6 +132,7 @@ func main() {
 			ApprovalRequestTemplate: e.ApprovalRequestTemplate,
 			ApprovedTemplate:        e.ApprovedTemplate,
 			RejectedTemplate:        e.RejectedTemplate,
+			Translations:            e.Translations,
 		})
 	}
 
@@ -151,6 +187,9 @@ func generateFieldEvents(events []FieldEvent) error {
 `, metaName(e.Type), eventName(e.Type), metaName(e.Type),
 			inputName(e.Type), constName(e.Type), inputName(e.Type), decideName(e.Type),
 			constName(e.Type), inputName(e.Type)))
+		if err := writeTranslations(&buf, constName(e.Type), e.Translations); err != nil {
+			return err
+		}
 	}
 	buf.WriteString("}\n")
 
@@ -200,6 +239,19 @@ func generateEntityCollectionEvents(events []EntityCollectionEvent) error {
 	RegisterInputType(%sRemovedType, %sRemovedInput{})
 `, e.Entity, e.Entity, e.Entity, e.Entity, e.Entity, e.Entity, e.Entity, e.Entity, e.Entity,
 			e.Entity, e.Entity, e.Entity, e.Entity, e.Entity, e.Entity, e.Entity, e.Entity, e.Entity))
+
+		added := make(map[string]Texts, len(e.Translations))
+		removed := make(map[string]Texts, len(e.Translations))
+		for lang, t := range e.Translations {
+			added[lang] = Texts{t.AddFriendlyName, t.AddNotificationTemplate, t.AddApprovalRequestTemplate, t.AddApprovedTemplate, t.AddRejectedTemplate}
+			removed[lang] = Texts{t.RemoveFriendlyName, t.RemoveNotificationTemplate, t.RemoveApprovalRequestTemplate, t.RemoveApprovedTemplate, t.RemoveRejectedTemplate}
+		}
+		if err := writeTranslations(&buf, e.Entity+"AddedType", added); err != nil {
+			return err
+		}
+		if err := writeTranslations(&buf, e.Entity+"RemovedType", removed); err != nil {
+			return err
+		}
 	}
 	buf.WriteString("}\n")
 
@@ -212,6 +264,32 @@ func generateEntityCollectionEvents(events []EntityCollectionEvent) error {
 	return os.WriteFile("entity_events_gen.go", formatted, 0644)
 }
 
+// writeTranslations writes the RegisterTranslation calls of one event type, ordered by language
+func writeTranslations(buf *bytes.Buffer, typeConst string, translations map[string]Texts) error {
+	langs := make([]string, 0, len(translations))
+	for lang := range translations {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+
+	for _, lang := range langs {
+		langConst, ok := languageConsts[lang]
+		if !ok {
+			return fmt.Errorf("%s: unsupported language %q", typeConst, lang)
+		}
+		t := translations[lang]
+		fmt.Fprintf(buf, `	RegisterTranslation(%s, %s, Translation{
+		FriendlyName:            %q,
+		NotificationTemplate:    %q,
+		ApprovalRequestTemplate: %q,
+		ApprovedTemplate:        %q,
+		RejectedTemplate:        %q,
+	})
+`, typeConst, langConst, t.FriendlyName, t.NotificationTemplate, t.ApprovalRequestTemplate, t.ApprovedTemplate, t.RejectedTemplate)
+	}
+	return nil
+}
+
 // Helper functions for templates
 func eventName(typ string) string {
 	parts := strings.Split(typ, ".")
@@ -282,6 +360,7 @@ func fieldHeader(events []FieldEvent) string {
 		`"context"`,
 		`"errors"`,
 		`"fmt"`,
+		`"github.com/SURF-Innovatie/MORIS/internal/domain/locale"`,
 		`projdomain "github.com/SURF-Innovatie/MORIS/internal/domain/project"`,
 		`"github.com/google/uuid"`,
 	}
@@ -309,6 +388,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/SURF-Innovatie/MORIS/internal/domain/locale"
 	projdomain "github.com/SURF-Innovatie/MORIS/internal/domain/project"
 	"github.com/google/uuid"
 )
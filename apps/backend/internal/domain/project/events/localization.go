@@ -0,0 +1,84 @@
+package events
+
+import (
+	"github.com/SURF-Innovatie/MORIS/internal/domain/locale"
+	"github.com/samber/lo"
+)
+
+// Translation holds the texts of an event type in one language.
+// Empty fields fall back to the English texts of the event and its meta.
+type Translation struct {
+	FriendlyName            string
+	NotificationTemplate    string
+	ApprovalRequestTemplate string
+	ApprovedTemplate        string
+	RejectedTemplate        string
+}
+
+var translations = make(map[string]map[locale.Language]Translation)
+
+// RegisterTranslation registers the texts of an event type in a language other than English
+func RegisterTranslation(eventType string, lang locale.Language, t Translation) {
+	if translations[eventType] == nil {
+		translations[eventType] = make(map[locale.Language]Translation)
+	}
+	translations[eventType][lang] = t
+}
+
+// GetTranslation returns the registered texts of an event type in a language
+func GetTranslation(eventType string, lang locale.Language) (Translation, bool) {
+	t, ok := translations[eventType][lang]
+	return t, ok
+}
+
+// FriendlyNameIn returns the friendly name of an event type in the given language
+func FriendlyNameIn(eventType string, lang locale.Language) string {
+	if t, ok := GetTranslation(eventType, lang); ok && t.FriendlyName != "" {
+		return t.FriendlyName
+	}
+	return GetMeta(eventType).FriendlyName
+}
+
+// GetAllMetasIn returns all registered event metadata with friendly names in the given language
+func GetAllMetasIn(lang locale.Language) []EventMeta {
+	return lo.Map(GetAllMetas(), func(m EventMeta, _ int) EventMeta {
+		m.FriendlyName = FriendlyNameIn(m.Type, lang)
+		return m
+	})
+}
+
+// Localize returns the event's notification templates in the given language.
+// It reports false when the event does not provide notification templates.
+func Localize(e Event, lang locale.Language) (Notifier, bool) {
+	n, ok := e.(Notifier)
+	if !ok {
+		return nil, false
+	}
+	t, ok := GetTranslation(e.Type(), lang)
+	if !ok {
+		return n, true
+	}
+	return localizedNotifier{Notifier: n, t: t}, true
+}
+
+// localizedNotifier overrides the templates of a Notifier that have a translation
+type localizedNotifier struct {
+	Notifier
+	t Translation
+}
+
+func (l localizedNotifier) NotificationTemplate() string {
+	return lo.CoalesceOrEmpty(l.t.NotificationTemplate, l.Notifier.NotificationTemplate())
+}
+
+func (l localizedNotifier) ApprovalRequestTemplate() string {
+	return lo.CoalesceOrEmpty(l.t.ApprovalRequestTemplate, l.Notifier.ApprovalRequestTemplate())
+}
+
+func (l localizedNotifier) ApprovedTemplate() string {
+	return lo.CoalesceOrEmpty(l.t.ApprovedTemplate, l.Notifier.ApprovedTemplate())
+}
+
+func (l localizedNotifier) RejectedTemplate() string {
+	return lo.CoalesceOrEmpty(l.t.RejectedTemplate, l.Notifier.RejectedTemplate())
+}
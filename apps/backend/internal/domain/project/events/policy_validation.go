@@ -0,0 +1,141 @@
+package events
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/SURF-Innovatie/MORIS/internal/domain/policy"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/policy/expression"
+	projdomain "github.com/SURF-Innovatie/MORIS/internal/domain/project"
+)
+
+// ValidatePolicy checks a policy definition against the registered event types:
+// the trigger and anchor event types must exist and every condition's field path
+// must resolve on the triggering events or on the project.
+func ValidatePolicy(p policy.EventPolicy) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	triggerTypes := p.EventTypes
+	if p.IsScheduled() {
+		triggerTypes = nil
+		if anchor, ok := p.AnchorEventType(); ok {
+			triggerTypes = []string{anchor}
+		}
+	}
+
+	for _, t := range triggerTypes {
+		if _, ok := eventRegistry[t]; !ok {
+			return fmt.Errorf("%w: unknown event type %q", policy.ErrInvalidPolicy, t)
+		}
+	}
+
+	for _, c := range p.Conditions {
+		if err := validateConditionPath(c, triggerTypes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateConditionPath(c policy.Condition, eventTypes []string) error {
+	if c.IsExpression() {
+		return validateExpressionReferences(c.Expression, eventTypes)
+	}
+
+	source, field := c.Path()
+
+	switch source {
+	case policy.SourceProject:
+		if !hasField(reflect.TypeOf(projdomain.Project{}), field) {
+			return fmt.Errorf("%w: project has no field %q", policy.ErrInvalidPolicy, field)
+		}
+	case policy.SourceEvent:
+		for _, t := range eventTypes {
+			if hasField(reflect.TypeOf(eventRegistry[t]()), field) {
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: none of the policy's event types has field %q", policy.ErrInvalidPolicy, field)
+	}
+	return nil
+}
+
+// validateExpressionReferences type-checks the fields an expression selects against
+// the policy's event types and the project
+func validateExpressionReferences(source string, eventTypes []string) error {
+	x, err := expression.Compile(source)
+	if err != nil {
+		return fmt.Errorf("%w: %s", policy.ErrInvalidPolicy, err)
+	}
+
+	for _, ref := range x.References() {
+		parts := strings.Split(ref, ".")
+		if len(parts) < 2 {
+			continue
+		}
+		switch parts[0] {
+		case expression.VarEvent:
+			if !slices.Contains(expressionBaseFields, parts[1]) && !slices.ContainsFunc(eventTypes, func(t string) bool {
+				return hasSnakeField(reflect.TypeOf(eventRegistry[t]()), parts[1])
+			}) {
+				return fmt.Errorf("%w: none of the policy's event types has field %q", policy.ErrInvalidPolicy, parts[1])
+			}
+		case expression.VarProject:
+			if parts[1] != expression.StateBefore && parts[1] != expression.StateAfter {
+				return fmt.Errorf("%w: use project.before.<field> or project.after.<field>, not %q", policy.ErrInvalidPolicy, ref)
+			}
+			if len(parts) > 2 && !hasSnakeField(reflect.TypeOf(projdomain.Project{}), parts[2]) {
+				return fmt.Errorf("%w: project has no field %q", policy.ErrInvalidPolicy, parts[2])
+			}
+		case expression.VarActor:
+			if !slices.Contains(expression.ActorFields, parts[1]) {
+				return fmt.Errorf("%w: actor has no field %q", policy.ErrInvalidPolicy, parts[1])
+			}
+		}
+	}
+	return nil
+}
+
+// expressionBaseFields are the fields every event exposes to expressions
+var expressionBaseFields = []string{"id", "type", "status", "at", "created_by"}
+
+// hasSnakeField reports whether a struct has an exported, non-embedded field with the given snake_case name
+func hasSnakeField(t reflect.Type, name string) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.Anonymous && f.IsExported() && expression.SnakeCase(f.Name) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// hasField mirrors the evaluator's field lookup: an exact (possibly promoted) field name,
+// or a case-insensitive match on the struct's own fields.
+func hasField(t reflect.Type, name string) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	if _, ok := t.FieldByName(name); ok {
+		return true
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if strings.EqualFold(t.Field(i).Name, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -4,17 +4,23 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/SURF-Innovatie/MORIS/internal/domain/locale"
 	projdomain "github.com/SURF-Innovatie/MORIS/internal/domain/project"
 	"github.com/google/uuid"
 )
 
 const ProjectRoleAssignedType = "project.project_role_assigned"
 
+// ProjectRoleAssigned gives a person a role on the project. Assigning a role the person already
+// holds changes the period of the position.
 type ProjectRoleAssigned struct {
 	Base
-	PersonID      uuid.UUID `json:"person_id"`
-	ProjectRoleID uuid.UUID `json:"project_role_id"`
+	PersonID      uuid.UUID  `json:"person_id"`
+	ProjectRoleID uuid.UUID  `json:"project_role_id"`
+	StartDate     *time.Time `json:"start_date,omitempty"`
+	EndDate       *time.Time `json:"end_date,omitempty"`
 }
 
 func (ProjectRoleAssigned) isEvent()     {}
@@ -24,14 +30,18 @@ func (e ProjectRoleAssigned) String() string {
 }
 
 func (e *ProjectRoleAssigned) Apply(p *projdomain.Project) {
-	for _, m := range p.Members {
+	for i, m := range p.Members {
 		if m.PersonID == e.PersonID && m.ProjectRoleID == e.ProjectRoleID {
-			return // already assigned
+			// already assigned, only the period changes
+			p.Members[i].StartDate, p.Members[i].EndDate = e.StartDate, e.EndDate
+			return
 		}
 	}
 	p.Members = append(p.Members, projdomain.Member{
 		PersonID:      e.PersonID,
 		ProjectRoleID: e.ProjectRoleID,
+		StartDate:     e.StartDate,
+		EndDate:       e.EndDate,
 	})
 }
 
@@ -44,8 +54,10 @@ func (e *ProjectRoleAssigned) ApprovalMessage(projectTitle string) string {
 }
 
 type ProjectRoleAssignedInput struct {
-	PersonID      uuid.UUID `json:"person_id"`
-	ProjectRoleID uuid.UUID `json:"project_role_id"`
+	PersonID      uuid.UUID  `json:"person_id"`
+	ProjectRoleID uuid.UUID  `json:"project_role_id"`
+	StartDate     *time.Time `json:"start_date,omitempty"`
+	EndDate       *time.Time `json:"end_date,omitempty"`
 }
 
 func DecideProjectRoleAssigned(
@@ -64,9 +76,13 @@ func DecideProjectRoleAssigned(
 	if cur == nil {
 		return nil, errors.New("current project is required")
 	}
+	if in.StartDate != nil && in.EndDate != nil && !in.EndDate.After(*in.StartDate) {
+		return nil, errors.New("end date must be after start date")
+	}
 
 	for _, m := range cur.Members {
-		if m.PersonID == in.PersonID && m.ProjectRoleID == in.ProjectRoleID {
+		if m.PersonID == in.PersonID && m.ProjectRoleID == in.ProjectRoleID &&
+			sameDate(m.StartDate, in.StartDate) && sameDate(m.EndDate, in.EndDate) {
 			return nil, nil
 		}
 	}
@@ -78,9 +94,18 @@ func DecideProjectRoleAssigned(
 		Base:          base,
 		PersonID:      in.PersonID,
 		ProjectRoleID: in.ProjectRoleID,
+		StartDate:     in.StartDate,
+		EndDate:       in.EndDate,
 	}, nil
 }
 
+func sameDate(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
 var ProjectRoleAssignedMeta = EventMeta{
 	Type:         ProjectRoleAssignedType,
 	FriendlyName: "Project Role Assignment",
@@ -99,4 +124,6 @@ func init() {
 		})
 
 	RegisterInputType(ProjectRoleAssignedType, ProjectRoleAssignedInput{})
+
+	RegisterTranslation(ProjectRoleAssignedType, locale.Dutch, Translation{FriendlyName: "Toewijzing projectrol"})
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/SURF-Innovatie/MORIS/internal/domain/locale"
 	projdomain "github.com/SURF-Innovatie/MORIS/internal/domain/project"
 	"github.com/google/uuid"
 )
@@ -101,4 +102,6 @@ func init() {
 		})
 
 	RegisterInputType(ProjectRoleUnassignedType, ProjectRoleUnassignedInput{})
+
+	RegisterTranslation(ProjectRoleUnassignedType, locale.Dutch, Translation{FriendlyName: "Intrekking projectrol"})
 }
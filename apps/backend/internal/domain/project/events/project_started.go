@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/SURF-Innovatie/MORIS/internal/domain/locale"
 	projdomain "github.com/SURF-Innovatie/MORIS/internal/domain/project"
 	"github.com/google/uuid"
 )
@@ -20,6 +21,7 @@ type ProjectStarted struct {
 	EndDate         time.Time           `json:"endDate"`
 	Members         []projdomain.Member `json:"members_ids"`
 	OwningOrgNodeID uuid.UUID           `json:"owning_org_node_id"`
+	Visibility      string              `json:"visibility,omitempty"`
 }
 
 func (ProjectStarted) isEvent()     {}
@@ -35,6 +37,7 @@ func (e *ProjectStarted) Apply(p *projdomain.Project) {
 	p.EndDate = e.EndDate
 	p.OwningOrgNodeID = e.OwningOrgNodeID
 	p.Members = e.Members
+	p.Visibility = projdomain.Visibility(e.Visibility)
 }
 
 func (e *ProjectStarted) NotificationTemplate() string {
@@ -71,6 +74,7 @@ type ProjectStartedInput struct {
 	EndDate         time.Time           `json:"end_date"`
 	Members         []projdomain.Member `json:"members_ids"`
 	OwningOrgNodeID uuid.UUID           `json:"owning_org_node_id"`
+	Visibility      string              `json:"visibility,omitempty"`
 }
 
 func DecideProjectStarted(
@@ -88,6 +92,10 @@ func DecideProjectStarted(
 	if in.EndDate.Before(in.StartDate) {
 		return nil, errors.New("end date before start date")
 	}
+	visibility, err := projdomain.ParseVisibility(in.Visibility)
+	if err != nil {
+		return nil, err
+	}
 
 	base := NewBase(projectID, actor, status)
 	base.FriendlyNameStr = ProjectStartedMeta.FriendlyName
@@ -100,6 +108,7 @@ func DecideProjectStarted(
 		EndDate:         in.EndDate,
 		Members:         in.Members,
 		OwningOrgNodeID: in.OwningOrgNodeID,
+		Visibility:      string(visibility),
 	}, nil
 }
 
@@ -121,4 +130,12 @@ func init() {
 		})
 
 	RegisterInputType(ProjectStartedType, ProjectStartedInput{})
+
+	RegisterTranslation(ProjectStartedType, locale.Dutch, Translation{
+		FriendlyName:            "Projectvoorstel",
+		NotificationTemplate:    "Projectvoorstel '{{event.Title}}' aangemaakt in '{{org_node.Name}}'",
+		ApprovalRequestTemplate: "Projectvoorstel '{{event.Title}}' vereist goedkeuring.",
+		ApprovedTemplate:        "Projectvoorstel '{{event.Title}}' is goedgekeurd.",
+		RejectedTemplate:        "Projectvoorstel '{{event.Title}}' is afgewezen.",
+	})
 }
@@ -0,0 +1,115 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/SURF-Innovatie/MORIS/internal/domain/locale"
+	projdomain "github.com/SURF-Innovatie/MORIS/internal/domain/project"
+	"github.com/google/uuid"
+)
+
+const VisibilityChangedType = "project.visibility_changed"
+
+type VisibilityChanged struct {
+	Base
+	Visibility string `json:"visibility"`
+}
+
+func (VisibilityChanged) isEvent()     {}
+func (VisibilityChanged) Type() string { return VisibilityChangedType }
+func (e VisibilityChanged) String() string {
+	return fmt.Sprintf("Visibility changed to %s", e.Visibility)
+}
+
+func (e *VisibilityChanged) Apply(p *projdomain.Project) {
+	p.Visibility = projdomain.Visibility(e.Visibility)
+}
+
+func (e *VisibilityChanged) NotificationTemplate() string {
+	return "Project visibility changed to {{event.Visibility}}"
+}
+
+func (e *VisibilityChanged) ApprovalRequestTemplate() string {
+	return "Request to change project visibility to {{event.Visibility}} requires approval"
+}
+
+func (e *VisibilityChanged) ApprovedTemplate() string {
+	return "Visibility change to {{event.Visibility}} approved"
+}
+
+func (e *VisibilityChanged) RejectedTemplate() string {
+	return "Visibility change to {{event.Visibility}} rejected"
+}
+
+func (e *VisibilityChanged) NotificationVariables() map[string]string {
+	return map[string]string{
+		"event.Visibility": e.Visibility,
+	}
+}
+
+type VisibilityChangedInput struct {
+	Visibility string `json:"visibility"`
+}
+
+func DecideVisibilityChanged(
+	projectID uuid.UUID,
+	actor uuid.UUID,
+	cur *projdomain.Project,
+	in VisibilityChangedInput,
+	status Status,
+) (Event, error) {
+	if projectID == uuid.Nil {
+		return nil, errors.New("project id is required")
+	}
+	if cur == nil {
+		return nil, errors.New("current project is required")
+	}
+	if in.Visibility == "" {
+		return nil, errors.New("visibility is required")
+	}
+	visibility, err := projdomain.ParseVisibility(in.Visibility)
+	if err != nil {
+		return nil, err
+	}
+	if cur.EffectiveVisibility() == visibility {
+		return nil, nil
+	}
+
+	base := NewBase(projectID, actor, status)
+	base.FriendlyNameStr = VisibilityChangedMeta.FriendlyName
+
+	return &VisibilityChanged{
+		Base:       base,
+		Visibility: string(visibility),
+	}, nil
+}
+
+var VisibilityChangedMeta = EventMeta{
+	Type:         VisibilityChangedType,
+	FriendlyName: "Visibility Change",
+}
+
+func init() {
+	RegisterMeta(VisibilityChangedMeta, func() Event {
+		return &VisibilityChanged{
+			Base: Base{FriendlyNameStr: VisibilityChangedMeta.FriendlyName},
+		}
+	})
+
+	RegisterDecider[VisibilityChangedInput](VisibilityChangedType,
+		func(ctx context.Context, projectID uuid.UUID, actor uuid.UUID, cur *projdomain.Project, in VisibilityChangedInput, status Status) (Event, error) {
+			return DecideVisibilityChanged(projectID, actor, cur, in, status)
+		})
+
+	RegisterInputType(VisibilityChangedType, VisibilityChangedInput{})
+
+	RegisterTranslation(VisibilityChangedType, locale.Dutch, Translation{
+		FriendlyName:            "Wijziging zichtbaarheid",
+		NotificationTemplate:    "Zichtbaarheid van het project gewijzigd in {{event.Visibility}}",
+		ApprovalRequestTemplate: "Verzoek om de zichtbaarheid van het project te wijzigen in {{event.Visibility}} vereist goedkeuring",
+		ApprovedTemplate:        "Wijziging zichtbaarheid naar {{event.Visibility}} goedgekeurd",
+		RejectedTemplate:        "Wijziging zichtbaarheid naar {{event.Visibility}} afgewezen",
+	})
+}
@@ -2,6 +2,7 @@ package events
 
 import (
 	"github.com/SURF-Innovatie/MORIS/internal/common"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/locale"
 	"github.com/samber/lo"
 )
 
@@ -30,4 +31,5 @@ func init() {
 	})
 	RegisterDecider(CustomFieldValueSetType, DecideCustomFieldValueSet)
 	RegisterInputType(CustomFieldValueSetType, CustomFieldValueSetInput{})
+	RegisterTranslation(CustomFieldValueSetType, locale.Dutch, Translation{FriendlyName: "Waarde eigen veld instellen"})
 }
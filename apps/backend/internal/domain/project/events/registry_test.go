@@ -3,6 +3,7 @@ package events_test
 import (
 	"testing"
 
+	"github.com/SURF-Innovatie/MORIS/internal/domain/locale"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/project/events"
 )
 
@@ -11,3 +12,43 @@ func TestAllRegisteredEventsHaveDeciders(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestAllRegisteredEventsHaveDutchTexts(t *testing.T) {
+	for _, meta := range events.GetAllMetas() {
+		nl, ok := events.GetTranslation(meta.Type, locale.Dutch)
+		if !ok || nl.FriendlyName == "" {
+			t.Errorf("%s: missing Dutch friendly name", meta.Type)
+			continue
+		}
+
+		e, _ := events.Create(meta.Type)
+		if _, ok := e.(events.Notifier); ok && (nl.NotificationTemplate == "" || nl.ApprovalRequestTemplate == "" ||
+			nl.ApprovedTemplate == "" || nl.RejectedTemplate == "") {
+			t.Errorf("%s: missing Dutch notification templates", meta.Type)
+		}
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	e := &events.TitleChanged{Title: "Beta"}
+
+	nl, ok := events.Localize(e, locale.Dutch)
+	if !ok {
+		t.Fatal("expected TitleChanged to provide notification templates")
+	}
+	if got := events.ResolveTemplate(nl.NotificationTemplate(), events.TemplateData(events.DetailedEvent{Event: e})); got != "Projecttitel gewijzigd in 'Beta'" {
+		t.Fatalf("unexpected Dutch message %q", got)
+	}
+
+	en, _ := events.Localize(e, locale.English)
+	if got := en.NotificationTemplate(); got != e.NotificationTemplate() {
+		t.Fatalf("expected the English template, got %q", got)
+	}
+
+	if got := events.FriendlyNameIn(events.TitleChangedType, locale.Dutch); got != "Titelwijziging" {
+		t.Fatalf("unexpected Dutch friendly name %q", got)
+	}
+	if got := events.FriendlyNameIn(events.TitleChangedType, locale.English); got != events.TitleChangedMeta.FriendlyName {
+		t.Fatalf("unexpected English friendly name %q", got)
+	}
+}
@@ -1,133 +1,85 @@
 package events
 
 import (
-	"regexp"
-	"strings"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/policy/messagetemplate"
 )
 
-var templateVarRegex = regexp.MustCompile(`\{\{([^}]+)\}\}`)
-
-// ResolveTemplate resolves {{variable}} placeholders in a template string.
-// It uses the provided variables map for substitution.
-// Variables from event.NotificationVariables() should be passed directly.
-// For related entities, use AddDetailedEventVariables to extend the variables map.
-func ResolveTemplate(template string, variables map[string]string) string {
+// ResolveTemplate renders a notification template, see the messagetemplate package for its syntax.
+// The data usually comes from TemplateData. A template that does not parse is returned as written;
+// policy templates are validated when the policy is saved.
+func ResolveTemplate(template string, data map[string]any) string {
 	if template == "" {
 		return ""
 	}
 
-	return templateVarRegex.ReplaceAllStringFunc(template, func(match string) string {
-		// Extract variable name from {{variable}}
-		varName := strings.TrimPrefix(strings.TrimSuffix(match, "}}"), "{{")
-		varName = strings.TrimSpace(varName)
+	t, err := messagetemplate.Parse(template)
+	if err != nil {
+		return template
+	}
+	return t.Render(data)
+}
+
+// TemplateData returns the data notification templates are rendered with: the event under "event",
+// the variables from NotificationVariables() and the hydrated related entities.
+func TemplateData(de DetailedEvent) map[string]any {
+	data := make(map[string]any)
+	if de.Event == nil {
+		return data
+	}
 
-		if val, ok := variables[varName]; ok {
-			return val
+	data["event"] = de.Event
+	if n, ok := de.Event.(Notifier); ok {
+		for k, v := range n.NotificationVariables() {
+			data[k] = v
 		}
-		// Keep the placeholder if no value found
-		return match
-	})
+	}
+	return AddDetailedEventVariables(data, de)
 }
 
-// AddDetailedEventVariables adds variables from DetailedEvent related entities.
-// This extends the base variables from NotificationVariables() with hydrated entity data.
-func AddDetailedEventVariables(vars map[string]string, de DetailedEvent) map[string]string {
-	if vars == nil {
-		vars = make(map[string]string)
+// AddDetailedEventVariables adds the hydrated entities of a DetailedEvent to template data,
+// so that templates can use e.g. {{person.Name}}, {{product.Name}} or {{org_node.Name}}
+func AddDetailedEventVariables(data map[string]any, de DetailedEvent) map[string]any {
+	if data == nil {
+		data = make(map[string]any)
 	}
 
-	// Add person variables
 	if de.Person != nil {
-		vars["person.Name"] = de.Person.Name
-		vars["person.Email"] = de.Person.Email
+		data["person"] = de.Person
 	}
-
-	// Add product variables
 	if de.Product != nil {
-		vars["product.Name"] = de.Product.Name
+		data["product"] = de.Product
 	}
-
-	// Add project role variables
 	if de.ProjectRole != nil {
-		vars["project_role.Name"] = de.ProjectRole.Name
+		data["project_role"] = de.ProjectRole
+		data["role"] = de.ProjectRole
 	}
-
-	// Add org node variables
 	if de.OrgNode != nil {
-		vars["org_node.Name"] = de.OrgNode.Name
+		data["org_node"] = de.OrgNode
 	}
-
-	// Add creator variables
 	if de.Creator != nil {
-		vars["creator.Name"] = de.Creator.Name
-		vars["creator.Email"] = de.Creator.Email
+		data["creator"] = de.Creator
 	}
 
-	return vars
+	return data
 }
 
-// BuildNotificationMessage builds a notification message from a Notifier event.
-// It combines the event's template with variables from both NotificationVariables()
-// and hydrated DetailedEvent data.
+// BuildNotificationMessage builds a notification message from a Notifier event
+// and the hydrated DetailedEvent data.
 func BuildNotificationMessage(n Notifier, de DetailedEvent) string {
-	template := n.NotificationTemplate()
-	if template == "" {
-		return ""
-	}
-
-	vars := n.NotificationVariables()
-	if vars == nil {
-		vars = make(map[string]string)
-	}
-
-	vars = AddDetailedEventVariables(vars, de)
-	return ResolveTemplate(template, vars)
+	return ResolveTemplate(n.NotificationTemplate(), TemplateData(de))
 }
 
 // BuildApprovalRequestMessage builds an approval request message from a Notifier event.
 func BuildApprovalRequestMessage(n Notifier, de DetailedEvent) string {
-	template := n.ApprovalRequestTemplate()
-	if template == "" {
-		return ""
-	}
-
-	vars := n.NotificationVariables()
-	if vars == nil {
-		vars = make(map[string]string)
-	}
-
-	vars = AddDetailedEventVariables(vars, de)
-	return ResolveTemplate(template, vars)
+	return ResolveTemplate(n.ApprovalRequestTemplate(), TemplateData(de))
 }
 
 // BuildApprovedMessage builds an approved status message from a Notifier event.
 func BuildApprovedMessage(n Notifier, de DetailedEvent) string {
-	template := n.ApprovedTemplate()
-	if template == "" {
-		return ""
-	}
-
-	vars := n.NotificationVariables()
-	if vars == nil {
-		vars = make(map[string]string)
-	}
-
-	vars = AddDetailedEventVariables(vars, de)
-	return ResolveTemplate(template, vars)
+	return ResolveTemplate(n.ApprovedTemplate(), TemplateData(de))
 }
 
 // BuildRejectedMessage builds a rejected status message from a Notifier event.
 func BuildRejectedMessage(n Notifier, de DetailedEvent) string {
-	template := n.RejectedTemplate()
-	if template == "" {
-		return ""
-	}
-
-	vars := n.NotificationVariables()
-	if vars == nil {
-		vars = make(map[string]string)
-	}
-
-	vars = AddDetailedEventVariables(vars, de)
-	return ResolveTemplate(template, vars)
+	return ResolveTemplate(n.RejectedTemplate(), TemplateData(de))
 }
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	projdomain "github.com/SURF-Innovatie/MORIS/internal/domain/project"
 	events2 "github.com/SURF-Innovatie/MORIS/internal/domain/project/events"
 	"github.com/google/uuid"
 )
@@ -50,3 +51,76 @@ func Test_StartProject_Validation(t *testing.T) {
 		t.Fatal("end before start should error")
 	}
 }
+
+func Test_VisibilityChanged_Validation(t *testing.T) {
+	id := uuid.New()
+	actor := uuid.New()
+	cur := &projdomain.Project{Id: id}
+
+	if _, err := events2.DecideVisibilityChanged(id, actor, cur, events2.VisibilityChangedInput{Visibility: "secret"}, events2.StatusApproved); err == nil {
+		t.Fatal("unknown visibility should error")
+	}
+
+	// projects without a visibility are organisation-visible, so this is not a change
+	e, err := events2.DecideVisibilityChanged(id, actor, cur, events2.VisibilityChangedInput{Visibility: "organisation"}, events2.StatusApproved)
+	if err != nil || e != nil {
+		t.Fatalf("expected no event, got %v, %v", e, err)
+	}
+
+	e, err = events2.DecideVisibilityChanged(id, actor, cur, events2.VisibilityChangedInput{Visibility: "private"}, events2.StatusApproved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.(*events2.VisibilityChanged).Apply(cur)
+	if cur.EffectiveVisibility() != projdomain.VisibilityPrivate {
+		t.Fatalf("expected private, got %s", cur.EffectiveVisibility())
+	}
+}
+
+func Test_ProjectRoleAssigned_Period(t *testing.T) {
+	id := uuid.New()
+	actor := uuid.New()
+	person := uuid.New()
+	role := uuid.New()
+	cur := &projdomain.Project{Id: id}
+
+	start := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 8, 31, 0, 0, 0, 0, time.UTC)
+
+	if _, err := events2.DecideProjectRoleAssigned(id, actor, cur, events2.ProjectRoleAssignedInput{
+		PersonID: person, ProjectRoleID: role, StartDate: &end, EndDate: &start,
+	}, events2.StatusApproved); err == nil {
+		t.Fatal("end before start should error")
+	}
+
+	e, err := events2.DecideProjectRoleAssigned(id, actor, cur, events2.ProjectRoleAssignedInput{
+		PersonID: person, ProjectRoleID: role, StartDate: &start,
+	}, events2.StatusApproved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.(*events2.ProjectRoleAssigned).Apply(cur)
+
+	// assigning the same role again with an end date changes the period of the position
+	e, err = events2.DecideProjectRoleAssigned(id, actor, cur, events2.ProjectRoleAssignedInput{
+		PersonID: person, ProjectRoleID: role, StartDate: &start, EndDate: &end,
+	}, events2.StatusApproved)
+	if err != nil || e == nil {
+		t.Fatalf("expected a period change, got %v, %v", e, err)
+	}
+	e.(*events2.ProjectRoleAssigned).Apply(cur)
+
+	if len(cur.Members) != 1 || cur.Members[0].EndDate == nil || !cur.Members[0].EndDate.Equal(end) {
+		t.Fatalf("expected one member ending at %s, got %+v", end, cur.Members)
+	}
+	if !cur.IsActiveMember(person, end.Add(-time.Hour)) || cur.IsActiveMember(person, end) || cur.IsActiveMember(person, start.Add(-time.Hour)) {
+		t.Fatal("membership should only be active within its period")
+	}
+
+	e, err = events2.DecideProjectRoleAssigned(id, actor, cur, events2.ProjectRoleAssignedInput{
+		PersonID: person, ProjectRoleID: role, StartDate: &start, EndDate: &end,
+	}, events2.StatusApproved)
+	if err != nil || e != nil {
+		t.Fatalf("expected no event for an unchanged assignment, got %v, %v", e, err)
+	}
+}
@@ -7,6 +7,9 @@ import (
 	"github.com/google/uuid"
 )
 
+// KeyLead is the key of the default project lead role, whose active holders manage their project
+const KeyLead = "lead"
+
 type ProjectRole struct {
 	ID                 uuid.UUID
 	Key                string
@@ -0,0 +1,47 @@
+package project
+
+import "fmt"
+
+// Visibility controls who can read a project besides its members and sysadmins
+type Visibility string
+
+const (
+	// VisibilityPrivate limits reading to members and sysadmins
+	VisibilityPrivate Visibility = "private"
+	// VisibilityOrganisation also allows holders of the view_projects permission on the owning
+	// organisation node or one of its ancestors
+	VisibilityOrganisation Visibility = "organisation"
+	// VisibilityPublic allows every signed-in user
+	VisibilityPublic Visibility = "public"
+)
+
+// DefaultVisibility applies to projects that never set a visibility
+const DefaultVisibility = VisibilityOrganisation
+
+func (v Visibility) Valid() bool {
+	switch v {
+	case VisibilityPrivate, VisibilityOrganisation, VisibilityPublic:
+		return true
+	}
+	return false
+}
+
+// ParseVisibility returns the visibility for s, or the default when s is empty
+func ParseVisibility(s string) (Visibility, error) {
+	if s == "" {
+		return DefaultVisibility, nil
+	}
+	v := Visibility(s)
+	if !v.Valid() {
+		return "", fmt.Errorf("invalid visibility %q: must be private, organisation or public", s)
+	}
+	return v, nil
+}
+
+// EffectiveVisibility returns the project's visibility, falling back to the default
+func (p *Project) EffectiveVisibility() Visibility {
+	if p.Visibility == "" {
+		return DefaultVisibility
+	}
+	return p.Visibility
+}
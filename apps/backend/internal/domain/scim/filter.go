@@ -0,0 +1,307 @@
+package scim
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Attributes exposes the values of a resource for filtering. Paths are lowercase and dotted,
+// e.g. "name.givenname"; booleans are "true" and "false".
+type Attributes interface {
+	Values(path string) []string
+}
+
+// Filter is a parsed SCIM filter expression (RFC 7644 section 3.4.2.2)
+type Filter interface {
+	Match(a Attributes) bool
+}
+
+// Comparison compares the values of an attribute path with a value. Op is lowercase; Value is
+// empty for "pr".
+type Comparison struct {
+	Path  string
+	Op    string
+	Value string
+}
+
+// Logical combines two filters with "and" or "or"
+type Logical struct {
+	And         bool
+	Left, Right Filter
+}
+
+// Negation is a "not" filter
+type Negation struct{ Inner Filter }
+
+func (c Comparison) Match(a Attributes) bool {
+	values := a.Values(c.Path)
+	if c.Op == "pr" {
+		return len(values) > 0
+	}
+	if c.Op == "ne" {
+		for _, v := range values {
+			if strings.EqualFold(v, c.Value) {
+				return false
+			}
+		}
+		return true
+	}
+	for _, v := range values {
+		if compare(c.Op, strings.ToLower(v), strings.ToLower(c.Value)) {
+			return true
+		}
+	}
+	return false
+}
+
+// compare applies an operator to lowercased values; strings compare case-insensitively, which is
+// what RFC 7643 prescribes for the attributes MORIS exposes
+func compare(op, v, want string) bool {
+	switch op {
+	case "eq":
+		return v == want
+	case "co":
+		return strings.Contains(v, want)
+	case "sw":
+		return strings.HasPrefix(v, want)
+	case "ew":
+		return strings.HasSuffix(v, want)
+	case "gt":
+		return v > want
+	case "ge":
+		return v >= want
+	case "lt":
+		return v < want
+	case "le":
+		return v <= want
+	}
+	return false
+}
+
+func (l Logical) Match(a Attributes) bool {
+	if l.And {
+		return l.Left.Match(a) && l.Right.Match(a)
+	}
+	return l.Left.Match(a) || l.Right.Match(a)
+}
+
+func (n Negation) Match(a Attributes) bool { return !n.Inner.Match(a) }
+
+// ParseFilter parses a filter. Attribute paths may carry the core schema URN; they are matched
+// case-insensitively. An empty filter matches everything.
+func ParseFilter(s string) (Filter, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	p := &parser{tokens: nil}
+	var err error
+	if p.tokens, err = tokenize(s); err != nil {
+		return nil, err
+	}
+	f, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.tokens) {
+		return nil, fmt.Errorf("%w: unexpected %q", ErrInvalidFilter, p.tokens[p.pos].text)
+	}
+	return f, nil
+}
+
+// MatchAll returns the resources matching f
+func MatchAll[T Attributes](f Filter, all []T) []T {
+	if f == nil {
+		return all
+	}
+	out := make([]T, 0, len(all))
+	for _, r := range all {
+		if f.Match(r) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenString
+	tokenOpen
+	tokenClose
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func tokenize(s string) ([]token, error) {
+	var out []token
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '(':
+			out = append(out, token{tokenOpen, "("})
+			i++
+		case c == ')':
+			out = append(out, token{tokenClose, ")"})
+			i++
+		case c == '"':
+			end := i + 1
+			for end < len(s) && s[end] != '"' {
+				if s[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(s) {
+				return nil, fmt.Errorf("%w: unterminated string", ErrInvalidFilter)
+			}
+			v, err := strconv.Unquote(s[i : end+1])
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid string %s", ErrInvalidFilter, s[i:end+1])
+			}
+			out = append(out, token{tokenString, v})
+			i = end + 1
+		default:
+			end := i
+			for end < len(s) && !unicode.IsSpace(rune(s[end])) && s[end] != '(' && s[end] != ')' && s[end] != '"' {
+				end++
+			}
+			out = append(out, token{tokenWord, s[i:end]})
+			i = end
+		}
+	}
+	return out, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peekWord(w string) bool {
+	return p.pos < len(p.tokens) && p.tokens[p.pos].kind == tokenWord && strings.EqualFold(p.tokens[p.pos].text, w)
+}
+
+func (p *parser) or() (Filter, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekWord("or") {
+		p.pos++
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = Logical{And: false, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) and() (Filter, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekWord("and") {
+		p.pos++
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		left = Logical{And: true, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) unary() (Filter, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("%w: unexpected end of filter", ErrInvalidFilter)
+	}
+	if p.peekWord("not") {
+		p.pos++
+		if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != tokenOpen {
+			return nil, fmt.Errorf("%w: expected ( after not", ErrInvalidFilter)
+		}
+		inner, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return Negation{Inner: inner}, nil
+	}
+	if p.tokens[p.pos].kind == tokenOpen {
+		p.pos++
+		inner, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != tokenClose {
+			return nil, fmt.Errorf("%w: missing )", ErrInvalidFilter)
+		}
+		p.pos++
+		return inner, nil
+	}
+	return p.comparison()
+}
+
+func (p *parser) comparison() (Filter, error) {
+	if p.tokens[p.pos].kind != tokenWord {
+		return nil, fmt.Errorf("%w: expected an attribute, got %q", ErrInvalidFilter, p.tokens[p.pos].text)
+	}
+	path := NormalizePath(p.tokens[p.pos].text)
+	p.pos++
+	if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != tokenWord {
+		return nil, fmt.Errorf("%w: expected an operator after %s", ErrInvalidFilter, path)
+	}
+	op := strings.ToLower(p.tokens[p.pos].text)
+	p.pos++
+
+	switch op {
+	case "pr":
+		return Comparison{Path: path, Op: op}, nil
+	case "eq", "ne", "co", "sw", "ew", "gt", "ge", "lt", "le":
+	default:
+		return nil, fmt.Errorf("%w: unknown operator %q", ErrInvalidFilter, op)
+	}
+
+	if p.pos >= len(p.tokens) || (p.tokens[p.pos].kind != tokenString && p.tokens[p.pos].kind != tokenWord) {
+		return nil, fmt.Errorf("%w: expected a value after %s %s", ErrInvalidFilter, path, op)
+	}
+	t := p.tokens[p.pos]
+	p.pos++
+	value := t.text
+	if t.kind == tokenWord {
+		// Unquoted values are booleans, numbers or null
+		switch lower := strings.ToLower(value); lower {
+		case "true", "false", "null":
+			value = lower
+		default:
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				return nil, fmt.Errorf("%w: invalid value %q", ErrInvalidFilter, value)
+			}
+		}
+	}
+	return Comparison{Path: path, Op: op, Value: value}, nil
+}
+
+// coreSchemaPrefixes are stripped from fully qualified attribute paths
+var coreSchemaPrefixes = []string{
+	"urn:ietf:params:scim:schemas:core:2.0:user:",
+	"urn:ietf:params:scim:schemas:core:2.0:group:",
+}
+
+// NormalizePath lowercases an attribute path and strips the core schema URN
+func NormalizePath(path string) string {
+	path = strings.ToLower(path)
+	for _, prefix := range coreSchemaPrefixes {
+		path = strings.TrimPrefix(path, prefix)
+	}
+	return path
+}
@@ -0,0 +1,48 @@
+package scim_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/SURF-Innovatie/MORIS/internal/domain/scim"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+func TestParseFilter(t *testing.T) {
+	u := scim.User{
+		ID:          uuid.New(),
+		UserName:    "ada@example.org",
+		DisplayName: "Ada Lovelace",
+		ExternalID:  lo.ToPtr("e-1"),
+		Active:      true,
+	}
+
+	cases := []struct {
+		filter string
+		match  bool
+	}{
+		{`userName eq "ADA@example.org"`, true},
+		{`urn:ietf:params:scim:schemas:core:2.0:User:userName eq "ada@example.org"`, true},
+		{`displayName sw "ada" and active eq true`, true},
+		{`displayName co "grace" or externalId eq "e-1"`, true},
+		{`not (externalId pr)`, false},
+		{`name.givenName pr`, false},
+		{`userName ne "ada@example.org" or (active eq false and displayName ew "lace")`, false},
+	}
+	for _, c := range cases {
+		f, err := scim.ParseFilter(c.filter)
+		if err != nil {
+			t.Fatalf("%s: %v", c.filter, err)
+		}
+		if got := f.Match(u); got != c.match {
+			t.Errorf("%s: expected %v, got %v", c.filter, c.match, got)
+		}
+	}
+
+	for _, bad := range []string{`userName`, `userName zz "x"`, `(userName eq "x"`, `userName eq "x" and`} {
+		if _, err := scim.ParseFilter(bad); !errors.Is(err, scim.ErrInvalidFilter) {
+			t.Errorf("%s: expected an invalid filter, got %v", bad, err)
+		}
+	}
+}
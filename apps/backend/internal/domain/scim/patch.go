@@ -0,0 +1,339 @@
+package scim
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// PatchOperation is one operation of a PATCH request (RFC 7644 section 3.5.2)
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Path is a parsed PATCH path: attribute[filter].subAttribute
+type Path struct {
+	Attribute    string
+	Filter       Filter
+	SubAttribute string
+}
+
+// ParsePath parses a PATCH path. The attribute and sub-attribute are lowercased.
+func ParsePath(s string) (Path, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Path{}, nil
+	}
+	var p Path
+	if open := strings.IndexByte(s, '['); open >= 0 {
+		closing := strings.LastIndexByte(s, ']')
+		if closing < open {
+			return p, fmt.Errorf("%w: unbalanced [ in %q", ErrInvalidPath, s)
+		}
+		f, err := ParseFilter(s[open+1 : closing])
+		if err != nil || f == nil {
+			return p, fmt.Errorf("%w: invalid filter in %q", ErrInvalidPath, s)
+		}
+		p.Filter = f
+		p.Attribute = NormalizePath(s[:open])
+		p.SubAttribute = strings.ToLower(strings.TrimPrefix(s[closing+1:], "."))
+		return p, nil
+	}
+	p.Attribute = NormalizePath(s)
+	if attr, sub, ok := strings.Cut(p.Attribute, "."); ok && !strings.HasPrefix(p.Attribute, "urn:") {
+		p.Attribute, p.SubAttribute = attr, sub
+	}
+	return p, nil
+}
+
+// String returns the dotted form of a path without filter
+func (p Path) String() string {
+	if p.SubAttribute == "" {
+		return p.Attribute
+	}
+	return p.Attribute + "." + p.SubAttribute
+}
+
+func normalizeOp(op string) (string, error) {
+	switch o := strings.ToLower(op); o {
+	case "add", "replace", "remove":
+		return o, nil
+	default:
+		return "", fmt.Errorf("%w: unknown operation %q", ErrInvalidSyntax, op)
+	}
+}
+
+// ApplyUserPatch applies the operations to u. Attributes MORIS does not store, such as phone numbers
+// or the enterprise extension, are ignored so that default identity management mappings keep working.
+func ApplyUserPatch(u *User, ops []PatchOperation) error {
+	for _, op := range ops {
+		kind, err := normalizeOp(op.Op)
+		if err != nil {
+			return err
+		}
+		path, err := ParsePath(op.Path)
+		if err != nil {
+			return err
+		}
+
+		if path.Attribute == "" {
+			if kind == "remove" {
+				return fmt.Errorf("%w: remove requires a path", ErrNoTarget)
+			}
+			var values map[string]json.RawMessage
+			if err := json.Unmarshal(op.Value, &values); err != nil {
+				return fmt.Errorf("%w: value must be an object when the path is omitted", ErrInvalidValue)
+			}
+			for key, raw := range values {
+				p, err := ParsePath(key)
+				if err != nil {
+					return err
+				}
+				if err := setUserAttribute(u, p, raw); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if kind == "remove" {
+			if err := removeUserAttribute(u, path); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := setUserAttribute(u, path, op.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setUserAttribute(u *User, p Path, raw json.RawMessage) error {
+	switch p.String() {
+	case "active":
+		active, err := parseBool(raw)
+		if err != nil {
+			return err
+		}
+		u.Active = active
+	case "username":
+		return setString(raw, &u.UserName)
+	case "displayname", "name.formatted":
+		return setString(raw, &u.DisplayName)
+	case "externalid":
+		return setOptional(raw, &u.ExternalID)
+	case "name.givenname":
+		return setOptional(raw, &u.GivenName)
+	case "name.familyname":
+		return setOptional(raw, &u.FamilyName)
+	case "name":
+		var name struct {
+			Formatted  *string `json:"formatted"`
+			GivenName  *string `json:"givenName"`
+			FamilyName *string `json:"familyName"`
+		}
+		if err := json.Unmarshal(raw, &name); err != nil {
+			return fmt.Errorf("%w: name must be an object", ErrInvalidValue)
+		}
+		if name.Formatted != nil {
+			u.DisplayName = *name.Formatted
+		}
+		u.GivenName = nonEmpty(name.GivenName)
+		u.FamilyName = nonEmpty(name.FamilyName)
+	case "emails", "emails.value":
+		// MORIS keeps one address; the primary one wins, otherwise the first
+		if p.SubAttribute == "value" {
+			return setString(raw, &u.Email)
+		}
+		var emails []struct {
+			Value   string `json:"value"`
+			Primary bool   `json:"primary"`
+		}
+		if err := json.Unmarshal(raw, &emails); err != nil {
+			return fmt.Errorf("%w: emails must be a list", ErrInvalidValue)
+		}
+		for i, e := range emails {
+			if e.Primary || i == 0 {
+				u.Email = e.Value
+			}
+		}
+	}
+	return nil
+}
+
+func removeUserAttribute(u *User, p Path) error {
+	switch p.String() {
+	case "externalid":
+		u.ExternalID = nil
+	case "name.givenname":
+		u.GivenName = nil
+	case "name.familyname":
+		u.FamilyName = nil
+	case "username", "emails", "emails.value", "active", "displayname":
+		return fmt.Errorf("%w: %s is required", ErrMutability, p.String())
+	}
+	return nil
+}
+
+// ApplyGroupPatch applies the operations to the members of g and returns the members to keep. The display name is derived from the
+// role scope, so it cannot be changed; replacing it with the same value is accepted.
+func ApplyGroupPatch(g Group, ops []PatchOperation) (map[uuid.UUID]bool, error) {
+	members := map[uuid.UUID]bool{}
+	for _, m := range g.Members {
+		members[m.UserID] = true
+	}
+
+	for _, op := range ops {
+		kind, err := normalizeOp(op.Op)
+		if err != nil {
+			return nil, err
+		}
+		path, err := ParsePath(op.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		if path.Attribute == "" {
+			if kind == "remove" {
+				return nil, fmt.Errorf("%w: remove requires a path", ErrNoTarget)
+			}
+			var values map[string]json.RawMessage
+			if err := json.Unmarshal(op.Value, &values); err != nil {
+				return nil, fmt.Errorf("%w: value must be an object when the path is omitted", ErrInvalidValue)
+			}
+			for key, raw := range values {
+				p, err := ParsePath(key)
+				if err != nil {
+					return nil, err
+				}
+				if err := patchGroupAttribute(g, members, kind, p, raw); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+		if err := patchGroupAttribute(g, members, kind, path, op.Value); err != nil {
+			return nil, err
+		}
+	}
+	return members, nil
+}
+
+func patchGroupAttribute(g Group, members map[uuid.UUID]bool, kind string, p Path, raw json.RawMessage) error {
+	switch p.Attribute {
+	case "displayname":
+		var name string
+		if kind == "remove" || json.Unmarshal(raw, &name) != nil || name != g.DisplayName {
+			return fmt.Errorf("%w: displayName is derived from the role and node", ErrMutability)
+		}
+		return nil
+	case "members":
+	default:
+		// Attributes MORIS does not store, such as externalId, are ignored
+		return nil
+	}
+
+	if kind == "remove" {
+		if p.Filter != nil {
+			for _, m := range g.Members {
+				if p.Filter.Match(m) {
+					delete(members, m.UserID)
+				}
+			}
+			return nil
+		}
+		if len(raw) == 0 {
+			clear(members)
+			return nil
+		}
+	}
+
+	ids, err := memberIDs(raw)
+	if err != nil {
+		return err
+	}
+	switch kind {
+	case "replace":
+		clear(members)
+		fallthrough
+	case "add":
+		for _, id := range ids {
+			members[id] = true
+		}
+	case "remove":
+		for _, id := range ids {
+			delete(members, id)
+		}
+	}
+	return nil
+}
+
+// memberIDs parses a members value: a list of {"value": "<user id>"}, or a single one
+func memberIDs(raw json.RawMessage) ([]uuid.UUID, error) {
+	type ref struct {
+		Value string `json:"value"`
+	}
+	var refs []ref
+	if err := json.Unmarshal(raw, &refs); err != nil {
+		var one ref
+		if err := json.Unmarshal(raw, &one); err != nil {
+			return nil, fmt.Errorf("%w: members must be a list of references", ErrInvalidValue)
+		}
+		refs = []ref{one}
+	}
+	ids := make([]uuid.UUID, 0, len(refs))
+	for _, r := range refs {
+		id, err := uuid.Parse(r.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: member %q is not a user id", ErrInvalidValue, r.Value)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// parseBool accepts JSON booleans as well as the strings "True" and "False" some clients send
+func parseBool(raw json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(raw, &b); err == nil {
+		return b, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b, nil
+		}
+	}
+	return false, fmt.Errorf("%w: expected a boolean", ErrInvalidValue)
+}
+
+func setString(raw json.RawMessage, dst *string) error {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return fmt.Errorf("%w: expected a string", ErrInvalidValue)
+	}
+	*dst = s
+	return nil
+}
+
+func setOptional(raw json.RawMessage, dst **string) error {
+	var s *string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return fmt.Errorf("%w: expected a string", ErrInvalidValue)
+	}
+	*dst = nonEmpty(s)
+	return nil
+}
+
+func nonEmpty(s *string) *string {
+	if s == nil || *s == "" {
+		return nil
+	}
+	return s
+}
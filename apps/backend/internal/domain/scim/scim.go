@@ -0,0 +1,189 @@
+// Package scim holds the resources and protocol rules of the SCIM 2.0 provisioning API (RFC 7643, RFC 7644).
+package scim
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/ent"
+	"github.com/google/uuid"
+)
+
+// Errors map onto the scimType values of RFC 7644 section 3.12
+var (
+	ErrNotFound      = errors.New("scim_resource_not_found")
+	ErrInvalidFilter = errors.New("invalidFilter")
+	ErrInvalidPath   = errors.New("invalidPath")
+	ErrInvalidValue  = errors.New("invalidValue")
+	ErrInvalidSyntax = errors.New("invalidSyntax")
+	ErrNoTarget      = errors.New("noTarget")
+	ErrMutability    = errors.New("mutability")
+	ErrUniqueness    = errors.New("uniqueness")
+	ErrUnauthorized  = errors.New("scim_unauthorized")
+	ErrNotRootNode   = errors.New("scim tokens can only be issued for root nodes")
+)
+
+// tokenPrefix marks SCIM tokens so that they are recognisable in configuration
+const tokenPrefix = "scim_"
+
+// Token authorises an identity management system to provision the users and memberships of one
+// root node. Only the hash of the secret is stored.
+type Token struct {
+	ID              uuid.UUID
+	RootNodeID      uuid.UUID
+	Name            string
+	CreatedByUserID *uuid.UUID
+	CreatedAt       time.Time
+	LastUsedAt      *time.Time
+}
+
+func (t *Token) FromEnt(row *ent.ScimToken) *Token {
+	return &Token{
+		ID:              row.ID,
+		RootNodeID:      row.RootNodeID,
+		Name:            row.Name,
+		CreatedByUserID: row.CreatedByUserID,
+		CreatedAt:       row.CreatedAt,
+		LastUsedAt:      row.LastUsedAt,
+	}
+}
+
+// NewTokenSecret generates a random bearer token
+func NewTokenSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return tokenPrefix + hex.EncodeToString(b), nil
+}
+
+// HashToken returns the stored form of a bearer token
+func HashToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// User is a MORIS user and its person as a SCIM User resource. ID is the user ID; userName is the
+// email address of the person, which is also its only email.
+type User struct {
+	ID          uuid.UUID
+	PersonID    uuid.UUID
+	ExternalID  *string
+	UserName    string
+	DisplayName string
+	GivenName   *string
+	FamilyName  *string
+	Email       string
+	Active      bool
+	Created     time.Time
+}
+
+// Values returns the values of a (lowercase) attribute path for filtering
+func (u User) Values(path string) []string {
+	switch path {
+	case "id":
+		return []string{u.ID.String()}
+	case "externalid":
+		return optional(u.ExternalID)
+	case "username":
+		return []string{u.UserName}
+	case "displayname", "name.formatted":
+		return []string{u.DisplayName}
+	case "name.givenname":
+		return optional(u.GivenName)
+	case "name.familyname":
+		return optional(u.FamilyName)
+	case "emails", "emails.value":
+		return []string{u.Email}
+	case "active":
+		return []string{boolString(u.Active)}
+	case "meta.created":
+		return []string{u.Created.UTC().Format(time.RFC3339)}
+	default:
+		return nil
+	}
+}
+
+// Group is a role scope as a SCIM Group resource: its members are the people of the tenant who
+// have a membership of the scope. ID is the role scope ID.
+type Group struct {
+	ID          uuid.UUID
+	DisplayName string
+	NodeID      uuid.UUID
+	RoleKey     string
+	Members     []Member
+}
+
+// Member is a user in a group
+type Member struct {
+	UserID  uuid.UUID
+	Display string
+}
+
+func (g Group) Values(path string) []string {
+	switch path {
+	case "id":
+		return []string{g.ID.String()}
+	case "displayname":
+		return []string{g.DisplayName}
+	case "members", "members.value":
+		out := make([]string, len(g.Members))
+		for i, m := range g.Members {
+			out[i] = m.UserID.String()
+		}
+		return out
+	case "members.display":
+		out := make([]string, len(g.Members))
+		for i, m := range g.Members {
+			out[i] = m.Display
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
+func (m Member) Values(path string) []string {
+	switch path {
+	case "value":
+		return []string{m.UserID.String()}
+	case "display":
+		return []string{m.Display}
+	default:
+		return nil
+	}
+}
+
+// Page is one page of a list request. StartIndex is 1-based.
+type Page[T any] struct {
+	Resources    []T
+	TotalResults int
+	StartIndex   int
+}
+
+// Paginate applies startIndex and count to the filtered resources
+func Paginate[T any](all []T, startIndex, count int) Page[T] {
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	from := min(startIndex-1, len(all))
+	to := min(from+count, len(all))
+	return Page[T]{Resources: all[from:to], TotalResults: len(all), StartIndex: startIndex}
+}
+
+func optional(s *string) []string {
+	if s == nil {
+		return nil
+	}
+	return []string{*s}
+}
+
+func boolString(b bool) string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
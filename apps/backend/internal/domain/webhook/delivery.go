@@ -0,0 +1,65 @@
+package webhook
+
+import (
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/ent"
+	"github.com/google/uuid"
+)
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// Delivery is a single queued webhook POST and the outcome of its latest attempt
+type Delivery struct {
+	ID             uuid.UUID
+	PolicyID       *uuid.UUID
+	SubscriptionID *uuid.UUID
+	EventID        *uuid.UUID
+	EventType      string
+	URL            string
+	Payload        []byte
+	Status         DeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastAttemptAt  *time.Time
+	LastStatusCode *int
+	LastError      *string
+	CreatedAt      time.Time
+}
+
+// AttemptResult records the outcome of a single delivery attempt
+type AttemptResult struct {
+	At            time.Time
+	StatusCode    *int
+	Error         *string
+	Status        DeliveryStatus
+	NextAttemptAt time.Time
+}
+
+func (d *Delivery) FromEnt(row *ent.WebhookDelivery) *Delivery {
+	if row == nil {
+		return nil
+	}
+	return &Delivery{
+		ID:             row.ID,
+		PolicyID:       row.PolicyID,
+		SubscriptionID: row.SubscriptionID,
+		EventID:        row.EventID,
+		EventType:      row.EventType,
+		URL:            row.URL,
+		Payload:        []byte(row.Payload),
+		Status:         DeliveryStatus(row.Status.String()),
+		Attempts:       row.Attempts,
+		NextAttemptAt:  row.NextAttemptAt,
+		LastAttemptAt:  row.LastAttemptAt,
+		LastStatusCode: row.LastStatusCode,
+		LastError:      row.LastError,
+		CreatedAt:      row.CreatedAt,
+	}
+}
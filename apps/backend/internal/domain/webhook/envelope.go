@@ -0,0 +1,131 @@
+package webhook
+
+import (
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/internal/domain/policy"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/project"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/project/events"
+	"github.com/google/uuid"
+)
+
+// EnvelopeTypePolicyTriggered is the envelope type sent by webhook event policies
+const EnvelopeTypePolicyTriggered = "event_policy.triggered"
+
+// Envelope is the JSON document POSTed to webhook endpoints
+type Envelope struct {
+	ID           uuid.UUID             `json:"id"`
+	Type         string                `json:"type"`
+	OccurredAt   time.Time             `json:"occurred_at"`
+	Event        EventSnapshot         `json:"event"`
+	Project      *ProjectSnapshot      `json:"project,omitempty"`
+	Policy       *PolicySnapshot       `json:"policy,omitempty"`
+	Subscription *SubscriptionSnapshot `json:"subscription,omitempty"`
+}
+
+// EventSnapshot describes the project event that triggered the webhook
+type EventSnapshot struct {
+	ID           uuid.UUID     `json:"id"`
+	Type         string        `json:"type"`
+	FriendlyName string        `json:"friendly_name"`
+	Status       events.Status `json:"status"`
+	CreatedBy    uuid.UUID     `json:"created_by"`
+	At           time.Time     `json:"at"`
+	Data         events.Event  `json:"data"`
+}
+
+// ProjectMemberSnapshot is a project member in a ProjectSnapshot
+type ProjectMemberSnapshot struct {
+	PersonID      uuid.UUID  `json:"person_id"`
+	ProjectRoleID uuid.UUID  `json:"project_role_id"`
+	StartDate     *time.Time `json:"start_date,omitempty"`
+	EndDate       *time.Time `json:"end_date,omitempty"`
+}
+
+// ProjectSnapshot is the state of the project after the event was applied
+type ProjectSnapshot struct {
+	ID                        uuid.UUID               `json:"id"`
+	Version                   int                     `json:"version"`
+	Title                     string                  `json:"title"`
+	Description               string                  `json:"description"`
+	StartDate                 time.Time               `json:"start_date"`
+	EndDate                   time.Time               `json:"end_date"`
+	OwningOrgNodeID           uuid.UUID               `json:"owning_org_node_id"`
+	Members                   []ProjectMemberSnapshot `json:"members"`
+	ProductIDs                []uuid.UUID             `json:"product_ids"`
+	AffiliatedOrganisationIDs []uuid.UUID             `json:"affiliated_organisation_ids"`
+	CustomFields              map[string]any          `json:"custom_fields,omitempty"`
+}
+
+// PolicySnapshot identifies the policy that triggered the webhook. Secrets are never included.
+type PolicySnapshot struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	EventTypes []string   `json:"event_types"`
+	OrgNodeID  *uuid.UUID `json:"org_node_id,omitempty"`
+	ProjectID  *uuid.UUID `json:"project_id,omitempty"`
+}
+
+// SubscriptionSnapshot identifies the subscription a delivery belongs to. Secrets are never included.
+type SubscriptionSnapshot struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	OrgNodeID uuid.UUID `json:"org_node_id"`
+}
+
+// NewEventSnapshot builds the event part of an envelope
+func NewEventSnapshot(e events.Event) EventSnapshot {
+	return EventSnapshot{
+		ID:           e.GetID(),
+		Type:         e.Type(),
+		FriendlyName: e.FriendlyName(),
+		Status:       e.GetStatus(),
+		CreatedBy:    e.CreatedByID(),
+		At:           e.OccurredAt(),
+		Data:         e,
+	}
+}
+
+// NewProjectSnapshot builds the project part of an envelope
+func NewProjectSnapshot(p *project.Project) *ProjectSnapshot {
+	if p == nil {
+		return nil
+	}
+	members := make([]ProjectMemberSnapshot, len(p.Members))
+	for i, m := range p.Members {
+		members[i] = ProjectMemberSnapshot{PersonID: m.PersonID, ProjectRoleID: m.ProjectRoleID, StartDate: m.StartDate, EndDate: m.EndDate}
+	}
+	return &ProjectSnapshot{
+		ID:                        p.Id,
+		Version:                   p.Version,
+		Title:                     p.Title,
+		Description:               p.Description,
+		StartDate:                 p.StartDate,
+		EndDate:                   p.EndDate,
+		OwningOrgNodeID:           p.OwningOrgNodeID,
+		Members:                   members,
+		ProductIDs:                p.ProductIDs,
+		AffiliatedOrganisationIDs: p.AffiliatedOrganisationIDs,
+		CustomFields:              p.CustomFields,
+	}
+}
+
+// NewPolicySnapshot builds the policy part of an envelope
+func NewPolicySnapshot(p policy.EventPolicy) *PolicySnapshot {
+	return &PolicySnapshot{
+		ID:         p.ID,
+		Name:       p.Name,
+		EventTypes: p.EventTypes,
+		OrgNodeID:  p.OrgNodeID,
+		ProjectID:  p.ProjectID,
+	}
+}
+
+// NewSubscriptionSnapshot builds the subscription part of an envelope
+func NewSubscriptionSnapshot(s Subscription) *SubscriptionSnapshot {
+	return &SubscriptionSnapshot{
+		ID:        s.ID,
+		Name:      s.Name,
+		OrgNodeID: s.OrgNodeID,
+	}
+}
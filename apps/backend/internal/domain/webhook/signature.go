@@ -0,0 +1,48 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// Headers sent with every webhook delivery
+const (
+	HeaderDeliveryID = "X-MORIS-Delivery"
+	HeaderEventType  = "X-MORIS-Event"
+	HeaderTimestamp  = "X-MORIS-Timestamp"
+	HeaderSignature  = "X-MORIS-Signature"
+)
+
+// Sign computes the HMAC-SHA256 signature of a delivery as "sha256=<hex>".
+// The signed message is "<unix timestamp>.<body>" so that receivers can reject replayed requests.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignAll signs a delivery with each of the secrets and joins the signatures with commas, so that
+// receivers that have not switched to a rotated secret yet can still verify it
+func SignAll(secrets []string, timestamp int64, body []byte) string {
+	signatures := make([]string, 0, len(secrets))
+	for _, secret := range secrets {
+		signatures = append(signatures, Sign(secret, timestamp, body))
+	}
+	return strings.Join(signatures, ",")
+}
+
+// Verify checks in constant time that one of the comma-separated signatures was produced by Sign
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	expected := []byte(Sign(secret, timestamp, body))
+	for _, sig := range strings.Split(signature, ",") {
+		if hmac.Equal(expected, []byte(strings.TrimSpace(sig))) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package webhook
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/ent"
+	"github.com/SURF-Innovatie/MORIS/internal/common/netguard"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/project/events"
+	"github.com/google/uuid"
+)
+
+// EnvelopeTypeSubscription is the envelope type sent to webhook subscriptions
+const EnvelopeTypeSubscription = "subscription.event"
+
+// secretPrefix marks subscription secrets so that they are recognisable in configuration
+const secretPrefix = "whsec_"
+
+// SecretGracePeriod is how long deliveries are still signed with a secret after it was rotated,
+// giving receivers time to switch to the new one
+const SecretGracePeriod = 24 * time.Hour
+
+var ErrInvalidSubscription = errors.New("invalid_webhook_subscription")
+
+// Subscription delivers the events of the subscribed types for all projects owned by an
+// organisation node, and by its descendants when IncludeDescendants is set
+type Subscription struct {
+	ID                 uuid.UUID
+	Name               string
+	OrgNodeID          uuid.UUID
+	IncludeDescendants bool
+	EventTypes         []string // empty means all event types
+	URL                string
+	Secret             string
+	SecretRotatedAt    time.Time
+	Enabled            bool
+	CreatedBy          *uuid.UUID
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+
+	// PreviousSecret is the secret replaced by the last rotation, valid until PreviousSecretExpiresAt
+	PreviousSecret          *string
+	PreviousSecretExpiresAt *time.Time
+}
+
+// Validate checks the name, scope, endpoint and event types of the subscription
+func (s *Subscription) Validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidSubscription)
+	}
+	if s.OrgNodeID == uuid.Nil {
+		return fmt.Errorf("%w: org_node_id is required", ErrInvalidSubscription)
+	}
+	if err := netguard.ValidateURL(s.URL); err != nil {
+		return fmt.Errorf("%w: url: %s", ErrInvalidSubscription, err)
+	}
+	for _, t := range s.EventTypes {
+		if _, err := events.Create(t); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidSubscription, err)
+		}
+	}
+	return nil
+}
+
+// MatchesEventType reports whether the subscription wants events of the given type
+func (s *Subscription) MatchesEventType(eventType string) bool {
+	return len(s.EventTypes) == 0 || slices.Contains(s.EventTypes, eventType)
+}
+
+// Covers reports whether the subscription applies to projects owned by a node, given that
+// node's ancestors (including the node itself)
+func (s *Subscription) Covers(ownerNodeID uuid.UUID, ancestorIDs []uuid.UUID) bool {
+	if s.OrgNodeID == ownerNodeID {
+		return true
+	}
+	return s.IncludeDescendants && slices.Contains(ancestorIDs, s.OrgNodeID)
+}
+
+// SigningSecrets returns the secrets deliveries are signed with at the given time: the current
+// secret, followed by the previous one while its grace period lasts
+func (s *Subscription) SigningSecrets(now time.Time) []string {
+	secrets := []string{s.Secret}
+	if s.PreviousSecret != nil && s.PreviousSecretExpiresAt != nil && now.Before(*s.PreviousSecretExpiresAt) {
+		secrets = append(secrets, *s.PreviousSecret)
+	}
+	return secrets
+}
+
+// NewSecret generates a random signing secret
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return secretPrefix + hex.EncodeToString(b), nil
+}
+
+func (s *Subscription) FromEnt(row *ent.WebhookSubscription) *Subscription {
+	if row == nil {
+		return nil
+	}
+	return &Subscription{
+		ID:                 row.ID,
+		Name:               row.Name,
+		OrgNodeID:          row.OrgNodeID,
+		IncludeDescendants: row.IncludeDescendants,
+		EventTypes:         row.EventTypes,
+		URL:                row.URL,
+		Secret:             row.Secret,
+		SecretRotatedAt:    row.SecretRotatedAt,
+		Enabled:            row.Enabled,
+		CreatedBy:          row.CreatedBy,
+		CreatedAt:          row.CreatedAt,
+		UpdatedAt:          row.UpdatedAt,
+
+		PreviousSecret:          row.PreviousSecret,
+		PreviousSecretExpiresAt: row.PreviousSecretExpiresAt,
+	}
+}
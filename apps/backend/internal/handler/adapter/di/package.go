@@ -2,6 +2,7 @@ package di
 
 import (
 	"github.com/SURF-Innovatie/MORIS/internal/adapter"
+	organisationrbac "github.com/SURF-Innovatie/MORIS/internal/app/organisation/rbac"
 	"github.com/SURF-Innovatie/MORIS/internal/app/project/queries"
 	adapterhandler "github.com/SURF-Innovatie/MORIS/internal/handler/adapter"
 	"github.com/samber/do/v2"
@@ -14,5 +15,6 @@ var Package = do.Package(
 func provideAdapterHandler(i do.Injector) (*adapterhandler.Handler, error) {
 	registry := do.MustInvoke[*adapter.Registry](i)
 	projSvc := do.MustInvoke[queries.Service](i)
-	return adapterhandler.NewHandler(registry, projSvc), nil
+	rbacSvc := do.MustInvoke[organisationrbac.Service](i)
+	return adapterhandler.NewHandler(registry, projSvc, rbacSvc), nil
 }
@@ -1,13 +1,17 @@
 package adapter
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/SURF-Innovatie/MORIS/internal/adapter"
 	"github.com/SURF-Innovatie/MORIS/internal/api/dto"
+	organisationrbac "github.com/SURF-Innovatie/MORIS/internal/app/organisation/rbac"
 	"github.com/SURF-Innovatie/MORIS/internal/app/project/queries"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/identity"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation/rbac"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/project"
 	"github.com/SURF-Innovatie/MORIS/internal/infra/httputil"
 	"github.com/go-chi/chi/v5"
@@ -17,12 +21,14 @@ import (
 type Handler struct {
 	registry   *adapter.Registry
 	projectSvc queries.Service
+	rbac       organisationrbac.Service
 }
 
-func NewHandler(registry *adapter.Registry, projectSvc queries.Service) *Handler {
+func NewHandler(registry *adapter.Registry, projectSvc queries.Service, rbac organisationrbac.Service) *Handler {
 	return &Handler{
 		registry:   registry,
 		projectSvc: projectSvc,
+		rbac:       rbac,
 	}
 }
 
@@ -68,6 +74,7 @@ func (h *Handler) ListAdapters(w http.ResponseWriter, r *http.Request) {
 // ExportProject godoc
 // @Summary Export a project using a sink adapter
 // @Description Triggers a project export to the specified sink. For file-based sinks, returns a file download.
+// @Description Requires the export_projects permission on the project's organisation node, or the project lead role on the project.
 // @Tags projects
 // @Param id path string true "Project ID"
 // @Param sink path string true "Sink Name"
@@ -76,6 +83,7 @@ func (h *Handler) ListAdapters(w http.ResponseWriter, r *http.Request) {
 // @Security BearerAuth
 // @Success 200 {object} map[string]string "Export successful or file download"
 // @Failure 400 {string} string "Invalid project id"
+// @Failure 403 {string} string "Forbidden"
 // @Failure 404 {string} string "Project or sink not found"
 // @Failure 500 {string} string "Export failed"
 // @Router /projects/{id}/export/{sink} [post]
@@ -96,10 +104,18 @@ func (h *Handler) ExportProject(w http.ResponseWriter, r *http.Request) {
 	// Get project details
 	projDetails, err := h.projectSvc.GetProject(r.Context(), projectID)
 	if err != nil {
+		if errors.Is(err, rbac.ErrForbidden) {
+			httputil.WriteError(w, r, http.StatusForbidden, err.Error(), nil)
+			return
+		}
 		httputil.WriteError(w, r, http.StatusNotFound, "project not found", nil)
 		return
 	}
 
+	if !h.requireExportPermission(w, r, projDetails) {
+		return
+	}
+
 	// Load event stream
 	evts, err := h.projectSvc.GetEvents(r.Context(), projectID)
 	if err != nil {
@@ -153,3 +169,31 @@ func (h *Handler) ExportProject(w http.ResponseWriter, r *http.Request) {
 
 	_ = httputil.WriteJSON(w, http.StatusOK, map[string]string{"message": "Export successful"})
 }
+
+// requireExportPermission checks that the current user may export the project: current project
+// leads may, as may holders of export_projects on its organisation node
+func (h *Handler) requireExportPermission(w http.ResponseWriter, r *http.Request, proj *queries.ProjectDetails) bool {
+	user, ok := httputil.GetUserFromContext(r.Context())
+	if !ok {
+		httputil.WriteError(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return false
+	}
+	if user.User.IsSysAdmin {
+		return true
+	}
+
+	if proj.IsActiveLead(user.Person.ID, time.Now()) {
+		return true
+	}
+
+	allowed, err := h.rbac.HasPermission(r.Context(), user.Person.ID, proj.Project.OwningOrgNodeID, rbac.PermissionExportProjects)
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+		return false
+	}
+	if !allowed {
+		httputil.WriteError(w, r, http.StatusForbidden, "forbidden: export_projects permission or project lead role required", nil)
+		return false
+	}
+	return true
+}
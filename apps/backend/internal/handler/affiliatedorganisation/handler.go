@@ -2,10 +2,12 @@ package affiliatedorganisation
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/SURF-Innovatie/MORIS/internal/app/affiliatedorganisation"
 	domain "github.com/SURF-Innovatie/MORIS/internal/domain/affiliatedorganisation"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation/rbac"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 	"github.com/google/uuid"
@@ -32,12 +34,13 @@ func (h *Handler) Routes() chi.Router {
 
 // Create godoc
 // @Summary      Create affiliated organisation
-// @Description  Create a new affiliated organisation
+// @Description  Create a new affiliated organisation. Requires the manage_affiliated_orgs permission.
 // @Tags         affiliated-organisations
 // @Accept       json
 // @Produce      json
 // @Param        request body domain.AffiliatedOrganisation true "Organisation data"
 // @Success      200  {object}  domain.AffiliatedOrganisation
+// @Failure      403  {object}  map[string]string
 // @Router       /affiliated-organisations [post]
 func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	var body domain.AffiliatedOrganisation
@@ -49,8 +52,7 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 
 	created, err := h.service.Create(r.Context(), body)
 	if err != nil {
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": err.Error()})
+		writeError(w, r, err)
 		return
 	}
 
@@ -135,7 +137,7 @@ func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
 
 // Update godoc
 // @Summary      Update affiliated organisation
-// @Description  Update an existing affiliated organisation by ID
+// @Description  Update an existing affiliated organisation by ID. Requires the manage_affiliated_orgs permission.
 // @Tags         affiliated-organisations
 // @Accept       json
 // @Produce      json
@@ -143,6 +145,7 @@ func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
 // @Param        request body      domain.AffiliatedOrganisation true "Organisation data"
 // @Success      200     {object}  domain.AffiliatedOrganisation
 // @Failure      400     {object}  map[string]string
+// @Failure      403     {object}  map[string]string
 // @Failure      500     {object}  map[string]string
 // @Router       /affiliated-organisations/{id} [put]
 func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
@@ -163,8 +166,7 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 
 	updated, err := h.service.Update(r.Context(), id, body)
 	if err != nil {
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": err.Error()})
+		writeError(w, r, err)
 		return
 	}
 	render.JSON(w, r, updated)
@@ -172,11 +174,12 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 
 // Delete godoc
 // @Summary      Delete affiliated organisation
-// @Description  Delete an affiliated organisation by ID
+// @Description  Delete an affiliated organisation by ID. Requires the manage_affiliated_orgs permission.
 // @Tags         affiliated-organisations
 // @Param        id   path      string  true  "Organisation ID"
 // @Success      204  "No Content"
 // @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
 // @Failure      500  {object}  map[string]string
 // @Router       /affiliated-organisations/{id} [delete]
 func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
@@ -189,9 +192,18 @@ func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.service.Delete(r.Context(), id); err != nil {
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": err.Error()})
+		writeError(w, r, err)
 		return
 	}
 	render.NoContent(w, r)
 }
+
+// writeError maps service errors to HTTP responses
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, rbac.ErrForbidden) {
+		status = http.StatusForbidden
+	}
+	render.Status(r, status)
+	render.JSON(w, r, map[string]string{"error": err.Error()})
+}
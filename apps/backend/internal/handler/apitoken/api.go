@@ -0,0 +1,26 @@
+package apitoken
+
+import (
+	"github.com/SURF-Innovatie/MORIS/internal/handler/middleware"
+	"github.com/go-chi/chi/v5"
+)
+
+// MountRoutes mounts the management of personal access tokens and, for sysadmins, of service accounts
+func MountRoutes(r chi.Router, h *Handler) {
+	r.Route("/api-tokens", func(r chi.Router) {
+		r.Get("/", h.ListTokens)
+		r.Post("/", h.CreateToken)
+		r.Delete("/{id}", h.DeleteToken)
+	})
+
+	r.Route("/service-accounts", func(r chi.Router) {
+		r.Use(middleware.RequireSysAdminMiddleware())
+		r.Get("/", h.ListServiceAccounts)
+		r.Post("/", h.CreateServiceAccount)
+		r.Get("/{id}", h.GetServiceAccount)
+		r.Put("/{id}", h.UpdateServiceAccount)
+		r.Get("/{id}/tokens", h.ListServiceAccountTokens)
+		r.Post("/{id}/tokens", h.CreateServiceAccountToken)
+		r.Delete("/{id}/tokens/{tokenId}", h.DeleteServiceAccountToken)
+	})
+}
@@ -0,0 +1,16 @@
+package di
+
+import (
+	"github.com/SURF-Innovatie/MORIS/internal/app/apitoken"
+	apitokenhandler "github.com/SURF-Innovatie/MORIS/internal/handler/apitoken"
+	"github.com/samber/do/v2"
+)
+
+var Package = do.Package(
+	do.Lazy(provideHandler),
+)
+
+func provideHandler(i do.Injector) (*apitokenhandler.Handler, error) {
+	svc := do.MustInvoke[apitoken.Service](i)
+	return apitokenhandler.NewHandler(svc), nil
+}
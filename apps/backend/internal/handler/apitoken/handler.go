@@ -0,0 +1,141 @@
+package apitoken
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/SURF-Innovatie/MORIS/internal/api/dto"
+	appapitoken "github.com/SURF-Innovatie/MORIS/internal/app/apitoken"
+	"github.com/SURF-Innovatie/MORIS/internal/common/transform"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/apitoken"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/httputil"
+	"github.com/google/uuid"
+)
+
+type Handler struct {
+	svc appapitoken.Service
+}
+
+func NewHandler(svc appapitoken.Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// ListTokens godoc
+// @Summary List my personal access tokens
+// @Description Lists the personal access tokens of the authenticated user, newest first. Secrets are never returned.
+// @Tags api-tokens
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} dto.APITokenResponse
+// @Failure 401 {string} string "unauthorized"
+// @Failure 500 {string} string "internal server error"
+// @Router /api-tokens [get]
+func (h *Handler) ListTokens(w http.ResponseWriter, r *http.Request) {
+	userID := httputil.GetUserIDFromContext(r.Context())
+	if userID == nil {
+		httputil.WriteError(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+	h.listTokens(w, r, *userID)
+}
+
+// CreateToken godoc
+// @Summary Create a personal access token
+// @Description Creates a token scripts can use instead of signing in. It acts as the authenticated user, limited to its scopes (projects:read, events:write, export) and, if given, to organisation nodes and their descendants. The token is returned only once and expires within a year.
+// @Tags api-tokens
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body dto.APITokenRequest true "Token details"
+// @Success 201 {object} dto.APITokenResponse
+// @Failure 400 {string} string "invalid name, scopes, nodes or expiry"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "impersonating"
+// @Failure 500 {string} string "internal server error"
+// @Router /api-tokens [post]
+func (h *Handler) CreateToken(w http.ResponseWriter, r *http.Request) {
+	userID := httputil.GetUserIDFromContext(r.Context())
+	if userID == nil {
+		httputil.WriteError(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+	h.createToken(w, r, *userID)
+}
+
+// DeleteToken godoc
+// @Summary Revoke a personal access token
+// @Description Revokes one of the authenticated user's personal access tokens
+// @Tags api-tokens
+// @Security BearerAuth
+// @Param id path string true "Token ID (UUID)"
+// @Success 204
+// @Failure 400 {string} string "invalid token id"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 404 {string} string "token not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /api-tokens/{id} [delete]
+func (h *Handler) DeleteToken(w http.ResponseWriter, r *http.Request) {
+	userID := httputil.GetUserIDFromContext(r.Context())
+	if userID == nil {
+		httputil.WriteError(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+	h.deleteToken(w, r, *userID, "id")
+}
+
+func (h *Handler) listTokens(w http.ResponseWriter, r *http.Request, userID uuid.UUID) {
+	tokens, err := h.svc.ListTokens(r.Context(), userID)
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+	_ = httputil.WriteJSON(w, http.StatusOK, transform.ToDTOs[dto.APITokenResponse](tokens))
+}
+
+func (h *Handler) createToken(w http.ResponseWriter, r *http.Request, userID uuid.UUID) {
+	var req dto.APITokenRequest
+	if !httputil.ReadJSON(w, r, &req) {
+		return
+	}
+
+	t, secret, err := h.svc.CreateToken(r.Context(), userID, appapitoken.TokenInput{
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		NodeIDs:   req.NodeIDs,
+		ExpiresAt: req.ExpiresAt,
+	})
+	switch {
+	case err == nil:
+		resp := transform.ToDTOItem[dto.APITokenResponse](*t)
+		resp.Token = secret
+		_ = httputil.WriteJSON(w, http.StatusCreated, resp)
+	case errors.Is(err, apitoken.ErrInvalidValue),
+		errors.Is(err, apitoken.ErrInvalidScope),
+		errors.Is(err, apitoken.ErrNoScopes),
+		errors.Is(err, apitoken.ErrInvalidExpiry),
+		errors.Is(err, apitoken.ErrUnknownNode):
+		httputil.WriteError(w, r, http.StatusBadRequest, err.Error(), nil)
+	case errors.Is(err, apitoken.ErrImpersonating):
+		httputil.WriteError(w, r, http.StatusForbidden, err.Error(), nil)
+	default:
+		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+	}
+}
+
+func (h *Handler) deleteToken(w http.ResponseWriter, r *http.Request, userID uuid.UUID, param string) {
+	tokenID, err := httputil.ParseUUIDParam(r, param)
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusBadRequest, "invalid token id", nil)
+		return
+	}
+
+	err = h.svc.DeleteToken(r.Context(), userID, tokenID)
+	switch {
+	case err == nil:
+		w.WriteHeader(http.StatusNoContent)
+	case errors.Is(err, apitoken.ErrNotFound):
+		httputil.WriteError(w, r, http.StatusNotFound, "token not found", nil)
+	default:
+		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+	}
+}
@@ -0,0 +1,222 @@
+package apitoken
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/SURF-Innovatie/MORIS/internal/api/dto"
+	appapitoken "github.com/SURF-Innovatie/MORIS/internal/app/apitoken"
+	"github.com/SURF-Innovatie/MORIS/internal/common/transform"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/apitoken"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/httputil"
+	"github.com/samber/lo"
+)
+
+// ListServiceAccounts godoc
+// @Summary List service accounts
+// @Description Lists the accounts integrations use to call the API with tokens. Sysadmin only.
+// @Tags service-accounts
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} dto.ServiceAccountResponse
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 500 {string} string "internal server error"
+// @Router /service-accounts [get]
+func (h *Handler) ListServiceAccounts(w http.ResponseWriter, r *http.Request) {
+	accounts, err := h.svc.ListServiceAccounts(r.Context())
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+	_ = httputil.WriteJSON(w, http.StatusOK, transform.ToDTOs[dto.ServiceAccountResponse](accounts))
+}
+
+// CreateServiceAccount godoc
+// @Summary Create a service account
+// @Description Creates an account for an integration. It cannot sign in; it calls the API with its tokens and is given organisation and project roles through its personId like any person. Sysadmin only.
+// @Tags service-accounts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body dto.ServiceAccountRequest true "Service account details"
+// @Success 201 {object} dto.ServiceAccountResponse
+// @Failure 400 {string} string "invalid name"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 500 {string} string "internal server error"
+// @Router /service-accounts [post]
+func (h *Handler) CreateServiceAccount(w http.ResponseWriter, r *http.Request) {
+	var req dto.ServiceAccountRequest
+	if !httputil.ReadJSON(w, r, &req) {
+		return
+	}
+
+	sa, err := h.svc.CreateServiceAccount(r.Context(), serviceAccountInput(req))
+	switch {
+	case err == nil:
+		_ = httputil.WriteJSON(w, http.StatusCreated, transform.ToDTOItem[dto.ServiceAccountResponse](*sa))
+	case errors.Is(err, apitoken.ErrInvalidValue):
+		httputil.WriteError(w, r, http.StatusBadRequest, err.Error(), nil)
+	default:
+		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+	}
+}
+
+// GetServiceAccount godoc
+// @Summary Get a service account
+// @Description Returns a service account. Sysadmin only.
+// @Tags service-accounts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Service account ID (UUID)"
+// @Success 200 {object} dto.ServiceAccountResponse
+// @Failure 400 {string} string "invalid service account id"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "service account not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /service-accounts/{id} [get]
+func (h *Handler) GetServiceAccount(w http.ResponseWriter, r *http.Request) {
+	sa, ok := h.loadServiceAccount(w, r)
+	if !ok {
+		return
+	}
+	_ = httputil.WriteJSON(w, http.StatusOK, transform.ToDTOItem[dto.ServiceAccountResponse](*sa))
+}
+
+// UpdateServiceAccount godoc
+// @Summary Update a service account
+// @Description Renames, activates or deactivates a service account. Deactivating it deletes its tokens. Sysadmin only.
+// @Tags service-accounts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Service account ID (UUID)"
+// @Param body body dto.ServiceAccountRequest true "Service account details"
+// @Success 200 {object} dto.ServiceAccountResponse
+// @Failure 400 {string} string "invalid request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "service account not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /service-accounts/{id} [put]
+func (h *Handler) UpdateServiceAccount(w http.ResponseWriter, r *http.Request) {
+	sa, ok := h.loadServiceAccount(w, r)
+	if !ok {
+		return
+	}
+	var req dto.ServiceAccountRequest
+	if !httputil.ReadJSON(w, r, &req) {
+		return
+	}
+
+	updated, err := h.svc.UpdateServiceAccount(r.Context(), sa.UserID, serviceAccountInput(req))
+	switch {
+	case err == nil:
+		_ = httputil.WriteJSON(w, http.StatusOK, transform.ToDTOItem[dto.ServiceAccountResponse](*updated))
+	case errors.Is(err, apitoken.ErrInvalidValue):
+		httputil.WriteError(w, r, http.StatusBadRequest, err.Error(), nil)
+	default:
+		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+	}
+}
+
+// ListServiceAccountTokens godoc
+// @Summary List the tokens of a service account
+// @Description Lists the tokens of a service account, newest first. Secrets are never returned. Sysadmin only.
+// @Tags service-accounts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Service account ID (UUID)"
+// @Success 200 {array} dto.APITokenResponse
+// @Failure 400 {string} string "invalid service account id"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "service account not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /service-accounts/{id}/tokens [get]
+func (h *Handler) ListServiceAccountTokens(w http.ResponseWriter, r *http.Request) {
+	sa, ok := h.loadServiceAccount(w, r)
+	if !ok {
+		return
+	}
+	h.listTokens(w, r, sa.UserID)
+}
+
+// CreateServiceAccountToken godoc
+// @Summary Issue a token for a service account
+// @Description Issues a token for a service account, limited to its scopes (projects:read, events:write, export) and, if given, to organisation nodes and their descendants. The token is returned only once and expires within a year. Sysadmin only.
+// @Tags service-accounts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Service account ID (UUID)"
+// @Param body body dto.APITokenRequest true "Token details"
+// @Success 201 {object} dto.APITokenResponse
+// @Failure 400 {string} string "invalid name, scopes, nodes or expiry"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "service account not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /service-accounts/{id}/tokens [post]
+func (h *Handler) CreateServiceAccountToken(w http.ResponseWriter, r *http.Request) {
+	sa, ok := h.loadServiceAccount(w, r)
+	if !ok {
+		return
+	}
+	if !sa.Active {
+		httputil.WriteError(w, r, http.StatusBadRequest, "service account is inactive", nil)
+		return
+	}
+	h.createToken(w, r, sa.UserID)
+}
+
+// DeleteServiceAccountToken godoc
+// @Summary Revoke a token of a service account
+// @Description Revokes a token of a service account. Sysadmin only.
+// @Tags service-accounts
+// @Security BearerAuth
+// @Param id path string true "Service account ID (UUID)"
+// @Param tokenId path string true "Token ID (UUID)"
+// @Success 204
+// @Failure 400 {string} string "invalid id"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "service account or token not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /service-accounts/{id}/tokens/{tokenId} [delete]
+func (h *Handler) DeleteServiceAccountToken(w http.ResponseWriter, r *http.Request) {
+	sa, ok := h.loadServiceAccount(w, r)
+	if !ok {
+		return
+	}
+	h.deleteToken(w, r, sa.UserID, "tokenId")
+}
+
+// loadServiceAccount reads the service account of the request path
+func (h *Handler) loadServiceAccount(w http.ResponseWriter, r *http.Request) (*apitoken.ServiceAccount, bool) {
+	id, err := httputil.ParseUUIDParam(r, "id")
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusBadRequest, "invalid service account id", nil)
+		return nil, false
+	}
+	sa, err := h.svc.GetServiceAccount(r.Context(), id)
+	if errors.Is(err, apitoken.ErrNotServiceAccount) {
+		httputil.WriteError(w, r, http.StatusNotFound, "service account not found", nil)
+		return nil, false
+	}
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+		return nil, false
+	}
+	return sa, true
+}
+
+func serviceAccountInput(req dto.ServiceAccountRequest) appapitoken.ServiceAccountInput {
+	return appapitoken.ServiceAccountInput{
+		Name:        req.Name,
+		Description: req.Description,
+		Active:      lo.FromPtrOr(req.Active, true),
+	}
+}
@@ -3,6 +3,7 @@ package auth
 import (
 	"encoding/json"
 	"errors"
+	"net"
 	"net/http"
 
 	"github.com/SURF-Innovatie/MORIS/internal/api/dto"
@@ -11,7 +12,9 @@ import (
 	surfconextapp "github.com/SURF-Innovatie/MORIS/internal/app/surfconext"
 	"github.com/SURF-Innovatie/MORIS/internal/app/user"
 	"github.com/SURF-Innovatie/MORIS/internal/common/transform"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/locale"
 	"github.com/SURF-Innovatie/MORIS/internal/infra/httputil"
+	"github.com/samber/lo"
 )
 
 type Handler struct {
@@ -32,7 +35,7 @@ func NewHandler(userService user.Service, authService coreauth.Service, orcidSer
 
 // Login godoc
 // @Summary Login user
-// @Description Authenticates a user and returns a JWT token
+// @Description Authenticates a user and starts a session. Returns a short-lived access token and a refresh token.
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -47,16 +50,132 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, authUser, err := h.authService.Login(r.Context(), req.Email, req.Password)
+	tokens, authUser, err := h.authService.Login(r.Context(), req.Email, req.Password, clientInfo(r))
 	if err != nil {
 		_ = httputil.WriteError(w, r, http.StatusUnauthorized, "Invalid credentials", nil)
 		return
 	}
 
-	resp := dto.FromEntity(token, authUser)
+	resp := dto.FromEntity(tokens, authUser)
 	_ = httputil.WriteJSON(w, http.StatusOK, resp)
 }
 
+// Refresh godoc
+// @Summary Refresh tokens
+// @Description Exchanges a refresh token for a new access token and refresh token. Each refresh token can be used once; presenting a used one again revokes its session.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.RefreshRequest true "Refresh token"
+// @Success 200 {object} dto.LoginResponse
+// @Failure 400 {object} httputil.BackendError "Invalid request body"
+// @Failure 401 {object} httputil.BackendError "Invalid or expired refresh token"
+// @Router /auth/refresh [post]
+func (h *Handler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req dto.RefreshRequest
+	if !httputil.ReadJSON(w, r, &req) {
+		return
+	}
+
+	tokens, authUser, err := h.authService.Refresh(r.Context(), req.RefreshToken)
+	if err != nil {
+		_ = httputil.WriteError(w, r, http.StatusUnauthorized, "Invalid or expired refresh token", nil)
+		return
+	}
+
+	_ = httputil.WriteJSON(w, http.StatusOK, dto.FromEntity(tokens, authUser))
+}
+
+// Logout godoc
+// @Summary Logout
+// @Description Revokes the session of the access token, together with its refresh token
+// @Tags auth
+// @Security BearerAuth
+// @Success 204 "No Content"
+// @Failure 401 {object} httputil.BackendError "User not authenticated"
+// @Failure 500 {object} httputil.BackendError "Internal server error"
+// @Router /logout [post]
+func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
+	sessionID, ok := httputil.GetSessionIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteError(w, r, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	if err := h.authService.Logout(r.Context(), sessionID); err != nil {
+		httputil.WriteError(w, r, http.StatusInternalServerError, "Failed to log out", nil)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListSessions godoc
+// @Summary List sessions
+// @Description Lists the active sessions of the authenticated user, most recently used first
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} dto.SessionResponse
+// @Failure 401 {object} httputil.BackendError "User not authenticated"
+// @Failure 500 {object} httputil.BackendError "Internal server error"
+// @Router /sessions [get]
+func (h *Handler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	u, ok := httputil.GetUserFromContext(r.Context())
+	if !ok || u == nil {
+		httputil.WriteError(w, r, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	current, _ := httputil.GetSessionIDFromContext(r.Context())
+
+	sessions, err := h.authService.ListSessions(r.Context(), u.User.ID)
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusInternalServerError, "Failed to list sessions", nil)
+		return
+	}
+
+	resp := transform.ToDTOs[dto.SessionResponse](sessions)
+	for i := range resp {
+		resp[i].Current = resp[i].ID == current
+	}
+	_ = httputil.WriteJSON(w, http.StatusOK, resp)
+}
+
+// RevokeSession godoc
+// @Summary Revoke a session
+// @Description Signs out one of the authenticated user's sessions. Its access and refresh tokens stop working immediately.
+// @Tags auth
+// @Security BearerAuth
+// @Param id path string true "Session ID (UUID)"
+// @Success 204 "No Content"
+// @Failure 400 {object} httputil.BackendError "Invalid session id"
+// @Failure 401 {object} httputil.BackendError "User not authenticated"
+// @Failure 403 {object} httputil.BackendError "Not allowed while impersonating"
+// @Failure 404 {object} httputil.BackendError "Session not found"
+// @Failure 500 {object} httputil.BackendError "Internal server error"
+// @Router /sessions/{id} [delete]
+func (h *Handler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	u, ok := httputil.GetUserFromContext(r.Context())
+	if !ok || u == nil {
+		httputil.WriteError(w, r, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	id, err := httputil.ParseUUIDParam(r, "id")
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusBadRequest, "invalid session id", nil)
+		return
+	}
+
+	if err := h.authService.RevokeSession(r.Context(), u.User.ID, id); err != nil {
+		if errors.Is(err, coreauth.ErrSessionNotFound) {
+			httputil.WriteError(w, r, http.StatusNotFound, "session not found", nil)
+			return
+		}
+		httputil.WriteError(w, r, http.StatusInternalServerError, "Failed to revoke session", nil)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // GetSurfconextAuthURL godoc
 // @Summary Get SURFconext authorization URL
 // @Description Returns the URL to redirect the user to for SURFconext (OIDC) login
@@ -79,7 +198,7 @@ func (h *Handler) GetSurfconextAuthURL(w http.ResponseWriter, r *http.Request) {
 
 // LoginWithSurfconext godoc
 // @Summary Login with SURFconext
-// @Description Exchanges an OIDC authorization code for a MORIS JWT token
+// @Description Exchanges an OIDC authorization code for a MORIS access token and refresh token. The organisation memberships granted by provisioning rules are created, reactivated or revoked according to the schacHomeOrganization, eduPersonAffiliation and eduPersonEntitlement claims.
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -95,7 +214,7 @@ func (h *Handler) LoginWithSurfconext(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, authUser, err := h.surfService.LoginWithCode(r.Context(), req.Code)
+	tokens, authUser, err := h.surfService.LoginWithCode(r.Context(), req.Code, clientInfo(r))
 	if err != nil {
 		statusCode := http.StatusInternalServerError
 		switch {
@@ -103,6 +222,8 @@ func (h *Handler) LoginWithSurfconext(w http.ResponseWriter, r *http.Request) {
 			statusCode = http.StatusBadRequest
 		case errors.Is(err, surfconextapp.ErrNoEmail):
 			statusCode = http.StatusUnauthorized
+		case errors.Is(err, surfconextapp.ErrProvisioning):
+			statusCode = http.StatusInternalServerError
 		default:
 			// If the wrapped error contains "invalid credentials" from LoginByEmail
 			statusCode = http.StatusUnauthorized
@@ -112,18 +233,18 @@ func (h *Handler) LoginWithSurfconext(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp := dto.FromEntity(token, authUser)
+	resp := dto.FromEntity(tokens, authUser)
 	_ = httputil.WriteJSON(w, http.StatusOK, resp)
 }
 
 // Profile godoc
 // @Summary Get user profile
-// @Description Returns the authenticated user's profile information
+// @Description Returns the authenticated user's profile information. When a sysadmin is impersonating the user, is_impersonated is set and impersonation names the sysadmin.
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {object} dto.UserResponse
+// @Success 200 {object} dto.ProfileResponse
 // @Failure 401 {object} httputil.BackendError "User not authenticated"
 // @Router /profile [get]
 func (h *Handler) Profile(w http.ResponseWriter, r *http.Request) {
@@ -140,12 +261,60 @@ func (h *Handler) Profile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	dtoResp := transform.ToDTOItem[dto.UserResponse](freshUser)
+	dtoResp := dto.ProfileResponse{UserResponse: transform.ToDTOItem[dto.UserResponse](freshUser)}
+	if imp, ok := httputil.GetImpersonationFromContext(r.Context()); ok {
+		dtoResp.IsImpersonated = true
+		dtoResp.Impersonation = lo.ToPtr(transform.ToDTOItem[dto.ProfileImpersonationResponse](*imp))
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(dtoResp)
 }
 
+// UpdateLanguage godoc
+// @Summary Set preferred language
+// @Description Sets the language of the authenticated user's notifications and emails ("en" or "nl")
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.UserLanguageRequest true "Preferred language"
+// @Success 200 {object} dto.UserResponse
+// @Failure 400 {object} httputil.BackendError "Unsupported language"
+// @Failure 401 {object} httputil.BackendError "User not authenticated"
+// @Failure 403 {object} httputil.BackendError "Not allowed while impersonating"
+// @Failure 500 {object} httputil.BackendError "Internal server error"
+// @Router /profile/language [put]
+func (h *Handler) UpdateLanguage(w http.ResponseWriter, r *http.Request) {
+	userCtx, ok := httputil.GetUserFromContext(r.Context())
+	if !ok || userCtx == nil {
+		httputil.WriteError(w, r, http.StatusUnauthorized, "User not found in context", nil)
+		return
+	}
+
+	var req dto.UserLanguageRequest
+	if !httputil.ReadJSON(w, r, &req) {
+		return
+	}
+
+	lang, err := locale.Parse(req.Language)
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusBadRequest, "unsupported language", nil)
+		return
+	}
+	if err := h.userService.SetLanguage(r.Context(), userCtx.User.ID, lang); err != nil {
+		httputil.WriteError(w, r, http.StatusInternalServerError, "Failed to update language", nil)
+		return
+	}
+
+	freshUser, err := h.userService.GetAccount(r.Context(), userCtx.User.ID)
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusInternalServerError, "Failed to fetch user profile", nil)
+		return
+	}
+	_ = httputil.WriteJSON(w, http.StatusOK, transform.ToDTOItem[dto.UserResponse](freshUser))
+}
+
 // GetORCIDAuthURL godoc
 // @Summary Get ORCID authorization URL
 // @Description Returns the URL to redirect the user to for ORCID authentication
@@ -191,6 +360,7 @@ func (h *Handler) GetORCIDAuthURL(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} httputil.StatusResponse
 // @Failure 400 {object} httputil.BackendError "Invalid request"
 // @Failure 401 {object} httputil.BackendError "User not authenticated"
+// @Failure 403 {object} httputil.BackendError "Not allowed while impersonating"
 // @Failure 409 {object} httputil.BackendError "ORCID ID already linked"
 // @Failure 500 {object} httputil.BackendError "Internal server error"
 // @Router /auth/orcid/link [post]
@@ -234,6 +404,7 @@ func (h *Handler) LinkORCID(w http.ResponseWriter, r *http.Request) {
 // @Security BearerAuth
 // @Success 200 {object} httputil.StatusResponse
 // @Failure 401 {object} httputil.BackendError "User not authenticated"
+// @Failure 403 {object} httputil.BackendError "Not allowed while impersonating"
 // @Failure 500 {object} httputil.BackendError "Internal server error"
 // @Router /auth/orcid/unlink [post]
 func (h *Handler) UnlinkORCID(w http.ResponseWriter, r *http.Request) {
@@ -251,3 +422,12 @@ func (h *Handler) UnlinkORCID(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	_ = httputil.WriteStatus(w)
 }
+
+// clientInfo describes the device of the request for its session
+func clientInfo(r *http.Request) coreauth.ClientInfo {
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	return coreauth.ClientInfo{UserAgent: r.UserAgent(), IPAddress: ip}
+}
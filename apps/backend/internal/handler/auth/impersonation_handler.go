@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/SURF-Innovatie/MORIS/ent"
+	"github.com/SURF-Innovatie/MORIS/internal/api/dto"
+	coreauth "github.com/SURF-Innovatie/MORIS/internal/app/auth"
+	"github.com/SURF-Innovatie/MORIS/internal/common/transform"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/httputil"
+)
+
+// Impersonate godoc
+// @Summary Impersonate a user
+// @Description Starts a time-limited session in which the sysadmin acts as another user, to see what they see. The tokens act as the user; the user's profile is flagged as impersonated and events record the sysadmin. The session and its reason are kept for audit. Sysadmins, service accounts and inactive users cannot be impersonated. Sysadmin only.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.ImpersonationRequest true "User to impersonate and why"
+// @Success 201 {object} dto.LoginResponse
+// @Failure 400 {object} httputil.BackendError "Reason required"
+// @Failure 401 {object} httputil.BackendError "User not authenticated"
+// @Failure 403 {object} httputil.BackendError "User cannot be impersonated"
+// @Failure 404 {object} httputil.BackendError "User not found"
+// @Failure 500 {object} httputil.BackendError "Internal server error"
+// @Router /admin/impersonations [post]
+func (h *Handler) Impersonate(w http.ResponseWriter, r *http.Request) {
+	u, ok := httputil.GetUserFromContext(r.Context())
+	if !ok || u == nil {
+		httputil.WriteError(w, r, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	var req dto.ImpersonationRequest
+	if !httputil.ReadJSON(w, r, &req) {
+		return
+	}
+
+	tokens, target, err := h.authService.Impersonate(r.Context(), u.User.ID, req.UserID, req.Reason, clientInfo(r))
+	switch {
+	case err == nil:
+		_ = httputil.WriteJSON(w, http.StatusCreated, dto.FromEntity(tokens, target))
+	case errors.Is(err, coreauth.ErrReasonRequired):
+		httputil.WriteError(w, r, http.StatusBadRequest, err.Error(), nil)
+	case errors.Is(err, coreauth.ErrCannotImpersonate):
+		httputil.WriteError(w, r, http.StatusForbidden, err.Error(), nil)
+	case ent.IsNotFound(err):
+		httputil.WriteError(w, r, http.StatusNotFound, "user not found", nil)
+	default:
+		httputil.WriteError(w, r, http.StatusInternalServerError, "Failed to start impersonation", nil)
+	}
+}
+
+// ListImpersonations godoc
+// @Summary List impersonation sessions
+// @Description Lists every impersonation session, ended ones included, newest first. Sysadmin only.
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} dto.ImpersonationResponse
+// @Failure 401 {object} httputil.BackendError "User not authenticated"
+// @Failure 403 {object} httputil.BackendError "Forbidden"
+// @Failure 500 {object} httputil.BackendError "Internal server error"
+// @Router /admin/impersonations [get]
+func (h *Handler) ListImpersonations(w http.ResponseWriter, r *http.Request) {
+	sessions, err := h.authService.ListImpersonations(r.Context())
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusInternalServerError, "Failed to list impersonations", nil)
+		return
+	}
+	_ = httputil.WriteJSON(w, http.StatusOK, transform.ToDTOs[dto.ImpersonationResponse](sessions))
+}
+
+// EndImpersonation godoc
+// @Summary End an impersonation session
+// @Description Ends an impersonation session before its time limit. The impersonating sysadmin can also end it with /logout. Sysadmin only.
+// @Tags auth
+// @Security BearerAuth
+// @Param id path string true "Session ID (UUID)"
+// @Success 204 "No Content"
+// @Failure 400 {object} httputil.BackendError "Invalid session id"
+// @Failure 401 {object} httputil.BackendError "User not authenticated"
+// @Failure 403 {object} httputil.BackendError "Forbidden"
+// @Failure 404 {object} httputil.BackendError "Impersonation not found"
+// @Failure 500 {object} httputil.BackendError "Internal server error"
+// @Router /admin/impersonations/{id} [delete]
+func (h *Handler) EndImpersonation(w http.ResponseWriter, r *http.Request) {
+	id, err := httputil.ParseUUIDParam(r, "id")
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusBadRequest, "invalid session id", nil)
+		return
+	}
+
+	if err := h.authService.EndImpersonation(r.Context(), id); err != nil {
+		if errors.Is(err, coreauth.ErrSessionNotFound) {
+			httputil.WriteError(w, r, http.StatusNotFound, "impersonation not found", nil)
+			return
+		}
+		httputil.WriteError(w, r, http.StatusInternalServerError, "Failed to end impersonation", nil)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -10,6 +10,7 @@ import (
 func MountRoutes(r chi.Router, authSvc coreauth.Service, h *Handler) {
 
 	r.Post("/login", h.Login)
+	r.Post("/auth/refresh", h.Refresh)
 
 	// SURFconext (OIDC) login
 	r.Get("/auth/surfconext/url", h.GetSurfconextAuthURL)
@@ -20,9 +21,28 @@ func MountRoutes(r chi.Router, authSvc coreauth.Service, h *Handler) {
 
 		r.Get("/profile", h.Profile)
 
+		// Sessions
+		r.Post("/logout", h.Logout)
+		r.Get("/sessions", h.ListSessions)
+
+		// Impersonation
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequireSysAdminMiddleware())
+			r.Post("/admin/impersonations", h.Impersonate)
+			r.Get("/admin/impersonations", h.ListImpersonations)
+			r.Delete("/admin/impersonations/{id}", h.EndImpersonation)
+		})
+
 		// ORCID
 		r.Get("/auth/orcid/url", h.GetORCIDAuthURL)
-		r.Post("/auth/orcid/link", h.LinkORCID)
-		r.Post("/auth/orcid/unlink", h.UnlinkORCID)
+
+		// Changes to the user's own account cannot be made by an impersonating sysadmin
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RejectImpersonationMiddleware())
+			r.Put("/profile/language", h.UpdateLanguage)
+			r.Delete("/sessions/{id}", h.RevokeSession)
+			r.Post("/auth/orcid/link", h.LinkORCID)
+			r.Post("/auth/orcid/unlink", h.UnlinkORCID)
+		})
 	})
 }
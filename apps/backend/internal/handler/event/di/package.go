@@ -3,6 +3,7 @@ package di
 import (
 	"github.com/SURF-Innovatie/MORIS/ent"
 	event2 "github.com/SURF-Innovatie/MORIS/internal/app/event"
+	"github.com/SURF-Innovatie/MORIS/internal/app/eventpolicy"
 	"github.com/SURF-Innovatie/MORIS/internal/app/project/queries"
 	"github.com/SURF-Innovatie/MORIS/internal/app/user"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/project/events/hydrator"
@@ -20,5 +21,6 @@ func provideEventHandler(i do.Injector) (*eventhandler.Handler, error) {
 	userSvc := do.MustInvoke[user.Service](i)
 	cli := do.MustInvoke[*ent.Client](i)
 	h := do.MustInvoke[*hydrator.Hydrator](i)
-	return eventhandler.NewHandler(evtSvc, projSvc, userSvc, cli, h), nil
+	policySvc := do.MustInvoke[eventpolicy.Service](i)
+	return eventhandler.NewHandler(evtSvc, projSvc, userSvc, cli, h, policySvc), nil
 }
@@ -1,13 +1,17 @@
 package event
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/SURF-Innovatie/MORIS/ent"
 	"github.com/SURF-Innovatie/MORIS/internal/api/dto"
 	"github.com/SURF-Innovatie/MORIS/internal/app/event"
+	"github.com/SURF-Innovatie/MORIS/internal/app/eventpolicy"
 	"github.com/SURF-Innovatie/MORIS/internal/app/project/queries"
 	"github.com/SURF-Innovatie/MORIS/internal/app/user"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation/rbac"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/policy"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/project/events"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/project/events/hydrator"
 	"github.com/SURF-Innovatie/MORIS/internal/infra/httputil"
@@ -20,15 +24,16 @@ type Handler struct {
 	userSvc  user.Service
 	cli      *ent.Client
 	hydrator *hydrator.Hydrator
+	policies eventpolicy.Service
 }
 
-func NewHandler(svc event.Service, querySvc queries.Service, userSvc user.Service, cli *ent.Client, h *hydrator.Hydrator) *Handler {
-	return &Handler{svc: svc, querySvc: querySvc, userSvc: userSvc, cli: cli, hydrator: h}
+func NewHandler(svc event.Service, querySvc queries.Service, userSvc user.Service, cli *ent.Client, h *hydrator.Hydrator, policies eventpolicy.Service) *Handler {
+	return &Handler{svc: svc, querySvc: querySvc, userSvc: userSvc, cli: cli, hydrator: h, policies: policies}
 }
 
 // ApproveEvent godoc
 // @Summary Approve an event
-// @Description Approves a pending event
+// @Description Approves a pending event. Requires the approve_events permission on the project's organisation node.
 // @Tags events
 // @Accept json
 // @Produce json
@@ -36,6 +41,7 @@ func NewHandler(svc event.Service, querySvc queries.Service, userSvc user.Servic
 // @Param id path string true "Event ID (UUID)"
 // @Success 200 {object} map[string]string
 // @Failure 400 {string} string "invalid event id"
+// @Failure 403 {string} string "forbidden"
 // @Failure 500 {string} string "internal server error"
 // @Router /events/{id}/approve [post]
 func (h *Handler) ApproveEvent(w http.ResponseWriter, r *http.Request) {
@@ -47,7 +53,7 @@ func (h *Handler) ApproveEvent(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.svc.ApproveEvent(ctx, id); err != nil {
-		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+		writeDecisionError(w, r, err)
 		return
 	}
 
@@ -56,7 +62,7 @@ func (h *Handler) ApproveEvent(w http.ResponseWriter, r *http.Request) {
 
 // RejectEvent godoc
 // @Summary Reject an event
-// @Description Rejects a pending event
+// @Description Rejects a pending event. Requires the approve_events permission on the project's organisation node.
 // @Tags events
 // @Accept json
 // @Produce json
@@ -64,6 +70,7 @@ func (h *Handler) ApproveEvent(w http.ResponseWriter, r *http.Request) {
 // @Param id path string true "Event ID (UUID)"
 // @Success 200 {object} map[string]string
 // @Failure 400 {string} string "invalid event id"
+// @Failure 403 {string} string "forbidden"
 // @Failure 500 {string} string "internal server error"
 // @Router /events/{id}/reject [post]
 func (h *Handler) RejectEvent(w http.ResponseWriter, r *http.Request) {
@@ -75,7 +82,7 @@ func (h *Handler) RejectEvent(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.svc.RejectEvent(ctx, id); err != nil {
-		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+		writeDecisionError(w, r, err)
 		return
 	}
 
@@ -92,6 +99,7 @@ func (h *Handler) RejectEvent(w http.ResponseWriter, r *http.Request) {
 // @Param id path string true "Event ID (UUID)"
 // @Success 200 {object} dto.Event
 // @Failure 400 {string} string "invalid event id"
+// @Failure 403 {string} string "forbidden"
 // @Failure 404 {string} string "event not found"
 // @Failure 500 {string} string "internal server error"
 // @Router /events/{id} [get]
@@ -107,12 +115,27 @@ func (h *Handler) GetEvent(w http.ResponseWriter, r *http.Request) {
 		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
 		return
 	}
+	if err := h.querySvc.AuthorizeView(r.Context(), e.AggregateID()); err != nil {
+		writeDecisionError(w, r, err)
+		return
+	}
 
 	detailed := h.hydrator.HydrateOne(r.Context(), e)
 
 	var d dto.Event
 	dtoEvent := d.FromDetailedEntity(detailed)
 
+	executions, err := h.policies.ListExecutionsForEvent(r.Context(), id)
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+	dtoEvent.PolicyExecutions = lo.Map(executions, func(x policy.Execution, _ int) dto.PolicyExecutionResponse {
+		var r dto.PolicyExecutionResponse
+		r.FromEntity(&x)
+		return r
+	})
+
 	_ = httputil.WriteJSON(w, http.StatusOK, dtoEvent)
 }
 
@@ -150,3 +173,12 @@ func (h *Handler) ListEventTypes(w http.ResponseWriter, r *http.Request) {
 
 	_ = httputil.WriteJSON(w, http.StatusOK, eventTypes)
 }
+
+// writeDecisionError maps authorization failures to 403 and other errors to 500
+func writeDecisionError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, rbac.ErrForbidden) {
+		httputil.WriteError(w, r, http.StatusForbidden, err.Error(), nil)
+		return
+	}
+	httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+}
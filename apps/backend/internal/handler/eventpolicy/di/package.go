@@ -2,6 +2,9 @@ package di
 
 import (
 	"github.com/SURF-Innovatie/MORIS/internal/app/eventpolicy"
+	rbacsvc "github.com/SURF-Innovatie/MORIS/internal/app/organisation/rbac"
+	"github.com/SURF-Innovatie/MORIS/internal/app/project/queries"
+	"github.com/SURF-Innovatie/MORIS/internal/app/webhook"
 	eventpolicyhandler "github.com/SURF-Innovatie/MORIS/internal/handler/eventpolicy"
 	"github.com/samber/do/v2"
 )
@@ -12,5 +15,9 @@ var Package = do.Package(
 
 func provideEventPolicyHandler(i do.Injector) (*eventpolicyhandler.Handler, error) {
 	svc := do.MustInvoke[eventpolicy.Service](i)
-	return eventpolicyhandler.NewHandler(svc), nil
+	simulator := do.MustInvoke[eventpolicy.Simulator](i)
+	webhookSvc := do.MustInvoke[webhook.Service](i)
+	rbac := do.MustInvoke[rbacsvc.Service](i)
+	querySvc := do.MustInvoke[queries.Service](i)
+	return eventpolicyhandler.NewHandler(svc, simulator, webhookSvc, rbac, querySvc), nil
 }
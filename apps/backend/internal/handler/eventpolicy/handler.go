@@ -1,11 +1,18 @@
 package eventpolicy
 
 import (
+	"errors"
 	"net/http"
 
+	"github.com/SURF-Innovatie/MORIS/ent"
 	"github.com/SURF-Innovatie/MORIS/internal/api/dto"
 	"github.com/SURF-Innovatie/MORIS/internal/app/eventpolicy"
+	rbacsvc "github.com/SURF-Innovatie/MORIS/internal/app/organisation/rbac"
+	"github.com/SURF-Innovatie/MORIS/internal/app/project/queries"
+	"github.com/SURF-Innovatie/MORIS/internal/app/webhook"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation/rbac"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/policy"
+	webhookdomain "github.com/SURF-Innovatie/MORIS/internal/domain/webhook"
 	"github.com/SURF-Innovatie/MORIS/internal/infra/httputil"
 	"github.com/google/uuid"
 	"github.com/samber/lo"
@@ -13,12 +20,16 @@ import (
 
 // Handler handles HTTP requests for event policies
 type Handler struct {
-	svc eventpolicy.Service
+	svc        eventpolicy.Service
+	simulator  eventpolicy.Simulator
+	webhookSvc webhook.Service
+	rbac       rbacsvc.Service
+	querySvc   queries.Service
 }
 
 // NewHandler creates a new event policy handler
-func NewHandler(svc eventpolicy.Service) *Handler {
-	return &Handler{svc: svc}
+func NewHandler(svc eventpolicy.Service, simulator eventpolicy.Simulator, webhookSvc webhook.Service, rbac rbacsvc.Service, querySvc queries.Service) *Handler {
+	return &Handler{svc: svc, simulator: simulator, webhookSvc: webhookSvc, rbac: rbac, querySvc: querySvc}
 }
 
 // ListForOrgNode godoc
@@ -69,6 +80,8 @@ func (h *Handler) ListForOrgNode(w http.ResponseWriter, r *http.Request) {
 // @Param body body dto.EventPolicyRequest true "Policy details"
 // @Success 201 {object} dto.EventPolicyResponse
 // @Failure 400 {string} string "invalid request"
+// @Failure 403 {string} string "forbidden"
+// @Failure 409 {string} string "policy shadows a locked policy"
 // @Failure 500 {string} string "internal server error"
 // @Router /organisations/{id}/policies [post]
 func (h *Handler) CreateForOrgNode(w http.ResponseWriter, r *http.Request) {
@@ -90,9 +103,13 @@ func (h *Handler) CreateForOrgNode(w http.ResponseWriter, r *http.Request) {
 	}
 	policy.OrgNodeID = &orgNodeID
 
+	if !h.requirePolicyManager(w, r, &policy) {
+		return
+	}
+
 	created, err := h.svc.Create(r.Context(), policy)
 	if err != nil {
-		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+		writePolicyError(w, r, err)
 		return
 	}
 
@@ -157,6 +174,7 @@ func (h *Handler) ListForProject(w http.ResponseWriter, r *http.Request) {
 // @Param body body dto.EventPolicyRequest true "Policy details"
 // @Success 201 {object} dto.EventPolicyResponse
 // @Failure 400 {string} string "invalid request"
+// @Failure 403 {string} string "forbidden"
 // @Failure 500 {string} string "internal server error"
 // @Router /projects/{id}/policies [post]
 func (h *Handler) CreateForProject(w http.ResponseWriter, r *http.Request) {
@@ -178,9 +196,13 @@ func (h *Handler) CreateForProject(w http.ResponseWriter, r *http.Request) {
 	}
 	policy.ProjectID = &projectID
 
+	if !h.requirePolicyManager(w, r, &policy) {
+		return
+	}
+
 	created, err := h.svc.Create(r.Context(), policy)
 	if err != nil {
-		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+		writePolicyError(w, r, err)
 		return
 	}
 
@@ -230,7 +252,9 @@ func (h *Handler) GetPolicy(w http.ResponseWriter, r *http.Request) {
 // @Param body body dto.EventPolicyRequest true "Updated policy details"
 // @Success 200 {object} dto.EventPolicyResponse
 // @Failure 400 {string} string "invalid request"
+// @Failure 403 {string} string "forbidden"
 // @Failure 404 {string} string "policy not found"
+// @Failure 409 {string} string "policy shadows a locked policy"
 // @Router /policies/{id} [put]
 func (h *Handler) UpdatePolicy(w http.ResponseWriter, r *http.Request) {
 	id, err := httputil.ParseUUIDParam(r, "id")
@@ -250,9 +274,18 @@ func (h *Handler) UpdatePolicy(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	existing, err := h.svc.GetByID(r.Context(), id)
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusNotFound, "policy not found", nil)
+		return
+	}
+	if !h.requirePolicyManager(w, r, existing) {
+		return
+	}
+
 	updated, err := h.svc.Update(r.Context(), id, policy)
 	if err != nil {
-		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+		writePolicyError(w, r, err)
 		return
 	}
 
@@ -271,6 +304,8 @@ func (h *Handler) UpdatePolicy(w http.ResponseWriter, r *http.Request) {
 // @Param id path string true "Policy ID (UUID)"
 // @Success 204 "No Content"
 // @Failure 400 {string} string "invalid policy id"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "policy not found"
 // @Failure 500 {string} string "internal server error"
 // @Router /policies/{id} [delete]
 func (h *Handler) DeletePolicy(w http.ResponseWriter, r *http.Request) {
@@ -280,6 +315,15 @@ func (h *Handler) DeletePolicy(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	existing, err := h.svc.GetByID(r.Context(), id)
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusNotFound, "policy not found", nil)
+		return
+	}
+	if !h.requirePolicyManager(w, r, existing) {
+		return
+	}
+
 	if err := h.svc.Delete(r.Context(), id); err != nil {
 		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
 		return
@@ -288,24 +332,379 @@ func (h *Handler) DeletePolicy(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// SimulatePolicy godoc
+// @Summary Dry-run a draft event policy
+// @Description Evaluates an unsaved policy against a hypothetical event or the historic events in a date range, without sending notifications or requiring approvals
+// @Tags event-policies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body dto.PolicySimulationRequest true "Draft policy and simulation input"
+// @Success 200 {object} dto.PolicySimulationResponse
+// @Failure 400 {string} string "invalid request"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "project not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /policies/simulate [post]
+func (h *Handler) SimulatePolicy(w http.ResponseWriter, r *http.Request) {
+	var req dto.PolicySimulationRequest
+	if !httputil.ReadJSON(w, r, &req) {
+		return
+	}
+
+	draft, err := h.requestToEntity(req.Policy)
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	draft.OrgNodeID = req.OrgNodeID
+	draft.ProjectID = req.ProjectID
+
+	if !h.requirePolicyManager(w, r, &draft) {
+		return
+	}
+
+	simReq := eventpolicy.SimulationRequest{
+		Policy: draft,
+		From:   req.From,
+		To:     req.To,
+	}
+	if userID := httputil.GetUserIDFromContext(r.Context()); userID != nil {
+		simReq.Actor = *userID
+	}
+	if req.Event != nil {
+		simReq.Event = &eventpolicy.HypotheticalEvent{
+			ProjectID: req.Event.ProjectID,
+			Type:      req.Event.Type,
+			Input:     req.Event.Input,
+		}
+	}
+
+	result, err := h.simulator.Simulate(r.Context(), simReq)
+	if err != nil {
+		switch {
+		case errors.Is(err, eventpolicy.ErrInvalidSimulation):
+			httputil.WriteError(w, r, http.StatusBadRequest, err.Error(), nil)
+		case errors.Is(err, eventpolicy.ErrProjectNotFound):
+			httputil.WriteError(w, r, http.StatusNotFound, "project not found", nil)
+		default:
+			httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+		}
+		return
+	}
+
+	var resp dto.PolicySimulationResponse
+	resp.FromEntity(result)
+	_ = httputil.WriteJSON(w, http.StatusOK, resp)
+}
+
+// ListWebhookDeliveries godoc
+// @Summary List webhook deliveries of a policy
+// @Description Retrieves the delivery log of a webhook policy's endpoint, newest first
+// @Tags event-policies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Policy ID (UUID)"
+// @Param status query string false "Filter by status (pending, succeeded, failed)"
+// @Success 200 {array} dto.WebhookDeliveryResponse
+// @Failure 400 {string} string "invalid policy id"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "policy not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /policies/{id}/webhook-deliveries [get]
+func (h *Handler) ListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.loadManagedPolicyID(w, r)
+	if !ok {
+		return
+	}
+
+	var status *webhookdomain.DeliveryStatus
+	if s := r.URL.Query().Get("status"); s != "" {
+		st := webhookdomain.DeliveryStatus(s)
+		switch st {
+		case webhookdomain.DeliveryPending, webhookdomain.DeliverySucceeded, webhookdomain.DeliveryFailed:
+			status = &st
+		default:
+			httputil.WriteError(w, r, http.StatusBadRequest, "invalid status", nil)
+			return
+		}
+	}
+
+	deliveries, err := h.webhookSvc.ListForPolicy(r.Context(), id, status)
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	resp := lo.Map(deliveries, func(d webhookdomain.Delivery, _ int) dto.WebhookDeliveryResponse {
+		var r dto.WebhookDeliveryResponse
+		r.FromEntity(&d)
+		return r
+	})
+
+	_ = httputil.WriteJSON(w, http.StatusOK, resp)
+}
+
+// ReplayFailedWebhookDeliveries godoc
+// @Summary Replay failed webhook deliveries of a policy
+// @Description Re-queues all failed deliveries of a webhook policy for immediate delivery
+// @Tags event-policies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Policy ID (UUID)"
+// @Success 200 {object} dto.WebhookReplayResponse
+// @Failure 400 {string} string "invalid policy id"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "policy not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /policies/{id}/webhook-deliveries/replay [post]
+func (h *Handler) ReplayFailedWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.loadManagedPolicyID(w, r)
+	if !ok {
+		return
+	}
+
+	n, err := h.webhookSvc.ReplayFailedForPolicy(r.Context(), id)
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	_ = httputil.WriteJSON(w, http.StatusOK, dto.WebhookReplayResponse{Requeued: n})
+}
+
+// ReplayWebhookDelivery godoc
+// @Summary Replay a failed webhook delivery
+// @Description Re-queues a single failed delivery for immediate delivery
+// @Tags event-policies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Delivery ID (UUID)"
+// @Success 200 {object} dto.WebhookDeliveryResponse
+// @Failure 400 {string} string "invalid delivery id"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "delivery not found"
+// @Failure 409 {string} string "delivery is not failed"
+// @Router /webhook-deliveries/{id}/replay [post]
+func (h *Handler) ReplayWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	id, err := httputil.ParseUUIDParam(r, "id")
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusBadRequest, "invalid delivery id", nil)
+		return
+	}
+
+	existing, err := h.webhookSvc.Get(r.Context(), id)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			httputil.WriteError(w, r, http.StatusNotFound, "delivery not found", nil)
+			return
+		}
+		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+	// Deliveries of subscriptions are replayed through the subscription
+	if existing.PolicyID == nil {
+		httputil.WriteError(w, r, http.StatusForbidden, "forbidden: delivery does not belong to a policy", nil)
+		return
+	}
+	p, err := h.svc.GetByID(r.Context(), *existing.PolicyID)
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusNotFound, "policy not found", nil)
+		return
+	}
+	if !h.requirePolicyManager(w, r, p) {
+		return
+	}
+
+	d, err := h.webhookSvc.Replay(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, webhook.ErrNotReplayable):
+			httputil.WriteError(w, r, http.StatusConflict, err.Error(), nil)
+		case ent.IsNotFound(err):
+			httputil.WriteError(w, r, http.StatusNotFound, "delivery not found", nil)
+		default:
+			httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+		}
+		return
+	}
+
+	var resp dto.WebhookDeliveryResponse
+	resp.FromEntity(d)
+	_ = httputil.WriteJSON(w, http.StatusOK, resp)
+}
+
+// ListExecutions godoc
+// @Summary List the execution history of a policy
+// @Description Retrieves the audit log of every time the policy matched, newest first. Requires the manage_policies permission on the organisation node that owns the policy.
+// @Tags event-policies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Policy ID (UUID)"
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Page size (default 20)"
+// @Success 200 {object} dto.PolicyExecutionPaginatedResponse
+// @Failure 400 {string} string "invalid policy id"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "policy not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /policies/{id}/executions [get]
+func (h *Handler) ListExecutions(w http.ResponseWriter, r *http.Request) {
+	id, err := httputil.ParseUUIDParam(r, "id")
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusBadRequest, "invalid policy id", nil)
+		return
+	}
+
+	p, err := h.svc.GetByID(r.Context(), id)
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusNotFound, "policy not found", nil)
+		return
+	}
+
+	if !h.requirePolicyManager(w, r, p) {
+		return
+	}
+
+	page := httputil.ParseIntQuery(r, "page", 1)
+	pageSize := httputil.ParseIntQuery(r, "page_size", 20)
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 20
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	executions, total, err := h.svc.ListExecutionsForPolicy(r.Context(), id, pageSize, (page-1)*pageSize)
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	resp := dto.PolicyExecutionPaginatedResponse{
+		Data: lo.Map(executions, func(x policy.Execution, _ int) dto.PolicyExecutionResponse {
+			var r dto.PolicyExecutionResponse
+			r.FromEntity(&x)
+			return r
+		}),
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: (total + pageSize - 1) / pageSize,
+	}
+
+	_ = httputil.WriteJSON(w, http.StatusOK, resp)
+}
+
+// loadManagedPolicyID reads the policy of the request path and checks that the current user manages it
+func (h *Handler) loadManagedPolicyID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := httputil.ParseUUIDParam(r, "id")
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusBadRequest, "invalid policy id", nil)
+		return uuid.Nil, false
+	}
+
+	p, err := h.svc.GetByID(r.Context(), id)
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusNotFound, "policy not found", nil)
+		return uuid.Nil, false
+	}
+	return id, h.requirePolicyManager(w, r, p)
+}
+
+// requirePolicyManager checks that the current user holds the manage_policies permission on the
+// organisation node owning the policy. For project policies this is the project's owning organisation node.
+func (h *Handler) requirePolicyManager(w http.ResponseWriter, r *http.Request, p *policy.EventPolicy) bool {
+	switch {
+	case p.OrgNodeID != nil:
+		return h.requireManagePolicies(w, r, *p.OrgNodeID)
+	case p.ProjectID != nil:
+		proj, err := h.querySvc.GetProject(r.Context(), *p.ProjectID)
+		if err != nil {
+			if errors.Is(err, rbac.ErrForbidden) {
+				httputil.WriteError(w, r, http.StatusForbidden, err.Error(), nil)
+				return false
+			}
+			httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+			return false
+		}
+		return h.requireManagePolicies(w, r, proj.Project.OwningOrgNodeID)
+	default:
+		httputil.WriteError(w, r, http.StatusForbidden, "forbidden: policy has no owner", nil)
+		return false
+	}
+}
+
+// requireManagePolicies checks that the current user holds the manage_policies permission on the organisation node.
+// Permissions are inherited downwards, so holders on descendant nodes do not pass.
+func (h *Handler) requireManagePolicies(w http.ResponseWriter, r *http.Request, nodeID uuid.UUID) bool {
+	user, ok := httputil.GetUserFromContext(r.Context())
+	if !ok {
+		httputil.WriteError(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return false
+	}
+	if user.User.IsSysAdmin {
+		return true
+	}
+
+	hasAccess, err := h.rbac.HasPermission(r.Context(), user.Person.ID, nodeID, rbac.PermissionManagePolicies)
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+		return false
+	}
+	if !hasAccess {
+		httputil.WriteError(w, r, http.StatusForbidden, "forbidden: manage_policies permission on the policy's organisation node required", nil)
+		return false
+	}
+	return true
+}
+
+// writePolicyError maps policy service errors to HTTP responses
+func writePolicyError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, policy.ErrInvalidPolicy) {
+		httputil.WriteError(w, r, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	if errors.Is(err, policy.ErrPolicyLocked) {
+		httputil.WriteError(w, r, http.StatusConflict, err.Error(), nil)
+		return
+	}
+	httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+}
+
 // requestToEntity converts request DTO to domain entity
 func (h *Handler) requestToEntity(req dto.EventPolicyRequest) (policy.EventPolicy, error) {
 	eventPolicy := policy.EventPolicy{
-		Name:             req.Name,
-		Description:      req.Description,
-		EventTypes:       req.EventTypes,
-		ActionType:       policy.ActionType(req.ActionType),
-		MessageTemplate:  req.MessageTemplate,
-		RecipientDynamic: req.RecipientDynamic,
-		Enabled:          req.Enabled,
+		Name:               req.Name,
+		Description:        req.Description,
+		TriggerType:        policy.TriggerType(req.TriggerType),
+		EventTypes:         req.EventTypes,
+		ScheduleAnchor:     req.ScheduleAnchor,
+		ScheduleOffsetDays: req.ScheduleOffsetDays,
+		ActionType:         policy.ActionType(req.ActionType),
+		MessageTemplate:    req.MessageTemplate,
+		WebhookURL:         req.WebhookURL,
+		WebhookSecret:      req.WebhookSecret,
+		RecipientDynamic:   req.RecipientDynamic,
+		Enabled:            req.Enabled,
+		Locked:             req.Locked,
 	}
 
 	// Convert conditions
 	for _, c := range req.Conditions {
 		eventPolicy.Conditions = append(eventPolicy.Conditions, policy.Condition{
-			Field:    c.Field,
-			Operator: c.Operator,
-			Value:    c.Value,
+			Field:      c.Field,
+			Operator:   c.Operator,
+			Value:      c.Value,
+			Expression: c.Expression,
 		})
 	}
 
@@ -8,10 +8,16 @@ import (
 func (h *Handler) RegisterRoutes(r chi.Router) {
 	// Policy CRUD routes
 	r.Route("/policies", func(r chi.Router) {
+		r.Post("/simulate", h.SimulatePolicy)
 		r.Get("/{id}", h.GetPolicy)
 		r.Put("/{id}", h.UpdatePolicy)
 		r.Delete("/{id}", h.DeletePolicy)
+		r.Get("/{id}/executions", h.ListExecutions)
+		r.Get("/{id}/webhook-deliveries", h.ListWebhookDeliveries)
+		r.Post("/{id}/webhook-deliveries/replay", h.ReplayFailedWebhookDeliveries)
 	})
+
+	r.Post("/webhook-deliveries/{id}/replay", h.ReplayWebhookDelivery)
 }
 
 // RegisterOrgRoutes registers org-scoped policy routes (to be mounted under /organisations)
@@ -9,7 +9,8 @@ import (
 	"github.com/SURF-Innovatie/MORIS/internal/infra/httputil"
 )
 
-// AuthMiddleware extracts and validates a JWT token from the Authorization header.
+// AuthMiddleware extracts and validates a JWT token or API token from the Authorization header.
+// API tokens may only make the requests their scopes allow.
 func AuthMiddleware(authSvc coreauth.Service) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -26,20 +27,38 @@ func AuthMiddleware(authSvc coreauth.Service) func(http.Handler) http.Handler {
 			}
 			token := parts[1]
 
-			user, err := authSvc.ValidateToken(token)
+			authn, err := authSvc.ValidateToken(r.Context(), token)
 			if err != nil {
 				httputil.WriteError(w, r, http.StatusUnauthorized, "Invalid or expired token", nil)
 				return
 			}
 
+			user := authn.Account
+
 			// Check if user is active
 			if !user.User.IsActive {
 				httputil.WriteError(w, r, http.StatusUnauthorized, "User account is inactive", nil)
 				return
 			}
 
+			if authn.Token != nil {
+				scope, ok := tokenScope(r)
+				if !ok || (scope != "" && !authn.Token.Token.HasScope(scope)) {
+					httputil.WriteError(w, r, http.StatusForbidden, "API token is not allowed to make this request", nil)
+					return
+				}
+			}
+
 			// Store the authenticated user in the request context
 			ctx := context.WithValue(r.Context(), httputil.ContextKeyUser, user)
+			if authn.Token != nil {
+				ctx = context.WithValue(ctx, httputil.ContextKeyAPIToken, authn.Token)
+			} else {
+				ctx = context.WithValue(ctx, httputil.ContextKeySession, authn.SessionID)
+			}
+			if authn.Impersonation != nil {
+				ctx = context.WithValue(ctx, httputil.ContextKeyImpersonation, authn.Impersonation)
+			}
 			r = r.WithContext(ctx)
 
 			next.ServeHTTP(w, r)
@@ -66,3 +85,18 @@ func RequireSysAdminMiddleware() func(next http.Handler) http.Handler {
 		})
 	}
 }
+
+// RejectImpersonationMiddleware refuses requests made while a sysadmin impersonates the user. It guards
+// changes to the user's own account, which the impersonator must not make on the user's behalf.
+func RejectImpersonationMiddleware() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := httputil.GetImpersonationFromContext(r.Context()); ok {
+				httputil.WriteError(w, r, http.StatusForbidden, "Forbidden: not allowed while impersonating a user", nil)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/SURF-Innovatie/MORIS/internal/domain/apitoken"
+	"github.com/go-chi/chi/v5"
+)
+
+// tokenRoute is a request API tokens may make. Pattern segments of "*" match any single segment;
+// an empty scope means any valid token may make the request.
+type tokenRoute struct {
+	method  string
+	pattern string
+	scope   apitoken.Scope
+}
+
+// tokenRoutes lists everything API tokens can do. Requests that are not listed, such as managing
+// tokens or organisations, need a signed-in user.
+var tokenRoutes = []tokenRoute{
+	{http.MethodGet, "/profile", ""},
+
+	{http.MethodGet, "/projects", apitoken.ScopeProjectsRead},
+	{http.MethodGet, "/projects/*", apitoken.ScopeProjectsRead},
+	{http.MethodGet, "/projects/*/*", apitoken.ScopeProjectsRead},
+
+	{http.MethodPost, "/projects/*/events", apitoken.ScopeEventsWrite},
+
+	{http.MethodGet, "/adapters", apitoken.ScopeExport},
+	{http.MethodPost, "/projects/*/export/*", apitoken.ScopeExport},
+}
+
+// tokenScope returns the scope an API token needs for the request, and false when API tokens may not make it
+func tokenScope(r *http.Request) (apitoken.Scope, bool) {
+	path := r.URL.Path
+	// Below the API base path chi routes on the remaining path
+	if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePath != "" {
+		path = rctx.RoutePath
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	for _, route := range tokenRoutes {
+		if route.method == r.Method && matchSegments(strings.Split(strings.Trim(route.pattern, "/"), "/"), segments) {
+			return route.scope, true
+		}
+	}
+	return "", false
+}
+
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) != len(segments) {
+		return false
+	}
+	for i, p := range pattern {
+		if p != "*" && p != segments[i] {
+			return false
+		}
+	}
+	return true
+}
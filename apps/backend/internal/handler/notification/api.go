@@ -4,7 +4,16 @@ import "github.com/go-chi/chi/v5"
 
 func MountNotificationRoutes(r chi.Router, h *Handler) {
 	r.Route("/notifications", func(r chi.Router) {
+		r.Get("/", h.List)
 		r.Get("/me", h.ListMe)
+		r.Get("/unread-count", h.UnreadCount)
+		r.Post("/read-all", h.MarkAllAsRead)
+		r.Post("/projects/{projectId}/read", h.MarkProjectAsRead)
+		r.Get("/preferences", h.GetPreferences)
+		r.Put("/preferences", h.UpdatePreferences)
 		r.Post("/{id}/read", h.MarkAsRead)
+		r.Post("/{id}/archive", h.Archive)
+		r.Post("/{id}/unarchive", h.Unarchive)
+		r.Delete("/{id}", h.Delete)
 	})
 }
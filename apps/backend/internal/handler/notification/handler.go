@@ -1,12 +1,18 @@
 package notification
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/SURF-Innovatie/MORIS/internal/api/dto"
 	appnotif "github.com/SURF-Innovatie/MORIS/internal/app/notification"
 	"github.com/SURF-Innovatie/MORIS/internal/common/transform"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/notification"
 	"github.com/SURF-Innovatie/MORIS/internal/infra/httputil"
+	"github.com/google/uuid"
 )
 
 type Handler struct {
@@ -19,6 +25,7 @@ func NewHandler(svc appnotif.Service) *Handler {
 
 // ListMe godoc
 // @Summary List notifications for current user
+// @Description Archived notifications are excluded. GET /notifications supports pagination and filters.
 // @Tags notifications
 // @Produce json
 // @Security BearerAuth
@@ -42,6 +49,124 @@ func (h *Handler) ListMe(w http.ResponseWriter, r *http.Request) {
 	_ = httputil.WriteJSON(w, http.StatusOK, transform.ToDTOs[dto.NotificationResponse](notifs))
 }
 
+// List godoc
+// @Summary List the current user's notifications with cursor pagination
+// @Description Newest first. Archived notifications are only listed with archived=true.
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param type query string false "Notification type" Enums(info, approval_request, status_update)
+// @Param project_id query string false "Project ID (UUID)"
+// @Param read query bool false "Read state"
+// @Param archived query bool false "List archived notifications"
+// @Success 200 {object} dto.NotificationPageResponse
+// @Failure 400 {string} string "invalid filter"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 500 {string} string "internal server error"
+// @Router /notifications [get]
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	userCtx, ok := httputil.GetUserFromContext(r.Context())
+	if !ok || userCtx == nil {
+		httputil.WriteError(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	filter, err := parseListFilter(r)
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	page, err := h.svc.ListInbox(r.Context(), userCtx.User.ID, filter)
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	resp := dto.NotificationPageResponse{Data: transform.ToDTOs[dto.NotificationResponse](page.Items)}
+	if page.NextCursor != nil {
+		cursor := page.NextCursor.Encode()
+		resp.NextCursor = &cursor
+	}
+	_ = httputil.WriteJSON(w, http.StatusOK, resp)
+}
+
+func parseListFilter(r *http.Request) (notification.ListFilter, error) {
+	q := r.URL.Query()
+	f := notification.ListFilter{Limit: httputil.ParseIntQuery(r, "limit", 0)}
+
+	if c := q.Get("cursor"); c != "" {
+		cursor, err := notification.DecodeCursor(c)
+		if err != nil {
+			return f, err
+		}
+		f.After = cursor
+	}
+	if t := q.Get("type"); t != "" {
+		nt := notification.NotificationType(t)
+		switch nt {
+		case notification.NotificationInfo, notification.NotificationApprovalRequest, notification.NotificationStatusUpdate:
+		default:
+			return f, fmt.Errorf("invalid type %q", t)
+		}
+		f.Type = &nt
+	}
+	if q.Get("project_id") != "" {
+		projectID, err := httputil.ParseUUIDQuery(r, "project_id")
+		if err != nil {
+			return f, fmt.Errorf("invalid project_id")
+		}
+		f.ProjectID = &projectID
+	}
+	if v := q.Get("read"); v != "" {
+		read, err := strconv.ParseBool(v)
+		if err != nil {
+			return f, fmt.Errorf("invalid read")
+		}
+		f.Read = &read
+	}
+	if v := q.Get("archived"); v != "" {
+		archived, err := strconv.ParseBool(v)
+		if err != nil {
+			return f, fmt.Errorf("invalid archived")
+		}
+		f.Archived = archived
+	}
+	return f, nil
+}
+
+// UnreadCount godoc
+// @Summary Count the current user's unread notifications
+// @Description Archived notifications are not counted.
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.NotificationUnreadCountResponse
+// @Failure 401 {string} string "unauthorized"
+// @Failure 500 {string} string "internal server error"
+// @Router /notifications/unread-count [get]
+func (h *Handler) UnreadCount(w http.ResponseWriter, r *http.Request) {
+	userCtx, ok := httputil.GetUserFromContext(r.Context())
+	if !ok || userCtx == nil {
+		httputil.WriteError(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	counts, err := h.svc.UnreadCounts(r.Context(), userCtx.User.ID)
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	resp := dto.NotificationUnreadCountResponse{Total: counts.Total, ByType: make(map[string]int, len(counts.ByType))}
+	for t, n := range counts.ByType {
+		resp.ByType[string(t)] = n
+	}
+	_ = httputil.WriteJSON(w, http.StatusOK, resp)
+}
+
 // MarkAsRead godoc
 // @Summary Mark notification as read
 // @Tags notifications
@@ -50,19 +175,227 @@ func (h *Handler) ListMe(w http.ResponseWriter, r *http.Request) {
 // @Param id path string true "Notification ID (UUID)"
 // @Success 200 {string} string "ok"
 // @Failure 400 {string} string "invalid id"
+// @Failure 404 {string} string "notification not found"
 // @Failure 500 {string} string "internal server error"
 // @Router /notifications/{id}/read [post]
 func (h *Handler) MarkAsRead(w http.ResponseWriter, r *http.Request) {
+	h.updateOne(w, r, h.svc.MarkAsReadForUser)
+}
+
+// MarkAllAsRead godoc
+// @Summary Mark all notifications of the current user as read
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.NotificationsUpdatedResponse
+// @Failure 401 {string} string "unauthorized"
+// @Failure 500 {string} string "internal server error"
+// @Router /notifications/read-all [post]
+func (h *Handler) MarkAllAsRead(w http.ResponseWriter, r *http.Request) {
+	userCtx, ok := httputil.GetUserFromContext(r.Context())
+	if !ok || userCtx == nil {
+		httputil.WriteError(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	n, err := h.svc.MarkAllAsRead(r.Context(), userCtx.User.ID)
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+	_ = httputil.WriteJSON(w, http.StatusOK, dto.NotificationsUpdatedResponse{Updated: n})
+}
+
+// MarkProjectAsRead godoc
+// @Summary Mark the current user's notifications about a project as read
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Param projectId path string true "Project ID (UUID)"
+// @Success 200 {object} dto.NotificationsUpdatedResponse
+// @Failure 400 {string} string "invalid project id"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 500 {string} string "internal server error"
+// @Router /notifications/projects/{projectId}/read [post]
+func (h *Handler) MarkProjectAsRead(w http.ResponseWriter, r *http.Request) {
+	userCtx, ok := httputil.GetUserFromContext(r.Context())
+	if !ok || userCtx == nil {
+		httputil.WriteError(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	projectID, err := httputil.ParseUUIDParam(r, "projectId")
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusBadRequest, "invalid project id", nil)
+		return
+	}
+
+	n, err := h.svc.MarkAsReadByProject(r.Context(), userCtx.User.ID, projectID)
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+	_ = httputil.WriteJSON(w, http.StatusOK, dto.NotificationsUpdatedResponse{Updated: n})
+}
+
+// Archive godoc
+// @Summary Archive a notification
+// @Description Archived notifications are hidden from the inbox and the unread count.
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Notification ID (UUID)"
+// @Success 200 {string} string "ok"
+// @Failure 400 {string} string "invalid id"
+// @Failure 404 {string} string "notification not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /notifications/{id}/archive [post]
+func (h *Handler) Archive(w http.ResponseWriter, r *http.Request) {
+	h.updateOne(w, r, h.svc.Archive)
+}
+
+// Unarchive godoc
+// @Summary Move an archived notification back to the inbox
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Notification ID (UUID)"
+// @Success 200 {string} string "ok"
+// @Failure 400 {string} string "invalid id"
+// @Failure 404 {string} string "notification not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /notifications/{id}/unarchive [post]
+func (h *Handler) Unarchive(w http.ResponseWriter, r *http.Request) {
+	h.updateOne(w, r, h.svc.Unarchive)
+}
+
+// Delete godoc
+// @Summary Delete a notification
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Notification ID (UUID)"
+// @Success 204 "No Content"
+// @Failure 400 {string} string "invalid id"
+// @Failure 404 {string} string "notification not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /notifications/{id} [delete]
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	userCtx, ok := httputil.GetUserFromContext(r.Context())
+	if !ok || userCtx == nil {
+		httputil.WriteError(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
 	id, err := httputil.ParseUUIDParam(r, "id")
 	if err != nil {
 		httputil.WriteError(w, r, http.StatusBadRequest, "invalid id", nil)
 		return
 	}
 
-	if err := h.svc.MarkAsRead(r.Context(), id); err != nil {
-		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+	if err := h.svc.Delete(r.Context(), userCtx.User.ID, id); err != nil {
+		writeNotificationError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// updateOne applies a user scoped change to the notification in the path
+func (h *Handler) updateOne(w http.ResponseWriter, r *http.Request, apply func(ctx context.Context, userID, id uuid.UUID) error) {
+	userCtx, ok := httputil.GetUserFromContext(r.Context())
+	if !ok || userCtx == nil {
+		httputil.WriteError(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	id, err := httputil.ParseUUIDParam(r, "id")
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusBadRequest, "invalid id", nil)
+		return
+	}
+
+	if err := apply(r.Context(), userCtx.User.ID, id); err != nil {
+		writeNotificationError(w, r, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
 }
+
+func writeNotificationError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, notification.ErrNotFound) {
+		httputil.WriteError(w, r, http.StatusNotFound, "notification not found", nil)
+		return
+	}
+	httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+}
+
+// GetPreferences godoc
+// @Summary List notification preferences of the current user
+// @Description Without preferences every notification is delivered in-app and by email immediately.
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} dto.NotificationPreferenceDTO
+// @Failure 401 {string} string "unauthorized"
+// @Failure 500 {string} string "internal server error"
+// @Router /notifications/preferences [get]
+func (h *Handler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	userCtx, ok := httputil.GetUserFromContext(r.Context())
+	if !ok || userCtx == nil {
+		httputil.WriteError(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	prefs, err := h.svc.GetPreferences(r.Context(), userCtx.User.ID)
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	_ = httputil.WriteJSON(w, http.StatusOK, transform.ToDTOs[dto.NotificationPreferenceDTO](prefs))
+}
+
+// UpdatePreferences godoc
+// @Summary Replace the notification preferences of the current user
+// @Description The most specific preference applies: notification and event type, then event type, then notification type, then the preference without either.
+// @Description Approval requests are always kept in-app.
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body dto.NotificationPreferencesRequest true "Preferences"
+// @Success 200 {array} dto.NotificationPreferenceDTO
+// @Failure 400 {string} string "invalid preferences"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 500 {string} string "internal server error"
+// @Router /notifications/preferences [put]
+func (h *Handler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	userCtx, ok := httputil.GetUserFromContext(r.Context())
+	if !ok || userCtx == nil {
+		httputil.WriteError(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	var req dto.NotificationPreferencesRequest
+	if !httputil.ReadJSON(w, r, &req) {
+		return
+	}
+
+	prefs := make([]notification.Preference, len(req.Preferences))
+	for i, p := range req.Preferences {
+		prefs[i] = p.ToEntity()
+	}
+
+	saved, err := h.svc.UpdatePreferences(r.Context(), userCtx.User.ID, prefs)
+	if err != nil {
+		if errors.Is(err, notification.ErrInvalidPreference) {
+			httputil.WriteError(w, r, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
+		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	_ = httputil.WriteJSON(w, http.StatusOK, transform.ToDTOs[dto.NotificationPreferenceDTO](saved))
+}
@@ -2,9 +2,10 @@ package organisation
 
 import "github.com/go-chi/chi/v5"
 
-func MountOrganisationRoutes(r chi.Router, h *Handler, rbac *RBACHandler, role *RoleHandler) {
+func MountOrganisationRoutes(r chi.Router, h *Handler, rbac *RBACHandler, role *RoleHandler, restructure *RestructureHandler, audit *AuditHandler, rorImport *RORImportHandler, provisioning *ProvisioningHandler) {
 	r.Route("/organisation-nodes", func(r chi.Router) {
 		r.Get("/ror/search", h.SearchROR)
+		r.Post("/ror/import", rorImport.ImportFromROR)
 		r.Get("/search", h.Search)
 		r.Get("/tree", h.GetTree) // Added tree endpoint
 		r.Post("/", h.CreateRoot)
@@ -12,6 +13,9 @@ func MountOrganisationRoutes(r chi.Router, h *Handler, rbac *RBACHandler, role *
 		r.Get("/roots", h.ListRoots)
 		r.Get("/{id}", h.GetOrganisationNode)
 		r.Patch("/{id}", h.UpdateOrganisationNode)
+		r.Delete("/{id}", restructure.DeleteOrganisationNode)
+		r.Post("/{id}/merge", restructure.MergeOrganisationNode)
+		r.Post("/{id}/move", restructure.MoveOrganisationNode)
 
 		r.Post("/{id}/children", h.CreateChild)
 		r.Get("/{id}/children", h.ListChildren)
@@ -20,6 +24,11 @@ func MountOrganisationRoutes(r chi.Router, h *Handler, rbac *RBACHandler, role *
 		r.Get("/{id}/memberships/effective", rbac.ListEffectiveMemberships)
 		r.Get("/{id}/approval-node", rbac.GetApprovalNode)
 		r.Get("/{id}/permissions/mine", rbac.GetMyPermissions)
+		r.Get("/{id}/permissions/explain", rbac.ExplainPermission)
+
+		// Audit log of roles, memberships, custom fields and project roles
+		r.Get("/{id}/audit-log", audit.ListAuditLog)
+		r.Get("/{id}/audit-log/export", audit.ExportAuditLog)
 
 		// Project Roles
 		r.Post("/{id}/roles", h.CreateProjectRole)
@@ -37,6 +46,12 @@ func MountOrganisationRoutes(r chi.Router, h *Handler, rbac *RBACHandler, role *
 		// Organisation Roles (RBAC)
 		r.Get("/{id}/organisation-roles", role.ListRoles)
 		r.Post("/{id}/organisation-roles", role.CreateRole)
+
+		// SURFconext membership provisioning (root nodes)
+		r.Get("/{id}/provisioning-rules", provisioning.ListProvisioningRules)
+		r.Post("/{id}/provisioning-rules", provisioning.CreateProvisioningRule)
+		r.Put("/{id}/provisioning-rules/{ruleId}", provisioning.UpdateProvisioningRule)
+		r.Delete("/{id}/provisioning-rules/{ruleId}", provisioning.DeleteProvisioningRule)
 	})
 
 	r.Route("/organisation-roles", func(r chi.Router) {
@@ -53,6 +68,7 @@ func MountOrganisationRoutes(r chi.Router, h *Handler, rbac *RBACHandler, role *
 
 	r.Route("/organisation-memberships", func(r chi.Router) {
 		r.Post("/", role.AddMembership)
+		r.Patch("/{id}", role.UpdateMembershipPeriod)
 		r.Delete("/{id}", role.RemoveMembership)
 		r.Get("/mine", rbac.ListMyMemberships)
 	})
@@ -1,10 +1,15 @@
 package di
 
 import (
+	"github.com/SURF-Innovatie/MORIS/internal/app/audit"
 	"github.com/SURF-Innovatie/MORIS/internal/app/customfield"
 	"github.com/SURF-Innovatie/MORIS/internal/app/organisation"
+	organisationprovisioning "github.com/SURF-Innovatie/MORIS/internal/app/organisation/provisioning"
 	organisationrbac "github.com/SURF-Innovatie/MORIS/internal/app/organisation/rbac"
+	organisationrestructure "github.com/SURF-Innovatie/MORIS/internal/app/organisation/restructure"
 	organisationrole "github.com/SURF-Innovatie/MORIS/internal/app/organisation/role"
+	organisationrorimport "github.com/SURF-Innovatie/MORIS/internal/app/organisation/rorimport"
+	"github.com/SURF-Innovatie/MORIS/internal/app/project/command"
 	"github.com/SURF-Innovatie/MORIS/internal/app/project/role"
 	organisationhandler "github.com/SURF-Innovatie/MORIS/internal/handler/organisation"
 	"github.com/samber/do/v2"
@@ -14,11 +19,16 @@ var Package = do.Package(
 	do.Lazy(provideOrganisationHandler),
 	do.Lazy(provideOrgRBACHandler),
 	do.Lazy(provideOrgRoleHandler),
+	do.Lazy(provideOrgRestructureHandler),
+	do.Lazy(provideOrgAuditHandler),
+	do.Lazy(provideOrgRORImportHandler),
+	do.Lazy(provideOrgProvisioningHandler),
 )
 
 func provideOrgRBACHandler(i do.Injector) (*organisationhandler.RBACHandler, error) {
 	svc := do.MustInvoke[organisationrbac.Service](i)
-	return organisationhandler.NewRBACHandler(svc), nil
+	commandSvc := do.MustInvoke[command.Service](i)
+	return organisationhandler.NewRBACHandler(svc, commandSvc), nil
 }
 
 func provideOrgRoleHandler(i do.Injector) (*organisationhandler.RoleHandler, error) {
@@ -34,3 +44,26 @@ func provideOrganisationHandler(i do.Injector) (*organisationhandler.Handler, er
 	cfSvc := do.MustInvoke[customfield.Service](i)
 	return organisationhandler.NewHandler(orgSvc, rbacSvc, roleSvc, cfSvc), nil
 }
+
+func provideOrgRestructureHandler(i do.Injector) (*organisationhandler.RestructureHandler, error) {
+	svc := do.MustInvoke[organisationrestructure.Service](i)
+	rbacSvc := do.MustInvoke[organisationrbac.Service](i)
+	return organisationhandler.NewRestructureHandler(svc, rbacSvc), nil
+}
+
+func provideOrgAuditHandler(i do.Injector) (*organisationhandler.AuditHandler, error) {
+	auditSvc := do.MustInvoke[audit.Service](i)
+	rbacSvc := do.MustInvoke[organisationrbac.Service](i)
+	return organisationhandler.NewAuditHandler(auditSvc, rbacSvc), nil
+}
+
+func provideOrgRORImportHandler(i do.Injector) (*organisationhandler.RORImportHandler, error) {
+	svc := do.MustInvoke[organisationrorimport.Service](i)
+	return organisationhandler.NewRORImportHandler(svc), nil
+}
+
+func provideOrgProvisioningHandler(i do.Injector) (*organisationhandler.ProvisioningHandler, error) {
+	svc := do.MustInvoke[organisationprovisioning.Service](i)
+	rbacSvc := do.MustInvoke[organisationrbac.Service](i)
+	return organisationhandler.NewProvisioningHandler(svc, rbacSvc), nil
+}
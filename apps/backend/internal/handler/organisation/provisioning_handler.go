@@ -0,0 +1,203 @@
+package organisation
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/SURF-Innovatie/MORIS/ent"
+	"github.com/SURF-Innovatie/MORIS/internal/api/dto"
+	"github.com/SURF-Innovatie/MORIS/internal/app/organisation/provisioning"
+	organisationrbacsvc "github.com/SURF-Innovatie/MORIS/internal/app/organisation/rbac"
+	"github.com/SURF-Innovatie/MORIS/internal/common/transform"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation/rbac"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/httputil"
+	"github.com/google/uuid"
+)
+
+type ProvisioningHandler struct {
+	svc  provisioning.Service
+	rbac organisationrbacsvc.Service
+}
+
+func NewProvisioningHandler(svc provisioning.Service, rbacSvc organisationrbacsvc.Service) *ProvisioningHandler {
+	return &ProvisioningHandler{svc: svc, rbac: rbacSvc}
+}
+
+// ListProvisioningRules godoc
+// @Summary List provisioning rules
+// @Description Lists the rules of a root node that grant role scopes to people logging in through SURFconext
+// @Tags organisation
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Root node ID"
+// @Success 200 {array} dto.OrganisationProvisioningRuleResponse
+// @Failure 400 {string} string "invalid id"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 500 {string} string "internal server error"
+// @Router /organisation-nodes/{id}/provisioning-rules [get]
+func (h *ProvisioningHandler) ListProvisioningRules(w http.ResponseWriter, r *http.Request) {
+	id, err := httputil.ParseUUIDParam(r, "id")
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusBadRequest, "invalid id", nil)
+		return
+	}
+	if !requireOrgPerm(w, r, h.rbac, id, rbac.PermissionManageMembers) {
+		return
+	}
+
+	rules, err := h.svc.ListRules(r.Context(), id)
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+	_ = httputil.WriteJSON(w, http.StatusOK, transform.ToDTOs[dto.OrganisationProvisioningRuleResponse](rules))
+}
+
+// CreateProvisioningRule godoc
+// @Summary Create a provisioning rule
+// @Description Adds a rule to a root node. On every SURFconext login, people whose schacHomeOrganization matches, and who have the affiliation and entitlement if these are set, get a membership of the role scope; the membership is revoked at a later login once no rule grants it anymore. The role scope must lie within the tree of the root node.
+// @Tags organisation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Root node ID"
+// @Param body body dto.OrganisationProvisioningRuleRequest true "Rule"
+// @Success 200 {object} dto.OrganisationProvisioningRuleResponse
+// @Failure 400 {string} string "invalid id / invalid request body / invalid provisioning rule"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "node or role scope not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /organisation-nodes/{id}/provisioning-rules [post]
+func (h *ProvisioningHandler) CreateProvisioningRule(w http.ResponseWriter, r *http.Request) {
+	id, err := httputil.ParseUUIDParam(r, "id")
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusBadRequest, "invalid id", nil)
+		return
+	}
+	if !requireOrgPerm(w, r, h.rbac, id, rbac.PermissionManageMembers) {
+		return
+	}
+
+	var req dto.OrganisationProvisioningRuleRequest
+	if !httputil.ReadJSON(w, r, &req) {
+		return
+	}
+
+	rule, err := h.svc.CreateRule(r.Context(), id, ruleInput(req))
+	if err != nil {
+		writeProvisioningError(w, r, err)
+		return
+	}
+	_ = httputil.WriteJSON(w, http.StatusOK, transform.ToDTOItem[dto.OrganisationProvisioningRuleResponse](*rule))
+}
+
+// UpdateProvisioningRule godoc
+// @Summary Update a provisioning rule
+// @Description Replaces the claims and role scope of a rule. Memberships follow at the next login of each person.
+// @Tags organisation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Root node ID"
+// @Param ruleId path string true "Rule ID"
+// @Param body body dto.OrganisationProvisioningRuleRequest true "Rule"
+// @Success 200 {object} dto.OrganisationProvisioningRuleResponse
+// @Failure 400 {string} string "invalid id / invalid request body / invalid provisioning rule"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "rule or role scope not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /organisation-nodes/{id}/provisioning-rules/{ruleId} [put]
+func (h *ProvisioningHandler) UpdateProvisioningRule(w http.ResponseWriter, r *http.Request) {
+	id, ruleID, ok := h.ruleOfNode(w, r)
+	if !ok {
+		return
+	}
+	if !requireOrgPerm(w, r, h.rbac, id, rbac.PermissionManageMembers) {
+		return
+	}
+
+	var req dto.OrganisationProvisioningRuleRequest
+	if !httputil.ReadJSON(w, r, &req) {
+		return
+	}
+
+	rule, err := h.svc.UpdateRule(r.Context(), ruleID, ruleInput(req))
+	if err != nil {
+		writeProvisioningError(w, r, err)
+		return
+	}
+	_ = httputil.WriteJSON(w, http.StatusOK, transform.ToDTOItem[dto.OrganisationProvisioningRuleResponse](*rule))
+}
+
+// DeleteProvisioningRule godoc
+// @Summary Delete a provisioning rule
+// @Description Removes a rule. The memberships it granted are revoked at the next login of each person.
+// @Tags organisation
+// @Security BearerAuth
+// @Param id path string true "Root node ID"
+// @Param ruleId path string true "Rule ID"
+// @Success 204
+// @Failure 400 {string} string "invalid id"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "rule not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /organisation-nodes/{id}/provisioning-rules/{ruleId} [delete]
+func (h *ProvisioningHandler) DeleteProvisioningRule(w http.ResponseWriter, r *http.Request) {
+	id, ruleID, ok := h.ruleOfNode(w, r)
+	if !ok {
+		return
+	}
+	if !requireOrgPerm(w, r, h.rbac, id, rbac.PermissionManageMembers) {
+		return
+	}
+
+	if err := h.svc.DeleteRule(r.Context(), ruleID); err != nil {
+		writeProvisioningError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ruleOfNode parses the node and rule IDs and checks that the rule is configured on the node
+func (h *ProvisioningHandler) ruleOfNode(w http.ResponseWriter, r *http.Request) (nodeID, ruleID uuid.UUID, ok bool) {
+	nodeID, err := httputil.ParseUUIDParam(r, "id")
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusBadRequest, "invalid id", nil)
+		return nodeID, ruleID, false
+	}
+	ruleID, err = httputil.ParseUUIDParam(r, "ruleId")
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusBadRequest, "invalid rule id", nil)
+		return nodeID, ruleID, false
+	}
+	rule, err := h.svc.GetRule(r.Context(), ruleID)
+	if err != nil || rule.RootNodeID != nodeID {
+		httputil.WriteError(w, r, http.StatusNotFound, "provisioning rule not found", nil)
+		return nodeID, ruleID, false
+	}
+	return nodeID, ruleID, true
+}
+
+func ruleInput(req dto.OrganisationProvisioningRuleRequest) provisioning.RuleInput {
+	return provisioning.RuleInput{
+		RoleScopeID:           req.RoleScopeID,
+		SchacHomeOrganization: req.SchacHomeOrganization,
+		Affiliation:           req.Affiliation,
+		Entitlement:           req.Entitlement,
+	}
+}
+
+func writeProvisioningError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, rbac.ErrInvalidProvisioningRule):
+		httputil.WriteError(w, r, http.StatusBadRequest, err.Error(), nil)
+	case ent.IsNotFound(err):
+		httputil.WriteError(w, r, http.StatusNotFound, "not found", nil)
+	default:
+		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+	}
+}
@@ -1,10 +1,12 @@
 package organisation
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/SURF-Innovatie/MORIS/internal/api/dto"
 	rbacsvc "github.com/SURF-Innovatie/MORIS/internal/app/organisation/rbac"
+	"github.com/SURF-Innovatie/MORIS/internal/app/project/command"
 	"github.com/SURF-Innovatie/MORIS/internal/common/transform"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation/rbac"
 	"github.com/SURF-Innovatie/MORIS/internal/infra/httputil"
@@ -12,11 +14,12 @@ import (
 )
 
 type RBACHandler struct {
-	rbac rbacsvc.Service
+	rbac     rbacsvc.Service
+	commands command.Service
 }
 
-func NewRBACHandler(r rbacsvc.Service) *RBACHandler {
-	return &RBACHandler{rbac: r}
+func NewRBACHandler(r rbacsvc.Service, commands command.Service) *RBACHandler {
+	return &RBACHandler{rbac: r, commands: commands}
 }
 
 // ListEffectiveMemberships godoc
@@ -147,3 +150,110 @@ func (h *RBACHandler) GetMyPermissions(w http.ResponseWriter, r *http.Request) {
 
 	_ = httputil.WriteJSON(w, http.StatusOK, out)
 }
+
+// ExplainPermission godoc
+// @Summary Explain a permission decision
+// @Description Shows why a person does or does not hold a permission on the node: the node's ancestors and, for each of the person's memberships that applies, the role scope, organisation role and ancestor it is rooted at. With project and event_type it also shows how the person's project roles decide on executing that event type; the project must be owned by the node or one of its descendants. Available to sysadmins, to people who can manage members of the node and to the person themselves.
+// @Tags organisation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Node ID"
+// @Param person query string true "Person ID"
+// @Param permission query string true "Permission key"
+// @Param project query string false "Project ID, to explain event execution"
+// @Param event_type query string false "Event type, to explain event execution"
+// @Success 200 {object} dto.PermissionExplanationResponse
+// @Failure 400 {string} string "invalid id / invalid person / unknown permission / unknown event type"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "project not found within the node"
+// @Failure 500 {string} string "internal server error"
+// @Router /organisation-nodes/{id}/permissions/explain [get]
+func (h *RBACHandler) ExplainPermission(w http.ResponseWriter, r *http.Request) {
+	nodeID, err := httputil.ParseUUIDParam(r, "id")
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusBadRequest, "invalid id", nil)
+		return
+	}
+	personID, err := httputil.ParseUUIDQuery(r, "person")
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusBadRequest, "invalid person", nil)
+		return
+	}
+	permission := rbac.Permission(r.URL.Query().Get("permission"))
+	if !lo.Contains(rbac.AllPermissions, permission) {
+		httputil.WriteError(w, r, http.StatusBadRequest, "unknown permission", nil)
+		return
+	}
+
+	user, ok := httputil.GetUserFromContext(r.Context())
+	if !ok {
+		httputil.WriteError(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+	if !user.User.IsSysAdmin && user.Person.ID != personID {
+		allowed, err := h.rbac.HasPermission(r.Context(), user.Person.ID, nodeID, rbac.PermissionManageMembers)
+		if err != nil {
+			httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+			return
+		}
+		if !allowed {
+			httputil.WriteError(w, r, http.StatusForbidden, "forbidden", nil)
+			return
+		}
+	}
+
+	explanation, err := h.rbac.ExplainPermission(r.Context(), personID, nodeID, permission)
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+	out := transform.ToDTOItem[dto.PermissionExplanationResponse](explanation)
+
+	eventType := r.URL.Query().Get("event_type")
+	if r.URL.Query().Get("project") != "" || eventType != "" {
+		projectID, err := httputil.ParseUUIDQuery(r, "project")
+		if err != nil || eventType == "" {
+			httputil.WriteError(w, r, http.StatusBadRequest, "project and event_type must be given together", nil)
+			return
+		}
+
+		exec, err := h.commands.ExplainEventExecution(r.Context(), nodeID, projectID, personID, eventType)
+		switch {
+		case errors.Is(err, command.ErrUnknownEventType):
+			httputil.WriteError(w, r, http.StatusBadRequest, err.Error(), nil)
+			return
+		case errors.Is(err, command.ErrProjectNotFound):
+			httputil.WriteError(w, r, http.StatusNotFound, "project not found", nil)
+			return
+		case err != nil:
+			httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+			return
+		}
+
+		out.EventExecution = &dto.EventExecutionExplanationResponse{
+			ProjectID: exec.ProjectID,
+			EventType: exec.EventType,
+			Member:    exec.Member,
+			Roles: lo.Map(exec.Roles, func(d command.ProjectRoleDecision, _ int) dto.ProjectRoleDecisionResponse {
+				return dto.ProjectRoleDecisionResponse{
+					RoleID:            d.RoleID,
+					Key:               d.Key,
+					Name:              d.Name,
+					AllowedEventTypes: d.AllowedEventTypes,
+					Allowed:           d.Allowed,
+				}
+			}),
+			Allowed: exec.Allowed,
+			Reason:  exec.Reason,
+		}
+		// Sysadmins skip the role check when executing events
+		if explanation.SysAdmin {
+			out.EventExecution.Allowed = true
+			out.EventExecution.Reason = "allowed: the person is a sysadmin"
+		}
+	}
+
+	_ = httputil.WriteJSON(w, http.StatusOK, out)
+}
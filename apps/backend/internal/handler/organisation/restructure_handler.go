@@ -0,0 +1,193 @@
+package organisation
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/SURF-Innovatie/MORIS/ent"
+	"github.com/SURF-Innovatie/MORIS/internal/api/dto"
+	rbacsvc "github.com/SURF-Innovatie/MORIS/internal/app/organisation/rbac"
+	"github.com/SURF-Innovatie/MORIS/internal/app/organisation/restructure"
+	"github.com/SURF-Innovatie/MORIS/internal/common/transform"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/httputil"
+	"github.com/google/uuid"
+)
+
+type RestructureHandler struct {
+	svc  restructure.Service
+	rbac rbacsvc.Service
+}
+
+func NewRestructureHandler(svc restructure.Service, r rbacsvc.Service) *RestructureHandler {
+	return &RestructureHandler{svc: svc, rbac: r}
+}
+
+// DeleteOrganisationNode godoc
+// @Summary Delete an organisation node
+// @Description Deletes a node that nothing references anymore; its webhook subscriptions are removed with it. A node that still has children, projects, roles, custom fields, memberships, policies or provisioning rules is refused with 409 and a usage report, unless reassign_to names a node that takes everything over (a merge).
+// @Tags organisation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Organisation node ID"
+// @Param reassign_to query string false "Node that takes over projects, roles, custom fields, memberships, policies and children"
+// @Param dry_run query bool false "Only report what would change"
+// @Success 200 {object} dto.OrganisationRestructureResponse
+// @Failure 400 {string} string "invalid id / invalid reassign_to / invalid restructure"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 409 {object} httputil.BackendError "node in use, errors holds the usage report"
+// @Failure 500 {string} string "internal server error"
+// @Router /organisation-nodes/{id} [delete]
+func (h *RestructureHandler) DeleteOrganisationNode(w http.ResponseWriter, r *http.Request) {
+	if !h.requireSysAdmin(w, r) {
+		return
+	}
+
+	id, err := httputil.ParseUUIDParam(r, "id")
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusBadRequest, "invalid id", nil)
+		return
+	}
+
+	opts := restructure.DeleteOptions{DryRun: r.URL.Query().Get("dry_run") == "true"}
+	if r.URL.Query().Get("reassign_to") != "" {
+		target, err := httputil.ParseUUIDQuery(r, "reassign_to")
+		if err != nil {
+			httputil.WriteError(w, r, http.StatusBadRequest, "invalid reassign_to", nil)
+			return
+		}
+		opts.ReassignTo = &target
+	}
+
+	report, err := h.svc.Delete(r.Context(), id, opts)
+	writeRestructureResult(w, r, report, err)
+}
+
+// MergeOrganisationNode godoc
+// @Summary Merge an organisation node into another
+// @Description Re-points projects, project and organisation roles, custom fields, memberships, event policies and webhook subscriptions to the target, moves the children under the target and deletes the node
+// @Tags organisation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Organisation node ID"
+// @Param body body dto.OrganisationMergeRequest true "Merge request"
+// @Success 200 {object} dto.OrganisationRestructureResponse
+// @Failure 400 {string} string "invalid id / invalid request body / invalid restructure"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /organisation-nodes/{id}/merge [post]
+func (h *RestructureHandler) MergeOrganisationNode(w http.ResponseWriter, r *http.Request) {
+	if !h.requireSysAdmin(w, r) {
+		return
+	}
+
+	id, err := httputil.ParseUUIDParam(r, "id")
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusBadRequest, "invalid id", nil)
+		return
+	}
+
+	var req dto.OrganisationMergeRequest
+	if !httputil.ReadJSON(w, r, &req) {
+		return
+	}
+	if req.TargetID == uuid.Nil {
+		httputil.WriteError(w, r, http.StatusBadRequest, "targetId is required", nil)
+		return
+	}
+
+	report, err := h.svc.Merge(r.Context(), id, req.TargetID, req.DryRun)
+	writeRestructureResult(w, r, report, err)
+}
+
+// MoveOrganisationNode godoc
+// @Summary Move an organisation subtree
+// @Description Moves a node with all its descendants under a new parent (null makes it a root) and rebuilds the closure rows in one transaction
+// @Tags organisation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Organisation node ID"
+// @Param body body dto.OrganisationMoveRequest true "Move request"
+// @Success 200 {object} dto.OrganisationRestructureResponse
+// @Failure 400 {string} string "invalid id / invalid request body / invalid restructure"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /organisation-nodes/{id}/move [post]
+func (h *RestructureHandler) MoveOrganisationNode(w http.ResponseWriter, r *http.Request) {
+	user, ok := httputil.GetUserFromContext(r.Context())
+	if !ok {
+		httputil.WriteError(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	id, err := httputil.ParseUUIDParam(r, "id")
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusBadRequest, "invalid id", nil)
+		return
+	}
+
+	var req dto.OrganisationMoveRequest
+	if !httputil.ReadJSON(w, r, &req) {
+		return
+	}
+
+	// Same rules as re-parenting through an update: admin rights on the node and on the new parent,
+	// and only sysadmins create roots
+	if !user.User.IsSysAdmin {
+		if req.ParentID == nil {
+			httputil.WriteError(w, r, http.StatusForbidden, "forbidden: only sysadmin can create root nodes", nil)
+			return
+		}
+		for _, nodeID := range []uuid.UUID{id, *req.ParentID} {
+			hasAccess, err := h.rbac.HasAdminAccess(r.Context(), user.Person.ID, nodeID)
+			if err != nil {
+				httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+				return
+			}
+			if !hasAccess {
+				httputil.WriteError(w, r, http.StatusForbidden, "forbidden: requires admin rights on node and new parent", nil)
+				return
+			}
+		}
+	}
+
+	report, err := h.svc.Move(r.Context(), id, req.ParentID, req.DryRun)
+	writeRestructureResult(w, r, report, err)
+}
+
+func (h *RestructureHandler) requireSysAdmin(w http.ResponseWriter, r *http.Request) bool {
+	user, ok := httputil.GetUserFromContext(r.Context())
+	if !ok {
+		httputil.WriteError(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return false
+	}
+	if !user.User.IsSysAdmin {
+		httputil.WriteError(w, r, http.StatusForbidden, "forbidden: only sysadmin can delete or merge nodes", nil)
+		return false
+	}
+	return true
+}
+
+func writeRestructureResult(w http.ResponseWriter, r *http.Request, report *organisation.RestructureReport, err error) {
+	switch {
+	case err == nil:
+		_ = httputil.WriteJSON(w, http.StatusOK, transform.ToDTOItem[dto.OrganisationRestructureResponse](*report))
+	case errors.Is(err, organisation.ErrNodeInUse) && report != nil:
+		httputil.WriteError(w, r, http.StatusConflict, err.Error(), transform.ToDTOItem[dto.OrganisationRestructureResponse](*report))
+	case errors.Is(err, organisation.ErrInvalidRestructure):
+		httputil.WriteError(w, r, http.StatusBadRequest, err.Error(), nil)
+	case ent.IsNotFound(err):
+		httputil.WriteError(w, r, http.StatusNotFound, "organisation node not found", nil)
+	default:
+		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+	}
+}
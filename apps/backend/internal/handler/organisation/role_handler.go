@@ -1,6 +1,7 @@
 package organisation
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/SURF-Innovatie/MORIS/internal/api/dto"
@@ -291,7 +292,66 @@ func (h *RoleHandler) AddMembership(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	m, err := h.roleSvc.AddMembership(r.Context(), req.PersonID, req.RoleScopeID)
+	m, err := h.roleSvc.AddMembership(r.Context(), req.PersonID, req.RoleScopeID, req.StartDate, req.EndDate)
+	if errors.Is(err, rbac.ErrInvalidMembershipPeriod) {
+		httputil.WriteError(w, r, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	_ = httputil.WriteJSON(w, http.StatusOK, transform.ToDTOItem[dto.OrganisationMembershipResponse](m))
+}
+
+// UpdateMembershipPeriod godoc
+// @Summary Update membership period
+// @Description Sets the period in which a membership grants its role. Omitted or null dates leave that side of the period open; the membership stops granting at the end date.
+// @Tags organisation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Membership ID"
+// @Param request body dto.OrganisationMembershipPeriodRequest true "Membership period"
+// @Success 200 {object} dto.OrganisationMembershipResponse
+// @Failure 400 {string} string "invalid id / invalid membership period"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /organisation-memberships/{id} [patch]
+func (h *RoleHandler) UpdateMembershipPeriod(w http.ResponseWriter, r *http.Request) {
+	id, err := httputil.ParseUUIDParam(r, "id")
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusBadRequest, "invalid id", nil)
+		return
+	}
+
+	var req dto.OrganisationMembershipPeriodRequest
+	if !httputil.ReadJSON(w, r, &req) {
+		return
+	}
+
+	m, err := h.roleSvc.GetMembership(r.Context(), id)
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusNotFound, "membership not found", nil)
+		return
+	}
+	scope, err := h.roleSvc.GetScope(r.Context(), m.RoleScopeID)
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusNotFound, "scope not found", nil)
+		return
+	}
+
+	if !requireOrgPerm(w, r, h.rbacSvc, scope.RootNodeID, rbac.PermissionManageMembers) {
+		return
+	}
+
+	m, err = h.roleSvc.SetMembershipPeriod(r.Context(), id, req.StartDate, req.EndDate)
+	if errors.Is(err, rbac.ErrInvalidMembershipPeriod) {
+		httputil.WriteError(w, r, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
 	if err != nil {
 		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
 		return
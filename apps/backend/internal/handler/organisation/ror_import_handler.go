@@ -0,0 +1,71 @@
+package organisation
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/SURF-Innovatie/MORIS/ent"
+	"github.com/SURF-Innovatie/MORIS/internal/api/dto"
+	"github.com/SURF-Innovatie/MORIS/internal/app/organisation/rorimport"
+	"github.com/SURF-Innovatie/MORIS/internal/common/transform"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/httputil"
+)
+
+type RORImportHandler struct {
+	svc rorimport.Service
+}
+
+func NewRORImportHandler(svc rorimport.Service) *RORImportHandler {
+	return &RORImportHandler{svc: svc}
+}
+
+// ImportFromROR godoc
+// @Summary Import an organisation hierarchy from ROR
+// @Description Fetches the organisation and, following its child relationships, its descendants from ROR, and creates or updates the matching nodes. Nodes are matched on their ROR ID, so running the import again renames and re-parents nodes and records withdrawn organisations instead of duplicating them. Withdrawn organisations are not created. Use dryRun for a preview of the changes.
+// @Tags organisation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body dto.OrganisationRORImportRequest true "Import request"
+// @Success 200 {object} dto.OrganisationRORImportResponse
+// @Failure 400 {string} string "invalid request body / invalid ror id / hierarchy too large / invalid restructure"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "ror organisation or parent node not found"
+// @Failure 502 {string} string "ror unavailable"
+// @Router /organisation-nodes/ror/import [post]
+func (h *RORImportHandler) ImportFromROR(w http.ResponseWriter, r *http.Request) {
+	user, ok := httputil.GetUserFromContext(r.Context())
+	if !ok {
+		httputil.WriteError(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+	if !user.User.IsSysAdmin {
+		httputil.WriteError(w, r, http.StatusForbidden, "forbidden: only sysadmin can import from ror", nil)
+		return
+	}
+
+	var req dto.OrganisationRORImportRequest
+	if !httputil.ReadJSON(w, r, &req) {
+		return
+	}
+
+	report, err := h.svc.Import(r.Context(), req.RorID, rorimport.ImportOptions{ParentID: req.ParentID, DryRun: req.DryRun})
+	switch {
+	case err == nil:
+		_ = httputil.WriteJSON(w, http.StatusOK, transform.ToDTOItem[dto.OrganisationRORImportResponse](*report))
+	case errors.Is(err, organisation.ErrInvalidRORID),
+		errors.Is(err, organisation.ErrRORImportTooLarge),
+		errors.Is(err, organisation.ErrInvalidRestructure):
+		httputil.WriteError(w, r, http.StatusBadRequest, err.Error(), nil)
+	case errors.Is(err, organisation.ErrRORNotFound):
+		httputil.WriteError(w, r, http.StatusNotFound, err.Error(), nil)
+	case ent.IsNotFound(err):
+		httputil.WriteError(w, r, http.StatusNotFound, "parent node not found", nil)
+	case errors.Is(err, rorimport.ErrRORUnavailable):
+		httputil.WriteError(w, r, http.StatusBadGateway, err.Error(), nil)
+	default:
+		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+	}
+}
@@ -1,10 +1,13 @@
 package command
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/SURF-Innovatie/MORIS/internal/api/dto"
 	"github.com/SURF-Innovatie/MORIS/internal/app/project/command"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation/rbac"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/policy"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/project/events"
 	"github.com/SURF-Innovatie/MORIS/internal/infra/httputil"
 )
@@ -67,7 +70,9 @@ func (h *Handler) ListAvailableEvents(w http.ResponseWriter, r *http.Request) {
 // @Param body body dto.ExecuteEventRequest true "Event execution request"
 // @Success 200 {object} dto.ExecuteEventRequest "Updated project"
 // @Failure 400 {string} string "invalid request"
+// @Failure 403 {string} string "role does not allow the event type"
 // @Failure 404 {string} string "unknown event type"
+// @Failure 409 {string} string "policy shadows a locked organisation policy"
 // @Failure 500 {string} string "internal server error"
 // @Router /projects/{id}/events [post]
 func (h *Handler) ExecuteEvent(w http.ResponseWriter, r *http.Request) {
@@ -90,6 +95,14 @@ func (h *Handler) ExecuteEvent(w http.ResponseWriter, r *http.Request) {
 	}
 
 	proj, err := h.svc.ExecuteEvent(r.Context(), appReq)
+	if errors.Is(err, rbac.ErrForbidden) {
+		httputil.WriteError(w, r, http.StatusForbidden, err.Error(), nil)
+		return
+	}
+	if errors.Is(err, policy.ErrPolicyLocked) {
+		httputil.WriteError(w, r, http.StatusConflict, err.Error(), nil)
+		return
+	}
 	if err != nil {
 		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
 		return
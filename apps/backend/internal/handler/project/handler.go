@@ -1,6 +1,7 @@
 package project
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/SURF-Innovatie/MORIS/internal/api/dto"
@@ -8,6 +9,7 @@ import (
 	"github.com/SURF-Innovatie/MORIS/internal/app/project/queries"
 	"github.com/SURF-Innovatie/MORIS/internal/common/transform"
 	customfield2 "github.com/SURF-Innovatie/MORIS/internal/domain/customfield"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation/rbac"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/project/events"
 	"github.com/SURF-Innovatie/MORIS/internal/infra/httputil"
 	"github.com/samber/lo"
@@ -32,6 +34,7 @@ func NewHandler(svc queries.Service, cfs customfield.Service) *Handler {
 // @Param id path string true "Project ID (UUID)"
 // @Success 200 {object} dto.ProjectResponse
 // @Failure 400 {string} string "invalid project id"
+// @Failure 403 {string} string "forbidden"
 // @Failure 404 {string} string "project not found"
 // @Router /projects/{id} [get]
 func (h *Handler) GetProject(w http.ResponseWriter, r *http.Request) {
@@ -42,7 +45,7 @@ func (h *Handler) GetProject(w http.ResponseWriter, r *http.Request) {
 	}
 	proj, err := h.svc.GetProject(r.Context(), id)
 	if err != nil {
-		httputil.WriteError(w, r, http.StatusNotFound, err.Error(), nil)
+		writeReadError(w, r, err, http.StatusNotFound)
 		return
 	}
 	_ = httputil.WriteJSON(w, http.StatusOK, transform.ToDTOItem[dto.ProjectResponse](proj))
@@ -78,6 +81,7 @@ func (h *Handler) GetAllProjects(w http.ResponseWriter, r *http.Request) {
 // @Param id path string true "Project ID (UUID)"
 // @Success 200 {object} dto.EventResponse
 // @Failure 400 {string} string "invalid project id"
+// @Failure 403 {string} string "forbidden"
 // @Failure 500 {string} string "internal server error"
 // @Router /projects/{id}/changelog [get]
 func (h *Handler) GetChangelog(w http.ResponseWriter, r *http.Request) {
@@ -89,7 +93,7 @@ func (h *Handler) GetChangelog(w http.ResponseWriter, r *http.Request) {
 
 	detailedEvents, err := h.svc.GetChangeLog(r.Context(), id)
 	if err != nil {
-		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+		writeReadError(w, r, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -113,6 +117,7 @@ func (h *Handler) GetChangelog(w http.ResponseWriter, r *http.Request) {
 // @Param id path string true "Project ID (UUID)"
 // @Success 200 {object} dto.EventResponse
 // @Failure 400 {string} string "invalid project id"
+// @Failure 403 {string} string "forbidden"
 // @Failure 500 {string} string "internal server error"
 // @Router /projects/{id}/pending-events [get]
 func (h *Handler) GetPendingEvents(w http.ResponseWriter, r *http.Request) {
@@ -124,7 +129,7 @@ func (h *Handler) GetPendingEvents(w http.ResponseWriter, r *http.Request) {
 
 	pendingEvents, err := h.svc.GetPendingEvents(r.Context(), id)
 	if err != nil {
-		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+		writeReadError(w, r, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -148,6 +153,7 @@ func (h *Handler) GetPendingEvents(w http.ResponseWriter, r *http.Request) {
 // @Param id path string true "Project ID (UUID)"
 // @Success 200 {array} dto.ProjectRoleResponse
 // @Failure 400 {string} string "invalid project id"
+// @Failure 403 {string} string "forbidden"
 // @Failure 500 {string} string "internal server error"
 // @Router /projects/{id}/roles [get]
 func (h *Handler) ListAvailableRoles(w http.ResponseWriter, r *http.Request) {
@@ -159,7 +165,7 @@ func (h *Handler) ListAvailableRoles(w http.ResponseWriter, r *http.Request) {
 
 	roles, err := h.svc.ListAvailableRoles(r.Context(), id)
 	if err != nil {
-		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+		writeReadError(w, r, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -210,6 +216,7 @@ func (h *Handler) GetAllowedEvents(w http.ResponseWriter, r *http.Request) {
 // @Param id path string true "Project ID (UUID)"
 // @Success 200 {array} dto.CustomFieldDefinitionResponse
 // @Failure 400 {string} string "invalid project id"
+// @Failure 403 {string} string "forbidden"
 // @Failure 500 {string} string "internal server error"
 // @Router /projects/{id}/custom-fields [get]
 func (h *Handler) ListAvailableCustomFields(w http.ResponseWriter, r *http.Request) {
@@ -222,6 +229,10 @@ func (h *Handler) ListAvailableCustomFields(w http.ResponseWriter, r *http.Reque
 	// 1. Get Project to find OwningOrgNode
 	proj, err := h.svc.GetProject(r.Context(), id)
 	if err != nil {
+		if errors.Is(err, rbac.ErrForbidden) {
+			httputil.WriteError(w, r, http.StatusForbidden, err.Error(), nil)
+			return
+		}
 		httputil.WriteError(w, r, http.StatusNotFound, "project not found", nil)
 		return
 	}
@@ -236,3 +247,12 @@ func (h *Handler) ListAvailableCustomFields(w http.ResponseWriter, r *http.Reque
 
 	_ = httputil.WriteJSON(w, http.StatusOK, transform.ToDTOs[dto.CustomFieldDefinitionResponse](defs))
 }
+
+// writeReadError maps project read errors to HTTP responses, using fallback for anything but a missing permission
+func writeReadError(w http.ResponseWriter, r *http.Request, err error, fallback int) {
+	if errors.Is(err, rbac.ErrForbidden) {
+		httputil.WriteError(w, r, http.StatusForbidden, err.Error(), nil)
+		return
+	}
+	httputil.WriteError(w, r, fallback, err.Error(), nil)
+}
@@ -0,0 +1,34 @@
+package scim
+
+import (
+	"github.com/go-chi/chi/v5"
+)
+
+// MountRoutes mounts the SCIM 2.0 endpoints, which authenticate with SCIM tokens instead of user sessions
+func MountRoutes(r chi.Router, h *Handler) {
+	r.Route("/scim/v2", func(r chi.Router) {
+		r.Use(TokenMiddleware(h.svc))
+		r.Get("/ServiceProviderConfig", h.ServiceProviderConfig)
+
+		r.Get("/Users", h.ListUsers)
+		r.Post("/Users", h.CreateUser)
+		r.Get("/Users/{id}", h.GetUser)
+		r.Put("/Users/{id}", h.ReplaceUser)
+		r.Patch("/Users/{id}", h.PatchUser)
+		r.Delete("/Users/{id}", h.DeleteUser)
+
+		r.Get("/Groups", h.ListGroups)
+		r.Post("/Groups", h.CreateGroup)
+		r.Get("/Groups/{id}", h.GetGroup)
+		r.Put("/Groups/{id}", h.ReplaceGroup)
+		r.Patch("/Groups/{id}", h.PatchGroup)
+		r.Delete("/Groups/{id}", h.DeleteGroup)
+	})
+}
+
+// MountTokenRoutes mounts the management of SCIM tokens, which requires a user session
+func MountTokenRoutes(r chi.Router, h *Handler) {
+	r.Get("/organisation-nodes/{id}/scim-tokens", h.ListTokens)
+	r.Post("/organisation-nodes/{id}/scim-tokens", h.CreateToken)
+	r.Delete("/organisation-nodes/{id}/scim-tokens/{tokenId}", h.DeleteToken)
+}
@@ -0,0 +1,16 @@
+package di
+
+import (
+	"github.com/SURF-Innovatie/MORIS/internal/app/scim"
+	scimhandler "github.com/SURF-Innovatie/MORIS/internal/handler/scim"
+	"github.com/samber/do/v2"
+)
+
+var Package = do.Package(
+	do.Lazy(provideHandler),
+)
+
+func provideHandler(i do.Injector) (*scimhandler.Handler, error) {
+	svc := do.MustInvoke[scim.Service](i)
+	return scimhandler.NewHandler(svc), nil
+}
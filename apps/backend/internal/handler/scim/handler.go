@@ -0,0 +1,479 @@
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/SURF-Innovatie/MORIS/ent"
+	"github.com/SURF-Innovatie/MORIS/internal/api/dto"
+	scimsvc "github.com/SURF-Innovatie/MORIS/internal/app/scim"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/scim"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/env"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/httputil"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+const (
+	contentType     = "application/scim+json"
+	defaultCount    = 100
+	maxCount        = 500
+	maxRequestBytes = 1 << 20
+)
+
+// Handler serves the SCIM 2.0 API of the root node of the request's token, and the management of SCIM tokens
+type Handler struct {
+	svc scimsvc.Service
+}
+
+func NewHandler(svc scimsvc.Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// ServiceProviderConfig godoc
+// @Summary SCIM service provider configuration
+// @Description Describes the SCIM features MORIS supports: PATCH and filtering, without bulk operations, sorting, ETags or password changes.
+// @Tags scim
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.ScimServiceProviderConfig
+// @Failure 401 {object} dto.ScimError
+// @Router /scim/v2/ServiceProviderConfig [get]
+func (h *Handler) ServiceProviderConfig(w http.ResponseWriter, r *http.Request) {
+	writeScim(w, http.StatusOK, dto.ScimServiceProviderConfig{
+		Schemas:        []string{dto.ScimServiceConfigSchema},
+		Patch:          dto.ScimSupported{Supported: true},
+		Filter:         dto.ScimFilterSupport{Supported: true, MaxResults: maxCount},
+		ChangePassword: dto.ScimSupported{},
+		Sort:           dto.ScimSupported{},
+		Etag:           dto.ScimSupported{},
+		AuthenticationSchemes: []dto.ScimAuthenticationScheme{{
+			Type:        "oauthbearertoken",
+			Name:        "SCIM token",
+			Description: "A bearer token issued for a root organisation node",
+		}},
+	})
+}
+
+// ListUsers godoc
+// @Summary List SCIM users
+// @Description Lists the users provisioned for the root node of the token. Supports the SCIM filter expression language, e.g. userName eq "ada@example.org".
+// @Tags scim
+// @Produce json
+// @Security BearerAuth
+// @Param filter query string false "SCIM filter"
+// @Param startIndex query int false "1-based index of the first result"
+// @Param count query int false "Page size (default 100, max 500)"
+// @Success 200 {object} dto.ScimListResponse[dto.ScimUser]
+// @Failure 400 {object} dto.ScimError
+// @Failure 401 {object} dto.ScimError
+// @Router /scim/v2/Users [get]
+func (h *Handler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	q, ok := listQuery(w, r)
+	if !ok {
+		return
+	}
+	page, err := h.svc.ListUsers(r.Context(), tokenFromContext(r.Context()).RootNodeID, q)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	base := baseURL(r)
+	writeScim(w, http.StatusOK, dto.ScimListResponse[dto.ScimUser]{
+		Schemas:      []string{dto.ScimListResponseSchema},
+		TotalResults: page.TotalResults,
+		StartIndex:   page.StartIndex,
+		ItemsPerPage: len(page.Resources),
+		Resources: lo.Map(page.Resources, func(u scim.User, _ int) dto.ScimUser {
+			return dto.ScimUser{}.FromEntity(u, base+"/Users/"+u.ID.String())
+		}),
+	})
+}
+
+// GetUser godoc
+// @Summary Get a SCIM user
+// @Tags scim
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID (UUID)"
+// @Success 200 {object} dto.ScimUser
+// @Failure 401 {object} dto.ScimError
+// @Failure 404 {object} dto.ScimError
+// @Router /scim/v2/Users/{id} [get]
+func (h *Handler) GetUser(w http.ResponseWriter, r *http.Request) {
+	id, ok := resourceID(w, r)
+	if !ok {
+		return
+	}
+	u, err := h.svc.GetUser(r.Context(), tokenFromContext(r.Context()).RootNodeID, id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	h.writeUser(w, r, http.StatusOK, u)
+}
+
+// CreateUser godoc
+// @Summary Provision a SCIM user
+// @Description Creates a person and user account for the email address in userName, or adopts the existing ones when no other root node provisions them.
+// @Tags scim
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body dto.ScimUser true "User"
+// @Success 201 {object} dto.ScimUser
+// @Failure 400 {object} dto.ScimError
+// @Failure 401 {object} dto.ScimError
+// @Failure 409 {object} dto.ScimError
+// @Router /scim/v2/Users [post]
+func (h *Handler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	var req dto.ScimUser
+	if !readScim(w, r, &req) {
+		return
+	}
+	u, err := h.svc.CreateUser(r.Context(), tokenFromContext(r.Context()).RootNodeID, req.ToEntity())
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	h.writeUser(w, r, http.StatusCreated, u)
+}
+
+// ReplaceUser godoc
+// @Summary Replace a SCIM user
+// @Description Replaces the attributes of a provisioned user. Setting active to false deactivates the account.
+// @Tags scim
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID (UUID)"
+// @Param body body dto.ScimUser true "User"
+// @Success 200 {object} dto.ScimUser
+// @Failure 400 {object} dto.ScimError
+// @Failure 401 {object} dto.ScimError
+// @Failure 404 {object} dto.ScimError
+// @Failure 409 {object} dto.ScimError
+// @Router /scim/v2/Users/{id} [put]
+func (h *Handler) ReplaceUser(w http.ResponseWriter, r *http.Request) {
+	id, ok := resourceID(w, r)
+	if !ok {
+		return
+	}
+	var req dto.ScimUser
+	if !readScim(w, r, &req) {
+		return
+	}
+	u, err := h.svc.ReplaceUser(r.Context(), tokenFromContext(r.Context()).RootNodeID, id, req.ToEntity())
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	h.writeUser(w, r, http.StatusOK, u)
+}
+
+// PatchUser godoc
+// @Summary Patch a SCIM user
+// @Description Applies PATCH operations to a provisioned user. Attributes MORIS does not store are ignored.
+// @Tags scim
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID (UUID)"
+// @Param body body dto.ScimPatchRequest true "Patch operations"
+// @Success 200 {object} dto.ScimUser
+// @Failure 400 {object} dto.ScimError
+// @Failure 401 {object} dto.ScimError
+// @Failure 404 {object} dto.ScimError
+// @Failure 409 {object} dto.ScimError
+// @Router /scim/v2/Users/{id} [patch]
+func (h *Handler) PatchUser(w http.ResponseWriter, r *http.Request) {
+	id, ok := resourceID(w, r)
+	if !ok {
+		return
+	}
+	var req dto.ScimPatchRequest
+	if !readScim(w, r, &req) {
+		return
+	}
+	u, err := h.svc.PatchUser(r.Context(), tokenFromContext(r.Context()).RootNodeID, id, req.Operations)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	h.writeUser(w, r, http.StatusOK, u)
+}
+
+// DeleteUser godoc
+// @Summary Deprovision a SCIM user
+// @Description Deactivates the user account and ends its provisioning. The account, person and their history are kept.
+// @Tags scim
+// @Security BearerAuth
+// @Param id path string true "User ID (UUID)"
+// @Success 204
+// @Failure 401 {object} dto.ScimError
+// @Failure 404 {object} dto.ScimError
+// @Router /scim/v2/Users/{id} [delete]
+func (h *Handler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	id, ok := resourceID(w, r)
+	if !ok {
+		return
+	}
+	if err := h.svc.DeleteUser(r.Context(), tokenFromContext(r.Context()).RootNodeID, id); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListGroups godoc
+// @Summary List SCIM groups
+// @Description Lists the roles on the root node of the token and its descendants. The members of a group are the provisioned users with an active membership of the role.
+// @Tags scim
+// @Produce json
+// @Security BearerAuth
+// @Param filter query string false "SCIM filter"
+// @Param startIndex query int false "1-based index of the first result"
+// @Param count query int false "Page size (default 100, max 500)"
+// @Success 200 {object} dto.ScimListResponse[dto.ScimGroup]
+// @Failure 400 {object} dto.ScimError
+// @Failure 401 {object} dto.ScimError
+// @Router /scim/v2/Groups [get]
+func (h *Handler) ListGroups(w http.ResponseWriter, r *http.Request) {
+	q, ok := listQuery(w, r)
+	if !ok {
+		return
+	}
+	page, err := h.svc.ListGroups(r.Context(), tokenFromContext(r.Context()).RootNodeID, q)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	base := baseURL(r)
+	writeScim(w, http.StatusOK, dto.ScimListResponse[dto.ScimGroup]{
+		Schemas:      []string{dto.ScimListResponseSchema},
+		TotalResults: page.TotalResults,
+		StartIndex:   page.StartIndex,
+		ItemsPerPage: len(page.Resources),
+		Resources: lo.Map(page.Resources, func(g scim.Group, _ int) dto.ScimGroup {
+			return dto.ScimGroup{}.FromEntity(g, base+"/Groups/"+g.ID.String(), base+"/Users")
+		}),
+	})
+}
+
+// GetGroup godoc
+// @Summary Get a SCIM group
+// @Tags scim
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Role scope ID (UUID)"
+// @Success 200 {object} dto.ScimGroup
+// @Failure 401 {object} dto.ScimError
+// @Failure 404 {object} dto.ScimError
+// @Router /scim/v2/Groups/{id} [get]
+func (h *Handler) GetGroup(w http.ResponseWriter, r *http.Request) {
+	id, ok := resourceID(w, r)
+	if !ok {
+		return
+	}
+	g, err := h.svc.GetGroup(r.Context(), tokenFromContext(r.Context()).RootNodeID, id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	h.writeGroup(w, r, http.StatusOK, g)
+}
+
+// CreateGroup godoc
+// @Summary Create a SCIM group (not supported)
+// @Description Groups are the roles of organisation nodes and are managed in MORIS.
+// @Tags scim
+// @Security BearerAuth
+// @Failure 501 {object} dto.ScimError
+// @Router /scim/v2/Groups [post]
+func (h *Handler) CreateGroup(w http.ResponseWriter, r *http.Request) {
+	writeScimError(w, http.StatusNotImplemented, "", "groups are the roles of organisation nodes and are managed in MORIS")
+}
+
+// DeleteGroup godoc
+// @Summary Delete a SCIM group (not supported)
+// @Description Groups are the roles of organisation nodes and are managed in MORIS.
+// @Tags scim
+// @Security BearerAuth
+// @Param id path string true "Role scope ID (UUID)"
+// @Failure 501 {object} dto.ScimError
+// @Router /scim/v2/Groups/{id} [delete]
+func (h *Handler) DeleteGroup(w http.ResponseWriter, r *http.Request) {
+	writeScimError(w, http.StatusNotImplemented, "", "groups are the roles of organisation nodes and are managed in MORIS")
+}
+
+// ReplaceGroup godoc
+// @Summary Replace the members of a SCIM group
+// @Description Sets the provisioned users that hold the role. Memberships of people the root node does not provision are left alone.
+// @Tags scim
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Role scope ID (UUID)"
+// @Param body body dto.ScimGroup true "Group"
+// @Success 200 {object} dto.ScimGroup
+// @Failure 400 {object} dto.ScimError
+// @Failure 401 {object} dto.ScimError
+// @Failure 404 {object} dto.ScimError
+// @Router /scim/v2/Groups/{id} [put]
+func (h *Handler) ReplaceGroup(w http.ResponseWriter, r *http.Request) {
+	id, ok := resourceID(w, r)
+	if !ok {
+		return
+	}
+	var req dto.ScimGroup
+	if !readScim(w, r, &req) {
+		return
+	}
+	in, err := req.ToEntity()
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	g, err := h.svc.ReplaceGroup(r.Context(), tokenFromContext(r.Context()).RootNodeID, id, in)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	h.writeGroup(w, r, http.StatusOK, g)
+}
+
+// PatchGroup godoc
+// @Summary Patch the members of a SCIM group
+// @Description Adds, replaces or removes members, e.g. with the path members[value eq "<user id>"].
+// @Tags scim
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Role scope ID (UUID)"
+// @Param body body dto.ScimPatchRequest true "Patch operations"
+// @Success 200 {object} dto.ScimGroup
+// @Failure 400 {object} dto.ScimError
+// @Failure 401 {object} dto.ScimError
+// @Failure 404 {object} dto.ScimError
+// @Router /scim/v2/Groups/{id} [patch]
+func (h *Handler) PatchGroup(w http.ResponseWriter, r *http.Request) {
+	id, ok := resourceID(w, r)
+	if !ok {
+		return
+	}
+	var req dto.ScimPatchRequest
+	if !readScim(w, r, &req) {
+		return
+	}
+	g, err := h.svc.PatchGroup(r.Context(), tokenFromContext(r.Context()).RootNodeID, id, req.Operations)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	h.writeGroup(w, r, http.StatusOK, g)
+}
+
+func (h *Handler) writeUser(w http.ResponseWriter, r *http.Request, status int, u *scim.User) {
+	location := baseURL(r) + "/Users/" + u.ID.String()
+	w.Header().Set("Location", location)
+	writeScim(w, status, dto.ScimUser{}.FromEntity(*u, location))
+}
+
+func (h *Handler) writeGroup(w http.ResponseWriter, r *http.Request, status int, g *scim.Group) {
+	base := baseURL(r)
+	location := base + "/Groups/" + g.ID.String()
+	w.Header().Set("Location", location)
+	writeScim(w, status, dto.ScimGroup{}.FromEntity(*g, location, base+"/Users"))
+}
+
+// baseURL returns the absolute URL of the SCIM API for resource locations
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return scheme + "://" + r.Host + env.Global.APIBasePath + "/scim/v2"
+}
+
+func listQuery(w http.ResponseWriter, r *http.Request) (scimsvc.ListQuery, bool) {
+	q := scimsvc.ListQuery{Filter: r.URL.Query().Get("filter"), StartIndex: 1, Count: defaultCount}
+	if v := r.URL.Query().Get("startIndex"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			writeScimError(w, http.StatusBadRequest, scim.ErrInvalidValue.Error(), "startIndex must be a number")
+			return q, false
+		}
+		q.StartIndex = max(n, 1)
+	}
+	if v := r.URL.Query().Get("count"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			writeScimError(w, http.StatusBadRequest, scim.ErrInvalidValue.Error(), "count must be a number")
+			return q, false
+		}
+		q.Count = min(max(n, 0), maxCount)
+	}
+	return q, true
+}
+
+func resourceID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := httputil.ParseUUIDParam(r, "id")
+	if err != nil {
+		writeScimError(w, http.StatusNotFound, "", "resource not found")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func readScim(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes)).Decode(v); err != nil {
+		writeScimError(w, http.StatusBadRequest, scim.ErrInvalidSyntax.Error(), "invalid request body")
+		return false
+	}
+	return true
+}
+
+func writeScim(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeScimError(w http.ResponseWriter, status int, scimType, detail string) {
+	writeScim(w, status, dto.ScimError{
+		Schemas:  []string{dto.ScimErrorSchema},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	})
+}
+
+// writeServiceError maps the errors of the SCIM service onto SCIM error responses
+func writeServiceError(w http.ResponseWriter, err error) {
+	for _, e := range []error{
+		scim.ErrInvalidFilter, scim.ErrInvalidPath, scim.ErrInvalidValue,
+		scim.ErrInvalidSyntax, scim.ErrNoTarget, scim.ErrMutability,
+	} {
+		if errors.Is(err, e) {
+			writeScimError(w, http.StatusBadRequest, e.Error(), err.Error())
+			return
+		}
+	}
+	switch {
+	case errors.Is(err, scim.ErrNotFound):
+		writeScimError(w, http.StatusNotFound, "", "resource not found")
+	case errors.Is(err, scim.ErrUniqueness), ent.IsConstraintError(err):
+		writeScimError(w, http.StatusConflict, scim.ErrUniqueness.Error(), err.Error())
+	default:
+		detail := err.Error()
+		if env.IsProd() {
+			detail = http.StatusText(http.StatusInternalServerError)
+		}
+		writeScimError(w, http.StatusInternalServerError, "", detail)
+	}
+}
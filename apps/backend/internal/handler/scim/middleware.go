@@ -0,0 +1,48 @@
+package scim
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	scimsvc "github.com/SURF-Innovatie/MORIS/internal/app/scim"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/scim"
+)
+
+type contextKey string
+
+const contextKeyToken contextKey = "scim_token"
+
+// TokenMiddleware authenticates SCIM requests with a bearer token and stores the token in the context
+func TokenMiddleware(svc scimsvc.Service) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scheme, secret, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+			if !ok || !strings.EqualFold(scheme, "bearer") {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="scim"`)
+				writeScimError(w, http.StatusUnauthorized, "", "Authorization header must be in 'Bearer <token>' format")
+				return
+			}
+
+			t, err := svc.Authenticate(r.Context(), strings.TrimSpace(secret))
+			switch {
+			case errors.Is(err, scim.ErrUnauthorized):
+				w.Header().Set("WWW-Authenticate", `Bearer realm="scim"`)
+				writeScimError(w, http.StatusUnauthorized, "", "invalid token")
+				return
+			case err != nil:
+				writeScimError(w, http.StatusInternalServerError, "", err.Error())
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), contextKeyToken, t)))
+		})
+	}
+}
+
+// tokenFromContext returns the token the request was authenticated with
+func tokenFromContext(ctx context.Context) *scim.Token {
+	t, _ := ctx.Value(contextKeyToken).(*scim.Token)
+	return t
+}
@@ -0,0 +1,141 @@
+package scim
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/SURF-Innovatie/MORIS/ent"
+	"github.com/SURF-Innovatie/MORIS/internal/api/dto"
+	"github.com/SURF-Innovatie/MORIS/internal/common/transform"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/scim"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/httputil"
+)
+
+// ListTokens godoc
+// @Summary List the SCIM tokens of a root node
+// @Description Lists the tokens identity management systems use to provision the users and memberships of a root node. Secrets are never returned. Sysadmin only.
+// @Tags scim
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Root node ID (UUID)"
+// @Success 200 {array} dto.ScimTokenResponse
+// @Failure 400 {string} string "invalid node id"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 500 {string} string "internal server error"
+// @Router /organisation-nodes/{id}/scim-tokens [get]
+func (h *Handler) ListTokens(w http.ResponseWriter, r *http.Request) {
+	if !requireSysAdmin(w, r) {
+		return
+	}
+	nodeID, err := httputil.ParseUUIDParam(r, "id")
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusBadRequest, "invalid node id", nil)
+		return
+	}
+
+	tokens, err := h.svc.ListTokens(r.Context(), nodeID)
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+	_ = httputil.WriteJSON(w, http.StatusOK, transform.ToDTOs[dto.ScimTokenResponse](tokens))
+}
+
+// CreateToken godoc
+// @Summary Issue a SCIM token for a root node
+// @Description Issues a bearer token for the SCIM API of a root node. The token is returned only once. Sysadmin only.
+// @Tags scim
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Root node ID (UUID)"
+// @Param body body dto.ScimTokenRequest true "Token details"
+// @Success 201 {object} dto.ScimTokenResponse
+// @Failure 400 {string} string "invalid request / not a root node"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "node not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /organisation-nodes/{id}/scim-tokens [post]
+func (h *Handler) CreateToken(w http.ResponseWriter, r *http.Request) {
+	if !requireSysAdmin(w, r) {
+		return
+	}
+	nodeID, err := httputil.ParseUUIDParam(r, "id")
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusBadRequest, "invalid node id", nil)
+		return
+	}
+	var req dto.ScimTokenRequest
+	if !httputil.ReadJSON(w, r, &req) {
+		return
+	}
+
+	t, secret, err := h.svc.CreateToken(r.Context(), nodeID, req.Name)
+	switch {
+	case err == nil:
+		resp := transform.ToDTOItem[dto.ScimTokenResponse](*t)
+		resp.Token = secret
+		_ = httputil.WriteJSON(w, http.StatusCreated, resp)
+	case errors.Is(err, scim.ErrInvalidValue), errors.Is(err, scim.ErrNotRootNode):
+		httputil.WriteError(w, r, http.StatusBadRequest, err.Error(), nil)
+	case ent.IsNotFound(err):
+		httputil.WriteError(w, r, http.StatusNotFound, "node not found", nil)
+	default:
+		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+	}
+}
+
+// DeleteToken godoc
+// @Summary Revoke a SCIM token
+// @Description Revokes a SCIM token of a root node. Sysadmin only.
+// @Tags scim
+// @Security BearerAuth
+// @Param id path string true "Root node ID (UUID)"
+// @Param tokenId path string true "Token ID (UUID)"
+// @Success 204
+// @Failure 400 {string} string "invalid id"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "token not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /organisation-nodes/{id}/scim-tokens/{tokenId} [delete]
+func (h *Handler) DeleteToken(w http.ResponseWriter, r *http.Request) {
+	if !requireSysAdmin(w, r) {
+		return
+	}
+	nodeID, err := httputil.ParseUUIDParam(r, "id")
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusBadRequest, "invalid node id", nil)
+		return
+	}
+	tokenID, err := httputil.ParseUUIDParam(r, "tokenId")
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusBadRequest, "invalid token id", nil)
+		return
+	}
+
+	err = h.svc.DeleteToken(r.Context(), nodeID, tokenID)
+	switch {
+	case err == nil:
+		w.WriteHeader(http.StatusNoContent)
+	case errors.Is(err, scim.ErrNotFound):
+		httputil.WriteError(w, r, http.StatusNotFound, "token not found", nil)
+	default:
+		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+	}
+}
+
+func requireSysAdmin(w http.ResponseWriter, r *http.Request) bool {
+	user, ok := httputil.GetUserFromContext(r.Context())
+	if !ok {
+		httputil.WriteError(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return false
+	}
+	if !user.User.IsSysAdmin {
+		httputil.WriteError(w, r, http.StatusForbidden, "forbidden: only sysadmin can manage scim tokens", nil)
+		return false
+	}
+	return true
+}
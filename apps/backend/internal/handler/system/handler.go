@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/SURF-Innovatie/MORIS/internal/api/dto"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/locale"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/project/events"
 	"github.com/SURF-Innovatie/MORIS/internal/infra/httputil"
 	"github.com/samber/lo"
@@ -47,21 +48,26 @@ type EventTypeInfo struct {
 
 // ListEventTypes godoc
 // @Summary List all available event types
-// @Description Returns all event types that can be configured for role permissions
+// @Description Returns all event types that can be configured for role permissions.
+// @Description Friendly names are in the language of the Accept-Language header ("en" or "nl", default "en").
 // @Tags system
 // @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Param Accept-Language header string false "Preferred language, e.g. nl-NL,nl;q=0.9,en;q=0.8"
 // @Success 200 {array} EventTypeInfo
 // @Router /event-types [get]
 func (h *Handler) ListEventTypes(w http.ResponseWriter, r *http.Request) {
-	metas := events.GetAllMetas()
+	lang := locale.FromAcceptLanguage(r.Header.Get("Accept-Language"))
+	metas := events.GetAllMetasIn(lang)
 	out := lo.Map(metas, func(m events.EventMeta, _ int) EventTypeInfo {
 		return EventTypeInfo{
 			Type:         m.Type,
 			FriendlyName: m.FriendlyName,
 		}
 	})
+	w.Header().Set("Content-Language", string(lang))
+	w.Header().Add("Vary", "Accept-Language")
 	_ = httputil.WriteJSON(w, http.StatusOK, out)
 }
 
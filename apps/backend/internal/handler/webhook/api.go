@@ -0,0 +1,16 @@
+package webhook
+
+import "github.com/go-chi/chi/v5"
+
+func MountRoutes(r chi.Router, h *Handler) {
+	r.Route("/webhook-subscriptions", func(r chi.Router) {
+		r.Get("/", h.List)
+		r.Post("/", h.Create)
+		r.Get("/{id}", h.Get)
+		r.Put("/{id}", h.Update)
+		r.Delete("/{id}", h.Delete)
+		r.Post("/{id}/rotate-secret", h.RotateSecret)
+		r.Get("/{id}/deliveries", h.ListDeliveries)
+		r.Post("/{id}/deliveries/replay", h.ReplayFailedDeliveries)
+	})
+}
@@ -0,0 +1,19 @@
+package di
+
+import (
+	rbacsvc "github.com/SURF-Innovatie/MORIS/internal/app/organisation/rbac"
+	"github.com/SURF-Innovatie/MORIS/internal/app/webhook"
+	webhookhandler "github.com/SURF-Innovatie/MORIS/internal/handler/webhook"
+	"github.com/samber/do/v2"
+)
+
+var Package = do.Package(
+	do.Lazy(provideWebhookHandler),
+)
+
+func provideWebhookHandler(i do.Injector) (*webhookhandler.Handler, error) {
+	subscriptions := do.MustInvoke[webhook.SubscriptionService](i)
+	webhookSvc := do.MustInvoke[webhook.Service](i)
+	rbac := do.MustInvoke[rbacsvc.Service](i)
+	return webhookhandler.NewHandler(subscriptions, webhookSvc, rbac), nil
+}
@@ -0,0 +1,356 @@
+package webhook
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/SURF-Innovatie/MORIS/ent"
+	"github.com/SURF-Innovatie/MORIS/internal/api/dto"
+	rbacsvc "github.com/SURF-Innovatie/MORIS/internal/app/organisation/rbac"
+	"github.com/SURF-Innovatie/MORIS/internal/app/webhook"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation/rbac"
+	webhookdomain "github.com/SURF-Innovatie/MORIS/internal/domain/webhook"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/httputil"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// Handler handles HTTP requests for webhook subscriptions
+type Handler struct {
+	subscriptions webhook.SubscriptionService
+	webhookSvc    webhook.Service
+	rbac          rbacsvc.Service
+}
+
+// NewHandler creates a new webhook subscription handler
+func NewHandler(subscriptions webhook.SubscriptionService, webhookSvc webhook.Service, rbac rbacsvc.Service) *Handler {
+	return &Handler{subscriptions: subscriptions, webhookSvc: webhookSvc, rbac: rbac}
+}
+
+// List godoc
+// @Summary List webhook subscriptions of an organisation node
+// @Description Retrieves the webhook subscriptions scoped to an organisation node. Requires the manage_policies permission on the node.
+// @Tags webhook-subscriptions
+// @Produce json
+// @Security BearerAuth
+// @Param org_node_id query string true "Organisation Node ID (UUID)"
+// @Success 200 {array} dto.WebhookSubscriptionResponse
+// @Failure 400 {string} string "invalid org node id"
+// @Failure 403 {string} string "forbidden"
+// @Failure 500 {string} string "internal server error"
+// @Router /webhook-subscriptions [get]
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	orgNodeID, err := uuid.Parse(r.URL.Query().Get("org_node_id"))
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusBadRequest, "invalid org node id", nil)
+		return
+	}
+	if !h.requireManagePolicies(w, r, orgNodeID) {
+		return
+	}
+
+	subs, err := h.subscriptions.ListForOrgNode(r.Context(), orgNodeID)
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	resp := lo.Map(subs, func(s webhookdomain.Subscription, _ int) dto.WebhookSubscriptionResponse {
+		var r dto.WebhookSubscriptionResponse
+		r.FromEntity(&s)
+		return r
+	})
+	_ = httputil.WriteJSON(w, http.StatusOK, resp)
+}
+
+// Create godoc
+// @Summary Create a webhook subscription
+// @Description Subscribes an endpoint to the events of projects owned by an organisation node (and by default its descendants). Events of private projects are not delivered. The signing secret is returned only once. Requires the manage_policies permission on the node.
+// @Tags webhook-subscriptions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body dto.WebhookSubscriptionRequest true "Subscription details"
+// @Success 201 {object} dto.WebhookSubscriptionResponse
+// @Failure 400 {string} string "invalid request"
+// @Failure 403 {string} string "forbidden"
+// @Failure 500 {string} string "internal server error"
+// @Router /webhook-subscriptions [post]
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	var req dto.WebhookSubscriptionRequest
+	if !httputil.ReadJSON(w, r, &req) {
+		return
+	}
+	if !h.requireManagePolicies(w, r, req.OrgNodeID) {
+		return
+	}
+
+	sub := requestToEntity(req)
+	sub.OrgNodeID = req.OrgNodeID
+	if user, ok := httputil.GetUserFromContext(r.Context()); ok {
+		sub.CreatedBy = &user.User.ID
+	}
+
+	created, err := h.subscriptions.Create(r.Context(), sub)
+	if err != nil {
+		writeSubscriptionError(w, r, err)
+		return
+	}
+
+	var resp dto.WebhookSubscriptionResponse
+	resp.FromEntity(created)
+	resp.Secret = &created.Secret
+	_ = httputil.WriteJSON(w, http.StatusCreated, resp)
+}
+
+// Get godoc
+// @Summary Get a webhook subscription
+// @Tags webhook-subscriptions
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Subscription ID (UUID)"
+// @Success 200 {object} dto.WebhookSubscriptionResponse
+// @Failure 400 {string} string "invalid subscription id"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "subscription not found"
+// @Router /webhook-subscriptions/{id} [get]
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	sub, ok := h.loadSubscription(w, r)
+	if !ok {
+		return
+	}
+
+	var resp dto.WebhookSubscriptionResponse
+	resp.FromEntity(sub)
+	_ = httputil.WriteJSON(w, http.StatusOK, resp)
+}
+
+// Update godoc
+// @Summary Update a webhook subscription
+// @Description Updates the name, event types, endpoint and enabled flag of a subscription. The organisation node and secret are not changed.
+// @Tags webhook-subscriptions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Subscription ID (UUID)"
+// @Param body body dto.WebhookSubscriptionRequest true "Subscription details"
+// @Success 200 {object} dto.WebhookSubscriptionResponse
+// @Failure 400 {string} string "invalid request"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "subscription not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /webhook-subscriptions/{id} [put]
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	sub, ok := h.loadSubscription(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.WebhookSubscriptionRequest
+	if !httputil.ReadJSON(w, r, &req) {
+		return
+	}
+
+	updated, err := h.subscriptions.Update(r.Context(), sub.ID, requestToEntity(req))
+	if err != nil {
+		writeSubscriptionError(w, r, err)
+		return
+	}
+
+	var resp dto.WebhookSubscriptionResponse
+	resp.FromEntity(updated)
+	_ = httputil.WriteJSON(w, http.StatusOK, resp)
+}
+
+// Delete godoc
+// @Summary Delete a webhook subscription
+// @Description Deletes a subscription together with its delivery log
+// @Tags webhook-subscriptions
+// @Security BearerAuth
+// @Param id path string true "Subscription ID (UUID)"
+// @Success 204 "No Content"
+// @Failure 400 {string} string "invalid subscription id"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "subscription not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /webhook-subscriptions/{id} [delete]
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	sub, ok := h.loadSubscription(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.subscriptions.Delete(r.Context(), sub.ID); err != nil {
+		writeSubscriptionError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RotateSecret godoc
+// @Summary Rotate the signing secret of a webhook subscription
+// @Description Generates a new signing secret, which is returned only once. For 24 hours deliveries, including those still pending, carry signatures with both the new and the old secret, separated by a comma in X-MORIS-Signature.
+// @Tags webhook-subscriptions
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Subscription ID (UUID)"
+// @Success 200 {object} dto.WebhookSubscriptionResponse
+// @Failure 400 {string} string "invalid subscription id"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "subscription not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /webhook-subscriptions/{id}/rotate-secret [post]
+func (h *Handler) RotateSecret(w http.ResponseWriter, r *http.Request) {
+	sub, ok := h.loadSubscription(w, r)
+	if !ok {
+		return
+	}
+
+	rotated, err := h.subscriptions.RotateSecret(r.Context(), sub.ID)
+	if err != nil {
+		writeSubscriptionError(w, r, err)
+		return
+	}
+
+	var resp dto.WebhookSubscriptionResponse
+	resp.FromEntity(rotated)
+	resp.Secret = &rotated.Secret
+	_ = httputil.WriteJSON(w, http.StatusOK, resp)
+}
+
+// ListDeliveries godoc
+// @Summary List the deliveries of a webhook subscription
+// @Description Retrieves the delivery log of a subscription, newest first. Single deliveries can be replayed via /webhook-deliveries/{id}/replay.
+// @Tags webhook-subscriptions
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Subscription ID (UUID)"
+// @Param status query string false "Filter by status (pending, succeeded, failed)"
+// @Success 200 {array} dto.WebhookDeliveryResponse
+// @Failure 400 {string} string "invalid subscription id"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "subscription not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /webhook-subscriptions/{id}/deliveries [get]
+func (h *Handler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	sub, ok := h.loadSubscription(w, r)
+	if !ok {
+		return
+	}
+
+	var status *webhookdomain.DeliveryStatus
+	if s := r.URL.Query().Get("status"); s != "" {
+		st := webhookdomain.DeliveryStatus(s)
+		switch st {
+		case webhookdomain.DeliveryPending, webhookdomain.DeliverySucceeded, webhookdomain.DeliveryFailed:
+			status = &st
+		default:
+			httputil.WriteError(w, r, http.StatusBadRequest, "invalid status", nil)
+			return
+		}
+	}
+
+	deliveries, err := h.webhookSvc.ListForSubscription(r.Context(), sub.ID, status)
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	resp := lo.Map(deliveries, func(d webhookdomain.Delivery, _ int) dto.WebhookDeliveryResponse {
+		var r dto.WebhookDeliveryResponse
+		r.FromEntity(&d)
+		return r
+	})
+	_ = httputil.WriteJSON(w, http.StatusOK, resp)
+}
+
+// ReplayFailedDeliveries godoc
+// @Summary Replay failed deliveries of a webhook subscription
+// @Description Re-queues all failed deliveries of a subscription for immediate delivery
+// @Tags webhook-subscriptions
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Subscription ID (UUID)"
+// @Success 200 {object} dto.WebhookReplayResponse
+// @Failure 400 {string} string "invalid subscription id"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "subscription not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /webhook-subscriptions/{id}/deliveries/replay [post]
+func (h *Handler) ReplayFailedDeliveries(w http.ResponseWriter, r *http.Request) {
+	sub, ok := h.loadSubscription(w, r)
+	if !ok {
+		return
+	}
+
+	n, err := h.webhookSvc.ReplayFailedForSubscription(r.Context(), sub.ID)
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+	_ = httputil.WriteJSON(w, http.StatusOK, dto.WebhookReplayResponse{Requeued: n})
+}
+
+// loadSubscription reads the subscription of the request path and checks that the current user manages its node
+func (h *Handler) loadSubscription(w http.ResponseWriter, r *http.Request) (*webhookdomain.Subscription, bool) {
+	id, err := httputil.ParseUUIDParam(r, "id")
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusBadRequest, "invalid subscription id", nil)
+		return nil, false
+	}
+
+	sub, err := h.subscriptions.Get(r.Context(), id)
+	if err != nil {
+		writeSubscriptionError(w, r, err)
+		return nil, false
+	}
+	if !h.requireManagePolicies(w, r, sub.OrgNodeID) {
+		return nil, false
+	}
+	return sub, true
+}
+
+// requireManagePolicies checks that the current user holds the manage_policies permission on the organisation node
+func (h *Handler) requireManagePolicies(w http.ResponseWriter, r *http.Request, nodeID uuid.UUID) bool {
+	user, ok := httputil.GetUserFromContext(r.Context())
+	if !ok {
+		httputil.WriteError(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return false
+	}
+	if user.User.IsSysAdmin {
+		return true
+	}
+
+	hasAccess, err := h.rbac.HasPermission(r.Context(), user.Person.ID, nodeID, rbac.PermissionManagePolicies)
+	if err != nil {
+		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+		return false
+	}
+	if !hasAccess {
+		httputil.WriteError(w, r, http.StatusForbidden, "forbidden: manage_policies permission on the subscription's organisation node required", nil)
+		return false
+	}
+	return true
+}
+
+func requestToEntity(req dto.WebhookSubscriptionRequest) webhookdomain.Subscription {
+	return webhookdomain.Subscription{
+		Name:               req.Name,
+		IncludeDescendants: lo.FromPtrOr(req.IncludeDescendants, true),
+		EventTypes:         req.EventTypes,
+		URL:                req.URL,
+		Enabled:            lo.FromPtrOr(req.Enabled, true),
+	}
+}
+
+// writeSubscriptionError maps subscription service errors to HTTP responses
+func writeSubscriptionError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, webhookdomain.ErrInvalidSubscription):
+		httputil.WriteError(w, r, http.StatusBadRequest, err.Error(), nil)
+	case ent.IsNotFound(err):
+		httputil.WriteError(w, r, http.StatusNotFound, "subscription not found", nil)
+	default:
+		httputil.WriteError(w, r, http.StatusInternalServerError, err.Error(), nil)
+	}
+}
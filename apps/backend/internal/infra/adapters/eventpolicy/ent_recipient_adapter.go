@@ -3,12 +3,14 @@ package eventpolicy
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/SURF-Innovatie/MORIS/ent"
 	en "github.com/SURF-Innovatie/MORIS/ent/event"
 	"github.com/SURF-Innovatie/MORIS/ent/membership"
 	"github.com/SURF-Innovatie/MORIS/ent/rolescope"
 	entuser "github.com/SURF-Innovatie/MORIS/ent/user"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/locale"
 	events2 "github.com/SURF-Innovatie/MORIS/internal/domain/project/events"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
@@ -47,8 +49,13 @@ func (r *RecipientAdapter) ResolveOrgRole(ctx context.Context, roleID uuid.UUID,
 	// NOTE: currently ignores orgNodeID. Either:
 	// (A) clarify semantics and filter by scopes rooted in orgNodeID+ancestors, or
 	// (B) remove orgNodeID param from the interface if it’s not needed.
+	now := time.Now()
 	memberships, err := r.client.Membership.Query().
-		Where(membership.HasRoleScopeWith(rolescope.RoleIDEQ(roleID))).
+		Where(
+			membership.HasRoleScopeWith(rolescope.RoleIDEQ(roleID)),
+			membership.Or(membership.StartDateIsNil(), membership.StartDateLTE(now)),
+			membership.Or(membership.EndDateIsNil(), membership.EndDateGT(now)),
+		).
 		All(ctx)
 	if err != nil {
 		return nil, err
@@ -86,6 +93,19 @@ func (r *RecipientAdapter) ResolveDynamic(ctx context.Context, dynType string, p
 	}
 }
 
+func (r *RecipientAdapter) ResolveLanguages(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]locale.Language, error) {
+	rows, err := r.client.User.Query().
+		Where(entuser.IDIn(userIDs...)).
+		Select(entuser.FieldID, entuser.FieldLanguage).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return lo.SliceToMap(rows, func(u *ent.User) (uuid.UUID, locale.Language) {
+		return u.ID, locale.Language(u.Language)
+	}), nil
+}
+
 func (r *RecipientAdapter) projectMembers(ctx context.Context, projectID uuid.UUID) ([]uuid.UUID, error) {
 	evts, err := r.client.Event.Query().
 		Where(en.TypeEQ(events2.ProjectRoleAssignedType), en.ProjectIDEQ(projectID)).
@@ -0,0 +1,16 @@
+package di
+
+import (
+	"github.com/SURF-Innovatie/MORIS/ent"
+	webhookadapter "github.com/SURF-Innovatie/MORIS/internal/infra/adapters/webhook"
+	"github.com/samber/do/v2"
+)
+
+var Package = do.Package(
+	do.Lazy(provideSecretAdapter),
+)
+
+func provideSecretAdapter(i do.Injector) (*webhookadapter.SecretAdapter, error) {
+	cli := do.MustInvoke[*ent.Client](i)
+	return webhookadapter.NewSecretAdapter(cli), nil
+}
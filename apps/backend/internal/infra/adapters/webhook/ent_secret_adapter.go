@@ -0,0 +1,45 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/ent"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/webhook"
+)
+
+var ErrNoSecret = errors.New("webhook_secret_missing")
+
+// SecretAdapter reads signing secrets from the subscription or policy that queued a delivery,
+// so rotating a secret also applies to deliveries that are still pending.
+type SecretAdapter struct {
+	client *ent.Client
+	now    func() time.Time
+}
+
+func NewSecretAdapter(client *ent.Client) *SecretAdapter {
+	return &SecretAdapter{client: client, now: time.Now}
+}
+
+func (a *SecretAdapter) SecretsFor(ctx context.Context, d webhook.Delivery) ([]string, error) {
+	if d.SubscriptionID != nil {
+		row, err := a.client.WebhookSubscription.Get(ctx, *d.SubscriptionID)
+		if err != nil {
+			return nil, err
+		}
+		return (&webhook.Subscription{}).FromEnt(row).SigningSecrets(a.now()), nil
+	}
+	if d.PolicyID == nil {
+		return nil, ErrNoSecret
+	}
+
+	row, err := a.client.EventPolicy.Get(ctx, *d.PolicyID)
+	if err != nil {
+		return nil, err
+	}
+	if row.WebhookSecret == nil || *row.WebhookSecret == "" {
+		return nil, ErrNoSecret
+	}
+	return []string{*row.WebhookSecret}, nil
+}
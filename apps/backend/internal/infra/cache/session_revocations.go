@@ -0,0 +1,41 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisSessionRevocations records revoked sessions until their access tokens have expired
+type RedisSessionRevocations struct {
+	rdb *redis.Client
+}
+
+func NewRedisSessionRevocations(rdb *redis.Client) *RedisSessionRevocations {
+	return &RedisSessionRevocations{rdb: rdb}
+}
+
+func (c *RedisSessionRevocations) key(id uuid.UUID) string {
+	return fmt.Sprintf("session_revoked:%s", id.String())
+}
+
+func (c *RedisSessionRevocations) Revoke(ctx context.Context, sessionID uuid.UUID, ttl time.Duration) error {
+	if c.rdb == nil {
+		return ErrCacheNotInitialized
+	}
+	return c.rdb.Set(ctx, c.key(sessionID), "1", ttl).Err()
+}
+
+func (c *RedisSessionRevocations) IsRevoked(ctx context.Context, sessionID uuid.UUID) (bool, error) {
+	if c.rdb == nil {
+		return false, ErrCacheNotInitialized
+	}
+	n, err := c.rdb.Exists(ctx, c.key(sessionID)).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
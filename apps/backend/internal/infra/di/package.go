@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/SURF-Innovatie/MORIS/ent"
+	authapp "github.com/SURF-Innovatie/MORIS/internal/app/auth"
 	"github.com/SURF-Innovatie/MORIS/internal/app/project/command"
 	"github.com/SURF-Innovatie/MORIS/internal/infra/cache"
 	"github.com/SURF-Innovatie/MORIS/internal/infra/env"
@@ -24,6 +25,7 @@ var Package = do.Package(
 	do.Lazy(provideProjectCache),
 	do.Lazy(provideUserCache),
 	do.Lazy(provideCacheRefresher),
+	do.Lazy(provideSessionRevocations),
 
 	// tx, ent providers, etc
 	do.Lazy(provideTxManager),
@@ -60,6 +62,11 @@ func provideUserCache(i do.Injector) (cache.UserCache, error) {
 	return cache.NewRedisUserCache(rdb, 1*time.Hour), nil
 }
 
+func provideSessionRevocations(i do.Injector) (authapp.RevocationStore, error) {
+	rdb := do.MustInvoke[*redis.Client](i)
+	return cache.NewRedisSessionRevocations(rdb), nil
+}
+
 func provideCacheRefresher(i do.Injector) (cache.ProjectCacheRefresher, error) {
 	eventRepo := do.MustInvoke[*eventrepo.EntRepo](i)
 	pc := do.MustInvoke[cache.ProjectCache](i)
@@ -3,6 +3,7 @@ package env
 import (
 	"os"
 	"strings"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 	"github.com/rs/zerolog/log"
@@ -23,6 +24,14 @@ type Config struct {
 	Port          string `env:"PORT" env-required:"true"`
 	RAiDAPIKey    string `env:"RAID_API_KEY" env-default:"test-key"`
 	APIBasePath   string `env:"API_BASE_PATH" env-default:"/api"`
+	// FrontendURL is the base of deep links in emails
+	FrontendURL string `env:"FRONTEND_URL" env-default:"http://127.0.0.1:3000"`
+	// AccessTokenTTL is the lifetime of access tokens; sessions are kept alive with refresh tokens
+	AccessTokenTTL time.Duration `env:"ACCESS_TOKEN_TTL" env-default:"15m"`
+	// RefreshTokenTTL is how long a session lasts without being refreshed
+	RefreshTokenTTL time.Duration `env:"REFRESH_TOKEN_TTL" env-default:"720h"`
+	// ImpersonationTTL is how long a sysadmin may act as another user
+	ImpersonationTTL time.Duration `env:"IMPERSONATION_TTL" env-default:"30m"`
 
 	ORCID      ORCIDConfig      `env-prefix:"ORCID_"`
 	Surfconext SurfconextConfig `env-prefix:"SURFCONEXT_"`
@@ -30,6 +39,7 @@ type Config struct {
 	Crossref   CrossrefConfig   `env-prefix:"CROSSREF_"`
 	NWO        NWOConfig        `env-prefix:"NWO_"`
 	KVK        KVKConfig        `env-prefix:"KVK_"`
+	SMTP       SMTPConfig       `env-prefix:"SMTP_"`
 }
 
 type ORCIDConfig struct {
@@ -69,6 +79,14 @@ type KVKConfig struct {
 	APIKey  string `env:"API_KEY"`
 }
 
+type SMTPConfig struct {
+	Host     string `env:"HOST"`
+	Port     int    `env:"PORT" env-default:"1025"`
+	Username string `env:"USERNAME"`
+	Password string `env:"PASSWORD"`
+	From     string `env:"FROM" env-default:"MORIS <no-reply@moris.local>"`
+}
+
 var Global Config
 
 func init() {
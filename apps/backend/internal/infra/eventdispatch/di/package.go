@@ -14,10 +14,12 @@ var Package = do.Package(
 func provideEventPublisher(i do.Injector) (event.Publisher, error) {
 	policyHandler := do.MustInvoke[*events.Handler](i)
 	execHandler := do.MustInvoke[*events.PolicyExecutionHandler](i)
+	subscriptionHandler := do.MustInvoke[*events.WebhookSubscriptionHandler](i)
 
 	notificationHandlers := []eventdispatch.NotificationHandler{
 		policyHandler,
 		execHandler,
+		subscriptionHandler,
 	}
 
 	cacheHandler := do.MustInvoke[*events.CacheRefreshHandler](i)
@@ -3,6 +3,7 @@ package di
 import (
 	"github.com/SURF-Innovatie/MORIS/ent"
 	"github.com/SURF-Innovatie/MORIS/internal/app/eventpolicy"
+	"github.com/SURF-Innovatie/MORIS/internal/app/webhook"
 	"github.com/SURF-Innovatie/MORIS/internal/infra/cache"
 	"github.com/SURF-Innovatie/MORIS/internal/infra/handlers/events"
 	eventrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/event"
@@ -13,6 +14,7 @@ var Package = do.Package(
 	do.Lazy(provideEventPolicyHandler),
 	do.Lazy(providePolicyExecutionHandler),
 	do.Lazy(provideCacheRefreshHandler),
+	do.Lazy(provideWebhookSubscriptionHandler),
 )
 
 func providePolicyExecutionHandler(i do.Injector) (*events.PolicyExecutionHandler, error) {
@@ -31,3 +33,9 @@ func provideCacheRefreshHandler(i do.Injector) (*events.CacheRefreshHandler, err
 	refresher := do.MustInvoke[cache.ProjectCacheRefresher](i)
 	return events.NewCacheRefreshHandler(refresher), nil
 }
+
+func provideWebhookSubscriptionHandler(i do.Injector) (*events.WebhookSubscriptionHandler, error) {
+	subscriptions := do.MustInvoke[webhook.SubscriptionService](i)
+	entRepo := do.MustInvoke[*eventrepo.EntRepo](i)
+	return events.NewWebhookSubscriptionHandler(subscriptions, entRepo), nil
+}
@@ -2,12 +2,15 @@ package events
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/SURF-Innovatie/MORIS/ent"
 	"github.com/SURF-Innovatie/MORIS/internal/app/eventpolicy"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/policy"
 	events2 "github.com/SURF-Innovatie/MORIS/internal/domain/project/events"
+	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
 )
 
 // Handler handles EventPolicyAdded, EventPolicyRemoved, and EventPolicyUpdated events
@@ -22,6 +25,11 @@ func NewEventPolicyHandler(policySvc eventpolicy.Service, cli *ent.Client) *Hand
 }
 
 func (h *Handler) Handle(ctx context.Context, event events2.Event) error {
+	// Policies only take effect once their event is applied; approval publishes the event again
+	if st := event.GetStatus(); st == events2.StatusPending || st == events2.StatusRejected {
+		return nil
+	}
+
 	switch e := event.(type) {
 	case *events2.EventPolicyAdded:
 		return h.handlePolicyAdded(ctx, e)
@@ -33,36 +41,58 @@ func (h *Handler) Handle(ctx context.Context, event events2.Event) error {
 	return nil
 }
 
-func (h *Handler) handlePolicyAdded(ctx context.Context, e *events2.EventPolicyAdded) error {
-	projectID := e.AggregateID()
-
-	policy := policy.EventPolicy{
-		ID:                      e.PolicyID,
-		Name:                    e.Name,
-		Description:             e.Description,
-		EventTypes:              e.EventTypes,
-		ActionType:              policy.ActionType(e.ActionType),
-		RecipientUserIDs:        e.RecipientUserIDs,
-		RecipientProjectRoleIDs: e.RecipientProjectRoleIDs,
-		RecipientOrgRoleIDs:     e.RecipientOrgRoleIDs,
-		RecipientDynamic:        e.RecipientDynamic,
-		ProjectID:               &projectID,
-		Enabled:                 e.Enabled,
-	}
+// Rebuild replaces the stored policies of a project with the ones defined by its event history
+func (h *Handler) Rebuild(ctx context.Context, projectID uuid.UUID, history []events2.Event) error {
+	want := events2.ProjectPolicies(history)
+	wantIDs := lo.SliceToMap(want, func(p policy.EventPolicy) (uuid.UUID, bool) { return p.ID, true })
 
-	_, err := h.policySvc.Create(ctx, policy)
+	existing, err := h.policySvc.ListForProject(ctx, projectID, uuid.Nil, false)
 	if err != nil {
+		return err
+	}
+	for _, p := range existing {
+		if !wantIDs[p.ID] {
+			if err := h.policySvc.Delete(ctx, p.ID); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, p := range want {
+		if err := h.upsert(ctx, p); err != nil {
+			return err
+		}
+	}
+
+	log.Info().Msgf("Rebuilt %d event policies for project %s", len(want), projectID)
+	return nil
+}
+
+func (h *Handler) handlePolicyAdded(ctx context.Context, e *events2.EventPolicyAdded) error {
+	if err := h.upsert(ctx, e.ToPolicy()); err != nil {
 		log.Error().Err(err).Msg("Failed to create policy from event")
 		return err
 	}
 
-	log.Info().Msgf("Event policy '%s' created for project %s", e.Name, projectID)
+	log.Info().Msgf("Event policy '%s' created for project %s", e.Name, e.AggregateID())
 	return nil
 }
 
 func (h *Handler) handlePolicyRemoved(ctx context.Context, e *events2.EventPolicyRemoved) error {
-	err := h.policySvc.Delete(ctx, e.PolicyID)
+	existing, err := h.policySvc.GetByID(ctx, e.PolicyID)
+	if ent.IsNotFound(err) {
+		return nil
+	}
 	if err != nil {
+		return err
+	}
+	if err := checkOwnedByProject(existing, e.AggregateID()); err != nil {
+		log.Error().Err(err).Msgf("Refusing to remove policy %s from project %s", e.PolicyID, e.AggregateID())
+		return err
+	}
+
+	err = h.policySvc.Delete(ctx, e.PolicyID)
+	if err != nil && !ent.IsNotFound(err) {
 		log.Error().Err(err).Msg("Failed to delete policy from event")
 		return err
 	}
@@ -72,28 +102,38 @@ func (h *Handler) handlePolicyRemoved(ctx context.Context, e *events2.EventPolic
 }
 
 func (h *Handler) handlePolicyUpdated(ctx context.Context, e *events2.EventPolicyUpdated) error {
-	projectID := e.AggregateID()
-
-	policy := policy.EventPolicy{
-		ID:                      e.PolicyID,
-		Name:                    e.Name,
-		Description:             e.Description,
-		EventTypes:              e.EventTypes,
-		ActionType:              policy.ActionType(e.ActionType),
-		RecipientUserIDs:        e.RecipientUserIDs,
-		RecipientProjectRoleIDs: e.RecipientProjectRoleIDs,
-		RecipientOrgRoleIDs:     e.RecipientOrgRoleIDs,
-		RecipientDynamic:        e.RecipientDynamic,
-		ProjectID:               &projectID,
-		Enabled:                 e.Enabled,
-	}
-
-	_, err := h.policySvc.Update(ctx, e.PolicyID, policy)
-	if err != nil {
+	if err := h.upsert(ctx, e.ToPolicy()); err != nil {
 		log.Error().Err(err).Msg("Failed to update policy from event")
 		return err
 	}
 
-	log.Info().Msgf("Event policy '%s' updated for project %s", e.Name, projectID)
+	log.Info().Msgf("Event policy '%s' updated for project %s", e.Name, e.AggregateID())
 	return nil
 }
+
+// upsert stores a policy under the ID carried by its event, so that handling an event twice is harmless
+func (h *Handler) upsert(ctx context.Context, p policy.EventPolicy) error {
+	existing, err := h.policySvc.GetByID(ctx, p.ID)
+	switch {
+	case err == nil:
+		if err := checkOwnedByProject(existing, *p.ProjectID); err != nil {
+			return err
+		}
+		_, err = h.policySvc.Update(ctx, p.ID, p)
+	case ent.IsNotFound(err):
+		_, err = h.policySvc.Create(ctx, p)
+	}
+	return err
+}
+
+// checkOwnedByProject ensures that project events only change the project's own policies,
+// so that inherited (and in particular locked) organisation policies cannot be disabled or removed
+func checkOwnedByProject(p *policy.EventPolicy, projectID uuid.UUID) error {
+	if p.ProjectID != nil && *p.ProjectID == projectID {
+		return nil
+	}
+	if p.Locked {
+		return fmt.Errorf("%w: policy %s is locked", policy.ErrPolicyLocked, p.ID)
+	}
+	return fmt.Errorf("policy %s does not belong to project %s", p.ID, projectID)
+}
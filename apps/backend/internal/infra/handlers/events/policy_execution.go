@@ -33,7 +33,7 @@ func (h *PolicyExecutionHandler) Handle(ctx context.Context, evt events2.Event)
 		return nil
 	}
 
-	project, err := h.loadProject(ctx, projectID)
+	project, err := loadProject(ctx, h.eventRepo, projectID)
 	if err != nil {
 		return err
 	}
@@ -44,10 +44,11 @@ func (h *PolicyExecutionHandler) Handle(ctx context.Context, evt events2.Event)
 	return h.evaluator.EvaluateAndExecute(ctx, evt, project)
 }
 
-func (h *PolicyExecutionHandler) loadProject(ctx context.Context, projectID uuid.UUID) (*project.Project, error) {
-	evts, version, err := h.eventRepo.Load(ctx, projectID)
+// loadProject rebuilds the current state of a project from its events; it returns nil for unknown projects
+func loadProject(ctx context.Context, eventRepo *eventrepo.EntRepo, projectID uuid.UUID) (*project.Project, error) {
+	evts, version, err := eventRepo.Load(ctx, projectID)
 	if err != nil {
-		log.Error().Err(err).Msgf("PolicyExecutionHandler: failed to load events for project %s", projectID)
+		log.Error().Err(err).Msgf("failed to load events for project %s", projectID)
 		return nil, err
 	}
 	if len(evts) == 0 {
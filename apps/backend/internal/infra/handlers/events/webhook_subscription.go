@@ -0,0 +1,51 @@
+package events
+
+import (
+	"context"
+
+	"github.com/SURF-Innovatie/MORIS/internal/app/webhook"
+	events2 "github.com/SURF-Innovatie/MORIS/internal/domain/project/events"
+	eventrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/event"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// WebhookSubscriptionHandler queues deliveries of applied events to the webhook
+// subscriptions of the project's organisation node and its ancestors
+type WebhookSubscriptionHandler struct {
+	subscriptions webhook.SubscriptionService
+	eventRepo     *eventrepo.EntRepo
+}
+
+func NewWebhookSubscriptionHandler(subscriptions webhook.SubscriptionService, eventRepo *eventrepo.EntRepo) *WebhookSubscriptionHandler {
+	return &WebhookSubscriptionHandler{subscriptions: subscriptions, eventRepo: eventRepo}
+}
+
+func (h *WebhookSubscriptionHandler) Handle(ctx context.Context, evt events2.Event) error {
+	// Only applied events are delivered; approval publishes the event again
+	if st := evt.GetStatus(); st == events2.StatusPending || st == events2.StatusRejected {
+		return nil
+	}
+
+	projectID := evt.AggregateID()
+	if projectID == uuid.Nil {
+		return nil
+	}
+
+	project, err := loadProject(ctx, h.eventRepo, projectID)
+	if err != nil {
+		return err
+	}
+	if project == nil {
+		return nil
+	}
+
+	n, err := h.subscriptions.Dispatch(ctx, evt, project)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		log.Info().Msgf("Queued event %s for %d webhook subscription(s)", evt.GetID(), n)
+	}
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 
 	"time"
 
+	"github.com/SURF-Innovatie/MORIS/internal/domain/apitoken"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/identity/readmodels"
 	"github.com/SURF-Innovatie/MORIS/internal/infra/env"
 	"github.com/go-chi/chi/v5"
@@ -40,6 +41,12 @@ const (
 	ContextKeyErrorDetails ContextKey = "error_details"
 	// ContextKeyUser is the key used to store user info in context
 	ContextKeyUser ContextKey = "user"
+	// ContextKeySession is the key used to store the ID of the authenticated session in context
+	ContextKeySession ContextKey = "session"
+	// ContextKeyAPIToken is the key used to store the grant of an API token in context
+	ContextKeyAPIToken ContextKey = "api_token"
+	// ContextKeyImpersonation is the key used to store the impersonation the request is made in
+	ContextKeyImpersonation ContextKey = "impersonation"
 )
 
 // GetUserFromContext retrieves the authUser from the request context
@@ -48,6 +55,24 @@ func GetUserFromContext(ctx context.Context) (*readmodels.UserAccount, bool) {
 	return user, ok
 }
 
+// GetSessionIDFromContext retrieves the ID of the session the request is authenticated with
+func GetSessionIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	id, ok := ctx.Value(ContextKeySession).(uuid.UUID)
+	return id, ok
+}
+
+// GetAPITokenFromContext retrieves the grant of the API token the request is authenticated with
+func GetAPITokenFromContext(ctx context.Context) (*apitoken.Grant, bool) {
+	grant, ok := ctx.Value(ContextKeyAPIToken).(*apitoken.Grant)
+	return grant, ok && grant != nil
+}
+
+// GetImpersonationFromContext retrieves the impersonation of a request in which a sysadmin acts as the user
+func GetImpersonationFromContext(ctx context.Context) (*readmodels.Impersonation, bool) {
+	imp, ok := ctx.Value(ContextKeyImpersonation).(*readmodels.Impersonation)
+	return imp, ok && imp != nil
+}
+
 // GetUserIDFromContext helper to extract user ID safely
 func GetUserIDFromContext(ctx context.Context) *uuid.UUID {
 	userCtx, ok := GetUserFromContext(ctx)
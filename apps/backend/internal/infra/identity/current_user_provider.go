@@ -29,11 +29,19 @@ func (p *CurrentUserProvider) Current(ctx context.Context) (identity.Principal,
 		return identity.Principal{}, err
 	}
 
-	return identity.Principal{
+	principal := identity.Principal{
 		UserID:     u.ID,
 		PersonID:   u.PersonID,
 		IsSysAdmin: u.IsSysAdmin,
-	}, nil
+	}
+	if grant, ok := httputil.GetAPITokenFromContext(ctx); ok && grant.NodeIDs != nil {
+		principal.NodeIDs = grant.NodeIDs
+	}
+	if imp, ok := httputil.GetImpersonationFromContext(ctx); ok {
+		principal.ImpersonatorUserID = &imp.Impersonator.User.ID
+		principal.ImpersonatorPersonID = &imp.Impersonator.User.PersonID
+	}
+	return principal, nil
 }
 
 var _ authapp.CurrentUserProvider = (*CurrentUserProvider)(nil)
@@ -0,0 +1,231 @@
+package apitoken_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/ent"
+	"github.com/SURF-Innovatie/MORIS/ent/enttest"
+	appapitoken "github.com/SURF-Innovatie/MORIS/internal/app/apitoken"
+	orgsvc "github.com/SURF-Innovatie/MORIS/internal/app/organisation"
+	"github.com/SURF-Innovatie/MORIS/internal/app/person"
+	"github.com/SURF-Innovatie/MORIS/internal/app/user"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/apitoken"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/identity"
+	apitokenrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/apitoken"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/persistence/enttx"
+	orgrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/organisation"
+	personrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/person"
+	userrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/user"
+	"github.com/google/uuid"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/samber/lo"
+)
+
+type noUser struct{}
+
+func (noUser) Current(context.Context) (identity.Principal, error) {
+	return identity.Principal{}, errors.New("unauthenticated")
+}
+
+type noCache struct{}
+
+func (noCache) GetUser(context.Context, uuid.UUID) (*identity.User, error) {
+	return nil, errors.New("miss")
+}
+func (noCache) SetUser(context.Context, *identity.User) error { return nil }
+func (noCache) DeleteUser(context.Context, uuid.UUID) error   { return nil }
+func (noCache) ProjectIDsForPerson(context.Context, uuid.UUID) ([]uuid.UUID, error) {
+	return nil, nil
+}
+func (noCache) PersonIDsForProjects(context.Context, []uuid.UUID) ([]uuid.UUID, error) {
+	return nil, nil
+}
+
+type fixture struct {
+	cli     *ent.Client
+	svc     appapitoken.Service
+	userID  uuid.UUID
+	rootID  uuid.UUID
+	childID uuid.UUID
+}
+
+func setup(t *testing.T) fixture {
+	t.Helper()
+	ctx := context.Background()
+	cli := enttest.Open(t, "sqlite3", "file:apitoken_"+uuid.NewString()+"?mode=memory&cache=shared&_fk=1")
+	t.Cleanup(func() { _ = cli.Close() })
+
+	tx := enttx.NewManager(cli)
+	orgs := orgsvc.NewService(orgrepo.NewEntRepo(cli), nil, nil, tx)
+	root, err := orgs.CreateRoot(ctx, "University", nil, nil, nil)
+	if err != nil {
+		t.Fatalf("create root: %v", err)
+	}
+	child, err := orgs.CreateChild(ctx, root.ID, "Faculty", nil, nil, nil)
+	if err != nil {
+		t.Fatalf("create child: %v", err)
+	}
+
+	p := cli.Person.Create().SetName("Ada").SetEmail("ada@example.org").SaveX(ctx)
+	u := cli.User.Create().SetPersonID(p.ID).SaveX(ctx)
+
+	people := person.NewService(personrepo.NewEntRepo(cli))
+	svc := appapitoken.NewService(
+		apitokenrepo.NewEntRepo(cli),
+		user.NewService(userrepo.NewEntRepo(cli), people, noCache{}, noCache{}),
+		people,
+		noUser{},
+		tx,
+	)
+	return fixture{cli: cli, svc: svc, userID: u.ID, rootID: root.ID, childID: child.ID}
+}
+
+func TestTokenIsHashedAndAuthenticates(t *testing.T) {
+	f := setup(t)
+	ctx := context.Background()
+
+	tok, secret, err := f.svc.CreateToken(ctx, f.userID, appapitoken.TokenInput{
+		Name:      "nightly export",
+		Scopes:    []string{"projects:read", "export"},
+		ExpiresAt: time.Now().Add(24 * time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("create token: %v", err)
+	}
+	if !apitoken.IsSecret(secret) {
+		t.Fatalf("secret %q lacks the token prefix", secret)
+	}
+	stored := f.cli.ApiToken.GetX(ctx, tok.ID)
+	if stored.TokenHash == secret || stored.TokenHash != apitoken.HashSecret(secret) {
+		t.Fatal("expected only the hash of the secret to be stored")
+	}
+
+	grant, err := f.svc.Authenticate(ctx, secret)
+	if err != nil {
+		t.Fatalf("authenticate: %v", err)
+	}
+	if grant.Token.UserID != f.userID || grant.NodeIDs != nil {
+		t.Fatalf("unexpected grant %+v", grant)
+	}
+	if !grant.Token.HasScope(apitoken.ScopeExport) || grant.Token.HasScope(apitoken.ScopeEventsWrite) {
+		t.Fatalf("unexpected scopes %v", grant.Token.Scopes)
+	}
+	if f.cli.ApiToken.GetX(ctx, tok.ID).LastUsedAt == nil {
+		t.Fatal("expected the last use to be recorded")
+	}
+
+	if _, err := f.svc.Authenticate(ctx, secret+"x"); !errors.Is(err, apitoken.ErrUnauthorized) {
+		t.Fatalf("expected unknown secret to be rejected, got %v", err)
+	}
+
+	if err := f.svc.DeleteToken(ctx, uuid.New(), tok.ID); !errors.Is(err, apitoken.ErrNotFound) {
+		t.Fatalf("expected another user's delete to miss, got %v", err)
+	}
+	if err := f.svc.DeleteToken(ctx, f.userID, tok.ID); err != nil {
+		t.Fatalf("delete token: %v", err)
+	}
+	if _, err := f.svc.Authenticate(ctx, secret); !errors.Is(err, apitoken.ErrUnauthorized) {
+		t.Fatalf("expected deleted token to be rejected, got %v", err)
+	}
+}
+
+func TestTokenValidation(t *testing.T) {
+	f := setup(t)
+	ctx := context.Background()
+	valid := appapitoken.TokenInput{Name: "script", Scopes: []string{"projects:read"}, ExpiresAt: time.Now().Add(time.Hour)}
+
+	cases := map[string]struct {
+		edit func(*appapitoken.TokenInput)
+		want error
+	}{
+		"unknown scope": {func(in *appapitoken.TokenInput) { in.Scopes = []string{"projects:delete"} }, apitoken.ErrInvalidScope},
+		"no scopes":     {func(in *appapitoken.TokenInput) { in.Scopes = nil }, apitoken.ErrNoScopes},
+		"expired":       {func(in *appapitoken.TokenInput) { in.ExpiresAt = time.Now().Add(-time.Minute) }, apitoken.ErrInvalidExpiry},
+		"too long":      {func(in *appapitoken.TokenInput) { in.ExpiresAt = time.Now().Add(2 * apitoken.MaxLifetime) }, apitoken.ErrInvalidExpiry},
+		"unknown node":  {func(in *appapitoken.TokenInput) { in.NodeIDs = []uuid.UUID{uuid.New()} }, apitoken.ErrUnknownNode},
+		"no name":       {func(in *appapitoken.TokenInput) { in.Name = " " }, apitoken.ErrInvalidValue},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			in := valid
+			tc.edit(&in)
+			if _, _, err := f.svc.CreateToken(ctx, f.userID, in); !errors.Is(err, tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestTokenNodesIncludeDescendants(t *testing.T) {
+	f := setup(t)
+	ctx := context.Background()
+
+	_, secret, err := f.svc.CreateToken(ctx, f.userID, appapitoken.TokenInput{
+		Name:      "faculty sync",
+		Scopes:    []string{"events:write"},
+		NodeIDs:   []uuid.UUID{f.rootID},
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("create token: %v", err)
+	}
+	grant, err := f.svc.Authenticate(ctx, secret)
+	if err != nil {
+		t.Fatalf("authenticate: %v", err)
+	}
+	if len(grant.NodeIDs) != 2 || !lo.Contains(grant.NodeIDs, f.childID) {
+		t.Fatalf("expected root and child nodes, got %v", grant.NodeIDs)
+	}
+
+	principal := identity.Principal{NodeIDs: grant.NodeIDs}
+	if !principal.CanAccessNode(f.childID) || principal.CanAccessNode(uuid.New()) {
+		t.Fatal("expected access limited to the token's nodes")
+	}
+}
+
+func TestServiceAccountLifecycle(t *testing.T) {
+	f := setup(t)
+	ctx := context.Background()
+
+	sa, err := f.svc.CreateServiceAccount(ctx, appapitoken.ServiceAccountInput{Name: "CRIS sync", Active: true})
+	if err != nil {
+		t.Fatalf("create service account: %v", err)
+	}
+	if !sa.Active || !f.cli.User.GetX(ctx, sa.UserID).IsServiceAccount {
+		t.Fatalf("expected an active service account, got %+v", sa)
+	}
+	if _, err := f.svc.GetServiceAccount(ctx, f.userID); !errors.Is(err, apitoken.ErrNotServiceAccount) {
+		t.Fatalf("expected regular users to be excluded, got %v", err)
+	}
+
+	_, secret, err := f.svc.CreateToken(ctx, sa.UserID, appapitoken.TokenInput{
+		Name:      "production",
+		Scopes:    []string{"projects:read"},
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("create token: %v", err)
+	}
+	if _, err := f.svc.Authenticate(ctx, secret); err != nil {
+		t.Fatalf("authenticate: %v", err)
+	}
+
+	updated, err := f.svc.UpdateServiceAccount(ctx, sa.UserID, appapitoken.ServiceAccountInput{Name: "CRIS import", Active: false})
+	if err != nil {
+		t.Fatalf("update service account: %v", err)
+	}
+	if updated.Name != "CRIS import" || updated.Active {
+		t.Fatalf("unexpected service account %+v", updated)
+	}
+	if _, err := f.svc.Authenticate(ctx, secret); !errors.Is(err, apitoken.ErrUnauthorized) {
+		t.Fatalf("expected tokens of a deactivated account to be deleted, got %v", err)
+	}
+
+	accounts, err := f.svc.ListServiceAccounts(ctx)
+	if err != nil || len(accounts) != 1 {
+		t.Fatalf("expected one service account, got %v (%v)", accounts, err)
+	}
+}
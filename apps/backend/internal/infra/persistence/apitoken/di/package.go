@@ -0,0 +1,16 @@
+package di
+
+import (
+	"github.com/SURF-Innovatie/MORIS/ent"
+	apitokenrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/apitoken"
+	"github.com/samber/do/v2"
+)
+
+var Package = do.Package(
+	do.Lazy(provideEntRepo),
+)
+
+func provideEntRepo(i do.Injector) (*apitokenrepo.EntRepo, error) {
+	cli := do.MustInvoke[*ent.Client](i)
+	return apitokenrepo.NewEntRepo(cli), nil
+}
@@ -0,0 +1,189 @@
+package apitoken
+
+import (
+	"context"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/ent"
+	entapitoken "github.com/SURF-Innovatie/MORIS/ent/apitoken"
+	entapitokennode "github.com/SURF-Innovatie/MORIS/ent/apitokennode"
+	entorganisationnode "github.com/SURF-Innovatie/MORIS/ent/organisationnode"
+	entclosure "github.com/SURF-Innovatie/MORIS/ent/organisationnodeclosure"
+	entperson "github.com/SURF-Innovatie/MORIS/ent/person"
+	"github.com/SURF-Innovatie/MORIS/ent/predicate"
+	entuser "github.com/SURF-Innovatie/MORIS/ent/user"
+	"github.com/SURF-Innovatie/MORIS/internal/common/transform"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/apitoken"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/persistence/enttx"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+type EntRepo struct {
+	cli *ent.Client
+}
+
+func NewEntRepo(cli *ent.Client) *EntRepo {
+	return &EntRepo{cli: cli}
+}
+
+// client returns the transactional client when ctx carries a transaction
+func (r *EntRepo) client(ctx context.Context) *ent.Client {
+	if tx, ok := enttx.TxFromContext(ctx); ok {
+		return tx.Client()
+	}
+	return r.cli
+}
+
+func (r *EntRepo) CreateToken(ctx context.Context, t apitoken.Token, hash string) (*apitoken.Token, error) {
+	cli := r.client(ctx)
+	row, err := cli.ApiToken.Create().
+		SetUserID(t.UserID).
+		SetName(t.Name).
+		SetTokenHash(hash).
+		SetScopes(lo.Map(t.Scopes, func(s apitoken.Scope, _ int) string { return string(s) })).
+		SetNillableCreatedByUserID(t.CreatedByUserID).
+		SetExpiresAt(t.ExpiresAt).
+		Save(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(t.NodeIDs) > 0 {
+		if err := cli.ApiTokenNode.MapCreateBulk(t.NodeIDs, func(c *ent.ApiTokenNodeCreate, i int) {
+			c.SetAPITokenID(row.ID).SetNodeID(t.NodeIDs[i])
+		}).Exec(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return r.token(ctx, entapitoken.IDEQ(row.ID))
+}
+
+func (r *EntRepo) ListTokens(ctx context.Context, userID uuid.UUID) ([]apitoken.Token, error) {
+	rows, err := r.client(ctx).ApiToken.Query().
+		Where(entapitoken.UserIDEQ(userID)).
+		WithNodes().
+		Order(ent.Desc(entapitoken.FieldCreatedAt)).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return transform.ToEntities[apitoken.Token](rows), nil
+}
+
+func (r *EntRepo) DeleteToken(ctx context.Context, userID, id uuid.UUID) (bool, error) {
+	cli := r.client(ctx)
+	exists, err := cli.ApiToken.Query().
+		Where(entapitoken.IDEQ(id), entapitoken.UserIDEQ(userID)).
+		Exist(ctx)
+	if err != nil || !exists {
+		return false, err
+	}
+	if _, err := cli.ApiTokenNode.Delete().Where(entapitokennode.APITokenIDEQ(id)).Exec(ctx); err != nil {
+		return false, err
+	}
+	n, err := cli.ApiToken.Delete().Where(entapitoken.IDEQ(id)).Exec(ctx)
+	return n > 0, err
+}
+
+func (r *EntRepo) DeleteUserTokens(ctx context.Context, userID uuid.UUID) error {
+	cli := r.client(ctx)
+	if _, err := cli.ApiTokenNode.Delete().
+		Where(entapitokennode.HasTokenWith(entapitoken.UserIDEQ(userID))).
+		Exec(ctx); err != nil {
+		return err
+	}
+	_, err := cli.ApiToken.Delete().Where(entapitoken.UserIDEQ(userID)).Exec(ctx)
+	return err
+}
+
+func (r *EntRepo) TokenByHash(ctx context.Context, hash string) (*apitoken.Token, error) {
+	return r.token(ctx, entapitoken.TokenHashEQ(hash))
+}
+
+func (r *EntRepo) token(ctx context.Context, where ...predicate.ApiToken) (*apitoken.Token, error) {
+	row, err := r.client(ctx).ApiToken.Query().
+		Where(where...).
+		WithNodes().
+		Only(ctx)
+	if ent.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return transform.ToEntityPtr[apitoken.Token](row), nil
+}
+
+func (r *EntRepo) TouchToken(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return r.client(ctx).ApiToken.UpdateOneID(id).SetLastUsedAt(at).Exec(ctx)
+}
+
+func (r *EntRepo) NodesExist(ctx context.Context, nodeIDs []uuid.UUID) (bool, error) {
+	n, err := r.client(ctx).OrganisationNode.Query().
+		Where(entorganisationnode.IDIn(nodeIDs...)).
+		Count(ctx)
+	return n == len(nodeIDs), err
+}
+
+func (r *EntRepo) Descendants(ctx context.Context, nodeIDs []uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := r.client(ctx).OrganisationNodeClosure.Query().
+		Where(entclosure.AncestorIDIn(nodeIDs...)).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids := lo.Map(rows, func(c *ent.OrganisationNodeClosure, _ int) uuid.UUID { return c.DescendantID })
+	return lo.Uniq(append(ids, nodeIDs...)), nil
+}
+
+func (r *EntRepo) ListServiceAccounts(ctx context.Context) ([]apitoken.ServiceAccount, error) {
+	users, err := r.client(ctx).User.Query().
+		Where(entuser.IsServiceAccountEQ(true)).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.toServiceAccounts(ctx, users)
+}
+
+func (r *EntRepo) GetServiceAccount(ctx context.Context, userID uuid.UUID) (*apitoken.ServiceAccount, error) {
+	u, err := r.client(ctx).User.Query().
+		Where(entuser.IDEQ(userID), entuser.IsServiceAccountEQ(true)).
+		Only(ctx)
+	if ent.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	out, err := r.toServiceAccounts(ctx, []*ent.User{u})
+	if err != nil || len(out) == 0 {
+		return nil, err
+	}
+	return &out[0], nil
+}
+
+// toServiceAccounts joins the users with their people, ordered by name
+func (r *EntRepo) toServiceAccounts(ctx context.Context, users []*ent.User) ([]apitoken.ServiceAccount, error) {
+	people, err := r.client(ctx).Person.Query().
+		Where(entperson.IDIn(lo.Map(users, func(u *ent.User, _ int) uuid.UUID { return u.PersonID })...)).
+		Order(ent.Asc(entperson.FieldName)).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userByPerson := lo.KeyBy(users, func(u *ent.User) uuid.UUID { return u.PersonID })
+
+	out := make([]apitoken.ServiceAccount, 0, len(people))
+	for _, p := range people {
+		u := userByPerson[p.ID]
+		out = append(out, apitoken.ServiceAccount{
+			UserID:      u.ID,
+			PersonID:    p.ID,
+			Name:        p.Name,
+			Description: p.Description,
+			Active:      u.IsActive,
+		})
+	}
+	return out, nil
+}
@@ -0,0 +1,158 @@
+package audit_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/SURF-Innovatie/MORIS/ent"
+	"github.com/SURF-Innovatie/MORIS/ent/enttest"
+	appaudit "github.com/SURF-Innovatie/MORIS/internal/app/audit"
+	organisationrole "github.com/SURF-Innovatie/MORIS/internal/app/organisation/role"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/audit"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/identity"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation/rbac"
+	auditrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/audit"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/persistence/enttx"
+	rolerepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/organisation/role"
+	"github.com/google/uuid"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/samber/lo"
+)
+
+type staticUser struct{ p identity.Principal }
+
+func (s staticUser) Current(context.Context) (identity.Principal, error) { return s.p, nil }
+
+func setup(t *testing.T) (*ent.Client, organisationrole.Service, *auditrepo.EntRepo, uuid.UUID, uuid.UUID, uuid.UUID) {
+	t.Helper()
+	ctx := context.Background()
+	cli := enttest.Open(t, "sqlite3", "file:"+t.Name()+"?mode=memory&cache=shared&_fk=1")
+	t.Cleanup(func() { _ = cli.Close() })
+
+	root := cli.OrganisationNode.Create().SetName("root").SaveX(ctx)
+	child := cli.OrganisationNode.Create().SetName("child").SetParentID(root.ID).SaveX(ctx)
+	cli.OrganisationNodeClosure.Create().SetAncestorID(root.ID).SetDescendantID(root.ID).SetDepth(0).ExecX(ctx)
+	cli.OrganisationNodeClosure.Create().SetAncestorID(child.ID).SetDescendantID(child.ID).SetDepth(0).ExecX(ctx)
+	cli.OrganisationNodeClosure.Create().SetAncestorID(root.ID).SetDescendantID(child.ID).SetDepth(1).ExecX(ctx)
+
+	person := cli.Person.Create().
+		SetName("Ada Lovelace").
+		SetGivenName("Ada").
+		SetFamilyName("Lovelace").
+		SetEmail(uuid.NewString() + "@example.org").
+		SaveX(ctx)
+	user := cli.User.Create().SetPersonID(person.ID).SaveX(ctx)
+
+	repo := auditrepo.NewEntRepo(cli)
+	auditSvc := appaudit.NewService(repo, staticUser{identity.Principal{UserID: user.ID, PersonID: person.ID}})
+	svc := organisationrole.NewService(rolerepo.NewEntRepo(cli), auditSvc, enttx.NewManager(cli))
+	return cli, svc, repo, root.ID, child.ID, user.ID
+}
+
+func TestRoleChanges_AreRecordedWithBeforeAndAfter(t *testing.T) {
+	ctx := context.Background()
+	_, svc, repo, rootID, _, userID := setup(t)
+
+	role, err := svc.CreateRole(ctx, rootID, "editor", "Editor", []rbac.Permission{rbac.PermissionManageMembers})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.UpdateRole(ctx, role.ID, "Editors", []rbac.Permission{rbac.PermissionManageMembers, rbac.PermissionViewAuditLog}); err != nil {
+		t.Fatal(err)
+	}
+
+	page, err := repo.ListPage(ctx, rootID, audit.ListFilter{Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 3 {
+		t.Fatalf("expected role create, scope create and role update, got %d entries", len(page.Items))
+	}
+
+	update := page.Items[0]
+	if update.Action != audit.ActionUpdate || update.EntityType != audit.EntityOrganisationRole || update.EntityID != role.ID {
+		t.Fatalf("expected the newest entry to be the role update, got %+v", update)
+	}
+	if update.ActorUserID == nil || *update.ActorUserID != userID || update.ActorName != "Ada Lovelace" {
+		t.Fatalf("expected the actor to be recorded, got %v %q", update.ActorUserID, update.ActorName)
+	}
+	var before, after rbac.OrganisationRole
+	if err := json.Unmarshal(update.Before, &before); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(update.After, &after); err != nil {
+		t.Fatal(err)
+	}
+	if before.DisplayName != "Editor" || after.DisplayName != "Editors" {
+		t.Fatalf("expected display name Editor -> Editors, got %q -> %q", before.DisplayName, after.DisplayName)
+	}
+
+	scopes := lo.Filter(page.Items, func(e audit.Entry, _ int) bool { return e.EntityType == audit.EntityRoleScope })
+	if len(scopes) != 1 || scopes[0].Before != nil {
+		t.Fatalf("expected one scope creation without a before state, got %+v", scopes)
+	}
+}
+
+func TestFailedChange_LeavesNoEntry(t *testing.T) {
+	ctx := context.Background()
+	_, svc, repo, rootID, _, _ := setup(t)
+
+	role, err := svc.CreateRole(ctx, rootID, "editor", "Editor", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The role's scope keeps it in use, so the delete is refused
+	if err := svc.DeleteRole(ctx, role.ID); err == nil {
+		t.Fatal("expected the delete to be refused")
+	}
+
+	deletes := audit.ActionDelete
+	page, err := repo.ListPage(ctx, rootID, audit.ListFilter{Action: &deletes, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 0 {
+		t.Fatalf("expected no delete entries, got %+v", page.Items)
+	}
+}
+
+func TestListPage_CoversDescendantsAndPages(t *testing.T) {
+	ctx := context.Background()
+	_, svc, repo, rootID, childID, _ := setup(t)
+
+	if _, err := svc.CreateRole(ctx, rootID, "root-role", "Root role", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.CreateRole(ctx, childID, "child-role", "Child role", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	roles := audit.EntityOrganisationRole
+	page, err := repo.ListPage(ctx, childID, audit.ListFilter{EntityType: &roles, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 1 || page.Items[0].OrgNodeID != childID {
+		t.Fatalf("expected only the child's role on the child, got %+v", page.Items)
+	}
+
+	var seen []uuid.UUID
+	var cursor *audit.Cursor
+	for {
+		page, err := repo.ListPage(ctx, rootID, audit.ListFilter{EntityType: &roles, After: cursor, Limit: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range page.Items {
+			seen = append(seen, e.ID)
+		}
+		if page.NextCursor == nil {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	if len(lo.Uniq(seen)) != 2 {
+		t.Fatalf("expected both roles on the root across pages, got %d", len(seen))
+	}
+}
@@ -0,0 +1,17 @@
+package di
+
+import (
+	"github.com/SURF-Innovatie/MORIS/ent"
+	"github.com/SURF-Innovatie/MORIS/internal/app/audit"
+	auditrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/audit"
+	"github.com/samber/do/v2"
+)
+
+var Package = do.Package(
+	do.Lazy(provideAuditRepo),
+)
+
+func provideAuditRepo(i do.Injector) (audit.Repository, error) {
+	cli := do.MustInvoke[*ent.Client](i)
+	return auditrepo.NewEntRepo(cli), nil
+}
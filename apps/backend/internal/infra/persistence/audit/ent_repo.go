@@ -0,0 +1,142 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/SURF-Innovatie/MORIS/ent"
+	entaudit "github.com/SURF-Innovatie/MORIS/ent/auditlogentry"
+	entclosure "github.com/SURF-Innovatie/MORIS/ent/organisationnodeclosure"
+	entperson "github.com/SURF-Innovatie/MORIS/ent/person"
+	"github.com/SURF-Innovatie/MORIS/ent/predicate"
+	entuser "github.com/SURF-Innovatie/MORIS/ent/user"
+	"github.com/SURF-Innovatie/MORIS/internal/common/transform"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/audit"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/persistence/enttx"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// EntRepo stores the audit log. Entries are only ever inserted.
+type EntRepo struct {
+	cli *ent.Client
+}
+
+func NewEntRepo(cli *ent.Client) *EntRepo {
+	return &EntRepo{cli: cli}
+}
+
+func (r *EntRepo) client(ctx context.Context) *ent.Client {
+	if tx, ok := enttx.TxFromContext(ctx); ok {
+		return tx.Client()
+	}
+	return r.cli
+}
+
+func (r *EntRepo) Append(ctx context.Context, e audit.Entry) error {
+	create := r.client(ctx).AuditLogEntry.
+		Create().
+		SetOrganisationNodeID(e.OrgNodeID).
+		SetNillableActorUserID(e.ActorUserID).
+		SetEntityType(entaudit.EntityType(e.EntityType)).
+		SetEntityID(e.EntityID).
+		SetAction(entaudit.Action(e.Action))
+	if e.Before != nil {
+		create.SetBefore(e.Before)
+	}
+	if e.After != nil {
+		create.SetAfter(e.After)
+	}
+	return create.Exec(ctx)
+}
+
+func (r *EntRepo) ListPage(ctx context.Context, nodeID uuid.UUID, f audit.ListFilter) (*audit.Page, error) {
+	// The closure table holds a row for the node itself as well
+	var nodeIDs []uuid.UUID
+	if err := r.cli.OrganisationNodeClosure.
+		Query().
+		Where(entclosure.AncestorIDEQ(nodeID)).
+		Select(entclosure.FieldDescendantID).
+		Scan(ctx, &nodeIDs); err != nil {
+		return nil, err
+	}
+
+	where := []predicate.AuditLogEntry{entaudit.OrganisationNodeIDIn(nodeIDs...)}
+	if f.EntityType != nil {
+		where = append(where, entaudit.EntityTypeEQ(entaudit.EntityType(*f.EntityType)))
+	}
+	if f.EntityID != nil {
+		where = append(where, entaudit.EntityIDEQ(*f.EntityID))
+	}
+	if f.Action != nil {
+		where = append(where, entaudit.ActionEQ(entaudit.Action(*f.Action)))
+	}
+	if f.ActorUserID != nil {
+		where = append(where, entaudit.ActorUserIDEQ(*f.ActorUserID))
+	}
+	if f.From != nil {
+		where = append(where, entaudit.CreatedAtGTE(*f.From))
+	}
+	if f.To != nil {
+		where = append(where, entaudit.CreatedAtLT(*f.To))
+	}
+	if f.After != nil {
+		where = append(where, entaudit.Or(
+			entaudit.CreatedAtLT(f.After.CreatedAt),
+			entaudit.And(entaudit.CreatedAtEQ(f.After.CreatedAt), entaudit.IDLT(f.After.ID)),
+		))
+	}
+
+	// Fetch one extra row to know whether there is a next page
+	rows, err := r.cli.AuditLogEntry.Query().
+		Where(where...).
+		Order(ent.Desc(entaudit.FieldCreatedAt), ent.Desc(entaudit.FieldID)).
+		Limit(f.Limit + 1).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &audit.Page{}
+	if len(rows) > f.Limit {
+		rows = rows[:f.Limit]
+		last := rows[len(rows)-1]
+		page.NextCursor = &audit.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+	page.Items = transform.ToEntities[audit.Entry](rows)
+
+	names, err := r.actorNames(ctx, page.Items)
+	if err != nil {
+		return nil, err
+	}
+	for i := range page.Items {
+		if id := page.Items[i].ActorUserID; id != nil {
+			page.Items[i].ActorName = names[*id]
+		}
+	}
+	return page, nil
+}
+
+// actorNames maps the users that made the changes to their person's name
+func (r *EntRepo) actorNames(ctx context.Context, items []audit.Entry) (map[uuid.UUID]string, error) {
+	userIDs := lo.Uniq(lo.FilterMap(items, func(e audit.Entry, _ int) (uuid.UUID, bool) {
+		return lo.FromPtr(e.ActorUserID), e.ActorUserID != nil
+	}))
+	if len(userIDs) == 0 {
+		return map[uuid.UUID]string{}, nil
+	}
+
+	users, err := r.cli.User.Query().
+		Where(entuser.IDIn(userIDs...)).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	people, err := r.cli.Person.Query().
+		Where(entperson.IDIn(lo.Map(users, func(u *ent.User, _ int) uuid.UUID { return u.PersonID })...)).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	nameByPerson := lo.SliceToMap(people, func(p *ent.Person) (uuid.UUID, string) { return p.ID, p.Name })
+	return lo.SliceToMap(users, func(u *ent.User) (uuid.UUID, string) { return u.ID, nameByPerson[u.PersonID] }), nil
+}
@@ -0,0 +1,343 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/ent"
+	"github.com/SURF-Innovatie/MORIS/ent/enttest"
+	authapp "github.com/SURF-Innovatie/MORIS/internal/app/auth"
+	"github.com/SURF-Innovatie/MORIS/internal/app/person"
+	"github.com/SURF-Innovatie/MORIS/internal/app/user"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/identity"
+	authrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/auth"
+	personrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/person"
+	userrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/user"
+	"github.com/google/uuid"
+	_ "github.com/mattn/go-sqlite3"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type noCache struct{}
+
+func (noCache) GetUser(context.Context, uuid.UUID) (*identity.User, error) {
+	return nil, errors.New("miss")
+}
+func (noCache) SetUser(context.Context, *identity.User) error { return nil }
+func (noCache) DeleteUser(context.Context, uuid.UUID) error   { return nil }
+func (noCache) ProjectIDsForPerson(context.Context, uuid.UUID) ([]uuid.UUID, error) {
+	return nil, nil
+}
+func (noCache) PersonIDsForProjects(context.Context, []uuid.UUID) ([]uuid.UUID, error) {
+	return nil, nil
+}
+
+// revocations is an in-memory revocation store; down simulates an unreachable Redis
+type revocations struct {
+	revoked map[uuid.UUID]bool
+	down    bool
+}
+
+func (r *revocations) Revoke(_ context.Context, id uuid.UUID, _ time.Duration) error {
+	if r.down {
+		return errors.New("unavailable")
+	}
+	r.revoked[id] = true
+	return nil
+}
+
+func (r *revocations) IsRevoked(_ context.Context, id uuid.UUID) (bool, error) {
+	if r.down {
+		return false, errors.New("unavailable")
+	}
+	return r.revoked[id], nil
+}
+
+type fixture struct {
+	cli    *ent.Client
+	svc    authapp.Service
+	store  *revocations
+	now    *time.Time
+	userID uuid.UUID
+	other  uuid.UUID
+	admin  uuid.UUID
+}
+
+func setup(t *testing.T) fixture {
+	t.Helper()
+	ctx := context.Background()
+	cli := enttest.Open(t, "sqlite3", "file:auth_"+uuid.NewString()+"?mode=memory&cache=shared&_fk=1")
+	t.Cleanup(func() { _ = cli.Close() })
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ada := cli.Person.Create().SetName("Ada Lovelace").SetEmail("ada@example.org").SaveX(ctx)
+	usr := cli.User.Create().SetPersonID(ada.ID).SetPassword(string(hash)).SaveX(ctx)
+	alan := cli.Person.Create().SetName("Alan Turing").SetEmail("alan@example.org").SaveX(ctx)
+	other := cli.User.Create().SetPersonID(alan.ID).SaveX(ctx)
+	grace := cli.Person.Create().SetName("Grace Hopper").SetEmail("grace@example.org").SaveX(ctx)
+	admin := cli.User.Create().SetPersonID(grace.ID).SetIsSysAdmin(true).SaveX(ctx)
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	store := &revocations{revoked: map[uuid.UUID]bool{}}
+	people := person.NewService(personrepo.NewEntRepo(cli))
+	users := user.NewService(userrepo.NewEntRepo(cli), people, noCache{}, noCache{})
+	svc := authapp.NewService(authrepo.NewEntRepo(cli, users), store, nil, authapp.Options{
+		JWTSecret:        "test-secret",
+		AccessTTL:        15 * time.Minute,
+		RefreshTTL:       24 * time.Hour,
+		ImpersonationTTL: 30 * time.Minute,
+		Now:              func() time.Time { return now },
+	})
+	return fixture{cli: cli, svc: svc, store: store, now: &now, userID: usr.ID, other: other.ID, admin: admin.ID}
+}
+
+func TestRefreshRotatesTokenAndDetectsReuse(t *testing.T) {
+	f := setup(t)
+	ctx := context.Background()
+
+	first, _, err := f.svc.Login(ctx, "ada@example.org", "secret", authapp.ClientInfo{UserAgent: "Firefox", IPAddress: "192.0.2.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	authn, err := f.svc.ValidateToken(ctx, first.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if authn.Account.User.ID != f.userID || authn.SessionID != first.SessionID {
+		t.Fatalf("unexpected authentication %+v", authn)
+	}
+
+	// The access token expires, the session does not
+	*f.now = f.now.Add(20 * time.Minute)
+	if _, err := f.svc.ValidateToken(ctx, first.AccessToken); err == nil {
+		t.Fatal("expected expired access token to be rejected")
+	}
+	second, _, err := f.svc.Refresh(ctx, first.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.SessionID != first.SessionID || second.RefreshToken == first.RefreshToken {
+		t.Fatalf("expected a rotated refresh token for the same session, got %+v", second)
+	}
+	if _, err := f.svc.ValidateToken(ctx, second.AccessToken); err != nil {
+		t.Fatal(err)
+	}
+
+	// Presenting the replaced token again revokes the session
+	if _, _, err := f.svc.Refresh(ctx, first.RefreshToken); !errors.Is(err, authapp.ErrInvalidRefreshToken) {
+		t.Fatalf("expected ErrInvalidRefreshToken, got %v", err)
+	}
+	if _, _, err := f.svc.Refresh(ctx, second.RefreshToken); !errors.Is(err, authapp.ErrInvalidRefreshToken) {
+		t.Fatalf("expected the session to be revoked, got %v", err)
+	}
+	if _, err := f.svc.ValidateToken(ctx, second.AccessToken); !errors.Is(err, authapp.ErrSessionRevoked) {
+		t.Fatalf("expected ErrSessionRevoked, got %v", err)
+	}
+}
+
+func TestSessionsExpireWithoutRefresh(t *testing.T) {
+	f := setup(t)
+	ctx := context.Background()
+
+	tokens, _, err := f.svc.Login(ctx, "ada@example.org", "secret", authapp.ClientInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	*f.now = f.now.Add(25 * time.Hour)
+	if _, _, err := f.svc.Refresh(ctx, tokens.RefreshToken); !errors.Is(err, authapp.ErrInvalidRefreshToken) {
+		t.Fatalf("expected ErrInvalidRefreshToken, got %v", err)
+	}
+	sessions, err := f.svc.ListSessions(ctx, f.userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 0 {
+		t.Fatalf("expected no active sessions, got %d", len(sessions))
+	}
+}
+
+func TestLogoutAndRevokeSession(t *testing.T) {
+	f := setup(t)
+	ctx := context.Background()
+
+	laptop, _, err := f.svc.Login(ctx, "ada@example.org", "secret", authapp.ClientInfo{UserAgent: "Firefox"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	*f.now = f.now.Add(time.Minute)
+	phone, _, err := f.svc.LoginByEmail(ctx, "ada@example.org", authapp.ClientInfo{UserAgent: "Safari"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sessions, err := f.svc.ListSessions(ctx, f.userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 2 || sessions[0].ID != phone.SessionID || *sessions[0].UserAgent != "Safari" {
+		t.Fatalf("expected both sessions, most recent first, got %+v", sessions)
+	}
+
+	if err := f.svc.RevokeSession(ctx, f.other, phone.SessionID); !errors.Is(err, authapp.ErrSessionNotFound) {
+		t.Fatalf("expected ErrSessionNotFound for another user's session, got %v", err)
+	}
+	if err := f.svc.RevokeSession(ctx, f.userID, phone.SessionID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.svc.ValidateToken(ctx, phone.AccessToken); !errors.Is(err, authapp.ErrSessionRevoked) {
+		t.Fatalf("expected ErrSessionRevoked, got %v", err)
+	}
+
+	// A revocation the store cannot record fails and leaves the session usable
+	f.store.down = true
+	if err := f.svc.Logout(ctx, laptop.SessionID); err == nil {
+		t.Fatal("expected logout to fail while the revocation store is unavailable")
+	}
+	f.store.down = false
+	if _, err := f.svc.ValidateToken(ctx, laptop.AccessToken); err != nil {
+		t.Fatalf("expected the session to remain, got %v", err)
+	}
+
+	// Without the revocation store the session itself is checked
+	if err := f.svc.Logout(ctx, laptop.SessionID); err != nil {
+		t.Fatal(err)
+	}
+	f.store.down = true
+	if _, err := f.svc.ValidateToken(ctx, laptop.AccessToken); !errors.Is(err, authapp.ErrSessionRevoked) {
+		t.Fatalf("expected ErrSessionRevoked, got %v", err)
+	}
+	f.store.down = false
+	if _, _, err := f.svc.Refresh(ctx, laptop.RefreshToken); !errors.Is(err, authapp.ErrInvalidRefreshToken) {
+		t.Fatalf("expected ErrInvalidRefreshToken, got %v", err)
+	}
+
+	sessions, err = f.svc.ListSessions(ctx, f.userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 0 {
+		t.Fatalf("expected no active sessions, got %d", len(sessions))
+	}
+}
+
+func TestImpersonationCarriesBothIdentitiesAndIsTimeLimited(t *testing.T) {
+	f := setup(t)
+	ctx := context.Background()
+	started := *f.now
+
+	if _, _, err := f.svc.Impersonate(ctx, f.admin, f.userID, " ", authapp.ClientInfo{}); !errors.Is(err, authapp.ErrReasonRequired) {
+		t.Fatalf("expected ErrReasonRequired, got %v", err)
+	}
+	for name, ids := range map[string][2]uuid.UUID{
+		"self":            {f.admin, f.admin},
+		"by regular user": {f.other, f.userID},
+	} {
+		if _, _, err := f.svc.Impersonate(ctx, ids[0], ids[1], "support", authapp.ClientInfo{}); !errors.Is(err, authapp.ErrCannotImpersonate) {
+			t.Fatalf("%s: expected ErrCannotImpersonate, got %v", name, err)
+		}
+	}
+
+	tokens, target, err := f.svc.Impersonate(ctx, f.admin, f.userID, "Ticket 42", authapp.ClientInfo{UserAgent: "Firefox"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if target.User.ID != f.userID {
+		t.Fatalf("expected to act as the user, got %s", target.User.ID)
+	}
+	authn, err := f.svc.ValidateToken(ctx, tokens.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if authn.Account.User.ID != f.userID || authn.Impersonation == nil ||
+		authn.Impersonation.Impersonator.User.ID != f.admin || authn.Impersonation.Reason != "Ticket 42" {
+		t.Fatalf("expected both identities, got %+v", authn)
+	}
+
+	// The user sees the impersonation among their sessions
+	sessions, err := f.svc.ListSessions(ctx, f.userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 1 || sessions[0].ImpersonatorUserID == nil || *sessions[0].ImpersonatorUserID != f.admin {
+		t.Fatalf("expected the impersonation session, got %+v", sessions)
+	}
+
+	// Refreshing does not extend the impersonation, and tokens never outlive it
+	*f.now = started.Add(20 * time.Minute)
+	refreshed, _, err := f.svc.Refresh(ctx, tokens.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !refreshed.AccessTokenExpiresAt.Equal(started.Add(30 * time.Minute)) {
+		t.Fatalf("expected the token to expire with the impersonation, got %s", refreshed.AccessTokenExpiresAt)
+	}
+	*f.now = started.Add(31 * time.Minute)
+	if _, err := f.svc.ValidateToken(ctx, refreshed.AccessToken); err == nil {
+		t.Fatal("expected the impersonation to have ended")
+	}
+	if _, _, err := f.svc.Refresh(ctx, refreshed.RefreshToken); !errors.Is(err, authapp.ErrInvalidRefreshToken) {
+		t.Fatalf("expected ErrInvalidRefreshToken, got %v", err)
+	}
+
+	// Ended impersonations are kept for audit
+	all, err := f.svc.ListImpersonations(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 1 || all[0].UserID != f.userID || *all[0].ImpersonationReason != "Ticket 42" {
+		t.Fatalf("expected the impersonation to be listed, got %+v", all)
+	}
+}
+
+func TestEndImpersonation(t *testing.T) {
+	f := setup(t)
+	ctx := context.Background()
+
+	own, _, err := f.svc.Login(ctx, "ada@example.org", "secret", authapp.ClientInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokens, _, err := f.svc.Impersonate(ctx, f.admin, f.userID, "Ticket 43", authapp.ClientInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.svc.EndImpersonation(ctx, own.SessionID); !errors.Is(err, authapp.ErrSessionNotFound) {
+		t.Fatalf("expected regular sessions to be left alone, got %v", err)
+	}
+	// Impersonation tokens are checked against the session itself, not the revocation store
+	f.store.down = true
+	if err := f.svc.EndImpersonation(ctx, tokens.SessionID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.svc.ValidateToken(ctx, tokens.AccessToken); !errors.Is(err, authapp.ErrSessionRevoked) {
+		t.Fatalf("expected ErrSessionRevoked, got %v", err)
+	}
+	if _, err := f.svc.ValidateToken(ctx, own.AccessToken); err != nil {
+		t.Fatalf("expected the user's own session to remain, got %v", err)
+	}
+}
+
+func TestValidateTokenTrustsTheRevocationStore(t *testing.T) {
+	f := setup(t)
+	ctx := context.Background()
+
+	tokens, _, err := f.svc.Login(ctx, "ada@example.org", "secret", authapp.ClientInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Revoked in the database behind the store's back: only noticed when the store is unavailable
+	f.cli.Session.UpdateOneID(tokens.SessionID).SetRevokedAt(*f.now).ExecX(ctx)
+	if _, err := f.svc.ValidateToken(ctx, tokens.AccessToken); err != nil {
+		t.Fatalf("expected the store to be trusted without a database lookup, got %v", err)
+	}
+	f.store.down = true
+	if _, err := f.svc.ValidateToken(ctx, tokens.AccessToken); !errors.Is(err, authapp.ErrSessionRevoked) {
+		t.Fatalf("expected ErrSessionRevoked from the database, got %v", err)
+	}
+}
@@ -2,11 +2,16 @@ package auth
 
 import (
 	"context"
+	"time"
 
 	"github.com/SURF-Innovatie/MORIS/ent"
+	"github.com/SURF-Innovatie/MORIS/ent/predicate"
+	sessionent "github.com/SURF-Innovatie/MORIS/ent/session"
 	userent "github.com/SURF-Innovatie/MORIS/ent/user"
 	authapp "github.com/SURF-Innovatie/MORIS/internal/app/auth"
 	"github.com/SURF-Innovatie/MORIS/internal/app/user"
+	"github.com/SURF-Innovatie/MORIS/internal/common/transform"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/identity"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/identity/readmodels"
 	"github.com/google/uuid"
 )
@@ -39,4 +44,94 @@ func (r *EntRepo) GetPasswordHash(ctx context.Context, userID uuid.UUID) (string
 	return u.Password, nil
 }
 
+func (r *EntRepo) CreateSession(ctx context.Context, s identity.Session, tokenHash string) (*identity.Session, error) {
+	row, err := r.client.Session.Create().
+		SetUserID(s.UserID).
+		SetRefreshTokenHash(tokenHash).
+		SetNillableUserAgent(s.UserAgent).
+		SetNillableIPAddress(s.IPAddress).
+		SetCreatedAt(s.CreatedAt).
+		SetLastUsedAt(s.LastUsedAt).
+		SetExpiresAt(s.ExpiresAt).
+		SetNillableImpersonatorUserID(s.ImpersonatorUserID).
+		SetNillableImpersonationReason(s.ImpersonationReason).
+		Save(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return transform.ToEntityPtr[identity.Session](row), nil
+}
+
+func (r *EntRepo) SessionByTokenHash(ctx context.Context, tokenHash string) (*identity.Session, error) {
+	return r.session(ctx, sessionent.RefreshTokenHashEQ(tokenHash))
+}
+
+func (r *EntRepo) SessionByPreviousTokenHash(ctx context.Context, tokenHash string) (*identity.Session, error) {
+	return r.session(ctx, sessionent.PreviousTokenHashEQ(tokenHash))
+}
+
+func (r *EntRepo) GetSession(ctx context.Context, id uuid.UUID) (*identity.Session, error) {
+	return r.session(ctx, sessionent.IDEQ(id))
+}
+
+func (r *EntRepo) session(ctx context.Context, where ...predicate.Session) (*identity.Session, error) {
+	row, err := r.client.Session.Query().Where(where...).First(ctx)
+	if ent.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return transform.ToEntityPtr[identity.Session](row), nil
+}
+
+func (r *EntRepo) RotateSession(ctx context.Context, id uuid.UUID, currentHash, newHash string, usedAt, expiresAt time.Time) (bool, error) {
+	n, err := r.client.Session.Update().
+		Where(
+			sessionent.IDEQ(id),
+			sessionent.RefreshTokenHashEQ(currentHash),
+			sessionent.RevokedAtIsNil(),
+		).
+		SetRefreshTokenHash(newHash).
+		SetPreviousTokenHash(currentHash).
+		SetLastUsedAt(usedAt).
+		SetExpiresAt(expiresAt).
+		Save(ctx)
+	return n > 0, err
+}
+
+func (r *EntRepo) ListSessions(ctx context.Context, userID uuid.UUID, at time.Time) ([]identity.Session, error) {
+	rows, err := r.client.Session.Query().
+		Where(
+			sessionent.UserIDEQ(userID),
+			sessionent.RevokedAtIsNil(),
+			sessionent.ExpiresAtGT(at),
+		).
+		Order(ent.Desc(sessionent.FieldLastUsedAt)).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return transform.ToEntities[identity.Session](rows), nil
+}
+
+func (r *EntRepo) ListImpersonations(ctx context.Context) ([]identity.Session, error) {
+	rows, err := r.client.Session.Query().
+		Where(sessionent.ImpersonatorUserIDNotNil()).
+		Order(ent.Desc(sessionent.FieldCreatedAt)).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return transform.ToEntities[identity.Session](rows), nil
+}
+
+func (r *EntRepo) RevokeSession(ctx context.Context, id uuid.UUID, at time.Time) error {
+	_, err := r.client.Session.Update().
+		Where(sessionent.IDEQ(id), sessionent.RevokedAtIsNil()).
+		SetRevokedAt(at).
+		Save(ctx)
+	return err
+}
+
 var _ authapp.Repository = (*EntRepo)(nil)
@@ -10,6 +10,7 @@ import (
 	"github.com/SURF-Innovatie/MORIS/internal/app/customfield"
 	"github.com/SURF-Innovatie/MORIS/internal/common/transform"
 	customfield2 "github.com/SURF-Innovatie/MORIS/internal/domain/customfield"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/persistence/enttx"
 	"github.com/google/uuid"
 )
 
@@ -21,8 +22,16 @@ func NewRepository(cli *ent.Client) customfield.Repository {
 	return &repo{cli: cli}
 }
 
+// client returns the transactional client when ctx carries a transaction
+func (r *repo) client(ctx context.Context) *ent.Client {
+	if tx, ok := enttx.TxFromContext(ctx); ok {
+		return tx.Client()
+	}
+	return r.cli
+}
+
 func (r *repo) Create(ctx context.Context, in customfield.CreateDefinitionInput) (*customfield2.Definition, error) {
-	creator := r.cli.CustomFieldDefinition.Create().
+	creator := r.client(ctx).CustomFieldDefinition.Create().
 		SetOrganisationNodeID(in.OrgID).
 		SetName(in.Name).
 		SetType(customfielddefinition.Type(in.Type)).
@@ -46,12 +55,20 @@ func (r *repo) Create(ctx context.Context, in customfield.CreateDefinitionInput)
 	return transform.ToEntityPtr[customfield2.Definition](row), nil
 }
 
+func (r *repo) Get(ctx context.Context, id uuid.UUID) (*customfield2.Definition, error) {
+	row, err := r.client(ctx).CustomFieldDefinition.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return transform.ToEntityPtr[customfield2.Definition](row), nil
+}
+
 func (r *repo) Delete(ctx context.Context, id uuid.UUID) error {
-	return r.cli.CustomFieldDefinition.DeleteOneID(id).Exec(ctx)
+	return r.client(ctx).CustomFieldDefinition.DeleteOneID(id).Exec(ctx)
 }
 
 func (r *repo) ExistsInOrg(ctx context.Context, id uuid.UUID, orgID uuid.UUID) (bool, error) {
-	return r.cli.CustomFieldDefinition.Query().
+	return r.client(ctx).CustomFieldDefinition.Query().
 		Where(
 			customfielddefinition.ID(id),
 			customfielddefinition.OrganisationNodeID(orgID),
@@ -61,7 +78,7 @@ func (r *repo) ExistsInOrg(ctx context.Context, id uuid.UUID, orgID uuid.UUID) (
 
 func (r *repo) ListAvailableForNode(ctx context.Context, orgID uuid.UUID, category *customfield2.Category) ([]customfield2.Definition, error) {
 	// Use closure table to find ancestors (including self if closure contains it)
-	ancestors, err := r.cli.OrganisationNodeClosure.Query().
+	ancestors, err := r.client(ctx).OrganisationNodeClosure.Query().
 		Where(organisationnodeclosure.DescendantIDEQ(orgID)).
 		All(ctx)
 	if err != nil {
@@ -73,7 +90,7 @@ func (r *repo) ListAvailableForNode(ctx context.Context, orgID uuid.UUID, catego
 		validOrgIDs = append(validOrgIDs, a.AncestorID)
 	}
 
-	q := r.cli.CustomFieldDefinition.Query().
+	q := r.client(ctx).CustomFieldDefinition.Query().
 		Where(customfielddefinition.OrganisationNodeIDIn(validOrgIDs...))
 
 	if category != nil {
@@ -92,6 +92,7 @@ func (s *EntRepo) Append(
 			SetStatus(en.Status(e.GetStatus())).
 			SetOccurredAt(now).
 			SetCreatedBy(createdBy).
+			SetNillableImpersonatorID(e.ImpersonatorID()).
 			SetData(dataMap)
 	}
 
@@ -114,6 +115,7 @@ func (s *EntRepo) Append(
 			ProjectID:       projectID,
 			At:              now,
 			CreatedBy:       e.CreatedByID(),
+			Impersonator:    e.ImpersonatorID(),
 			Status:          e.GetStatus(),
 			FriendlyNameStr: e.FriendlyName(),
 		}
@@ -209,13 +211,53 @@ func (s *EntRepo) LoadUserApprovedEvents(ctx context.Context, userID uuid.UUID)
 	return out, nil
 }
 
+// LoadBetween loads a page of the events of the given types that occurred within [from, to], oldest first.
+// If no event types are given, events of every type are returned; if a project is given, only its events are.
+func (s *EntRepo) LoadBetween(ctx context.Context, from, to time.Time, eventTypes []string, projectID *uuid.UUID, offset, limit int) ([]events2.Event, error) {
+	q := s.cli.Event.
+		Query().
+		Where(
+			en.OccurredAtGTE(from),
+			en.OccurredAtLTE(to),
+		)
+	if len(eventTypes) > 0 {
+		q = q.Where(en.TypeIn(eventTypes...))
+	}
+	if projectID != nil {
+		q = q.Where(en.ProjectIDEQ(*projectID))
+	}
+
+	rows, err := q.
+		Order(ent.Asc(en.FieldOccurredAt), ent.Asc(en.FieldID)).
+		Offset(offset).
+		Limit(limit).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]events2.Event, 0, len(rows))
+	for _, r := range rows {
+		evt, err := s.mapEventRow(r)
+		if err != nil {
+			return nil, err
+		}
+		if evt != nil {
+			out = append(out, evt)
+		}
+	}
+
+	return out, nil
+}
+
 func (s *EntRepo) mapEventRow(r *ent.Event) (events2.Event, error) {
 	base := events2.Base{
-		ID:        r.ID,
-		ProjectID: r.ProjectID,
-		At:        r.OccurredAt,
-		CreatedBy: r.CreatedBy,
-		Status:    events2.Status(r.Status),
+		ID:           r.ID,
+		ProjectID:    r.ProjectID,
+		At:           r.OccurredAt,
+		CreatedBy:    r.CreatedBy,
+		Status:       events2.Status(r.Status),
+		Impersonator: r.ImpersonatorID,
 	}
 
 	evt, err := events2.Create(r.Type)
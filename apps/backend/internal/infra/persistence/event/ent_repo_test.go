@@ -71,3 +71,41 @@ func TestEntStore_AppendAndLoad(t *testing.T) {
 		t.Errorf("expected description %q, got %q", evt.Description, loadedEvt.Description)
 	}
 }
+
+func TestEntStore_KeepsImpersonator(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:ent_impersonator?mode=memory&cache=shared&_fk=1")
+	defer client.Close()
+
+	store := event.NewEntRepo(client)
+	ctx := context.Background()
+
+	projectID := uuid.New()
+	userID := uuid.New()
+	adminID := uuid.New()
+
+	evt := &events2.ProjectStarted{
+		Base:            events2.NewBase(projectID, userID, events2.StatusApproved),
+		Title:           "Impersonated Project",
+		OwningOrgNodeID: uuid.New(),
+	}
+	evt.SetImpersonator(adminID)
+
+	if err := store.Append(ctx, projectID, 0, evt); err != nil {
+		t.Fatalf("failed to append event: %v", err)
+	}
+
+	loadedEvents, _, err := store.Load(ctx, projectID)
+	if err != nil {
+		t.Fatalf("failed to load events: %v", err)
+	}
+	if len(loadedEvents) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(loadedEvents))
+	}
+	loaded := loadedEvents[0]
+	if loaded.CreatedByID() != userID {
+		t.Errorf("expected creator %s, got %s", userID, loaded.CreatedByID())
+	}
+	if loaded.ImpersonatorID() == nil || *loaded.ImpersonatorID() != adminID {
+		t.Errorf("expected impersonator %s, got %v", adminID, loaded.ImpersonatorID())
+	}
+}
@@ -2,9 +2,12 @@ package eventpolicy
 
 import (
 	"context"
+	"time"
 
 	"github.com/SURF-Innovatie/MORIS/ent"
 	"github.com/SURF-Innovatie/MORIS/ent/eventpolicy"
+	"github.com/SURF-Innovatie/MORIS/ent/policyexecution"
+	"github.com/SURF-Innovatie/MORIS/ent/scheduledpolicyrun"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/policy"
 	"github.com/google/uuid"
 	"github.com/samber/lo"
@@ -22,10 +25,18 @@ func NewEntRepository(client *ent.Client) *EntRepo {
 func (r *EntRepo) Create(ctx context.Context, eventPolicy policy.EventPolicy) (*policy.EventPolicy, error) {
 	create := r.client.EventPolicy.Create().
 		SetName(eventPolicy.Name).
-		SetEventTypes(eventPolicy.EventTypes).
+		SetTriggerType(triggerType(eventPolicy)).
+		SetEventTypes(lo.CoalesceSliceOrEmpty(eventPolicy.EventTypes)).
+		SetNillableScheduleAnchor(eventPolicy.ScheduleAnchor).
+		SetNillableScheduleOffsetDays(eventPolicy.ScheduleOffsetDays).
 		SetActionType(eventpolicy.ActionType(eventPolicy.ActionType)).
-		SetEnabled(eventPolicy.Enabled)
+		SetEnabled(eventPolicy.Enabled).
+		SetLocked(eventPolicy.Locked)
 
+	// Project policies created through events keep the policy ID carried by the event
+	if eventPolicy.ID != uuid.Nil {
+		create.SetID(eventPolicy.ID)
+	}
 	if eventPolicy.Description != nil {
 		create.SetDescription(*eventPolicy.Description)
 	}
@@ -35,6 +46,12 @@ func (r *EntRepo) Create(ctx context.Context, eventPolicy policy.EventPolicy) (*
 	if eventPolicy.MessageTemplate != nil {
 		create.SetMessageTemplate(*eventPolicy.MessageTemplate)
 	}
+	if eventPolicy.WebhookURL != nil {
+		create.SetWebhookURL(*eventPolicy.WebhookURL)
+	}
+	if eventPolicy.WebhookSecret != nil {
+		create.SetWebhookSecret(*eventPolicy.WebhookSecret)
+	}
 	if len(eventPolicy.RecipientUserIDs) > 0 {
 		create.SetRecipientUserIds(eventPolicy.RecipientUserIDs)
 	}
@@ -64,10 +81,20 @@ func (r *EntRepo) Create(ctx context.Context, eventPolicy policy.EventPolicy) (*
 
 func (r *EntRepo) Update(ctx context.Context, id uuid.UUID, eventPolicy policy.EventPolicy) (*policy.EventPolicy, error) {
 	update := r.client.EventPolicy.UpdateOneID(id).
+		AddVersion(1).
 		SetName(eventPolicy.Name).
-		SetEventTypes(eventPolicy.EventTypes).
+		SetTriggerType(triggerType(eventPolicy)).
+		SetEventTypes(lo.CoalesceSliceOrEmpty(eventPolicy.EventTypes)).
 		SetActionType(eventpolicy.ActionType(eventPolicy.ActionType)).
-		SetEnabled(eventPolicy.Enabled)
+		SetEnabled(eventPolicy.Enabled).
+		SetLocked(eventPolicy.Locked)
+
+	if eventPolicy.IsScheduled() {
+		update.SetNillableScheduleAnchor(eventPolicy.ScheduleAnchor).
+			SetNillableScheduleOffsetDays(eventPolicy.ScheduleOffsetDays)
+	} else {
+		update.ClearScheduleAnchor().ClearScheduleOffsetDays()
+	}
 
 	if eventPolicy.Description != nil {
 		update.SetDescription(*eventPolicy.Description)
@@ -87,6 +114,19 @@ func (r *EntRepo) Update(ctx context.Context, id uuid.UUID, eventPolicy policy.E
 		update.ClearMessageTemplate()
 	}
 
+	if eventPolicy.ActionType == policy.ActionTypeWebhook {
+		if eventPolicy.WebhookURL != nil {
+			update.SetWebhookURL(*eventPolicy.WebhookURL)
+		}
+		// A nil secret keeps the stored one so that it does not have to be resent on every update
+		if eventPolicy.WebhookSecret != nil {
+			update.SetWebhookSecret(*eventPolicy.WebhookSecret)
+		}
+	} else {
+		update.ClearWebhookURL()
+		update.ClearWebhookSecret()
+	}
+
 	if len(eventPolicy.RecipientUserIDs) > 0 {
 		update.SetRecipientUserIds(eventPolicy.RecipientUserIDs)
 	} else {
@@ -159,3 +199,120 @@ func (r *EntRepo) ListForProject(ctx context.Context, projectID uuid.UUID) ([]po
 		return *new(policy.EventPolicy).FromEnt(row)
 	}), nil
 }
+
+// ListScheduled returns all enabled policies that are triggered by the scheduler
+func (r *EntRepo) ListScheduled(ctx context.Context) ([]policy.EventPolicy, error) {
+	rows, err := r.client.EventPolicy.Query().
+		Where(
+			eventpolicy.TriggerTypeEQ(eventpolicy.TriggerTypeSchedule),
+			eventpolicy.EnabledEQ(true),
+		).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return lo.Map(rows, func(row *ent.EventPolicy, _ int) policy.EventPolicy {
+		return *new(policy.EventPolicy).FromEnt(row)
+	}), nil
+}
+
+// ClaimScheduledRun records that a scheduled policy is firing for a project on the given due date.
+// It returns false if the run was already claimed.
+func (r *EntRepo) ClaimScheduledRun(ctx context.Context, policyID, projectID uuid.UUID, dueAt time.Time) (bool, error) {
+	err := r.client.ScheduledPolicyRun.Create().
+		SetPolicyID(policyID).
+		SetProjectID(projectID).
+		SetDueAt(dueAt).
+		Exec(ctx)
+	if ent.IsConstraintError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ReleaseScheduledRun removes a claimed run so that it is retried by the next scheduler pass
+func (r *EntRepo) ReleaseScheduledRun(ctx context.Context, policyID, projectID uuid.UUID, dueAt time.Time) error {
+	_, err := r.client.ScheduledPolicyRun.Delete().
+		Where(
+			scheduledpolicyrun.PolicyIDEQ(policyID),
+			scheduledpolicyrun.ProjectIDEQ(projectID),
+			scheduledpolicyrun.DueAtEQ(dueAt),
+		).
+		Exec(ctx)
+	return err
+}
+
+func triggerType(p policy.EventPolicy) eventpolicy.TriggerType {
+	if p.IsScheduled() {
+		return eventpolicy.TriggerTypeSchedule
+	}
+	return eventpolicy.TriggerTypeEvent
+}
+
+// RecordExecution stores the audit record of a matched policy
+func (r *EntRepo) RecordExecution(ctx context.Context, x policy.Execution) error {
+	create := r.client.PolicyExecution.Create().
+		SetPolicyID(x.PolicyID).
+		SetPolicyVersion(x.PolicyVersion).
+		SetPolicyName(x.PolicyName).
+		SetTrigger(policyexecution.Trigger(x.Trigger)).
+		SetNillableEventID(x.EventID).
+		SetEventType(x.EventType).
+		SetProjectID(x.ProjectID).
+		SetActionType(policyexecution.ActionType(x.ActionType)).
+		SetOutcome(policyexecution.Outcome(x.Outcome)).
+		SetNillableMessage(x.Message).
+		SetNillableDetail(x.Detail)
+
+	if len(x.ConditionResults) > 0 {
+		create.SetConditionResults(x.ConditionResultsToMap())
+	}
+	if len(x.RecipientUserIDs) > 0 {
+		create.SetRecipientUserIds(x.RecipientUserIDs)
+	}
+
+	return create.Exec(ctx)
+}
+
+// ListExecutionsForEvent returns the policies that matched an event, oldest first
+func (r *EntRepo) ListExecutionsForEvent(ctx context.Context, eventID uuid.UUID) ([]policy.Execution, error) {
+	rows, err := r.client.PolicyExecution.Query().
+		Where(policyexecution.EventIDEQ(eventID)).
+		Order(ent.Asc(policyexecution.FieldCreatedAt)).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return lo.Map(rows, func(row *ent.PolicyExecution, _ int) policy.Execution {
+		return *new(policy.Execution).FromEnt(row)
+	}), nil
+}
+
+// ListExecutionsForPolicy returns a page of a policy's execution history, newest first, and the total count
+func (r *EntRepo) ListExecutionsForPolicy(ctx context.Context, policyID uuid.UUID, limit, offset int) ([]policy.Execution, int, error) {
+	q := r.client.PolicyExecution.Query().
+		Where(policyexecution.PolicyIDEQ(policyID))
+
+	total, err := q.Clone().Count(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := q.
+		Order(ent.Desc(policyexecution.FieldCreatedAt)).
+		Limit(limit).
+		Offset(offset).
+		All(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return lo.Map(rows, func(row *ent.PolicyExecution, _ int) policy.Execution {
+		return *new(policy.Execution).FromEnt(row)
+	}), total, nil
+}
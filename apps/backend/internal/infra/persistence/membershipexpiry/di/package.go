@@ -0,0 +1,16 @@
+package di
+
+import (
+	"github.com/SURF-Innovatie/MORIS/ent"
+	membershipexpiryrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/membershipexpiry"
+	"github.com/samber/do/v2"
+)
+
+var Package = do.Package(
+	do.Lazy(provideMembershipExpiryRepo),
+)
+
+func provideMembershipExpiryRepo(i do.Injector) (*membershipexpiryrepo.EntRepo, error) {
+	cli := do.MustInvoke[*ent.Client](i)
+	return membershipexpiryrepo.NewEntRepo(cli), nil
+}
@@ -0,0 +1,166 @@
+package membershipexpiry
+
+import (
+	"context"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/ent"
+	entmembership "github.com/SURF-Innovatie/MORIS/ent/membership"
+	entnotice "github.com/SURF-Innovatie/MORIS/ent/membershipexpirynotice"
+	entclosure "github.com/SURF-Innovatie/MORIS/ent/organisationnodeclosure"
+	entrolescope "github.com/SURF-Innovatie/MORIS/ent/rolescope"
+	entuser "github.com/SURF-Innovatie/MORIS/ent/user"
+	"github.com/SURF-Innovatie/MORIS/internal/app/membershipexpiry"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/locale"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation/rbac"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+type EntRepo struct {
+	cli *ent.Client
+}
+
+func NewEntRepo(cli *ent.Client) *EntRepo {
+	return &EntRepo{cli: cli}
+}
+
+func (r *EntRepo) ExpiringMemberships(ctx context.Context, from, to time.Time) ([]membershipexpiry.ExpiringMembership, error) {
+	rows, err := r.cli.Membership.
+		Query().
+		Where(entmembership.EndDateGTE(from), entmembership.EndDateLT(to)).
+		WithPerson().
+		WithRoleScope(func(q *ent.RoleScopeQuery) { q.WithRole().WithRootNode() }).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return lo.FilterMap(rows, func(m *ent.Membership, _ int) (membershipexpiry.ExpiringMembership, bool) {
+		sc := m.Edges.RoleScope
+		if sc == nil || sc.Edges.Role == nil || sc.Edges.RootNode == nil || m.Edges.Person == nil {
+			return membershipexpiry.ExpiringMembership{}, false
+		}
+		return membershipexpiry.ExpiringMembership{
+			MembershipID:  m.ID,
+			PersonID:      m.PersonID,
+			PersonName:    m.Edges.Person.Name,
+			RoleScopeID:   sc.ID,
+			RoleKey:       sc.Edges.Role.Key,
+			ScopeNodeID:   sc.RootNodeID,
+			ScopeNodeName: sc.Edges.RootNode.Name,
+			EndDate:       *m.EndDate,
+		}, true
+	}), nil
+}
+
+func (r *EntRepo) MemberManagers(ctx context.Context, nodeID uuid.UUID, at time.Time) ([]uuid.UUID, error) {
+	var ancestorIDs []uuid.UUID
+	if err := r.cli.OrganisationNodeClosure.
+		Query().
+		Where(entclosure.DescendantIDEQ(nodeID)).
+		Select(entclosure.FieldAncestorID).
+		Scan(ctx, &ancestorIDs); err != nil {
+		return nil, err
+	}
+
+	scopes, err := r.cli.RoleScope.
+		Query().
+		Where(entrolescope.RootNodeIDIn(ancestorIDs...)).
+		WithRole().
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	scopeIDs := lo.FilterMap(scopes, func(sc *ent.RoleScope, _ int) (uuid.UUID, bool) {
+		return sc.ID, sc.Edges.Role != nil && lo.Contains(sc.Edges.Role.Permissions, string(rbac.PermissionManageMembers))
+	})
+	if len(scopeIDs) == 0 {
+		return []uuid.UUID{}, nil
+	}
+
+	memberships, err := r.cli.Membership.
+		Query().
+		Where(entmembership.RoleScopeIDIn(scopeIDs...)).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	personIDs := lo.FilterMap(memberships, func(m *ent.Membership, _ int) (uuid.UUID, bool) {
+		return m.PersonID, rbac.MembershipPeriodContains(m.StartDate, m.EndDate, at)
+	})
+	if len(personIDs) == 0 {
+		return []uuid.UUID{}, nil
+	}
+
+	var userIDs []uuid.UUID
+	if err := r.cli.User.
+		Query().
+		Where(entuser.PersonIDIn(lo.Uniq(personIDs)...)).
+		Select(entuser.FieldID).
+		Scan(ctx, &userIDs); err != nil {
+		return nil, err
+	}
+	return userIDs, nil
+}
+
+func (r *EntRepo) PersonName(ctx context.Context, personID uuid.UUID) (string, error) {
+	p, err := r.cli.Person.Get(ctx, personID)
+	if err != nil {
+		return "", err
+	}
+	return p.Name, nil
+}
+
+func (r *EntRepo) ProjectRoleName(ctx context.Context, roleID uuid.UUID) (string, error) {
+	pr, err := r.cli.ProjectRole.Get(ctx, roleID)
+	if err != nil {
+		return "", err
+	}
+	return pr.Name, nil
+}
+
+func (r *EntRepo) ResolveLanguages(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]locale.Language, error) {
+	rows, err := r.cli.User.Query().
+		Where(entuser.IDIn(userIDs...)).
+		Select(entuser.FieldID, entuser.FieldLanguage).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return lo.SliceToMap(rows, func(u *ent.User) (uuid.UUID, locale.Language) {
+		return u.ID, locale.Language(u.Language)
+	}), nil
+}
+
+func (r *EntRepo) ClaimNotice(ctx context.Context, n membershipexpiry.Notice) (bool, error) {
+	err := r.cli.MembershipExpiryNotice.
+		Create().
+		SetKind(entnotice.Kind(n.Kind)).
+		SetSubjectID(n.SubjectID).
+		SetPersonID(n.PersonID).
+		SetRoleID(n.RoleID).
+		SetEndDate(n.EndDate).
+		Exec(ctx)
+	if ent.IsConstraintError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *EntRepo) ReleaseNotice(ctx context.Context, n membershipexpiry.Notice) error {
+	_, err := r.cli.MembershipExpiryNotice.
+		Delete().
+		Where(
+			entnotice.KindEQ(entnotice.Kind(n.Kind)),
+			entnotice.SubjectIDEQ(n.SubjectID),
+			entnotice.PersonIDEQ(n.PersonID),
+			entnotice.RoleIDEQ(n.RoleID),
+			entnotice.EndDateEQ(n.EndDate),
+		).
+		Exec(ctx)
+	return err
+}
@@ -2,9 +2,11 @@ package notification
 
 import (
 	"context"
+	"time"
 
 	"github.com/SURF-Innovatie/MORIS/ent"
 	entnotification "github.com/SURF-Innovatie/MORIS/ent/notification"
+	"github.com/SURF-Innovatie/MORIS/ent/predicate"
 	entuser "github.com/SURF-Innovatie/MORIS/ent/user"
 	"github.com/SURF-Innovatie/MORIS/internal/common/transform"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/notification"
@@ -29,6 +31,15 @@ func (r *EntRepo) Create(ctx context.Context, n notification.Notification) (*not
 	if n.EventID != nil && *n.EventID != uuid.Nil {
 		create.SetEventID(*n.EventID)
 	}
+	if len(n.Variables) > 0 {
+		create.SetVariables(n.Variables)
+	}
+	if n.EmailStatus != nil {
+		create.SetEmailStatus(entnotification.EmailStatus(*n.EmailStatus))
+	}
+	if n.EmailDigest != nil {
+		create.SetEmailDigest(entnotification.EmailDigest(*n.EmailDigest))
+	}
 
 	row, err := create.Save(ctx)
 	if err != nil {
@@ -95,7 +106,7 @@ func (r *EntRepo) List(ctx context.Context) ([]notification.Notification, error)
 
 func (r *EntRepo) ListForUser(ctx context.Context, userID uuid.UUID) ([]notification.Notification, error) {
 	rows, err := r.cli.Notification.Query().
-		Where(entnotification.HasUserWith(entuser.IDEQ(userID))).
+		Where(entnotification.HasUserWith(entuser.IDEQ(userID)), entnotification.ArchivedAtIsNil()).
 		Order(ent.Desc(entnotification.FieldSentAt)).
 		WithEvent().
 		All(ctx)
@@ -126,3 +137,93 @@ func (r *EntRepo) MarkAsReadByEventID(ctx context.Context, eventID uuid.UUID) er
 		Save(ctx)
 	return err
 }
+
+// ClaimPendingEmails returns up to limit notifications whose email is due and does not wait for
+// a digest, oldest first, and holds them back from other workers for lease
+func (r *EntRepo) ClaimPendingEmails(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]notification.Notification, error) {
+	return r.claimPending(ctx, now, lease, limit, entnotification.EmailDigestIsNil())
+}
+
+// ClaimPendingDigest returns the due notifications waiting for the given digest, oldest first,
+// and holds them back from other workers for lease
+func (r *EntRepo) ClaimPendingDigest(ctx context.Context, frequency notification.DigestFrequency, now time.Time, lease time.Duration) ([]notification.Notification, error) {
+	return r.claimPending(ctx, now, lease, 0, entnotification.EmailDigestEQ(entnotification.EmailDigest(frequency)))
+}
+
+// claimPending selects the due pending emails and claims each one with a conditional update, so
+// that a notification selected by two workers at once is only returned to one of them
+func (r *EntRepo) claimPending(ctx context.Context, now time.Time, lease time.Duration, limit int, where predicate.Notification) ([]notification.Notification, error) {
+	due := []predicate.Notification{
+		entnotification.EmailStatusEQ(entnotification.EmailStatusPending),
+		where,
+		entnotification.Or(
+			entnotification.EmailNextAttemptAtIsNil(),
+			entnotification.EmailNextAttemptAtLTE(now),
+		),
+	}
+	q := r.cli.Notification.Query().
+		Where(due...).
+		Order(ent.Asc(entnotification.FieldSentAt)).
+		WithEvent()
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	rows, err := q.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claimed := make([]*ent.Notification, 0, len(rows))
+	for _, row := range rows {
+		n, err := r.cli.Notification.Update().
+			Where(entnotification.IDEQ(row.ID)).
+			Where(due...).
+			SetEmailNextAttemptAt(now.Add(lease)).
+			Save(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if n == 1 {
+			claimed = append(claimed, row)
+		}
+	}
+
+	dtos := transform.ToEntities[notification.Notification](claimed)
+	for i, row := range claimed {
+		if row.Edges.Event != nil {
+			meta := events.GetMeta(row.Edges.Event.Type)
+			fname := meta.FriendlyName
+			dtos[i].EventFriendlyName = &fname
+		}
+	}
+	return dtos, nil
+}
+
+// EventType returns the type of the event a notification is about
+func (r *EntRepo) EventType(ctx context.Context, eventID uuid.UUID) (string, error) {
+	row, err := r.cli.Event.Get(ctx, eventID)
+	if err != nil {
+		return "", err
+	}
+	return row.Type, nil
+}
+
+// RecordEmailAttempt records the outcome of an email delivery attempt
+func (r *EntRepo) RecordEmailAttempt(ctx context.Context, id uuid.UUID, attempt notification.EmailAttempt) error {
+	upd := r.cli.Notification.UpdateOneID(id).
+		AddEmailAttempts(1).
+		SetEmailStatus(entnotification.EmailStatus(attempt.Status)).
+		SetNillableEmailSentAt(attempt.SentAt)
+	if attempt.Error != nil {
+		upd.SetEmailError(*attempt.Error)
+	} else {
+		upd.ClearEmailError()
+	}
+	if attempt.NextAttemptAt != nil {
+		upd.SetEmailNextAttemptAt(*attempt.NextAttemptAt)
+	} else {
+		upd.ClearEmailNextAttemptAt()
+	}
+	_, err := upd.Save(ctx)
+	return err
+}
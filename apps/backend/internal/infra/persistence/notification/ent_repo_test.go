@@ -0,0 +1,77 @@
+package notification_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/ent/enttest"
+	entnotification "github.com/SURF-Innovatie/MORIS/ent/notification"
+	notificationdomain "github.com/SURF-Innovatie/MORIS/internal/domain/notification"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/persistence/notification"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestEntRepo_ClaimPendingEmailsLeasesAndRecordsAttempts(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:notification_"+uuid.NewString()+"?mode=memory&cache=shared&_fk=1")
+	defer client.Close()
+
+	ctx := context.Background()
+	repo := notification.NewEntRepo(client)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	user := client.User.Create().SaveX(ctx)
+	pending := client.Notification.Create().SetUserID(user.ID).SetMessage("n").
+		SetEmailStatus(entnotification.EmailStatusPending).SaveX(ctx)
+	client.Notification.Create().SetUserID(user.ID).SetMessage("digest").
+		SetEmailStatus(entnotification.EmailStatusPending).SetEmailDigest(entnotification.EmailDigestDaily).SaveX(ctx)
+
+	claimed, err := repo.ClaimPendingEmails(ctx, now, time.Minute, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(claimed) != 1 || claimed[0].ID != pending.ID {
+		t.Fatalf("expected the immediate email to be claimed, got %+v", claimed)
+	}
+
+	// A second worker does not get the claimed email, until the lease expires
+	if again, err := repo.ClaimPendingEmails(ctx, now, time.Minute, 10); err != nil || len(again) != 0 {
+		t.Fatalf("expected no emails during the lease, got %d (%v)", len(again), err)
+	}
+	if again, err := repo.ClaimPendingEmails(ctx, now.Add(2*time.Minute), time.Minute, 10); err != nil || len(again) != 1 {
+		t.Fatalf("expected the email after the lease expired, got %d (%v)", len(again), err)
+	}
+	if digest, err := repo.ClaimPendingDigest(ctx, notificationdomain.DigestDaily, now, time.Minute); err != nil || len(digest) != 1 {
+		t.Fatalf("expected the digest email to be claimed, got %d (%v)", len(digest), err)
+	}
+
+	// A transient failure is retried at the given time; the retry clears the error
+	retryAt := now.Add(5 * time.Minute)
+	if err := repo.RecordEmailAttempt(ctx, pending.ID, notificationdomain.EmailAttempt{
+		Status: notificationdomain.EmailPending, Error: lo.ToPtr("421 try again later"), NextAttemptAt: &retryAt,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if again, err := repo.ClaimPendingEmails(ctx, retryAt.Add(-time.Second), time.Minute, 10); err != nil || len(again) != 0 {
+		t.Fatalf("expected no emails before the retry, got %d (%v)", len(again), err)
+	}
+	if err := repo.RecordEmailAttempt(ctx, pending.ID, notificationdomain.EmailAttempt{
+		Status: notificationdomain.EmailSent, SentAt: &retryAt,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := repo.Get(ctx, pending.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *got.EmailStatus != notificationdomain.EmailSent || got.EmailAttempts != 2 {
+		t.Fatalf("unexpected notification %+v", got)
+	}
+	if got.EmailError != nil || got.EmailNextAttemptAt != nil {
+		t.Fatalf("expected the error and retry to be cleared, got %v / %v", got.EmailError, got.EmailNextAttemptAt)
+	}
+}
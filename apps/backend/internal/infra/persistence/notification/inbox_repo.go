@@ -0,0 +1,165 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/ent"
+	entevent "github.com/SURF-Innovatie/MORIS/ent/event"
+	entnotification "github.com/SURF-Innovatie/MORIS/ent/notification"
+	"github.com/SURF-Innovatie/MORIS/ent/predicate"
+	"github.com/SURF-Innovatie/MORIS/internal/common/transform"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/notification"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/project/events"
+	"github.com/google/uuid"
+)
+
+// ListPage returns a page of a user's notifications, newest first
+func (r *EntRepo) ListPage(ctx context.Context, userID uuid.UUID, f notification.ListFilter) (*notification.Page, error) {
+	where := []predicate.Notification{entnotification.UserIDEQ(userID)}
+	if f.Archived {
+		where = append(where, entnotification.ArchivedAtNotNil())
+	} else {
+		where = append(where, entnotification.ArchivedAtIsNil())
+	}
+	if f.Type != nil {
+		where = append(where, entnotification.TypeEQ(entnotification.Type(*f.Type)))
+	}
+	if f.ProjectID != nil {
+		where = append(where, entnotification.HasEventWith(entevent.ProjectIDEQ(*f.ProjectID)))
+	}
+	if f.Read != nil {
+		where = append(where, entnotification.ReadEQ(*f.Read))
+	}
+	if f.After != nil {
+		where = append(where, entnotification.Or(
+			entnotification.SentAtLT(f.After.SentAt),
+			entnotification.And(entnotification.SentAtEQ(f.After.SentAt), entnotification.IDLT(f.After.ID)),
+		))
+	}
+
+	// Fetch one extra row to know whether there is a next page
+	rows, err := r.cli.Notification.Query().
+		Where(where...).
+		Order(ent.Desc(entnotification.FieldSentAt), ent.Desc(entnotification.FieldID)).
+		Limit(f.Limit + 1).
+		WithEvent().
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &notification.Page{}
+	if len(rows) > f.Limit {
+		rows = rows[:f.Limit]
+		last := rows[len(rows)-1]
+		page.NextCursor = &notification.Cursor{SentAt: last.SentAt, ID: last.ID}
+	}
+
+	page.Items = transform.ToEntities[notification.Notification](rows)
+	for i, row := range rows {
+		if row.Edges.Event != nil {
+			meta := events.GetMeta(row.Edges.Event.Type)
+			fname := meta.FriendlyName
+			page.Items[i].EventFriendlyName = &fname
+		}
+	}
+	return page, nil
+}
+
+// CountUnread counts a user's unread notifications in the inbox by type
+func (r *EntRepo) CountUnread(ctx context.Context, userID uuid.UUID) (*notification.UnreadCounts, error) {
+	var rows []struct {
+		Type  string `json:"type"`
+		Count int    `json:"count"`
+	}
+	err := r.cli.Notification.Query().
+		Where(
+			entnotification.UserIDEQ(userID),
+			entnotification.ReadEQ(false),
+			entnotification.ArchivedAtIsNil(),
+		).
+		GroupBy(entnotification.FieldType).
+		Aggregate(ent.Count()).
+		Scan(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := &notification.UnreadCounts{ByType: make(map[notification.NotificationType]int, len(rows))}
+	for _, row := range rows {
+		counts.ByType[notification.NotificationType(row.Type)] = row.Count
+		counts.Total += row.Count
+	}
+	return counts, nil
+}
+
+// MarkAsReadForUser marks one of the user's notifications as read
+func (r *EntRepo) MarkAsReadForUser(ctx context.Context, userID, id uuid.UUID) error {
+	return r.updateOwned(ctx, userID, id, func(u *ent.NotificationUpdate) { u.SetRead(true) })
+}
+
+// MarkAllAsRead marks all of the user's notifications as read and returns the number changed
+func (r *EntRepo) MarkAllAsRead(ctx context.Context, userID uuid.UUID) (int, error) {
+	return r.cli.Notification.Update().
+		Where(entnotification.UserIDEQ(userID), entnotification.ReadEQ(false)).
+		SetRead(true).
+		Save(ctx)
+}
+
+// MarkAsReadByProject marks the user's notifications about a project as read and returns the number changed
+func (r *EntRepo) MarkAsReadByProject(ctx context.Context, userID, projectID uuid.UUID) (int, error) {
+	return r.cli.Notification.Update().
+		Where(
+			entnotification.UserIDEQ(userID),
+			entnotification.ReadEQ(false),
+			entnotification.HasEventWith(entevent.ProjectIDEQ(projectID)),
+		).
+		SetRead(true).
+		Save(ctx)
+}
+
+// Archive hides one of the user's notifications from the inbox
+func (r *EntRepo) Archive(ctx context.Context, userID, id uuid.UUID) error {
+	return r.updateOwned(ctx, userID, id, func(u *ent.NotificationUpdate) { u.SetArchivedAt(time.Now()) })
+}
+
+// Unarchive moves one of the user's notifications back to the inbox
+func (r *EntRepo) Unarchive(ctx context.Context, userID, id uuid.UUID) error {
+	return r.updateOwned(ctx, userID, id, func(u *ent.NotificationUpdate) { u.ClearArchivedAt() })
+}
+
+// DeleteForUser deletes one of the user's notifications
+func (r *EntRepo) DeleteForUser(ctx context.Context, userID, id uuid.UUID) error {
+	n, err := r.cli.Notification.Delete().
+		Where(entnotification.IDEQ(id), entnotification.UserIDEQ(userID)).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return notification.ErrNotFound
+	}
+	return nil
+}
+
+// DeleteReadBefore deletes read notifications sent before cutoff and returns the number deleted
+func (r *EntRepo) DeleteReadBefore(ctx context.Context, cutoff time.Time) (int, error) {
+	return r.cli.Notification.Delete().
+		Where(entnotification.ReadEQ(true), entnotification.SentAtLT(cutoff)).
+		Exec(ctx)
+}
+
+func (r *EntRepo) updateOwned(ctx context.Context, userID, id uuid.UUID, apply func(*ent.NotificationUpdate)) error {
+	upd := r.cli.Notification.Update().
+		Where(entnotification.IDEQ(id), entnotification.UserIDEQ(userID))
+	apply(upd)
+	n, err := upd.Save(ctx)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return notification.ErrNotFound
+	}
+	return nil
+}
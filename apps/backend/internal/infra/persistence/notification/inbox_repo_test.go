@@ -0,0 +1,133 @@
+package notification_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/ent/enttest"
+	entnotification "github.com/SURF-Innovatie/MORIS/ent/notification"
+	notificationdomain "github.com/SURF-Innovatie/MORIS/internal/domain/notification"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/persistence/notification"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestEntRepo_Inbox(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:inbox?mode=memory&cache=shared&_fk=1")
+	defer client.Close()
+
+	ctx := context.Background()
+	repo := notification.NewEntRepo(client)
+
+	user := client.User.Create().SaveX(ctx)
+	other := client.User.Create().SaveX(ctx)
+	projectA, projectB := uuid.New(), uuid.New()
+	eventA := client.Event.Create().SetProjectID(projectA).SetVersion(1).SetType("project.title_changed").SaveX(ctx)
+	eventB := client.Event.Create().SetProjectID(projectB).SetVersion(1).SetType("project.title_changed").SaveX(ctx)
+
+	// Seven notifications one minute apart; two share a timestamp to exercise the ID tie-breaker
+	base := time.Now().UTC().Truncate(time.Second)
+	var ids []uuid.UUID
+	for i := 0; i < 7; i++ {
+		create := client.Notification.Create().
+			SetUserID(user.ID).
+			SetMessage("n").
+			SetSentAt(base.Add(-time.Duration(min(i, 5)) * time.Minute))
+		switch {
+		case i%2 == 0:
+			create.SetEventID(eventA.ID)
+		default:
+			create.SetEventID(eventB.ID).SetType(entnotification.TypeApprovalRequest)
+		}
+		ids = append(ids, create.SaveX(ctx).ID)
+	}
+	client.Notification.Create().SetUserID(other.ID).SetMessage("other").SetEventID(eventA.ID).SaveX(ctx)
+
+	// Walk all pages
+	var seen []uuid.UUID
+	var cursor *notificationdomain.Cursor
+	for pages := 0; ; pages++ {
+		page, err := repo.ListPage(ctx, user.ID, notificationdomain.ListFilter{Limit: 3, After: cursor})
+		if err != nil {
+			t.Fatalf("list page: %v", err)
+		}
+		seen = append(seen, lo.Map(page.Items, func(n notificationdomain.Notification, _ int) uuid.UUID { return n.ID })...)
+		if page.NextCursor == nil {
+			break
+		}
+		decoded, err := notificationdomain.DecodeCursor(page.NextCursor.Encode())
+		if err != nil {
+			t.Fatalf("decode cursor: %v", err)
+		}
+		cursor = decoded
+		if pages > 5 {
+			t.Fatal("pagination does not terminate")
+		}
+	}
+	if len(seen) != 7 || len(lo.Uniq(seen)) != 7 {
+		t.Fatalf("expected all 7 notifications exactly once, got %d (%d unique)", len(seen), len(lo.Uniq(seen)))
+	}
+
+	// Filters
+	approvals := notificationdomain.NotificationApprovalRequest
+	page, _ := repo.ListPage(ctx, user.ID, notificationdomain.ListFilter{Limit: 10, Type: &approvals})
+	if len(page.Items) != 3 {
+		t.Fatalf("expected 3 approval requests, got %d", len(page.Items))
+	}
+	page, _ = repo.ListPage(ctx, user.ID, notificationdomain.ListFilter{Limit: 10, ProjectID: &projectA})
+	if len(page.Items) != 4 || *page.Items[0].ProjectID != projectA {
+		t.Fatalf("expected 4 notifications for project A, got %d", len(page.Items))
+	}
+
+	// Read state and unread counts
+	if n, err := repo.MarkAsReadByProject(ctx, user.ID, projectA); err != nil || n != 4 {
+		t.Fatalf("expected 4 marked as read, got %d (%v)", n, err)
+	}
+	counts, _ := repo.CountUnread(ctx, user.ID)
+	if counts.Total != 3 || counts.ByType[notificationdomain.NotificationApprovalRequest] != 3 {
+		t.Fatalf("unexpected unread counts %+v", counts)
+	}
+	read := true
+	page, _ = repo.ListPage(ctx, user.ID, notificationdomain.ListFilter{Limit: 10, Read: &read})
+	if len(page.Items) != 4 {
+		t.Fatalf("expected 4 read notifications, got %d", len(page.Items))
+	}
+
+	// Archive and delete are scoped to the owner
+	if err := repo.Archive(ctx, other.ID, ids[1]); !errors.Is(err, notificationdomain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound archiving another user's notification, got %v", err)
+	}
+	if err := repo.Archive(ctx, user.ID, ids[1]); err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+	counts, _ = repo.CountUnread(ctx, user.ID)
+	if counts.Total != 2 {
+		t.Fatalf("archived notifications must not count as unread, got %d", counts.Total)
+	}
+	page, _ = repo.ListPage(ctx, user.ID, notificationdomain.ListFilter{Limit: 10, Archived: true})
+	if len(page.Items) != 1 || page.Items[0].ID != ids[1] {
+		t.Fatalf("expected the archived notification, got %+v", page.Items)
+	}
+	if err := repo.DeleteForUser(ctx, other.ID, ids[3]); !errors.Is(err, notificationdomain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound deleting another user's notification, got %v", err)
+	}
+	if err := repo.DeleteForUser(ctx, user.ID, ids[3]); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	if n, err := repo.MarkAllAsRead(ctx, user.ID); err != nil || n != 2 {
+		t.Fatalf("expected 2 marked as read, got %d (%v)", n, err)
+	}
+
+	// Retention removes read notifications older than the cutoff only
+	if n, err := repo.DeleteReadBefore(ctx, base.Add(-150*time.Second)); err != nil || n != 3 {
+		t.Fatalf("expected 3 purged, got %d (%v)", n, err)
+	}
+	if left := client.Notification.Query().CountX(ctx); left != 4 {
+		t.Fatalf("expected 4 notifications left, got %d", left)
+	}
+}
@@ -0,0 +1,56 @@
+package notification
+
+import (
+	"context"
+
+	"github.com/SURF-Innovatie/MORIS/ent"
+	entpref "github.com/SURF-Innovatie/MORIS/ent/notificationpreference"
+	"github.com/SURF-Innovatie/MORIS/internal/common/transform"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/notification"
+	"github.com/google/uuid"
+)
+
+// ListPreferences returns the notification preferences of the given users
+func (r *EntRepo) ListPreferences(ctx context.Context, userIDs []uuid.UUID) ([]notification.Preference, error) {
+	rows, err := r.cli.NotificationPreference.Query().
+		Where(entpref.UserIDIn(userIDs...)).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return transform.ToEntities[notification.Preference](rows), nil
+}
+
+// ReplacePreferences replaces all notification preferences of a user
+func (r *EntRepo) ReplacePreferences(ctx context.Context, userID uuid.UUID, prefs []notification.Preference) ([]notification.Preference, error) {
+	tx, err := r.cli.Tx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.NotificationPreference.Delete().Where(entpref.UserIDEQ(userID)).Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	builders := make([]*ent.NotificationPreferenceCreate, len(prefs))
+	for i, p := range prefs {
+		b := tx.NotificationPreference.Create().
+			SetUserID(userID).
+			SetNillableEventType(p.EventType).
+			SetChannel(entpref.Channel(p.Channel)).
+			SetFrequency(entpref.Frequency(p.Frequency))
+		if p.NotificationType != nil {
+			b.SetNotificationType(entpref.NotificationType(*p.NotificationType))
+		}
+		builders[i] = b
+	}
+	rows, err := tx.NotificationPreference.CreateBulk(builders...).Save(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return transform.ToEntities[notification.Preference](rows), nil
+}
@@ -4,8 +4,11 @@ import (
 	"github.com/SURF-Innovatie/MORIS/ent"
 	organisationrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/organisation"
 	organisationhierarchyrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/organisation/hierarchy"
+	organisationprovisioningrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/organisation/provisioning"
 	organisationrbacrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/organisation/rbac"
+	organisationrestructurerepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/organisation/restructure"
 	organisationrolerepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/organisation/role"
+	organisationrorimportrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/organisation/rorimport"
 	"github.com/samber/do/v2"
 )
 
@@ -14,6 +17,9 @@ var Package = do.Package(
 	do.Lazy(provideOrgRBACRepo),
 	do.Lazy(provideOrgHierarchyRepo),
 	do.Lazy(provideOrgRoleRepo),
+	do.Lazy(provideOrgRestructureRepo),
+	do.Lazy(provideOrgRORImportRepo),
+	do.Lazy(provideOrgProvisioningRepo),
 )
 
 func provideOrgRepo(i do.Injector) (*organisationrepo.EntRepo, error) {
@@ -35,3 +41,18 @@ func provideOrgRoleRepo(i do.Injector) (*organisationrolerepo.EntRepo, error) {
 	cli := do.MustInvoke[*ent.Client](i)
 	return organisationrolerepo.NewEntRepo(cli), nil
 }
+
+func provideOrgRestructureRepo(i do.Injector) (*organisationrestructurerepo.EntRepo, error) {
+	cli := do.MustInvoke[*ent.Client](i)
+	return organisationrestructurerepo.NewEntRepo(cli), nil
+}
+
+func provideOrgRORImportRepo(i do.Injector) (*organisationrorimportrepo.EntRepo, error) {
+	cli := do.MustInvoke[*ent.Client](i)
+	return organisationrorimportrepo.NewEntRepo(cli), nil
+}
+
+func provideOrgProvisioningRepo(i do.Injector) (*organisationprovisioningrepo.EntRepo, error) {
+	cli := do.MustInvoke[*ent.Client](i)
+	return organisationprovisioningrepo.NewEntRepo(cli), nil
+}
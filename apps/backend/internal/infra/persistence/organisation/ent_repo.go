@@ -88,6 +88,16 @@ func (r *EntRepo) UpdateNode(ctx context.Context, id uuid.UUID, name string, par
 	return transform.ToEntityPtr[organisation.OrganisationNode](row), nil
 }
 
+func (r *EntRepo) SetParent(ctx context.Context, id uuid.UUID, parentID *uuid.UUID) error {
+	upd := r.node(ctx).UpdateOneID(id)
+	if parentID == nil {
+		upd = upd.ClearParent()
+	} else {
+		upd = upd.SetParentID(*parentID)
+	}
+	return upd.Exec(ctx)
+}
+
 func (r *EntRepo) ListRoots(ctx context.Context) ([]organisation.OrganisationNode, error) {
 	rows, err := r.node(ctx).
 		Query().
@@ -0,0 +1,142 @@
+package provisioning
+
+import (
+	"context"
+
+	"github.com/SURF-Innovatie/MORIS/ent"
+	entmembership "github.com/SURF-Innovatie/MORIS/ent/membership"
+	entrule "github.com/SURF-Innovatie/MORIS/ent/provisioningrule"
+	"github.com/SURF-Innovatie/MORIS/internal/common/transform"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation/rbac"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/persistence/enttx"
+	"github.com/google/uuid"
+)
+
+type EntRepo struct {
+	cli *ent.Client
+}
+
+func NewEntRepo(cli *ent.Client) *EntRepo {
+	return &EntRepo{cli: cli}
+}
+
+// client returns the transactional client when ctx carries a transaction
+func (r *EntRepo) client(ctx context.Context) *ent.Client {
+	if tx, ok := enttx.TxFromContext(ctx); ok {
+		return tx.Client()
+	}
+	return r.cli
+}
+
+func (r *EntRepo) ListRules(ctx context.Context, rootNodeID uuid.UUID) ([]rbac.ProvisioningRule, error) {
+	rows, err := r.client(ctx).ProvisioningRule.Query().
+		Where(entrule.RootNodeIDEQ(rootNodeID)).
+		Order(ent.Asc(entrule.FieldCreatedAt)).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return transform.ToEntities[rbac.ProvisioningRule](rows), nil
+}
+
+func (r *EntRepo) GetRule(ctx context.Context, id uuid.UUID) (*rbac.ProvisioningRule, error) {
+	row, err := r.client(ctx).ProvisioningRule.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return transform.ToEntityPtr[rbac.ProvisioningRule](row), nil
+}
+
+func (r *EntRepo) CreateRule(ctx context.Context, rule rbac.ProvisioningRule) (*rbac.ProvisioningRule, error) {
+	row, err := r.client(ctx).ProvisioningRule.Create().
+		SetRootNodeID(rule.RootNodeID).
+		SetRoleScopeID(rule.RoleScopeID).
+		SetSchacHomeOrganization(rule.SchacHomeOrganization).
+		SetNillableAffiliation(rule.Affiliation).
+		SetNillableEntitlement(rule.Entitlement).
+		Save(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return transform.ToEntityPtr[rbac.ProvisioningRule](row), nil
+}
+
+func (r *EntRepo) UpdateRule(ctx context.Context, rule rbac.ProvisioningRule) (*rbac.ProvisioningRule, error) {
+	upd := r.client(ctx).ProvisioningRule.UpdateOneID(rule.ID).
+		SetRoleScopeID(rule.RoleScopeID).
+		SetSchacHomeOrganization(rule.SchacHomeOrganization)
+	if rule.Affiliation != nil {
+		upd.SetAffiliation(*rule.Affiliation)
+	} else {
+		upd.ClearAffiliation()
+	}
+	if rule.Entitlement != nil {
+		upd.SetEntitlement(*rule.Entitlement)
+	} else {
+		upd.ClearEntitlement()
+	}
+	row, err := upd.Save(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return transform.ToEntityPtr[rbac.ProvisioningRule](row), nil
+}
+
+func (r *EntRepo) DeleteRule(ctx context.Context, id uuid.UUID) error {
+	return r.client(ctx).ProvisioningRule.DeleteOneID(id).Exec(ctx)
+}
+
+func (r *EntRepo) RulesForHomeOrganization(ctx context.Context, schacHomeOrganization string) ([]rbac.ProvisioningRule, error) {
+	rows, err := r.client(ctx).ProvisioningRule.Query().
+		Where(entrule.SchacHomeOrganizationEQ(schacHomeOrganization)).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return transform.ToEntities[rbac.ProvisioningRule](rows), nil
+}
+
+func (r *EntRepo) GetScope(ctx context.Context, id uuid.UUID) (*rbac.RoleScope, error) {
+	row, err := r.client(ctx).RoleScope.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return transform.ToEntityPtr[rbac.RoleScope](row), nil
+}
+
+func (r *EntRepo) ListMemberships(ctx context.Context, personID uuid.UUID) ([]rbac.Membership, error) {
+	rows, err := r.client(ctx).Membership.Query().
+		Where(entmembership.PersonIDEQ(personID)).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return transform.ToEntities[rbac.Membership](rows), nil
+}
+
+func (r *EntRepo) AddProvisionedMembership(ctx context.Context, personID, roleScopeID uuid.UUID) (*rbac.Membership, error) {
+	row, err := r.client(ctx).Membership.Create().
+		SetPersonID(personID).
+		SetRoleScopeID(roleScopeID).
+		SetProvisioned(true).
+		Save(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return transform.ToEntityPtr[rbac.Membership](row), nil
+}
+
+func (r *EntRepo) ClearMembershipPeriod(ctx context.Context, membershipID uuid.UUID) (*rbac.Membership, error) {
+	row, err := r.client(ctx).Membership.UpdateOneID(membershipID).
+		ClearStartDate().
+		ClearEndDate().
+		Save(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return transform.ToEntityPtr[rbac.Membership](row), nil
+}
+
+func (r *EntRepo) RemoveMembership(ctx context.Context, membershipID uuid.UUID) error {
+	return r.client(ctx).Membership.DeleteOneID(membershipID).Exec(ctx)
+}
@@ -0,0 +1,201 @@
+package provisioning_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/ent"
+	entauditlogentry "github.com/SURF-Innovatie/MORIS/ent/auditlogentry"
+	"github.com/SURF-Innovatie/MORIS/ent/enttest"
+	entmembership "github.com/SURF-Innovatie/MORIS/ent/membership"
+	appaudit "github.com/SURF-Innovatie/MORIS/internal/app/audit"
+	orgsvc "github.com/SURF-Innovatie/MORIS/internal/app/organisation"
+	"github.com/SURF-Innovatie/MORIS/internal/app/organisation/hierarchy"
+	appprovisioning "github.com/SURF-Innovatie/MORIS/internal/app/organisation/provisioning"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/identity"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation/rbac"
+	auditrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/audit"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/persistence/enttx"
+	orgrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/organisation"
+	hierarchyrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/organisation/hierarchy"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/persistence/organisation/provisioning"
+	"github.com/google/uuid"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/samber/lo"
+)
+
+// noUser makes the audit log record changes as made by the system, as happens during a login
+type noUser struct{}
+
+func (noUser) Current(context.Context) (identity.Principal, error) {
+	return identity.Principal{}, errors.New("unauthenticated")
+}
+
+type fixture struct {
+	cli      *ent.Client
+	svc      appprovisioning.Service
+	rootID   uuid.UUID
+	childID  uuid.UUID
+	personID uuid.UUID
+	staff    uuid.UUID // role scope on the child node
+	admins   uuid.UUID // role scope on the root node
+}
+
+func setup(t *testing.T) fixture {
+	t.Helper()
+	ctx := context.Background()
+	cli := enttest.Open(t, "sqlite3", "file:provisioning_"+uuid.NewString()+"?mode=memory&cache=shared&_fk=1")
+	t.Cleanup(func() { _ = cli.Close() })
+
+	root := cli.OrganisationNode.Create().SetName("University").SaveX(ctx)
+	child := cli.OrganisationNode.Create().SetName("Faculty").SetParentID(root.ID).SaveX(ctx)
+	cli.OrganisationNodeClosure.Create().SetAncestorID(root.ID).SetDescendantID(root.ID).SetDepth(0).ExecX(ctx)
+	cli.OrganisationNodeClosure.Create().SetAncestorID(child.ID).SetDescendantID(child.ID).SetDepth(0).ExecX(ctx)
+	cli.OrganisationNodeClosure.Create().SetAncestorID(root.ID).SetDescendantID(child.ID).SetDepth(1).ExecX(ctx)
+
+	staffRole := cli.OrganisationRole.Create().SetOrganisationNodeID(child.ID).SetKey("staff").SetDisplayName("Staff").SetPermissions([]string{}).SaveX(ctx)
+	adminRole := cli.OrganisationRole.Create().SetOrganisationNodeID(root.ID).SetKey("admin").SetDisplayName("Admin").SetPermissions([]string{}).SaveX(ctx)
+	staff := cli.RoleScope.Create().SetRoleID(staffRole.ID).SetRootNodeID(child.ID).SaveX(ctx)
+	admins := cli.RoleScope.Create().SetRoleID(adminRole.ID).SetRootNodeID(root.ID).SaveX(ctx)
+
+	person := cli.Person.Create().
+		SetName("Ada Lovelace").
+		SetEmail(uuid.NewString() + "@example.org").
+		SaveX(ctx)
+
+	tx := enttx.NewManager(cli)
+	svc := appprovisioning.NewService(
+		provisioning.NewEntRepo(cli),
+		orgsvc.NewService(orgrepo.NewEntRepo(cli), nil, nil, tx),
+		hierarchy.NewService(hierarchyrepo.NewEntRepo(cli)),
+		appaudit.NewService(auditrepo.NewEntRepo(cli), noUser{}),
+		tx,
+	)
+	return fixture{cli: cli, svc: svc, rootID: root.ID, childID: child.ID, personID: person.ID, staff: staff.ID, admins: admins.ID}
+}
+
+func TestCreateRule_RequiresRootNodeAndScopeInTree(t *testing.T) {
+	ctx := context.Background()
+	f := setup(t)
+
+	_, err := f.svc.CreateRule(ctx, f.childID, appprovisioning.RuleInput{RoleScopeID: f.staff, SchacHomeOrganization: "uva.nl"})
+	if !errors.Is(err, rbac.ErrInvalidProvisioningRule) {
+		t.Fatalf("expected a rule on a child node to be refused, got %v", err)
+	}
+	_, err = f.svc.CreateRule(ctx, f.rootID, appprovisioning.RuleInput{RoleScopeID: f.staff})
+	if !errors.Is(err, rbac.ErrInvalidProvisioningRule) {
+		t.Fatalf("expected a rule without home organisation to be refused, got %v", err)
+	}
+
+	rule, err := f.svc.CreateRule(ctx, f.rootID, appprovisioning.RuleInput{RoleScopeID: f.staff, SchacHomeOrganization: " UvA.nl ", Affiliation: lo.ToPtr("")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rule.SchacHomeOrganization != "uva.nl" || rule.Affiliation != nil {
+		t.Fatalf("expected a normalized rule without affiliation, got %+v", rule)
+	}
+	if n := f.cli.AuditLogEntry.Query().Where(entauditlogentry.EntityIDEQ(rule.ID)).CountX(ctx); n != 1 {
+		t.Fatalf("expected the rule creation to be audited, got %d entries", n)
+	}
+}
+
+func TestSync_CreatesReactivatesAndRevokesProvisionedMemberships(t *testing.T) {
+	ctx := context.Background()
+	f := setup(t)
+
+	if _, err := f.svc.CreateRule(ctx, f.rootID, appprovisioning.RuleInput{
+		RoleScopeID: f.staff, SchacHomeOrganization: "uva.nl", Affiliation: lo.ToPtr("employee"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.svc.CreateRule(ctx, f.rootID, appprovisioning.RuleInput{
+		RoleScopeID: f.admins, SchacHomeOrganization: "uva.nl", Entitlement: lo.ToPtr("urn:mace:surf.nl:moris:admin"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// Added by hand, so never revoked by provisioning
+	manual := f.cli.Membership.Create().SetPersonID(f.personID).SetRoleScopeID(f.admins).SaveX(ctx)
+
+	employee := rbac.ProvisioningClaims{SchacHomeOrganization: "UVA.NL", Affiliations: []string{"Employee", "member"}}
+	res, err := f.svc.Sync(ctx, f.personID, employee)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Created) != 1 || res.Created[0].RoleScopeID != f.staff || !res.Created[0].Provisioned {
+		t.Fatalf("expected a provisioned staff membership, got %+v", res)
+	}
+	staffMembership := res.Created[0].ID
+
+	res, err = f.svc.Sync(ctx, f.personID, employee)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Created)+len(res.Updated)+len(res.Revoked) != 0 {
+		t.Fatalf("expected a repeated login to change nothing, got %+v", res)
+	}
+
+	f.cli.Membership.UpdateOneID(staffMembership).SetEndDate(time.Now().Add(-time.Hour)).ExecX(ctx)
+	res, err = f.svc.Sync(ctx, f.personID, employee)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Updated) != 1 || res.Updated[0].EndDate != nil {
+		t.Fatalf("expected the ended membership to be reactivated, got %+v", res)
+	}
+
+	res, err = f.svc.Sync(ctx, f.personID, rbac.ProvisioningClaims{SchacHomeOrganization: "uva.nl", Affiliations: []string{"student"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Revoked) != 1 || res.Revoked[0].ID != staffMembership {
+		t.Fatalf("expected the staff membership to be revoked, got %+v", res)
+	}
+	if _, err := f.cli.Membership.Get(ctx, manual.ID); err != nil {
+		t.Fatalf("expected the manual membership to be kept: %v", err)
+	}
+
+	entries := f.cli.AuditLogEntry.Query().
+		Where(entauditlogentry.EntityIDEQ(staffMembership)).
+		Order(ent.Asc(entauditlogentry.FieldCreatedAt)).
+		AllX(ctx)
+	actions := lo.Map(entries, func(e *ent.AuditLogEntry, _ int) string { return string(e.Action) })
+	if len(actions) != 3 || actions[0] != "create" || actions[1] != "update" || actions[2] != "delete" {
+		t.Fatalf("expected create, update and delete in the audit log, got %v", actions)
+	}
+	if entries[0].OrganisationNodeID != f.childID || entries[0].ActorUserID != nil {
+		t.Fatalf("expected a system entry on the scope's node, got %+v", entries[0])
+	}
+}
+
+func TestSync_RevokesProvisionedMembershipsWithoutHomeOrganisation(t *testing.T) {
+	ctx := context.Background()
+	f := setup(t)
+
+	if _, err := f.svc.CreateRule(ctx, f.rootID, appprovisioning.RuleInput{RoleScopeID: f.staff, SchacHomeOrganization: "uva.nl"}); err != nil {
+		t.Fatal(err)
+	}
+	manual := f.cli.Membership.Create().SetPersonID(f.personID).SetRoleScopeID(f.admins).SaveX(ctx)
+
+	res, err := f.svc.Sync(ctx, f.personID, rbac.ProvisioningClaims{SchacHomeOrganization: "uva.nl"})
+	if err != nil || len(res.Created) != 1 {
+		t.Fatalf("expected a provisioned membership, got %+v (%v)", res, err)
+	}
+	provisioned := res.Created[0].ID
+
+	// A login through another identity provider, or after the institution stopped releasing the claim
+	res, err = f.svc.Sync(ctx, f.personID, rbac.ProvisioningClaims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Revoked) != 1 || res.Revoked[0].ID != provisioned {
+		t.Fatalf("expected the provisioned membership to be revoked, got %+v", res)
+	}
+	if f.cli.Membership.Query().Where(entmembership.IDEQ(provisioned)).ExistX(ctx) {
+		t.Fatal("expected the provisioned membership to be removed")
+	}
+	if !f.cli.Membership.Query().Where(entmembership.IDEQ(manual.ID)).ExistX(ctx) {
+		t.Fatal("expected the manual membership to be kept")
+	}
+}
@@ -3,10 +3,13 @@ package rbac
 import (
 	"context"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/SURF-Innovatie/MORIS/ent"
 	entmembership "github.com/SURF-Innovatie/MORIS/ent/membership"
 	entclosure "github.com/SURF-Innovatie/MORIS/ent/organisationnodeclosure"
+	"github.com/SURF-Innovatie/MORIS/ent/predicate"
 	entrolescope "github.com/SURF-Innovatie/MORIS/ent/rolescope"
 	entuser "github.com/SURF-Innovatie/MORIS/ent/user"
 	organisation_rbac "github.com/SURF-Innovatie/MORIS/internal/app/organisation/rbac"
@@ -66,6 +69,7 @@ func (r *EntRepo) GetMyPermissions(ctx context.Context, personID, nodeID uuid.UU
 		Where(
 			entmembership.PersonIDEQ(personID),
 			entmembership.RoleScopeIDIn(scopeIDs...),
+			activeAt(time.Now()),
 		).
 		All(ctx)
 	if err != nil {
@@ -126,7 +130,7 @@ func (r *EntRepo) ListEffectiveMemberships(ctx context.Context, nodeID uuid.UUID
 
 	memberships, err := r.cli.Membership.
 		Query().
-		Where(entmembership.RoleScopeIDIn(scopeIDs...)).
+		Where(entmembership.RoleScopeIDIn(scopeIDs...), activeAt(time.Now())).
 		WithPerson().
 		All(ctx)
 	if err != nil {
@@ -160,6 +164,8 @@ func (r *EntRepo) ListEffectiveMemberships(ctx context.Context, nodeID uuid.UUID
 			PersonID:              m.PersonID,
 			RoleScopeID:           m.RoleScopeID,
 			ScopeRootOrganisation: scopeRoot,
+			StartDate:             m.StartDate,
+			EndDate:               m.EndDate,
 			RoleID:                sc.RoleID,
 			RoleKey:               sc.Edges.Role.Key,
 			Permissions:           toPermissions(sc.Edges.Role.Permissions),
@@ -174,7 +180,7 @@ func (r *EntRepo) ListEffectiveMemberships(ctx context.Context, nodeID uuid.UUID
 func (r *EntRepo) ListMyMemberships(ctx context.Context, personID uuid.UUID) ([]organisation_rbac.EffectiveMembership, error) {
 	memberships, err := r.cli.Membership.
 		Query().
-		Where(entmembership.PersonIDEQ(personID)).
+		Where(entmembership.PersonIDEQ(personID), activeAt(time.Now())).
 		WithRoleScope(func(q *ent.RoleScopeQuery) { q.WithRole() }).
 		WithPerson().
 		All(ctx)
@@ -210,6 +216,8 @@ func (r *EntRepo) ListMyMemberships(ctx context.Context, personID uuid.UUID) ([]
 			PersonID:              m.PersonID,
 			RoleScopeID:           m.RoleScopeID,
 			ScopeRootOrganisation: scopeRoot,
+			StartDate:             m.StartDate,
+			EndDate:               m.EndDate,
 			RoleID:                sc.RoleID,
 			RoleKey:               sc.Edges.Role.Key,
 			Permissions:           toPermissions(sc.Edges.Role.Permissions),
@@ -242,14 +250,14 @@ func (r *EntRepo) GetApprovalNode(ctx context.Context, nodeID uuid.UUID) (*organ
 			return nil, err
 		}
 
-		// Filter scopes for Admin role (manage_details permission)
+		// Filter scopes for roles that may approve events
 		var adminScopeIDs []uuid.UUID
 		for _, sc := range scopes { // Note: iterate scopes
 			if sc.Edges.Role == nil {
 				continue
 			}
 			for _, p := range sc.Edges.Role.Permissions {
-				if rbac.Permission(p) == rbac.PermissionManageDetails {
+				if rbac.Permission(p) == rbac.PermissionApproveEvents {
 					adminScopeIDs = append(adminScopeIDs, sc.ID)
 					break
 				}
@@ -262,7 +270,7 @@ func (r *EntRepo) GetApprovalNode(ctx context.Context, nodeID uuid.UUID) (*organ
 
 		count, err := r.cli.Membership.
 			Query().
-			Where(entmembership.RoleScopeIDIn(adminScopeIDs...)).
+			Where(entmembership.RoleScopeIDIn(adminScopeIDs...), activeAt(time.Now())).
 			Count(ctx)
 		if err != nil {
 			return nil, err
@@ -278,7 +286,7 @@ func (r *EntRepo) GetApprovalNode(ctx context.Context, nodeID uuid.UUID) (*organ
 		return transform.ToEntityPtr[organisation.OrganisationNode](n), nil
 	}
 
-	return nil, fmt.Errorf("no approval node found: ensure an admin membership exists in some ancestor scope")
+	return nil, fmt.Errorf("no approval node found: ensure a membership with the %s permission exists in some ancestor scope", rbac.PermissionApproveEvents)
 }
 
 func (r *EntRepo) HasAdminAccess(ctx context.Context, personID uuid.UUID, nodeID uuid.UUID) (bool, error) {
@@ -338,6 +346,7 @@ func (r *EntRepo) HasPermission(ctx context.Context, personID uuid.UUID, nodeID
 		Where(
 			entmembership.RoleScopeIDIn(validScopeIDs...),
 			entmembership.PersonIDEQ(personID),
+			activeAt(time.Now()),
 		).
 		Count(ctx)
 	if err != nil {
@@ -346,6 +355,174 @@ func (r *EntRepo) HasPermission(ctx context.Context, personID uuid.UUID, nodeID
 	return count > 0, nil
 }
 
+// HasPermissionAnywhere reports whether the person holds the permission on any organisation node,
+// for resources such as affiliated organisations that do not belong to a node
+func (r *EntRepo) HasPermissionAnywhere(ctx context.Context, personID uuid.UUID, permission rbac.Permission) (bool, error) {
+	user, err := r.cli.User.Query().Where(entuser.PersonIDEQ(personID)).First(ctx)
+	if err != nil {
+		return false, err
+	}
+	if user.IsSysAdmin {
+		return true, nil
+	}
+
+	memberships, err := r.cli.Membership.
+		Query().
+		Where(entmembership.PersonIDEQ(personID), activeAt(time.Now())).
+		WithRoleScope(func(q *ent.RoleScopeQuery) { q.WithRole() }).
+		All(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return lo.SomeBy(memberships, func(m *ent.Membership) bool {
+		sc := m.Edges.RoleScope
+		if sc == nil || sc.Edges.Role == nil {
+			return false
+		}
+		return lo.Contains(sc.Edges.Role.Permissions, string(permission))
+	}), nil
+}
+
+// NodeIDsWithPermission returns the nodes the person's active memberships grant the permission on,
+// together with their descendants. Sysadmin status is not taken into account.
+func (r *EntRepo) NodeIDsWithPermission(ctx context.Context, personID uuid.UUID, permission rbac.Permission) ([]uuid.UUID, error) {
+	memberships, err := r.cli.Membership.
+		Query().
+		Where(entmembership.PersonIDEQ(personID), activeAt(time.Now())).
+		WithRoleScope(func(q *ent.RoleScopeQuery) { q.WithRole() }).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rootIDs := lo.Uniq(lo.FilterMap(memberships, func(m *ent.Membership, _ int) (uuid.UUID, bool) {
+		sc := m.Edges.RoleScope
+		if sc == nil || sc.Edges.Role == nil {
+			return uuid.Nil, false
+		}
+		return sc.RootNodeID, lo.Contains(sc.Edges.Role.Permissions, string(permission))
+	}))
+	if len(rootIDs) == 0 {
+		return nil, nil
+	}
+
+	var nodeIDs []uuid.UUID
+	if err := r.cli.OrganisationNodeClosure.
+		Query().
+		Where(entclosure.AncestorIDIn(rootIDs...)).
+		Select(entclosure.FieldDescendantID).
+		Scan(ctx, &nodeIDs); err != nil {
+		return nil, err
+	}
+	return lo.Uniq(nodeIDs), nil
+}
+
+// ExplainPermission walks the same closure rows, role scopes and memberships as HasPermission and
+// records every step, so that support staff can see why a permission is or is not granted
+func (r *EntRepo) ExplainPermission(ctx context.Context, personID, nodeID uuid.UUID, permission rbac.Permission) (*rbac.PermissionExplanation, error) {
+	out := &rbac.PermissionExplanation{
+		PersonID:   personID,
+		NodeID:     nodeID,
+		Permission: permission,
+		Ancestors:  []rbac.AncestorNode{},
+		Grants:     []rbac.GrantPath{},
+	}
+
+	user, err := r.cli.User.Query().Where(entuser.PersonIDEQ(personID)).First(ctx)
+	if err != nil && !ent.IsNotFound(err) {
+		return nil, err
+	}
+
+	ancestors, err := r.cli.OrganisationNodeClosure.
+		Query().
+		Where(entclosure.DescendantIDEQ(nodeID)).
+		WithAncestor().
+		Order(ent.Asc(entclosure.FieldDepth)).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	depthByNode := make(map[uuid.UUID]int, len(ancestors))
+	nameByNode := make(map[uuid.UUID]string, len(ancestors))
+	for _, a := range ancestors {
+		depthByNode[a.AncestorID] = a.Depth
+		if a.Edges.Ancestor != nil {
+			nameByNode[a.AncestorID] = a.Edges.Ancestor.Name
+		}
+		out.Ancestors = append(out.Ancestors, rbac.AncestorNode{
+			NodeID: a.AncestorID,
+			Name:   nameByNode[a.AncestorID],
+			Depth:  a.Depth,
+		})
+	}
+
+	memberships, err := r.cli.Membership.
+		Query().
+		Where(
+			entmembership.PersonIDEQ(personID),
+			entmembership.HasRoleScopeWith(entrolescope.RootNodeIDIn(lo.Keys(depthByNode)...)),
+		).
+		WithRoleScope(func(q *ent.RoleScopeQuery) { q.WithRole() }).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, m := range memberships {
+		sc := m.Edges.RoleScope
+		if sc == nil || sc.Edges.Role == nil {
+			continue
+		}
+		active := rbac.MembershipPeriodContains(m.StartDate, m.EndDate, now)
+		grants := active && lo.Contains(sc.Edges.Role.Permissions, string(permission))
+		out.Grants = append(out.Grants, rbac.GrantPath{
+			MembershipID:  m.ID,
+			RoleScopeID:   sc.ID,
+			RoleID:        sc.RoleID,
+			RoleKey:       sc.Edges.Role.Key,
+			ScopeNodeID:   sc.RootNodeID,
+			ScopeNodeName: nameByNode[sc.RootNodeID],
+			Depth:         depthByNode[sc.RootNodeID],
+			Permissions:   toPermissions(sc.Edges.Role.Permissions),
+			StartDate:     m.StartDate,
+			EndDate:       m.EndDate,
+			Active:        active,
+			Grants:        grants,
+		})
+		out.Granted = out.Granted || grants
+	}
+	sort.SliceStable(out.Grants, func(i, j int) bool { return out.Grants[i].Depth < out.Grants[j].Depth })
+
+	switch {
+	case user != nil && user.IsSysAdmin:
+		out.Granted = true
+		out.SysAdmin = true
+		out.Reason = "granted: the person is a sysadmin"
+	case len(ancestors) == 0:
+		out.Reason = "denied: the node has no closure rows, so no role scope can apply to it"
+	case out.Granted:
+		g, _ := lo.Find(out.Grants, func(g rbac.GrantPath) bool { return g.Grants })
+		out.Reason = fmt.Sprintf("granted: role %q scoped at %q includes %s", g.RoleKey, g.ScopeNodeName, permission)
+	case len(out.Grants) == 0:
+		out.Reason = "denied: the person has no membership on this node or any of its ancestors"
+	case !lo.SomeBy(out.Grants, func(g rbac.GrantPath) bool { return g.Active }):
+		out.Reason = "denied: none of the person's memberships on this node or its ancestors is currently valid"
+	default:
+		out.Reason = fmt.Sprintf("denied: none of the person's roles on this node or its ancestors includes %s", permission)
+	}
+	return out, nil
+}
+
+// activeAt matches memberships whose validity period contains t
+func activeAt(t time.Time) predicate.Membership {
+	return entmembership.And(
+		entmembership.Or(entmembership.StartDateIsNil(), entmembership.StartDateLTE(t)),
+		entmembership.Or(entmembership.EndDateIsNil(), entmembership.EndDateGT(t)),
+	)
+}
+
 func toPermissions(s []string) []rbac.Permission {
 	return lo.Map(s, func(v string, _ int) rbac.Permission {
 		return rbac.Permission(v)
@@ -3,6 +3,7 @@ package rbac_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/SURF-Innovatie/MORIS/ent"
 	"github.com/SURF-Innovatie/MORIS/ent/enttest"
@@ -147,6 +148,57 @@ func TestHasPermission_SysAdminAlwaysTrue(t *testing.T) {
 	}
 }
 
+func TestHasPermissionAnywhere_ChecksAllMemberships(t *testing.T) {
+	cli := newRBACClient(t)
+	defer cli.Close()
+	ctx := context.Background()
+
+	repo := rbac.NewEntRepo(cli)
+	_, childID := seedOrgTree(t, cli)
+	personID, _ := seedPersonUser(t, cli, false)
+
+	ok, err := repo.HasPermissionAnywhere(ctx, personID, rbac2.PermissionManageAffiliatedOrgs)
+	if err != nil {
+		t.Fatalf("HasPermissionAnywhere: %v", err)
+	}
+	if ok {
+		t.Fatalf("expected no permission without memberships")
+	}
+
+	role, err := cli.OrganisationRole.Create().
+		SetOrganisationNodeID(childID).
+		SetKey("registrar").
+		SetDisplayName("Registrar").
+		SetPermissions([]string{string(rbac2.PermissionManageAffiliatedOrgs)}).
+		Save(ctx)
+	if err != nil {
+		t.Fatalf("create role: %v", err)
+	}
+	sc, err := cli.RoleScope.Create().SetRoleID(role.ID).SetRootNodeID(childID).Save(ctx)
+	if err != nil {
+		t.Fatalf("create scope: %v", err)
+	}
+	if _, err := cli.Membership.Create().SetPersonID(personID).SetRoleScopeID(sc.ID).Save(ctx); err != nil {
+		t.Fatalf("create membership: %v", err)
+	}
+
+	ok, err = repo.HasPermissionAnywhere(ctx, personID, rbac2.PermissionManageAffiliatedOrgs)
+	if err != nil {
+		t.Fatalf("HasPermissionAnywhere: %v", err)
+	}
+	if !ok {
+		t.Fatalf("expected permission via child scope")
+	}
+
+	ok, err = repo.HasPermissionAnywhere(ctx, personID, rbac2.PermissionManagePolicies)
+	if err != nil {
+		t.Fatalf("HasPermissionAnywhere: %v", err)
+	}
+	if ok {
+		t.Fatalf("expected no manage_policies permission")
+	}
+}
+
 func TestGetMyPermissions_CollectsUnionFromMemberships(t *testing.T) {
 	cli := newRBACClient(t)
 	defer cli.Close()
@@ -313,12 +365,12 @@ func TestGetApprovalNode_FindsNearestAncestorWithAdminMembership(t *testing.T) {
 	rootID, childID := seedOrgTree(t, cli)
 	personID, _ := seedPersonUser(t, cli, false)
 
-	// Create admin role on root with manage_details
+	// Create admin role on root with approve_events
 	r, err := cli.OrganisationRole.Create().
 		SetOrganisationNodeID(rootID).
 		SetKey("admin").
 		SetDisplayName("Admin").
-		SetPermissions([]string{string(rbac2.PermissionManageDetails)}).
+		SetPermissions([]string{string(rbac2.PermissionApproveEvents)}).
 		Save(ctx)
 	if err != nil {
 		t.Fatalf("create role: %v", err)
@@ -363,3 +415,150 @@ func TestGetApprovalNode_ErrorsWhenNoAdminMembershipAnywhere(t *testing.T) {
 		t.Fatalf("expected error when no approval node exists")
 	}
 }
+
+func TestExplainPermission_ListsGrantingAndNonGrantingPaths(t *testing.T) {
+	cli := newRBACClient(t)
+	defer cli.Close()
+	ctx := context.Background()
+
+	repo := rbac.NewEntRepo(cli)
+	rootID, childID := seedOrgTree(t, cli)
+	personID, _ := seedPersonUser(t, cli, false)
+
+	viewer := cli.OrganisationRole.Create().
+		SetOrganisationNodeID(childID).
+		SetKey("viewer").
+		SetDisplayName("Viewer").
+		SetPermissions([]string{string(rbac2.PermissionViewProjects)}).
+		SaveX(ctx)
+	admin := cli.OrganisationRole.Create().
+		SetOrganisationNodeID(rootID).
+		SetKey("admin").
+		SetDisplayName("Admin").
+		SetPermissions([]string{string(rbac2.PermissionManageDetails)}).
+		SaveX(ctx)
+	viewerScope := cli.RoleScope.Create().SetRoleID(viewer.ID).SetRootNodeID(childID).SaveX(ctx)
+	adminScope := cli.RoleScope.Create().SetRoleID(admin.ID).SetRootNodeID(rootID).SaveX(ctx)
+	cli.Membership.Create().SetPersonID(personID).SetRoleScopeID(viewerScope.ID).SaveX(ctx)
+	cli.Membership.Create().SetPersonID(personID).SetRoleScopeID(adminScope.ID).SaveX(ctx)
+
+	exp, err := repo.ExplainPermission(ctx, personID, childID, rbac2.PermissionManageDetails)
+	if err != nil {
+		t.Fatalf("ExplainPermission: %v", err)
+	}
+	if !exp.Granted || exp.SysAdmin {
+		t.Fatalf("expected a grant through a role, got %+v", exp)
+	}
+	if len(exp.Ancestors) != 2 || exp.Ancestors[0].NodeID != childID || exp.Ancestors[1].NodeID != rootID {
+		t.Fatalf("expected child then root as ancestors, got %+v", exp.Ancestors)
+	}
+	if len(exp.Grants) != 2 {
+		t.Fatalf("expected both memberships to be listed, got %d", len(exp.Grants))
+	}
+	if exp.Grants[0].RoleKey != "viewer" || exp.Grants[0].Grants {
+		t.Fatalf("expected the nearer viewer scope first and not granting, got %+v", exp.Grants[0])
+	}
+	if exp.Grants[1].RoleKey != "admin" || !exp.Grants[1].Grants || exp.Grants[1].ScopeNodeID != rootID || exp.Grants[1].Depth != 1 {
+		t.Fatalf("expected the admin scope on root to grant, got %+v", exp.Grants[1])
+	}
+
+	exp, err = repo.ExplainPermission(ctx, personID, rootID, rbac2.PermissionViewProjects)
+	if err != nil {
+		t.Fatalf("ExplainPermission: %v", err)
+	}
+	if exp.Granted || len(exp.Grants) != 1 || exp.Reason == "" {
+		t.Fatalf("expected a denial with only the root scope applying, got %+v", exp)
+	}
+}
+
+func TestHasPermission_IgnoresMembershipsOutsideTheirPeriod(t *testing.T) {
+	cli := newRBACClient(t)
+	defer cli.Close()
+	ctx := context.Background()
+
+	repo := rbac.NewEntRepo(cli)
+	rootID, childID := seedOrgTree(t, cli)
+	ended, _ := seedPersonUser(t, cli, false)
+	future, _ := seedPersonUser(t, cli, false)
+	current, _ := seedPersonUser(t, cli, false)
+
+	role := cli.OrganisationRole.Create().
+		SetOrganisationNodeID(rootID).
+		SetKey("admin").
+		SetDisplayName("Admin").
+		SetPermissions([]string{string(rbac2.PermissionManageDetails)}).
+		SaveX(ctx)
+	sc := cli.RoleScope.Create().SetRoleID(role.ID).SetRootNodeID(rootID).SaveX(ctx)
+
+	now := time.Now()
+	cli.Membership.Create().SetPersonID(ended).SetRoleScopeID(sc.ID).SetEndDate(now.Add(-time.Hour)).SaveX(ctx)
+	cli.Membership.Create().SetPersonID(future).SetRoleScopeID(sc.ID).SetStartDate(now.Add(time.Hour)).SaveX(ctx)
+	cli.Membership.Create().SetPersonID(current).SetRoleScopeID(sc.ID).
+		SetStartDate(now.Add(-time.Hour)).SetEndDate(now.Add(time.Hour)).SaveX(ctx)
+
+	for personID, want := range map[uuid.UUID]bool{ended: false, future: false, current: true} {
+		ok, err := repo.HasPermission(ctx, personID, childID, rbac2.PermissionManageDetails)
+		if err != nil {
+			t.Fatalf("HasPermission: %v", err)
+		}
+		if ok != want {
+			t.Fatalf("expected %v for person %s, got %v", want, personID, ok)
+		}
+	}
+
+	exp, err := repo.ExplainPermission(ctx, ended, childID, rbac2.PermissionManageDetails)
+	if err != nil {
+		t.Fatalf("ExplainPermission: %v", err)
+	}
+	if exp.Granted || len(exp.Grants) != 1 || exp.Grants[0].Active {
+		t.Fatalf("expected the ended membership to be listed as inactive, got %+v", exp)
+	}
+}
+
+func TestNodeIDsWithPermission_IncludesDescendantsOfActiveScopes(t *testing.T) {
+	cli := newRBACClient(t)
+	defer cli.Close()
+	ctx := context.Background()
+
+	repo := rbac.NewEntRepo(cli)
+	rootID, childID := seedOrgTree(t, cli)
+	personID, _ := seedPersonUser(t, cli, false)
+
+	viewer := cli.OrganisationRole.Create().
+		SetOrganisationNodeID(rootID).
+		SetKey("viewer").
+		SetDisplayName("Viewer").
+		SetPermissions([]string{string(rbac2.PermissionViewProjects)}).
+		SaveX(ctx)
+	other := cli.OrganisationRole.Create().
+		SetOrganisationNodeID(rootID).
+		SetKey("other").
+		SetDisplayName("Other").
+		SetPermissions([]string{string(rbac2.PermissionManagePolicies)}).
+		SaveX(ctx)
+
+	// An expired membership and a membership without the permission grant nothing
+	expired := cli.RoleScope.Create().SetRoleID(viewer.ID).SetRootNodeID(childID).SaveX(ctx)
+	cli.Membership.Create().SetPersonID(personID).SetRoleScopeID(expired.ID).SetEndDate(time.Now().Add(-time.Hour)).ExecX(ctx)
+	unrelated := cli.RoleScope.Create().SetRoleID(other.ID).SetRootNodeID(rootID).SaveX(ctx)
+	cli.Membership.Create().SetPersonID(personID).SetRoleScopeID(unrelated.ID).ExecX(ctx)
+
+	ids, err := repo.NodeIDsWithPermission(ctx, personID, rbac2.PermissionViewProjects)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 0 {
+		t.Fatalf("expected no nodes, got %v", ids)
+	}
+
+	sc := cli.RoleScope.Create().SetRoleID(viewer.ID).SetRootNodeID(rootID).SaveX(ctx)
+	cli.Membership.Create().SetPersonID(personID).SetRoleScopeID(sc.ID).ExecX(ctx)
+
+	ids, err = repo.NodeIDsWithPermission(ctx, personID, rbac2.PermissionViewProjects)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 || !lo.Contains(ids, rootID) || !lo.Contains(ids, childID) {
+		t.Fatalf("expected the root and its child, got %v", ids)
+	}
+}
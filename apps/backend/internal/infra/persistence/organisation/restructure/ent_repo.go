@@ -0,0 +1,364 @@
+package restructure
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SURF-Innovatie/MORIS/ent"
+	entapitokennode "github.com/SURF-Innovatie/MORIS/ent/apitokennode"
+	entcustomfield "github.com/SURF-Innovatie/MORIS/ent/customfielddefinition"
+	enteventpolicy "github.com/SURF-Innovatie/MORIS/ent/eventpolicy"
+	entmembership "github.com/SURF-Innovatie/MORIS/ent/membership"
+	entorgnode "github.com/SURF-Innovatie/MORIS/ent/organisationnode"
+	entclosure "github.com/SURF-Innovatie/MORIS/ent/organisationnodeclosure"
+	entorgrole "github.com/SURF-Innovatie/MORIS/ent/organisationrole"
+	entperson "github.com/SURF-Innovatie/MORIS/ent/person"
+	entprojectrole "github.com/SURF-Innovatie/MORIS/ent/projectrole"
+	entprovisioningrule "github.com/SURF-Innovatie/MORIS/ent/provisioningrule"
+	entrolescope "github.com/SURF-Innovatie/MORIS/ent/rolescope"
+	entscimtoken "github.com/SURF-Innovatie/MORIS/ent/scimtoken"
+	entscimuser "github.com/SURF-Innovatie/MORIS/ent/scimuser"
+	entwebhooksub "github.com/SURF-Innovatie/MORIS/ent/webhooksubscription"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/persistence/enttx"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// EntRepo counts, re-points and removes the rows that reference an organisation node.
+// All methods join the transaction in the context, if any.
+type EntRepo struct {
+	cli *ent.Client
+}
+
+func NewEntRepo(cli *ent.Client) *EntRepo {
+	return &EntRepo{cli: cli}
+}
+
+func (r *EntRepo) client(ctx context.Context) *ent.Client {
+	if tx, ok := enttx.TxFromContext(ctx); ok {
+		return tx.Client()
+	}
+	return r.cli
+}
+
+func (r *EntRepo) Usage(ctx context.Context, nodeID uuid.UUID) (organisation.NodeUsage, error) {
+	c := r.client(ctx)
+	var (
+		u   organisation.NodeUsage
+		err error
+	)
+
+	if u.ChildIDs, err = c.OrganisationNode.Query().Where(entorgnode.ParentIDEQ(nodeID)).IDs(ctx); err != nil {
+		return u, err
+	}
+	if u.ProjectRoles, err = c.ProjectRole.Query().Where(entprojectrole.OrganisationNodeIDEQ(nodeID)).Count(ctx); err != nil {
+		return u, err
+	}
+	if u.OrganisationRoles, err = c.OrganisationRole.Query().Where(entorgrole.OrganisationNodeIDEQ(nodeID)).Count(ctx); err != nil {
+		return u, err
+	}
+	if u.CustomFields, err = c.CustomFieldDefinition.Query().Where(entcustomfield.OrganisationNodeIDEQ(nodeID)).Count(ctx); err != nil {
+		return u, err
+	}
+	if u.RoleScopes, err = c.RoleScope.Query().Where(entrolescope.RootNodeIDEQ(nodeID)).Count(ctx); err != nil {
+		return u, err
+	}
+	if u.Memberships, err = c.Membership.Query().Where(entmembership.HasRoleScopeWith(entrolescope.RootNodeIDEQ(nodeID))).Count(ctx); err != nil {
+		return u, err
+	}
+	if u.EventPolicies, err = c.EventPolicy.Query().Where(enteventpolicy.OrgNodeIDEQ(nodeID)).Count(ctx); err != nil {
+		return u, err
+	}
+	// Rules configured on the node as well as rules on an ancestor that grant one of its scopes
+	if u.ProvisioningRules, err = c.ProvisioningRule.Query().
+		Where(entprovisioningrule.Or(
+			entprovisioningrule.RootNodeIDEQ(nodeID),
+			entprovisioningrule.HasRoleScopeWith(entrolescope.RootNodeIDEQ(nodeID)),
+		)).
+		Count(ctx); err != nil {
+		return u, err
+	}
+	if u.ScimTokens, err = c.ScimToken.Query().Where(entscimtoken.RootNodeIDEQ(nodeID)).Count(ctx); err != nil {
+		return u, err
+	}
+	if u.ScimUsers, err = c.ScimUser.Query().Where(entscimuser.RootNodeIDEQ(nodeID)).Count(ctx); err != nil {
+		return u, err
+	}
+	if u.APITokens, err = c.ApiTokenNode.Query().Where(entapitokennode.NodeIDEQ(nodeID)).Count(ctx); err != nil {
+		return u, err
+	}
+	if u.WebhookSubscriptions, err = c.WebhookSubscription.Query().Where(entwebhooksub.OrgNodeIDEQ(nodeID)).Count(ctx); err != nil {
+		return u, err
+	}
+	return u, nil
+}
+
+// Reassign re-points everything that references fromID to toID. Project roles whose key the target
+// already uses are renamed, organisation roles whose key the target already uses are replaced by the
+// target's role, and memberships are merged into the target's scopes.
+func (r *EntRepo) Reassign(ctx context.Context, fromID, toID uuid.UUID) (organisation.Reassignment, error) {
+	c := r.client(ctx)
+	out := organisation.Reassignment{RenamedProjectRoles: map[string]string{}}
+
+	// Project roles are referenced by project events, so they are moved and never deleted
+	projectRoles, err := c.ProjectRole.Query().Where(entprojectrole.OrganisationNodeIDEQ(fromID)).All(ctx)
+	if err != nil {
+		return out, err
+	}
+	for _, pr := range projectRoles {
+		key := pr.Key
+		taken, err := c.ProjectRole.Query().Where(entprojectrole.KeyEQ(key), entprojectrole.OrganisationNodeIDEQ(toID)).Exist(ctx)
+		if err != nil {
+			return out, err
+		}
+		if taken {
+			key = fmt.Sprintf("%s-%s", pr.Key, fromID.String()[:8])
+			out.RenamedProjectRoles[pr.Key] = key
+		}
+		if err := c.ProjectRole.UpdateOneID(pr.ID).SetKey(key).SetOrganisationNodeID(toID).Exec(ctx); err != nil {
+			return out, err
+		}
+	}
+
+	orgRoles, err := c.OrganisationRole.Query().Where(entorgrole.OrganisationNodeIDEQ(fromID)).All(ctx)
+	if err != nil {
+		return out, err
+	}
+	for _, orgRole := range orgRoles {
+		existing, err := c.OrganisationRole.Query().Where(entorgrole.KeyEQ(orgRole.Key), entorgrole.OrganisationNodeIDEQ(toID)).Only(ctx)
+		if ent.IsNotFound(err) {
+			if err := c.OrganisationRole.UpdateOneID(orgRole.ID).SetOrganisationNodeID(toID).Exec(ctx); err != nil {
+				return out, err
+			}
+			continue
+		}
+		if err != nil {
+			return out, err
+		}
+
+		scopes, err := c.RoleScope.Query().Where(entrolescope.RoleIDEQ(orgRole.ID)).All(ctx)
+		if err != nil {
+			return out, err
+		}
+		for _, sc := range scopes {
+			merged, err := moveScope(ctx, c, sc, existing.ID, sc.RootNodeID)
+			if err != nil {
+				return out, err
+			}
+			out.MergedMemberships += merged
+		}
+		if err := c.OrganisationRole.DeleteOneID(orgRole.ID).Exec(ctx); err != nil {
+			return out, err
+		}
+		out.MergedOrganisationRoles = append(out.MergedOrganisationRoles, orgRole.Key)
+	}
+
+	scopes, err := c.RoleScope.Query().Where(entrolescope.RootNodeIDEQ(fromID)).All(ctx)
+	if err != nil {
+		return out, err
+	}
+	for _, sc := range scopes {
+		merged, err := moveScope(ctx, c, sc, sc.RoleID, toID)
+		if err != nil {
+			return out, err
+		}
+		out.MergedMemberships += merged
+	}
+
+	if err := c.CustomFieldDefinition.Update().
+		Where(entcustomfield.OrganisationNodeIDEQ(fromID)).
+		SetOrganisationNodeID(toID).
+		Exec(ctx); err != nil {
+		return out, err
+	}
+	if err := c.ProvisioningRule.Update().
+		Where(entprovisioningrule.RootNodeIDEQ(fromID)).
+		SetRootNodeID(toID).
+		Exec(ctx); err != nil {
+		return out, err
+	}
+	if err := c.ScimToken.Update().
+		Where(entscimtoken.RootNodeIDEQ(fromID)).
+		SetRootNodeID(toID).
+		Exec(ctx); err != nil {
+		return out, err
+	}
+	if err := moveScimUsers(ctx, c, fromID, toID); err != nil {
+		return out, err
+	}
+	if err := moveAPITokenNodes(ctx, c, fromID, toID); err != nil {
+		return out, err
+	}
+	if err := c.EventPolicy.Update().
+		Where(enteventpolicy.OrgNodeIDEQ(fromID)).
+		SetOrgNodeID(toID).
+		Exec(ctx); err != nil {
+		return out, err
+	}
+	if err := c.WebhookSubscription.Update().
+		Where(entwebhooksub.OrgNodeIDEQ(fromID)).
+		SetOrgNodeID(toID).
+		Exec(ctx); err != nil {
+		return out, err
+	}
+
+	// Member custom field values are keyed by node; values already set for the target win
+	err = rewritePersonCustomFields(ctx, c, fromID, func(fields map[string]any, values any) {
+		target, _ := fields[toID.String()].(map[string]any)
+		source, _ := values.(map[string]any)
+		if target == nil {
+			fields[toID.String()] = values
+			return
+		}
+		for k, v := range source {
+			if _, ok := target[k]; !ok {
+				target[k] = v
+			}
+		}
+	})
+	return out, err
+}
+
+// moveScope points a role scope at another role and root node. When that scope already exists the
+// memberships are merged into it and the number of duplicate memberships that were dropped is returned.
+func moveScope(ctx context.Context, c *ent.Client, sc *ent.RoleScope, roleID, rootNodeID uuid.UUID) (int, error) {
+	existing, err := c.RoleScope.Query().
+		Where(
+			entrolescope.RoleIDEQ(roleID),
+			entrolescope.RootNodeIDEQ(rootNodeID),
+			entrolescope.IDNEQ(sc.ID),
+		).
+		Only(ctx)
+	if ent.IsNotFound(err) {
+		return 0, c.RoleScope.UpdateOneID(sc.ID).SetRoleID(roleID).SetRootNodeID(rootNodeID).Exec(ctx)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var present []uuid.UUID
+	if err := c.Membership.Query().
+		Where(entmembership.RoleScopeIDEQ(existing.ID)).
+		Select(entmembership.FieldPersonID).
+		Scan(ctx, &present); err != nil {
+		return 0, err
+	}
+
+	dropped, err := c.Membership.Delete().
+		Where(entmembership.RoleScopeIDEQ(sc.ID), entmembership.PersonIDIn(present...)).
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if err := c.Membership.Update().
+		Where(entmembership.RoleScopeIDEQ(sc.ID)).
+		SetRoleScopeID(existing.ID).
+		Exec(ctx); err != nil {
+		return 0, err
+	}
+	if err := c.ProvisioningRule.Update().
+		Where(entprovisioningrule.RoleScopeIDEQ(sc.ID)).
+		SetRoleScopeID(existing.ID).
+		Exec(ctx); err != nil {
+		return 0, err
+	}
+	return dropped, c.RoleScope.DeleteOneID(sc.ID).Exec(ctx)
+}
+
+// moveScimUsers moves the provisioned users of a node to another. External IDs the target already
+// uses are cleared, the identity management system matches those users on userName again.
+func moveScimUsers(ctx context.Context, c *ent.Client, fromID, toID uuid.UUID) error {
+	taken, err := c.ScimUser.Query().
+		Where(entscimuser.RootNodeIDEQ(toID), entscimuser.ExternalIDNotNil()).
+		Select(entscimuser.FieldExternalID).
+		Strings(ctx)
+	if err != nil {
+		return err
+	}
+	if err := c.ScimUser.Update().
+		Where(entscimuser.RootNodeIDEQ(fromID), entscimuser.ExternalIDIn(taken...)).
+		ClearExternalID().
+		Exec(ctx); err != nil {
+		return err
+	}
+	return c.ScimUser.Update().
+		Where(entscimuser.RootNodeIDEQ(fromID)).
+		SetRootNodeID(toID).
+		Exec(ctx)
+}
+
+// moveAPITokenNodes limits the API tokens of a node to another instead. Tokens that already
+// include the target keep that entry only.
+func moveAPITokenNodes(ctx context.Context, c *ent.Client, fromID, toID uuid.UUID) error {
+	covered, err := c.ApiTokenNode.Query().
+		Where(entapitokennode.NodeIDEQ(toID)).
+		Select(entapitokennode.FieldAPITokenID).
+		All(ctx)
+	if err != nil {
+		return err
+	}
+	tokenIDs := lo.Map(covered, func(n *ent.ApiTokenNode, _ int) uuid.UUID { return n.APITokenID })
+	if _, err := c.ApiTokenNode.Delete().
+		Where(entapitokennode.NodeIDEQ(fromID), entapitokennode.APITokenIDIn(tokenIDs...)).
+		Exec(ctx); err != nil {
+		return err
+	}
+	return c.ApiTokenNode.Update().
+		Where(entapitokennode.NodeIDEQ(fromID)).
+		SetNodeID(toID).
+		Exec(ctx)
+}
+
+// rewritePersonCustomFields removes the custom field values people have for nodeID, after passing
+// them to merge (if set) together with the person's remaining values
+func rewritePersonCustomFields(ctx context.Context, c *ent.Client, nodeID uuid.UUID, merge func(fields map[string]any, values any)) error {
+	people, err := c.Person.Query().Where(entperson.OrgCustomFieldsNotNil()).All(ctx)
+	if err != nil {
+		return err
+	}
+	key := nodeID.String()
+	for _, p := range people {
+		values, ok := p.OrgCustomFields[key]
+		if !ok {
+			continue
+		}
+		fields := lo.OmitByKeys(p.OrgCustomFields, []string{key})
+		if merge != nil {
+			merge(fields, values)
+		}
+		if err := c.Person.UpdateOneID(p.ID).SetOrgCustomFields(fields).Exec(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *EntRepo) DeleteWebhookSubscriptions(ctx context.Context, nodeID uuid.UUID) (int, error) {
+	return r.client(ctx).WebhookSubscription.Delete().
+		Where(entwebhooksub.OrgNodeIDEQ(nodeID)).
+		Exec(ctx)
+}
+
+// DeleteNode removes an unused node with its closure rows, role scopes without members and the
+// custom field values people have for it, and returns the number of closure rows removed
+func (r *EntRepo) DeleteNode(ctx context.Context, nodeID uuid.UUID) (int, error) {
+	c := r.client(ctx)
+
+	if _, err := c.RoleScope.Delete().
+		Where(entrolescope.RootNodeIDEQ(nodeID), entrolescope.Not(entrolescope.HasMemberships())).
+		Exec(ctx); err != nil {
+		return 0, err
+	}
+	if err := rewritePersonCustomFields(ctx, c, nodeID, nil); err != nil {
+		return 0, err
+	}
+
+	removed, err := c.OrganisationNodeClosure.Delete().
+		Where(entclosure.Or(entclosure.AncestorIDEQ(nodeID), entclosure.DescendantIDEQ(nodeID))).
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return removed, c.OrganisationNode.DeleteOneID(nodeID).Exec(ctx)
+}
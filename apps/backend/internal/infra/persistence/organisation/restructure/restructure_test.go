@@ -0,0 +1,164 @@
+package restructure_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SURF-Innovatie/MORIS/ent"
+	"github.com/SURF-Innovatie/MORIS/ent/enttest"
+	entclosure "github.com/SURF-Innovatie/MORIS/ent/organisationnodeclosure"
+	entprojectrole "github.com/SURF-Innovatie/MORIS/ent/projectrole"
+	entrolescope "github.com/SURF-Innovatie/MORIS/ent/rolescope"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/persistence/organisation/restructure"
+	"github.com/google/uuid"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func newClient(t *testing.T) *ent.Client {
+	t.Helper()
+	return enttest.Open(t, "sqlite3", "file:restructure_"+uuid.NewString()+"?mode=memory&cache=shared&_fk=1")
+}
+
+func createNode(t *testing.T, cli *ent.Client, name string, parent *ent.OrganisationNode) *ent.OrganisationNode {
+	t.Helper()
+	ctx := context.Background()
+
+	create := cli.OrganisationNode.Create().SetName(name)
+	if parent != nil {
+		create = create.SetParentID(parent.ID)
+	}
+	n, err := create.Save(ctx)
+	if err != nil {
+		t.Fatalf("create node %s: %v", name, err)
+	}
+	if _, err := cli.OrganisationNodeClosure.Create().SetAncestorID(n.ID).SetDescendantID(n.ID).SetDepth(0).Save(ctx); err != nil {
+		t.Fatalf("self closure: %v", err)
+	}
+	if parent != nil {
+		if _, err := cli.OrganisationNodeClosure.Create().SetAncestorID(parent.ID).SetDescendantID(n.ID).SetDepth(1).Save(ctx); err != nil {
+			t.Fatalf("parent closure: %v", err)
+		}
+	}
+	return n
+}
+
+func createPerson(t *testing.T, cli *ent.Client) *ent.Person {
+	t.Helper()
+	p, err := cli.Person.Create().
+		SetName("Ada Lovelace").
+		SetEmail(uuid.NewString() + "@example.org").
+		Save(context.Background())
+	if err != nil {
+		t.Fatalf("create person: %v", err)
+	}
+	return p
+}
+
+func TestUsageAndDeleteNode(t *testing.T) {
+	cli := newClient(t)
+	defer cli.Close()
+	ctx := context.Background()
+	repo := restructure.NewEntRepo(cli)
+
+	root := createNode(t, cli, "root", nil)
+	leaf := createNode(t, cli, "leaf", root)
+
+	if _, err := cli.WebhookSubscription.Create().
+		SetName("hook").SetOrgNodeID(leaf.ID).SetURL("https://example.org").SetSecret("s").
+		Save(ctx); err != nil {
+		t.Fatalf("create subscription: %v", err)
+	}
+
+	usage, err := repo.Usage(ctx, root.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(usage.ChildIDs) != 1 || !usage.InUse() {
+		t.Fatalf("root should be in use by its child, got %+v", usage)
+	}
+
+	usage, err = repo.Usage(ctx, leaf.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usage.InUse() || usage.WebhookSubscriptions != 1 {
+		t.Fatalf("leaf should only have a webhook subscription, got %+v", usage)
+	}
+
+	if n, err := repo.DeleteWebhookSubscriptions(ctx, leaf.ID); err != nil || n != 1 {
+		t.Fatalf("expected 1 deleted subscription, got %d, %v", n, err)
+	}
+	removed, err := repo.DeleteNode(ctx, leaf.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 2 {
+		t.Fatalf("expected 2 closure rows removed, got %d", removed)
+	}
+	if n := cli.OrganisationNodeClosure.Query().CountX(ctx); n != 1 {
+		t.Fatalf("expected only the root self closure, got %d rows", n)
+	}
+}
+
+func TestReassign_RenamesProjectRolesAndMergesMemberships(t *testing.T) {
+	cli := newClient(t)
+	defer cli.Close()
+	ctx := context.Background()
+	repo := restructure.NewEntRepo(cli)
+
+	root := createNode(t, cli, "root", nil)
+	source := createNode(t, cli, "source", root)
+	target := createNode(t, cli, "target", root)
+
+	cli.ProjectRole.Create().SetKey("contributor").SetName("Contributor").SetOrganisationNodeID(target.ID).SaveX(ctx)
+	moved := cli.ProjectRole.Create().SetKey("contributor").SetName("Contributor").SetOrganisationNodeID(source.ID).SaveX(ctx)
+
+	role := cli.OrganisationRole.Create().SetKey("admin").SetDisplayName("Admin").SetOrganisationNodeID(root.ID).SaveX(ctx)
+	sourceScope := cli.RoleScope.Create().SetRoleID(role.ID).SetRootNodeID(source.ID).SaveX(ctx)
+	targetScope := cli.RoleScope.Create().SetRoleID(role.ID).SetRootNodeID(target.ID).SaveX(ctx)
+
+	both, onlySource := createPerson(t, cli), createPerson(t, cli)
+	cli.Membership.Create().SetPersonID(both.ID).SetRoleScopeID(sourceScope.ID).SaveX(ctx)
+	cli.Membership.Create().SetPersonID(both.ID).SetRoleScopeID(targetScope.ID).SaveX(ctx)
+	cli.Membership.Create().SetPersonID(onlySource.ID).SetRoleScopeID(sourceScope.ID).SaveX(ctx)
+
+	cli.EventPolicy.Create().SetName("p").SetEventTypes([]string{}).SetActionType("notify").SetOrgNodeID(source.ID).SaveX(ctx)
+
+	res, err := repo.Reassign(ctx, source.ID, target.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := res.RenamedProjectRoles["contributor"]; got != "contributor-"+source.ID.String()[:8] {
+		t.Fatalf("expected the moved role to be renamed, got %q", got)
+	}
+	pr := cli.ProjectRole.Query().Where(entprojectrole.IDEQ(moved.ID)).OnlyX(ctx)
+	if pr.OrganisationNodeID != target.ID {
+		t.Fatalf("project role was not moved")
+	}
+
+	if res.MergedMemberships != 1 {
+		t.Fatalf("expected 1 duplicate membership dropped, got %d", res.MergedMemberships)
+	}
+	if cli.RoleScope.Query().Where(entrolescope.RootNodeIDEQ(source.ID)).ExistX(ctx) {
+		t.Fatalf("source scope should be gone")
+	}
+	if n := cli.Membership.Query().CountX(ctx); n != 2 {
+		t.Fatalf("expected 2 memberships on the target scope, got %d", n)
+	}
+
+	usage, err := repo.Usage(ctx, source.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usage.InUse() {
+		t.Fatalf("source should be unused after reassigning, got %+v", usage)
+	}
+
+	if _, err := repo.DeleteNode(ctx, source.ID); err != nil {
+		t.Fatal(err)
+	}
+	if cli.OrganisationNodeClosure.Query().Where(entclosure.DescendantIDEQ(source.ID)).ExistX(ctx) {
+		t.Fatalf("closure rows of the source should be gone")
+	}
+}
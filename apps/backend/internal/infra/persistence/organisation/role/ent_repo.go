@@ -3,6 +3,7 @@ package role
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/SURF-Innovatie/MORIS/ent"
 	entmembership "github.com/SURF-Innovatie/MORIS/ent/membership"
@@ -10,6 +11,7 @@ import (
 	entrolescope "github.com/SURF-Innovatie/MORIS/ent/rolescope"
 	"github.com/SURF-Innovatie/MORIS/internal/common/transform"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation/rbac"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/persistence/enttx"
 	"github.com/google/uuid"
 )
 
@@ -21,6 +23,14 @@ func NewEntRepo(cli *ent.Client) *EntRepo {
 	return &EntRepo{cli: cli}
 }
 
+// client returns the transactional client when ctx carries a transaction
+func (r *EntRepo) client(ctx context.Context) *ent.Client {
+	if tx, ok := enttx.TxFromContext(ctx); ok {
+		return tx.Client()
+	}
+	return r.cli
+}
+
 // EnsureDefaultRoles upserts/ensures keys exist + admin flags are correct.
 // EnsureDefaultRoles is deprecated as roles are now per-organisation.
 func (r *EntRepo) EnsureDefaultRoles(ctx context.Context) error {
@@ -28,7 +38,7 @@ func (r *EntRepo) EnsureDefaultRoles(ctx context.Context) error {
 }
 
 func (r *EntRepo) ListRoles(ctx context.Context, orgID *uuid.UUID) ([]*rbac.OrganisationRole, error) {
-	q := r.cli.OrganisationRole.Query()
+	q := r.client(ctx).OrganisationRole.Query()
 	if orgID != nil {
 		q = q.Where(entorgrole.OrganisationNodeIDEQ(*orgID))
 	}
@@ -47,7 +57,7 @@ func (r *EntRepo) CreateRole(ctx context.Context, orgID uuid.UUID, key, displayN
 		perms[i] = string(p)
 	}
 
-	row, err := r.cli.OrganisationRole.
+	row, err := r.client(ctx).OrganisationRole.
 		Create().
 		SetOrganisationNodeID(orgID).
 		SetKey(key).
@@ -61,7 +71,7 @@ func (r *EntRepo) CreateRole(ctx context.Context, orgID uuid.UUID, key, displayN
 }
 
 func (r *EntRepo) GetRole(ctx context.Context, roleID uuid.UUID) (*rbac.OrganisationRole, error) {
-	row, err := r.cli.OrganisationRole.Get(ctx, roleID)
+	row, err := r.client(ctx).OrganisationRole.Get(ctx, roleID)
 	if err != nil {
 		return nil, err
 	}
@@ -74,7 +84,7 @@ func (r *EntRepo) UpdateRole(ctx context.Context, roleID uuid.UUID, displayName
 		perms[i] = string(p)
 	}
 
-	row, err := r.cli.OrganisationRole.
+	row, err := r.client(ctx).OrganisationRole.
 		UpdateOneID(roleID).
 		SetDisplayName(displayName).
 		SetPermissions(perms).
@@ -87,7 +97,7 @@ func (r *EntRepo) UpdateRole(ctx context.Context, roleID uuid.UUID, displayName
 
 func (r *EntRepo) DeleteRole(ctx context.Context, roleID uuid.UUID) error {
 	// Check if used in scopes
-	inUse, err := r.cli.RoleScope.Query().Where(entrolescope.RoleIDEQ(roleID)).Exist(ctx)
+	inUse, err := r.client(ctx).RoleScope.Query().Where(entrolescope.RoleIDEQ(roleID)).Exist(ctx)
 	if err != nil {
 		return err
 	}
@@ -95,13 +105,13 @@ func (r *EntRepo) DeleteRole(ctx context.Context, roleID uuid.UUID) error {
 		return fmt.Errorf("role is in use by memberships and cannot be deleted")
 	}
 
-	return r.cli.OrganisationRole.DeleteOneID(roleID).Exec(ctx)
+	return r.client(ctx).OrganisationRole.DeleteOneID(roleID).Exec(ctx)
 }
 
 func (r *EntRepo) CreateScope(ctx context.Context, roleKey string, rootNodeID uuid.UUID) (*rbac.RoleScope, error) {
 	// Role Key is not unique globally anymore.
 	// We assume CreateScope is called with a key that is valid for the given rootNodeID (org).
-	role, err := r.cli.OrganisationRole.
+	role, err := r.client(ctx).OrganisationRole.
 		Query().
 		Where(
 			entorgrole.KeyEQ(roleKey),
@@ -112,11 +122,11 @@ func (r *EntRepo) CreateScope(ctx context.Context, roleKey string, rootNodeID uu
 		return nil, fmt.Errorf("role %q not found for org %s: %w", roleKey, rootNodeID, err)
 	}
 
-	if _, err := r.cli.OrganisationNode.Get(ctx, rootNodeID); err != nil {
+	if _, err := r.client(ctx).OrganisationNode.Get(ctx, rootNodeID); err != nil {
 		return nil, err
 	}
 
-	existing, err := r.cli.RoleScope.
+	existing, err := r.client(ctx).RoleScope.
 		Query().
 		Where(
 			entrolescope.RoleIDEQ(role.ID),
@@ -131,7 +141,7 @@ func (r *EntRepo) CreateScope(ctx context.Context, roleKey string, rootNodeID uu
 		return nil, err
 	}
 
-	row, err := r.cli.RoleScope.
+	row, err := r.client(ctx).RoleScope.
 		Create().
 		SetRoleID(role.ID).
 		SetRootNodeID(rootNodeID).
@@ -144,7 +154,7 @@ func (r *EntRepo) CreateScope(ctx context.Context, roleKey string, rootNodeID uu
 }
 
 func (r *EntRepo) GetScope(ctx context.Context, id uuid.UUID) (*rbac.RoleScope, error) {
-	row, err := r.cli.RoleScope.Get(ctx, id)
+	row, err := r.client(ctx).RoleScope.Get(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -152,15 +162,36 @@ func (r *EntRepo) GetScope(ctx context.Context, id uuid.UUID) (*rbac.RoleScope,
 	return transform.ToEntityPtr[rbac.RoleScope](row), nil
 }
 
-func (r *EntRepo) AddMembership(ctx context.Context, personID uuid.UUID, roleScopeID uuid.UUID) (*rbac.Membership, error) {
-	if _, err := r.cli.Person.Get(ctx, personID); err != nil {
+func (r *EntRepo) FindScope(ctx context.Context, roleKey string, rootNodeID uuid.UUID) (*rbac.RoleScope, error) {
+	row, err := r.client(ctx).RoleScope.
+		Query().
+		Where(
+			entrolescope.RootNodeIDEQ(rootNodeID),
+			entrolescope.HasRoleWith(
+				entorgrole.KeyEQ(roleKey),
+				entorgrole.OrganisationNodeIDEQ(rootNodeID),
+			),
+		).
+		Only(ctx)
+	if ent.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
 		return nil, err
 	}
-	if _, err := r.cli.RoleScope.Get(ctx, roleScopeID); err != nil {
+
+	return transform.ToEntityPtr[rbac.RoleScope](row), nil
+}
+
+func (r *EntRepo) AddMembership(ctx context.Context, personID uuid.UUID, roleScopeID uuid.UUID, startDate, endDate *time.Time) (*rbac.Membership, error) {
+	if _, err := r.client(ctx).Person.Get(ctx, personID); err != nil {
+		return nil, err
+	}
+	if _, err := r.client(ctx).RoleScope.Get(ctx, roleScopeID); err != nil {
 		return nil, err
 	}
 
-	exists, err := r.cli.Membership.
+	exists, err := r.client(ctx).Membership.
 		Query().
 		Where(
 			entmembership.PersonIDEQ(personID),
@@ -174,10 +205,12 @@ func (r *EntRepo) AddMembership(ctx context.Context, personID uuid.UUID, roleSco
 		return nil, fmt.Errorf("membership already exists")
 	}
 
-	row, err := r.cli.Membership.
+	row, err := r.client(ctx).Membership.
 		Create().
 		SetPersonID(personID).
 		SetRoleScopeID(roleScopeID).
+		SetNillableStartDate(startDate).
+		SetNillableEndDate(endDate).
 		Save(ctx)
 	if err != nil {
 		return nil, err
@@ -187,7 +220,27 @@ func (r *EntRepo) AddMembership(ctx context.Context, personID uuid.UUID, roleSco
 }
 
 func (r *EntRepo) GetMembership(ctx context.Context, membershipID uuid.UUID) (*rbac.Membership, error) {
-	row, err := r.cli.Membership.Get(ctx, membershipID)
+	row, err := r.client(ctx).Membership.Get(ctx, membershipID)
+	if err != nil {
+		return nil, err
+	}
+	return transform.ToEntityPtr[rbac.Membership](row), nil
+}
+
+func (r *EntRepo) SetMembershipPeriod(ctx context.Context, membershipID uuid.UUID, startDate, endDate *time.Time) (*rbac.Membership, error) {
+	upd := r.client(ctx).Membership.UpdateOneID(membershipID)
+	if startDate != nil {
+		upd.SetStartDate(*startDate)
+	} else {
+		upd.ClearStartDate()
+	}
+	if endDate != nil {
+		upd.SetEndDate(*endDate)
+	} else {
+		upd.ClearEndDate()
+	}
+
+	row, err := upd.Save(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -195,5 +248,5 @@ func (r *EntRepo) GetMembership(ctx context.Context, membershipID uuid.UUID) (*r
 }
 
 func (r *EntRepo) RemoveMembership(ctx context.Context, membershipID uuid.UUID) error {
-	return r.cli.Membership.DeleteOneID(membershipID).Exec(ctx)
+	return r.client(ctx).Membership.DeleteOneID(membershipID).Exec(ctx)
 }
@@ -286,13 +286,13 @@ func TestAddMembership_DeduplicatesAndRemoveMembership(t *testing.T) {
 		t.Fatalf("create scope: %v", err)
 	}
 
-	m1, err := repo.AddMembership(ctx, personID, sc.ID)
+	m1, err := repo.AddMembership(ctx, personID, sc.ID, nil, nil)
 	if err != nil {
 		t.Fatalf("AddMembership: %v", err)
 	}
 
 	// Duplicate should error
-	_, err = repo.AddMembership(ctx, personID, sc.ID)
+	_, err = repo.AddMembership(ctx, personID, sc.ID, nil, nil)
 	if err == nil {
 		t.Fatalf("expected duplicate membership error")
 	}
@@ -0,0 +1,45 @@
+package rorimport
+
+import (
+	"context"
+
+	"github.com/SURF-Innovatie/MORIS/ent"
+	entorgnode "github.com/SURF-Innovatie/MORIS/ent/organisationnode"
+	"github.com/SURF-Innovatie/MORIS/internal/common/transform"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/persistence/enttx"
+	"github.com/google/uuid"
+)
+
+// EntRepo looks up nodes by ROR ID and records their ROR status.
+// All methods join the transaction in the context, if any.
+type EntRepo struct {
+	cli *ent.Client
+}
+
+func NewEntRepo(cli *ent.Client) *EntRepo {
+	return &EntRepo{cli: cli}
+}
+
+func (r *EntRepo) client(ctx context.Context) *ent.Client {
+	if tx, ok := enttx.TxFromContext(ctx); ok {
+		return tx.Client()
+	}
+	return r.cli
+}
+
+func (r *EntRepo) FindByRORIDs(ctx context.Context, ids []string) ([]organisation.OrganisationNode, error) {
+	rows, err := r.client(ctx).OrganisationNode.
+		Query().
+		Where(entorgnode.RorIDIn(ids...)).
+		Order(ent.Asc(entorgnode.FieldName)).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return transform.ToEntities[organisation.OrganisationNode](rows), nil
+}
+
+func (r *EntRepo) SetRORStatus(ctx context.Context, nodeID uuid.UUID, status string) error {
+	return r.client(ctx).OrganisationNode.UpdateOneID(nodeID).SetRorStatus(status).Exec(ctx)
+}
@@ -0,0 +1,125 @@
+package rorimport_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SURF-Innovatie/MORIS/ent"
+	"github.com/SURF-Innovatie/MORIS/ent/enttest"
+	"github.com/SURF-Innovatie/MORIS/external/ror"
+	orgsvc "github.com/SURF-Innovatie/MORIS/internal/app/organisation"
+	approrimport "github.com/SURF-Innovatie/MORIS/internal/app/organisation/rorimport"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/persistence/enttx"
+	orgrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/organisation"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/persistence/organisation/rorimport"
+	"github.com/google/uuid"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type fakeROR map[string]*ror.Organization
+
+func (f fakeROR) GetOrganization(_ context.Context, id string) (*ror.Organization, error) {
+	return f[id], nil
+}
+
+func org(id, name string, status ror.OrganizationStatus, children ...string) *ror.Organization {
+	o := &ror.Organization{
+		Id:                 "https://ror.org/" + id,
+		Names:              []ror.OrganizationName{{Value: name, Types: []ror.OrganizationNameType{ror.OrganizationNameTypeRorDisplay}}},
+		OrganizationStatus: status,
+	}
+	for _, c := range children {
+		o.Relationships = append(o.Relationships, ror.OrganizationRelationship{Id: "https://ror.org/" + c, Type: "child"})
+	}
+	return o
+}
+
+func setup(t *testing.T, registry fakeROR) (*ent.Client, approrimport.Service) {
+	t.Helper()
+	cli := enttest.Open(t, "sqlite3", "file:rorimport_"+uuid.NewString()+"?mode=memory&cache=shared&_fk=1")
+	t.Cleanup(func() { _ = cli.Close() })
+
+	tx := enttx.NewManager(cli)
+	orgs := orgsvc.NewService(orgrepo.NewEntRepo(cli), nil, nil, tx)
+	return cli, approrimport.NewService(rorimport.NewEntRepo(cli), registry, orgs, tx)
+}
+
+func TestImport_CreatesThenUpdatesIdempotently(t *testing.T) {
+	ctx := context.Background()
+	registry := fakeROR{
+		"04dkp9463": org("04dkp9463", "University of Amsterdam", ror.OrganizationStatusActive, "00aaaaa00", "00bbbbb00", "00ccccc00"),
+		"00aaaaa00": org("00aaaaa00", "Faculty of Science", ror.OrganizationStatusActive),
+		"00bbbbb00": org("00bbbbb00", "Faculty of Law", ror.OrganizationStatusActive),
+		"00ccccc00": org("00ccccc00", "Closed Institute", ror.OrganizationStatusWithdrawn),
+	}
+	cli, svc := setup(t, registry)
+
+	preview, err := svc.Import(ctx, "https://ror.org/04dkp9463", approrimport.ImportOptions{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preview.Created != 3 || preview.Skipped != 1 || preview.Items[0].NodeID != nil {
+		t.Fatalf("expected a preview of 3 creates and 1 skip without node IDs, got %+v", preview)
+	}
+	if n := cli.OrganisationNode.Query().CountX(ctx); n != 0 {
+		t.Fatalf("expected the dry run to create nothing, found %d nodes", n)
+	}
+
+	first, err := svc.Import(ctx, "04dkp9463", approrimport.ImportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Created != 3 || first.Skipped != 1 {
+		t.Fatalf("expected 3 creates and 1 skip, got %+v", first)
+	}
+	root := cli.OrganisationNode.GetX(ctx, *first.Items[0].NodeID)
+	if root.ParentID != nil || root.RorID == nil || *root.RorID != "https://ror.org/04dkp9463" {
+		t.Fatalf("expected a root node with the normalized ROR ID, got %+v", root)
+	}
+	child := cli.OrganisationNode.GetX(ctx, *first.Items[1].NodeID)
+	if child.ParentID == nil || *child.ParentID != root.ID {
+		t.Fatalf("expected the faculty under the university, got %+v", child)
+	}
+
+	again, err := svc.Import(ctx, "04dkp9463", approrimport.ImportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Unchanged != 3 || again.Created != 0 || again.Updated != 0 {
+		t.Fatalf("expected a re-run to change nothing, got %+v", again)
+	}
+
+	registry["00aaaaa00"] = org("00aaaaa00", "Faculty of Natural Sciences", ror.OrganizationStatusActive)
+	registry["00bbbbb00"] = org("00bbbbb00", "Faculty of Law", ror.OrganizationStatusWithdrawn)
+	updated, err := svc.Import(ctx, "04dkp9463", approrimport.ImportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Updated != 2 || updated.Unchanged != 1 || updated.Created != 0 {
+		t.Fatalf("expected the rename and the withdrawal as updates, got %+v", updated)
+	}
+	if got := updated.Items[1].Changes; len(got) != 1 || got[0] != organisation.RORImportChangeName {
+		t.Fatalf("expected a name change, got %v", got)
+	}
+	if n := cli.OrganisationNode.GetX(ctx, child.ID); n.Name != "Faculty of Natural Sciences" {
+		t.Fatalf("expected the node to be renamed, got %q", n.Name)
+	}
+	law := cli.OrganisationNode.GetX(ctx, *updated.Items[2].NodeID)
+	if law.RorStatus == nil || *law.RorStatus != "withdrawn" {
+		t.Fatalf("expected the withdrawn faculty to be kept and marked, got %+v", law)
+	}
+	if n := cli.OrganisationNode.Query().CountX(ctx); n != 3 {
+		t.Fatalf("expected no duplicate nodes, found %d", n)
+	}
+}
+
+func TestImport_UnknownRORID(t *testing.T) {
+	_, svc := setup(t, fakeROR{})
+	if _, err := svc.Import(context.Background(), "04dkp9463", approrimport.ImportOptions{}); err == nil {
+		t.Fatal("expected an error for an organisation ROR does not know")
+	}
+	if _, err := svc.Import(context.Background(), "not-a-ror-id", approrimport.ImportOptions{}); err == nil {
+		t.Fatal("expected an error for a malformed ROR ID")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	pe "github.com/SURF-Innovatie/MORIS/ent/person"
 	"github.com/SURF-Innovatie/MORIS/internal/common/transform"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/identity"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/persistence/enttx"
 	"github.com/google/uuid"
 )
 
@@ -18,11 +19,19 @@ func NewEntRepo(cli *ent.Client) *EntRepo {
 	return &EntRepo{cli: cli}
 }
 
+// client returns the transactional client when ctx carries a transaction
+func (r *EntRepo) client(ctx context.Context) *ent.Client {
+	if tx, ok := enttx.TxFromContext(ctx); ok {
+		return tx.Client()
+	}
+	return r.cli
+}
+
 func (r *EntRepo) Create(ctx context.Context, p identity.Person) (*identity.Person, error) {
 	if p.ORCiD != nil && *p.ORCiD == "" {
 		p.ORCiD = nil
 	}
-	row, err := r.cli.Person.
+	row, err := r.client(ctx).Person.
 		Create().
 		SetName(p.Name).
 		SetNillableGivenName(p.GivenName).
@@ -39,7 +48,7 @@ func (r *EntRepo) Create(ctx context.Context, p identity.Person) (*identity.Pers
 }
 
 func (r *EntRepo) Get(ctx context.Context, id uuid.UUID) (*identity.Person, error) {
-	row, err := r.cli.Person.
+	row, err := r.client(ctx).Person.
 		Query().
 		Where(pe.IDEQ(id)).
 		Only(ctx)
@@ -50,7 +59,7 @@ func (r *EntRepo) Get(ctx context.Context, id uuid.UUID) (*identity.Person, erro
 }
 
 func (r *EntRepo) Update(ctx context.Context, id uuid.UUID, p identity.Person) (*identity.Person, error) {
-	q := r.cli.Person.
+	q := r.client(ctx).Person.
 		UpdateOneID(id).
 		SetName(p.Name).
 		SetNillableGivenName(p.GivenName).
@@ -74,7 +83,7 @@ func (r *EntRepo) Update(ctx context.Context, id uuid.UUID, p identity.Person) (
 }
 
 func (r *EntRepo) List(ctx context.Context) ([]*identity.Person, error) {
-	rows, err := r.cli.Person.Query().All(ctx)
+	rows, err := r.client(ctx).Person.Query().All(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -82,7 +91,7 @@ func (r *EntRepo) List(ctx context.Context) ([]*identity.Person, error) {
 }
 
 func (r *EntRepo) GetByEmail(ctx context.Context, email string) (*identity.Person, error) {
-	row, err := r.cli.Person.
+	row, err := r.client(ctx).Person.
 		Query().
 		Where(pe.EmailEQ(email)).
 		Only(ctx)
@@ -93,7 +102,7 @@ func (r *EntRepo) GetByEmail(ctx context.Context, email string) (*identity.Perso
 }
 
 func (r *EntRepo) Search(ctx context.Context, query string, limit int) ([]identity.Person, error) {
-	rows, err := r.cli.Person.
+	rows, err := r.client(ctx).Person.
 		Query().
 		Where(pe.Or(
 			pe.NameContainsFold(query),
@@ -108,7 +117,7 @@ func (r *EntRepo) Search(ctx context.Context, query string, limit int) ([]identi
 }
 
 func (r *EntRepo) SetORCID(ctx context.Context, personID uuid.UUID, orcidID string) error {
-	_, err := r.cli.Person.
+	_, err := r.client(ctx).Person.
 		UpdateOneID(personID).
 		SetOrcidID(orcidID).
 		Save(ctx)
@@ -116,7 +125,7 @@ func (r *EntRepo) SetORCID(ctx context.Context, personID uuid.UUID, orcidID stri
 }
 
 func (r *EntRepo) ClearORCID(ctx context.Context, personID uuid.UUID) error {
-	_, err := r.cli.Person.
+	_, err := r.client(ctx).Person.
 		UpdateOneID(personID).
 		ClearOrcidID().
 		Save(ctx)
@@ -127,7 +136,7 @@ func (r *EntRepo) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]identity.Per
 	if len(ids) == 0 {
 		return []identity.Person{}, nil
 	}
-	rows, err := r.cli.Person.Query().
+	rows, err := r.client(ctx).Person.Query().
 		Where(pe.IDIn(ids...)).
 		All(ctx)
 	if err != nil {
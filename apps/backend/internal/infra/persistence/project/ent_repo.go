@@ -3,12 +3,17 @@ package project
 import (
 	"context"
 	"encoding/json"
+	"time"
 
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqljson"
 	"github.com/SURF-Innovatie/MORIS/ent"
 	affiliatedorgent "github.com/SURF-Innovatie/MORIS/ent/affiliatedorganisation"
 	en "github.com/SURF-Innovatie/MORIS/ent/event"
 	organisationent "github.com/SURF-Innovatie/MORIS/ent/organisationnode"
+	entclosure "github.com/SURF-Innovatie/MORIS/ent/organisationnodeclosure"
 	personent "github.com/SURF-Innovatie/MORIS/ent/person"
+	"github.com/SURF-Innovatie/MORIS/ent/predicate"
 	productent "github.com/SURF-Innovatie/MORIS/ent/product"
 	entprojectrole "github.com/SURF-Innovatie/MORIS/ent/projectrole"
 	"github.com/SURF-Innovatie/MORIS/internal/common/transform"
@@ -16,6 +21,7 @@ import (
 	"github.com/SURF-Innovatie/MORIS/internal/domain/identity"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/product"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/project"
 	events2 "github.com/SURF-Innovatie/MORIS/internal/domain/project/events"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/project/role"
 	"github.com/google/uuid"
@@ -154,6 +160,148 @@ func (r *EntRepo) ProjectIDsStarted(ctx context.Context) ([]uuid.UUID, error) {
 	return projectIDs, nil
 }
 
+// CandidateProjectIDs returns the projects the person was ever assigned to, that were ever public, or that
+// were ever owned by one of the nodes. The projects a person may read are among them; since the events
+// are filtered on their payload rather than replayed, callers check each candidate against its current state.
+func (r *EntRepo) CandidateProjectIDs(ctx context.Context, personID uuid.UUID, ownerNodeIDs []uuid.UUID) ([]uuid.UUID, error) {
+	owners := lo.Map(ownerNodeIDs, func(id uuid.UUID, _ int) any { return id.String() })
+
+	var projectIDs []uuid.UUID
+	if err := r.cli.Event.Query().
+		Where(predicate.Event(func(s *sql.Selector) {
+			data := s.C(en.FieldData)
+			typ := s.C(en.FieldType)
+			candidates := []*sql.Predicate{
+				sql.And(
+					sql.EQ(typ, events2.ProjectRoleAssignedType),
+					sqljson.ValueEQ(data, personID.String(), sqljson.Path("person_id")),
+				),
+				sql.And(
+					sql.In(typ, events2.ProjectStartedType, events2.VisibilityChangedType),
+					sqljson.ValueEQ(data, string(project.VisibilityPublic), sqljson.Path("visibility")),
+				),
+			}
+			if len(owners) > 0 {
+				candidates = append(candidates, sql.And(
+					sql.In(typ, events2.ProjectStartedType, events2.OwningOrgNodeChangedType),
+					sqljson.ValueIn(data, owners, sqljson.Path("owning_org_node_id")),
+				))
+			}
+			s.Where(sql.Or(candidates...))
+		})).
+		Unique(true).
+		Select(en.FieldProjectID).
+		Scan(ctx, &projectIDs); err != nil {
+		return nil, err
+	}
+	return lo.Uniq(projectIDs), nil
+}
+
+// ProjectIDsEverOwnedBy returns the projects that were started under or moved to one of the nodes.
+// The projects the nodes own now are among them; callers check each candidate against its current state.
+func (r *EntRepo) ProjectIDsEverOwnedBy(ctx context.Context, nodeIDs []uuid.UUID) ([]uuid.UUID, error) {
+	if len(nodeIDs) == 0 {
+		return nil, nil
+	}
+	owners := lo.Map(nodeIDs, func(id uuid.UUID, _ int) any { return id.String() })
+
+	var projectIDs []uuid.UUID
+	if err := r.cli.Event.Query().
+		Where(
+			en.TypeIn(events2.ProjectStartedType, events2.OwningOrgNodeChangedType),
+			predicate.Event(func(s *sql.Selector) {
+				s.Where(sqljson.ValueIn(s.C(en.FieldData), owners, sqljson.Path("owning_org_node_id")))
+			}),
+		).
+		Unique(true).
+		Select(en.FieldProjectID).
+		Scan(ctx, &projectIDs); err != nil {
+		return nil, err
+	}
+	return lo.Uniq(projectIDs), nil
+}
+
+// ProjectIDsEverOwnedUnder returns the projects that were started under or moved to the node or one
+// of its descendants
+func (r *EntRepo) ProjectIDsEverOwnedUnder(ctx context.Context, nodeID uuid.UUID) ([]uuid.UUID, error) {
+	var nodeIDs []uuid.UUID
+	if err := r.cli.OrganisationNodeClosure.Query().
+		Where(entclosure.AncestorIDEQ(nodeID)).
+		Select(entclosure.FieldDescendantID).
+		Scan(ctx, &nodeIDs); err != nil {
+		return nil, err
+	}
+	return r.ProjectIDsEverOwnedBy(ctx, nodeIDs)
+}
+
+// ProjectIDsWithStartDateBetween returns the projects whose start date was at some point set to a
+// day within [from, to]
+func (r *EntRepo) ProjectIDsWithStartDateBetween(ctx context.Context, from, to time.Time) ([]uuid.UUID, error) {
+	return r.projectIDsWithDateBetween(ctx, from, to, map[string]string{
+		events2.ProjectStartedType:   "start_date",
+		events2.StartDateChangedType: "startDate",
+	})
+}
+
+// ProjectIDsWithEndDateBetween returns the projects whose end date was at some point set to a day
+// within [from, to]
+func (r *EntRepo) ProjectIDsWithEndDateBetween(ctx context.Context, from, to time.Time) ([]uuid.UUID, error) {
+	return r.projectIDsWithDateBetween(ctx, from, to, map[string]string{
+		events2.ProjectStartedType: "end_date",
+		events2.EndDateChangedType: "endDate",
+	})
+}
+
+// ProjectIDsWithMemberEndDateBetween returns the projects with a member whose end date was at some
+// point set to a day within [from, to]
+func (r *EntRepo) ProjectIDsWithMemberEndDateBetween(ctx context.Context, from, to time.Time) ([]uuid.UUID, error) {
+	return r.projectIDsWithDateBetween(ctx, from, to, map[string]string{
+		events2.ProjectRoleAssignedType: "end_date",
+	})
+}
+
+// projectIDsWithDateBetween finds the events that set a date, keyed by event type and payload key.
+// Dates are stored in the offset they were given in and compared as text, so the range is widened by
+// a day on both sides: the result is a superset that callers check against the current state.
+func (r *EntRepo) projectIDsWithDateBetween(ctx context.Context, from, to time.Time, keys map[string]string) ([]uuid.UUID, error) {
+	lower := from.UTC().AddDate(0, 0, -1).Format(time.DateOnly)
+	upper := to.UTC().AddDate(0, 0, 2).Format(time.DateOnly)
+
+	var projectIDs []uuid.UUID
+	if err := r.cli.Event.Query().
+		Where(predicate.Event(func(s *sql.Selector) {
+			data := s.C(en.FieldData)
+			preds := make([]*sql.Predicate, 0, len(keys))
+			for eventType, key := range keys {
+				preds = append(preds, sql.And(
+					sql.EQ(s.C(en.FieldType), eventType),
+					sqljson.ValueGTE(data, lower, sqljson.Path(key)),
+					sqljson.ValueLT(data, upper, sqljson.Path(key)),
+				))
+			}
+			s.Where(sql.Or(preds...))
+		})).
+		Unique(true).
+		Select(en.FieldProjectID).
+		Scan(ctx, &projectIDs); err != nil {
+		return nil, err
+	}
+	return lo.Uniq(projectIDs), nil
+}
+
+// ProjectIDsWithEventBetween returns the projects with an event of the type that occurred within [from, to]
+func (r *EntRepo) ProjectIDsWithEventBetween(ctx context.Context, eventType string, from, to time.Time) ([]uuid.UUID, error) {
+	var projectIDs []uuid.UUID
+	if err := r.cli.Event.Query().
+		Where(en.TypeEQ(eventType), en.OccurredAtGTE(from), en.OccurredAtLTE(to)).
+		Unique(true).
+		Select(en.FieldProjectID).
+		Scan(ctx, &projectIDs); err != nil {
+		return nil, err
+	}
+	return lo.Uniq(projectIDs), nil
+}
+
 func (r *EntRepo) ListAncestors(ctx context.Context, orgID uuid.UUID) ([]uuid.UUID, error) {
 	rows, err := r.cli.OrganisationNodeClosure.Query().
 		Where().
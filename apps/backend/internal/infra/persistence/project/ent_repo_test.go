@@ -0,0 +1,178 @@
+package project_test
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/ent/enttest"
+	events2 "github.com/SURF-Innovatie/MORIS/internal/domain/project/events"
+	projectrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/project"
+	"github.com/google/uuid"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestEntRepo_CandidateProjectIDs(t *testing.T) {
+	ctx := context.Background()
+	cli := enttest.Open(t, "sqlite3", "file:project_"+uuid.NewString()+"?mode=memory&cache=shared&_fk=1")
+	defer cli.Close()
+
+	repo := projectrepo.NewEntRepo(cli)
+	person, node, otherNode := uuid.New(), uuid.New(), uuid.New()
+
+	add := func(projectID uuid.UUID, version int, eventType string, data map[string]any) {
+		cli.Event.Create().SetProjectID(projectID).SetVersion(version).SetType(eventType).SetStatus("approved").SetData(data).ExecX(ctx)
+	}
+	started := func(projectID, owner uuid.UUID, visibility string) {
+		add(projectID, 1, events2.ProjectStartedType, map[string]any{"owning_org_node_id": owner.String(), "visibility": visibility})
+	}
+
+	member, public, madePublic, owned, movedIn, unrelated := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	started(member, otherNode, "private")
+	add(member, 2, events2.ProjectRoleAssignedType, map[string]any{"person_id": person.String(), "project_role_id": uuid.NewString()})
+	started(public, otherNode, "public")
+	started(madePublic, otherNode, "private")
+	add(madePublic, 2, events2.VisibilityChangedType, map[string]any{"visibility": "public"})
+	started(owned, node, "organisation")
+	started(movedIn, otherNode, "organisation")
+	add(movedIn, 2, events2.OwningOrgNodeChangedType, map[string]any{"owning_org_node_id": node.String()})
+	started(unrelated, otherNode, "organisation")
+	add(unrelated, 2, events2.ProjectRoleAssignedType, map[string]any{"person_id": uuid.NewString(), "project_role_id": uuid.NewString()})
+
+	ids, err := repo.CandidateProjectIDs(ctx, person, []uuid.UUID{node})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, id := range map[string]uuid.UUID{"member": member, "public": public, "made public": madePublic, "owned": owned, "moved in": movedIn} {
+		if !slices.Contains(ids, id) {
+			t.Errorf("expected the %s project to be a candidate", name)
+		}
+	}
+	if slices.Contains(ids, unrelated) || len(ids) != 5 {
+		t.Fatalf("expected exactly 5 candidates, got %d", len(ids))
+	}
+
+	ids, err = repo.CandidateProjectIDs(ctx, person, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 3 {
+		t.Fatalf("expected the member and public projects only, got %d", len(ids))
+	}
+}
+
+func TestEntRepo_ProjectIDsEverOwnedBy(t *testing.T) {
+	ctx := context.Background()
+	cli := enttest.Open(t, "sqlite3", "file:project_"+uuid.NewString()+"?mode=memory&cache=shared&_fk=1")
+	defer cli.Close()
+
+	repo := projectrepo.NewEntRepo(cli)
+	node, otherNode := uuid.New(), uuid.New()
+
+	add := func(projectID uuid.UUID, version int, eventType string, owner uuid.UUID) {
+		cli.Event.Create().SetProjectID(projectID).SetVersion(version).SetType(eventType).SetStatus("approved").
+			SetData(map[string]any{"owning_org_node_id": owner.String()}).ExecX(ctx)
+	}
+
+	owned, movedIn, movedOut, unrelated := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	add(owned, 1, events2.ProjectStartedType, node)
+	add(movedIn, 1, events2.ProjectStartedType, otherNode)
+	add(movedIn, 2, events2.OwningOrgNodeChangedType, node)
+	add(movedOut, 1, events2.ProjectStartedType, node)
+	add(movedOut, 2, events2.OwningOrgNodeChangedType, otherNode)
+	add(unrelated, 1, events2.ProjectStartedType, otherNode)
+
+	ids, err := repo.ProjectIDsEverOwnedBy(ctx, []uuid.UUID{node})
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.SortFunc(ids, func(a, b uuid.UUID) int { return strings.Compare(a.String(), b.String()) })
+	want := []uuid.UUID{owned, movedIn, movedOut}
+	slices.SortFunc(want, func(a, b uuid.UUID) int { return strings.Compare(a.String(), b.String()) })
+	if !slices.Equal(ids, want) {
+		t.Fatalf("expected the projects that were ever owned by the node, got %v", ids)
+	}
+
+	if ids, err := repo.ProjectIDsEverOwnedBy(ctx, nil); err != nil || len(ids) != 0 {
+		t.Fatalf("expected no candidates without nodes, got %v (%v)", ids, err)
+	}
+}
+
+func TestEntRepo_ProjectIDsWithDateOrEventBetween(t *testing.T) {
+	ctx := context.Background()
+	cli := enttest.Open(t, "sqlite3", "file:project_"+uuid.NewString()+"?mode=memory&cache=shared&_fk=1")
+	defer cli.Close()
+
+	repo := projectrepo.NewEntRepo(cli)
+	add := func(projectID uuid.UUID, version int, eventType string, occurredAt time.Time, data map[string]any) {
+		cli.Event.Create().SetProjectID(projectID).SetVersion(version).SetType(eventType).SetStatus("approved").
+			SetOccurredAt(occurredAt).SetData(data).ExecX(ctx)
+	}
+	date := func(y int, m time.Month, d int) string {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Format(time.RFC3339)
+	}
+	startedAt := time.Date(2025, 1, 10, 9, 0, 0, 0, time.UTC)
+
+	endsInMarch, movedToMarch, endsLater := uuid.New(), uuid.New(), uuid.New()
+	add(endsInMarch, 1, events2.ProjectStartedType, startedAt, map[string]any{"start_date": date(2025, 1, 1), "end_date": date(2025, 3, 15)})
+	add(movedToMarch, 1, events2.ProjectStartedType, startedAt, map[string]any{"start_date": date(2025, 1, 1), "end_date": date(2026, 1, 1)})
+	add(movedToMarch, 2, events2.EndDateChangedType, startedAt.AddDate(0, 1, 0), map[string]any{"endDate": date(2025, 3, 20)})
+	add(endsLater, 1, events2.ProjectStartedType, startedAt.AddDate(0, 2, 0), map[string]any{"start_date": date(2025, 3, 1), "end_date": date(2027, 1, 1)})
+
+	from, to := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+	ids, err := repo.ProjectIDsWithEndDateBetween(ctx, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 || !slices.Contains(ids, endsInMarch) || !slices.Contains(ids, movedToMarch) {
+		t.Fatalf("expected the projects ending in March, got %v", ids)
+	}
+
+	ids, err = repo.ProjectIDsWithStartDateBetween(ctx, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 1 || ids[0] != endsLater {
+		t.Fatalf("expected only the project starting in March, got %v", ids)
+	}
+
+	ids, err = repo.ProjectIDsWithEventBetween(ctx, events2.ProjectStartedType, startedAt, startedAt.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 || slices.Contains(ids, endsLater) {
+		t.Fatalf("expected the projects started on the first day, got %v", ids)
+	}
+}
+
+func TestEntRepo_ProjectIDsWithMemberEndDateBetween(t *testing.T) {
+	ctx := context.Background()
+	cli := enttest.Open(t, "sqlite3", "file:project_"+uuid.NewString()+"?mode=memory&cache=shared&_fk=1")
+	defer cli.Close()
+
+	repo := projectrepo.NewEntRepo(cli)
+	assign := func(projectID uuid.UUID, end *time.Time) {
+		data := map[string]any{"person_id": uuid.NewString(), "project_role_id": uuid.NewString()}
+		if end != nil {
+			data["end_date"] = end.Format(time.RFC3339)
+		}
+		cli.Event.Create().SetProjectID(projectID).SetVersion(1).SetType(events2.ProjectRoleAssignedType).SetStatus("approved").SetData(data).ExecX(ctx)
+	}
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	nextWeek, nextYear := now.AddDate(0, 0, 7), now.AddDate(1, 0, 0)
+	leaving, later, open := uuid.New(), uuid.New(), uuid.New()
+	assign(leaving, &nextWeek)
+	assign(later, &nextYear)
+	assign(open, nil)
+
+	ids, err := repo.ProjectIDsWithMemberEndDateBetween(ctx, now.AddDate(0, 0, -30), now.AddDate(0, 0, 14))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 1 || ids[0] != leaving {
+		t.Fatalf("expected only the project with a member leaving soon, got %v", ids)
+	}
+}
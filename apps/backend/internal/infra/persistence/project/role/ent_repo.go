@@ -11,6 +11,7 @@ import (
 	"github.com/SURF-Innovatie/MORIS/internal/app/project/role"
 	"github.com/SURF-Innovatie/MORIS/internal/common/transform"
 	role2 "github.com/SURF-Innovatie/MORIS/internal/domain/project/role"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/persistence/enttx"
 	"github.com/google/uuid"
 )
 
@@ -22,8 +23,16 @@ func NewEntRepo(cli *ent.Client) role.Repository {
 	return &entRepo{cli: cli}
 }
 
+// client returns the transactional client when ctx carries a transaction
+func (e *entRepo) client(ctx context.Context) *ent.Client {
+	if tx, ok := enttx.TxFromContext(ctx); ok {
+		return tx.Client()
+	}
+	return e.cli
+}
+
 func (e *entRepo) Create(ctx context.Context, key, name string, orgNodeID uuid.UUID) (*role2.ProjectRole, error) {
-	r, err := e.cli.ProjectRole.Create().
+	r, err := e.client(ctx).ProjectRole.Create().
 		SetKey(key).
 		SetName(name).
 		SetOrganisationNodeID(orgNodeID).
@@ -36,7 +45,7 @@ func (e *entRepo) Create(ctx context.Context, key, name string, orgNodeID uuid.U
 }
 
 func (e *entRepo) GetByKeyAndOrg(ctx context.Context, key string, orgNodeID uuid.UUID) (*role2.ProjectRole, error) {
-	r, err := e.cli.ProjectRole.Query().
+	r, err := e.client(ctx).ProjectRole.Query().
 		Where(
 			entprojectrole.KeyEQ(key),
 			entprojectrole.OrganisationNodeIDEQ(orgNodeID),
@@ -52,7 +61,7 @@ func (e *entRepo) GetByKeyAndOrg(ctx context.Context, key string, orgNodeID uuid
 
 func (e *entRepo) Delete(ctx context.Context, id uuid.UUID, orgNodeID uuid.UUID) error {
 	// Soft delete
-	n, err := e.cli.ProjectRole.Update().
+	n, err := e.client(ctx).ProjectRole.Update().
 		Where(
 			entprojectrole.ID(id),
 			entprojectrole.OrganisationNodeIDEQ(orgNodeID),
@@ -69,7 +78,7 @@ func (e *entRepo) Delete(ctx context.Context, id uuid.UUID, orgNodeID uuid.UUID)
 }
 
 func (e *entRepo) ListByOrgIDs(ctx context.Context, orgIDs []uuid.UUID) ([]role2.ProjectRole, error) {
-	rows, err := e.cli.ProjectRole.Query().
+	rows, err := e.client(ctx).ProjectRole.Query().
 		Where(
 			entprojectrole.OrganisationNodeIDIn(orgIDs...),
 			entprojectrole.ArchivedAtIsNil(),
@@ -84,7 +93,7 @@ func (e *entRepo) ListByOrgIDs(ctx context.Context, orgIDs []uuid.UUID) ([]role2
 }
 
 func (e *entRepo) Exists(ctx context.Context, key string, orgNodeID uuid.UUID) (bool, error) {
-	return e.cli.ProjectRole.Query().
+	return e.client(ctx).ProjectRole.Query().
 		Where(
 			entprojectrole.KeyEQ(key),
 			entprojectrole.OrganisationNodeIDEQ(orgNodeID),
@@ -92,20 +101,21 @@ func (e *entRepo) Exists(ctx context.Context, key string, orgNodeID uuid.UUID) (
 		Exist(ctx)
 }
 
-func (e *entRepo) Unarchive(ctx context.Context, key string, orgNodeID uuid.UUID) error {
-	return e.cli.ProjectRole.Update().
+func (e *entRepo) Unarchive(ctx context.Context, key string, orgNodeID uuid.UUID) (bool, error) {
+	n, err := e.client(ctx).ProjectRole.Update().
 		Where(
 			entprojectrole.KeyEQ(key),
 			entprojectrole.OrganisationNodeIDEQ(orgNodeID),
 			entprojectrole.ArchivedAtNotNil(),
 		).
 		ClearArchivedAt().
-		Exec(ctx)
+		Save(ctx)
+	return n > 0, err
 }
 
 func (e *entRepo) CreateOrRestore(ctx context.Context, key, name string, orgNodeID uuid.UUID) (*role2.ProjectRole, error) {
 	// First check if it exists (including archived)
-	existing, err := e.cli.ProjectRole.Query().
+	existing, err := e.client(ctx).ProjectRole.Query().
 		Where(
 			entprojectrole.KeyEQ(key),
 			entprojectrole.OrganisationNodeIDEQ(orgNodeID),
@@ -118,7 +128,7 @@ func (e *entRepo) CreateOrRestore(ctx context.Context, key, name string, orgNode
 
 	if existing != nil {
 		if existing.ArchivedAt != nil {
-			updated, err := e.cli.ProjectRole.UpdateOne(existing).
+			updated, err := e.client(ctx).ProjectRole.UpdateOne(existing).
 				ClearArchivedAt().
 				SetName(name).
 				Save(ctx)
@@ -130,7 +140,7 @@ func (e *entRepo) CreateOrRestore(ctx context.Context, key, name string, orgNode
 		return nil, fmt.Errorf("role with key '%s' already exists", key)
 	}
 
-	r, err := e.cli.ProjectRole.Create().
+	r, err := e.client(ctx).ProjectRole.Create().
 		SetKey(key).
 		SetName(name).
 		SetOrganisationNodeID(orgNodeID).
@@ -142,7 +152,7 @@ func (e *entRepo) CreateOrRestore(ctx context.Context, key, name string, orgNode
 }
 
 func (e *entRepo) List(ctx context.Context) ([]role2.ProjectRole, error) {
-	rows, err := e.cli.ProjectRole.Query().
+	rows, err := e.client(ctx).ProjectRole.Query().
 		Where(entprojectrole.ArchivedAtIsNil()).
 		Order(ent.Asc(entprojectrole.FieldKey)).
 		All(ctx)
@@ -154,7 +164,7 @@ func (e *entRepo) List(ctx context.Context) ([]role2.ProjectRole, error) {
 }
 
 func (e *entRepo) CreateWithEventTypes(ctx context.Context, key, name string, orgNodeID uuid.UUID, allowedEventTypes []string) (*role2.ProjectRole, error) {
-	r, err := e.cli.ProjectRole.Create().
+	r, err := e.client(ctx).ProjectRole.Create().
 		SetKey(key).
 		SetName(name).
 		SetOrganisationNodeID(orgNodeID).
@@ -168,7 +178,7 @@ func (e *entRepo) CreateWithEventTypes(ctx context.Context, key, name string, or
 }
 
 func (e *entRepo) GetByID(ctx context.Context, id uuid.UUID) (*role2.ProjectRole, error) {
-	r, err := e.cli.ProjectRole.Query().
+	r, err := e.client(ctx).ProjectRole.Query().
 		Where(
 			entprojectrole.ID(id),
 			entprojectrole.ArchivedAtIsNil(),
@@ -182,7 +192,7 @@ func (e *entRepo) GetByID(ctx context.Context, id uuid.UUID) (*role2.ProjectRole
 }
 
 func (e *entRepo) UpdateAllowedEventTypes(ctx context.Context, id uuid.UUID, eventTypes []string) (*role2.ProjectRole, error) {
-	r, err := e.cli.ProjectRole.UpdateOneID(id).
+	r, err := e.client(ctx).ProjectRole.UpdateOneID(id).
 		SetAllowedEventTypes(eventTypes).
 		Save(ctx)
 	if err != nil {
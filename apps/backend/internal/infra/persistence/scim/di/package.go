@@ -0,0 +1,16 @@
+package di
+
+import (
+	"github.com/SURF-Innovatie/MORIS/ent"
+	scimrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/scim"
+	"github.com/samber/do/v2"
+)
+
+var Package = do.Package(
+	do.Lazy(provideEntRepo),
+)
+
+func provideEntRepo(i do.Injector) (*scimrepo.EntRepo, error) {
+	cli := do.MustInvoke[*ent.Client](i)
+	return scimrepo.NewEntRepo(cli), nil
+}
@@ -0,0 +1,378 @@
+package scim
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/ent"
+	entmembership "github.com/SURF-Innovatie/MORIS/ent/membership"
+	entclosure "github.com/SURF-Innovatie/MORIS/ent/organisationnodeclosure"
+	entperson "github.com/SURF-Innovatie/MORIS/ent/person"
+	"github.com/SURF-Innovatie/MORIS/ent/predicate"
+	entrolescope "github.com/SURF-Innovatie/MORIS/ent/rolescope"
+	entscimtoken "github.com/SURF-Innovatie/MORIS/ent/scimtoken"
+	entscimuser "github.com/SURF-Innovatie/MORIS/ent/scimuser"
+	entuser "github.com/SURF-Innovatie/MORIS/ent/user"
+	"github.com/SURF-Innovatie/MORIS/internal/common/transform"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/organisation/rbac"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/scim"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/persistence/enttx"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+type EntRepo struct {
+	cli *ent.Client
+}
+
+func NewEntRepo(cli *ent.Client) *EntRepo {
+	return &EntRepo{cli: cli}
+}
+
+// client returns the transactional client when ctx carries a transaction
+func (r *EntRepo) client(ctx context.Context) *ent.Client {
+	if tx, ok := enttx.TxFromContext(ctx); ok {
+		return tx.Client()
+	}
+	return r.cli
+}
+
+func (r *EntRepo) CreateToken(ctx context.Context, t scim.Token, hash string) (*scim.Token, error) {
+	row, err := r.client(ctx).ScimToken.Create().
+		SetRootNodeID(t.RootNodeID).
+		SetName(t.Name).
+		SetTokenHash(hash).
+		SetNillableCreatedByUserID(t.CreatedByUserID).
+		Save(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return transform.ToEntityPtr[scim.Token](row), nil
+}
+
+func (r *EntRepo) ListTokens(ctx context.Context, rootNodeID uuid.UUID) ([]scim.Token, error) {
+	rows, err := r.client(ctx).ScimToken.Query().
+		Where(entscimtoken.RootNodeIDEQ(rootNodeID)).
+		Order(ent.Asc(entscimtoken.FieldCreatedAt)).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return transform.ToEntities[scim.Token](rows), nil
+}
+
+func (r *EntRepo) DeleteToken(ctx context.Context, rootNodeID, id uuid.UUID) (bool, error) {
+	n, err := r.client(ctx).ScimToken.Delete().
+		Where(entscimtoken.IDEQ(id), entscimtoken.RootNodeIDEQ(rootNodeID)).
+		Exec(ctx)
+	return n > 0, err
+}
+
+func (r *EntRepo) TokenByHash(ctx context.Context, hash string) (*scim.Token, error) {
+	row, err := r.client(ctx).ScimToken.Query().
+		Where(entscimtoken.TokenHashEQ(hash)).
+		Only(ctx)
+	if ent.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return transform.ToEntityPtr[scim.Token](row), nil
+}
+
+func (r *EntRepo) TouchToken(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return r.client(ctx).ScimToken.UpdateOneID(id).SetLastUsedAt(at).Exec(ctx)
+}
+
+func (r *EntRepo) ListUsers(ctx context.Context, rootNodeID uuid.UUID) ([]scim.User, error) {
+	links, err := r.client(ctx).ScimUser.Query().
+		Where(entscimuser.RootNodeIDEQ(rootNodeID)).
+		Order(ent.Asc(entscimuser.FieldCreatedAt), ent.Asc(entscimuser.FieldID)).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.toUsers(ctx, links)
+}
+
+func (r *EntRepo) FindUsers(ctx context.Context, rootNodeID uuid.UUID, f scim.Filter, offset, limit int) ([]scim.User, int, error) {
+	q := r.client(ctx).ScimUser.Query().Where(entscimuser.RootNodeIDEQ(rootNodeID))
+	if f != nil {
+		p, err := userPredicate(f)
+		if err != nil {
+			return nil, 0, err
+		}
+		q.Where(p)
+	}
+	total, err := q.Clone().Count(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	if limit <= 0 || offset >= total {
+		return []scim.User{}, total, nil
+	}
+	links, err := q.
+		Order(ent.Asc(entscimuser.FieldCreatedAt), ent.Asc(entscimuser.FieldID)).
+		Offset(offset).
+		Limit(limit).
+		All(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	users, err := r.toUsers(ctx, links)
+	return users, total, err
+}
+
+func (r *EntRepo) ExternalIDInUse(ctx context.Context, rootNodeID, userID uuid.UUID, externalID string) (bool, error) {
+	return r.client(ctx).ScimUser.Query().
+		Where(
+			entscimuser.RootNodeIDEQ(rootNodeID),
+			entscimuser.ExternalIDEQ(externalID),
+			entscimuser.UserIDNEQ(userID),
+		).
+		Exist(ctx)
+}
+
+func (r *EntRepo) GetUser(ctx context.Context, rootNodeID, userID uuid.UUID) (*scim.User, error) {
+	link, err := r.client(ctx).ScimUser.Query().
+		Where(entscimuser.RootNodeIDEQ(rootNodeID), entscimuser.UserIDEQ(userID)).
+		Only(ctx)
+	if ent.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	users, err := r.toUsers(ctx, []*ent.ScimUser{link})
+	if err != nil || len(users) == 0 {
+		return nil, err
+	}
+	return &users[0], nil
+}
+
+// toUsers joins the links with their users and people
+func (r *EntRepo) toUsers(ctx context.Context, links []*ent.ScimUser) ([]scim.User, error) {
+	if len(links) == 0 {
+		return []scim.User{}, nil
+	}
+	cli := r.client(ctx)
+
+	users, err := cli.User.Query().
+		Where(entuser.IDIn(lo.Map(links, func(l *ent.ScimUser, _ int) uuid.UUID { return l.UserID })...)).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	people, err := cli.Person.Query().
+		Where(entperson.IDIn(lo.Map(users, func(u *ent.User, _ int) uuid.UUID { return u.PersonID })...)).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userByID := lo.KeyBy(users, func(u *ent.User) uuid.UUID { return u.ID })
+	personByID := lo.KeyBy(people, func(p *ent.Person) uuid.UUID { return p.ID })
+
+	out := make([]scim.User, 0, len(links))
+	for _, l := range links {
+		u, ok := userByID[l.UserID]
+		if !ok {
+			continue
+		}
+		p, ok := personByID[u.PersonID]
+		if !ok {
+			continue
+		}
+		out = append(out, scim.User{
+			ID:          u.ID,
+			PersonID:    p.ID,
+			ExternalID:  l.ExternalID,
+			UserName:    p.Email,
+			DisplayName: p.Name,
+			GivenName:   p.GivenName,
+			FamilyName:  p.FamilyName,
+			Email:       p.Email,
+			Active:      u.IsActive,
+			Created:     l.CreatedAt,
+		})
+	}
+	return out, nil
+}
+
+func (r *EntRepo) LinkedRoot(ctx context.Context, userID uuid.UUID) (*uuid.UUID, error) {
+	link, err := r.client(ctx).ScimUser.Query().
+		Where(entscimuser.UserIDEQ(userID)).
+		Only(ctx)
+	if ent.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &link.RootNodeID, nil
+}
+
+func (r *EntRepo) CreateLink(ctx context.Context, rootNodeID, userID uuid.UUID, externalID *string) error {
+	return r.client(ctx).ScimUser.Create().
+		SetRootNodeID(rootNodeID).
+		SetUserID(userID).
+		SetNillableExternalID(externalID).
+		Exec(ctx)
+}
+
+func (r *EntRepo) SetExternalID(ctx context.Context, userID uuid.UUID, externalID *string) error {
+	upd := r.client(ctx).ScimUser.Update().Where(entscimuser.UserIDEQ(userID))
+	if externalID != nil {
+		upd.SetExternalID(*externalID)
+	} else {
+		upd.ClearExternalID()
+	}
+	_, err := upd.Save(ctx)
+	return err
+}
+
+func (r *EntRepo) DeleteLink(ctx context.Context, userID uuid.UUID) error {
+	_, err := r.client(ctx).ScimUser.Delete().
+		Where(entscimuser.UserIDEQ(userID)).
+		Exec(ctx)
+	return err
+}
+
+func (r *EntRepo) AccountByEmail(ctx context.Context, email string) (*uuid.UUID, *uuid.UUID, error) {
+	cli := r.client(ctx)
+	p, err := cli.Person.Query().
+		Where(entperson.EmailEqualFold(email)).
+		First(ctx)
+	if ent.IsNotFound(err) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	u, err := cli.User.Query().
+		Where(entuser.PersonIDEQ(p.ID)).
+		Only(ctx)
+	if ent.IsNotFound(err) {
+		return &p.ID, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return &p.ID, &u.ID, nil
+}
+
+func (r *EntRepo) HasMembershipUnder(ctx context.Context, rootNodeID, personID uuid.UUID) (bool, error) {
+	cli := r.client(ctx)
+	closure, err := cli.OrganisationNodeClosure.Query().
+		Where(entclosure.AncestorIDEQ(rootNodeID)).
+		All(ctx)
+	if err != nil {
+		return false, err
+	}
+	return cli.Membership.Query().
+		Where(
+			entmembership.PersonIDEQ(personID),
+			entmembership.HasRoleScopeWith(entrolescope.RootNodeIDIn(lo.Map(closure, func(c *ent.OrganisationNodeClosure, _ int) uuid.UUID { return c.DescendantID })...)),
+		).
+		Exist(ctx)
+}
+
+func (r *EntRepo) ListGroups(ctx context.Context, rootNodeID uuid.UUID, at time.Time) ([]scim.Group, error) {
+	return r.groups(ctx, rootNodeID, at)
+}
+
+func (r *EntRepo) GetGroup(ctx context.Context, rootNodeID, id uuid.UUID, at time.Time) (*scim.Group, error) {
+	groups, err := r.groups(ctx, rootNodeID, at, entrolescope.IDEQ(id))
+	if err != nil || len(groups) == 0 {
+		return nil, err
+	}
+	return &groups[0], nil
+}
+
+// groups returns the role scopes in the tree of the root node that match the predicates as groups
+func (r *EntRepo) groups(ctx context.Context, rootNodeID uuid.UUID, at time.Time, where ...predicate.RoleScope) ([]scim.Group, error) {
+	cli := r.client(ctx)
+
+	closure, err := cli.OrganisationNodeClosure.Query().
+		Where(entclosure.AncestorIDEQ(rootNodeID)).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	scopes, err := cli.RoleScope.Query().
+		Where(entrolescope.RootNodeIDIn(lo.Map(closure, func(c *ent.OrganisationNodeClosure, _ int) uuid.UUID { return c.DescendantID })...)).
+		Where(where...).
+		WithRole().
+		WithRootNode().
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(scopes) == 0 {
+		return []scim.Group{}, nil
+	}
+
+	memberships, err := cli.Membership.Query().
+		Where(entmembership.RoleScopeIDIn(lo.Map(scopes, func(s *ent.RoleScope, _ int) uuid.UUID { return s.ID })...)).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	memberships = lo.Filter(memberships, func(m *ent.Membership, _ int) bool {
+		return transform.ToEntityPtr[rbac.Membership](m).ActiveAt(at)
+	})
+
+	// Only the members the root node provisions are listed
+	links, err := cli.ScimUser.Query().
+		Where(
+			entscimuser.RootNodeIDEQ(rootNodeID),
+			entscimuser.HasUserWith(entuser.PersonIDIn(lo.Uniq(lo.Map(memberships, func(m *ent.Membership, _ int) uuid.UUID { return m.PersonID }))...)),
+		).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	users, err := r.toUsers(ctx, links)
+	if err != nil {
+		return nil, err
+	}
+	userByPerson := lo.KeyBy(users, func(u scim.User) uuid.UUID { return u.PersonID })
+
+	members := map[uuid.UUID][]scim.Member{}
+	for _, row := range memberships {
+		u, ok := userByPerson[row.PersonID]
+		if !ok {
+			continue
+		}
+		members[row.RoleScopeID] = append(members[row.RoleScopeID], scim.Member{UserID: u.ID, Display: u.DisplayName})
+	}
+
+	out := make([]scim.Group, 0, len(scopes))
+	for _, s := range scopes {
+		g := scim.Group{
+			ID:          s.ID,
+			DisplayName: s.Edges.RootNode.Name + ": " + s.Edges.Role.DisplayName,
+			NodeID:      s.RootNodeID,
+			RoleKey:     s.Edges.Role.Key,
+			Members:     members[s.ID],
+		}
+		if g.Members == nil {
+			g.Members = []scim.Member{}
+		}
+		sort.Slice(g.Members, func(i, j int) bool { return g.Members[i].Display < g.Members[j].Display })
+		out = append(out, g)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].DisplayName < out[j].DisplayName })
+	return out, nil
+}
+
+func (r *EntRepo) ScopeMemberships(ctx context.Context, scopeID uuid.UUID) ([]rbac.Membership, error) {
+	rows, err := r.client(ctx).Membership.Query().
+		Where(entmembership.RoleScopeIDEQ(scopeID)).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return transform.ToEntities[rbac.Membership](rows), nil
+}
@@ -0,0 +1,219 @@
+package scim
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	entperson "github.com/SURF-Innovatie/MORIS/ent/person"
+	"github.com/SURF-Innovatie/MORIS/ent/predicate"
+	entscimuser "github.com/SURF-Innovatie/MORIS/ent/scimuser"
+	entuser "github.com/SURF-Innovatie/MORIS/ent/user"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/scim"
+	"github.com/google/uuid"
+)
+
+// userPredicate translates a filter on users into a predicate on their links, with the semantics
+// of scim.Filter.Match: strings compare case-insensitively and attributes MORIS does not expose
+// have no values
+func userPredicate(f scim.Filter) (predicate.ScimUser, error) {
+	switch f := f.(type) {
+	case scim.Logical:
+		left, err := userPredicate(f.Left)
+		if err != nil {
+			return nil, err
+		}
+		right, err := userPredicate(f.Right)
+		if err != nil {
+			return nil, err
+		}
+		if f.And {
+			return entscimuser.And(left, right), nil
+		}
+		return entscimuser.Or(left, right), nil
+	case scim.Negation:
+		inner, err := userPredicate(f.Inner)
+		if err != nil {
+			return nil, err
+		}
+		return entscimuser.Not(inner), nil
+	case scim.Comparison:
+		return userComparison(f)
+	default:
+		return nil, fmt.Errorf("%w: unsupported filter", scim.ErrInvalidFilter)
+	}
+}
+
+func userComparison(c scim.Comparison) (predicate.ScimUser, error) {
+	switch c.Path {
+	case "id":
+		return linkColumn(entscimuser.FieldUserID, c, compareUUID)
+	case "externalid":
+		return linkColumn(entscimuser.FieldExternalID, c, compareString)
+	case "username", "emails", "emails.value":
+		return personColumn(entperson.FieldEmail, c)
+	case "displayname", "name.formatted":
+		return personColumn(entperson.FieldName, c)
+	case "name.givenname":
+		return personColumn(entperson.FieldGivenName, c)
+	case "name.familyname":
+		return personColumn(entperson.FieldFamilyName, c)
+	case "active":
+		p, err := compareBool(entuser.FieldIsActive, c)
+		if err != nil {
+			return nil, err
+		}
+		return func(s *sql.Selector) {
+			u := sql.Table(entuser.Table)
+			s.Where(sql.In(s.C(entscimuser.FieldUserID), sql.Select(u.C(entuser.FieldID)).From(u).Where(p(u.C))))
+		}, nil
+	case "meta.created":
+		return linkColumn(entscimuser.FieldCreatedAt, c, compareTime)
+	default:
+		// An attribute without values is only unequal to everything
+		if c.Op == "ne" {
+			return func(s *sql.Selector) { s.Where(sql.Not(sql.False())) }, nil
+		}
+		return func(s *sql.Selector) { s.Where(sql.False()) }, nil
+	}
+}
+
+// columnPredicate is a predicate on a column, given the function that qualifies the column name
+// with its table
+type columnPredicate func(qualify func(string) string) *sql.Predicate
+
+// comparer builds the predicate of a comparison on a column
+type comparer func(col string, c scim.Comparison) (columnPredicate, error)
+
+func linkColumn(col string, c scim.Comparison, compare comparer) (predicate.ScimUser, error) {
+	p, err := compare(col, c)
+	if err != nil {
+		return nil, err
+	}
+	return func(s *sql.Selector) { s.Where(p(s.C)) }, nil
+}
+
+// personColumn compares a column of the person of the linked user
+func personColumn(col string, c scim.Comparison) (predicate.ScimUser, error) {
+	p, err := compareString(col, c)
+	if err != nil {
+		return nil, err
+	}
+	return func(s *sql.Selector) {
+		u, pt := sql.Table(entuser.Table), sql.Table(entperson.Table)
+		s.Where(sql.In(s.C(entscimuser.FieldUserID),
+			sql.Select(u.C(entuser.FieldID)).
+				From(u).
+				Join(pt).On(u.C(entuser.FieldPersonID), pt.C(entperson.FieldID)).
+				Where(p(pt.C)),
+		))
+	}, nil
+}
+
+// compareString compares a nullable text column case-insensitively; NULL has no values
+func compareString(col string, c scim.Comparison) (columnPredicate, error) {
+	return func(qualify func(string) string) *sql.Predicate {
+		col := qualify(col)
+		switch c.Op {
+		case "pr":
+			return sql.NotNull(col)
+		case "eq":
+			return sql.And(sql.NotNull(col), sql.EqualFold(col, c.Value))
+		case "ne":
+			return sql.Or(sql.IsNull(col), sql.Not(sql.EqualFold(col, c.Value)))
+		case "co":
+			return sql.And(sql.NotNull(col), sql.ContainsFold(col, c.Value))
+		case "sw":
+			return sql.And(sql.NotNull(col), sql.HasPrefixFold(col, c.Value))
+		case "ew":
+			return sql.And(sql.NotNull(col), sql.HasSuffixFold(col, c.Value))
+		}
+		op := map[string]sql.Op{"gt": sql.OpGT, "ge": sql.OpGTE, "lt": sql.OpLT, "le": sql.OpLTE}[c.Op]
+		return sql.And(sql.NotNull(col), sql.P(func(b *sql.Builder) {
+			b.WriteString("LOWER(").Ident(col).WriteString(")").WriteOp(op).Arg(strings.ToLower(c.Value))
+		}))
+	}, nil
+}
+
+// compareUUID compares a UUID column; only equality is meaningful
+func compareUUID(col string, c scim.Comparison) (columnPredicate, error) {
+	id, err := uuid.Parse(c.Value)
+	switch c.Op {
+	case "pr":
+		return func(qualify func(string) string) *sql.Predicate { return sql.NotNull(qualify(col)) }, nil
+	case "eq", "ne":
+	default:
+		return nil, fmt.Errorf("%w: %s only supports eq, ne and pr", scim.ErrInvalidFilter, c.Path)
+	}
+	return func(qualify func(string) string) *sql.Predicate {
+		switch {
+		case err != nil && c.Op == "eq":
+			return sql.False()
+		case err != nil:
+			return sql.Not(sql.False())
+		case c.Op == "eq":
+			return sql.EQ(qualify(col), id)
+		default:
+			return sql.NEQ(qualify(col), id)
+		}
+	}, nil
+}
+
+// compareBool compares a boolean column with "true" or "false"
+func compareBool(col string, c scim.Comparison) (columnPredicate, error) {
+	switch c.Op {
+	case "pr":
+		return func(qualify func(string) string) *sql.Predicate { return sql.NotNull(qualify(col)) }, nil
+	case "eq", "ne":
+	default:
+		return nil, fmt.Errorf("%w: %s only supports eq, ne and pr", scim.ErrInvalidFilter, c.Path)
+	}
+	value := strings.ToLower(c.Value)
+	return func(qualify func(string) string) *sql.Predicate {
+		switch {
+		case value != "true" && value != "false" && c.Op == "eq":
+			return sql.False()
+		case value != "true" && value != "false":
+			return sql.Not(sql.False())
+		case c.Op == "eq":
+			return sql.EQ(qualify(col), value == "true")
+		default:
+			return sql.NEQ(qualify(col), value == "true")
+		}
+	}, nil
+}
+
+// compareTime compares a timestamp column with an RFC 3339 value at the precision of a second,
+// which is how meta.created is rendered
+func compareTime(col string, c scim.Comparison) (columnPredicate, error) {
+	switch c.Op {
+	case "pr":
+		return func(qualify func(string) string) *sql.Predicate { return sql.NotNull(qualify(col)) }, nil
+	case "co", "sw", "ew":
+		return nil, fmt.Errorf("%w: %s does not support %s", scim.ErrInvalidFilter, c.Path, c.Op)
+	}
+	at, err := time.Parse(time.RFC3339, c.Value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s must be an RFC 3339 date-time", scim.ErrInvalidFilter, c.Path)
+	}
+	from := at.Truncate(time.Second)
+	next := from.Add(time.Second)
+	return func(qualify func(string) string) *sql.Predicate {
+		col := qualify(col)
+		switch c.Op {
+		case "eq":
+			return sql.And(sql.GTE(col, from), sql.LT(col, next))
+		case "ne":
+			return sql.Or(sql.LT(col, from), sql.GTE(col, next))
+		case "gt":
+			return sql.GTE(col, next)
+		case "ge":
+			return sql.GTE(col, from)
+		case "lt":
+			return sql.LT(col, from)
+		default: // le
+			return sql.LT(col, next)
+		}
+	}, nil
+}
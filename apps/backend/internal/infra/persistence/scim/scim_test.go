@@ -0,0 +1,342 @@
+package scim_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/SURF-Innovatie/MORIS/ent"
+	"github.com/SURF-Innovatie/MORIS/ent/enttest"
+	entmembership "github.com/SURF-Innovatie/MORIS/ent/membership"
+	entscimuser "github.com/SURF-Innovatie/MORIS/ent/scimuser"
+	appaudit "github.com/SURF-Innovatie/MORIS/internal/app/audit"
+	orgsvc "github.com/SURF-Innovatie/MORIS/internal/app/organisation"
+	"github.com/SURF-Innovatie/MORIS/internal/app/organisation/role"
+	"github.com/SURF-Innovatie/MORIS/internal/app/person"
+	appscim "github.com/SURF-Innovatie/MORIS/internal/app/scim"
+	"github.com/SURF-Innovatie/MORIS/internal/app/user"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/identity"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/scim"
+	auditrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/audit"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/persistence/enttx"
+	orgrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/organisation"
+	rolerepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/organisation/role"
+	personrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/person"
+	scimrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/scim"
+	userrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/user"
+	"github.com/google/uuid"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/samber/lo"
+)
+
+// noUser makes changes appear as made by the identity management system, which has no user
+type noUser struct{}
+
+func (noUser) Current(context.Context) (identity.Principal, error) {
+	return identity.Principal{}, errors.New("unauthenticated")
+}
+
+type noCache struct{}
+
+func (noCache) GetUser(context.Context, uuid.UUID) (*identity.User, error) {
+	return nil, errors.New("miss")
+}
+func (noCache) SetUser(context.Context, *identity.User) error { return nil }
+func (noCache) DeleteUser(context.Context, uuid.UUID) error   { return nil }
+func (noCache) ProjectIDsForPerson(context.Context, uuid.UUID) ([]uuid.UUID, error) {
+	return nil, nil
+}
+func (noCache) PersonIDsForProjects(context.Context, []uuid.UUID) ([]uuid.UUID, error) {
+	return nil, nil
+}
+
+type fixture struct {
+	cli     *ent.Client
+	svc     appscim.Service
+	rootID  uuid.UUID
+	childID uuid.UUID
+	staff   uuid.UUID // role scope on the child node
+}
+
+func setup(t *testing.T) fixture {
+	t.Helper()
+	ctx := context.Background()
+	cli := enttest.Open(t, "sqlite3", "file:scim_"+uuid.NewString()+"?mode=memory&cache=shared&_fk=1")
+	t.Cleanup(func() { _ = cli.Close() })
+
+	root := cli.OrganisationNode.Create().SetName("University").SaveX(ctx)
+	child := cli.OrganisationNode.Create().SetName("Faculty").SetParentID(root.ID).SaveX(ctx)
+	cli.OrganisationNodeClosure.Create().SetAncestorID(root.ID).SetDescendantID(root.ID).SetDepth(0).ExecX(ctx)
+	cli.OrganisationNodeClosure.Create().SetAncestorID(child.ID).SetDescendantID(child.ID).SetDepth(0).ExecX(ctx)
+	cli.OrganisationNodeClosure.Create().SetAncestorID(root.ID).SetDescendantID(child.ID).SetDepth(1).ExecX(ctx)
+
+	staffRole := cli.OrganisationRole.Create().SetOrganisationNodeID(child.ID).SetKey("staff").SetDisplayName("Staff").SetPermissions([]string{}).SaveX(ctx)
+	staff := cli.RoleScope.Create().SetRoleID(staffRole.ID).SetRootNodeID(child.ID).SaveX(ctx)
+
+	tx := enttx.NewManager(cli)
+	people := person.NewService(personrepo.NewEntRepo(cli))
+	auditSvc := appaudit.NewService(auditrepo.NewEntRepo(cli), noUser{})
+	svc := appscim.NewService(
+		scimrepo.NewEntRepo(cli),
+		user.NewService(userrepo.NewEntRepo(cli), people, noCache{}, noCache{}),
+		people,
+		role.NewService(rolerepo.NewEntRepo(cli), auditSvc, tx),
+		orgsvc.NewService(orgrepo.NewEntRepo(cli), nil, nil, tx),
+		noUser{},
+		tx,
+	)
+	return fixture{cli: cli, svc: svc, rootID: root.ID, childID: child.ID, staff: staff.ID}
+}
+
+func patch(t *testing.T, op, path string, value any) scim.PatchOperation {
+	t.Helper()
+	raw, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return scim.PatchOperation{Op: op, Path: path, Value: raw}
+}
+
+func TestTokens_AreScopedToRootNodes(t *testing.T) {
+	ctx := context.Background()
+	f := setup(t)
+
+	if _, _, err := f.svc.CreateToken(ctx, f.childID, "IdM"); !errors.Is(err, scim.ErrNotRootNode) {
+		t.Fatalf("expected a token for a child node to be refused, got %v", err)
+	}
+	tok, secret, err := f.svc.CreateToken(ctx, f.rootID, "IdM")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := f.svc.Authenticate(ctx, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != tok.ID || got.RootNodeID != f.rootID || got.LastUsedAt == nil {
+		t.Fatalf("expected the token of the root node with its last use, got %+v", got)
+	}
+	if _, err := f.svc.Authenticate(ctx, secret+"x"); !errors.Is(err, scim.ErrUnauthorized) {
+		t.Fatalf("expected an unknown secret to be refused, got %v", err)
+	}
+
+	if err := f.svc.DeleteToken(ctx, f.rootID, tok.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.svc.Authenticate(ctx, secret); !errors.Is(err, scim.ErrUnauthorized) {
+		t.Fatalf("expected a revoked token to be refused, got %v", err)
+	}
+}
+
+func TestUsers_ProvisionFilterPatchAndDeprovision(t *testing.T) {
+	ctx := context.Background()
+	f := setup(t)
+
+	created, err := f.svc.CreateUser(ctx, f.rootID, scim.User{
+		UserName:   "ada@example.org",
+		GivenName:  lo.ToPtr("Ada"),
+		FamilyName: lo.ToPtr("Lovelace"),
+		ExternalID: lo.ToPtr("e-1"),
+		Active:     true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.DisplayName != "Ada Lovelace" || !created.Active || created.Email != "ada@example.org" {
+		t.Fatalf("unexpected user %+v", created)
+	}
+
+	// A person who already belongs to the organisation is adopted
+	existing := f.cli.Person.Create().SetName("Grace Hopper").SetEmail("grace@example.org").SaveX(ctx)
+	f.cli.User.Create().SetPersonID(existing.ID).ExecX(ctx)
+	f.cli.Membership.Create().SetPersonID(existing.ID).SetRoleScopeID(f.staff).ExecX(ctx)
+	grace, err := f.svc.CreateUser(ctx, f.rootID, scim.User{UserName: "Grace@example.org", DisplayName: "Grace Hopper", Active: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if grace.PersonID != existing.ID {
+		t.Fatalf("expected the existing person to be adopted, got %+v", grace)
+	}
+
+	if _, err := f.svc.CreateUser(ctx, f.rootID, scim.User{UserName: "other@example.org", ExternalID: lo.ToPtr("e-1")}); !errors.Is(err, scim.ErrUniqueness) {
+		t.Fatalf("expected a duplicate externalId to be refused, got %v", err)
+	}
+
+	page, err := f.svc.ListUsers(ctx, f.rootID, appscim.ListQuery{Filter: `userName eq "ADA@example.org" or externalId eq "nope"`, StartIndex: 1, Count: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.TotalResults != 1 || page.Resources[0].ID != created.ID {
+		t.Fatalf("expected the filter to find ada, got %+v", page)
+	}
+	if _, err := f.svc.ListUsers(ctx, f.rootID, appscim.ListQuery{Filter: `userName zz "x"`}); !errors.Is(err, scim.ErrInvalidFilter) {
+		t.Fatalf("expected an invalid filter, got %v", err)
+	}
+
+	patched, err := f.svc.PatchUser(ctx, f.rootID, created.ID, []scim.PatchOperation{
+		patch(t, "Replace", "active", "False"),
+		patch(t, "replace", "name.givenName", "Augusta Ada"),
+		patch(t, "add", "phoneNumbers", []string{"ignored"}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if patched.Active || lo.FromPtr(patched.GivenName) != "Augusta Ada" {
+		t.Fatalf("expected a deactivated user with a new given name, got %+v", patched)
+	}
+	if f.cli.User.GetX(ctx, created.ID).IsActive {
+		t.Fatalf("the account should be deactivated")
+	}
+
+	if _, err := f.svc.PatchUser(ctx, f.rootID, created.ID, []scim.PatchOperation{patch(t, "replace", "userName", "grace@example.org")}); !errors.Is(err, scim.ErrUniqueness) {
+		t.Fatalf("expected the address of another person to be refused, got %v", err)
+	}
+
+	if err := f.svc.DeleteUser(ctx, f.rootID, grace.ID); err != nil {
+		t.Fatal(err)
+	}
+	if f.cli.User.GetX(ctx, grace.ID).IsActive {
+		t.Fatalf("a deprovisioned account should be inactive")
+	}
+	if f.cli.ScimUser.Query().Where(entscimuser.UserIDEQ(grace.ID)).ExistX(ctx) {
+		t.Fatalf("a deprovisioned user should no longer be linked")
+	}
+	if _, err := f.svc.GetUser(ctx, f.rootID, grace.ID); !errors.Is(err, scim.ErrNotFound) {
+		t.Fatalf("expected a deprovisioned user to be gone, got %v", err)
+	}
+}
+
+func TestUsers_FilterAndPaginateInTheDatabase(t *testing.T) {
+	ctx := context.Background()
+	f := setup(t)
+
+	var ids []uuid.UUID
+	for _, u := range []scim.User{
+		{UserName: "ada@example.org", DisplayName: "Ada Lovelace", FamilyName: lo.ToPtr("Lovelace"), ExternalID: lo.ToPtr("e-1"), Active: true},
+		{UserName: "grace@example.org", DisplayName: "Grace Hopper", FamilyName: lo.ToPtr("Hopper"), Active: true},
+		{UserName: "alan@example.net", DisplayName: "Alan Turing", ExternalID: lo.ToPtr("e-3"), Active: false},
+	} {
+		created, err := f.svc.CreateUser(ctx, f.rootID, u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, created.ID)
+	}
+
+	for _, tc := range []struct {
+		filter string
+		want   []uuid.UUID
+	}{
+		{`userName ew "@EXAMPLE.ORG"`, ids[:2]},
+		{`displayName co "o" and active eq true`, ids[:2]},
+		{`externalId pr`, []uuid.UUID{ids[0], ids[2]}},
+		{`externalId ne "e-1"`, ids[1:]},
+		{`not (name.familyName sw "l")`, ids[1:]},
+		{`name.givenName pr or id eq "` + ids[2].String() + `"`, ids[2:]},
+		{`emails.value gt "b" and userName lt "h"`, ids[1:2]},
+		{`phoneNumbers eq "1" or title ne "x"`, ids},
+	} {
+		page, err := f.svc.ListUsers(ctx, f.rootID, appscim.ListQuery{Filter: tc.filter, StartIndex: 1, Count: 10})
+		if err != nil {
+			t.Fatalf("%s: %v", tc.filter, err)
+		}
+		got := lo.Map(page.Resources, func(u scim.User, _ int) uuid.UUID { return u.ID })
+		if page.TotalResults != len(tc.want) || !slices.Equal(got, tc.want) {
+			t.Fatalf("%s: expected %v, got %v (%d)", tc.filter, tc.want, got, page.TotalResults)
+		}
+	}
+
+	page, err := f.svc.ListUsers(ctx, f.rootID, appscim.ListQuery{StartIndex: 2, Count: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.TotalResults != 3 || page.StartIndex != 2 || len(page.Resources) != 1 || page.Resources[0].ID != ids[1] {
+		t.Fatalf("expected the second user on a page of one, got %+v", page)
+	}
+	if _, err := f.svc.ListUsers(ctx, f.rootID, appscim.ListQuery{Filter: `active co "t"`, Count: 1}); !errors.Is(err, scim.ErrInvalidFilter) {
+		t.Fatalf("expected a substring match on a boolean to be refused, got %v", err)
+	}
+}
+
+func TestUsers_DoNotAdoptAccountsOutsideTheOrganisation(t *testing.T) {
+	ctx := context.Background()
+	f := setup(t)
+
+	// An administrator with a membership in the organisation is still not adopted
+	admin := f.cli.Person.Create().SetName("Admin").SetEmail("admin@example.org").SaveX(ctx)
+	f.cli.User.Create().SetPersonID(admin.ID).SetIsSysAdmin(true).ExecX(ctx)
+	f.cli.Membership.Create().SetPersonID(admin.ID).SetRoleScopeID(f.staff).ExecX(ctx)
+
+	// A member of another institution
+	other := f.cli.OrganisationNode.Create().SetName("Other University").SaveX(ctx)
+	f.cli.OrganisationNodeClosure.Create().SetAncestorID(other.ID).SetDescendantID(other.ID).SetDepth(0).ExecX(ctx)
+	otherRole := f.cli.OrganisationRole.Create().SetOrganisationNodeID(other.ID).SetKey("staff").SetDisplayName("Staff").SetPermissions([]string{}).SaveX(ctx)
+	otherScope := f.cli.RoleScope.Create().SetRoleID(otherRole.ID).SetRootNodeID(other.ID).SaveX(ctx)
+	alan := f.cli.Person.Create().SetName("Alan Turing").SetEmail("alan@example.org").SaveX(ctx)
+	f.cli.User.Create().SetPersonID(alan.ID).ExecX(ctx)
+	f.cli.Membership.Create().SetPersonID(alan.ID).SetRoleScopeID(otherScope.ID).ExecX(ctx)
+
+	for _, email := range []string{"admin@example.org", "alan@example.org"} {
+		if _, err := f.svc.CreateUser(ctx, f.rootID, scim.User{UserName: email, DisplayName: "Taken Over", Active: false}); !errors.Is(err, scim.ErrUniqueness) {
+			t.Fatalf("expected %s not to be adopted, got %v", email, err)
+		}
+	}
+	if f.cli.Person.GetX(ctx, alan.ID).Name != "Alan Turing" {
+		t.Fatalf("the other institution's person should be untouched")
+	}
+	if f.cli.ScimUser.Query().CountX(ctx) != 0 {
+		t.Fatalf("no account should be linked")
+	}
+}
+
+func TestGroups_PatchMembersOfProvisionedUsers(t *testing.T) {
+	ctx := context.Background()
+	f := setup(t)
+
+	ada, err := f.svc.CreateUser(ctx, f.rootID, scim.User{UserName: "ada@example.org", DisplayName: "Ada", Active: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Memberships of people the root node does not provision are left alone
+	outsider := f.cli.Person.Create().SetName("Outsider").SetEmail("outsider@example.org").SaveX(ctx)
+	f.cli.Membership.Create().SetPersonID(outsider.ID).SetRoleScopeID(f.staff).ExecX(ctx)
+
+	page, err := f.svc.ListGroups(ctx, f.rootID, appscim.ListQuery{Filter: `displayName eq "Faculty: Staff"`, StartIndex: 1, Count: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.TotalResults != 1 || page.Resources[0].ID != f.staff || len(page.Resources[0].Members) != 0 {
+		t.Fatalf("expected the staff group without provisioned members, got %+v", page)
+	}
+
+	g, err := f.svc.PatchGroup(ctx, f.rootID, f.staff, []scim.PatchOperation{
+		patch(t, "add", "members", []map[string]string{{"value": ada.ID.String()}}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Members) != 1 || g.Members[0].UserID != ada.ID {
+		t.Fatalf("expected ada to be a member, got %+v", g.Members)
+	}
+
+	if _, err := f.svc.PatchGroup(ctx, f.rootID, f.staff, []scim.PatchOperation{
+		patch(t, "add", "members", []map[string]string{{"value": uuid.NewString()}}),
+	}); !errors.Is(err, scim.ErrInvalidValue) {
+		t.Fatalf("expected an unknown member to be refused, got %v", err)
+	}
+
+	g, err = f.svc.PatchGroup(ctx, f.rootID, f.staff, []scim.PatchOperation{
+		{Op: "remove", Path: `members[value eq "` + ada.ID.String() + `"]`},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Members) != 0 {
+		t.Fatalf("expected ada to be removed, got %+v", g.Members)
+	}
+	if n := f.cli.Membership.Query().Where(entmembership.RoleScopeIDEQ(f.staff)).CountX(ctx); n != 1 {
+		t.Fatalf("expected only the outsider's membership to remain, got %d", n)
+	}
+}
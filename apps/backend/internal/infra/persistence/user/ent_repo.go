@@ -7,6 +7,8 @@ import (
 	entuser "github.com/SURF-Innovatie/MORIS/ent/user"
 	"github.com/SURF-Innovatie/MORIS/internal/common/transform"
 	"github.com/SURF-Innovatie/MORIS/internal/domain/identity"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/locale"
+	"github.com/SURF-Innovatie/MORIS/internal/infra/persistence/enttx"
 	"github.com/google/uuid"
 )
 
@@ -18,8 +20,16 @@ func NewEntRepo(cli *ent.Client) *EntRepo {
 	return &EntRepo{cli: cli}
 }
 
+// client returns the transactional client when ctx carries a transaction
+func (r *EntRepo) client(ctx context.Context) *ent.Client {
+	if tx, ok := enttx.TxFromContext(ctx); ok {
+		return tx.Client()
+	}
+	return r.cli
+}
+
 func (r *EntRepo) Get(ctx context.Context, id uuid.UUID) (*identity.User, error) {
-	row, err := r.cli.User.Query().Where(entuser.IDEQ(id)).Only(ctx)
+	row, err := r.client(ctx).User.Query().Where(entuser.IDEQ(id)).Only(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -27,7 +37,7 @@ func (r *EntRepo) Get(ctx context.Context, id uuid.UUID) (*identity.User, error)
 }
 
 func (r *EntRepo) GetByPersonID(ctx context.Context, personID uuid.UUID) (*identity.User, error) {
-	row, err := r.cli.User.Query().Where(entuser.PersonIDEQ(personID)).Only(ctx)
+	row, err := r.client(ctx).User.Query().Where(entuser.PersonIDEQ(personID)).Only(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -35,9 +45,14 @@ func (r *EntRepo) GetByPersonID(ctx context.Context, personID uuid.UUID) (*ident
 }
 
 func (r *EntRepo) Create(ctx context.Context, u identity.User) (*identity.User, error) {
-	builder := r.cli.User.Create().
+	builder := r.client(ctx).User.Create().
 		SetPersonID(u.PersonID).
-		SetIsSysAdmin(u.IsSysAdmin)
+		SetIsSysAdmin(u.IsSysAdmin).
+		SetIsServiceAccount(u.IsServiceAccount)
+
+	if u.Language != "" {
+		builder.SetLanguage(entuser.Language(u.Language))
+	}
 
 	// Only set password if provided (OAuth-only users don't have passwords)
 	if u.Password != "" {
@@ -52,7 +67,7 @@ func (r *EntRepo) Create(ctx context.Context, u identity.User) (*identity.User,
 }
 
 func (r *EntRepo) Update(ctx context.Context, id uuid.UUID, u identity.User) (*identity.User, error) {
-	row, err := r.cli.User.UpdateOneID(id).
+	row, err := r.client(ctx).User.UpdateOneID(id).
 		SetPersonID(u.PersonID).
 		SetPassword(u.Password).
 		SetIsSysAdmin(u.IsSysAdmin).
@@ -64,19 +79,19 @@ func (r *EntRepo) Update(ctx context.Context, id uuid.UUID, u identity.User) (*i
 }
 
 func (r *EntRepo) Delete(ctx context.Context, id uuid.UUID) error {
-	return r.cli.User.DeleteOneID(id).Exec(ctx)
+	return r.client(ctx).User.DeleteOneID(id).Exec(ctx)
 }
 
 func (r *EntRepo) ToggleActive(ctx context.Context, id uuid.UUID, isActive bool) error {
-	return r.cli.User.UpdateOneID(id).SetIsActive(isActive).Exec(ctx)
+	return r.client(ctx).User.UpdateOneID(id).SetIsActive(isActive).Exec(ctx)
 }
 
 func (r *EntRepo) ListUsers(ctx context.Context, limit, offset int) ([]identity.User, int, error) {
-	total, err := r.cli.User.Query().Count(ctx)
+	total, err := r.client(ctx).User.Query().Count(ctx)
 	if err != nil {
 		return nil, 0, err
 	}
-	rows, err := r.cli.User.Query().Limit(limit).Offset(offset).All(ctx)
+	rows, err := r.client(ctx).User.Query().Limit(limit).Offset(offset).All(ctx)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -85,7 +100,7 @@ func (r *EntRepo) ListUsers(ctx context.Context, limit, offset int) ([]identity.
 }
 
 func (r *EntRepo) SetZenodoTokens(ctx context.Context, userID uuid.UUID, access, refresh string) error {
-	upd := r.cli.User.UpdateOneID(userID).SetZenodoAccessToken(access)
+	upd := r.client(ctx).User.UpdateOneID(userID).SetZenodoAccessToken(access)
 	if refresh != "" {
 		upd = upd.SetZenodoRefreshToken(refresh)
 	}
@@ -94,9 +109,28 @@ func (r *EntRepo) SetZenodoTokens(ctx context.Context, userID uuid.UUID, access,
 }
 
 func (r *EntRepo) ClearZenodoTokens(ctx context.Context, userID uuid.UUID) error {
-	_, err := r.cli.User.UpdateOneID(userID).
+	_, err := r.client(ctx).User.UpdateOneID(userID).
 		ClearZenodoAccessToken().
 		ClearZenodoRefreshToken().
 		Save(ctx)
 	return err
 }
+
+func (r *EntRepo) SetLanguage(ctx context.Context, userID uuid.UUID, lang locale.Language) error {
+	return r.client(ctx).User.UpdateOneID(userID).SetLanguage(entuser.Language(lang)).Exec(ctx)
+}
+
+func (r *EntRepo) LanguagesByIDs(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]locale.Language, error) {
+	rows, err := r.client(ctx).User.Query().
+		Where(entuser.IDIn(userIDs...)).
+		Select(entuser.FieldID, entuser.FieldLanguage).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[uuid.UUID]locale.Language, len(rows))
+	for _, row := range rows {
+		out[row.ID] = locale.Language(row.Language)
+	}
+	return out, nil
+}
@@ -0,0 +1,22 @@
+package di
+
+import (
+	"github.com/SURF-Innovatie/MORIS/ent"
+	webhookrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/webhook"
+	"github.com/samber/do/v2"
+)
+
+var Package = do.Package(
+	do.Lazy(provideWebhookDeliveryRepo),
+	do.Lazy(provideWebhookSubscriptionRepo),
+)
+
+func provideWebhookDeliveryRepo(i do.Injector) (*webhookrepo.EntRepo, error) {
+	cli := do.MustInvoke[*ent.Client](i)
+	return webhookrepo.NewEntRepo(cli), nil
+}
+
+func provideWebhookSubscriptionRepo(i do.Injector) (*webhookrepo.EntSubscriptionRepo, error) {
+	cli := do.MustInvoke[*ent.Client](i)
+	return webhookrepo.NewEntSubscriptionRepo(cli), nil
+}
@@ -0,0 +1,142 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/ent"
+	"github.com/SURF-Innovatie/MORIS/ent/predicate"
+	entwebhookdelivery "github.com/SURF-Innovatie/MORIS/ent/webhookdelivery"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/webhook"
+	"github.com/google/uuid"
+)
+
+type EntRepo struct {
+	cli *ent.Client
+}
+
+func NewEntRepo(cli *ent.Client) *EntRepo {
+	return &EntRepo{cli: cli}
+}
+
+func (r *EntRepo) Create(ctx context.Context, d webhook.Delivery) (*webhook.Delivery, error) {
+	row, err := r.cli.WebhookDelivery.Create().
+		SetID(d.ID).
+		SetNillablePolicyID(d.PolicyID).
+		SetNillableSubscriptionID(d.SubscriptionID).
+		SetNillableEventID(d.EventID).
+		SetEventType(d.EventType).
+		SetURL(d.URL).
+		SetPayload(string(d.Payload)).
+		SetStatus(entwebhookdelivery.Status(d.Status)).
+		SetNextAttemptAt(d.NextAttemptAt).
+		Save(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return (&webhook.Delivery{}).FromEnt(row), nil
+}
+
+func (r *EntRepo) Get(ctx context.Context, id uuid.UUID) (*webhook.Delivery, error) {
+	row, err := r.cli.WebhookDelivery.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return (&webhook.Delivery{}).FromEnt(row), nil
+}
+
+// ClaimDue leases due deliveries by moving their next attempt to the end of the lease. Each lease is a
+// conditional update, so a delivery claimed by another worker in the meantime is skipped rather than sent twice.
+func (r *EntRepo) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]webhook.Delivery, error) {
+	due := []predicate.WebhookDelivery{
+		entwebhookdelivery.StatusEQ(entwebhookdelivery.StatusPending),
+		entwebhookdelivery.NextAttemptAtLTE(now),
+	}
+	rows, err := r.cli.WebhookDelivery.Query().
+		Where(due...).
+		Order(ent.Asc(entwebhookdelivery.FieldNextAttemptAt)).
+		Limit(limit).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claimed := make([]*ent.WebhookDelivery, 0, len(rows))
+	for _, row := range rows {
+		n, err := r.cli.WebhookDelivery.Update().
+			Where(entwebhookdelivery.IDEQ(row.ID)).
+			Where(due...).
+			SetNextAttemptAt(now.Add(lease)).
+			Save(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if n == 1 {
+			claimed = append(claimed, row)
+		}
+	}
+	return toDeliveries(claimed), nil
+}
+
+func (r *EntRepo) RecordAttempt(ctx context.Context, id uuid.UUID, result webhook.AttemptResult) error {
+	upd := r.cli.WebhookDelivery.UpdateOneID(id).
+		AddAttempts(1).
+		SetStatus(entwebhookdelivery.Status(result.Status)).
+		SetLastAttemptAt(result.At).
+		SetNextAttemptAt(result.NextAttemptAt)
+	if result.StatusCode != nil {
+		upd.SetLastStatusCode(*result.StatusCode)
+	} else {
+		upd.ClearLastStatusCode()
+	}
+	if result.Error != nil {
+		upd.SetLastError(*result.Error)
+	} else {
+		upd.ClearLastError()
+	}
+	return upd.Exec(ctx)
+}
+
+func (r *EntRepo) Requeue(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return r.cli.WebhookDelivery.UpdateOneID(id).
+		SetStatus(entwebhookdelivery.StatusPending).
+		SetAttempts(0).
+		SetNextAttemptAt(at).
+		Exec(ctx)
+}
+
+func (r *EntRepo) ListForPolicy(ctx context.Context, policyID uuid.UUID, status *webhook.DeliveryStatus) ([]webhook.Delivery, error) {
+	q := r.cli.WebhookDelivery.Query().
+		Where(entwebhookdelivery.PolicyIDEQ(policyID))
+	if status != nil {
+		q.Where(entwebhookdelivery.StatusEQ(entwebhookdelivery.Status(*status)))
+	}
+
+	rows, err := q.Order(ent.Desc(entwebhookdelivery.FieldCreatedAt)).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return toDeliveries(rows), nil
+}
+
+func (r *EntRepo) ListForSubscription(ctx context.Context, subscriptionID uuid.UUID, status *webhook.DeliveryStatus) ([]webhook.Delivery, error) {
+	q := r.cli.WebhookDelivery.Query().
+		Where(entwebhookdelivery.SubscriptionIDEQ(subscriptionID))
+	if status != nil {
+		q.Where(entwebhookdelivery.StatusEQ(entwebhookdelivery.Status(*status)))
+	}
+
+	rows, err := q.Order(ent.Desc(entwebhookdelivery.FieldCreatedAt)).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return toDeliveries(rows), nil
+}
+
+func toDeliveries(rows []*ent.WebhookDelivery) []webhook.Delivery {
+	out := make([]webhook.Delivery, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, *(&webhook.Delivery{}).FromEnt(row))
+	}
+	return out
+}
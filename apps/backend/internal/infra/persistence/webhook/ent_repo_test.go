@@ -0,0 +1,73 @@
+package webhook_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/ent/enttest"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/webhook"
+	webhookrepo "github.com/SURF-Innovatie/MORIS/internal/infra/persistence/webhook"
+	"github.com/google/uuid"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestEntRepo_ClaimDueLeasesAndRecordAttemptClearsErrors(t *testing.T) {
+	ctx := context.Background()
+	cli := enttest.Open(t, "sqlite3", "file:webhook_"+uuid.NewString()+"?mode=memory&cache=shared&_fk=1")
+	defer cli.Close()
+
+	repo := webhookrepo.NewEntRepo(cli)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	d, err := repo.Create(ctx, webhook.Delivery{
+		ID:            uuid.New(),
+		EventType:     "project.started",
+		URL:           "https://example.org/hook",
+		Payload:       []byte(`{}`),
+		Status:        webhook.DeliveryPending,
+		NextAttemptAt: now,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claimed, err := repo.ClaimDue(ctx, now, time.Minute, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(claimed) != 1 || claimed[0].ID != d.ID {
+		t.Fatalf("expected the delivery to be claimed, got %+v", claimed)
+	}
+
+	// A second worker does not get the leased delivery, until the lease expires
+	if again, err := repo.ClaimDue(ctx, now, time.Minute, 10); err != nil || len(again) != 0 {
+		t.Fatalf("expected no deliveries during the lease, got %d (%v)", len(again), err)
+	}
+	if again, err := repo.ClaimDue(ctx, now.Add(2*time.Minute), time.Minute, 10); err != nil || len(again) != 1 {
+		t.Fatalf("expected the delivery after the lease expired, got %d (%v)", len(again), err)
+	}
+
+	code, msg := 503, "unexpected status code 503"
+	if err := repo.RecordAttempt(ctx, d.ID, webhook.AttemptResult{
+		At: now, StatusCode: &code, Error: &msg, Status: webhook.DeliveryPending, NextAttemptAt: now.Add(time.Minute),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.RecordAttempt(ctx, d.ID, webhook.AttemptResult{
+		At: now.Add(time.Minute), Status: webhook.DeliverySucceeded, NextAttemptAt: now.Add(time.Minute),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := repo.Get(ctx, d.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != webhook.DeliverySucceeded || got.Attempts != 2 {
+		t.Fatalf("unexpected delivery %+v", got)
+	}
+	if got.LastError != nil || got.LastStatusCode != nil {
+		t.Fatalf("expected the earlier error to be cleared, got %v / %v", got.LastError, got.LastStatusCode)
+	}
+}
@@ -0,0 +1,124 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/SURF-Innovatie/MORIS/ent"
+	entwebhookdelivery "github.com/SURF-Innovatie/MORIS/ent/webhookdelivery"
+	entwebhooksubscription "github.com/SURF-Innovatie/MORIS/ent/webhooksubscription"
+	"github.com/SURF-Innovatie/MORIS/internal/domain/webhook"
+	"github.com/google/uuid"
+)
+
+type EntSubscriptionRepo struct {
+	cli *ent.Client
+}
+
+func NewEntSubscriptionRepo(cli *ent.Client) *EntSubscriptionRepo {
+	return &EntSubscriptionRepo{cli: cli}
+}
+
+func (r *EntSubscriptionRepo) Create(ctx context.Context, s webhook.Subscription) (*webhook.Subscription, error) {
+	row, err := r.cli.WebhookSubscription.Create().
+		SetID(s.ID).
+		SetName(s.Name).
+		SetOrgNodeID(s.OrgNodeID).
+		SetIncludeDescendants(s.IncludeDescendants).
+		SetEventTypes(s.EventTypes).
+		SetURL(s.URL).
+		SetSecret(s.Secret).
+		SetSecretRotatedAt(s.SecretRotatedAt).
+		SetEnabled(s.Enabled).
+		SetNillableCreatedBy(s.CreatedBy).
+		Save(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return (&webhook.Subscription{}).FromEnt(row), nil
+}
+
+func (r *EntSubscriptionRepo) Get(ctx context.Context, id uuid.UUID) (*webhook.Subscription, error) {
+	row, err := r.cli.WebhookSubscription.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return (&webhook.Subscription{}).FromEnt(row), nil
+}
+
+func (r *EntSubscriptionRepo) Update(ctx context.Context, s webhook.Subscription) (*webhook.Subscription, error) {
+	row, err := r.cli.WebhookSubscription.UpdateOneID(s.ID).
+		SetName(s.Name).
+		SetIncludeDescendants(s.IncludeDescendants).
+		SetEventTypes(s.EventTypes).
+		SetURL(s.URL).
+		SetEnabled(s.Enabled).
+		Save(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return (&webhook.Subscription{}).FromEnt(row), nil
+}
+
+func (r *EntSubscriptionRepo) SetSecret(ctx context.Context, id uuid.UUID, secret string, at time.Time, previous string, previousExpiresAt time.Time) (*webhook.Subscription, error) {
+	row, err := r.cli.WebhookSubscription.UpdateOneID(id).
+		SetSecret(secret).
+		SetSecretRotatedAt(at).
+		SetPreviousSecret(previous).
+		SetPreviousSecretExpiresAt(previousExpiresAt).
+		Save(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return (&webhook.Subscription{}).FromEnt(row), nil
+}
+
+func (r *EntSubscriptionRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	tx, err := r.cli.Tx(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.WebhookDelivery.Delete().
+		Where(entwebhookdelivery.SubscriptionIDEQ(id)).
+		Exec(ctx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := tx.WebhookSubscription.DeleteOneID(id).Exec(ctx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *EntSubscriptionRepo) ListForOrgNode(ctx context.Context, orgNodeID uuid.UUID) ([]webhook.Subscription, error) {
+	rows, err := r.cli.WebhookSubscription.Query().
+		Where(entwebhooksubscription.OrgNodeIDEQ(orgNodeID)).
+		Order(ent.Asc(entwebhooksubscription.FieldCreatedAt)).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return toSubscriptions(rows), nil
+}
+
+func (r *EntSubscriptionRepo) ListEnabledForOrgNodes(ctx context.Context, orgNodeIDs []uuid.UUID) ([]webhook.Subscription, error) {
+	rows, err := r.cli.WebhookSubscription.Query().
+		Where(
+			entwebhooksubscription.OrgNodeIDIn(orgNodeIDs...),
+			entwebhooksubscription.EnabledEQ(true),
+		).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return toSubscriptions(rows), nil
+}
+
+func toSubscriptions(rows []*ent.WebhookSubscription) []webhook.Subscription {
+	out := make([]webhook.Subscription, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, *(&webhook.Subscription{}).FromEnt(row))
+	}
+	return out
+}